@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the batch command.
+var (
+	batchOutputDir string
+	batchNameTmpl  string
+)
+
+// batchCmd generates a model for each job spec read as a line of JSON.
+var batchCmd = &cobra.Command{
+	Use:   "batch <jobs.jsonl | ->",
+	Short: "Generate a 3D model for each job read as a line of JSON",
+	Long: `Batch reads job specs, one JSON object per line, from a file or, given -, from
+standard input, and generates a model for each, such as:
+
+  {"user": "octocat", "years": "2020-2024", "format": "obj", "output": "octocat"}
+
+Only "user" is required; "years" defaults to the current year, "format" to the output's
+extension or STL, and "output" to a generated filename. Each job's result is written to
+standard output as a line of JSON once it finishes, so other tools can drive large runs.
+Log messages are suppressed; a failing job is reported and the batch carries on, exiting
+with an error at the end.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBatch(cmd.InOrStdin(), cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	flags := batchCmd.Flags()
+	flags.StringVar(&batchOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&batchNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// runBatch validates the batch command's flags and runs the jobs read from path, or from
+// stdin when path is "-".
+func runBatch(stdin io.Reader, out io.Writer, path string) error {
+	if err := utils.ValidateNameTemplate(batchNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	in := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to open batch jobs", err)
+		}
+		defer func() { _ = file.Close() }()
+		in = file
+	}
+
+	// Standard output carries the results alone, so each line parses as JSON.
+	log := logger.GetLogger()
+	log.SetQuiet(true)
+	defer log.SetQuiet(false)
+
+	return skyline.RunBatch(in, out, skyline.BatchOptions{
+		OutputDir:    batchOutputDir,
+		NameTemplate: batchNameTmpl,
+	})
+}
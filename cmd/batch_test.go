@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestBatchCmd(t *testing.T) {
+	if batchCmd.Use != "batch <jobs.jsonl | ->" {
+		t.Errorf("expected command use to be 'batch <jobs.jsonl | ->', got %s", batchCmd.Use)
+	}
+	for _, flag := range []string{"output-dir", "name-template"} {
+		if batchCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunBatchValidation(t *testing.T) {
+	defer func() { batchNameTmpl = "" }()
+	batchNameTmpl = "{user}-{nope}"
+	if err := runBatch(strings.NewReader(""), &bytes.Buffer{}, "-"); errors.ExitCode(err) != errors.ExitValidation {
+		t.Errorf("runBatch() error = %v, want a --name-template validation error", err)
+	}
+
+	batchNameTmpl = ""
+	if err := runBatch(strings.NewReader(""), &bytes.Buffer{}, filepath.Join(t.TempDir(), "missing.jsonl")); errors.ExitCode(err) != errors.ExitIO {
+		t.Errorf("runBatch() error = %v, want an I/O error for a missing jobs file", err)
+	}
+
+	// A bad job is reported on standard output rather than stopping the batch.
+	var out bytes.Buffer
+	if err := runBatch(strings.NewReader(`{"years": "2024"}`+"\n"), &out, "-"); err == nil || !strings.Contains(out.String(), `"ok":false`) {
+		t.Errorf("runBatch() = %v, %q, want a failed result line", err, out.String())
+	}
+}
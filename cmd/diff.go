@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the diff command.
+var (
+	diffYears     []string
+	diffUser      string
+	diffOutput    string
+	diffOutputDir string
+	diffNameTmpl  string
+)
+
+// diffCmd renders the change in contributions between two years as a skyline.
+var diffCmd = &cobra.Command{
+	Use:   "diff --year <before> --year <after>",
+	Short: "Generate a 3D model of the change in contributions between two years",
+	Long: `Diff compares each day of one year with the same weekday of the same week of another
+and renders the change: days with more contributions rise as towers from a deck over the
+base, and days with fewer sink into the deck, deepest for the largest change.
+
+Pass --year twice, the year to compare against first.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runDiff()
+	},
+}
+
+func init() {
+	flags := diffCmd.Flags()
+	flags.StringSliceVarP(&diffYears, "year", "y", nil, "Year to compare against, then the year to compare (pass twice)")
+	flags.StringVarP(&diffUser, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
+	flags.StringVarP(&diffOutput, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&diffOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&diffNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff validates the diff command's flags and generates the model.
+func runDiff() error {
+	if len(diffYears) != 2 {
+		return errors.New(errors.ValidationError, "invalid --year", fmt.Errorf("expected two years, got %d", len(diffYears)))
+	}
+	var years [2]int
+	for i, value := range diffYears {
+		start, end, err := utils.ParseYearRange(value)
+		if err != nil {
+			return errors.New(errors.ValidationError, "invalid --year", err)
+		}
+		if start != end {
+			return errors.New(errors.ValidationError, "invalid --year", fmt.Errorf("expected a single year, got %q", value))
+		}
+		years[i] = start
+	}
+	if years[0] == years[1] {
+		return errors.New(errors.ValidationError, "invalid --year", fmt.Errorf("the two years must differ, got %d twice", years[0]))
+	}
+	if err := utils.ValidateNameTemplate(diffNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	return skyline.GenerateDiff(skyline.DiffOptions{
+		Before:       years[0],
+		After:        years[1],
+		User:         diffUser,
+		Output:       diffOutput,
+		OutputDir:    diffOutputDir,
+		NameTemplate: diffNameTmpl,
+	})
+}
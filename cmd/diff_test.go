@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestDiffCmd(t *testing.T) {
+	if diffCmd.Use != "diff --year <before> --year <after>" {
+		t.Errorf("expected command use to be 'diff --year <before> --year <after>', got %s", diffCmd.Use)
+	}
+	for _, flag := range []string{"year", "user", "output", "output-dir", "name-template"} {
+		if diffCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunDiffValidation(t *testing.T) {
+	defer func() { diffYears = nil }()
+	tests := []struct {
+		name  string
+		years []string
+	}{
+		{"no years", nil},
+		{"one year", []string{"2024"}},
+		{"three years", []string{"2022", "2023", "2024"}},
+		{"range", []string{"2022-2023", "2024"}},
+		{"same year", []string{"2024", "2024"}},
+		{"before GitHub", []string{"2001", "2024"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffYears = tt.years
+			if err := runDiff(); err == nil {
+				t.Errorf("runDiff() expected error for --year %v", tt.years)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the doctor command.
+var (
+	doctorHost      string
+	doctorOutputDir string
+)
+
+// Credential lookups of the doctor command, replaced in tests.
+var (
+	tokenForHost = auth.TokenForHost
+	tokenScopes  = github.TokenScopes
+)
+
+// doctorCmd checks that the extension's environment is ready for a first run.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that gh skyline is ready to run and explain how to fix what is not",
+	Long: `Doctor checks what gh skyline depends on and prints a fix for each problem it finds:
+whether the GitHub CLI is logged in to the host, whether the host's GraphQL API answers
+with those credentials, whether the token may read private contributions, whether the
+fonts for embossed text load, and whether files can be written to the output directory.
+
+It exits with an error if any check fails. Caveats, marked with !, do not fail it.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		host := doctorHost
+		if host == "" {
+			host, _ = auth.DefaultHost()
+		}
+		return runDoctor(cmd.OutOrStdout(), host, doctorOutputDir)
+	},
+}
+
+func init() {
+	flags := doctorCmd.Flags()
+	flags.StringVar(&doctorHost, "hostname", "", "GitHub host to check (optional, defaults to the GitHub CLI's default host)")
+	flags.StringVar(&doctorOutputDir, "output-dir", "", "Directory generated files will be written to (optional, defaults to the current directory)")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// Outcomes of a doctor check.
+const (
+	checkPassed = "✓"
+	checkCaveat = "!"
+	checkFailed = "✗"
+)
+
+// finding is the outcome of one doctor check.
+type finding struct {
+	status  string // checkPassed, checkCaveat or checkFailed
+	message string
+	fix     string // What to do about a caveat or failure; empty if nothing
+}
+
+// runDoctor runs every check against host and outputDir and reports each to out. The
+// API is only queried once the host has credentials, and scopes once it answers.
+func runDoctor(out io.Writer, host, outputDir string) error {
+	host = auth.NormalizeHostname(strings.ToLower(host))
+	findings := []finding{checkLogin(host)}
+	if findings[0].status != checkFailed {
+		findings = append(findings, checkAPI(host))
+		if findings[1].status != checkFailed {
+			findings = append(findings, checkScopes(host))
+		}
+	}
+	findings = append(findings, checkFonts(), checkOutputDir(outputDir))
+
+	failed := 0
+	for _, f := range findings {
+		if f.status == checkFailed {
+			failed++
+		}
+		if _, err := fmt.Fprintf(out, "%s %s\n", f.status, f.message); err != nil {
+			return err
+		}
+		if f.fix != "" {
+			if _, err := fmt.Fprintf(out, "  %s\n", f.fix); err != nil {
+				return err
+			}
+		}
+	}
+	if failed > 0 {
+		return errors.New(errors.GeneralError, fmt.Sprintf("doctor found %d problems", failed), nil)
+	}
+	return nil
+}
+
+// checkLogin reports whether the GitHub CLI has a token for host.
+func checkLogin(host string) finding {
+	token, source := tokenForHost(host)
+	if token == "" {
+		return finding{checkFailed, fmt.Sprintf("Not logged in to %s", host),
+			fmt.Sprintf("Run 'gh auth login --hostname %s'", host)}
+	}
+	return finding{status: checkPassed, message: fmt.Sprintf("Logged in to %s (token from %s)", host, source)}
+}
+
+// checkAPI reports whether the GraphQL API of host answers with the CLI's credentials.
+func checkAPI(host string) finding {
+	client, err := github.InitializeGitHubClientForHost(host)
+	var login string
+	if err == nil {
+		login, err = client.GetAuthenticatedUser()
+	}
+	if err == nil {
+		return finding{status: checkPassed, message: fmt.Sprintf("GraphQL API of %s answers as %s", host, login)}
+	}
+
+	fix := "Check the network connection and the proxy settings in HTTPS_PROXY and NO_PROXY"
+	switch errors.ExitCode(err) {
+	case errors.ExitAuth:
+		fix = fmt.Sprintf("Run 'gh auth login --hostname %s' to renew the credentials", host)
+	case errors.ExitRateLimit:
+		fix = "Wait for the rate limit to reset; 'gh api rate_limit' shows when"
+	}
+	return finding{checkFailed, fmt.Sprintf("GraphQL API of %s is unreachable: %v", host, err), fix}
+}
+
+// checkScopes reports whether the token of host may read private contributions. Without
+// that, public skylines still work, so a missing scope is only a caveat.
+func checkScopes(host string) finding {
+	scopes, known, err := tokenScopes(host)
+	switch {
+	case err != nil:
+		return finding{status: checkCaveat, message: fmt.Sprintf("Could not read the token's scopes: %v", err)}
+	case !known:
+		return finding{status: checkCaveat, message: "The token reports no scopes, as fine-grained and app tokens do; private contributions count only if it may read the repositories they were made to"}
+	case !slices.Contains(scopes, github.PrivateContributionsScope):
+		return finding{checkCaveat, fmt.Sprintf("The token lacks the %s scope, so contributions to private repositories are left out", github.PrivateContributionsScope),
+			fmt.Sprintf("Run 'gh auth refresh --hostname %s --scopes %s'", host, github.PrivateContributionsScope)}
+	}
+	return finding{status: checkPassed, message: fmt.Sprintf("The token has the %s scope, so private contributions count", github.PrivateContributionsScope)}
+}
+
+// checkFonts reports whether the bundled fonts load and which system fonts can emboss
+// names in scripts they do not cover.
+func checkFonts() finding {
+	if err := geometry.CheckFonts(); err != nil {
+		return finding{checkFailed, fmt.Sprintf("The bundled fonts cannot be loaded: %v", err),
+			"Reinstall the extension with 'gh extension remove skyline && gh extension install github/gh-skyline'"}
+	}
+	installed := geometry.InstalledFontPaths()
+	if len(installed) == 0 {
+		return finding{checkCaveat, "No system font covers scripts the bundled fonts lack, such as CJK or Cyrillic; such names are embossed transliterated",
+			"Install DejaVu Sans or Noto Sans, or pass --font with a TrueType font that covers the name"}
+	}
+	return finding{status: checkPassed, message: fmt.Sprintf("Fonts load, with system fonts for other scripts: %s", strings.Join(installed, ", "))}
+}
+
+// checkOutputDir reports whether files can be written to dir, or, if it does not exist
+// yet, to the closest existing directory it would be created in.
+func checkOutputDir(dir string) finding {
+	if dir == "" {
+		dir = "."
+	}
+	existing := dir
+	for {
+		if _, err := os.Stat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	probe, err := os.CreateTemp(existing, ".gh-skyline-doctor-*")
+	if err != nil {
+		return finding{checkFailed, fmt.Sprintf("Cannot write to %s: %v", existing, err),
+			"Pass --output-dir with a directory you can write to, or change its permissions"}
+	}
+	closeErr := probe.Close()
+	if err := os.Remove(probe.Name()); err != nil || closeErr != nil {
+		return finding{checkFailed, fmt.Sprintf("Cannot clean up a test file in %s: %v", existing, stderrors.Join(closeErr, err)),
+			fmt.Sprintf("Remove %s and check the directory's permissions", probe.Name())}
+	}
+	if existing != dir {
+		return finding{status: checkPassed, message: fmt.Sprintf("%s can be created in %s", dir, existing)}
+	}
+	return finding{status: checkPassed, message: fmt.Sprintf("Can write to %s", dir)}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestDoctorCmd(t *testing.T) {
+	if doctorCmd.Use != "doctor" {
+		t.Errorf("expected command use to be 'doctor', got %s", doctorCmd.Use)
+	}
+	for _, flag := range []string{"hostname", "output-dir"} {
+		if doctorCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	originalInit, originalToken, originalScopes := github.InitializeGitHubClientForHost, tokenForHost, tokenScopes
+	defer func() {
+		github.InitializeGitHubClientForHost, tokenForHost, tokenScopes = originalInit, originalToken, originalScopes
+	}()
+	github.InitializeGitHubClientForHost = func(string) (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "octocat"}), nil
+	}
+	tokenForHost = func(string) (string, string) { return "token", "GH_TOKEN" }
+	tokenScopes = func(string) ([]string, bool, error) { return []string{"gist", "read:org"}, true, nil }
+
+	var out bytes.Buffer
+	dir := t.TempDir()
+	if err := runDoctor(&out, "GitHub.com", filepath.Join(dir, "models")); err != nil {
+		t.Fatalf("runDoctor() error = %v", err)
+	}
+	for _, want := range []string{
+		"✓ Logged in to github.com (token from GH_TOKEN)",
+		"✓ GraphQL API of github.com answers as octocat",
+		"! The token lacks the repo scope",
+		"  Run 'gh auth refresh --hostname github.com --scopes repo'",
+		"✓ " + filepath.Join(dir, "models") + " can be created in " + dir,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("runDoctor() output = %q, want it to contain %q", out.String(), want)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("runDoctor() left %d files behind in the output directory", len(entries))
+	}
+
+	// Without credentials the API is not queried, and the failure fails the run.
+	tokenForHost = func(string) (string, string) { return "", "" }
+	github.InitializeGitHubClientForHost = func(string) (*github.Client, error) {
+		t.Error("runDoctor() queried the API without credentials")
+		return nil, nil
+	}
+	out.Reset()
+	err := runDoctor(&out, "ghe.example.com", dir)
+	if err == nil || errors.ExitCode(err) != errors.ExitGeneral {
+		t.Errorf("runDoctor() error = %v, want a failed check", err)
+	}
+	if !strings.Contains(out.String(), "✗ Not logged in to ghe.example.com\n  Run 'gh auth login --hostname ghe.example.com'") {
+		t.Errorf("runDoctor() output = %q, want the login fix", out.String())
+	}
+}
+
+func TestCheckAPIFixes(t *testing.T) {
+	originalInit := github.InitializeGitHubClientForHost
+	defer func() { github.InitializeGitHubClientForHost = originalInit }()
+
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{&api.HTTPError{StatusCode: http.StatusUnauthorized}, "gh auth login --hostname github.com"},
+		{&api.HTTPError{StatusCode: http.StatusTooManyRequests}, "gh api rate_limit"},
+		{fmt.Errorf("connection refused"), "HTTPS_PROXY"},
+	} {
+		github.InitializeGitHubClientForHost = func(string) (*github.Client, error) {
+			return github.NewClient(&mocks.MockGitHubClient{Err: tt.err}), nil
+		}
+		got := checkAPI("github.com")
+		if got.status != checkFailed || !strings.Contains(got.fix, tt.want) {
+			t.Errorf("checkAPI() with error %v = %+v, want a fix mentioning %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOutputDirUnwritable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkOutputDir(file); got.status != checkFailed {
+		t.Errorf("checkOutputDir(a file) = %+v, want a failure", got)
+	}
+}
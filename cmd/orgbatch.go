@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the org-batch command.
+var (
+	orgBatchYearRange string
+	orgBatchTop       int
+	orgBatchHeights   bool
+	orgBatchOutputDir string
+	orgBatchNameTmpl  string
+)
+
+// orgBatchCmd generates a skyline for each of an organization's top contributors.
+var orgBatchCmd = &cobra.Command{
+	Use:   "org-batch <org>",
+	Short: "Generate 3D models of an organization's top contributors",
+	Long: `Org-batch ranks the members of an organization by their contributions over the years
+and generates a skyline for each of the top ones, such as a set of awards for an all-hands.
+With --ranked-heights, the column heights are scaled by rank: the leader's towers keep
+their full height and the others' shrink evenly, down to half height for the last, on
+bases of the same size.
+
+Every member's calendar is fetched, one request per member and year, so large
+organizations take a while. Members who keep their membership private are only listed
+to other members. Each contributor's result is written to standard output as a line of
+JSON, in rank order; a failing member is reported and the batch carries on, exiting with
+an error at the end.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOrgBatch(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	flags := orgBatchCmd.Flags()
+	flags.StringVarP(&orgBatchYearRange, "year", "y", "", "Year or year range to rank and model (optional, defaults to the current year)")
+	flags.IntVar(&orgBatchTop, "top", 10, "Number of top contributors to generate skylines for")
+	flags.BoolVar(&orgBatchHeights, "ranked-heights", false, "Scale each skyline's column heights by rank, from full height for the leader to half height for the last")
+	flags.StringVar(&orgBatchOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&orgBatchNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	rootCmd.AddCommand(orgBatchCmd)
+}
+
+// runOrgBatch validates the org-batch command's flags and generates the leaderboard.
+func runOrgBatch(out io.Writer, org string) error {
+	if orgBatchTop < 1 {
+		return errors.New(errors.ValidationError, "invalid --top", fmt.Errorf("must be at least 1, got %d", orgBatchTop))
+	}
+	yearRange := orgBatchYearRange
+	if yearRange == "" {
+		yearRange = strconv.Itoa(time.Now().Year())
+	}
+	startYear, endYear, err := utils.ParseYearRange(yearRange)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid year range", err)
+	}
+	if err := utils.ValidateNameTemplate(orgBatchNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	// Standard output carries the results alone, so each line parses as JSON.
+	log := logger.GetLogger()
+	log.SetQuiet(true)
+	defer log.SetQuiet(false)
+
+	return skyline.RunOrgBatch(out, skyline.OrgBatchOptions{
+		Org:           org,
+		StartYear:     startYear,
+		EndYear:       endYear,
+		Top:           orgBatchTop,
+		RankedHeights: orgBatchHeights,
+		OutputDir:     orgBatchOutputDir,
+		NameTemplate:  orgBatchNameTmpl,
+	})
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestOrgBatchCmd(t *testing.T) {
+	if orgBatchCmd.Use != "org-batch <org>" {
+		t.Errorf("expected command use to be 'org-batch <org>', got %s", orgBatchCmd.Use)
+	}
+	for _, flag := range []string{"year", "top", "ranked-heights", "output-dir", "name-template"} {
+		if orgBatchCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunOrgBatchValidation(t *testing.T) {
+	defer func() { orgBatchTop, orgBatchYearRange, orgBatchNameTmpl = 10, "", "" }()
+	tests := map[string]func(){
+		"top":           func() { orgBatchTop = 0 },
+		"year":          func() { orgBatchYearRange = "1999" },
+		"name template": func() { orgBatchNameTmpl = "{nope}" },
+	}
+	for name, set := range tests {
+		t.Run(name, func(t *testing.T) {
+			orgBatchTop, orgBatchYearRange, orgBatchNameTmpl = 10, "", ""
+			set()
+			if err := runOrgBatch(&bytes.Buffer{}, "github"); errors.ExitCode(err) != errors.ExitValidation {
+				t.Errorf("runOrgBatch() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/wireframe"
+	"github.com/spf13/cobra"
+)
+
+// Terminal size used when it can't be detected, and the delay between frames of --spin.
+const (
+	previewDefaultCols = 80
+	previewDefaultRows = 24
+	previewFrameDelay  = 80 * time.Millisecond
+)
+
+// previewSpin keeps the preview turning until interrupted.
+var previewSpin bool
+
+// previewCmd draws a generated model in the terminal.
+var previewCmd = &cobra.Command{
+	Use:   "preview <model.stl>",
+	Short: "Draw a skyline model as a wireframe in the terminal",
+	Long: `Preview draws the edges of a binary STL model, such as one generated by gh skyline,
+with Braille characters sized to the terminal, so its proportions can be checked
+without opening an external viewer.
+
+Pass --spin to turn the model about its vertical axis until interrupted with Ctrl+C.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t := term.FromEnv()
+		cols, rows, err := t.Size()
+		if err != nil || cols <= 0 || rows <= 1 {
+			cols, rows = previewDefaultCols, previewDefaultRows
+		}
+		if previewSpin && !t.IsTerminalOutput() {
+			return errors.New(errors.ValidationError, "--spin needs a terminal to draw on", nil)
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		// Leave the last line for the prompt.
+		return runPreview(ctx, cmd.OutOrStdout(), args[0], cols, rows-1, previewSpin)
+	},
+}
+
+func init() {
+	previewCmd.Flags().BoolVar(&previewSpin, "spin", false, "Turn the model until interrupted")
+	rootCmd.AddCommand(previewCmd)
+}
+
+// runPreview draws the model at path into cols by rows characters of out, turning it
+// until ctx is done if spin is set.
+func runPreview(ctx context.Context, out io.Writer, path string, cols, rows int, spin bool) error {
+	triangles, err := stl.ReadSTLBinary(path)
+	if err != nil {
+		return err
+	}
+	mesh, err := wireframe.NewMesh(triangles)
+	if err != nil {
+		return err
+	}
+
+	if spin {
+		return mesh.Spin(ctx, out, cols, rows, stl.DefaultCameraElevation, 0, previewFrameDelay)
+	}
+	for _, line := range mesh.Render(cols, rows, stl.DefaultCameraAzimuth, stl.DefaultCameraElevation) {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestPreviewCmd(t *testing.T) {
+	if previewCmd.Use != "preview <model.stl>" {
+		t.Errorf("expected command use to be 'preview <model.stl>', got %s", previewCmd.Use)
+	}
+	if previewCmd.Flags().Lookup("spin") == nil {
+		t.Error("expected flag spin to be initialized")
+	}
+}
+
+func TestRunPreview(t *testing.T) {
+	dir := t.TempDir()
+	model := filepath.Join(dir, "model.stl")
+	box, err := geometry.CreateCube(0, 0, 0, 40, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stl.WriteSTLBinary(model, box); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runPreview(context.Background(), &out, model, 40, 12, false); err != nil {
+		t.Fatalf("runPreview() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 12 || strings.TrimSpace(out.String()) == "" {
+		t.Errorf("runPreview() drew %d lines:\n%s", len(lines), out.String())
+	}
+
+	// A cancelled spin draws a single frame and returns.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out.Reset()
+	if err := runPreview(ctx, &out, model, 40, 12, true); err != nil {
+		t.Fatalf("runPreview() spin error = %v", err)
+	}
+	if !strings.Contains(out.String(), "\x1b[?25l") {
+		t.Errorf("runPreview() spin output = %q", out.String())
+	}
+
+	if err := runPreview(context.Background(), &out, filepath.Join(dir, "missing.stl"), 40, 12, false); err == nil {
+		t.Error("runPreview() expected error for missing model")
+	}
+	ascii := filepath.Join(dir, "ascii.stl")
+	if err := os.WriteFile(ascii, []byte("solid model\nendsolid model\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runPreview(context.Background(), &out, ascii, 40, 12, false); err == nil {
+		t.Error("runPreview() expected error for ASCII STL")
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/publish"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Defaults of the publish command's listing flags.
+const (
+	defaultPublishTitle       = "{user}'s GitHub Skyline {range}"
+	defaultPublishDescription = "A 3D-printable skyline of {user}'s GitHub contributions in {range}, generated with gh skyline."
+)
+
+// Flags of the publish command.
+var (
+	publishYearRange   string
+	publishUser        string
+	publishFull        bool
+	publishOutput      string
+	publishOutputDir   string
+	publishService     string
+	publishTitle       string
+	publishDescription string
+	publishTags        []string
+	publishDraft       bool
+	publishThresholds  string
+	publishAzimuth     float64
+	publishElevation   float64
+	publishBackground  string
+	publishMaterial    string
+)
+
+// publishCmd generates a skyline and uploads it to a model-sharing service.
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Generate a skyline and upload it to a model-sharing service",
+	Long: `Publish generates a skyline, renders a preview image of it and uploads both as a new
+listing on a model-sharing service. The title and description are templates using
+{user}, {range}, {start}, {end} and {date}.
+
+The service's API token is read from the environment, e.g. THINGIVERSE_TOKEN.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runPublish()
+	},
+}
+
+func init() {
+	flags := publishCmd.Flags()
+	flags.StringVarP(&publishYearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year or year range (e.g., 2024 or 2014-2024)")
+	flags.StringVarP(&publishUser, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
+	flags.BoolVarP(&publishFull, "full", "f", false, "Generate contribution graph from join year to current year")
+	flags.StringVarP(&publishOutput, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&publishOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&publishService, "service", "thingiverse", "Model-sharing service to upload to (thingiverse)")
+	flags.StringVar(&publishTitle, "title", defaultPublishTitle, "Listing title template")
+	flags.StringVar(&publishDescription, "description", defaultPublishDescription, "Listing description template")
+	flags.StringSliceVar(&publishTags, "tags", []string{"github", "skyline", "3d-printing"}, "Listing tags")
+	flags.BoolVar(&publishDraft, "draft", false, "Upload the files without publishing the listing")
+	flags.StringVar(&publishThresholds, "thresholds", "", "Ascending daily counts that grade days in the ASCII preview and the uploaded image, e.g. 1,5,10,20")
+	flags.Float64Var(&publishAzimuth, "camera-azimuth", stl.DefaultCameraAzimuth, "Degrees the preview camera swings from the front, to the right when positive (-180 to 180)")
+	flags.Float64Var(&publishElevation, "camera-elevation", stl.DefaultCameraElevation, "Degrees the preview camera looks down from level (0 to 90)")
+	flags.StringVar(&publishBackground, "bg-color", "", "Background of the preview image as a hex color, e.g. #ffffff (optional)")
+	flags.StringVar(&publishMaterial, "material-color", "", "Color the whole model in the preview image as one filament, e.g. #c0c0c0 (optional)")
+	rootCmd.AddCommand(publishCmd)
+}
+
+// runPublish validates the publish command's flags, then generates and uploads the model.
+func runPublish() error {
+	service, err := publish.ParseService(publishService)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --service", err)
+	}
+	token := os.Getenv(service.TokenEnv())
+	if token == "" {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s must be set to publish to %s", service.TokenEnv(), service), nil)
+	}
+	for flag, template := range map[string]string{"--title": publishTitle, "--description": publishDescription} {
+		if err := utils.ValidateNameTemplate(template); err != nil {
+			return errors.New(errors.ValidationError, "invalid "+flag, err)
+		}
+	}
+
+	thresholds, err := types.ParseThresholds(publishThresholds)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --thresholds", err)
+	}
+
+	preview, err := previewOptions()
+	if err != nil {
+		return err
+	}
+
+	startYear, endYear, err := utils.ParseYearRange(publishYearRange)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid year range", err)
+	}
+
+	return skyline.GenerateSkyline(skyline.Options{
+		StartYear:  startYear,
+		EndYear:    endYear,
+		User:       publishUser,
+		Full:       publishFull,
+		Output:     publishOutput,
+		OutputDir:  publishOutputDir,
+		Thresholds: thresholds,
+		Publish: &skyline.PublishOptions{
+			Publisher:   publish.NewPublisher(service, token),
+			Title:       publishTitle,
+			Description: publishDescription,
+			Tags:        publishTags,
+			Draft:       publishDraft,
+			Preview:     preview,
+		},
+	})
+}
+
+// previewOptions validates the camera and color flags of the uploaded preview image.
+func previewOptions() (stl.PreviewOptions, error) {
+	preview := stl.PreviewOptions{Azimuth: publishAzimuth, Elevation: publishElevation}
+	if publishAzimuth < -180 || publishAzimuth > 180 {
+		return stl.PreviewOptions{}, errors.New(errors.ValidationError, "invalid --camera-azimuth", fmt.Errorf("must be between -180 and 180"))
+	}
+	if publishElevation < 0 || publishElevation > 90 {
+		return stl.PreviewOptions{}, errors.New(errors.ValidationError, "invalid --camera-elevation", fmt.Errorf("must be between 0 and 90"))
+	}
+	if publishBackground != "" {
+		background, err := utils.ParseHexColor(publishBackground)
+		if err != nil {
+			return stl.PreviewOptions{}, errors.New(errors.ValidationError, "invalid --bg-color", err)
+		}
+		preview.Background = background
+	}
+	if publishMaterial != "" {
+		material, err := utils.ParseHexColor(publishMaterial)
+		if err != nil {
+			return stl.PreviewOptions{}, errors.New(errors.ValidationError, "invalid --material-color", err)
+		}
+		preview.Material = material
+	}
+	return preview, nil
+}
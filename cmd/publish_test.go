@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestPublishCmd(t *testing.T) {
+	if publishCmd.Use != "publish" {
+		t.Errorf("expected command use to be 'publish', got %s", publishCmd.Use)
+	}
+	for _, flag := range []string{"year", "user", "full", "output", "output-dir", "service", "title", "description", "tags", "draft", "thresholds", "camera-azimuth", "camera-elevation", "bg-color", "material-color"} {
+		if publishCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunPublishValidation(t *testing.T) {
+	defer func(service, title string) { publishService, publishTitle = service, title }(publishService, publishTitle)
+
+	publishService = "printables"
+	if got := errors.ExitCode(runPublish()); got != errors.ExitValidation {
+		t.Errorf("unsupported service exit code = %d, want %d", got, errors.ExitValidation)
+	}
+
+	publishService = "thingiverse"
+	t.Setenv("THINGIVERSE_TOKEN", "")
+	if got := errors.ExitCode(runPublish()); got != errors.ExitValidation {
+		t.Errorf("missing token exit code = %d, want %d", got, errors.ExitValidation)
+	}
+
+	t.Setenv("THINGIVERSE_TOKEN", "secret")
+	publishTitle = "{nope}"
+	if got := errors.ExitCode(runPublish()); got != errors.ExitValidation {
+		t.Errorf("invalid title exit code = %d, want %d", got, errors.ExitValidation)
+	}
+}
+
+func TestPreviewOptions(t *testing.T) {
+	defer func(azimuth, elevation float64, background, material string) {
+		publishAzimuth, publishElevation, publishBackground, publishMaterial = azimuth, elevation, background, material
+	}(publishAzimuth, publishElevation, publishBackground, publishMaterial)
+
+	publishAzimuth, publishElevation, publishBackground, publishMaterial = -45, 60, "#fff", "c04020"
+	preview, err := previewOptions()
+	if err != nil {
+		t.Fatalf("previewOptions() error = %v", err)
+	}
+	if preview.Azimuth != -45 || preview.Elevation != 60 || preview.Background == nil || preview.Material == nil {
+		t.Errorf("previewOptions() = %+v", preview)
+	}
+
+	for name, set := range map[string]func(){
+		"azimuth":   func() { publishAzimuth = 270 },
+		"elevation": func() { publishElevation = -10 },
+		"bg-color":  func() { publishBackground = "white" },
+		"material":  func() { publishMaterial = "#12" },
+	} {
+		publishAzimuth, publishElevation, publishBackground, publishMaterial = 0, 30, "", ""
+		set()
+		if _, err := previewOptions(); errors.ExitCode(err) != errors.ExitValidation {
+			t.Errorf("invalid %s: error = %v, want a validation error", name, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/spf13/cobra"
+)
+
+// repairOutput is where the repair command writes the cleaned model.
+var repairOutput string
+
+// repairCmd cleans up an STL file and writes the result as a new binary STL.
+var repairCmd = &cobra.Command{
+	Use:   "repair <model.stl>",
+	Short: "Clean up an STL file's mesh",
+	Long: `Repair loads a binary or ASCII STL file, removes degenerate and duplicate triangles,
+makes the winding of neighbouring triangles agree with each closed shell facing outward,
+recomputes every normal and writes the result as a binary STL.
+
+The cleaned model is written next to the original with a -repaired suffix unless
+--output is given. Shells that only touch along an edge are left as they are, as joining
+them needs a slicer's mesh union.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRepair(cmd.OutOrStdout(), args[0], repairOutput)
+	},
+}
+
+func init() {
+	repairCmd.Flags().StringVarP(&repairOutput, "output", "o", "", "Output file path (optional)")
+	rootCmd.AddCommand(repairCmd)
+}
+
+// runRepair repairs the STL file at path, writes it to output, or next to path if output
+// is empty, and reports the changes to out.
+func runRepair(out io.Writer, path, output string) error {
+	if output == "" {
+		output = strings.TrimSuffix(path, filepath.Ext(path)) + "-repaired.stl"
+	}
+	if filepath.Clean(output) == filepath.Clean(path) {
+		return errors.New(errors.ValidationError, "--output must differ from the file being repaired", nil)
+	}
+
+	f, err := stl.ReadSTL(path)
+	if err != nil {
+		return err
+	}
+	triangles, stats := stl.RepairMesh(f.Triangles)
+	if len(triangles) == 0 {
+		return errors.New(errors.ValidationError, "STL file has no triangles left to write", nil)
+	}
+	if err := stl.WriteSTLBinary(output, triangles); err != nil {
+		return err
+	}
+
+	lines := []string{
+		fmt.Sprintf("Removed %d degenerate and %d duplicate triangles", stats.Degenerate, stats.Duplicates),
+		fmt.Sprintf("Reversed the winding of %d triangles and replaced %d normals", stats.Reoriented, stats.Normals),
+	}
+	if f.Truncated {
+		lines = append(lines, fmt.Sprintf("! Kept the %d whole triangles of a file declaring %d", len(f.Triangles), f.Declared))
+	}
+	if report := stl.ValidateMesh(triangles); !report.Watertight() {
+		lines = append(lines, fmt.Sprintf("! Still not watertight: %d open edges", report.OpenEdges))
+	}
+	lines = append(lines, fmt.Sprintf("✓ Wrote %d triangles to %s", len(triangles), output))
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
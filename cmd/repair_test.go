@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestRepairCmd(t *testing.T) {
+	if repairCmd.Use != "repair <model.stl>" {
+		t.Errorf("expected command use to be 'repair <model.stl>', got %s", repairCmd.Use)
+	}
+	if repairCmd.Flags().Lookup("output") == nil {
+		t.Error("expected flag output to be initialized")
+	}
+}
+
+func TestRunRepair(t *testing.T) {
+	dir := t.TempDir()
+	cube, err := geometry.CreateCube(0, 0, 0, 10, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cube[3].V2, cube[3].V3 = cube[3].V3, cube[3].V2
+	cube = append(cube, cube[0], types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}})
+	model := filepath.Join(dir, "model.stl")
+	if err := stl.WriteSTLBinary(model, cube); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runRepair(&out, model, ""); err != nil {
+		t.Fatalf("runRepair() error = %v", err)
+	}
+	for _, want := range []string{"Removed 1 degenerate and 1 duplicate", "winding of 1 triangles", "model-repaired.stl"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("runRepair() output = %q, want it to contain %q", out.String(), want)
+		}
+	}
+	repaired, err := stl.ReadSTLBinary(filepath.Join(dir, "model-repaired.stl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report := stl.ValidateMesh(repaired); len(repaired) != 12 || !report.OK() {
+		t.Errorf("repaired model has %d triangles, report %+v", len(repaired), report)
+	}
+
+	if err := runRepair(&out, model, model); err == nil {
+		t.Error("runRepair() expected error when overwriting the input")
+	}
+	if err := runRepair(&out, filepath.Join(dir, "missing.stl"), ""); err == nil {
+		t.Error("runRepair() expected error for a missing file")
+	}
+}
@@ -4,17 +4,36 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/browser"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/decorate"
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/github/fixtures"
+	"github.com/github/gh-skyline/internal/i18n"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/notify"
+	"github.com/github/gh-skyline/internal/printserver"
+	"github.com/github/gh-skyline/internal/profiling"
+	"github.com/github/gh-skyline/internal/slicer"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
 	"github.com/github/gh-skyline/internal/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Command line variables and root command configuration
@@ -22,10 +41,90 @@ var (
 	yearRange string
 	user      string
 	full      bool
+	fromDate  string
+	mergeWith []string
+	plugins   []string
+	toDate    string
 	debug     bool
+	verbosity string
 	web       bool
 	artOnly   bool
 	output    string // new output path flag
+	format    string
+	heightmap string
+	outlineTo string
+	tooltips  string
+	heatmapTo string
+	reportTo  string
+	gifTo     string
+	theme     string
+	fontFile  string
+	resume    bool
+	logFile   string
+	logFormat string
+	archive   string
+	signKey   string
+	maxMemory string
+	dryRun    bool
+	quiet     bool
+	offline   bool
+	anonymous bool
+	input     string
+	orient    string
+	describe  bool
+	badges    bool
+	avatar    bool
+	outputDir string
+	nameTmpl  string
+	stand     bool
+	textPos   string
+	textSize  float64
+	braille   string
+	engrave   bool
+	baseStyle string
+	towerCap  string
+	footprint string
+	shape     string
+	stretch   float64
+	snapTo    float64
+	streaks   bool
+	grain     string
+	inverted  bool
+	bucket    string
+	levels    string
+	connect   bool
+	layout    string
+	wrap      int
+	wrapLines bool
+	sides     string
+	weekStart string
+	breakdown string
+	metric    string
+	stats     bool
+	months    bool
+	yearTags  string
+	mirror    bool
+	highlight int
+	sendTo    string
+	slicing   bool
+	sliceWith string
+	sliceIni  string
+	watch     time.Duration
+	notifyURL string
+	notifyImg bool
+	caBundle  string
+	insecure  bool
+
+	recordFixtures string
+	cpuProfile     string
+	memProfile     string
+	traceFile      string
+)
+
+// Modes accepted by --braille.
+const (
+	brailleWithText = "with-text"
+	brailleOnly     = "only"
 )
 
 // rootCmd is the root command for the GitHub Skyline CLI tool.
@@ -54,45 +153,242 @@ to create a "building" effect, with empty spaces (no contributions) at the top.`
 // init initializes command line flags for the skyline CLI tool.
 func init() {
 	initFlags()
+	rootCmd.SetFlagErrorFunc(flagError)
 }
 
 // Execute initializes and executes the root command for the GitHub Skyline CLI.
+// Help text and messages are shown in the language of the user's locale.
 func Execute(_ context.Context) error {
+	if err := i18n.SetLanguage(i18n.Detect()); err != nil {
+		return errors.New(errors.GeneralError, "failed to load translations", err)
+	}
+	localize(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// localize translates the help text and flag descriptions of cmd and its subcommands into
+// the active language.
+func localize(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		flag.Usage = i18n.T(flag.Usage)
+	})
+	for _, sub := range cmd.Commands() {
+		localize(sub)
+	}
+}
+
 // initFlags sets up command line flags for the skyline CLI tool.
 func initFlags() {
 	flags := rootCmd.Flags()
 	flags.StringVarP(&yearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year or year range (e.g., 2024 or 2014-2024)")
 	flags.StringVarP(&user, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
 	flags.BoolVarP(&full, "full", "f", false, "Generate contribution graph from join year to current year")
+	flags.StringVar(&fromDate, "from", "", "First day of a date window replacing --year, e.g. 2024-03-01 (requires --to)")
+	flags.StringVar(&toDate, "to", "", "Last day of the date window, laid out as a row per year of it (requires --from)")
+	flags.StringVar(&verbosity, "verbosity", "info", "Least severe log messages shown: error, warn, info, debug, or trace to also log every GitHub API request")
 	flags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
+	_ = flags.MarkDeprecated("debug", "use --verbosity debug")
+	flags.StringVar(&logFile, "log-file", "", "Append timestamped log records to a file (optional)")
+	flags.StringVar(&logFormat, "log-format", "json", "Format of --log-file records (json or text)")
 	flags.BoolVarP(&web, "web", "w", false, "Open GitHub profile (authenticated or specified user).")
 	flags.BoolVarP(&artOnly, "art-only", "a", false, "Generate only ASCII preview")
+	flags.StringVar(&orient, "orientation", "horizontal", "Layout of the ASCII preview (horizontal or vertical)")
+	flags.BoolVar(&describe, "describe", false, "Print a prose summary of each year instead of the ASCII preview, for screen readers")
 	flags.StringVarP(&output, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&format, "format", export.Default, fmt.Sprintf("File format of the model (%s); inferred from the --output extension when not given", strings.Join(export.Names(), ", ")))
+	_ = rootCmd.RegisterFlagCompletionFunc("format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return export.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+	flags.StringVar(&outputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&nameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	flags.BoolVar(&resume, "resume", false, "Reuse years fetched by a previous, interrupted run")
+	flags.BoolVar(&anonymous, "anonymous", false, fmt.Sprintf("Read public contributions without GitHub credentials, under stricter rate limits; %s may hold an app token to raise them", github.AppTokenEnv))
+	flags.BoolVar(&offline, "offline", false, "Never touch the network; generate from cached years or --input alone")
+	flags.StringArrayVar(&mergeWith, "merge-account", nil, "Add the contributions of another account, as host:user, authenticated per host (repeatable)")
+	flags.StringVar(&input, "input", "", "Generate from a contributions.json or --archive zip instead of fetching (optional)")
+	flags.StringVar(&heightmap, "export-heightmap", "", "Also write a 16-bit grayscale PNG heightmap of the model (optional)")
+	flags.StringVar(&archive, "archive", "", "Bundle the generated files, contribution data, summary, preview and a manifest into a zip, or gzip the STL alone for a .gz path (optional)")
+	flags.StringVar(&signKey, "sign-key", "", "SSH private key, such as ~/.ssh/id_ed25519, used to sign the archive manifest (optional)")
+	flags.StringVar(&maxMemory, "max-memory", "", "Cap estimated geometry memory (e.g. 512MB); larger models are streamed to disk (optional)")
+	flags.BoolVar(&dryRun, "dry-run", false, "Fetch contributions and print size and memory estimates without writing files")
+	flags.BoolVarP(&quiet, "quiet", "q", false, "Print only errors: no ASCII preview, progress or achievements, e.g. for CI")
+	flags.StringVar(&recordFixtures, "record-fixtures", "", "Record GitHub API responses as test fixtures in this directory (development only)")
+	_ = flags.MarkHidden("record-fixtures")
+	flags.StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile to this file (development only)")
+	flags.StringVar(&memProfile, "memprofile", "", "Write a heap profile to this file on exit (development only)")
+	flags.StringVar(&traceFile, "trace", "", "Write an execution trace to this file (development only)")
+	_ = flags.MarkHidden("cpuprofile")
+	_ = flags.MarkHidden("memprofile")
+	_ = flags.MarkHidden("trace")
+	flags.StringVar(&textPos, "text-position", "front", "Base face for the username and year (front, back, left or right); use USERNAME,YEAR to split them")
+	flags.Float64Var(&textSize, "text-size", 1.0, "Scale of the embossed username and year (e.g. 0.8 or 1.5)")
+	flags.StringVar(&footprint, "base", "flat", "Underside of the base (flat, or gridfinity to size it in 42 mm units that slot into Gridfinity baseplates)")
+	flags.StringVar(&baseStyle, "base-style", "sharp", "Corner finish of the base (sharp, chamfer or rounded)")
+	flags.StringVar(&towerCap, "tower-cap", "flat", "Top of each day's column (flat, pyramid or dome); the last active day of each week gets a taller accent cap")
+	flags.StringVar(&shape, "style", "towers", "Shape of the contributions: towers, smooth for a continuous mountain-range surface, bricks, penholder to wrap them around a hollow cylinder, lithophane for a backlit panel, plaque for a wall plate, silhouette for a thin plate of the front profile with an SVG of it, or clock for a tower per hour of the day the commits were authored at")
+	flags.StringArrayVar(&plugins, "decorate", nil, "Run a decoration plugin command that reads the model as STL on stdin and writes geometry to add, such as a logo, as STL on stdout (repeatable)")
+	flags.Float64Var(&snapTo, "snap", 0.001, "Round every vertex to a grid this many millimetres apart, welding hairline gaps some slicers flag; 0 disables")
+	flags.Float64Var(&stretch, "height-scale", 1.0, "Multiply the column heights, e.g. 1.5 to exaggerate modest contribution counts")
+	flags.BoolVar(&inverted, "inverted", false, "Subtract the skyline from a solid block so contribution days become valleys, as a mold")
+	flags.StringVar(&grain, "granularity", "day", "What each tower stands for: day, week to sum each week into a single, chunkier tower, or month for twelve towers a year")
+	flags.BoolVar(&streaks, "merge-streaks", false, "Fuse each run of consecutive active days in a week into one ridge instead of separate columns")
+	flags.StringVar(&bucket, "bucket", "sqrt", "Mapping of daily counts to column heights (sqrt, or percentile to rank each day among the active days)")
+	flags.StringVar(&levels, "thresholds", "", "Ascending daily counts that grade days in the ASCII preview, e.g. 1,5,10,20 (default: shares of the busiest day)")
+	flags.StringVar(&layout, "layout", "stacked", "Arrangement of the weeks (stacked, strip for one long row of weeks, or spiral for a round base with the weeks around it)")
+	flags.IntVar(&wrap, "wrap", 0, "Split each year into rows of about this many weeks on a squarer base, e.g. 26 for two rows (default: a row per year)")
+	flags.BoolVar(&wrapLines, "wrap-separators", false, "Raise a low ridge between the rows of a wrapped model")
+	flags.StringVar(&sides, "double-sided", "", "Two years as FRONT:BACK, e.g. 2015:2025, for a then-and-now print with the back year turned to read from the back of the base")
+	flags.StringVar(&weekStart, "week-start", "sunday", "First day of each week in the grid (e.g. sunday or monday)")
+	flags.StringVar(&metric, "metric", "contributions", "Daily activity rendered as the skyline (contributions, reviews or discussions)")
+	flags.StringVar(&breakdown, "breakdown", "off", "Segment columns by contribution type: stacked in the model, or split into one STL per type")
+	flags.BoolVar(&connect, "connectors", false, "Add pegs and sockets to the base sides so separately printed years snap together")
+	flags.BoolVar(&stats, "stats-engraving", false, "Engrave the total contributions and longest streak on the back of the base")
+	flags.BoolVar(&months, "month-labels", false, "Engrave month initials along the front of the base, over the weeks they start in")
+	flags.StringVar(&yearTags, "year-labels", "none", "Engrave each year's number beside its row (side for the left face, front for the top of a wider base, or none)")
+	flags.BoolVar(&mirror, "mirror", false, "Run the weeks right to left, oldest at the right, keeping the text and logo readable")
+	flags.IntVar(&highlight, "highlight-top", 0, "Cap the columns of this many busiest days with a pyramid and mark them in the ASCII preview")
+	flags.BoolVar(&engrave, "engrave-text", false, "Recess the username and year into the base instead of embossing them")
+	flags.StringVar(&braille, "braille", "", "Emboss the username and year in Grade-1 Braille (with-text, or only to replace the visual text)")
+	flags.Lookup("braille").NoOptDefVal = brailleWithText
+	flags.StringVar(&sendTo, "send-to", "", "Upload the model to a print server (octoprint or moonraker), configured from the environment (optional)")
+	flags.BoolVar(&slicing, "slice", false, "Slice the finished model to G-code beside it and report the estimated print time and filament use")
+	flags.StringVar(&sliceWith, "slicer", "prusaslicer", "Slicer run by --slice, found on the PATH: prusaslicer or superslicer")
+	flags.StringVar(&sliceIni, "profile", "", "Slicer config exported as .ini to slice with; the model is centred on its bed (optional)")
+	flags.StringVar(&caBundle, "ca-bundle", "", "PEM file of certificate authorities to trust for GitHub besides the system's, e.g. for GitHub Enterprise Server (optional)")
+	flags.BoolVar(&insecure, "insecure-skip-verify", false, "Skip verifying GitHub's TLS certificate; for testing only, as it exposes the token to interception")
+	flags.StringVar(&notifyURL, "notify-url", "", "POST a JSON summary of the run to this webhook when generation finishes (optional)")
+	flags.BoolVar(&notifyImg, "notify-preview", false, "Embed the rendered preview image in the --notify-url summary")
+	flags.DurationVar(&watch, "watch", 0, "Keep running, re-fetching every interval such as 24h and regenerating the outputs when contributions change")
+	flags.BoolVar(&stand, "stand", false, "Also write an angled display stand STL sized to the model's base")
+	flags.BoolVar(&badges, "badges", false, "Emboss icons for earned achievements along the back edge of the base")
+	flags.BoolVar(&avatar, "avatar", false, "Download the user's avatar and emboss it as dithered dots before the username on the front")
+	flags.StringVar(&tooltips, "export-tooltips", "", "Also write a JSON sidecar with the date, count and bounds of each tower, for viewers that show hover tooltips (optional)")
+	flags.StringVar(&outlineTo, "export-outline", "", "Also write the front silhouette as an SVG or DXF outline in millimeters (optional)")
+	flags.StringVar(&heatmapTo, "heatmap", "", "Also write the contribution calendar as a PNG of colored squares (optional)")
+	flags.StringVar(&reportTo, "report", "", "Also write a single-file HTML report with an interactive 3D viewer, the heatmap, statistics and ASCII art (optional)")
+	flags.StringVar(&gifTo, "gif", "", "Also write an animated GIF of the model building up week by week, for sharing (optional)")
+	flags.StringVar(&fontFile, "font", "", "TrueType font for the embossed text instead of the bundled Mona Sans (optional)")
+	flags.StringVar(&theme, "theme", "light", "Colors of the heatmap (light or dark)")
+}
+
+// flagError reports flag parsing failures as validation errors so they exit with ExitValidation.
+func flagError(_ *cobra.Command, err error) error {
+	return errors.New(errors.ValidationError, "invalid flags", err)
+}
+
+// validateArgs wraps a positional argument validator so its failures exit with ExitValidation.
+func validateArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return errors.New(errors.ValidationError, "invalid arguments", err)
+		}
+		return nil
+	}
 }
 
 // executeRootCmd is the main execution function for the root command.
-func handleSkylineCommand(_ *cobra.Command, _ []string) error {
+func handleSkylineCommand(cmd *cobra.Command, _ []string) (err error) {
 	log := logger.GetLogger()
+	records, err := logger.ParseFormat(logFormat)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --log-format", err)
+	}
+	if logFile == "" && cmd.Flags().Changed("log-format") {
+		return errors.New(errors.ValidationError, "--log-format requires --log-file", nil)
+	}
+	if logFile != "" {
+		closeLog, err := openLogFile(log, logFile, records)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+	}
+
+	level, err := logger.ParseLevel(verbosity)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --verbosity", err)
+	}
 	if debug {
-		log.SetLevel(logger.DEBUG)
-		if err := log.Debug("Debug logging enabled"); err != nil {
+		if cmd.Flags().Changed("verbosity") {
+			return errors.New(errors.ValidationError, "--debug is --verbosity debug and cannot be combined with --verbosity", nil)
+		}
+		level = logger.DEBUG
+	}
+
+	if quiet {
+		if level < logger.INFO || artOnly || describe || dryRun {
+			return errors.New(errors.ValidationError, "--quiet cannot be combined with --verbosity debug or trace, --art-only, --describe or --dry-run", nil)
+		}
+		log.SetQuiet(true)
+		defer log.SetQuiet(false)
+	}
+
+	if level != logger.INFO {
+		log.SetLevel(level)
+		defer log.SetLevel(logger.INFO)
+		if err := log.Debug("%s logging enabled", level); err != nil {
 			return err
 		}
 	}
 
-	client, err := github.InitializeGitHubClient()
+	stopProfiling, err := profiling.Start(profiling.Options{CPUProfile: cpuProfile, MemProfile: memProfile, Trace: traceFile})
 	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		return errors.Wrap(err, "failed to start profiling")
+	}
+	defer func() {
+		if perr := stopProfiling(); perr != nil && err == nil {
+			err = errors.Wrap(perr, "failed to write profile")
+		}
+	}()
+
+	// Requests go through the proxy environment variables either way; only a custom
+	// certificate setup needs a transport of its own. The clients are built for this run
+	// alone and passed down.
+	var transport http.RoundTripper
+	newClient, newClientForHost := github.InitializeGitHubClient, github.InitializeGitHubClientForHost
+	if caBundle != "" || insecure {
+		if offline {
+			return errors.New(errors.ValidationError, "--ca-bundle and --insecure-skip-verify configure the connection to GitHub and cannot be combined with --offline", nil)
+		}
+		if caBundle != "" && insecure {
+			return errors.New(errors.ValidationError, "--ca-bundle has no effect with --insecure-skip-verify", nil)
+		}
+		if transport, err = github.NewTransport(github.TransportOptions{CABundle: caBundle, Insecure: insecure}); err != nil {
+			return errors.Wrap(err, "invalid --ca-bundle")
+		}
+		if insecure {
+			if err := log.Warning("TLS certificate verification is disabled; the connection to GitHub can be intercepted"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if recordFixtures != "" {
+		transport = fixtures.NewRecorder(recordFixtures, transport)
+	}
+	if transport != nil {
+		newClient, newClientForHost = github.NewClientInitializer(transport), github.NewHostClientInitializer(transport)
 	}
 
 	if web {
-		b := browser.New("", os.Stdout, os.Stderr)
+		if offline {
+			return errors.New(errors.ValidationError, "--web cannot be combined with --offline", nil)
+		}
+		client, err := newClient()
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize GitHub client")
+		}
+
+		// Without a terminal, as in CI or cron, there is nobody at a browser to look.
+		var b Browser = browser.New("", os.Stdout, os.Stderr)
+		if !term.FromEnv().IsTerminalOutput() {
+			b = urlPrinter{os.Stdout}
+		}
 		if err := openGitHubProfile(user, client, b); err != nil {
 			return err
 		}
@@ -101,10 +397,477 @@ func handleSkylineCommand(_ *cobra.Command, _ []string) error {
 
 	startYear, endYear, err := utils.ParseYearRange(yearRange)
 	if err != nil {
-		return fmt.Errorf("invalid year range: %v", err)
+		return errors.New(errors.ValidationError, "invalid year range", err)
+	}
+	if input != "" && !cmd.Flags().Changed("year") {
+		// Without --year, the model spans every year in the input file.
+		startYear, endYear = 0, 0
+	}
+
+	orientation, err := ascii.ParseOrientation(orient)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --orientation", err)
+	}
+
+	usernameFace, yearFace, err := geometry.ParseTextPosition(textPos)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --text-position", err)
+	}
+	if textSize <= 0 || textSize > geometry.MaxTextScale {
+		return errors.New(errors.ValidationError, "invalid --text-size", fmt.Errorf("must be greater than 0 and at most %g", geometry.MaxTextScale))
+	}
+
+	style, err := geometry.ParseBaseStyle(baseStyle)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --base-style", err)
+	}
+
+	arrangement, err := stl.ParseLayout(layout)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --layout", err)
+	}
+
+	rowYears, err := stl.ParseYearLabels(yearTags)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --year-labels", err)
+	}
+
+	firstDay, err := types.ParseWeekStart(weekStart)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --week-start", err)
+	}
+
+	breakdownMode, err := stl.ParseBreakdownMode(breakdown)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --breakdown", err)
+	}
+
+	baseFootprint, err := geometry.ParseBaseFootprint(footprint)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --base", err)
+	}
+	if baseFootprint == geometry.FootprintGridfinity && (stand || connect) {
+		return errors.New(errors.ValidationError, "--base gridfinity cannot be combined with --stand or --connectors", nil)
+	}
+
+	columnStyle, err := stl.ParseStyle(shape)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --style", err)
+	}
+	if columnStyle != stl.StyleTowers && breakdownMode != stl.BreakdownOff {
+		return errors.New(errors.ValidationError, "--breakdown requires --style towers", nil)
+	}
+	if baseFootprint == geometry.FootprintGridfinity && (columnStyle == stl.StyleBricks || breakdownMode == stl.BreakdownSplit) {
+		return errors.New(errors.ValidationError, "--base gridfinity cannot be combined with --style bricks or --breakdown split", nil)
+	}
+	bucketing, err := stl.ParseBucketing(bucket)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --bucket", err)
 	}
 
-	return skyline.GenerateSkyline(startYear, endYear, user, full, output, artOnly)
+	palette, err := stl.ParseTheme(theme)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --theme", err)
+	}
+
+	grades, err := types.ParseThresholds(levels)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --thresholds", err)
+	}
+
+	if snapTo < 0 || snapTo > stl.MaxSnap {
+		return errors.New(errors.ValidationError, "invalid --snap", fmt.Errorf("must be between 0 and %g mm, got %g", stl.MaxSnap, snapTo))
+	}
+
+	if stretch <= 0 || stretch > stl.MaxHeightScale {
+		return errors.New(errors.ValidationError, "invalid --height-scale", fmt.Errorf("must be greater than 0 and at most %g", stl.MaxHeightScale))
+	}
+	if stretch != 1 && (columnStyle == stl.StyleBricks || columnStyle == stl.StyleLithophane || columnStyle == stl.StyleSilhouette) {
+		return errors.New(errors.ValidationError, "--height-scale cannot be combined with --style bricks, lithophane or silhouette", nil)
+	}
+	if streaks && (columnStyle == stl.StyleSmooth || columnStyle == stl.StyleBricks || columnStyle == stl.StyleLithophane || columnStyle == stl.StyleSilhouette || breakdownMode != stl.BreakdownOff) {
+		return errors.New(errors.ValidationError, "--merge-streaks cannot be combined with --style smooth, bricks, lithophane or silhouette, or with --breakdown", nil)
+	}
+	if inverted && (columnStyle != stl.StyleTowers || breakdownMode != stl.BreakdownOff || streaks || arrangement == stl.LayoutSpiral || badges || months) {
+		return errors.New(errors.ValidationError, "--inverted requires --style towers and cannot be combined with --breakdown, --merge-streaks, --layout spiral, --badges or --month-labels", nil)
+	}
+	if columnStyle.ReplacesBase() && (baseFootprint == geometry.FootprintGridfinity || stand || connect || braille != "" || badges || stats || months || engrave) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("--style %s cannot be combined with --base gridfinity, --stand, --connectors, --braille, --badges, --stats-engraving, --month-labels or --engrave-text", strings.ToLower(shape)), nil)
+	}
+	if arrangement == stl.LayoutSpiral && (columnStyle.ReplacesBase() || breakdownMode == stl.BreakdownSplit || baseFootprint == geometry.FootprintGridfinity ||
+		style != geometry.BaseSharp || stand || connect || braille != "" || badges || stats || months || engrave || cmd.Flags().Changed("text-position")) {
+		return errors.New(errors.ValidationError, "--layout spiral has a round base of its own and cannot be combined with --style penholder, lithophane, plaque or silhouette, --breakdown split, --base gridfinity, --base-style, --stand, --connectors, --braille, --badges, --stats-engraving, --month-labels, --engrave-text or --text-position", nil)
+	}
+
+	if avatar && (columnStyle.ReplacesBase() || arrangement == stl.LayoutSpiral || usernameFace != geometry.FaceFront || braille == brailleOnly) {
+		return errors.New(errors.ValidationError, "--avatar is embossed before the username on the front of the base, so it needs the username there and cannot be combined with --style penholder, lithophane, plaque or silhouette, --layout spiral or --braille only", nil)
+	}
+	if mirror && arrangement == stl.LayoutSpiral {
+		return errors.New(errors.ValidationError, "--mirror cannot be combined with --layout spiral, which has no left or right", nil)
+	}
+
+	granularity, err := types.ParseGranularity(grain)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --granularity", err)
+	}
+	if granularity != types.GranularityDay && (highlight > 0 || streaks) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("--granularity %s cannot be combined with --highlight-top or --merge-streaks, which work on single days", granularity), nil)
+	}
+
+	capShape, err := geometry.ParseTowerCap(towerCap)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --tower-cap", err)
+	}
+	if capShape != geometry.CapFlat && (granularity != types.GranularityDay || columnStyle == stl.StyleSmooth || columnStyle == stl.StyleBricks ||
+		columnStyle == stl.StyleLithophane || columnStyle == stl.StyleSilhouette || columnStyle == stl.StyleClock || streaks || inverted) {
+		return errors.New(errors.ValidationError, "--tower-cap caps separate day columns and cannot be combined with --granularity, --style smooth, bricks, lithophane, silhouette or clock, --merge-streaks or --inverted", nil)
+	}
+
+	if tooltips != "" && (granularity != types.GranularityDay || columnStyle == stl.StyleSmooth || columnStyle == stl.StyleBricks ||
+		columnStyle == stl.StyleLithophane || columnStyle == stl.StyleSilhouette || columnStyle == stl.StyleClock || streaks || inverted) {
+		return errors.New(errors.ValidationError, "--export-tooltips describes separate day columns and cannot be combined with --granularity, --style smooth, bricks, lithophane, silhouette or clock, --merge-streaks or --inverted", nil)
+	}
+
+	if ext := strings.ToLower(filepath.Ext(outlineTo)); outlineTo != "" && ext != ".svg" && ext != ".dxf" {
+		return errors.New(errors.ValidationError, "invalid --export-outline", fmt.Errorf("must be an .svg or .dxf file, got %q", outlineTo))
+	}
+
+	if highlight < 0 {
+		return errors.New(errors.ValidationError, "invalid --highlight-top", fmt.Errorf("must be zero or more, got %d", highlight))
+	}
+	if highlight > 0 && (columnStyle == stl.StyleSmooth || columnStyle == stl.StyleBricks || columnStyle == stl.StyleLithophane || columnStyle == stl.StyleSilhouette || streaks || inverted) {
+		return errors.New(errors.ValidationError, "--highlight-top caps separate columns and cannot be combined with --style smooth, bricks, lithophane or silhouette, --merge-streaks or --inverted", nil)
+	}
+
+	if rowYears != stl.YearLabelsNone && (arrangement != stl.LayoutStacked || columnStyle.ReplacesBase()) {
+		return errors.New(errors.ValidationError, "--year-labels requires --layout stacked and cannot be combined with --style penholder, lithophane, plaque or silhouette", nil)
+	}
+	if rowYears == stl.YearLabelsSide && (connect || usernameFace == geometry.FaceLeft || yearFace == geometry.FaceLeft) {
+		return errors.New(errors.ValidationError, "--year-labels side cannot be combined with --connectors or text on the left face", nil)
+	}
+	if rowYears == stl.YearLabelsFront && inverted {
+		return errors.New(errors.ValidationError, "--year-labels front cannot be combined with --inverted, which would cover the labels", nil)
+	}
+
+	if wrap < 0 || (wrap > 0 && wrap < stl.MinWrapWeeks) {
+		return errors.New(errors.ValidationError, "invalid --wrap", fmt.Errorf("must be zero or at least %d weeks, got %d", stl.MinWrapWeeks, wrap))
+	}
+	if wrapLines && wrap == 0 {
+		return errors.New(errors.ValidationError, "--wrap-separators divides wrapped rows and requires --wrap", nil)
+	}
+	if wrap > 0 && (arrangement != stl.LayoutStacked || columnStyle.ReplacesBase() || columnStyle == stl.StyleSmooth || breakdownMode == stl.BreakdownSplit ||
+		stand || heightmap != "" || outlineTo != "" || months || rowYears != stl.YearLabelsNone) {
+		return errors.New(errors.ValidationError, "--wrap requires --layout stacked and cannot be combined with --style smooth, penholder, lithophane, plaque or silhouette, --breakdown split, --stand, --export-heightmap, --export-outline, --month-labels or --year-labels, which expect a row per year", nil)
+	}
+
+	var pair [2]int
+	if sides != "" {
+		if pair[0], pair[1], err = utils.ParseYearPair(sides); err != nil {
+			return errors.New(errors.ValidationError, "invalid --double-sided", err)
+		}
+		if cmd.Flags().Changed("year") || full || fromDate != "" || toDate != "" || input != "" || offline || archive != "" || notifyURL != "" {
+			return errors.New(errors.ValidationError, "--double-sided replaces the year range and cannot be combined with --year, --full, --from, --to, --input, --offline, --archive or --notify-url", nil)
+		}
+		if arrangement != stl.LayoutStacked || wrap > 0 || columnStyle.ReplacesBase() || inverted || breakdownMode == stl.BreakdownSplit || rowYears != stl.YearLabelsNone ||
+			heightmap != "" || outlineTo != "" || cmd.Flags().Changed("text-position") || stats || braille != "" || engrave {
+			return errors.New(errors.ValidationError, "--double-sided needs stacked rows and the back face for its label, so it cannot be combined with --layout, --wrap, --style penholder, lithophane, plaque or silhouette, --inverted, --breakdown split, --year-labels, --export-heightmap, --export-outline, --text-position, --stats-engraving, --braille or --engrave-text", nil)
+		}
+	}
+
+	activity, err := github.ParseMetric(metric)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --metric", err)
+	}
+	if breakdownMode != stl.BreakdownOff && activity != github.MetricContributions {
+		return errors.New(errors.ValidationError, "--breakdown requires --metric contributions", nil)
+	}
+
+	var from, to time.Time
+	if fromDate != "" || toDate != "" {
+		if fromDate == "" || toDate == "" {
+			return errors.New(errors.ValidationError, "--from and --to must be given together", nil)
+		}
+		if from, to, err = utils.ParseDateRange(fromDate, toDate); err != nil {
+			return errors.New(errors.ValidationError, "invalid --from/--to", err)
+		}
+		if cmd.Flags().Changed("year") || full || resume || offline || input != "" || describe || archive != "" || activity != github.MetricContributions || breakdownMode != stl.BreakdownOff {
+			return errors.New(errors.ValidationError, "--from and --to cannot be combined with --year, --full, --resume, --offline, --input, --describe, --archive, --metric reviews, --metric discussions or --breakdown", nil)
+		}
+	}
+
+	accounts := make([]github.Account, len(mergeWith))
+	for i, value := range mergeWith {
+		if accounts[i], err = github.ParseAccount(value); err != nil {
+			return errors.New(errors.ValidationError, "invalid --merge-account", err)
+		}
+	}
+	if len(accounts) > 0 && (offline || input != "" || activity != github.MetricContributions || breakdownMode != stl.BreakdownOff) {
+		return errors.New(errors.ValidationError, "--merge-account cannot be combined with --offline, --input, --metric reviews, --metric discussions or --breakdown", nil)
+	}
+
+	if anonymous {
+		if user == "" {
+			return errors.New(errors.ValidationError, "--anonymous requires --user, as nobody is signed in to default to", nil)
+		}
+		if offline || input != "" || len(accounts) > 0 || activity != github.MetricContributions || breakdownMode != stl.BreakdownOff || columnStyle == stl.StyleClock {
+			return errors.New(errors.ValidationError, "--anonymous reads public calendars only and cannot be combined with --offline, --input, --merge-account, --metric reviews, --metric discussions, --breakdown or --style clock", nil)
+		}
+	}
+
+	if columnStyle == stl.StyleClock && (granularity != types.GranularityDay || arrangement != stl.LayoutStacked || wrap > 0 || streaks || inverted || highlight > 0 ||
+		months || rowYears != stl.YearLabelsNone || stats || badges || avatar || stand || heightmap != "" || outlineTo != "" || heatmapTo != "" || gifTo != "" ||
+		archive != "" || describe || sides != "" || !from.IsZero() || offline || input != "" || resume || len(accounts) > 0 ||
+		activity != github.MetricContributions || notifyURL != "" || watch != 0) {
+		return errors.New(errors.ValidationError, "--style clock counts commits by the hour over the whole range and cannot be combined with --granularity, --layout, --wrap, --merge-streaks, --inverted, --highlight-top, --month-labels, --year-labels, --stats-engraving, --badges, --avatar, --stand, --export-heightmap, --export-outline, --heatmap, --gif, --archive, --describe, --double-sided, --from, --to, --offline, --input, --resume, --merge-account, --metric, --notify-url or --watch, which work on the contribution calendar", nil)
+	}
+
+	if connect && (isSideFace(usernameFace) || isSideFace(yearFace)) {
+		return errors.New(errors.ValidationError, "--connectors cannot be combined with text on the left or right face", nil)
+	}
+
+	if stats && braille != brailleOnly && (usernameFace == geometry.FaceBack || yearFace == geometry.FaceBack) {
+		return errors.New(errors.ValidationError, "--stats-engraving cannot be combined with text on the back face", nil)
+	}
+
+	if braille != "" && braille != brailleWithText && braille != brailleOnly {
+		return errors.New(errors.ValidationError, "invalid --braille", fmt.Errorf("unknown mode %q (expected %s or %s)", braille, brailleWithText, brailleOnly))
+	}
+
+	exporter, err := export.Lookup(format)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid --format", err)
+	}
+	if inferred, ok := export.ForPath(output); ok && !cmd.Flags().Changed("format") {
+		exporter = inferred
+	}
+
+	if err := utils.ValidateNameTemplate(nameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	if fontFile != "" {
+		if err := geometry.UseFont(fontFile); err != nil {
+			return errors.Wrap(err, "invalid --font")
+		}
+		defer func() { _ = geometry.UseFont("") }()
+	}
+
+	var server *printserver.Server
+	if sendTo != "" {
+		kind, err := printserver.ParseKind(sendTo)
+		if err != nil {
+			return errors.New(errors.ValidationError, "invalid --send-to", err)
+		}
+		if server, err = printserver.FromEnv(kind); err != nil {
+			return err
+		}
+		if exporter.Extension() != export.Default {
+			return errors.New(errors.ValidationError, fmt.Sprintf("--format %s cannot be combined with --send-to, as print servers take STL files", exporter.Extension()), nil)
+		}
+	}
+
+	var sliceTo *slicer.Slicer
+	if slicing {
+		if breakdownMode == stl.BreakdownSplit || artOnly || dryRun {
+			return errors.New(errors.ValidationError, "--slice cannot be combined with --breakdown split, --art-only or --dry-run", nil)
+		}
+		kind, err := slicer.ParseKind(sliceWith)
+		if err != nil {
+			return errors.New(errors.ValidationError, "invalid --slicer", err)
+		}
+		if sliceTo, err = slicer.New(kind, sliceIni); err != nil {
+			return err
+		}
+		// --send-to uploads the G-code of --slice, leaving its own slicer command unused.
+		if server != nil && server.Slicer != "" {
+			if err := log.Warning("%s is ignored with --slice; the G-code --slice writes is sent instead", printserver.SlicerEnv); err != nil {
+				return err
+			}
+		}
+	} else if cmd.Flags().Changed("slicer") || sliceIni != "" {
+		return errors.New(errors.ValidationError, "--slicer and --profile require --slice", nil)
+	}
+
+	var webhook *notify.Webhook
+	if notifyURL != "" {
+		if artOnly || dryRun {
+			return errors.New(errors.ValidationError, "--notify-url cannot be combined with --art-only or --dry-run, which generate no model", nil)
+		}
+		if webhook, err = notify.New(notifyURL, notifyImg); err != nil {
+			return err
+		}
+	} else if notifyImg {
+		return errors.New(errors.ValidationError, "--notify-preview requires --notify-url", nil)
+	}
+
+	if reportTo != "" {
+		if ext := strings.ToLower(filepath.Ext(reportTo)); ext != ".html" && ext != ".htm" {
+			return errors.New(errors.ValidationError, "invalid --report", fmt.Errorf("must be an .html file, got %q", reportTo))
+		}
+		if artOnly || dryRun || columnStyle == stl.StyleClock {
+			return errors.New(errors.ValidationError, "--report cannot be combined with --art-only, --dry-run or --style clock, as it embeds the calendar model", nil)
+		}
+	}
+
+	if watch != 0 {
+		if watch < skyline.MinWatchInterval {
+			return errors.New(errors.ValidationError, "invalid --watch", fmt.Errorf("must be at least %s, got %s", skyline.MinWatchInterval, watch))
+		}
+		if offline || input != "" || resume || dryRun || server != nil {
+			return errors.New(errors.ValidationError, "--watch re-fetches the range and cannot be combined with --offline, --input, --resume, --dry-run or --send-to", nil)
+		}
+	}
+
+	decorators := make([]stl.Decorator, len(plugins))
+	for i, command := range plugins {
+		if decorators[i], err = decorate.Parse(command); err != nil {
+			return errors.Wrap(err, "invalid --decorate")
+		}
+	}
+
+	var memoryCap uint64
+	if maxMemory != "" {
+		if memoryCap, err = utils.ParseByteSize(maxMemory); err != nil {
+			return errors.New(errors.ValidationError, "invalid --max-memory", err)
+		}
+	}
+
+	if anonymous {
+		newClient = github.NewAnonymousClientInitializer(transport)
+		limits := fmt.Sprintf("is limited to 60 requests an hour and 10 searches a minute; set %s to raise the limits", github.AppTokenEnv)
+		if os.Getenv(github.AppTokenEnv) != "" {
+			limits = "is limited to 30 searches a minute"
+		}
+		if err := log.Warning("Anonymous mode counts public contributions only, rebuilt from search, and %s", limits); err != nil {
+			return err
+		}
+	}
+
+	opts := skyline.Options{
+		StartYear:     startYear,
+		EndYear:       endYear,
+		User:          user,
+		Full:          full,
+		From:          from,
+		To:            to,
+		Output:        output,
+		OutputDir:     outputDir,
+		NameTemplate:  nameTmpl,
+		ArtOnly:       artOnly,
+		Describe:      describe,
+		HeightmapPath: heightmap,
+		OutlinePath:   outlineTo,
+		TooltipsPath:  tooltips,
+		HeatmapPath:   heatmapTo,
+		ReportPath:    reportTo,
+		GIFPath:       gifTo,
+		Theme:         palette,
+		ArchivePath:   archive,
+		SignKeyPath:   signKey,
+		Resume:        resume,
+		MaxMemory:     memoryCap,
+		DryRun:        dryRun,
+		Quiet:         quiet,
+		Offline:       offline,
+		InputPath:     input,
+		MergeAccounts: accounts,
+		Orientation:   orientation,
+		Badges:        badges,
+		Avatar:        avatar,
+		HighlightTop:  highlight,
+		Stand:         stand,
+		Text: geometry.TextOptions{
+			UsernameFace: usernameFace,
+			YearFace:     yearFace,
+			Scale:        textSize,
+		},
+		Format:      exporter,
+		Braille:     braille == brailleWithText,
+		BrailleOnly: braille == brailleOnly,
+		EngraveText: engrave,
+		BaseStyle:   style,
+		TowerCap:    capShape,
+		Footprint:   baseFootprint,
+		Style:       columnStyle,
+		HeightScale: stretch,
+		Snap:        snapTo,
+		Decorators:  decorators,
+		Streaks:     streaks,
+		Granularity: granularity,
+		DoubleSided: pair,
+		Inverted:    inverted,
+		Bucket:      bucketing,
+		Thresholds:  grades,
+		Connectors:  connect,
+		Layout:      arrangement,
+		Wrap:        wrap,
+		Separators:  wrapLines,
+		WeekStart:   firstDay,
+		Breakdown:   breakdownMode,
+		Metric:      activity,
+		Stats:       stats,
+		Months:      months,
+		YearLabels:  rowYears,
+		Mirror:      mirror,
+		SendTo:      server,
+		Slice:       sliceTo,
+		Notify:      webhook,
+		Flags:       changedFlags(cmd.Flags()),
+
+		NewClient:        newClient,
+		NewClientForHost: newClientForHost,
+	}
+	if watch > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		return skyline.WatchSkyline(ctx, opts, watch)
+	}
+	return skyline.GenerateSkyline(opts)
+}
+
+// modelFlags are the flags recorded in the model's metadata: those that shape it. Models
+// and reports are shared, so paths, URLs, keys and commands are never recorded.
+var modelFlags = map[string]bool{
+	"year": true, "from": true, "to": true, "full": true, "metric": true, "format": true,
+	"style": true, "layout": true, "wrap": true, "wrap-separators": true, "double-sided": true,
+	"week-start": true, "granularity": true, "bucket": true, "thresholds": true, "breakdown": true,
+	"height-scale": true, "snap": true, "inverted": true, "merge-streaks": true, "highlight-top": true,
+	"mirror": true, "base": true, "base-style": true, "tower-cap": true, "connectors": true,
+	"text-position": true, "text-size": true, "braille": true, "engrave-text": true,
+	"stats-engraving": true, "month-labels": true, "year-labels": true, "badges": true,
+	"avatar": true, "stand": true,
+}
+
+// changedFlags formats the model flags set on the command line, in name order, for the
+// model's STL header and metadata.
+func changedFlags(flags *pflag.FlagSet) string {
+	var set []string
+	flags.Visit(func(flag *pflag.Flag) {
+		if modelFlags[flag.Name] {
+			set = append(set, fmt.Sprintf("--%s=%s", flag.Name, flag.Value))
+		}
+	})
+	return strings.Join(set, " ")
+}
+
+// isSideFace reports whether a text face is one of the sides used by --connectors.
+func isSideFace(face geometry.Face) bool {
+	return face == geometry.FaceLeft || face == geometry.FaceRight
+}
+
+// openLogFile directs a copy of all log records to the given file and returns a
+// function that detaches and closes it.
+func openLogFile(log *logger.Logger, path string, format logger.Format) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open log file", err)
+	}
+
+	log.SetFile(file, format)
+	return func() {
+		log.SetFile(nil, format)
+		_ = file.Close()
+	}, nil
 }
 
 // Browser interface matches browser.Browser functionality.
@@ -112,12 +875,26 @@ type Browser interface {
 	Browse(url string) error
 }
 
+// urlPrinter is the Browser used when output is not a terminal: it prints the URL for
+// the user to open instead of launching a browser.
+type urlPrinter struct {
+	out io.Writer
+}
+
+// Browse prints url on its own line.
+func (p urlPrinter) Browse(url string) error {
+	if _, err := fmt.Fprintln(p.out, url); err != nil {
+		return errors.New(errors.IOError, "failed to write profile URL", err)
+	}
+	return nil
+}
+
 // openGitHubProfile opens the GitHub profile page for the specified user or authenticated user.
 func openGitHubProfile(targetUser string, client skyline.GitHubClientInterface, b Browser) error {
 	if targetUser == "" {
 		username, err := client.GetAuthenticatedUser()
 		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
+			return errors.Wrap(err, "failed to get authenticated user")
 		}
 		targetUser = username
 	}
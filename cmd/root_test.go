@@ -1,10 +1,25 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // MockBrowser implements the Browser interface
@@ -34,7 +49,7 @@ func TestRootCmd(t *testing.T) {
 
 func TestInit(t *testing.T) {
 	flags := rootCmd.Flags()
-	expectedFlags := []string{"year", "user", "full", "debug", "web", "art-only", "output"}
+	expectedFlags := []string{"year", "user", "full", "from", "to", "debug", "verbosity", "web", "art-only", "output", "export-heightmap", "export-tooltips", "heatmap", "report", "gif", "theme", "font", "resume", "export-outline", "log-file", "log-format", "archive", "sign-key", "max-memory", "dry-run", "quiet", "anonymous", "offline", "input", "merge-account", "orientation", "describe", "record-fixtures", "badges", "output-dir", "name-template", "stand", "text-position", "text-size", "braille", "engrave-text", "base-style", "tower-cap", "connectors", "layout", "wrap", "wrap-separators", "double-sided", "week-start", "breakdown", "metric", "stats-engraving", "send-to", "slice", "slicer", "profile", "style", "height-scale", "decorate", "snap", "merge-streaks", "granularity", "inverted", "bucket", "thresholds", "month-labels", "year-labels", "mirror", "highlight-top", "avatar", "watch", "notify-url", "notify-preview", "ca-bundle", "insecure-skip-verify", "format", "base", "cpuprofile", "memprofile", "trace"}
 	for _, flag := range expectedFlags {
 		if flags.Lookup(flag) == nil {
 			t.Errorf("expected flag %s to be initialized", flag)
@@ -96,3 +111,568 @@ func TestOpenGitHubProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestURLPrinter(t *testing.T) {
+	var out bytes.Buffer
+	if err := openGitHubProfile("mona", &mocks.MockGitHubClient{}, urlPrinter{&out}); err != nil {
+		t.Fatalf("openGitHubProfile() error = %v", err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "https://") || !strings.HasSuffix(got, "/mona\n") {
+		t.Errorf("printed %q, want the profile URL on its own line", got)
+	}
+}
+
+func TestQuietConflicts(t *testing.T) {
+	defer func() { quiet, debug, artOnly, describe, dryRun = false, false, false, false, false }()
+	for _, conflict := range []*bool{&debug, &artOnly, &describe, &dryRun} {
+		quiet, debug, artOnly, describe, dryRun = true, false, false, false, false
+		*conflict = true
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation {
+			t.Errorf("handleSkylineCommand() error = %v, want a validation error", err)
+		}
+	}
+}
+
+func TestVerbosityValidation(t *testing.T) {
+	defer func() {
+		verbosity, debug, quiet = "info", false, false
+		rootCmd.Flags().Lookup("verbosity").Changed = false
+	}()
+	for name, set := range map[string]func(){
+		"unknown level": func() { verbosity = "loud" },
+		"with debug": func() {
+			verbosity, debug = "trace", true
+			rootCmd.Flags().Lookup("verbosity").Changed = true
+		},
+		"quiet trace": func() { verbosity, quiet = "trace", true },
+	} {
+		verbosity, debug, quiet = "info", false, false
+		rootCmd.Flags().Lookup("verbosity").Changed = false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--verbosity") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --verbosity validation error", name, err)
+		}
+	}
+}
+
+func TestDateWindowValidation(t *testing.T) {
+	defer func() { fromDate, toDate, full = "", "", false }()
+	tests := []struct {
+		name     string
+		from, to string
+		full     bool
+	}{
+		{"from alone", "2024-03-01", "", false},
+		{"to before from", "2024-03-01", "2024-02-01", false},
+		{"before launch", "2007-01-01", "2008-06-30", false},
+		{"with full", "2024-03-01", "2024-06-30", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromDate, toDate, full = tt.from, tt.to, tt.full
+			err := handleSkylineCommand(rootCmd, nil)
+			if got := errors.ExitCode(err); got != errors.ExitValidation {
+				t.Errorf("handleSkylineCommand() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestMergeAccountValidation(t *testing.T) {
+	defer func() { mergeWith, offline = nil, false }()
+	for name, set := range map[string]func(){
+		"malformed":    func() { mergeWith = []string{"mona"} },
+		"with offline": func() { mergeWith, offline = []string{"ghe.example.com:mona"}, true },
+	} {
+		mergeWith, offline = nil, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a validation error", name, err)
+		}
+	}
+}
+
+func TestAnonymousValidation(t *testing.T) {
+	defer func() { anonymous, user, offline, shape = false, "", false, "towers" }()
+	for name, set := range map[string]func(){
+		"without user": func() {},
+		"with offline": func() { user, offline = "mona", true },
+		"clock":        func() { user, shape = "mona", "clock" },
+	} {
+		anonymous, user, offline, shape = true, "", false, "towers"
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--anonymous") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want an --anonymous validation error", name, err)
+		}
+	}
+}
+
+func TestFontValidation(t *testing.T) {
+	defer func() { fontFile = "" }()
+	fontFile = filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(fontFile, []byte("not a font"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := handleSkylineCommand(rootCmd, nil)
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--font") {
+		t.Errorf("handleSkylineCommand() error = %v, want a validation error", err)
+	}
+}
+
+func TestYearLabelsValidation(t *testing.T) {
+	defer func() { yearTags, layout, connect, inverted = "none", "stacked", false, false }()
+	for name, set := range map[string]func(){
+		"unknown":            func() { yearTags = "top" },
+		"strip layout":       func() { yearTags, layout = "side", "strip" },
+		"side by connectors": func() { yearTags, connect = "side", true },
+		"front on a mold":    func() { yearTags, inverted = "front", true },
+	} {
+		yearTags, layout, connect, inverted = "none", "stacked", false, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--year-labels") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --year-labels validation error", name, err)
+		}
+	}
+}
+
+func TestMirrorValidation(t *testing.T) {
+	defer func() { mirror, layout = false, "stacked" }()
+	mirror, layout = true, "spiral"
+	err := handleSkylineCommand(rootCmd, nil)
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--mirror") {
+		t.Errorf("handleSkylineCommand() error = %v, want a --mirror validation error", err)
+	}
+}
+
+func TestHighlightValidation(t *testing.T) {
+	defer func() { highlight, shape, streaks = 0, "towers", false }()
+	for name, set := range map[string]func(){
+		"negative":      func() { highlight = -1 },
+		"smooth":        func() { highlight, shape = 5, "smooth" },
+		"merge streaks": func() { highlight, streaks = 5, true },
+	} {
+		highlight, shape, streaks = 0, "towers", false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--highlight-top") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --highlight-top validation error", name, err)
+		}
+	}
+}
+
+func TestClockValidation(t *testing.T) {
+	defer func() { shape, grain, months, offline = "towers", "day", false, false }()
+	for name, set := range map[string]func(){
+		"weekly":       func() { grain = "week" },
+		"month labels": func() { months = true },
+		"offline":      func() { offline = true },
+	} {
+		shape, grain, months, offline = "clock", "day", false, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--style clock") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --style clock validation error", name, err)
+		}
+	}
+}
+
+func TestTowerCapValidation(t *testing.T) {
+	defer func() { towerCap, shape, grain = "flat", "towers", "day" }()
+	for name, set := range map[string]func(){
+		"unknown": func() { towerCap = "spire" },
+		"smooth":  func() { towerCap, shape = "dome", "smooth" },
+		"weekly":  func() { towerCap, grain = "pyramid", "week" },
+	} {
+		towerCap, shape, grain = "flat", "towers", "day"
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--tower-cap") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --tower-cap validation error", name, err)
+		}
+	}
+}
+
+func TestTooltipsValidation(t *testing.T) {
+	defer func() { tooltips, shape, grain, streaks = "", "towers", "day", false }()
+	for name, set := range map[string]func(){
+		"smooth":  func() { shape = "smooth" },
+		"weekly":  func() { grain = "week" },
+		"streaks": func() { streaks = true },
+	} {
+		tooltips, shape, grain, streaks = "towers.json", "towers", "day", false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--export-tooltips") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want an --export-tooltips validation error", name, err)
+		}
+	}
+}
+
+func TestDecorateValidation(t *testing.T) {
+	defer func() { plugins = nil }()
+	plugins = []string{"no-such-decoration --logo acme.svg"}
+	err := handleSkylineCommand(rootCmd, nil)
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--decorate") {
+		t.Errorf("handleSkylineCommand() error = %v, want a --decorate validation error", err)
+	}
+}
+
+func TestSnapValidation(t *testing.T) {
+	defer func() { snapTo = 0.001 }()
+	for _, value := range []float64{-0.001, 0.5} {
+		snapTo = value
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--snap") {
+			t.Errorf("--snap %g: handleSkylineCommand() error = %v, want a --snap validation error", value, err)
+		}
+	}
+}
+
+func TestFormatValidation(t *testing.T) {
+	defer func() { format = "stl" }()
+	format = "3mf"
+	err := handleSkylineCommand(rootCmd, nil)
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--format") {
+		t.Errorf("handleSkylineCommand() error = %v, want a --format validation error", err)
+	}
+}
+
+func TestFormatCompletion(t *testing.T) {
+	complete, ok := rootCmd.GetFlagCompletionFunc("format")
+	if !ok {
+		t.Fatal("--format has no completion")
+	}
+	got, _ := complete(rootCmd, nil, "")
+	if !slices.Equal(got, export.Names()) {
+		t.Errorf("--format completions = %v, want %v", got, export.Names())
+	}
+}
+
+func TestGranularityValidation(t *testing.T) {
+	defer func() { grain, highlight, streaks = "day", 0, false }()
+	for name, set := range map[string]func(){
+		"unknown":       func() { grain = "hour" },
+		"highlight-top": func() { grain, highlight = "week", 5 },
+		"merge streaks": func() { grain, streaks = "week", true },
+		"month streaks": func() { grain, streaks = "month", true },
+	} {
+		grain, highlight, streaks = "day", 0, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--granularity") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --granularity validation error", name, err)
+		}
+	}
+}
+
+func TestWrapValidation(t *testing.T) {
+	defer func() { wrap, wrapLines, layout, months = 0, false, "stacked", false }()
+	for name, set := range map[string]func(){
+		"negative":        func() { wrap = -1 },
+		"too narrow":      func() { wrap = 4 },
+		"spiral":          func() { wrap, layout = 26, "spiral" },
+		"month labels":    func() { wrap, months = 26, true },
+		"separators only": func() { wrapLines = true },
+	} {
+		wrap, wrapLines, layout, months = 0, false, "stacked", false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--wrap") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --wrap validation error", name, err)
+		}
+	}
+}
+
+func TestDoubleSidedValidation(t *testing.T) {
+	defer func() { sides, full, stats, shape = "", false, false, "towers" }()
+	for name, set := range map[string]func(){
+		"one year":    func() { sides = "2015" },
+		"too early":   func() { sides = "2001:2025" },
+		"full":        func() { sides, full = "2015:2025", true },
+		"back stats":  func() { sides, stats = "2015:2025", true },
+		"lithophane":  func() { sides, shape = "2015:2025", "lithophane" },
+		"not a year":  func() { sides = "then:now" },
+		"three years": func() { sides = "2015:2020:2025" },
+	} {
+		sides, full, stats, shape = "", false, false, "towers"
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--double-sided") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --double-sided validation error", name, err)
+		}
+	}
+}
+
+func TestSliceValidation(t *testing.T) {
+	defer func() {
+		slicing, sliceWith, sliceIni, dryRun = false, "prusaslicer", "", false
+		rootCmd.Flags().Lookup("slicer").Changed = false
+	}()
+	for name, set := range map[string]func(){
+		"profile alone": func() { sliceIni = "mini.ini" },
+		"slicer alone": func() {
+			sliceWith = "superslicer"
+			rootCmd.Flags().Lookup("slicer").Changed = true
+		},
+		"unknown slicer":  func() { slicing, sliceWith = true, "cura" },
+		"dry run":         func() { slicing, dryRun = true, true },
+		"missing profile": func() { slicing, sliceIni = true, filepath.Join(t.TempDir(), "missing.ini") },
+	} {
+		slicing, sliceWith, sliceIni, dryRun = false, "prusaslicer", "", false
+		rootCmd.Flags().Lookup("slicer").Changed = false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !(strings.Contains(err.Error(), "--slice") || strings.Contains(err.Error(), "--profile")) {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --slice validation error", name, err)
+		}
+	}
+}
+
+func TestCABundleValidation(t *testing.T) {
+	defer func() { caBundle, insecure, offline = "", false, false }()
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, set := range map[string]func(){
+		"offline":       func() { caBundle, offline = notPEM, true },
+		"insecure too":  func() { caBundle, insecure = notPEM, true },
+		"no PEM blocks": func() { caBundle = notPEM },
+	} {
+		caBundle, insecure, offline = "", false, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--ca-bundle") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --ca-bundle validation error", name, err)
+		}
+	}
+}
+
+func TestAvatarValidation(t *testing.T) {
+	defer func() { avatar, textPos, shape = false, "front", "towers" }()
+	for name, set := range map[string]func(){
+		"username on the back": func() { textPos = "back" },
+		"plaque":               func() { shape = "plaque" },
+	} {
+		avatar, textPos, shape = true, "front", "towers"
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--avatar") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want an --avatar validation error", name, err)
+		}
+	}
+}
+
+func TestWatchValidation(t *testing.T) {
+	defer func() { watch, offline = 0, false }()
+	for name, set := range map[string]func(){
+		"too short": func() { watch = time.Second },
+		"negative":  func() { watch = -time.Hour },
+		"offline":   func() { watch, offline = 24*time.Hour, true },
+	} {
+		watch, offline = 0, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--watch") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --watch validation error", name, err)
+		}
+	}
+}
+
+func TestNotifyValidation(t *testing.T) {
+	defer func() { notifyURL, notifyImg, dryRun = "", false, false }()
+	for name, set := range map[string]func(){
+		"not a URL":           func() { notifyURL = "hooks.example.com" },
+		"dry run":             func() { notifyURL, dryRun = "https://hooks.example.com", true },
+		"preview without URL": func() { notifyImg = true },
+	} {
+		notifyURL, notifyImg, dryRun = "", false, false
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--notify") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --notify validation error", name, err)
+		}
+	}
+}
+
+func TestReportValidation(t *testing.T) {
+	defer func() { reportTo, artOnly, shape = "", false, "towers" }()
+	for name, set := range map[string]func(){
+		"not HTML": func() { reportTo = "report.pdf" },
+		"art only": func() { reportTo, artOnly = "report.html", true },
+		"clock":    func() { reportTo, shape = "report.html", "clock" },
+	} {
+		reportTo, artOnly, shape = "", false, "towers"
+		set()
+		err := handleSkylineCommand(rootCmd, nil)
+		if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--report") {
+			t.Errorf("%s: handleSkylineCommand() error = %v, want a --report validation error", name, err)
+		}
+	}
+}
+
+func TestOpenLogFile(t *testing.T) {
+	log := logger.GetLogger()
+	path := filepath.Join(t.TempDir(), "skyline.log")
+
+	closeLog, err := openLogFile(log, path, logger.TextFormat)
+	if err != nil {
+		t.Fatalf("openLogFile() error = %v", err)
+	}
+	if err := log.Error("something failed"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	closeLog()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "ERROR   something failed") {
+		t.Errorf("log file = %q, want error record", string(data))
+	}
+}
+
+func TestLogFormatValidation(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for name, values := range map[string]map[string]string{
+		"unknown format":   {"log-format": "yaml", "log-file": filepath.Join(t.TempDir(), "skyline.log")},
+		"unknown, no file": {"log-format": "yaml"},
+		"without log file": {"log-format": "text"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			setFlags(t, values)
+			err := handleSkylineCommand(rootCmd, nil)
+			if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--log-format") {
+				t.Errorf("handleSkylineCommand() error = %v, want a --log-format validation error", err)
+			}
+		})
+	}
+}
+
+func TestExportOutlineValidation(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	setFlags(t, map[string]string{"export-outline": filepath.Join(t.TempDir(), "outline.png")})
+
+	// The extension is checked before anything is fetched.
+	err := handleSkylineCommand(rootCmd, nil)
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--export-outline") {
+		t.Errorf("handleSkylineCommand() error = %v, want an --export-outline validation error", err)
+	}
+}
+
+func TestValidationExitCodes(t *testing.T) {
+	if got := errors.ExitCode(flagError(rootCmd, fmt.Errorf("unknown flag: --nope"))); got != errors.ExitValidation {
+		t.Errorf("flagError exit code = %d, want %d", got, errors.ExitValidation)
+	}
+
+	validate := validateArgs(cobra.ExactArgs(1))
+	if err := validate(rootCmd, []string{"one"}); err != nil {
+		t.Errorf("validateArgs() unexpected error = %v", err)
+	}
+	if got := errors.ExitCode(validate(rootCmd, nil)); got != errors.ExitValidation {
+		t.Errorf("validateArgs exit code = %d, want %d", got, errors.ExitValidation)
+	}
+}
+
+func TestChangedFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("layout", "stacked", "")
+	flags.Bool("stand", false, "")
+	flags.String("user", "", "")
+	flags.String("notify-url", "", "")
+	flags.String("sign-key", "", "")
+	if err := flags.Parse([]string{"--stand", "--layout", "strip", "--notify-url", "https://hooks.example.com/?token=s3cret", "--sign-key", "key.pem"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := changedFlags(flags), "--layout=strip --stand=true"; got != want {
+		t.Errorf("changedFlags() = %q, want %q", got, want)
+	}
+}
+
+// setFlags sets root command flags as if given on the command line, restoring them when
+// the test ends.
+func setFlags(t *testing.T, values map[string]string) {
+	t.Helper()
+	for name, value := range values {
+		flag := rootCmd.Flags().Lookup(name)
+		if err := rootCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = flag.Value.Set(flag.DefValue)
+			flag.Changed = false
+		})
+	}
+}
+
+func TestClientInitializersLeftUnchanged(t *testing.T) {
+	// Nobody is signed in, so the run stops at its first client.
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	before := reflect.ValueOf(github.InitializeGitHubClient).Pointer()
+	beforeHost := reflect.ValueOf(github.InitializeGitHubClientForHost).Pointer()
+
+	// The transport set up for this run goes to its own clients, not the package defaults.
+	setFlags(t, map[string]string{"insecure-skip-verify": "true", "record-fixtures": t.TempDir(), "art-only": "true"})
+	if err := handleSkylineCommand(rootCmd, nil); errors.ExitCode(err) != errors.ExitAuth {
+		t.Errorf("handleSkylineCommand() error = %v, want an authentication error", err)
+	}
+	if reflect.ValueOf(github.InitializeGitHubClient).Pointer() != before || reflect.ValueOf(github.InitializeGitHubClientForHost).Pointer() != beforeHost {
+		t.Error("handleSkylineCommand() replaced the default client initializers")
+	}
+}
+
+func TestExportedFilesOmitSecrets(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() { github.InitializeGitHubClient = originalInit }()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+
+	dir := t.TempDir()
+	setFlags(t, map[string]string{
+		"user":       "testuser",
+		"year":       "2024",
+		"output-dir": dir,
+		"format":     "obj",
+		"mirror":     "true",
+		"report":     filepath.Join(dir, "report.html"),
+		"notify-url": hook.URL + "/hook?token=s3cret",
+	})
+	if err := handleSkylineCommand(rootCmd, nil); err != nil {
+		t.Fatalf("handleSkylineCommand() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded bool
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte("s3cret")) || bytes.Contains(data, []byte("notify-url")) {
+			t.Errorf("%s records the --notify-url webhook", entry.Name())
+		}
+		recorded = recorded || bytes.Contains(data, []byte("--mirror=true"))
+	}
+	if len(entries) != 2 || !recorded {
+		t.Errorf("wrote %d files recording the model flags: %v, want the model and report", len(entries), recorded)
+	}
+}
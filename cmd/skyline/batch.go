@@ -0,0 +1,132 @@
+package skyline
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// BatchJob is one model of a batch, read from a line of JSON such as
+// {"user": "octocat", "years": "2020-2024", "format": "obj"}.
+type BatchJob struct {
+	User   string `json:"user"`   // Target user; required, as a batch has no single authenticated target
+	Years  string `json:"years"`  // Year or year range; empty means the current year
+	Format string `json:"format"` // File format; empty infers it from the output extension, or STL
+	Output string `json:"output"` // Output path; empty means a generated filename
+}
+
+// BatchResult reports the outcome of one job as a line of JSON, in the order the jobs
+// were read.
+type BatchResult struct {
+	Line     int    `json:"line"` // Line of the input holding the job, from 1
+	User     string `json:"user,omitempty"`
+	Years    string `json:"years,omitempty"`
+	Output   string `json:"output,omitempty"` // Path of the written model
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"` // Exit code the job would have had on its own
+}
+
+// BatchOptions configures every job of a batch.
+type BatchOptions struct {
+	OutputDir    string // Directory for generated or relative output paths
+	NameTemplate string // Filename template for generated names
+	CacheDir     string // Optional cache location; empty means the default user cache
+}
+
+// RunBatch generates a model for each job read from in, one JSON object per line, and
+// writes a BatchResult per job to out as it finishes. Blank lines are skipped. A job that
+// fails, including one that cannot be parsed, is reported and the batch carries on; the
+// returned error then counts the failures.
+func RunBatch(in io.Reader, out io.Writer, opts BatchOptions) error {
+	scanner := bufio.NewScanner(in)
+	encoder := json.NewEncoder(out)
+	jobs, failed := 0, 0
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		jobs++
+		result := runBatchJob(text, opts)
+		result.Line = line
+		if !result.OK {
+			failed++
+		}
+		if err := encoder.Encode(result); err != nil {
+			return errors.New(errors.IOError, "failed to write batch result", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.New(errors.IOError, "failed to read batch jobs", err)
+	}
+	if failed > 0 {
+		return errors.New(errors.GeneralError, fmt.Sprintf("%d of %d batch jobs failed", failed, jobs), nil)
+	}
+	return nil
+}
+
+// runBatchJob parses and generates a single job.
+func runBatchJob(text []byte, opts BatchOptions) BatchResult {
+	var job BatchJob
+	decoder := json.NewDecoder(bytes.NewReader(text))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&job); err != nil {
+		return failedJob(job, errors.New(errors.ValidationError, "invalid batch job", err))
+	}
+	if job.User == "" {
+		return failedJob(job, errors.New(errors.ValidationError, "batch job has no user", nil))
+	}
+	if job.Years == "" {
+		job.Years = strconv.Itoa(time.Now().Year())
+	}
+	startYear, endYear, err := utils.ParseYearRange(job.Years)
+	if err != nil {
+		return failedJob(job, errors.New(errors.ValidationError, "invalid years", err))
+	}
+
+	var exporter export.Exporter
+	if job.Format != "" {
+		if exporter, err = export.Lookup(job.Format); err != nil {
+			return failedJob(job, err)
+		}
+	} else if inferred, ok := export.ForPath(job.Output); ok {
+		exporter = inferred
+	}
+	format := ""
+	if exporter != nil {
+		format = exporter.Extension()
+	}
+
+	output := utils.GenerateOutputFilename(job.User, startYear, endYear, job.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+		Format:   format,
+	})
+	err = GenerateSkyline(Options{
+		StartYear: startYear,
+		EndYear:   endYear,
+		User:      job.User,
+		Output:    output,
+		CacheDir:  opts.CacheDir,
+		Quiet:     true,
+		Format:    exporter,
+	})
+	if err != nil {
+		return failedJob(job, err)
+	}
+	return BatchResult{User: job.User, Years: job.Years, Output: output, OK: true}
+}
+
+// failedJob reports a job that failed with err.
+func failedJob(job BatchJob, err error) BatchResult {
+	return BatchResult{User: job.User, Years: job.Years, Error: err.Error(), ExitCode: errors.ExitCode(err)}
+}
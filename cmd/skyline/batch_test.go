@@ -0,0 +1,78 @@
+package skyline
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestRunBatch(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	jobs := strings.Join([]string{
+		`{"user": "octocat", "years": "2024"}`,
+		``,
+		`{"user": "hubot", "years": "2023-2024", "format": "obj", "output": "hubot"}`,
+		`{"user": "octocat", "years": "1999"}`,
+		`{"user": "octocat", "colour": "red"}`,
+		`not json`,
+	}, "\n")
+	var out bytes.Buffer
+	err := RunBatch(strings.NewReader(jobs), &out, BatchOptions{OutputDir: dir, CacheDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "3 of 5 batch jobs failed") {
+		t.Errorf("RunBatch() error = %v, want 3 of 5 jobs failed", err)
+	}
+
+	var results []BatchResult
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var result BatchResult
+		if err := decoder.Decode(&result); err != nil {
+			t.Fatalf("result is not a JSON line: %v", err)
+		}
+		results = append(results, result)
+	}
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want one per job: %+v", len(results), results)
+	}
+
+	// Results follow the input, numbered by line, blank lines included.
+	want := []struct {
+		line   int
+		ok     bool
+		output string
+	}{
+		{1, true, filepath.Join(dir, "octocat-2024-github-skyline.stl")},
+		{3, true, filepath.Join(dir, "hubot.obj")},
+		{4, false, ""},
+		{5, false, ""},
+		{6, false, ""},
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Line != w.line || got.OK != w.ok || got.Output != w.output {
+			t.Errorf("result %d = %+v, want line %d, ok %v, output %q", i, got, w.line, w.ok, w.output)
+		}
+		if got.OK {
+			if _, err := os.Stat(got.Output); err != nil {
+				t.Errorf("job on line %d should write %s: %v", got.Line, got.Output, err)
+			}
+		} else if got.Error == "" || got.ExitCode != errors.ExitValidation {
+			t.Errorf("failed job on line %d = %+v, want an error with the validation exit code", got.Line, got)
+		}
+	}
+}
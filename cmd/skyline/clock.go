@@ -0,0 +1,131 @@
+package skyline
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// generateClock creates a skyline of the hours of the day targetUser authored commits at
+// over the years, a tower per hour, in place of the contribution calendar. The towers
+// share the calendar's base, text and export options.
+func generateClock(client *github.Client, targetUser string, startYear, endYear int, opts Options, observer progress.Observer) error {
+	log := logger.GetLogger()
+
+	observer.OnFetchStart(targetUser, startYear, endYear)
+	var hours [24]int
+	for year := startYear; year <= endYear; year++ {
+		yearHours, err := client.FetchCommitHours(targetUser, year)
+		if err != nil {
+			return err
+		}
+		for hour, count := range yearHours {
+			hours[hour] += count
+		}
+		observer.OnYearFetched(year, false)
+	}
+	if _, err := reportRateLimit(client); err != nil {
+		return err
+	}
+
+	label := utils.FormatYearRange(startYear, endYear) + " by hour"
+	if !opts.DryRun && !opts.Quiet {
+		asciiArt, err := ascii.GenerateClock(hours, targetUser, label, !opts.ArtOnly)
+		if err != nil {
+			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
+				return warnErr
+			}
+		} else {
+			fmt.Println(asciiArt)
+		}
+	}
+	if opts.ArtOnly {
+		return nil
+	}
+
+	row := [][][]types.ContributionDay{types.ClockRow(hours)}
+	modelContributions := stl.BucketContributions(row, opts.Bucket)
+	stlOpts := stl.Options{
+		MaxMemory:   opts.MaxMemory,
+		Observer:    observer,
+		Text:        opts.Text,
+		Braille:     opts.Braille || opts.BrailleOnly,
+		OmitText:    opts.BrailleOnly,
+		EngraveText: opts.EngraveText,
+		Mirror:      opts.Mirror,
+		Base:        geometry.BaseOptions{Style: opts.BaseStyle, Connectors: opts.Connectors, Footprint: opts.Footprint},
+		Style:       stl.StyleClock,
+		HeightScale: opts.HeightScale,
+		Snap:        opts.Snap,
+		Decorators:  opts.Decorators,
+		Flags:       opts.Flags,
+	}
+	format := export.Default
+	if opts.Format != nil && opts.Format.Extension() != export.Default {
+		format = opts.Format.Extension()
+		stlOpts.Encoder = opts.Format
+	}
+	if opts.DryRun {
+		estimate := stl.EstimateModelWithOptions(row, targetUser, startYear, endYear, stlOpts)
+		size, err := stl.MeasureModel(modelContributions, targetUser, startYear, endYear, stlOpts)
+		if err != nil {
+			return err
+		}
+		return writeDryRun(os.Stdout, targetUser, startYear, endYear, estimate, printEstimate(size), opts.MaxMemory, false)
+	}
+
+	outputPath := utils.GenerateOutputFilename(targetUser+"-clock", startYear, endYear, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+		Format:   format,
+	})
+	if err := createOutputDir(outputPath); err != nil {
+		return err
+	}
+	if err := stl.GenerateSTLRangeWithOptions(modelContributions, outputPath, targetUser, startYear, endYear, stlOpts); err != nil {
+		return err
+	}
+
+	var sliced *types.PrintEstimate
+	if opts.Slice != nil {
+		result, err := opts.Slice.Slice(outputPath)
+		if err != nil {
+			return err
+		}
+		observer.OnWriteComplete(result.GCode)
+		if err := log.Info("Sliced with %s to %s: %s", opts.Slice.Kind, result.GCode, result); err != nil {
+			return err
+		}
+		sliced = result
+	}
+	if opts.SendTo != nil {
+		name, err := opts.SendTo.Send(outputPath, sliced)
+		if err != nil {
+			return err
+		}
+		if !opts.Quiet {
+			fmt.Printf("Sent %s to %s\n", name, opts.SendTo.Kind)
+		}
+	}
+	return nil
+}
+
+// clockConflicts reports whether opts asks for anything a clock cannot honour: other
+// sources than the GitHub API, or data other than the commits of whole years.
+func clockConflicts(opts Options) error {
+	if opts.Offline || opts.InputPath != "" || !opts.From.IsZero() || opts.DoubleSided != [2]int{} || len(opts.MergeAccounts) > 0 ||
+		opts.Metric != github.MetricContributions || opts.Breakdown != stl.BreakdownOff {
+		return errors.New(errors.ValidationError, "--style clock counts commits fetched for whole years and cannot be combined with --offline, --input, --from, --to, --double-sided, --merge-account, --metric or --breakdown", nil)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package skyline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestGenerateClock(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+
+	late := time.FixedZone("UTC-7", -7*60*60)
+	commits := []time.Time{
+		time.Date(2024, 3, 1, 9, 15, 0, 0, time.UTC),
+		time.Date(2024, 3, 2, 23, 40, 0, 0, late),
+	}
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser", Commits: commits}), nil
+	}
+
+	outputDir := t.TempDir()
+	if err := GenerateSkyline(Options{StartYear: 2023, EndYear: 2024, Style: stl.StyleClock, OutputDir: outputDir, Quiet: true}); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(outputDir, "testuser-clock-*2023-24*.stl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		entries, _ := os.ReadDir(outputDir)
+		t.Fatalf("expected one clock model, found %v", entries)
+	}
+	triangles, err := stl.ReadSTLBinary(matches[0])
+	if err != nil || len(triangles) == 0 {
+		t.Errorf("clock model is unreadable or empty: %v", err)
+	}
+}
+
+func TestClockConflicts(t *testing.T) {
+	err := GenerateSkyline(Options{StartYear: 2024, EndYear: 2024, Style: stl.StyleClock, Offline: true})
+	if got := errors.ExitCode(err); got != errors.ExitValidation || !strings.Contains(err.Error(), "--style clock") {
+		t.Errorf("GenerateSkyline() error = %v, want a --style clock validation error", err)
+	}
+}
@@ -0,0 +1,136 @@
+package skyline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// DiffOptions configures a model of the change in a user's contributions between two years.
+type DiffOptions struct {
+	Before       int    // Year compared against
+	After        int    // Year whose change from Before is modelled
+	User         string // Target user; empty means the authenticated user
+	Output       string // Output STL path; empty means a generated filename
+	OutputDir    string // Directory for the generated or relative output path
+	NameTemplate string // Filename template for generated names
+
+	// Observer is notified of fetch, geometry and write progress; nil ignores every event.
+	Observer progress.Observer
+}
+
+// GenerateDiff creates a delta model of how a user's contributions changed from one year to
+// another: days with more contributions rise as towers, and days with fewer sink into the
+// base.
+func GenerateDiff(opts DiffOptions) error {
+	log := logger.GetLogger()
+	observer := progress.OrNop(opts.Observer)
+	if opts.Before == opts.After {
+		return errors.New(errors.ValidationError, "the two years to compare must differ", nil)
+	}
+
+	client, err := github.InitializeGitHubClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize GitHub client")
+	}
+	targetUser := opts.User
+	if targetUser == "" {
+		if targetUser, err = client.GetAuthenticatedUser(); err != nil {
+			return errors.Wrap(err, "failed to get authenticated user")
+		}
+	}
+
+	observer.OnFetchStart(targetUser, opts.Before, opts.After)
+	var grids [2][][]types.ContributionDay
+	for i, year := range []int{opts.Before, opts.After} {
+		if grids[i], _, err = fetchContributionData(client, targetUser, year); err != nil {
+			return err
+		}
+		observer.OnYearFetched(year, false)
+	}
+
+	delta := deltaGrid(grids[0], grids[1])
+	if _, err := fmt.Println(describeDelta(delta, targetUser, opts.Before, opts.After)); err != nil {
+		return errors.New(errors.IOError, "failed to write summary", err)
+	}
+
+	outputPath := utils.GenerateOutputFilename(targetUser+"-diff", opts.Before, opts.After, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+	})
+	if err := createOutputDir(outputPath); err != nil {
+		return err
+	}
+	if err := log.Debug("Writing delta model of %d against %d to %s", opts.After, opts.Before, outputPath); err != nil {
+		return err
+	}
+	return stl.GenerateSTLRangeWithOptions([][][]types.ContributionDay{delta}, outputPath, targetUser, opts.Before, opts.After, stl.Options{
+		Observer: observer,
+		Delta:    true,
+	})
+}
+
+// deltaGrid returns each day's count in after less the count on the same weekday of the
+// same week of before, so the model shows how the rhythm of each week shifted. Days only
+// one year has are compared with zero, and keep that year's date.
+func deltaGrid(before, after [][]types.ContributionDay) [][]types.ContributionDay {
+	delta := make([][]types.ContributionDay, max(len(before), len(after)))
+	for w := range delta {
+		var days [7]*types.ContributionDay
+		add := func(grid [][]types.ContributionDay, sign int) {
+			if w >= len(grid) {
+				return
+			}
+			for i, day := range grid[w] {
+				slot := weekdayIndex(day, i)
+				if days[slot] == nil {
+					days[slot] = &types.ContributionDay{Date: day.Date}
+				}
+				days[slot].ContributionCount += sign * day.ContributionCount
+			}
+		}
+		add(after, 1)
+		add(before, -1)
+		for _, day := range days {
+			if day != nil {
+				delta[w] = append(delta[w], *day)
+			}
+		}
+	}
+	return delta
+}
+
+// weekdayIndex returns the weekday of a day from its date, Sunday first, or its index in
+// its week when the date is missing or invalid.
+func weekdayIndex(day types.ContributionDay, index int) int {
+	date, err := time.Parse("2006-01-02", day.Date)
+	if err != nil {
+		return min(index, 6)
+	}
+	return int(date.Weekday())
+}
+
+// describeDelta summarises a delta grid in a line, such as
+// "octocat 2023 → 2024: +120 contributions, 80 days up and 45 down".
+func describeDelta(delta [][]types.ContributionDay, username string, before, after int) string {
+	total, up, down := 0, 0, 0
+	for _, week := range delta {
+		for _, day := range week {
+			total += day.ContributionCount
+			switch {
+			case day.ContributionCount > 0:
+				up++
+			case day.ContributionCount < 0:
+				down++
+			}
+		}
+	}
+	return fmt.Sprintf("%s %d → %d: %+d contributions, %d days up and %d down", username, before, after, total, up, down)
+}
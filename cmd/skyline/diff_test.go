@@ -0,0 +1,77 @@
+package skyline
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestDeltaGrid(t *testing.T) {
+	day := func(date string, count int) types.ContributionDay {
+		return types.ContributionDay{Date: date, ContributionCount: count}
+	}
+	// 2023 starts on a Sunday and 2024 on a Monday, so their first weeks line up by weekday.
+	before := [][]types.ContributionDay{
+		{day("2023-01-01", 4), day("2023-01-02", 1), day("2023-01-03", 3)},
+		{day("2023-01-08", 2)},
+		{day("2023-01-15", 5)},
+	}
+	after := [][]types.ContributionDay{
+		{day("2024-01-01", 3), day("2024-01-02", 3)},
+		{day("2024-01-07", 2), day("2024-01-08", 6)},
+	}
+
+	want := [][]types.ContributionDay{
+		{day("2023-01-01", -4), day("2024-01-01", 2), day("2024-01-02", 0)},
+		{day("2024-01-07", 0), day("2024-01-08", 6)},
+		{day("2023-01-15", -5)},
+	}
+	got := deltaGrid(before, after)
+	if len(got) != len(want) {
+		t.Fatalf("deltaGrid() has %d weeks, want %d: %v", len(got), len(want), got)
+	}
+	for w := range want {
+		if len(got[w]) != len(want[w]) {
+			t.Fatalf("week %d = %v, want %v", w, got[w], want[w])
+		}
+		for d := range want[w] {
+			if got[w][d] != want[w][d] {
+				t.Errorf("week %d day %d = %+v, want %+v", w, d, got[w][d], want[w][d])
+			}
+		}
+	}
+
+	if line := describeDelta(got, "octocat", 2023, 2024); line != "octocat 2023 → 2024: -1 contributions, 2 days up and 2 down" {
+		t.Errorf("describeDelta() = %q", line)
+	}
+}
+
+func TestGenerateDiff(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	observer := &mocks.MockObserver{}
+	output := filepath.Join(t.TempDir(), "diff.stl")
+	if err := GenerateDiff(DiffOptions{Before: 2023, After: 2024, Output: output, Observer: observer}); err != nil {
+		t.Fatalf("GenerateDiff() error = %v", err)
+	}
+	events := strings.Join(observer.Events, "\n")
+	for _, want := range []string{"fetch-start testuser 2023-2024", "year-fetched 2024", "write " + output} {
+		if !strings.Contains(events, want) {
+			t.Errorf("observer events missing %q:\n%s", want, events)
+		}
+	}
+
+	if err := GenerateDiff(DiffOptions{Before: 2024, After: 2024, Output: output}); err == nil {
+		t.Error("GenerateDiff() expected error for the same year twice")
+	}
+}
@@ -0,0 +1,175 @@
+package skyline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// MinRankScale is the height scale of the last-ranked skyline's columns on a leaderboard
+// with ranked heights; the leader's towers keep their full height and the rest shrink
+// evenly towards it, on bases of the usual size.
+const MinRankScale = 0.5
+
+// OrgBatchOptions configures a leaderboard of an organization's top contributors.
+type OrgBatchOptions struct {
+	Org           string // Organization login
+	StartYear     int    // First year of the range
+	EndYear       int    // Last year of the range
+	Top           int    // Number of top contributors to generate skylines for
+	RankedHeights bool   // Scale each skyline's column heights by its rank, from full height down to MinRankScale
+	OutputDir     string // Directory for generated files
+	NameTemplate  string // Filename template for generated names
+	CacheDir      string // Optional cache location; empty means the default user cache
+}
+
+// LeaderboardResult reports the outcome of one contributor's skyline as a line of JSON,
+// in rank order.
+type LeaderboardResult struct {
+	Rank          int     `json:"rank,omitempty"` // Place on the leaderboard, from 1; zero when the contributions could not be fetched
+	User          string  `json:"user"`
+	Contributions int     `json:"contributions"`    // Contributions over the years, which set the rank
+	Scale         float64 `json:"scale,omitempty"`  // Height scale of the columns when scaled by rank
+	Output        string  `json:"output,omitempty"` // Path of the written model
+	OK            bool    `json:"ok"`
+	Error         string  `json:"error,omitempty"`
+	ExitCode      int     `json:"exitCode,omitempty"` // Exit code the skyline would have had on its own
+}
+
+// ranked is an organization member and their contributions over the years.
+type ranked struct {
+	user          string
+	contributions int
+}
+
+// RunOrgBatch ranks the members of an organization by their contributions over the years
+// and generates a skyline for each of the top ones, writing a LeaderboardResult per member
+// to out as it finishes. Every member's calendar is fetched, one request per member and
+// year, and cached, so the skylines are then generated from the cache. Members whose
+// contributions cannot be fetched are reported first, unranked; a failing member is
+// reported and the batch carries on, and the returned error then counts the failures.
+func RunOrgBatch(out io.Writer, opts OrgBatchOptions) error {
+	client, err := github.InitializeGitHubClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize GitHub client")
+	}
+	members, err := client.FetchOrgMembers(opts.Org)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s has no members visible to you", opts.Org), nil)
+	}
+
+	store := cache.Default()
+	if opts.CacheDir != "" {
+		store = cache.New(opts.CacheDir)
+	}
+
+	encoder := json.NewEncoder(out)
+	reported, failed := 0, 0
+	var board []ranked
+	for _, member := range members {
+		total, err := fetchMemberContributions(client, store, member, opts.StartYear, opts.EndYear)
+		if err != nil {
+			reported++
+			failed++
+			if err := encoder.Encode(LeaderboardResult{User: member, Error: err.Error(), ExitCode: errors.ExitCode(err)}); err != nil {
+				return errors.New(errors.IOError, "failed to write leaderboard result", err)
+			}
+			continue
+		}
+		board = append(board, ranked{member, total})
+	}
+
+	top := rankContributors(board, opts.Top)
+	for i, entry := range top {
+		reported++
+		result := LeaderboardResult{Rank: i + 1, User: entry.user, Contributions: entry.contributions}
+		if opts.RankedHeights {
+			result.Scale = rankScale(i, len(top))
+		}
+		output := utils.GenerateOutputFilename(entry.user, opts.StartYear, opts.EndYear, "", utils.OutputNaming{
+			Dir:      opts.OutputDir,
+			Template: opts.NameTemplate,
+		})
+		err := GenerateSkyline(Options{
+			StartYear:   opts.StartYear,
+			EndYear:     opts.EndYear,
+			User:        entry.user,
+			Output:      output,
+			CacheDir:    opts.CacheDir,
+			Offline:     true,
+			Quiet:       true,
+			HeightScale: result.Scale,
+		})
+		if err != nil {
+			failed++
+			result.Error, result.ExitCode = err.Error(), errors.ExitCode(err)
+		} else {
+			result.Output, result.OK = output, true
+		}
+		if err := encoder.Encode(result); err != nil {
+			return errors.New(errors.IOError, "failed to write leaderboard result", err)
+		}
+	}
+
+	if failed > 0 {
+		return errors.New(errors.GeneralError, fmt.Sprintf("%d of %d leaderboard members failed", failed, reported), nil)
+	}
+	return nil
+}
+
+// fetchMemberContributions fetches and caches a member's calendar for each year of the
+// range and returns their total contributions.
+func fetchMemberContributions(client *github.Client, store *cache.Cache, member string, startYear, endYear int) (int, error) {
+	total := 0
+	for year := startYear; year <= endYear; year++ {
+		weeks, _, err := loadOrFetchContributions(client, store, member, year, false)
+		if err != nil {
+			return 0, err
+		}
+		total += gridTotal(weeks)
+	}
+	return total, nil
+}
+
+// gridTotal sums the contributions of a year's grid.
+func gridTotal(weeks [][]types.ContributionDay) int {
+	total := 0
+	for _, week := range weeks {
+		for _, day := range week {
+			total += day.ContributionCount
+		}
+	}
+	return total
+}
+
+// rankContributors orders members by their contributions, most first with ties in login
+// order, and keeps the first top.
+func rankContributors(board []ranked, top int) []ranked {
+	board = append([]ranked(nil), board...)
+	sort.SliceStable(board, func(i, j int) bool {
+		if board[i].contributions != board[j].contributions {
+			return board[i].contributions > board[j].contributions
+		}
+		return board[i].user < board[j].user
+	})
+	return board[:min(top, len(board))]
+}
+
+// rankScale returns the height scale of the skyline at index i of n ranked ones: 1 for
+// the leader, falling evenly to MinRankScale for the last.
+func rankScale(i, n int) float64 {
+	if n < 2 {
+		return 1
+	}
+	return 1 - (1-MinRankScale)*float64(i)/float64(n-1)
+}
@@ -0,0 +1,96 @@
+package skyline
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestRunOrgBatch(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser", Members: []string{"octocat", "hubot", "monalisa"}}), nil
+	}
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	err := RunOrgBatch(&out, OrgBatchOptions{Org: "github", StartYear: 2024, EndYear: 2024, Top: 2, RankedHeights: true, OutputDir: dir, CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("RunOrgBatch() error = %v", err)
+	}
+
+	var results []LeaderboardResult
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var result LeaderboardResult
+		if err := decoder.Decode(&result); err != nil {
+			t.Fatalf("result is not a JSON line: %v", err)
+		}
+		results = append(results, result)
+	}
+	// Every member has the same contributions, so the tie is broken by login.
+	if len(results) != 2 || results[0].User != "hubot" || results[1].User != "monalisa" {
+		t.Fatalf("results = %+v, want hubot and monalisa", results)
+	}
+	for i, result := range results {
+		if result.Rank != i+1 || !result.OK || result.Contributions == 0 {
+			t.Errorf("result %d = %+v, want rank %d with contributions", i, result, i+1)
+		}
+		if want := filepath.Join(dir, result.User+"-2024-github-skyline.stl"); result.Output != want {
+			t.Errorf("result %d output = %s, want %s", i, result.Output, want)
+		}
+		if _, err := os.Stat(result.Output); err != nil {
+			t.Errorf("skyline of %s was not written: %v", result.User, err)
+		}
+	}
+	if results[0].Scale != 1 || results[1].Scale != MinRankScale {
+		t.Errorf("scales = %g, %g, want 1 and %g", results[0].Scale, results[1].Scale, MinRankScale)
+	}
+
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{}), nil
+	}
+	if err := RunOrgBatch(&out, OrgBatchOptions{Org: "empty", StartYear: 2024, EndYear: 2024, Top: 2}); errors.ExitCode(err) != errors.ExitValidation {
+		t.Errorf("RunOrgBatch() error = %v, want a validation error for an organization without members", err)
+	}
+}
+
+func TestRankContributors(t *testing.T) {
+	board := []ranked{{"octocat", 120}, {"hubot", 300}, {"monalisa", 120}, {"ghost", 5}}
+	var got []string
+	for _, entry := range rankContributors(board, 3) {
+		got = append(got, entry.user)
+	}
+	if want := []string{"hubot", "monalisa", "octocat"}; !slices.Equal(got, want) {
+		t.Errorf("rankContributors() = %v, want %v", got, want)
+	}
+	if n := len(rankContributors(board, 10)); n != len(board) {
+		t.Errorf("rankContributors() kept %d of %d members", n, len(board))
+	}
+}
+
+func TestRankScale(t *testing.T) {
+	for _, tt := range []struct {
+		i, n int
+		want float64
+	}{
+		{0, 1, 1},
+		{0, 3, 1},
+		{1, 3, (1 + MinRankScale) / 2},
+		{2, 3, MinRankScale},
+	} {
+		if got := rankScale(tt.i, tt.n); got != tt.want {
+			t.Errorf("rankScale(%d, %d) = %g, want %g", tt.i, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package skyline
+
+import (
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/publish"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// PublishOptions describes the listing created for the finished models.
+// Title and Description are templates using the placeholders of utils.ExpandTemplate.
+type PublishOptions struct {
+	Publisher   publish.Publisher // Service the models are uploaded to
+	Title       string            // Listing title template
+	Description string            // Listing description template
+	Tags        []string          // Listing tags
+	Draft       bool              // Upload without publishing the listing
+
+	// Preview places the camera and picks the colors of the uploaded preview image. Its
+	// thresholds are taken from the skyline's Options.
+	Preview stl.PreviewOptions
+}
+
+// publishModels renders a preview of the model next to it and uploads the preview and
+// the model files as a new listing.
+func publishModels(opts *PublishOptions, observer progress.Observer, rows [][][]types.ContributionDay, thresholds types.Thresholds, username string, startYear, endYear int, models []string) error {
+	previewPath := utils.PreviewFilename(models[0])
+	preview := opts.Preview
+	preview.Thresholds = thresholds
+	if err := stl.GeneratePreview(rows, previewPath, preview); err != nil {
+		return err
+	}
+	observer.OnWriteComplete(previewPath)
+
+	if err := logger.GetLogger().Info("Uploading %d files", len(models)+1); err != nil {
+		return err
+	}
+	url, err := opts.Publisher.Publish(publish.Model{
+		Title:       utils.ExpandTemplate(opts.Title, username, startYear, endYear),
+		Description: utils.ExpandTemplate(opts.Description, username, startYear, endYear),
+		Tags:        opts.Tags,
+		Files:       models,
+		Images:      []string{previewPath},
+		Draft:       opts.Draft,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Published to %s\n", url)
+	return nil
+}
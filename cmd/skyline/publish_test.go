@@ -0,0 +1,74 @@
+package skyline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/publish"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// recordingPublisher keeps the models it is asked to publish.
+type recordingPublisher struct {
+	models []publish.Model
+}
+
+func (p *recordingPublisher) Publish(model publish.Model) (string, error) {
+	p.models = append(p.models, model)
+	return "https://example.com/thing:1", nil
+}
+
+func TestGenerateSkylinePublish(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	publisher := &recordingPublisher{}
+	output := filepath.Join(t.TempDir(), "skyline.stl")
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    output,
+		CacheDir:  t.TempDir(),
+		Stand:     true,
+		Publish: &PublishOptions{
+			Publisher:   publisher,
+			Title:       "{user}'s skyline {range}",
+			Description: "Contributions by {user}",
+			Tags:        []string{"github"},
+		},
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+
+	if len(publisher.models) != 1 {
+		t.Fatalf("published %d models, want 1", len(publisher.models))
+	}
+	model := publisher.models[0]
+	if model.Title != "testuser's skyline 2024" || model.Description != "Contributions by testuser" {
+		t.Errorf("listing = %q, %q", model.Title, model.Description)
+	}
+	// The model, its stand and the rendered preview are all uploaded.
+	if len(model.Files) != 2 || model.Files[0] != output {
+		t.Errorf("uploaded files = %v", model.Files)
+	}
+	preview := filepath.Join(filepath.Dir(output), "skyline-preview.png")
+	if len(model.Images) != 1 || model.Images[0] != preview {
+		t.Errorf("uploaded images = %v, want %s", model.Images, preview)
+	}
+	if _, err := os.Stat(preview); err != nil {
+		t.Errorf("expected preview to be written: %v", err)
+	}
+}
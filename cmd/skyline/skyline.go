@@ -3,14 +3,34 @@
 package skyline
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/badges"
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/cache"
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/i18n"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/notify"
+	"github.com/github/gh-skyline/internal/outline"
+	"github.com/github/gh-skyline/internal/printserver"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/report"
+	"github.com/github/gh-skyline/internal/slicer"
 	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/types"
 	"github.com/github/gh-skyline/internal/utils"
 )
@@ -20,76 +40,1039 @@ type GitHubClientInterface interface {
 	GetAuthenticatedUser() (string, error)
 	GetUserJoinYear(username string) (int, error)
 	FetchContributions(username string, year int) (*types.ContributionsResponse, error)
+	FetchContributionsForDateRange(username string, start, end time.Time) (*types.ContributionsResponse, error)
+	FetchContributionBreakdown(username string, year int) (map[string]types.Breakdown, error)
+}
+
+// Options configures a single skyline generation run.
+type Options struct {
+	StartYear     int               // First year of the range
+	EndYear       int               // Last year of the range
+	User          string            // Target user; empty means the authenticated user
+	Full          bool              // Generate from the user's join year to the current year
+	From          time.Time         // First day of a date window replacing the years; zero means whole years
+	To            time.Time         // Last day of the date window, of any length; each year of it is a row
+	Output        string            // Output STL path; empty means a generated filename
+	OutputDir     string            // Directory for the generated or relative output path
+	NameTemplate  string            // Filename template for generated names, e.g. "{user}-{range}"
+	ArtOnly       bool              // Only print the ASCII preview
+	Describe      bool              // Print a prose summary of each year instead of the ASCII art
+	HeightmapPath string            // Optional 16-bit grayscale PNG heightmap destination
+	OutlinePath   string            // Optional SVG or DXF front-elevation outline destination
+	HeatmapPath   string            // Optional PNG contribution calendar destination
+	ReportPath    string            // Optional self-contained HTML report destination
+	GIFPath       string            // Optional animated GIF of the model building up
+	TooltipsPath  string            // Optional JSON sidecar of each tower's day and bounds, for viewers
+	Theme         stl.Theme         // Colors of the heatmap
+	ArchivePath   string            // Optional zip bundling the outputs, data and a manifest
+	SignKeyPath   string            // Optional SSH key used to sign the archive manifest
+	Resume        bool              // Reuse years cached by a previous, interrupted run
+	CacheDir      string            // Optional cache location; empty means the default user cache
+	MaxMemory     uint64            // Memory cap in bytes above which geometry is streamed; zero means no cap
+	DryRun        bool              // Fetch data and print a size estimate without writing any files
+	Offline       bool              // Generate from the cache alone, never touching the network
+	InputPath     string            // Optional contributions.json or archive to generate from instead of fetching
+	MergeAccounts []github.Account  // Other accounts, possibly on other hosts, whose contributions are added to the user's
+	Quiet         bool              // Print nothing but errors: no ASCII preview, achievements or upload notices
+	Orientation   ascii.Orientation // Layout of the ASCII preview
+	Badges        bool              // Emboss icons for earned achievements along the base edge
+	Avatar        bool              // Emboss the user's avatar before the username on the front
+	Stand         bool              // Also write a display stand STL next to the model
+
+	// Text places and sizes the embossed username and year.
+	Text geometry.TextOptions
+
+	Braille     bool // Also emboss the username and year in Grade-1 Braille
+	BrailleOnly bool // Emboss Braille instead of the visual username and year
+	EngraveText bool // Recess the username and year into the base instead of raising them
+
+	BaseStyle  geometry.BaseStyle // Corner finish of the base slab
+	TowerCap   geometry.TowerCap  // Top of each day's column, accented on each week's last active day
+	Style      stl.Style          // Shape of the contributions: towers, a smooth surface or bricks
+	Bucket     stl.Bucketing      // Mapping of daily counts to column heights
+	Thresholds types.Thresholds   // Counts grading days in the ASCII and PNG previews; nil scales to the busiest day
+	Connectors bool               // Add pegs and sockets so separately printed years join up
+	Layout     stl.Layout         // Arrangement of multiple years on the base
+	Wrap       int                // Split each stacked year into rows of about this many weeks; zero keeps a row per year
+	Separators bool               // Raise a low ridge between wrapped rows
+	WeekStart  time.Weekday       // First day of each week in the grid; the zero value is Sunday
+	Breakdown  stl.BreakdownMode  // Segment columns by contribution type, in the model or as separate files
+	Metric     github.Metric      // Daily count rendered as the skyline
+	Stats      bool               // Engrave the total and longest streak on the back of the base
+	Months     bool               // Engrave month initials along the front of the base
+	YearLabels stl.YearLabels     // Engrave each row's year beside it on a stacked model
+	Mirror     bool               // Run the weeks right to left, leaving text and the logo readable
+	Streaks    bool               // Fuse runs of consecutive active days into ridges
+	Inverted   bool               // Subtract the columns from a solid block, as a mold
+
+	// HighlightTop marks this many of the busiest days of the range with capped columns and
+	// a distinct block in the ASCII preview; zero marks none.
+	HighlightTop int
+
+	// Footprint is the underside of the base; Gridfinity grows it to whole grid units.
+	Footprint geometry.BaseFootprint
+
+	// HeightScale multiplies the column heights after normalization; zero leaves them as they are.
+	HeightScale float64
+
+	// Snap rounds the model's vertices to a grid this many millimetres apart; zero leaves them.
+	Snap float64
+
+	// Flags are the command-line flags recorded in the model's STL header.
+	Flags string
+
+	// Granularity sums the days of each week or month into a single tower, in the
+	// previews and the model alike; the zero value keeps a column per day. Monthly towers
+	// have their initials engraved in front of them.
+	Granularity types.Granularity
+
+	// DoubleSided holds the front and back years of a then-and-now print, replacing the
+	// range: the back year's row is turned to read from the back of the base, where it is
+	// labelled. Zero years generate the range as usual.
+	DoubleSided [2]int
+
+	// Decorators add custom geometry, such as a sponsor's logo, to the model before it is
+	// written; nil adds none.
+	Decorators []stl.Decorator
+
+	// Format writes the model in another file format, named by its extension; nil writes
+	// binary STL.
+	Format export.Exporter
+
+	// SendTo uploads the model, or its G-code when sliced with Slice or the server's
+	// slicer command, to a print server; nil skips sending.
+	SendTo *printserver.Server
+
+	// Slice slices the finished model to G-code beside it and reports the slicer's print
+	// time and filament estimate; nil skips slicing.
+	Slice *slicer.Slicer
+
+	// Notify posts a summary of the finished run to a webhook; nil skips notifying.
+	Notify *notify.Webhook
+
+	// Publish uploads the finished models and a preview to a model-sharing service;
+	// nil skips publishing.
+	Publish *PublishOptions
+
+	// Observer is notified of fetch, geometry and write progress; nil ignores every event.
+	Observer progress.Observer
+
+	// NewClient and NewClientForHost create the run's GitHub clients, such as ones going
+	// through a --ca-bundle transport; nil uses github.InitializeGitHubClient and
+	// github.InitializeGitHubClientForHost.
+	NewClient        github.ClientInitializer
+	NewClientForHost github.HostClientInitializer
+
+	// unchanged is consulted by watch mode once the years are fetched; returning true
+	// skips writing any output for this run.
+	unchanged func(years [][][]types.ContributionDay) bool
 }
 
 // GenerateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user
-func GenerateSkyline(startYear, endYear int, targetUser string, full bool, output string, artOnly bool) error {
+func GenerateSkyline(opts Options) error {
 	log := logger.GetLogger()
+	observer := progress.OrNop(opts.Observer)
+	startYear, endYear, targetUser := opts.StartYear, opts.EndYear, opts.User
+	newClient, newClientForHost := opts.NewClient, opts.NewClientForHost
+	if newClient == nil {
+		newClient = github.InitializeGitHubClient
+	}
+	if newClientForHost == nil {
+		newClientForHost = github.InitializeGitHubClientForHost
+	}
+
+	// Load the signing key up front so a bad key fails before any fetching happens.
+	var signer *bundle.Signer
+	if opts.SignKeyPath != "" {
+		if opts.ArchivePath == "" || gzipArchive(opts.ArchivePath) {
+			return errors.New(errors.ValidationError, "--sign-key requires a zip --archive", nil)
+		}
+		var err error
+		if signer, err = bundle.LoadSigner(opts.SignKeyPath); err != nil {
+			return err
+		}
+	}
+
+	if opts.Offline || opts.InputPath != "" {
+		if opts.Full || opts.Metric != github.MetricContributions || opts.Breakdown != stl.BreakdownOff || opts.Avatar {
+			return errors.New(errors.ValidationError, "--offline and --input cannot be combined with --full, --metric reviews, --metric discussions, --breakdown or --avatar, which need the network", nil)
+		}
+		if opts.Offline && (opts.SendTo != nil || opts.Publish != nil) {
+			return errors.New(errors.ValidationError, "--offline cannot be combined with uploads such as --send-to", nil)
+		}
+	}
+
+	if !opts.From.IsZero() && (opts.Full || opts.Offline || opts.InputPath != "" || opts.Resume || opts.Describe || opts.ArchivePath != "" ||
+		opts.Metric != github.MetricContributions || opts.Breakdown != stl.BreakdownOff) {
+		return errors.New(errors.ValidationError, "--from and --to cannot be combined with --full, --offline, --input, --resume, --describe, --archive, --metric reviews, --metric discussions or --breakdown, which work on whole years", nil)
+	}
+
+	if opts.DoubleSided != [2]int{} && (opts.Full || !opts.From.IsZero() || opts.Offline || opts.InputPath != "" || opts.ArchivePath != "" || opts.Notify != nil) {
+		return errors.New(errors.ValidationError, "--double-sided cannot be combined with --full, --from, --offline, --input, --archive or --notify-url, which work on a range of years", nil)
+	}
+
+	format := export.Default
+	if opts.Format != nil {
+		format = opts.Format.Extension()
+	}
+	if format != export.Default && opts.SendTo != nil {
+		return errors.New(errors.ValidationError, fmt.Sprintf("--format %s cannot be combined with --send-to, as print servers take STL files", format), nil)
+	}
 
-	client, err := github.InitializeGitHubClient()
+	if opts.Style == stl.StyleClock {
+		if err := clockConflicts(opts); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.MergeAccounts) > 0 && (opts.Offline || opts.InputPath != "" || opts.Metric != github.MetricContributions || opts.Breakdown != stl.BreakdownOff) {
+		return errors.New(errors.ValidationError, "--merge-account cannot be combined with --offline, --input, --metric reviews, --metric discussions or --breakdown", nil)
+	}
+
+	store := cache.Default()
+	if opts.CacheDir != "" {
+		store = cache.New(opts.CacheDir)
+	}
+
+	// Offline runs read every year up front, so missing years fail before anything is
+	// generated, and never create a client.
+	var client *github.Client
+	var offline map[int][][]types.ContributionDay
+	var err error
+	if opts.Offline || opts.InputPath != "" {
+		if targetUser, startYear, endYear, offline, err = loadOfflineContributions(opts, store); err != nil {
+			return err
+		}
+	} else {
+		if client, err = newClient(); err != nil {
+			return errors.Wrap(err, "failed to initialize GitHub client")
+		}
+
+		if targetUser == "" {
+			if err := log.Debug("No target user specified, using authenticated user"); err != nil {
+				return err
+			}
+			username, err := client.GetAuthenticatedUser()
+			if err != nil {
+				return errors.Wrap(err, "failed to get authenticated user")
+			}
+			targetUser = username
+		}
+
+		if opts.Full {
+			joinYear, err := client.GetUserJoinYear(targetUser)
+			if err != nil {
+				return errors.Wrap(err, "failed to get user join year")
+			}
+			startYear = joinYear
+			endYear = time.Now().Year()
+		}
+	}
+
+	if opts.Style == stl.StyleClock {
+		return generateClock(client, targetUser, startYear, endYear, opts, observer)
+	}
+
+	// Merged accounts are fetched with a client for their own host, authenticated
+	// separately, before anything is generated.
+	merged := make([]*github.Client, len(opts.MergeAccounts))
+	for i, account := range opts.MergeAccounts {
+		if merged[i], err = newClientForHost(account.Host); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to initialize GitHub client for %s", account.Host))
+		}
+	}
+
+	// A date window is labelled with its dates, however many years it touches, and laid
+	// out as a row for each year of it, named by the year the row starts in.
+	label := ""
+	windowed := !opts.From.IsZero()
+	var spans [][2]time.Time
+	if windowed {
+		startYear, endYear = opts.From.Year(), opts.To.Year()
+		label = utils.FormatDateRange(opts.From, opts.To)
+		spans = utils.SplitDateRange(opts.From, opts.To)
+	}
+
+	// A double-sided model has a row for each of its two years, the back one first, as
+	// rows run from the back of the base to the front.
+	years := make([]int, 0, endYear-startYear+1)
+	for year := startYear; year <= endYear; year++ {
+		years = append(years, year)
+	}
+	if windowed {
+		years = years[:0]
+		for _, span := range spans {
+			years = append(years, span[0].Year())
+		}
+	}
+	front, back := opts.DoubleSided[0], opts.DoubleSided[1]
+	doubleSided := front != 0 && back != 0
+	if doubleSided {
+		years = []int{back, front}
+		startYear, endYear = min(front, back), max(front, back)
+	}
+
+	observer.OnFetchStart(targetUser, startYear, endYear)
+
+	// Fetch times are summed over the years and logged once at debug level.
+	var fetchTime time.Duration
+
+	// A window is fetched whole, a year at a time, then split into its rows; the rows of
+	// each merged account follow the user's.
+	var windowRows [][][][]types.ContributionDay
+	if windowed {
+		fetchStart := time.Now()
+		weeks, err := fetchDateRangeData(client, targetUser, opts.From, opts.To)
+		if err != nil {
+			return err
+		}
+		windowRows = append(windowRows, types.SplitWeeks(weeks, spanStarts(spans)))
+		for i, account := range opts.MergeAccounts {
+			if weeks, err = fetchDateRangeData(merged[i], account.User, opts.From, opts.To); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("failed to fetch contributions for %s", account))
+			}
+			windowRows = append(windowRows, types.SplitWeeks(weeks, spanStarts(spans)))
+		}
+		fetchTime += time.Since(fetchStart)
+	}
+
+	var allContributions [][][]types.ContributionDay
+	for row, year := range years {
+		fetchStart := time.Now()
+		contributions, cached := offline[year], true
+		if windowed {
+			contributions, cached = windowRows[0][row], false
+		} else if client != nil {
+			contributions, cached, err = loadOrFetchContributions(client, store, targetUser, year, opts.Resume)
+			if err != nil {
+				if year > startYear && !doubleSided {
+					if infoErr := log.Info("Years %d-%d are cached; rerun with --resume to continue from %d", startYear, year-1, year); infoErr != nil {
+						return infoErr
+					}
+				}
+				return err
+			}
+			if contributions, err = client.ApplyMetric(contributions, targetUser, year, opts.Metric); err != nil {
+				return err
+			}
+		}
+		for i, account := range opts.MergeAccounts {
+			var extra [][]types.ContributionDay
+			if windowed {
+				extra = windowRows[i+1][row]
+			} else if extra, _, err = fetchContributionData(merged[i], account.User, year); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("failed to fetch contributions for %s", account))
+			}
+			contributions = types.AddCounts(contributions, extra)
+		}
+		if opts.Breakdown != stl.BreakdownOff && !opts.DryRun && !opts.ArtOnly {
+			counts, err := client.FetchContributionBreakdown(targetUser, year)
+			if err != nil {
+				return err
+			}
+			contributions = types.ApplyBreakdown(contributions, counts)
+		}
+		contributions = types.RebucketWeeks(contributions, opts.WeekStart)
+		allContributions = append(allContributions, contributions)
+		fetchTime += time.Since(fetchStart)
+		observer.OnYearFetched(year, cached)
+	}
+	if err := log.Timing("fetch", fetchTime); err != nil {
+		return err
+	}
+	budget, err := reportRateLimit(client)
 	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		return err
+	}
+
+	// The previews and the model show the same towers: days summed over each span of the
+	// granularity. Statistics, badges and saved data keep the daily counts.
+	grid := types.Aggregate(allContributions, opts.Granularity)
+
+	// Personal records are picked across the whole range, so the previews wait for
+	// every year.
+	var highlight map[string]bool
+	if opts.HighlightTop > 0 {
+		highlight = types.TopDays(allContributions, opts.HighlightTop)
 	}
 
-	if targetUser == "" {
-		if err := log.Debug("No target user specified, using authenticated user"); err != nil {
+	// The previews are kept for the report even when quiet runs print none.
+	var previews []string
+	if !opts.DryRun && (!opts.Quiet || opts.ReportPath != "") {
+		asciiStart := time.Now()
+		for i, contributions := range grid {
+			year := years[i]
+			var asciiArt string
+			if opts.Describe {
+				asciiArt, err = ascii.Describe(contributions, targetUser, year, opts.Metric.String(), time.Now())
+			} else {
+				asciiArt, err = ascii.GenerateASCIIWithOptions(contributions, targetUser, year, ascii.Options{
+					IncludeHeader:   i == 0 && !opts.ArtOnly,
+					IncludeUserInfo: !opts.ArtOnly,
+					Orientation:     opts.Orientation,
+					Thresholds:      opts.Thresholds,
+					Label:           rowLabel(spans, i),
+					Highlight:       highlight,
+				})
+			}
+			if err != nil {
+				if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
+					return warnErr
+				}
+			} else {
+				previews = append(previews, asciiArt)
+				if !opts.Quiet {
+					fmt.Println(asciiArt)
+				}
+			}
+		}
+		if err := log.Timing("ascii", time.Since(asciiStart)); err != nil {
 			return err
 		}
-		username, err := client.GetAuthenticatedUser()
+	}
+
+	if opts.unchanged != nil && opts.unchanged(allContributions) {
+		return nil
+	}
+
+	earned := badges.Evaluate(allContributions)
+	if !opts.DryRun && !opts.Quiet {
+		if err := writeAchievements(os.Stdout, earned); err != nil {
+			return err
+		}
+	}
+
+	// The heatmap is a 2D artifact of the real counts, so it is also written with --art-only.
+	labels := make([]string, len(allContributions))
+	for i := range labels {
+		labels[i] = strconv.Itoa(years[i])
+		if windowed {
+			labels[i] = rowLabel(spans, i)
+		}
+	}
+	heatmap := stl.HeatmapOptions{Theme: opts.Theme, Thresholds: opts.Thresholds, Labels: labels}
+	if opts.HeatmapPath != "" && !opts.DryRun {
+		if err := stl.GenerateHeatmap(allContributions, opts.HeatmapPath, heatmap); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.HeatmapPath)
+	}
+
+	// The model's geometry follows the bucketed counts; stats, badges and archives keep the
+	// real ones.
+	modelContributions := stl.BucketContributions(grid, opts.Bucket)
+
+	// The animation is a shareable picture of the model rather than the model itself, so
+	// it is also written with --art-only.
+	if opts.GIFPath != "" && !opts.DryRun {
+		animation := stl.DefaultPreviewOptions()
+		animation.Thresholds = opts.Thresholds
+		if err := stl.GenerateGIF(stl.ArrangeContributions(modelContributions, opts.Layout), opts.GIFPath, animation); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.GIFPath)
+	}
+
+	if opts.ArtOnly {
+		return nil
+	}
+
+	// The model's options, which its dry run estimates are made with too.
+	stlOpts := stl.Options{
+		MaxMemory:    opts.MaxMemory,
+		Observer:     observer,
+		Text:         opts.Text,
+		Braille:      opts.Braille || opts.BrailleOnly,
+		OmitText:     opts.BrailleOnly,
+		EngraveText:  opts.EngraveText,
+		MonthLabels:  opts.Months,
+		YearLabels:   opts.YearLabels,
+		Mirror:       opts.Mirror,
+		Highlight:    highlight,
+		TowerCap:     opts.TowerCap,
+		Base:         geometry.BaseOptions{Style: opts.BaseStyle, Connectors: opts.Connectors, Footprint: opts.Footprint},
+		Layout:       opts.Layout,
+		Wrap:         opts.Wrap,
+		Separators:   opts.Separators,
+		Breakdown:    opts.Breakdown,
+		Style:        opts.Style,
+		HeightScale:  opts.HeightScale,
+		Snap:         opts.Snap,
+		Decorators:   opts.Decorators,
+		MergeStreaks: opts.Streaks,
+		Granularity:  opts.Granularity,
+		Inverted:     opts.Inverted,
+		Label:        label,
+		Flags:        opts.Flags,
+	}
+	if format != export.Default {
+		// The generator writes binary STL itself, with the run's metadata in its header;
+		// other formats encode the assembled model.
+		stlOpts.Encoder = opts.Format
+	}
+	if windowed {
+		// The base has room for the dates in full, which read better than the compact label.
+		stlOpts.Label = utils.FormatDateSpan(opts.From, opts.To)
+	}
+	if doubleSided {
+		stlOpts.DoubleSided = true
+		stlOpts.Label, stlOpts.BackLabel = strconv.Itoa(front), strconv.Itoa(back)
+	}
+	if opts.Stats {
+		stlOpts.Text.Stats = badges.ComputeStats(allContributions).Line(opts.Metric.String())
+	}
+	if opts.DryRun {
+		estimate := stl.EstimateModelWithOptions(grid, targetUser, startYear, endYear, stlOpts)
+		size, err := stl.MeasureModel(modelContributions, targetUser, startYear, endYear, stlOpts)
 		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
+			return err
+		}
+		streamed := stl.StreamsByYear(len(stl.ArrangeContributions(allContributions, opts.Layout)), opts.Layout)
+		return writeDryRun(os.Stdout, targetUser, startYear, endYear, estimate, printEstimate(size), opts.MaxMemory, streamed)
+	}
+
+	// Heightmaps, outlines and stands follow the rows of the model rather than the years.
+	rows := stl.ArrangeContributions(modelContributions, opts.Layout)
+
+	if opts.HeightmapPath != "" {
+		if err := stl.GenerateHeightmap(rows, opts.HeightmapPath); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.HeightmapPath)
+	}
+
+	if opts.TooltipsPath != "" {
+		if err := stl.GenerateTooltips(grid, modelContributions, opts.TooltipsPath, targetUser, startYear, endYear, stlOpts); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.TooltipsPath)
+	}
+
+	if opts.OutlinePath != "" {
+		profile := outline.Profile(rows)
+		if opts.Mirror && opts.Layout != stl.LayoutSpiral {
+			profile = outline.Mirror(profile)
+		}
+		if err := outline.Write(opts.OutlinePath, profile); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.OutlinePath)
+		if err := log.Info("Outline written successfully to: %s", opts.OutlinePath); err != nil {
+			return err
 		}
-		targetUser = username
 	}
 
-	if full {
-		joinYear, err := client.GetUserJoinYear(targetUser)
+	// Generate filename
+	outputPath := utils.GenerateOutputFilename(targetUser, startYear, endYear, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+		Range:    strings.ReplaceAll(label, "/", "--"),
+		Format:   format,
+	})
+	if err := createOutputDir(outputPath); err != nil {
+		return err
+	}
+
+	// Generate the STL file
+	if opts.Badges {
+		if stlOpts.Badges, err = loadBadgeIcons(earned); err != nil {
+			return err
+		}
+	}
+	if opts.Avatar {
+		if stlOpts.Avatar, err = client.FetchAvatar(targetUser); err != nil {
+			return err
+		}
+	}
+	if err := stl.GenerateSTLRangeWithOptions(modelContributions, outputPath, targetUser, startYear, endYear, stlOpts); err != nil {
+		return err
+	}
+
+	models := []string{outputPath}
+	if opts.Breakdown == stl.BreakdownSplit {
+		paths, err := stl.GenerateBreakdownSTLs(rows, outputPath, opts.HeightScale, opts.Mirror)
 		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get user join year", err)
+			return err
+		}
+		for _, path := range paths {
+			observer.OnWriteComplete(path)
 		}
-		startYear = joinYear
-		endYear = time.Now().Year()
+		models = append(models, paths...)
+	}
+	if opts.Style == stl.StyleSilhouette {
+		// The plate's profile, for cutting it from sheet stock instead of printing it.
+		svgPath := utils.SilhouetteFilename(outputPath)
+		profile := outline.Profile(rows)
+		if opts.Mirror {
+			profile = outline.Mirror(profile)
+		}
+		if err := outline.Write(svgPath, profile); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(svgPath)
+	}
+	if opts.Stand {
+		standPath := utils.StandFilename(outputPath)
+		if err := stl.GenerateStand(rows, standPath); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(standPath)
+		models = append(models, standPath)
 	}
 
-	var allContributions [][][]types.ContributionDay
-	for year := startYear; year <= endYear; year++ {
-		contributions, err := fetchContributionData(client, targetUser, year)
+	summary := archiveSummary(targetUser, startYear, endYear, allContributions, earned, budget)
+	if opts.ArchivePath != "" || opts.Notify != nil || opts.ReportPath != "" {
+		size, err := stl.MeasureModel(modelContributions, targetUser, startYear, endYear, stlOpts)
 		if err != nil {
 			return err
 		}
-		allContributions = append(allContributions, contributions)
+		estimate := printEstimate(size)
+		summary.Print = &estimate
+	}
+	if opts.Slice != nil {
+		if summary.Slice, err = opts.Slice.Slice(outputPath); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(summary.Slice.GCode)
+		if err := log.Info("Sliced with %s to %s: %s", opts.Slice.Kind, summary.Slice.GCode, summary.Slice); err != nil {
+			return err
+		}
+	}
+
+	if opts.ReportPath != "" {
+		if err := writeReport(opts.ReportPath, modelContributions, targetUser, startYear, endYear, stlOpts, report.Report{
+			Summary:  summary,
+			Label:    reportLabel(startYear, endYear, label),
+			Heatmap:  stl.RenderHeatmap(allContributions, heatmap),
+			Previews: previews,
+		}); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(opts.ReportPath)
+	}
 
-		// Generate ASCII art for each year
-		asciiArt, err := ascii.GenerateASCII(contributions, targetUser, year, (year == startYear) && !artOnly, !artOnly)
+	if opts.ArchivePath != "" {
+		if err := writeArchive(opts, signer, targetUser, startYear, endYear, allContributions, rows, summary, models); err != nil {
+			return err
+		}
+	}
+	if opts.SendTo != nil {
+		name, err := opts.SendTo.Send(outputPath, summary.Slice)
 		if err != nil {
-			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
-				return warnErr
-			}
-		} else {
-			fmt.Println(asciiArt)
+			return err
+		}
+		if !opts.Quiet {
+			fmt.Printf("Sent %s to %s\n", name, opts.SendTo.Kind)
+		}
+	}
+	if opts.Publish != nil {
+		if err := publishModels(opts.Publish, observer, rows, opts.Thresholds, targetUser, startYear, endYear, models); err != nil {
+			return err
 		}
 	}
+	if opts.Notify != nil {
+		return notifyWebhook(opts, rows, summary, models)
+	}
+	return nil
+}
 
-	if !artOnly {
-		// Generate filename
-		outputPath := utils.GenerateOutputFilename(targetUser, startYear, endYear, output)
+// notifyWebhook posts the run's summary and every file it wrote to the --notify-url
+// webhook, with the rendered preview when asked for.
+func notifyWebhook(opts Options, rows [][][]types.ContributionDay, summary *bundle.Summary, models []string) error {
+	payload := notify.Payload{Summary: summary, Files: append([]string(nil), models...)}
+	for _, path := range []string{opts.HeightmapPath, opts.TooltipsPath, opts.OutlinePath, opts.HeatmapPath, opts.ReportPath, opts.ArchivePath} {
+		if path != "" {
+			payload.Files = append(payload.Files, path)
+		}
+	}
+	if opts.Style == stl.StyleSilhouette {
+		payload.Files = append(payload.Files, utils.SilhouetteFilename(models[0]))
+	}
+	if summary.Slice != nil {
+		payload.Files = append(payload.Files, summary.Slice.GCode)
+	}
+	if opts.Notify.Preview {
+		preview, err := renderPreviewPNG(rows, opts.Thresholds)
+		if err != nil {
+			return err
+		}
+		payload.Preview = notify.PreviewDataURL(preview)
+	}
+	if err := opts.Notify.Send(payload); err != nil {
+		return err
+	}
+	return logger.GetLogger().Info("Sent the run summary to the --notify-url webhook")
+}
+
+// spanStarts returns the first day of each span after the first, where the rows of a
+// window split.
+func spanStarts(spans [][2]time.Time) []string {
+	starts := make([]string, 0, len(spans))
+	for _, span := range spans[1:] {
+		starts = append(starts, span[0].Format(time.DateOnly))
+	}
+	return starts
+}
+
+// rowLabel returns the dates of a window's row, or "" for the rows of whole years.
+func rowLabel(spans [][2]time.Time, row int) string {
+	if row >= len(spans) {
+		return ""
+	}
+	return utils.FormatDateRange(spans[row][0], spans[row][1])
+}
+
+// writeReport assembles the model again, without writing it, to embed it in the HTML
+// report r at path.
+func writeReport(path string, contributions [][][]types.ContributionDay, username string, startYear, endYear int, stlOpts stl.Options, r report.Report) error {
+	// The model was already written with progress reported; rebuilding it stays quiet.
+	stlOpts.Observer = nil
+	model, err := stl.GenerateModel(contributions, username, startYear, endYear, stlOpts)
+	if err != nil {
+		return err
+	}
+	r.Model = model
+	if err := report.Write(path, r); err != nil {
+		return err
+	}
+	return logger.GetLogger().Info("Report written successfully to: %s", path)
+}
+
+// reportLabel names the range a report covers: the date window, or the years.
+func reportLabel(startYear, endYear int, window string) string {
+	if window != "" {
+		return window
+	}
+	return utils.FormatYearRange(startYear, endYear)
+}
+
+// createOutputDir creates the directory that will hold outputPath, if it is missing.
+func createOutputDir(outputPath string) error {
+	if dir := filepath.Dir(outputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return errors.New(errors.IOError, "failed to create output directory", err)
+		}
+	}
+	return nil
+}
+
+// writeAchievements lists the badges earned by the contribution history, if any.
+func writeAchievements(w io.Writer, earned []badges.Badge) error {
+	if len(earned) == 0 {
+		return nil
+	}
+	names := make([]string, len(earned))
+	for i, b := range earned {
+		names[i] = b.Name
+	}
+	if _, err := fmt.Fprintf(w, "Achievements unlocked: %s\n", strings.Join(names, ", ")); err != nil {
+		return errors.New(errors.IOError, "failed to write achievements", err)
+	}
+	return nil
+}
 
-		// Generate the STL file
-		if len(allContributions) == 1 {
-			return stl.GenerateSTL(allContributions[0], outputPath, targetUser, startYear)
+// loadBadgeIcons decodes the embedded icon of every earned badge, in badge order.
+func loadBadgeIcons(earned []badges.Badge) ([]image.Image, error) {
+	icons := make([]image.Image, 0, len(earned))
+	for _, b := range earned {
+		icon, err := b.LoadIcon()
+		if err != nil {
+			return nil, err
 		}
-		return stl.GenerateSTLRange(allContributions, outputPath, targetUser, startYear, endYear)
+		icons = append(icons, icon)
 	}
+	return icons, nil
+}
 
+// writeDryRun prints the preflight estimate for a model, its native print estimate and
+// which generation path the memory cap would select. Streamed models are written year by
+// year regardless of the cap.
+func writeDryRun(w io.Writer, username string, startYear, endYear int, estimate stl.Estimate, material types.PrintEstimate, maxMemory uint64, streamed bool) error {
+	mode := "in memory"
+	switch {
+	case streamed && (maxMemory == 0 || estimate.StreamingBytes <= maxMemory):
+		mode = "streaming"
+	case !streamed && (maxMemory == 0 || estimate.InMemoryBytes <= maxMemory):
+	case estimate.StreamingBytes <= maxMemory:
+		mode = "streaming"
+	default:
+		mode = "exceeds --max-memory"
+	}
+
+	_, err := fmt.Fprintf(w, `Dry run for %s, %s
+  Triangles:        %s
+  STL file size:    %s
+  Peak memory:      %s in memory, %s streaming
+  Generation mode:  %s
+  Filament:         %.0f g (%.1f m), estimated
+  Print time:       %s, estimated
+`, username, utils.FormatYearRange(startYear, endYear), i18n.FormatInt(estimate.Triangles),
+		utils.FormatByteSize(estimate.FileSize),
+		utils.FormatByteSize(estimate.InMemoryBytes),
+		utils.FormatByteSize(estimate.StreamingBytes),
+		mode,
+		material.FilamentWeight, material.FilamentLength/1000,
+		material.PrintTime)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to write dry run estimate", err)
+	}
 	return nil
 }
 
-// fetchContributionData retrieves and formats the contribution data for the specified year.
-func fetchContributionData(client *github.Client, username string, year int) ([][]types.ContributionDay, error) {
+// printEstimate estimates the filament use and print time of a model of the given size
+// with typical PLA settings, for comparing models without slicing them.
+func printEstimate(size stl.MeshSize) types.PrintEstimate {
+	return slicer.DefaultSettings().Estimate(size.Volume, size.Area, size.Height)
+}
+
+// writeArchive bundles the generated files, the contribution data, the run's summary and
+// a rendered preview into a zip with a manifest. A .gz archive holds the main model alone.
+func writeArchive(opts Options, signer *bundle.Signer, username string, startYear, endYear int, contributions, rows [][][]types.ContributionDay, summary *bundle.Summary, models []string) error {
+	if gzipArchive(opts.ArchivePath) {
+		if err := bundle.WriteGzip(opts.ArchivePath, models[0]); err != nil {
+			return err
+		}
+		progress.OrNop(opts.Observer).OnWriteComplete(opts.ArchivePath)
+		return logger.GetLogger().Info("Compressed model written successfully to: %s", opts.ArchivePath)
+	}
+
+	files := append([]string(nil), models...)
+	for _, path := range []string{opts.HeightmapPath, opts.TooltipsPath, opts.OutlinePath, opts.HeatmapPath, opts.ReportPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	if opts.Style == stl.StyleSilhouette {
+		files = append(files, utils.SilhouetteFilename(models[0]))
+	}
+	if summary.Slice != nil {
+		files = append(files, summary.Slice.GCode)
+	}
+
+	data := bundle.Contributions{User: username}
+	for i, weeks := range contributions {
+		data.Years = append(data.Years, bundle.YearContributions{Year: startYear + i, Weeks: weeks})
+	}
+
+	preview, err := renderPreviewPNG(rows, opts.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	if err := bundle.Write(opts.ArchivePath, bundle.Options{
+		Files:         files,
+		Contributions: data,
+		StartYear:     startYear,
+		EndYear:       endYear,
+		Signer:        signer,
+		Summary:       summary,
+		Preview:       preview,
+	}); err != nil {
+		return err
+	}
+	progress.OrNop(opts.Observer).OnWriteComplete(opts.ArchivePath)
+
+	return logger.GetLogger().Info("Archive written successfully to: %s", opts.ArchivePath)
+}
+
+// renderPreviewPNG renders the default preview of the rows as PNG data, for archives and
+// notifications.
+func renderPreviewPNG(rows [][][]types.ContributionDay, thresholds types.Thresholds) ([]byte, error) {
+	preview := stl.DefaultPreviewOptions()
+	preview.Thresholds = thresholds
+	img, err := stl.RenderPreview(rows, preview)
+	if err != nil {
+		return nil, err
+	}
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return nil, errors.New(errors.IOError, "failed to encode preview", err)
+	}
+	return data.Bytes(), nil
+}
+
+// reportRateLimit logs the GitHub API budget the client's queries left at debug level and
+// returns it, or nil when there is no client or the server reported none.
+func reportRateLimit(client *github.Client) (*types.RateLimit, error) {
+	if client == nil {
+		return nil, nil
+	}
+	budget, ok := client.RateLimit()
+	if !ok {
+		return nil, nil
+	}
+	if err := logger.GetLogger().Debug("GitHub API rate limit: %d of %d points remaining, resets at %s",
+		budget.Remaining, budget.Limit, budget.ResetAt.Local().Format(time.TimeOnly)); err != nil {
+		return nil, errors.Wrap(err, "failed to log debug message")
+	}
+	return &budget, nil
+}
+
+// gzipArchive reports whether an --archive path asks for the model gzipped rather than a zip.
+func gzipArchive(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gz")
+}
+
+// archiveSummary totals the contributions ([year][week][day], oldest first) for the
+// archive's summary.json, along with the API budget left after fetching them.
+func archiveSummary(username string, startYear, endYear int, contributions [][][]types.ContributionDay, earned []badges.Badge, budget *types.RateLimit) *bundle.Summary {
+	stats := badges.ComputeStats(contributions)
+	summary := &bundle.Summary{User: username, StartYear: startYear, EndYear: endYear, Total: stats.Total, LongestStreak: stats.LongestStreak, RateLimit: budget}
+	for _, b := range earned {
+		summary.Achievements = append(summary.Achievements, b.Name)
+	}
+	for i, weeks := range contributions {
+		year := bundle.YearSummary{Year: startYear + i}
+		for _, week := range weeks {
+			for _, day := range week {
+				year.Total += day.ContributionCount
+			}
+		}
+		summary.Years = append(summary.Years, year)
+	}
+	return summary
+}
+
+// loadOrFetchContributions returns the contribution grid for a year. When resuming, a
+// previously cached grid is reused if it was fetched from the client's host and still
+// holds the whole year, and, unless the client is signed out, was not approximated;
+// freshly fetched grids are cached for later resumes. The boolean result reports whether
+// the grid came from the cache.
+func loadOrFetchContributions(client *github.Client, store *cache.Cache, username string, year int, resume bool) ([][]types.ContributionDay, bool, error) {
+	log := logger.GetLogger()
+
+	if resume {
+		entry, ok, err := store.Load(client.Host(), username, year)
+		switch {
+		case err != nil:
+			if warnErr := log.Warning("Ignoring unreadable cache entry for %d: %v", year, err); warnErr != nil {
+				return nil, false, warnErr
+			}
+		case ok && !entry.Fresh(time.Now()):
+			if err := log.Info("Refetching %d, as the cached contributions were fetched before the year ended", year); err != nil {
+				return nil, false, err
+			}
+		case ok && entry.Approximate && !client.SignedOut():
+			if err := log.Info("Refetching %d, as the cached contributions were approximated", year); err != nil {
+				return nil, false, err
+			}
+		case ok:
+			if err := log.Info("Resuming with cached contributions for %d", year); err != nil {
+				return nil, false, err
+			}
+			return entry.Weeks, true, nil
+		}
+	}
+
+	contributions, approximate, err := fetchContributionData(client, username, year)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry := cache.Entry{Host: client.Host(), User: username, Year: year, Approximate: approximate, Weeks: contributions}
+	if err := store.Save(entry); err != nil {
+		if warnErr := log.Warning("Failed to cache contributions for %d: %v", year, err); warnErr != nil {
+			return nil, false, warnErr
+		}
+	}
+
+	return contributions, false, nil
+}
+
+// loadOfflineContributions reads every year of the range from the --input file, or else
+// from the cache, without touching the network. It returns the user the data belongs to
+// and the range, and fails listing the years that are missing. With an input file, a
+// zero range spans every year the file holds.
+func loadOfflineContributions(opts Options, store *cache.Cache) (username string, startYear, endYear int, years map[int][][]types.ContributionDay, err error) {
+	startYear, endYear, username = opts.StartYear, opts.EndYear, opts.User
+	years = map[int][][]types.ContributionDay{}
+
+	if opts.InputPath != "" {
+		input, err := bundle.ReadContributions(opts.InputPath)
+		if err != nil {
+			return "", 0, 0, nil, err
+		}
+		if username != "" && !strings.EqualFold(username, input.User) {
+			return "", 0, 0, nil, errors.New(errors.ValidationError, fmt.Sprintf("%s holds contributions for %s, not %s", opts.InputPath, input.User, username), nil)
+		}
+		if len(input.Years) == 0 {
+			return "", 0, 0, nil, errors.New(errors.ValidationError, fmt.Sprintf("%s holds no contributions", opts.InputPath), nil)
+		}
+		username = input.User
+		spanInput := startYear == 0 && endYear == 0
+		for i, y := range input.Years {
+			years[y.Year] = y.Weeks
+			if spanInput && (i == 0 || y.Year < startYear) {
+				startYear = y.Year
+			}
+			if spanInput && y.Year > endYear {
+				endYear = y.Year
+			}
+		}
+	} else if username == "" {
+		return "", 0, 0, nil, errors.New(errors.ValidationError, "--offline requires --user, as finding the authenticated user needs the network", nil)
+	}
+
+	var missing []string
+	for year := startYear; year <= endYear; year++ {
+		if _, ok := years[year]; ok {
+			continue
+		}
+		if opts.InputPath == "" {
+			entry, ok, err := store.Load(github.DefaultHost(), username, year)
+			if err != nil {
+				return "", 0, 0, nil, err
+			}
+			if ok {
+				if entry.Approximate {
+					if err := logger.GetLogger().Warning("Contributions cached for %d were approximated from the REST API and omit private and older activity", year); err != nil {
+						return "", 0, 0, nil, err
+					}
+				}
+				years[year] = entry.Weeks
+				continue
+			}
+		}
+		missing = append(missing, fmt.Sprint(year))
+	}
+	if len(missing) > 0 {
+		source := fmt.Sprintf("the cache in %s; run without --offline to fetch them", store.Dir())
+		if opts.InputPath != "" {
+			source = opts.InputPath
+		}
+		return "", 0, 0, nil, errors.New(errors.ValidationError, fmt.Sprintf("contributions for %s in %s are missing from %s", username, strings.Join(missing, ", "), source), nil)
+	}
+	return username, startYear, endYear, years, nil
+}
+
+// fetchContributionData retrieves and formats the contribution data for the specified
+// year. The boolean result reports whether the calendar was approximated.
+func fetchContributionData(client *github.Client, username string, year int) ([][]types.ContributionDay, bool, error) {
 	response, err := client.FetchContributions(username, year)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch contributions: %w", err)
+	}
+	grid, err := contributionGrid(response, strconv.Itoa(year))
+	return grid, response.Approximate, err
+}
+
+// fetchDateRangeData retrieves the contribution grid for the days from start to end.
+func fetchDateRangeData(client *github.Client, username string, start, end time.Time) ([][]types.ContributionDay, error) {
+	response, err := client.FetchContributionsForDateRange(username, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch contributions: %w", err)
 	}
+	return contributionGrid(response, utils.FormatDateRange(start, end))
+}
+
+// contributionGrid converts the weeks of a contributions response to the grid used for
+// generation, warning when the calendar for period was approximated.
+func contributionGrid(response *types.ContributionsResponse, period string) ([][]types.ContributionDay, error) {
+	if response.Approximate {
+		if err := logger.GetLogger().Warning("GraphQL is unavailable; contributions for %s are approximated from the REST API and omit private and older activity", period); err != nil {
+			return nil, err
+		}
+	}
 
 	// Convert weeks data to 2D array for STL generation
 	weeks := response.User.ContributionsCollection.ContributionCalendar.Weeks
@@ -1,11 +1,39 @@
 package skyline
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/badges"
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/notify"
+	"github.com/github/gh-skyline/internal/printserver"
+	"github.com/github/gh-skyline/internal/slicer"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
 )
 
 func TestGenerateSkyline(t *testing.T) {
@@ -21,6 +49,7 @@ func TestGenerateSkyline(t *testing.T) {
 		endYear    int
 		targetUser string
 		full       bool
+		heightmap  bool
 		mockClient *mocks.MockGitHubClient
 		wantErr    bool
 	}{
@@ -63,6 +92,18 @@ func TestGenerateSkyline(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:       "with extra exports and archive",
+			startYear:  2024,
+			endYear:    2024,
+			targetUser: "testuser",
+			heightmap:  true,
+			mockClient: &mocks.MockGitHubClient{
+				Username: "testuser",
+				JoinYear: 2020,
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,10 +113,1134 @@ func TestGenerateSkyline(t *testing.T) {
 				return github.NewClient(tt.mockClient), nil
 			}
 
-			err := GenerateSkyline(tt.startYear, tt.endYear, tt.targetUser, tt.full, "", false)
+			opts := Options{
+				StartYear: tt.startYear,
+				EndYear:   tt.endYear,
+				User:      tt.targetUser,
+				Full:      tt.full,
+				CacheDir:  t.TempDir(),
+			}
+			if tt.heightmap {
+				opts.HeightmapPath = filepath.Join(t.TempDir(), "depth.png")
+				opts.OutlinePath = filepath.Join(t.TempDir(), "skyline.svg")
+				opts.TooltipsPath = filepath.Join(t.TempDir(), "towers.json")
+				opts.ArchivePath = filepath.Join(t.TempDir(), "skyline.zip")
+				opts.Output = filepath.Join(t.TempDir(), "skyline.stl")
+				opts.Stand = true
+			}
+
+			err := GenerateSkyline(opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateSkyline() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			standPath := ""
+			if opts.Stand {
+				standPath = utils.StandFilename(opts.Output)
+			}
+			for _, path := range []string{opts.HeightmapPath, opts.TooltipsPath, opts.OutlinePath, opts.ArchivePath, standPath} {
+				if path == "" {
+					continue
+				}
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("expected %s to be written: %v", path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineResume(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+
+	// Every API call fails, so only cached years can be used.
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Err: fmt.Errorf("network down")}), nil
+	}
+
+	cacheDir := t.TempDir()
+	response := fixtures.GenerateContributionsResponse("testuser", 2024)
+	weeks := make([][]types.ContributionDay, len(response.User.ContributionsCollection.ContributionCalendar.Weeks))
+	for i, week := range response.User.ContributionsCollection.ContributionCalendar.Weeks {
+		weeks[i] = week.ContributionDays
+	}
+	if err := cache.New(cacheDir).Save(cache.Entry{User: "testuser", Year: 2024, Weeks: weeks}); err != nil {
+		t.Fatalf("failed to seed cache: %v", err)
+	}
+
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    filepath.Join(t.TempDir(), "resume.stl"),
+		CacheDir:  cacheDir,
+	}
+
+	if err := GenerateSkyline(opts); err == nil {
+		t.Error("GenerateSkyline() without --resume expected a fetch error")
+	}
+
+	observer := &mocks.MockObserver{}
+	opts.Resume = true
+	opts.Observer = observer
+	if err := GenerateSkyline(opts); err != nil {
+		t.Errorf("GenerateSkyline() with --resume error = %v", err)
+	}
+
+	events := strings.Join(observer.Events, "\n")
+	for _, want := range []string{"fetch-start testuser 2024-2024", "year-fetched 2024 cached=true", "geometry image 4/4", "write " + opts.Output} {
+		if !strings.Contains(events, want) {
+			t.Errorf("observer events missing %q:\n%s", want, events)
+		}
+	}
+
+	// Grids cached for another host, or approximated by a signed-out run, are refetched.
+	for name, entry := range map[string]cache.Entry{
+		"other host":  {Host: "ghe.example.com", User: "testuser", Year: 2024, Weeks: weeks},
+		"approximate": {User: "testuser", Year: 2024, Approximate: true, Weeks: weeks},
+	} {
+		store := cache.New(t.TempDir())
+		if err := store.Save(entry); err != nil {
+			t.Fatal(err)
+		}
+		opts.CacheDir = store.Dir()
+		if err := GenerateSkyline(opts); err == nil {
+			t.Errorf("%s: GenerateSkyline() with --resume reused the cached grid", name)
+		}
+	}
+}
+
+func TestGenerateSkylineOffline(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		t.Fatal("offline run created a GitHub client")
+		return nil, nil
+	}
+
+	cacheDir := t.TempDir()
+	response := fixtures.GenerateContributionsResponse("testuser", 2024)
+	weeks := make([][]types.ContributionDay, len(response.User.ContributionsCollection.ContributionCalendar.Weeks))
+	for i, week := range response.User.ContributionsCollection.ContributionCalendar.Weeks {
+		weeks[i] = week.ContributionDays
+	}
+	if err := cache.New(cacheDir).Save(cache.Entry{User: "testuser", Year: 2024, Weeks: weeks}); err != nil {
+		t.Fatalf("failed to seed cache: %v", err)
+	}
+
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    filepath.Join(t.TempDir(), "offline.stl"),
+		CacheDir:  cacheDir,
+		Offline:   true,
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() offline error = %v", err)
+	}
+	if _, err := os.Stat(opts.Output); err != nil {
+		t.Errorf("offline run should write the model: %v", err)
+	}
+
+	opts.StartYear = 2021
+	err := GenerateSkyline(opts)
+	if err == nil || !strings.Contains(err.Error(), "2021, 2022, 2023") {
+		t.Errorf("GenerateSkyline() with missing years error = %v, want them listed", err)
+	}
+
+	for name, change := range map[string]func(o *Options){
+		"no user":     func(o *Options) { o.User = "" },
+		"full":        func(o *Options) { o.Full = true },
+		"reviews":     func(o *Options) { o.Metric = github.MetricReviews },
+		"discussions": func(o *Options) { o.Metric = github.MetricDiscussions },
+		"breakdown":   func(o *Options) { o.Breakdown = stl.BreakdownStacked },
+	} {
+		o := opts
+		o.StartYear = 2024
+		change(&o)
+		if err := GenerateSkyline(o); err == nil {
+			t.Errorf("GenerateSkyline() offline with %s expected an error", name)
+		}
+	}
+}
+
+func TestGenerateSkylineInput(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		t.Fatal("run from --input created a GitHub client")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "contributions.json")
+	data := `{"user": "mona", "years": [
+		{"year": 2022, "weeks": [[{"contributionCount": 4, "date": "2022-01-02"}]]},
+		{"year": 2023, "weeks": [[{"contributionCount": 2, "date": "2023-01-01"}]]}]}`
+	if err := os.WriteFile(input, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	observer := &mocks.MockObserver{}
+	opts := Options{
+		Output:    filepath.Join(dir, "input.stl"),
+		CacheDir:  t.TempDir(),
+		InputPath: input,
+		Observer:  observer,
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() from input error = %v", err)
+	}
+	if events := strings.Join(observer.Events, "\n"); !strings.Contains(events, "fetch-start mona 2022-2023") {
+		t.Errorf("input run should span the file's years:\n%s", events)
+	}
+
+	opts.User = "octocat"
+	if err := GenerateSkyline(opts); err == nil {
+		t.Error("GenerateSkyline() with another user's input expected an error")
+	}
+
+	opts.User, opts.StartYear, opts.EndYear = "", 2021, 2023
+	if err := GenerateSkyline(opts); err == nil || !strings.Contains(err.Error(), "2021") {
+		t.Errorf("GenerateSkyline() with a year missing from the input error = %v", err)
+	}
+}
+
+func TestGenerateSkylineDryRun(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	opts := Options{
+		StartYear:     2024,
+		EndYear:       2024,
+		User:          "testuser",
+		Output:        filepath.Join(t.TempDir(), "dry.stl"),
+		HeightmapPath: filepath.Join(t.TempDir(), "dry.png"),
+		CacheDir:      t.TempDir(),
+		DryRun:        true,
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	for _, path := range []string{opts.Output, opts.HeightmapPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("dry run should not write %s", path)
+		}
+	}
+}
+
+func TestGenerateSkylineQuiet(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	// The logger binds stdout when it is created, so create it before capturing.
+	logger.GetLogger()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    filepath.Join(t.TempDir(), "quiet.stl"),
+		CacheDir:  t.TempDir(),
+		Quiet:     true,
+	}
+	err = GenerateSkyline(opts)
+	os.Stdout = stdout
+	_ = writer.Close()
+	if err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+
+	var printed bytes.Buffer
+	if _, err := printed.ReadFrom(reader); err != nil {
+		t.Fatal(err)
+	}
+	if printed.Len() > 0 {
+		t.Errorf("quiet run printed %q", printed.String())
+	}
+	if _, err := os.Stat(opts.Output); err != nil {
+		t.Errorf("quiet run should still write the model: %v", err)
+	}
+}
+
+func TestGenerateSkylineHighlight(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	logger.GetLogger()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	err = GenerateSkyline(Options{StartYear: 2024, EndYear: 2024, User: "testuser", CacheDir: t.TempDir(), ArtOnly: true, HighlightTop: 5})
+	os.Stdout = stdout
+	_ = writer.Close()
+	if err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	var printed bytes.Buffer
+	if _, err := printed.ReadFrom(reader); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(printed.String(), string(ascii.HighlightBlock)); got != 5 {
+		t.Errorf("previews mark %d days, want 5:\n%s", got, printed.String())
+	}
+}
+
+func TestGenerateSkylineOutputDir(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "models")
+	opts := Options{
+		StartYear:    2024,
+		EndYear:      2024,
+		User:         "testuser",
+		OutputDir:    outputDir,
+		NameTemplate: "{user}/{start}",
+		CacheDir:     t.TempDir(),
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "testuser", "2024.stl")); err != nil {
+		t.Errorf("expected templated output in the output directory: %v", err)
+	}
+}
+
+func TestGenerateSkylineFormat(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	obj, err := export.Lookup("obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputDir := t.TempDir()
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", OutputDir: outputDir, CacheDir: t.TempDir(), Quiet: true, Format: obj}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "testuser-2024-github-skyline.obj"))
+	if err != nil {
+		t.Fatalf("expected the model with the format's extension: %v", err)
+	}
+	if !bytes.Contains(data, []byte("\nf 1 2 3\n")) {
+		t.Error("model should be written as OBJ faces")
+	}
+
+	opts.SendTo = &printserver.Server{}
+	if err := GenerateSkyline(opts); err == nil || !strings.Contains(err.Error(), "--send-to") {
+		t.Errorf("GenerateSkyline() with --send-to error = %v", err)
+	}
+}
+
+func TestGenerateSkylineGranularity(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	triangles := map[types.Granularity]int{}
+	for _, granularity := range []types.Granularity{types.GranularityDay, types.GranularityWeek, types.GranularityMonth} {
+		output := filepath.Join(dir, granularity.String()+".stl")
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: output, CacheDir: t.TempDir(), Quiet: true, Granularity: granularity}
+		if err := GenerateSkyline(opts); err != nil {
+			t.Fatalf("GenerateSkyline(%s) error = %v", granularity, err)
+		}
+		model, err := stl.ReadSTLBinary(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		triangles[granularity] = len(model)
+	}
+	// A tower per week replaces up to seven columns.
+	if triangles[types.GranularityWeek] >= triangles[types.GranularityDay] {
+		t.Errorf("weekly model has %d triangles, want fewer than the daily %d", triangles[types.GranularityWeek], triangles[types.GranularityDay])
+	}
+
+	// Monthly towers have their initials engraved, so they compare with daily columns
+	// under month labels.
+	output := filepath.Join(dir, "labelled.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: output, CacheDir: t.TempDir(), Quiet: true, Months: true}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline(month labels) error = %v", err)
+	}
+	labelled, err := stl.ReadSTLBinary(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if triangles[types.GranularityMonth] >= len(labelled) {
+		t.Errorf("monthly model has %d triangles, want fewer than the labelled daily %d", triangles[types.GranularityMonth], len(labelled))
+	}
+}
+
+func TestGenerateSkylineDateRange(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	outputDir := t.TempDir()
+	opts := Options{
+		User:      "testuser",
+		From:      time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		OutputDir: outputDir,
+		CacheDir:  t.TempDir(),
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(outputDir, "*2023-11-01--2024-02-29*.stl"))
+	if err != nil || len(matches) != 1 {
+		t.Errorf("expected one model named for the window, got %v", matches)
+	}
+
+	opts.Full = true
+	if err := GenerateSkyline(opts); err == nil {
+		t.Error("expected a date window combined with --full to be rejected")
+	}
+}
+
+func TestGenerateSkylineLongDateRange(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	// Eighteen months are fetched a year at a time and laid out as a row per year of them.
+	depth := map[string]float64{}
+	for name, from := range map[string]time.Time{
+		"year":            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"eighteen months": time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		observer := &mocks.MockObserver{}
+		opts := Options{
+			User:     "testuser",
+			From:     from,
+			To:       time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			Output:   filepath.Join(t.TempDir(), "window.stl"),
+			CacheDir: t.TempDir(),
+			Quiet:    true,
+			Observer: observer,
+		}
+		if err := GenerateSkyline(opts); err != nil {
+			t.Fatalf("%s: GenerateSkyline() error = %v", name, err)
+		}
+		model, err := stl.ReadSTLBinary(opts.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		minY, maxY := math.Inf(1), math.Inf(-1)
+		for _, tri := range model {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				minY, maxY = min(minY, v.Y), max(maxY, v.Y)
+			}
+		}
+		depth[name] = maxY - minY
+
+		if name == "eighteen months" {
+			events := strings.Join(observer.Events, "\n")
+			for _, want := range []string{"fetch-start testuser 2023-2024", "year-fetched 2023 cached=false", "year-fetched 2024 cached=false"} {
+				if !strings.Contains(events, want) {
+					t.Errorf("observer events missing %q:\n%s", want, events)
+				}
+			}
+		}
+	}
+	if depth["eighteen months"] <= depth["year"] {
+		t.Errorf("eighteen-month model is %.1f mm deep, want deeper than the one-year %.1f mm", depth["eighteen months"], depth["year"])
+	}
+}
+
+func TestGenerateSkylineMergeAccounts(t *testing.T) {
+	originalInit, originalHostInit := github.InitializeGitHubClient, github.InitializeGitHubClientForHost
+	defer func() {
+		github.InitializeGitHubClient, github.InitializeGitHubClientForHost = originalInit, originalHostInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+	var hosts []string
+	github.InitializeGitHubClientForHost = func(host string) (*github.Client, error) {
+		hosts = append(hosts, host)
+		return github.NewClient(&mocks.MockGitHubClient{Username: "work"}), nil
+	}
+
+	dir := t.TempDir()
+	opts := Options{
+		StartYear:     2024,
+		EndYear:       2024,
+		User:          "testuser",
+		Output:        filepath.Join(dir, "merged.stl"),
+		ArchivePath:   filepath.Join(dir, "merged.zip"),
+		CacheDir:      t.TempDir(),
+		MergeAccounts: []github.Account{{Host: "ghe.example.com", User: "work"}},
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if len(hosts) != 1 || hosts[0] != "ghe.example.com" {
+		t.Errorf("clients created for %v, want ghe.example.com", hosts)
+	}
+
+	// Both mocks serve the same calendar, so every merged day is doubled.
+	data, err := bundle.ReadContributions(opts.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var single, merged int
+	for _, week := range fixtures.GenerateContributionsResponse("testuser", time.Now().Year()).User.ContributionsCollection.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			single += day.ContributionCount
+		}
+	}
+	for _, week := range data.Years[0].Weeks {
+		for _, day := range week {
+			merged += day.ContributionCount
+		}
+	}
+	if single == 0 || merged != 2*single {
+		t.Errorf("merged total = %d, want twice %d", merged, single)
+	}
+
+	opts.Offline = true
+	if err := GenerateSkyline(opts); err == nil {
+		t.Error("expected --merge-account combined with --offline to be rejected")
+	}
+}
+
+func TestGenerateSkylineImagesArtOnly(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	opts := Options{
+		StartYear:   2024,
+		EndYear:     2024,
+		User:        "testuser",
+		OutputDir:   dir,
+		HeatmapPath: filepath.Join(dir, "calendar.png"),
+		GIFPath:     filepath.Join(dir, "skyline.gif"),
+		Theme:       stl.ThemeDark,
+		ArtOnly:     true,
+		CacheDir:    t.TempDir(),
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if _, err := os.Stat(opts.HeatmapPath); err != nil {
+		t.Errorf("expected the heatmap to be written: %v", err)
+	}
+	if _, err := os.Stat(opts.GIFPath); err != nil {
+		t.Errorf("expected the animation to be written: %v", err)
+	}
+	if models, _ := filepath.Glob(filepath.Join(dir, "*.stl")); len(models) > 0 {
+		t.Errorf("art-only run wrote models %v", models)
+	}
+}
+
+func TestGenerateSkylineReport(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	opts := Options{
+		StartYear:  2024,
+		EndYear:    2024,
+		User:       "testuser",
+		OutputDir:  dir,
+		ReportPath: filepath.Join(dir, "report.html"),
+		Quiet:      true,
+		CacheDir:   t.TempDir(),
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	data, err := os.ReadFile(opts.ReportPath)
+	if err != nil {
+		t.Fatalf("expected the report to be written: %v", err)
+	}
+	// Quiet runs print no previews, but the report still shows them.
+	for _, want := range []string{"<h1>testuser's GitHub Skyline 2024</h1>", `data-model="Z2xURg`, "data:image/png;base64,", "<pre>"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+}
+
+func TestGenerateSkylineArchiveContents(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	opts := Options{
+		StartYear:   2024,
+		EndYear:     2024,
+		User:        "testuser",
+		Output:      filepath.Join(dir, "skyline.stl"),
+		ArchivePath: filepath.Join(dir, "skyline.zip"),
+		CacheDir:    t.TempDir(),
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	result, err := bundle.Verify(opts.ArchivePath, nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	names := map[string]bool{}
+	for _, f := range result.Manifest.Files {
+		names[f.Name] = true
+	}
+	for _, want := range []string{"skyline.stl", bundle.ContributionsName, bundle.SummaryName, bundle.PreviewName} {
+		if !names[want] {
+			t.Errorf("archive is missing %s: %v", want, result.Manifest.Files)
+		}
+	}
+
+	// A .gz archive holds the model alone, and cannot be signed.
+	opts.ArchivePath = filepath.Join(dir, "skyline.stl.gz")
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() with a .gz archive error = %v", err)
+	}
+	if info, err := os.Stat(opts.ArchivePath); err != nil || info.Size() == 0 {
+		t.Errorf("expected a gzipped model: %v", err)
+	}
+	opts.SignKeyPath = filepath.Join(dir, "key.pem")
+	if err := GenerateSkyline(opts); err == nil {
+		t.Error("expected --sign-key with a .gz archive to be rejected")
+	}
+}
+
+func TestGenerateSkylineSlice(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	// The fake slicer writes the G-code tail PrusaSlicer ends with to the path after --output.
+	dir := t.TempDir()
+	command := filepath.Join(dir, "prusa-slicer")
+	script := "#!/bin/sh\nwhile [ \"$1\" != --output ]; do shift; done\nprintf '; filament used [mm] = 812.5\\n; estimated printing time (normal mode) = 1h 5m 0s\\n' > \"$2\"\n"
+	if err := os.WriteFile(command, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	// The print server is sent the G-code --slice wrote, not sliced with its own command.
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if _, header, err := r.FormFile("file"); err == nil {
+			received = header.Filename
+		}
+	}))
+	defer server.Close()
+
+	opts := Options{
+		StartYear:   2024,
+		EndYear:     2024,
+		User:        "testuser",
+		Output:      filepath.Join(dir, "skyline.stl"),
+		ArchivePath: filepath.Join(dir, "skyline.zip"),
+		CacheDir:    t.TempDir(),
+		Quiet:       true,
+		Slice:       &slicer.Slicer{Kind: slicer.KindPrusaSlicer, Command: command},
+		SendTo:      &printserver.Server{Kind: printserver.KindMoonraker, Host: server.URL, Slicer: "false {input} {output}", Client: server.Client()},
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if received != "skyline.gcode" {
+		t.Errorf("print server received %q, want skyline.gcode", received)
+	}
+	result, err := bundle.Verify(opts.ArchivePath, nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	// The G-code is bundled, and its estimate is part of the run summary.
+	names := map[string]bool{}
+	for _, f := range result.Manifest.Files {
+		names[f.Name] = true
+	}
+	if !names["skyline.gcode"] {
+		t.Errorf("archive is missing the G-code: %v", result.Manifest.Files)
+	}
+	archive, err := zip.OpenReader(opts.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = archive.Close() }()
+	file, err := archive.Open(bundle.SummaryName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	var summary struct {
+		Print map[string]any `json:"print"`
+		Slice map[string]any `json:"slice"`
+	}
+	if err := json.NewDecoder(file).Decode(&summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Slice["gcode"] != "skyline.gcode" || summary.Slice["printSeconds"] != float64(3900) || summary.Slice["filamentLength"] != 812.5 {
+		t.Errorf("summary slice = %v, want 3900 s and 812.5 mm for skyline.gcode", summary.Slice)
+	}
+	// The native estimate sits beside the slicer's, for comparison.
+	if weight, ok := summary.Print["filamentWeight"].(float64); !ok || weight <= 0 {
+		t.Errorf("summary print = %v, want a native filament estimate", summary.Print)
+	}
+}
+
+func TestGenerateSkylineBreakdownSplit(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	output := filepath.Join(t.TempDir(), "skyline.stl")
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    output,
+		CacheDir:  t.TempDir(),
+		Breakdown: stl.BreakdownSplit,
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+
+	// The mock reports no pull requests, issues or reviews, so every contribution is a commit.
+	if _, err := os.Stat(utils.BreakdownFilename(output, "commits")); err != nil {
+		t.Errorf("expected commits STL to be written: %v", err)
+	}
+	if _, err := os.Stat(utils.BreakdownFilename(output, "reviews")); !os.IsNotExist(err) {
+		t.Errorf("expected no reviews STL, got %v", err)
+	}
+}
+
+func TestWriteDryRun(t *testing.T) {
+	estimate := stl.Estimate{Triangles: 1000, FileSize: 50084, InMemoryBytes: 200 << 20, StreamingBytes: 40 << 20}
+
+	tests := []struct {
+		name      string
+		maxMemory uint64
+		streamed  bool
+		wantMode  string
+	}{
+		{"no cap", 0, false, "in memory"},
+		{"cap above estimate", 512 << 20, false, "in memory"},
+		{"cap forces streaming", 64 << 20, false, "streaming"},
+		{"cap too small", 1 << 20, false, "exceeds --max-memory"},
+		{"streamed by year", 0, true, "streaming"},
+		{"streamed under cap", 64 << 20, true, "streaming"},
+		{"streamed over cap", 1 << 20, true, "exceeds --max-memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeDryRun(&out, "octocat", 2020, 2024, estimate, types.PrintEstimate{PrintTime: 90 * time.Minute, FilamentLength: 4050, FilamentWeight: 12.3}, tt.maxMemory, tt.streamed); err != nil {
+				t.Fatalf("writeDryRun() error = %v", err)
+			}
+			for _, want := range []string{"octocat, 2020-24", "Triangles:        1,000", "200.0 MB in memory", "Generation mode:  " + tt.wantMode, "Filament:         12 g (4.0 m)", "Print time:       1h30m0s"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
 		})
 	}
 }
+
+func TestWriteAchievements(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeAchievements(&out, nil); err != nil {
+		t.Fatalf("writeAchievements() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output without badges, got %q", out.String())
+	}
+
+	earned := []badges.Badge{{ID: "a", Name: "First"}, {ID: "b", Name: "Second"}}
+	if err := writeAchievements(&out, earned); err != nil {
+		t.Fatalf("writeAchievements() error = %v", err)
+	}
+	if want := "Achievements unlocked: First, Second\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestLoadBadgeIcons(t *testing.T) {
+	icons, err := loadBadgeIcons(badges.All())
+	if err != nil {
+		t.Fatalf("loadBadgeIcons() error = %v", err)
+	}
+	if len(icons) != len(badges.All()) {
+		t.Errorf("got %d icons, want %d", len(icons), len(badges.All()))
+	}
+
+	if _, err := loadBadgeIcons([]badges.Badge{{ID: "missing", Icon: "missing.png"}}); err == nil {
+		t.Error("expected error for missing icon")
+	}
+}
+
+func TestGenerateSkylineStats(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	output := filepath.Join(t.TempDir(), "stats.stl")
+	plain := filepath.Join(t.TempDir(), "plain.stl")
+	for path, stats := range map[string]bool{output: true, plain: false} {
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: path, CacheDir: t.TempDir(), Stats: stats}
+		if err := GenerateSkyline(opts); err != nil {
+			t.Fatalf("GenerateSkyline() error = %v", err)
+		}
+	}
+
+	// Engraving the stats splits the back face of the base into more triangles.
+	withStats, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	without, err := os.Stat(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withStats.Size() <= without.Size() {
+		t.Errorf("stats model is %d bytes, want more than the plain %d", withStats.Size(), without.Size())
+	}
+}
+
+func TestGenerateSkylineAvatar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		avatar := image.NewGray(image.Rect(0, 0, 8, 8))
+		for i := range avatar.Pix {
+			avatar.Pix[i] = 0xff
+		}
+		_ = png.Encode(w, avatar)
+	}))
+	defer server.Close()
+
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+			Avatar:   server.URL + "/u/1",
+		}), nil
+	}
+
+	output := filepath.Join(t.TempDir(), "avatar.stl")
+	plain := filepath.Join(t.TempDir(), "plain.stl")
+	for path, avatar := range map[string]bool{output: true, plain: false} {
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: path, CacheDir: t.TempDir(), Avatar: avatar}
+		if err := GenerateSkyline(opts); err != nil {
+			t.Fatalf("GenerateSkyline() error = %v", err)
+		}
+	}
+
+	withAvatar, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	without, err := os.Stat(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withAvatar.Size() <= without.Size() {
+		t.Errorf("avatar model is %d bytes, want more than the plain %d", withAvatar.Size(), without.Size())
+	}
+
+	offline := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Offline: true, CacheDir: t.TempDir(), Avatar: true}
+	if err := GenerateSkyline(offline); errors.ExitCode(err) != errors.ExitValidation {
+		t.Errorf("GenerateSkyline() offline with --avatar error = %v, want a validation error", err)
+	}
+}
+
+func TestGenerateSkylineSendTo(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{
+			Username: "testuser",
+			MockData: fixtures.GenerateContributionsResponse("testuser", 2024),
+		}), nil
+	}
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if _, header, err := r.FormFile("file"); err == nil {
+			received = header.Filename
+		}
+	}))
+	defer server.Close()
+
+	opts := Options{
+		StartYear: 2024,
+		EndYear:   2024,
+		User:      "testuser",
+		Output:    filepath.Join(t.TempDir(), "skyline.stl"),
+		CacheDir:  t.TempDir(),
+		SendTo:    &printserver.Server{Kind: printserver.KindOctoPrint, Host: server.URL, APIKey: "key", Client: server.Client()},
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if received != "skyline.stl" {
+		t.Errorf("print server received %q, want skyline.stl", received)
+	}
+}
+
+func TestGenerateSkylineNotify(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	var payload notify.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	opts := Options{
+		StartYear:   2024,
+		EndYear:     2024,
+		User:        "testuser",
+		Output:      filepath.Join(dir, "skyline.stl"),
+		HeatmapPath: filepath.Join(dir, "heatmap.png"),
+		CacheDir:    t.TempDir(),
+		Notify:      &notify.Webhook{URL: server.URL, Preview: true, Client: server.Client()},
+	}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	if payload.Summary == nil || payload.Summary.User != "testuser" || payload.Summary.Total == 0 {
+		t.Errorf("webhook received summary %+v", payload.Summary)
+	}
+	if len(payload.Files) != 2 || payload.Files[0] != opts.Output || payload.Files[1] != opts.HeatmapPath {
+		t.Errorf("webhook received files %v, want the model and heatmap", payload.Files)
+	}
+	if payload.Preview == "" {
+		t.Error("webhook received no preview")
+	}
+}
+
+func TestReportRateLimit(t *testing.T) {
+	if budget, err := reportRateLimit(nil); budget != nil || err != nil {
+		t.Errorf("reportRateLimit(nil) = %v, %v, want nothing for offline runs", budget, err)
+	}
+
+	want := types.RateLimit{Limit: 5000, Cost: 1, Remaining: 4321, ResetAt: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)}
+	client := github.NewClient(&mocks.MockGitHubClient{Username: "testuser", RateLimit: &want})
+	if budget, err := reportRateLimit(client); budget != nil || err != nil {
+		t.Errorf("reportRateLimit() before any query = %v, %v, want nothing", budget, err)
+	}
+	if _, err := client.FetchContributions("testuser", 2024); err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+	budget, err := reportRateLimit(client)
+	if err != nil || budget == nil || *budget != want {
+		t.Fatalf("reportRateLimit() = %v, %v, want %+v", budget, err, want)
+	}
+
+	summary := archiveSummary("testuser", 2024, 2024, nil, nil, budget)
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"rateLimit":{"limit":5000,"cost":1,"remaining":4321`) {
+		t.Errorf("summary = %s, want the remaining budget", data)
+	}
+}
+
+func TestGenerateSkylineWrap(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir := t.TempDir()
+	widths := map[int]float64{}
+	for _, wrap := range []int{0, 26} {
+		output := filepath.Join(dir, fmt.Sprintf("wrap-%d.stl", wrap))
+		opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: output, CacheDir: t.TempDir(), Quiet: true, Wrap: wrap, Separators: wrap > 0}
+		if err := GenerateSkyline(opts); err != nil {
+			t.Fatalf("GenerateSkyline(wrap %d) error = %v", wrap, err)
+		}
+		model, err := stl.ReadSTLBinary(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inv := meshtest.Measure(model)
+		widths[wrap] = inv.Max.X - inv.Min.X
+	}
+	// Two rows of half a year sit on a base about half as wide.
+	if widths[26] >= 0.6*widths[0] {
+		t.Errorf("wrapped model is %v wide, want about half the unwrapped %v", widths[26], widths[0])
+	}
+}
+
+func TestGenerateSkylineDoubleSided(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	dir, cacheDir := t.TempDir(), t.TempDir()
+	opts := Options{StartYear: 2025, EndYear: 2025, User: "testuser", Output: filepath.Join(dir, "then-now.stl"), CacheDir: cacheDir, Quiet: true, DoubleSided: [2]int{2025, 2015}}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	// Only the two years are fetched, not the decade between them.
+	store := cache.New(cacheDir)
+	for year, want := range map[int]bool{2015: true, 2020: false, 2025: true} {
+		if _, ok, err := store.Load("", "testuser", year); err != nil || ok != want {
+			t.Errorf("cached %d = %v, %v, want %v", year, ok, err, want)
+		}
+	}
+	model, err := stl.ReadSTLBinary(opts.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, depth := geometry.CalculateGridDimensions(geometry.GridSize, 2); meshtest.Measure(model).Max.Y < depth {
+		t.Errorf("double-sided model is %v deep, want two rows, %v", meshtest.Measure(model).Max.Y, depth)
+	}
+
+	opts.Full = true
+	if err := GenerateSkyline(opts); err == nil || !strings.Contains(err.Error(), "--double-sided") {
+		t.Errorf("GenerateSkyline(--full) error = %v, want a --double-sided validation error", err)
+	}
+}
+
+func TestGenerateSkylineSilhouette(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	output := filepath.Join(t.TempDir(), "silhouette.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, User: "testuser", Output: output, CacheDir: t.TempDir(), Quiet: true, Style: stl.StyleSilhouette}
+	if err := GenerateSkyline(opts); err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+	// The plate's profile is written beside it for cutting.
+	svg, err := os.ReadFile(utils.SilhouetteFilename(output))
+	if err != nil {
+		t.Fatalf("silhouette profile not written: %v", err)
+	}
+	if !strings.Contains(string(svg), "<path d=\"M") {
+		t.Errorf("silhouette profile = %q, want an SVG path", svg)
+	}
+}
@@ -0,0 +1,125 @@
+package skyline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// now returns the current time; tests replace it to pin the current week.
+var now = time.Now
+
+// StarsOptions configures a stars-over-time skyline for a repository.
+type StarsOptions struct {
+	Owner        string // Repository owner
+	Repo         string // Repository name
+	StartYear    int    // First year of the range; zero means the year of the first star
+	EndYear      int    // Last year of the range; zero means the current year
+	Output       string // Output STL path; empty means a generated filename
+	OutputDir    string // Directory for the generated or relative output path
+	NameTemplate string // Filename template for generated names
+	ArtOnly      bool   // Only print the ASCII preview
+
+	// Observer is notified of geometry and write progress; nil ignores every event.
+	Observer progress.Observer
+}
+
+// GenerateStars creates a skyline of a repository's stargazers, where each week is a
+// tower as tall as the number of stars it received.
+func GenerateStars(opts StarsOptions) error {
+	log := logger.GetLogger()
+	observer := progress.OrNop(opts.Observer)
+	repository := opts.Owner + "/" + opts.Repo
+
+	client, err := github.InitializeGitHubClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize GitHub client")
+	}
+
+	if err := log.Info("Fetching stargazers of %s", repository); err != nil {
+		return err
+	}
+	starredAt, err := client.FetchStargazers(opts.Owner, opts.Repo)
+	if err != nil {
+		return err
+	}
+	if len(starredAt) == 0 {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s has no stargazers", repository), nil)
+	}
+
+	startYear, endYear := opts.StartYear, opts.EndYear
+	if startYear == 0 {
+		startYear = starredAt[0].UTC().Year()
+	}
+	if endYear == 0 {
+		endYear = now().Year()
+	}
+
+	grids := make([][][]types.ContributionDay, 0, endYear-startYear+1)
+	for year := startYear; year <= endYear; year++ {
+		grid := weeklyStarGrid(starredAt, year)
+		grids = append(grids, grid)
+
+		asciiArt, err := ascii.GenerateASCIIWithOptions(grid, repository, year, ascii.Options{
+			IncludeHeader:   (year == startYear) && !opts.ArtOnly,
+			IncludeUserInfo: !opts.ArtOnly,
+		})
+		if err != nil {
+			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
+				return warnErr
+			}
+		} else {
+			fmt.Println(asciiArt)
+		}
+	}
+
+	if opts.ArtOnly {
+		return nil
+	}
+
+	outputPath := utils.GenerateOutputFilename(opts.Owner+"-"+opts.Repo+"-stars", startYear, endYear, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+	})
+	if err := createOutputDir(outputPath); err != nil {
+		return err
+	}
+
+	return stl.GenerateSTLRangeWithOptions(grids, outputPath, repository, startYear, endYear, stl.Options{Observer: observer})
+}
+
+// weeklyStarGrid builds a year's grid of Sunday-start weeks in which every day carries the
+// number of stars received during its week, so each week renders as a single tower.
+// Days after the current time are left empty.
+func weeklyStarGrid(starredAt []time.Time, year int) [][]types.ContributionDay {
+	weekly := map[time.Time]int{}
+	for _, t := range starredAt {
+		weekly[weekStart(t.UTC())]++
+	}
+
+	current := now().UTC()
+	grid := blankYearGrid(year)
+	for _, week := range grid {
+		for i, day := range week {
+			date, _ := time.Parse("2006-01-02", day.Date)
+			if !date.After(current) {
+				week[i].ContributionCount = weekly[weekStart(date)]
+			}
+		}
+	}
+	return grid
+}
+
+// weekStart returns midnight on the Sunday starting t's week.
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
@@ -0,0 +1,92 @@
+package skyline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// pinNow fixes the current time for the duration of a test.
+func pinNow(t *testing.T, at time.Time) {
+	t.Helper()
+	original := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = original })
+}
+
+func TestWeeklyStarGrid(t *testing.T) {
+	pinNow(t, time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC))
+	starredAt := []time.Time{
+		time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 13, 8, 0, 0, 0, time.UTC),
+	}
+
+	grid := weeklyStarGrid(starredAt, 2024)
+	if len(grid) != 53 {
+		t.Fatalf("grid has %d weeks, want 53", len(grid))
+	}
+	// 2024 starts on a Monday, so the first week is partial.
+	if len(grid[0]) != 6 || grid[0][0].Date != "2024-01-01" {
+		t.Errorf("first week = %v, want 6 days from 2024-01-01", grid[0])
+	}
+	for _, day := range grid[0] {
+		if day.ContributionCount != 2 {
+			t.Errorf("%s count = %d, want 2", day.Date, day.ContributionCount)
+		}
+	}
+
+	// The week of 2024-03-10 has three stars, up to the pinned current day.
+	week := grid[10]
+	if week[0].Date != "2024-03-10" {
+		t.Fatalf("week 10 starts on %s, want 2024-03-10", week[0].Date)
+	}
+	for i, want := range []int{3, 3, 3, 3, 0, 0, 0} {
+		if week[i].ContributionCount != want {
+			t.Errorf("%s count = %d, want %d", week[i].Date, week[i].ContributionCount, want)
+		}
+	}
+	if grid[1][0].ContributionCount != 0 {
+		t.Errorf("week without stars has count %d", grid[1][0].ContributionCount)
+	}
+}
+
+func TestGenerateStars(t *testing.T) {
+	pinNow(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+
+	stars := []time.Time{
+		time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC),
+	}
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Stars: stars}), nil
+	}
+
+	outputDir := t.TempDir()
+	if err := GenerateStars(StarsOptions{Owner: "octocat", Repo: "Hello-World", OutputDir: outputDir}); err != nil {
+		t.Fatalf("GenerateStars() error = %v", err)
+	}
+	// Years default to the first star through the current year.
+	matches, err := filepath.Glob(filepath.Join(outputDir, "octocat-Hello-World-stars-*2023-24*.stl"))
+	if err != nil || len(matches) != 1 {
+		entries, _ := os.ReadDir(outputDir)
+		t.Errorf("expected a 2023-24 stars model, found %v", entries)
+	}
+
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{}), nil
+	}
+	if err := GenerateStars(StarsOptions{Owner: "octocat", Repo: "empty", ArtOnly: true}); err == nil {
+		t.Error("GenerateStars() expected error for a repository without stars")
+	}
+}
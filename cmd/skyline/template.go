@@ -0,0 +1,95 @@
+package skyline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// TemplateOptions configures a blank skyline of years still to come.
+type TemplateOptions struct {
+	User         string // Name on the label; empty means the authenticated user
+	StartYear    int    // First year of the range
+	EndYear      int    // Last year of the range
+	Output       string // Output STL path; empty means a generated filename
+	OutputDir    string // Directory for the generated or relative output path
+	NameTemplate string // Filename template for generated names
+	ArtOnly      bool   // Only print the ASCII preview
+
+	// Observer is notified of geometry and write progress; nil ignores every event.
+	Observer progress.Observer
+}
+
+// GenerateTemplate creates a skyline without contributions: the base and its labels, with
+// a low dot marking each day of the years, to print as a blank canvas for the year ahead.
+// Nothing is fetched unless the label needs the authenticated user's name.
+func GenerateTemplate(opts TemplateOptions) error {
+	log := logger.GetLogger()
+	observer := progress.OrNop(opts.Observer)
+
+	user := opts.User
+	if user == "" {
+		client, err := github.InitializeGitHubClient()
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize GitHub client")
+		}
+		if user, err = client.GetAuthenticatedUser(); err != nil {
+			return errors.Wrap(err, "failed to get authenticated user")
+		}
+	}
+
+	grids := make([][][]types.ContributionDay, 0, opts.EndYear-opts.StartYear+1)
+	for year := opts.StartYear; year <= opts.EndYear; year++ {
+		grid := blankYearGrid(year)
+		grids = append(grids, grid)
+
+		asciiArt, err := ascii.GenerateASCIIWithOptions(grid, user, year, ascii.Options{
+			IncludeHeader:   (year == opts.StartYear) && !opts.ArtOnly,
+			IncludeUserInfo: !opts.ArtOnly,
+		})
+		if err != nil {
+			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
+				return warnErr
+			}
+		} else {
+			fmt.Println(asciiArt)
+		}
+	}
+
+	if opts.ArtOnly {
+		return nil
+	}
+
+	outputPath := utils.GenerateOutputFilename(user+"-template", opts.StartYear, opts.EndYear, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+	})
+	if err := createOutputDir(outputPath); err != nil {
+		return err
+	}
+
+	return stl.GenerateSTLRangeWithOptions(grids, outputPath, user, opts.StartYear, opts.EndYear, stl.Options{Template: true, Observer: observer})
+}
+
+// blankYearGrid builds a year's grid of Sunday-start weeks holding every day of the year
+// without contributions.
+func blankYearGrid(year int) [][]types.ContributionDay {
+	var grid [][]types.ContributionDay
+	var week []types.ContributionDay
+	for day := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Sunday && len(week) > 0 {
+			grid = append(grid, week)
+			week = nil
+		}
+		week = append(week, types.ContributionDay{Date: day.Format("2006-01-02")})
+	}
+	return append(grid, week)
+}
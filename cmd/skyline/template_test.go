@@ -0,0 +1,65 @@
+package skyline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestBlankYearGrid(t *testing.T) {
+	grid := blankYearGrid(2027)
+	if len(grid) != 53 {
+		t.Fatalf("grid has %d weeks, want 53", len(grid))
+	}
+	// 2027 starts on a Friday and ends on a Friday.
+	if len(grid[0]) != 2 || grid[0][0].Date != "2027-01-01" {
+		t.Errorf("first week = %v, want 2 days from 2027-01-01", grid[0])
+	}
+	if last := grid[len(grid)-1]; len(last) != 6 || last[len(last)-1].Date != "2027-12-31" {
+		t.Errorf("last week = %v, want 6 days to 2027-12-31", last)
+	}
+	for _, week := range grid {
+		for _, day := range week {
+			if day.ContributionCount != 0 {
+				t.Errorf("%s count = %d, want 0", day.Date, day.ContributionCount)
+			}
+		}
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	// The label defaults to the authenticated user.
+	outputDir := t.TempDir()
+	if err := GenerateTemplate(TemplateOptions{StartYear: 2027, EndYear: 2027, OutputDir: outputDir}); err != nil {
+		t.Fatalf("GenerateTemplate() error = %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(outputDir, "testuser-template-*2027*.stl"))
+	if err != nil || len(matches) != 1 {
+		entries, _ := os.ReadDir(outputDir)
+		t.Errorf("expected a 2027 template, found %v", entries)
+	}
+
+	// A named template needs no client.
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		t.Fatal("GenerateTemplate() initialized a client for a named template")
+		return nil, nil
+	}
+	output := filepath.Join(t.TempDir(), "canvas.stl")
+	if err := GenerateTemplate(TemplateOptions{User: "mona", StartYear: 2027, EndYear: 2028, Output: output}); err != nil {
+		t.Fatalf("GenerateTemplate() error = %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("template was not written: %v", err)
+	}
+}
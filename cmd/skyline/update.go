@@ -0,0 +1,158 @@
+package skyline
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// UpdateOptions configures a refresh of the current year's model.
+type UpdateOptions struct {
+	User         string // Target user; empty means the authenticated user
+	Output       string // Output STL path; empty means a generated filename
+	OutputDir    string // Directory for the generated or relative output path
+	NameTemplate string // Filename template for generated names
+	CacheDir     string // Optional cache location; empty means the default user cache
+	Force        bool   // Regenerate the model even when no contributions changed
+
+	// Observer is notified of fetch, geometry and write progress; nil ignores every event.
+	Observer progress.Observer
+}
+
+// dayChange is a day whose contribution count differs from the cached snapshot.
+type dayChange struct {
+	Date   string
+	Before int // Count in the snapshot, zero for days it did not have yet
+	After  int
+}
+
+// GenerateUpdate refreshes the model of the current year. It fetches only that year,
+// compares it with the grid cached by the previous run and prints the days that changed.
+// The model is regenerated from the fresh grid only when something changed or the model
+// is missing, so scheduled runs are cheap when there is nothing new.
+func GenerateUpdate(opts UpdateOptions) error {
+	log := logger.GetLogger()
+	observer := progress.OrNop(opts.Observer)
+	year := time.Now().Year()
+
+	store := cache.Default()
+	if opts.CacheDir != "" {
+		store = cache.New(opts.CacheDir)
+	}
+
+	client, err := github.InitializeGitHubClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize GitHub client")
+	}
+	targetUser := opts.User
+	if targetUser == "" {
+		if targetUser, err = client.GetAuthenticatedUser(); err != nil {
+			return errors.Wrap(err, "failed to get authenticated user")
+		}
+	}
+
+	snapshot, cached, err := store.Load(client.Host(), targetUser, year)
+	if err != nil {
+		if warnErr := log.Warning("Ignoring unreadable cache entry for %d: %v", year, err); warnErr != nil {
+			return warnErr
+		}
+	}
+
+	observer.OnFetchStart(targetUser, year, year)
+	contributions, approximate, err := fetchContributionData(client, targetUser, year)
+	if err != nil {
+		return err
+	}
+	observer.OnYearFetched(year, false)
+
+	var changes []dayChange
+	if cached {
+		changes = changedDays(snapshot.Weeks, contributions)
+	}
+	if _, err := fmt.Print(describeUpdate(changes, snapshot, targetUser, year)); err != nil {
+		return errors.New(errors.IOError, "failed to write summary", err)
+	}
+
+	outputPath := utils.GenerateOutputFilename(targetUser, year, year, opts.Output, utils.OutputNaming{
+		Dir:      opts.OutputDir,
+		Template: opts.NameTemplate,
+	})
+	_, statErr := os.Stat(outputPath)
+	if cached && len(changes) == 0 && statErr == nil && !opts.Force {
+		return log.Info("%s is up to date", outputPath)
+	}
+
+	// The fresh grid becomes the snapshot the next run compares against, and the model
+	// is generated from it without fetching the year again.
+	if err := store.Save(cache.Entry{Host: client.Host(), User: targetUser, Year: year, Approximate: approximate, Weeks: contributions}); err != nil {
+		return err
+	}
+	return GenerateSkyline(Options{
+		StartYear:    year,
+		EndYear:      year,
+		User:         targetUser,
+		Output:       opts.Output,
+		OutputDir:    opts.OutputDir,
+		NameTemplate: opts.NameTemplate,
+		CacheDir:     store.Dir(),
+		Offline:      true,
+		Quiet:        true,
+		Observer:     observer,
+	})
+}
+
+// changedDays lists, in date order, the days of after whose count differs from the same
+// date in before, including days before did not have yet.
+func changedDays(before, after [][]types.ContributionDay) []dayChange {
+	previous := map[string]int{}
+	for _, week := range before {
+		for _, day := range week {
+			previous[day.Date] = day.ContributionCount
+		}
+	}
+
+	var changes []dayChange
+	for _, week := range after {
+		for _, day := range week {
+			count := previous[day.Date]
+			if count == day.ContributionCount {
+				continue
+			}
+			changes = append(changes, dayChange{Date: day.Date, Before: count, After: day.ContributionCount})
+		}
+	}
+	return changes
+}
+
+// describeUpdate summarises the changes since the snapshot, one line for the total and
+// then one per day, such as "  2024-03-04: 2 → 5 (+3)".
+func describeUpdate(changes []dayChange, snapshot *cache.Entry, username string, year int) string {
+	if snapshot == nil {
+		return fmt.Sprintf("%s %d: no cached snapshot to compare with\n", username, year)
+	}
+	since := snapshot.FetchedAt.Local().Format(time.DateTime)
+	if len(changes) == 0 {
+		return fmt.Sprintf("%s %d: no changes since %s\n", username, year, since)
+	}
+
+	total := 0
+	var lines strings.Builder
+	for _, change := range changes {
+		total += change.After - change.Before
+		fmt.Fprintf(&lines, "  %s: %d → %d (%+d)\n", change.Date, change.Before, change.After, change.After-change.Before)
+	}
+	noun := "days"
+	if len(changes) == 1 {
+		noun = "day"
+	}
+	return fmt.Sprintf("%s %d: %d %s changed since %s, %+d contributions\n%s", username, year, len(changes), noun, since, total, lines.String())
+}
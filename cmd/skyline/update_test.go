@@ -0,0 +1,101 @@
+package skyline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestChangedDays(t *testing.T) {
+	day := func(date string, count int) types.ContributionDay {
+		return types.ContributionDay{Date: date, ContributionCount: count}
+	}
+	before := [][]types.ContributionDay{{day("2024-03-03", 1), day("2024-03-04", 2)}}
+	after := [][]types.ContributionDay{
+		{day("2024-03-03", 1), day("2024-03-04", 5)},
+		{day("2024-03-10", 0), day("2024-03-11", 4)},
+	}
+
+	want := []dayChange{{"2024-03-04", 2, 5}, {"2024-03-11", 0, 4}}
+	got := changedDays(before, after)
+	if len(got) != len(want) {
+		t.Fatalf("changedDays() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	snapshot := &cache.Entry{FetchedAt: time.Date(2024, 3, 9, 12, 0, 0, 0, time.Local)}
+	summary := describeUpdate(got, snapshot, "octocat", 2024)
+	for _, line := range []string{"octocat 2024: 2 days changed since 2024-03-09 12:00:00, +7 contributions", "  2024-03-04: 2 → 5 (+3)", "  2024-03-11: 0 → 4 (+4)"} {
+		if !strings.Contains(summary, line) {
+			t.Errorf("describeUpdate() missing %q:\n%s", line, summary)
+		}
+	}
+	if summary := describeUpdate(nil, snapshot, "octocat", 2024); !strings.Contains(summary, "no changes") {
+		t.Errorf("describeUpdate() without changes = %q", summary)
+	}
+	if summary := describeUpdate(nil, nil, "octocat", 2024); !strings.Contains(summary, "no cached snapshot") {
+		t.Errorf("describeUpdate() without a snapshot = %q", summary)
+	}
+}
+
+func TestGenerateUpdate(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	year := time.Now().Year()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	cacheDir := t.TempDir()
+	output := filepath.Join(t.TempDir(), "update.stl")
+	opts := UpdateOptions{Output: output, CacheDir: cacheDir}
+
+	// The first run has no snapshot, so it caches the year and writes the model.
+	if err := GenerateUpdate(opts); err != nil {
+		t.Fatalf("GenerateUpdate() error = %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("first update should write the model: %v", err)
+	}
+	store := cache.New(cacheDir)
+	snapshot, ok, err := store.Load("", "testuser", year)
+	if !ok || err != nil {
+		t.Fatalf("first update should cache the year, got %v, %v", ok, err)
+	}
+
+	// Nothing changed, so the model is left alone.
+	observer := &mocks.MockObserver{}
+	opts.Observer = observer
+	if err := GenerateUpdate(opts); err != nil {
+		t.Fatalf("GenerateUpdate() unchanged error = %v", err)
+	}
+	if events := strings.Join(observer.Events, "\n"); strings.Contains(events, "write ") {
+		t.Errorf("unchanged update should not regenerate the model:\n%s", events)
+	}
+
+	// A snapshot missing a contribution regenerates it.
+	snapshot.Weeks[10][3].ContributionCount++
+	if err := store.Save(*snapshot); err != nil {
+		t.Fatal(err)
+	}
+	observer.Events = nil
+	if err := GenerateUpdate(opts); err != nil {
+		t.Fatalf("GenerateUpdate() changed error = %v", err)
+	}
+	if events := strings.Join(observer.Events, "\n"); !strings.Contains(events, "write "+output) {
+		t.Errorf("changed update should regenerate the model:\n%s", events)
+	}
+}
@@ -0,0 +1,81 @@
+package skyline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// MinWatchInterval is the shortest pause allowed between watch cycles, so a watched
+// range cannot drain the API rate limit.
+const MinWatchInterval = time.Minute
+
+// WatchSkyline generates the skyline, then re-fetches the range every interval until ctx
+// is done, regenerating every output only when the contributions changed. Each cycle is
+// logged. A failing first cycle ends the watch, as it points at the options; later
+// failures, such as a network outage, are logged and retried at the next cycle.
+func WatchSkyline(ctx context.Context, opts Options, interval time.Duration) error {
+	log := logger.GetLogger()
+	if opts.Offline || opts.InputPath != "" || opts.Resume || opts.DryRun || opts.SendTo != nil || opts.Publish != nil {
+		return errors.New(errors.ValidationError, "--watch re-fetches the range and cannot be combined with --offline, --input, --resume, --dry-run or --send-to", nil)
+	}
+
+	var last [sha256.Size]byte
+	seen, changed := false, false
+	opts.unchanged = func(years [][][]types.ContributionDay) bool {
+		sum := fingerprint(years)
+		changed = !seen || sum != last
+		last, seen = sum, true
+		return !changed
+	}
+
+	for cycle := 1; ; cycle++ {
+		start := time.Now()
+		changed = false
+		err := GenerateSkyline(opts)
+		switch {
+		case err != nil && cycle == 1:
+			return err
+		case err != nil:
+			// Outputs may be missing or stale, so the next cycle regenerates them.
+			seen = false
+			if warnErr := log.Warning("Watch cycle %d failed: %v", cycle, err); warnErr != nil {
+				return warnErr
+			}
+		case changed:
+			if err := log.Info("Watch cycle %d: contributions changed; outputs regenerated in %s", cycle, time.Since(start).Round(time.Millisecond)); err != nil {
+				return err
+			}
+		default:
+			if err := log.Info("Watch cycle %d: no changes", cycle); err != nil {
+				return err
+			}
+		}
+
+		// Only the first cycle prints the previews, so a long-running watch logs a line
+		// per cycle.
+		opts.Quiet = true
+
+		next := start.Add(interval)
+		if err := log.Info("Next watch cycle at %s", next.Local().Format(time.DateTime)); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
+
+// fingerprint summarises the fetched years, so cycles can tell whether anything changed.
+func fingerprint(years [][][]types.ContributionDay) [sha256.Size]byte {
+	// Contribution days are plain values, which always encode.
+	data, _ := json.Marshal(years)
+	return sha256.Sum256(data)
+}
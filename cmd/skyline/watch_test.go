@@ -0,0 +1,69 @@
+package skyline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWatchSkyline(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser"}), nil
+	}
+
+	// The mock returns the same contributions every cycle, so only the first writes.
+	ctx, cancel := context.WithCancel(context.Background())
+	observer := &cancellingObserver{cancel: cancel, after: 3}
+	output := filepath.Join(t.TempDir(), "watch.stl")
+	opts := Options{StartYear: 2024, EndYear: 2024, Output: output, Observer: observer}
+	if err := WatchSkyline(ctx, opts, 10*time.Millisecond); err != nil {
+		t.Fatalf("WatchSkyline() error = %v", err)
+	}
+	if writes := strings.Count(strings.Join(observer.Events, "\n"), "write "+output); writes != 1 {
+		t.Errorf("watch wrote the model %d times, want once for unchanged contributions", writes)
+	}
+
+	opts.Offline = true
+	if err := WatchSkyline(context.Background(), opts, time.Hour); errors.ExitCode(err) != errors.ExitValidation {
+		t.Errorf("WatchSkyline() offline error = %v, want a validation error", err)
+	}
+}
+
+// cancellingObserver records events and cancels the watch once it has started a number
+// of fetches.
+type cancellingObserver struct {
+	mocks.MockObserver
+	cancel  context.CancelFunc
+	after   int
+	fetches int
+}
+
+func (o *cancellingObserver) OnFetchStart(user string, startYear, endYear int) {
+	o.MockObserver.OnFetchStart(user, startYear, endYear)
+	if o.fetches++; o.fetches == o.after {
+		o.cancel()
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	years := [][][]types.ContributionDay{{{{Date: "2024-03-04", ContributionCount: 2}}}}
+	same := [][][]types.ContributionDay{{{{Date: "2024-03-04", ContributionCount: 2}}}}
+	changed := [][][]types.ContributionDay{{{{Date: "2024-03-04", ContributionCount: 3}}}}
+	if fingerprint(years) != fingerprint(same) {
+		t.Error("fingerprint() differs for equal contributions")
+	}
+	if fingerprint(years) == fingerprint(changed) {
+		t.Error("fingerprint() matches for changed contributions")
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the stars command.
+var (
+	starsYearRange string
+	starsOutput    string
+	starsOutputDir string
+	starsNameTmpl  string
+	starsArtOnly   bool
+)
+
+// starsCmd renders a repository's stargazers over time as a skyline.
+var starsCmd = &cobra.Command{
+	Use:   "stars <owner/repo>",
+	Short: "Generate a 3D model of a repository's stars over time",
+	Long: `Stars buckets a repository's stargazers into weekly counts and renders the growth
+as a skyline: each week is a tower as tall as the number of stars it received.
+
+By default the model spans every year from the first star to the current year.
+Large repositories take one API request per 100 stargazers.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runStars(args[0])
+	},
+}
+
+func init() {
+	flags := starsCmd.Flags()
+	flags.StringVarP(&starsYearRange, "year", "y", "", "Year or year range (optional, defaults to the first star through the current year)")
+	flags.StringVarP(&starsOutput, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&starsOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&starsNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	flags.BoolVarP(&starsArtOnly, "art-only", "a", false, "Generate only ASCII preview")
+	rootCmd.AddCommand(starsCmd)
+}
+
+// runStars validates the stars command's arguments and generates the model.
+func runStars(repository string) error {
+	owner, repo, err := parseRepository(repository)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid repository", err)
+	}
+
+	opts := skyline.StarsOptions{
+		Owner:        owner,
+		Repo:         repo,
+		Output:       starsOutput,
+		OutputDir:    starsOutputDir,
+		NameTemplate: starsNameTmpl,
+		ArtOnly:      starsArtOnly,
+	}
+	if starsYearRange != "" {
+		if opts.StartYear, opts.EndYear, err = utils.ParseYearRange(starsYearRange); err != nil {
+			return errors.New(errors.ValidationError, "invalid year range", err)
+		}
+	}
+	if err := utils.ValidateNameTemplate(starsNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	return skyline.GenerateStars(opts)
+}
+
+// parseRepository splits an "owner/repo" argument.
+func parseRepository(repository string) (owner, repo string, err error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("expected OWNER/REPO, got %q", repository)
+	}
+	return owner, repo, nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import "testing"
+
+func TestStarsCmd(t *testing.T) {
+	if starsCmd.Use != "stars <owner/repo>" {
+		t.Errorf("expected command use to be 'stars <owner/repo>', got %s", starsCmd.Use)
+	}
+	for _, flag := range []string{"year", "output", "output-dir", "name-template", "art-only"} {
+		if starsCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{"octocat/Hello-World", "octocat", "Hello-World", false},
+		{"octocat", "", "", true},
+		{"/Hello-World", "", "", true},
+		{"octocat/", "", "", true},
+		{"octocat/Hello-World/extra", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			owner, repo, err := parseRepository(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if owner != tt.wantOwner || repo != tt.wantRepo {
+				t.Errorf("parseRepository() = %s, %s, want %s, %s", owner, repo, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the template command.
+var (
+	templateYearRange string
+	templateUser      string
+	templateOutput    string
+	templateOutputDir string
+	templateNameTmpl  string
+	templateArtOnly   bool
+)
+
+// templateCmd renders a blank skyline for a year still to come.
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Generate a blank 3D model of an upcoming year",
+	Long: `Template generates a skyline without contributions: the base with its labels and a
+low dot marking each day of the year, to print as a blank canvas for the year ahead.
+
+By default the model is of next year. Any year from 2008 to next year can be given.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runTemplate()
+	},
+}
+
+func init() {
+	flags := templateCmd.Flags()
+	flags.StringVarP(&templateYearRange, "year", "y", "", "Year or year range (optional, defaults to next year)")
+	flags.StringVarP(&templateUser, "user", "u", "", "GitHub username for the label (optional, defaults to authenticated user)")
+	flags.StringVarP(&templateOutput, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&templateOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&templateNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	flags.BoolVarP(&templateArtOnly, "art-only", "a", false, "Generate only ASCII preview")
+	rootCmd.AddCommand(templateCmd)
+}
+
+// runTemplate validates the template command's flags and generates the model.
+func runTemplate() error {
+	yearRange := templateYearRange
+	if yearRange == "" {
+		yearRange = strconv.Itoa(time.Now().Year() + 1)
+	}
+	startYear, endYear, err := utils.ParseTemplateYearRange(yearRange)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid year range", err)
+	}
+	if err := utils.ValidateNameTemplate(templateNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	return skyline.GenerateTemplate(skyline.TemplateOptions{
+		User:         templateUser,
+		StartYear:    startYear,
+		EndYear:      endYear,
+		Output:       templateOutput,
+		OutputDir:    templateOutputDir,
+		NameTemplate: templateNameTmpl,
+		ArtOnly:      templateArtOnly,
+	})
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestTemplateCmd(t *testing.T) {
+	if templateCmd.Use != "template" {
+		t.Errorf("expected command use to be 'template', got %s", templateCmd.Use)
+	}
+	for _, flag := range []string{"year", "user", "output", "output-dir", "name-template", "art-only"} {
+		if templateCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunTemplateValidation(t *testing.T) {
+	defer func() { templateYearRange, templateNameTmpl = "", "" }()
+	next := time.Now().Year() + 1
+	tests := map[string]struct {
+		years, nameTemplate string
+	}{
+		"after next year": {strconv.Itoa(next + 1), ""},
+		"before GitHub":   {"2001", ""},
+		"not a year":      {"soon", ""},
+		"name template":   {strconv.Itoa(next), "{nope}"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			templateYearRange, templateNameTmpl = tt.years, tt.nameTemplate
+			if err := runTemplate(); errors.ExitCode(err) != errors.ExitValidation {
+				t.Errorf("runTemplate() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags of the update command.
+var (
+	updateUser      string
+	updateOutput    string
+	updateOutputDir string
+	updateNameTmpl  string
+	updateForce     bool
+)
+
+// updateCmd refreshes the current year's skyline when its contributions change.
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Regenerate the current year's 3D model when its contributions change",
+	Long: `Update fetches only the current year, compares it with the snapshot cached by the
+previous run and prints the days whose contributions changed. The model is regenerated
+only when something changed or the model is missing, so it suits scheduled runs, such
+as a weekly cron job.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runUpdate()
+	},
+}
+
+func init() {
+	flags := updateCmd.Flags()
+	flags.StringVarP(&updateUser, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
+	flags.StringVarP(&updateOutput, "output", "o", "", "Output file path (optional)")
+	flags.StringVar(&updateOutputDir, "output-dir", "", "Directory for generated files; created if missing (optional)")
+	flags.StringVar(&updateNameTmpl, "name-template", "", "Filename template using {user}, {range}, {start}, {end}, {date} and {format} (optional)")
+	flags.BoolVar(&updateForce, "force", false, "Regenerate the model even when no contributions changed")
+	rootCmd.AddCommand(updateCmd)
+}
+
+// runUpdate validates the update command's flags and refreshes the model.
+func runUpdate() error {
+	if err := utils.ValidateNameTemplate(updateNameTmpl); err != nil {
+		return errors.New(errors.ValidationError, "invalid --name-template", err)
+	}
+
+	return skyline.GenerateUpdate(skyline.UpdateOptions{
+		User:         updateUser,
+		Output:       updateOutput,
+		OutputDir:    updateOutputDir,
+		NameTemplate: updateNameTmpl,
+		Force:        updateForce,
+	})
+}
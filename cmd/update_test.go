@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestUpdateCmd(t *testing.T) {
+	if updateCmd.Use != "update" {
+		t.Errorf("expected command use to be 'update', got %s", updateCmd.Use)
+	}
+	for _, flag := range []string{"user", "output", "output-dir", "name-template", "force"} {
+		if updateCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag %s to be initialized", flag)
+		}
+	}
+}
+
+func TestRunUpdateValidation(t *testing.T) {
+	defer func() { updateNameTmpl = "" }()
+	updateNameTmpl = "{user}-{nope}"
+	if err := runUpdate(); err == nil {
+		t.Error("runUpdate() expected error for an unknown --name-template placeholder")
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd checks an STL file for problems that would trouble a slicer.
+var validateCmd = &cobra.Command{
+	Use:   "validate <model.stl>",
+	Short: "Check an STL file for mesh problems",
+	Long: `Validate loads a binary or ASCII STL file, whether generated by gh skyline or
+another tool, and reports whether its triangle count matches the file size, whether any
+triangles are degenerate or have flipped normals, and whether the mesh is watertight.
+
+It exits with an error if any check fails. Edges shared by more than two triangles, where
+separate closed shells touch, are reported but do not fail validation.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runValidate(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// runValidate checks the STL file at path and reports each check to out.
+func runValidate(out io.Writer, path string) error {
+	f, err := stl.ReadSTL(path)
+	if err != nil {
+		return err
+	}
+	report := stl.ValidateModel(f.Model())
+
+	format := "binary"
+	if f.ASCII {
+		format = "ASCII"
+	}
+	lines := []string{fmt.Sprintf("%s: %s STL, %d triangles", path, format, len(f.Triangles))}
+	if f.Header != "" {
+		lines = append(lines, fmt.Sprintf("  header: %s", f.Header))
+	}
+	check := func(ok bool, pass, fail string) {
+		if ok {
+			lines = append(lines, "✓ "+pass)
+		} else {
+			lines = append(lines, "✗ "+fail)
+		}
+	}
+	if !f.ASCII {
+		check(!f.Truncated, "Triangle count matches the file size",
+			fmt.Sprintf("Header declares %d triangles, but the file holds %d", f.Declared, len(f.Triangles)))
+	}
+	check(report.Degenerate == 0, "No degenerate triangles",
+		fmt.Sprintf("%d degenerate triangles", report.Degenerate))
+	check(report.FlippedNormals == 0, "Normals agree with triangle winding",
+		fmt.Sprintf("%d triangles have normals pointing against their winding", report.FlippedNormals))
+	check(report.Watertight(), "Watertight",
+		fmt.Sprintf("Not watertight: %d open edges", report.OpenEdges))
+	if report.NonManifoldEdges > 0 {
+		lines = append(lines, fmt.Sprintf("! %d edges are shared by more than two triangles, where closed shells touch", report.NonManifoldEdges))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	if f.Truncated || !report.OK() {
+		return errors.New(errors.ValidationError, "STL file failed validation", nil)
+	}
+	return nil
+}
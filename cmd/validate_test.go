@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestValidateCmd(t *testing.T) {
+	if validateCmd.Use != "validate <model.stl>" {
+		t.Errorf("expected command use to be 'validate <model.stl>', got %s", validateCmd.Use)
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	cube, err := geometry.CreateCube(0, 0, 0, 10, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := filepath.Join(dir, "closed.stl")
+	if err := stl.WriteSTLBinary(closed, cube); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runValidate(&out, closed); err != nil {
+		t.Fatalf("runValidate() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "12 triangles") || !strings.Contains(out.String(), "✓ Watertight") || strings.Contains(out.String(), "✗") {
+		t.Errorf("runValidate() output = %q", out.String())
+	}
+
+	open := filepath.Join(dir, "open.stl")
+	if err := stl.WriteSTLBinary(open, cube[2:]); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runValidate(&out, open); err == nil {
+		t.Error("runValidate() expected error for an open mesh")
+	}
+	if !strings.Contains(out.String(), "✗ Not watertight: 4 open edges") {
+		t.Errorf("runValidate() output = %q", out.String())
+	}
+
+	// A truncated file still gets a report on the triangles it holds.
+	data, err := os.ReadFile(closed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dir, "truncated.stl")
+	if err := os.WriteFile(truncated, data[:len(data)-20], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runValidate(&out, truncated); err == nil {
+		t.Error("runValidate() expected error for a truncated file")
+	}
+	if !strings.Contains(out.String(), "declares 12 triangles, but the file holds 11") {
+		t.Errorf("runValidate() output = %q", out.String())
+	}
+
+	if err := runValidate(&out, filepath.Join(dir, "missing.stl")); err == nil {
+		t.Error("runValidate() expected error for a missing file")
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// verifyKeyPath is the trusted public key used by the verify command.
+var verifyKeyPath string
+
+// verifyCmd checks the integrity and signature of an archive created with --archive.
+var verifyCmd = &cobra.Command{
+	Use:   "verify <bundle.zip>",
+	Short: "Verify the manifest and signature of a skyline archive",
+	Long: `Verify checks that every file in an archive created with --archive matches its
+manifest, and that the manifest signature (if any) is valid.
+
+Pass --key with the signer's public key (an OpenSSH .pub line or PEM) to confirm who
+signed the archive. A signed archive checked without --key fails as having an
+unverified signer, since anyone who edits an archive can sign it again with their own key.
+
+Signatures use the SSHSIG format, so a manifest can also be checked with
+ssh-keygen -Y verify -n gh-skyline.`,
+	Args: validateArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerify(cmd.OutOrStdout(), args[0], verifyKeyPath)
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&verifyKeyPath, "key", "k", "", "Trusted SSH public key of the signer (optional)")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// runVerify verifies the archive at path and reports the outcome to out.
+func runVerify(out io.Writer, path, keyPath string) error {
+	var trusted *bundle.PublicKey
+	if keyPath != "" {
+		var err error
+		if trusted, err = bundle.LoadPublicKey(keyPath); err != nil {
+			return err
+		}
+	}
+
+	result, err := bundle.Verify(path, trusted)
+	if err != nil {
+		return err
+	}
+
+	m := result.Manifest
+	if _, err := fmt.Fprintf(out, "✓ %d files match the manifest (%s, %d-%d)\n", len(m.Files), m.User, m.StartYear, m.EndYear); err != nil {
+		return err
+	}
+
+	switch {
+	case !result.Signed:
+		_, err = fmt.Fprintln(out, "! Archive is not signed; only file integrity was checked")
+	case result.Trusted:
+		_, err = fmt.Fprintf(out, "✓ Signed by trusted key %s\n", result.KeyID)
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unverified signer %s: pass --key with the signer's public key to confirm who signed the archive", result.KeyID), nil)
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestVerifyCmd(t *testing.T) {
+	if verifyCmd.Use != "verify <bundle.zip>" {
+		t.Errorf("expected command use to be 'verify <bundle.zip>', got %s", verifyCmd.Use)
+	}
+	if verifyCmd.Flags().Lookup("key") == nil {
+		t.Error("expected flag key to be initialized")
+	}
+}
+
+func TestRunVerify(t *testing.T) {
+	dir := t.TempDir()
+	stlPath := filepath.Join(dir, "model.stl")
+	if err := os.WriteFile(stlPath, []byte("solid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "bundle.zip")
+	if err := bundle.Write(archive, bundle.Options{
+		Files:         []string{stlPath},
+		Contributions: bundle.Contributions{User: "mona"},
+		StartYear:     2024,
+		EndYear:       2024,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runVerify(&out, archive, ""); err != nil {
+		t.Fatalf("runVerify() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "2 files match") || !strings.Contains(out.String(), "not signed") {
+		t.Errorf("runVerify() output = %q", out.String())
+	}
+
+	if err := runVerify(&out, filepath.Join(dir, "missing.zip"), ""); err == nil {
+		t.Error("runVerify() expected error for missing archive")
+	}
+	if err := runVerify(&out, archive, filepath.Join(dir, "missing.pub")); err == nil {
+		t.Error("runVerify() expected error for missing key")
+	}
+}
+
+func TestRunVerifySigned(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privPath := filepath.Join(dir, "id_ed25519")
+	pubPath := filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, ssh.MarshalAuthorizedKey(sshPub), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := bundle.LoadSigner(privPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stlPath := filepath.Join(dir, "model.stl")
+	if err := os.WriteFile(stlPath, []byte("solid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "bundle.zip")
+	if err := bundle.Write(archive, bundle.Options{
+		Files:         []string{stlPath},
+		Contributions: bundle.Contributions{User: "mona"},
+		StartYear:     2024,
+		EndYear:       2024,
+		Signer:        signer,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runVerify(&out, archive, pubPath); err != nil {
+		t.Fatalf("runVerify() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Signed by trusted key") {
+		t.Errorf("runVerify() output = %q", out.String())
+	}
+
+	// Anyone can re-sign an edited archive, so a signature checked without --key fails.
+	err = runVerify(&out, archive, "")
+	if errors.ExitCode(err) != errors.ExitValidation || !strings.Contains(err.Error(), "unverified signer") {
+		t.Errorf("runVerify() without --key error = %v, want an unverified signer", err)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Repository the extension is released from.
+const (
+	releaseOwner = "github"
+	releaseRepo  = "gh-skyline"
+)
+
+// versionCheck looks up the latest release after printing the version.
+var versionCheck bool
+
+// versionCmd prints the build version and, on request, whether an upgrade is available.
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the gh-skyline version",
+	Long: `Version prints the version and commit gh-skyline was built from.
+
+Pass --check to look up the latest release on GitHub and see whether
+'gh extension upgrade skyline' would install a newer one.`,
+	Args: validateArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return runVersion(cmd.OutOrStdout(), utils.ToolVersion(), versionCheck)
+	},
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check GitHub for a newer release")
+	rootCmd.AddCommand(versionCmd)
+}
+
+// runVersion reports the running version to out and, if check is set, how it compares
+// with the latest release.
+func runVersion(out io.Writer, version string, check bool) error {
+	if _, err := fmt.Fprintf(out, "gh-skyline %s (commit %s, %s)\n", version, utils.ToolCommit(), runtime.Version()); err != nil {
+		return err
+	}
+	if !check {
+		return nil
+	}
+
+	client, err := github.InitializeGitHubClient()
+	if err != nil {
+		return err
+	}
+	latest, url, err := client.FetchLatestRelease(releaseOwner, releaseRepo)
+	if err != nil {
+		return err
+	}
+
+	switch order, ok := utils.CompareVersions(version, latest); {
+	case !ok:
+		_, err = fmt.Fprintf(out, "! Latest release is %s; this build's version cannot be compared with it\n", latest)
+	case order < 0:
+		_, err = fmt.Fprintf(out, "! A new release is available: %s → %s\n  %s\n  Run 'gh extension upgrade skyline' to upgrade\n", version, latest, url)
+	default:
+		_, err = fmt.Fprintf(out, "✓ Up to date with the latest release, %s\n", latest)
+	}
+	return err
+}
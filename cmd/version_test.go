@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestVersionCmd(t *testing.T) {
+	if versionCmd.Use != "version" {
+		t.Errorf("expected command use to be 'version', got %s", versionCmd.Use)
+	}
+	if versionCmd.Flags().Lookup("check") == nil {
+		t.Error("expected flag check to be initialized")
+	}
+}
+
+func TestRunVersion(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Release: "v1.5.0"}), nil
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		check   bool
+		want    string
+	}{
+		{"no check", "v1.4.0", false, "gh-skyline v1.4.0 (commit "},
+		{"outdated", "v1.4.0", true, "A new release is available: v1.4.0 → v1.5.0"},
+		{"pre-release", "v1.5.0-rc.1", true, "gh extension upgrade skyline"},
+		{"current", "v1.5.0", true, "✓ Up to date with the latest release, v1.5.0"},
+		{"development build", "dev", true, "cannot be compared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runVersion(&out, tt.version, tt.check); err != nil {
+				t.Fatalf("runVersion() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("runVersion() output = %q, want it to contain %q", out.String(), tt.want)
+			}
+			if !tt.check && strings.Count(out.String(), "\n") != 1 {
+				t.Errorf("runVersion() without --check printed %q", out.String())
+			}
+		})
+	}
+
+	github.InitializeGitHubClient = func() (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{}), nil
+	}
+	if err := runVersion(&bytes.Buffer{}, "v1.4.0", true); err == nil {
+		t.Error("runVersion() expected error when there are no releases")
+	}
+}
@@ -8,6 +8,10 @@ const (
 	EmptyBlock  = ' ' // Represents days with no contributions
 	FutureBlock = '.' // Represents future dates
 
+	// HighlightBlock marks highlighted days, such as personal records, wherever they sit
+	// in their column
+	HighlightBlock = '█'
+
 	// Foundation blocks (bottom layer)
 	FoundationLow  = '░' // 1-33% intensity
 	FoundationMed  = '▒' // 34-66% intensity
@@ -22,8 +26,21 @@ const (
 	TopLow  = '╻' // Lower intensity peak
 	TopMed  = '┃' // Medium intensity peak
 	TopHigh = '╽' // High intensity peak
+
+	// Sideways top blocks, used by the vertical orientation where columns grow to the right
+	SideTopLow  = '╸' // Lower intensity peak
+	SideTopMed  = '━' // Medium intensity peak
+	SideTopHigh = '╾' // High intensity peak
 )
 
+// sidewaysBlocks maps top blocks to their rotated equivalents for the vertical orientation.
+// Shading blocks are symmetric and need no rotation.
+var sidewaysBlocks = map[rune]rune{
+	TopLow:  SideTopLow,
+	TopMed:  SideTopMed,
+	TopHigh: SideTopHigh,
+}
+
 // Contribution level thresholds as percentages of the maximum contribution count
 const (
 	LowThreshold    = 0.33 // 33% of max contributions
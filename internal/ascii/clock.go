@@ -0,0 +1,64 @@
+package ascii
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// clockHourWidth is the number of characters each hour's bar is drawn with, so the day
+// spans about as much of the terminal as a year of weeks.
+const clockHourWidth = 2
+
+// clockRows is the height of the tallest bar, as tall as a week column of the calendar.
+const clockRows = 7
+
+// GenerateClock draws commit counts per hour of the day as 24 bars, the busiest hour
+// full height, above an axis marking every sixth hour. Bars are shaded like the
+// calendar's columns, by their share of the busiest hour.
+func GenerateClock(hours [24]int, username, label string, includeHeader bool) (string, error) {
+	busiest := 0
+	for _, count := range hours {
+		busiest = max(busiest, count)
+	}
+	if busiest == 0 {
+		return "", fmt.Errorf("%w: no commits to draw", ErrInvalidGrid)
+	}
+
+	var buffer bytes.Buffer
+	if includeHeader {
+		for _, line := range strings.Split(HeaderTemplate, "\n") {
+			buffer.WriteString(line + "\n")
+		}
+		buffer.WriteString("\n")
+	}
+
+	margin := strings.Repeat(" ", (GridWidth-len(hours)*clockHourWidth)/2)
+	for row := clockRows - 1; row >= 0; row-- {
+		buffer.WriteString(margin)
+		for _, count := range hours {
+			normalized := float64(count) / float64(busiest)
+			height := 0
+			if count > 0 {
+				height = max(1, int(normalized*clockRows+0.5))
+			}
+			block := EmptyBlock
+			if row < height {
+				block = getBlock(normalized, row, height)
+			}
+			buffer.WriteString(strings.Repeat(string(block), clockHourWidth))
+		}
+		buffer.WriteString("\n")
+	}
+
+	axis := []byte(strings.Repeat(" ", len(hours)*clockHourWidth))
+	for hour := 0; hour < len(hours); hour += 6 {
+		copy(axis[hour*clockHourWidth:], fmt.Sprintf("%dh", hour))
+	}
+	buffer.WriteString(margin + strings.TrimRight(string(axis), " ") + "\n")
+
+	buffer.WriteString("\n")
+	buffer.WriteString(centerText(username))
+	buffer.WriteString(centerText(label))
+	return buffer.String(), nil
+}
@@ -0,0 +1,41 @@
+package ascii
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateClock(t *testing.T) {
+	if _, err := GenerateClock([24]int{}, "testuser", "2024 by hour", false); !errors.Is(err, ErrInvalidGrid) {
+		t.Errorf("GenerateClock() without commits error = %v, want ErrInvalidGrid", err)
+	}
+
+	art, err := GenerateClock([24]int{9: 8, 23: 1}, "testuser", "2024 by hour", false)
+	if err != nil {
+		t.Fatalf("GenerateClock() error = %v", err)
+	}
+	lines := strings.Split(art, "\n")
+	// Seven rows of bars, the hour axis, a blank line and the two centred labels.
+	if len(lines) < clockRows+4 || !strings.Contains(art, "testuser") || !strings.Contains(art, "2024 by hour") {
+		t.Fatalf("GenerateClock() = %q, want bars, an axis and both labels", art)
+	}
+	margin := (GridWidth - 24*clockHourWidth) / 2
+	column := func(line string, hour int) string {
+		runes := []rune(line)
+		return string(runes[margin+hour*clockHourWidth : margin+(hour+1)*clockHourWidth])
+	}
+	// The busiest hour reaches the top row; the quiet one only the bottom row.
+	if top := column(lines[0], 9); top == strings.Repeat(string(EmptyBlock), clockHourWidth) {
+		t.Errorf("top row at 9h = %q, want the busiest hour's bar", top)
+	}
+	if top := column(lines[0], 23); top != strings.Repeat(string(EmptyBlock), clockHourWidth) {
+		t.Errorf("top row at 23h = %q, want it empty", top)
+	}
+	if bottom := column(lines[clockRows-1], 23); bottom == strings.Repeat(string(EmptyBlock), clockHourWidth) {
+		t.Errorf("bottom row at 23h = %q, want a bar for a single commit", bottom)
+	}
+	if axis := lines[clockRows]; !strings.HasPrefix(strings.TrimSpace(axis), "0h") || !strings.Contains(axis, "18h") {
+		t.Errorf("axis = %q, want every sixth hour marked", axis)
+	}
+}
@@ -0,0 +1,112 @@
+package ascii
+
+import (
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/i18n"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// steadyTrend is the largest relative change between the halves of a year still
+// described as steady.
+const steadyTrend = 0.1
+
+// Describe summarizes a year of contributions in prose, for readers who cannot see the
+// block-character art: the total, how many days were active, whether activity rose or fell
+// across the year, the busiest and quietest months, and the busiest week and day. unit names
+// what is counted, such as "contributions"; days after now and days without a valid date
+// are left out. Sentences are translated and numbers grouped for the active language.
+func Describe(contributionGrid [][]types.ContributionDay, username string, year int, unit string, now time.Time) (string, error) {
+	if len(contributionGrid) == 0 {
+		return "", ErrInvalidGrid
+	}
+
+	type weekTotal struct {
+		start time.Time
+		count int
+	}
+	var (
+		total, days, active int
+		months              [12]int
+		halves              [2]int
+		busiestDay          types.ContributionDay
+		busiestDate         time.Time
+		busiestWeek         weekTotal
+	)
+	mid := time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+	for _, week := range contributionGrid {
+		var current weekTotal
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil || date.After(now) {
+				continue
+			}
+			if current.start.IsZero() {
+				current.start = date
+			}
+			current.count += day.ContributionCount
+			total += day.ContributionCount
+			days++
+			if day.ContributionCount > 0 {
+				active++
+			}
+			months[date.Month()-1] += day.ContributionCount
+			if date.Before(mid) {
+				halves[0] += day.ContributionCount
+			} else {
+				halves[1] += day.ContributionCount
+			}
+			if day.ContributionCount > busiestDay.ContributionCount {
+				busiestDay, busiestDate = day, date
+			}
+		}
+		if current.count > busiestWeek.count {
+			busiestWeek = current
+		}
+	}
+
+	if total == 0 {
+		return i18n.Sprintf("%s made no %s in %d.", username, i18n.T(unit), year) + "\n", nil
+	}
+	sentences := []string{
+		i18n.Sprintf("%s made %s %s in %d, active on %s of %s days.", username, i18n.FormatInt(total), i18n.T(unit), year, i18n.FormatInt(active), i18n.FormatInt(days)),
+		describeTrend(halves, unit),
+	}
+
+	busiest, quietest := 0, -1
+	for m, count := range months {
+		if count > months[busiest] {
+			busiest = m
+		}
+		if count > 0 && (quietest < 0 || count < months[quietest]) {
+			quietest = m
+		}
+	}
+	if quietest != busiest {
+		sentences = append(sentences, i18n.Sprintf("The busiest month was %s with %s; the quietest active month was %s with %s.",
+			time.Month(busiest+1), i18n.FormatInt(months[busiest]), time.Month(quietest+1), i18n.FormatInt(months[quietest])))
+	} else {
+		sentences = append(sentences, i18n.Sprintf("All %s %s came in %s.", i18n.FormatInt(total), i18n.T(unit), time.Month(busiest+1)))
+	}
+
+	sentences = append(sentences,
+		i18n.Sprintf("The busiest week began on %s with %s.", busiestWeek.start.Format("Monday 2 January"), i18n.FormatInt(busiestWeek.count)),
+		i18n.Sprintf("The busiest day was %s with %s.", busiestDate.Format("Monday 2 January"), i18n.FormatInt(busiestDay.ContributionCount)),
+	)
+	return strings.Join(sentences, " ") + "\n", nil
+}
+
+// describeTrend compares the first and second halves of a year.
+func describeTrend(halves [2]int, unit string) string {
+	first, second := halves[0], halves[1]
+	change := float64(second-first) / float64(max(first, second))
+	format := "Activity held steady, with %s %s in the first half of the year and %s in the second."
+	switch {
+	case change > steadyTrend:
+		format = "Activity rose from %s %s in the first half of the year to %s in the second."
+	case change < -steadyTrend:
+		format = "Activity fell from %s %s in the first half of the year to %s in the second."
+	}
+	return i18n.Sprintf(format, i18n.FormatInt(first), i18n.T(unit), i18n.FormatInt(second))
+}
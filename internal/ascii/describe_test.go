@@ -0,0 +1,72 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// weeksFrom lays out count days from start in weeks of seven, with counts taken from the
+// function of each day.
+func weeksFrom(start time.Time, count int, counts func(time.Time) int) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	for i := range count {
+		if i%7 == 0 {
+			weeks = append(weeks, nil)
+		}
+		d := start.AddDate(0, 0, i)
+		weeks[len(weeks)-1] = append(weeks[len(weeks)-1], types.ContributionDay{Date: d.Format("2006-01-02"), ContributionCount: counts(d)})
+	}
+	return weeks
+}
+
+func TestDescribe(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	// One contribution a day in the first half, three in the second and a spike on 4 October.
+	grid := weeksFrom(start, 365, func(d time.Time) int {
+		switch {
+		case d.Month() == time.October && d.Day() == 4:
+			return 20
+		case d.Month() >= time.July:
+			return 3
+		}
+		return 1
+	})
+	got, err := Describe(grid, "octocat", 2023, "contributions", now)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	for _, want := range []string{
+		"octocat made 750 contributions in 2023, active on 365 of 365 days.",
+		"Activity rose from 181 contributions in the first half of the year to 569 in the second.",
+		"The busiest month was October with 110; the quietest active month was February with 28.",
+		"The busiest week began on Sunday 1 October with 38.",
+		"The busiest day was Wednesday 4 October with 20.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Describe() = %q, missing %q", got, want)
+		}
+	}
+
+	// Days after now are left out.
+	got, err = Describe(grid, "octocat", 2023, "contributions", time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if !strings.Contains(got, "active on 10 of 10 days") || !strings.Contains(got, "All 10 contributions came in January.") {
+		t.Errorf("Describe() before the year ended = %q", got)
+	}
+
+	got, err = Describe(weeksFrom(start, 14, func(time.Time) int { return 0 }), "octocat", 2023, "reviews", now)
+	if err != nil || got != "octocat made no reviews in 2023.\n" {
+		t.Errorf("Describe() of an empty year = %q, %v", got, err)
+	}
+
+	if _, err := Describe(nil, "octocat", 2023, "contributions", now); err != ErrInvalidGrid {
+		t.Errorf("Describe(nil) error = %v, want ErrInvalidGrid", err)
+	}
+}
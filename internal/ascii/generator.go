@@ -13,10 +13,57 @@ import (
 // ErrInvalidGrid is returned when the contribution grid is invalid
 var ErrInvalidGrid = errors.New("invalid contribution grid")
 
+// Orientation controls how the preview grid is laid out in the terminal.
+type Orientation int
+
+const (
+	// Horizontal draws one column per week, with columns growing upwards (the default).
+	Horizontal Orientation = iota
+	// Vertical rotates the grid 90°: one row per week, flowing downwards, with columns growing to the right.
+	Vertical
+)
+
+// ParseOrientation converts a flag value ("horizontal" or "vertical") into an Orientation.
+func ParseOrientation(name string) (Orientation, error) {
+	switch strings.ToLower(name) {
+	case "", "horizontal":
+		return Horizontal, nil
+	case "vertical":
+		return Vertical, nil
+	default:
+		return Horizontal, fmt.Errorf("unknown orientation %q (expected horizontal or vertical)", name)
+	}
+}
+
+// Options configures GenerateASCIIWithOptions.
+type Options struct {
+	IncludeHeader   bool        // Print the ASCII art banner above the grid
+	IncludeUserInfo bool        // Print the username and year below the grid
+	Orientation     Orientation // Grid layout
+	Label           string      // Printed in place of the year, e.g. a date range; empty prints the year
+
+	// Thresholds, when set, grade days by their count instead of their share of the busiest
+	// day; days below the first threshold still show at the lowest level.
+	Thresholds types.Thresholds
+
+	// Highlight holds the dates of days drawn as HighlightBlock instead of their level,
+	// such as the busiest days of the range.
+	Highlight map[string]bool
+}
+
 // GenerateASCII creates a 2D ASCII art representation of the contribution data.
 // It returns the generated ASCII art as a string and an error if the operation fails.
 // When includeHeader is true, the output includes the header template.
 func GenerateASCII(contributionGrid [][]types.ContributionDay, username string, year int, includeHeader bool, includeUserInfo bool) (string, error) {
+	return GenerateASCIIWithOptions(contributionGrid, username, year, Options{
+		IncludeHeader:   includeHeader,
+		IncludeUserInfo: includeUserInfo,
+	})
+}
+
+// GenerateASCIIWithOptions is GenerateASCII with an explicit orientation.
+// The vertical orientation omits the banner, which is wider than the rotated grid.
+func GenerateASCIIWithOptions(contributionGrid [][]types.ContributionDay, username string, year int, opts Options) (string, error) {
 	if len(contributionGrid) == 0 {
 		return "", ErrInvalidGrid
 	}
@@ -24,13 +71,64 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 	var buffer bytes.Buffer
 
 	// Only include header if requested
-	if includeHeader {
+	if opts.IncludeHeader && opts.Orientation == Horizontal {
 		for _, line := range strings.Split(HeaderTemplate, "\n") {
 			buffer.WriteString(line + "\n")
 		}
 		buffer.WriteString("\n")
 	}
 
+	asciiGrid := buildGrid(contributionGrid, opts.Thresholds, opts.Highlight)
+	label := opts.Label
+	if label == "" {
+		label = fmt.Sprintf("%d", year)
+	}
+
+	if opts.Orientation == Vertical {
+		writeVertical(&buffer, asciiGrid)
+		if opts.IncludeUserInfo {
+			buffer.WriteString("\n" + username + "\n" + label + "\n")
+		}
+		return buffer.String(), nil
+	}
+
+	// Write the contribution grid
+	for i := len(asciiGrid) - 1; i >= 0; i-- {
+		for _, ch := range asciiGrid[i] {
+			buffer.WriteRune(ch)
+		}
+		buffer.WriteRune('\n')
+	}
+
+	if opts.IncludeUserInfo {
+		// Add centered user info below
+		buffer.WriteString("\n")
+		buffer.WriteString(centerText(username))
+		buffer.WriteString(centerText(label))
+	}
+
+	return buffer.String(), nil
+}
+
+// writeVertical writes the grid rotated 90° clockwise: each week becomes a row and
+// each column grows from the left edge, with top blocks swapped for sideways glyphs.
+func writeVertical(buffer *bytes.Buffer, asciiGrid [][]rune) {
+	for weekIdx := range asciiGrid[0] {
+		for dayIdx := range asciiGrid {
+			ch := asciiGrid[dayIdx][weekIdx]
+			if side, ok := sidewaysBlocks[ch]; ok {
+				ch = side
+			}
+			buffer.WriteRune(ch)
+		}
+		buffer.WriteRune('\n')
+	}
+}
+
+// buildGrid converts contribution data into a grid of block characters indexed
+// as [level][week], where level 0 is the bottom of each column. Active days whose date
+// is in highlight are drawn as HighlightBlock.
+func buildGrid(contributionGrid [][]types.ContributionDay, thresholds types.Thresholds, highlight map[string]bool) [][]rune {
 	// Find max contribution count for normalization
 	maxContributions := 0
 	for _, week := range contributionGrid {
@@ -65,9 +163,14 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 			day := sortedDays[dayIdx]
 			if day.ContributionCount == -1 {
 				asciiGrid[dayIdx][weekIdx] = FutureBlock // #nosec G602 -- bounds checked by maxDayIdx calculation above
+			} else if day.ContributionCount > 0 && highlight[day.Date] {
+				asciiGrid[dayIdx][weekIdx] = HighlightBlock // #nosec G602 -- bounds checked by maxDayIdx calculation above
 			} else {
 				normalized := 0.0
-				if maxContributions != 0 {
+				switch {
+				case len(thresholds) > 0 && day.ContributionCount > 0:
+					normalized = thresholdFraction(thresholds, day.ContributionCount)
+				case maxContributions != 0:
 					normalized = float64(day.ContributionCount) / float64(maxContributions)
 				}
 				asciiGrid[dayIdx][weekIdx] = getBlock(normalized, dayIdx, nonZeroCount) // #nosec G602 -- bounds checked by maxDayIdx calculation above
@@ -75,22 +178,14 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 		}
 	}
 
-	// Write the contribution grid
-	for i := len(asciiGrid) - 1; i >= 0; i-- {
-		for _, ch := range asciiGrid[i] {
-			buffer.WriteRune(ch)
-		}
-		buffer.WriteRune('\n')
-	}
-
-	if includeUserInfo {
-		// Add centered user info below
-		buffer.WriteString("\n")
-		buffer.WriteString(centerText(username))
-		buffer.WriteString(centerText(fmt.Sprintf("%d", year)))
-	}
+	return asciiGrid
+}
 
-	return buffer.String(), nil
+// thresholdFraction places an active day's threshold level in the middle of its share of
+// the levels, so any number of levels spreads evenly over the low, medium and high blocks.
+func thresholdFraction(thresholds types.Thresholds, count int) float64 {
+	level := max(thresholds.Level(count), 1)
+	return (float64(level) - 0.5) / float64(len(thresholds))
 }
 
 // sortContributionDays sorts the contribution days within a week.
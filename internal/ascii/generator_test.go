@@ -179,3 +179,137 @@ func TestGenerateASCIIZeroContributions(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateASCIIVertical(t *testing.T) {
+	grid := makeTestGrid(3, 7)
+	result, err := GenerateASCIIWithOptions(grid, "testuser", 2023, Options{
+		IncludeHeader:   true,
+		IncludeUserInfo: true,
+		Orientation:     Vertical,
+	})
+	if err != nil {
+		t.Fatalf("GenerateASCIIWithOptions() error = %v", err)
+	}
+
+	if strings.Contains(result, "____") {
+		t.Error("vertical output should omit the banner")
+	}
+
+	lines := strings.Split(result, "\n")
+	// One row per week, each as wide as a week has days.
+	for i := 0; i < len(grid); i++ {
+		if got := len([]rune(lines[i])); got != 7 {
+			t.Errorf("row %d width = %d, want 7", i, got)
+		}
+	}
+	if strings.TrimSpace(lines[0]) != "" {
+		t.Errorf("week without contributions should be empty, got %q", lines[0])
+	}
+	if want := "░▒▒▓▓╾ "; lines[2] != want {
+		t.Errorf("busiest week = %q, want %q", lines[2], want)
+	}
+	if strings.ContainsAny(result, string([]rune{TopLow, TopMed, TopHigh})) {
+		t.Error("vertical output should use sideways top blocks")
+	}
+	if !strings.Contains(result, "\ntestuser\n2023\n") {
+		t.Errorf("vertical output should end with user info, got %q", result)
+	}
+}
+
+func TestGenerateASCIILabel(t *testing.T) {
+	for _, orientation := range []Orientation{Horizontal, Vertical} {
+		result, err := GenerateASCIIWithOptions(makeTestGrid(3, 7), "testuser", 2023, Options{
+			IncludeUserInfo: true,
+			Orientation:     orientation,
+			Label:           "2023-11-01/2024-01-15",
+		})
+		if err != nil {
+			t.Fatalf("GenerateASCIIWithOptions() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+		if got := strings.TrimSpace(lines[len(lines)-1]); got != "2023-11-01/2024-01-15" {
+			t.Errorf("orientation %d ends with %q, want the label in place of the year", orientation, got)
+		}
+	}
+}
+
+func TestGenerateASCIIThresholds(t *testing.T) {
+	// The busiest week has 2, 4, ... 12 contributions; with these thresholds none reaches
+	// the fourth level, so nothing is drawn at high intensity.
+	result, err := GenerateASCIIWithOptions(makeTestGrid(3, 7), "testuser", 2023, Options{
+		Orientation: Vertical,
+		Thresholds:  types.Thresholds{1, 5, 10, 20},
+	})
+	if err != nil {
+		t.Fatalf("GenerateASCIIWithOptions() error = %v", err)
+	}
+	if want := "░░▒▒▒━ "; strings.Split(result, "\n")[2] != want {
+		t.Errorf("busiest week = %q, want %q", strings.Split(result, "\n")[2], want)
+	}
+}
+
+func TestGenerateASCIIHighlight(t *testing.T) {
+	grid := makeTestGrid(3, 7)
+	grid[2][6].Date = "2023-01-21"
+	grid[1][0].Date = "2023-01-08"
+	result, err := GenerateASCIIWithOptions(grid, "testuser", 2023, Options{
+		Orientation: Vertical,
+		Highlight:   map[string]bool{"2023-01-21": true, "2023-01-08": true},
+	})
+	if err != nil {
+		t.Fatalf("GenerateASCIIWithOptions() error = %v", err)
+	}
+	// The busiest day is marked where it sits in its column; the quiet highlighted day
+	// has nothing to mark.
+	lines := strings.Split(result, "\n")
+	if strings.Count(result, string(HighlightBlock)) != 1 || !strings.ContainsRune(lines[2], HighlightBlock) {
+		t.Errorf("want a single highlight in the busiest week, got:\n%s", result)
+	}
+}
+
+func TestThresholdFraction(t *testing.T) {
+	quartiles := types.Thresholds{1, 5, 10, 20}
+	tests := []struct {
+		thresholds types.Thresholds
+		count      int
+		want       int
+	}{
+		{quartiles, 1, 0},
+		{quartiles, 7, 1},
+		{quartiles, 12, 1},
+		{quartiles, 25, 2},
+		{types.Thresholds{3, 6, 9}, 1, 0}, // Below the first threshold
+		{types.Thresholds{3, 6, 9}, 6, 1},
+		{types.Thresholds{3, 6, 9}, 9, 2},
+	}
+	for _, tt := range tests {
+		if got := getBlockType(thresholdFraction(tt.thresholds, tt.count)); got != tt.want {
+			t.Errorf("block type for %d with %v = %d, want %d", tt.count, tt.thresholds, got, tt.want)
+		}
+	}
+}
+
+func TestParseOrientation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Orientation
+		wantErr bool
+	}{
+		{"", Horizontal, false},
+		{"horizontal", Horizontal, false},
+		{"Vertical", Vertical, false},
+		{"diagonal", Horizontal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOrientation(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOrientation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOrientation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package badges computes contribution achievements and provides the icons that can be
+// embossed on a skyline for each earned badge.
+package badges
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"image"
+	"image/png"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+//go:embed assets/*.png
+var assets embed.FS
+
+// dateLayout is the format of ContributionDay dates.
+const dateLayout = "2006-01-02"
+
+// Badge is an achievement that can be earned from a contribution history.
+type Badge struct {
+	ID     string                // Stable identifier, e.g. "streak-365"
+	Name   string                // Human-readable name
+	Icon   string                // File name of the embedded icon in assets/
+	Earned func(days []Day) bool // Reports whether the history earns the badge
+}
+
+// Day is a single contribution day with its parsed date.
+// Days passed to Badge.Earned are sorted chronologically and never have a zero Date.
+type Day struct {
+	Date  time.Time
+	Count int
+}
+
+var (
+	mu       sync.RWMutex
+	registry []Badge
+)
+
+// Register adds a badge to the registry. Badges are evaluated and embossed in
+// registration order. Registering an ID twice panics, as it is a programming error.
+func Register(b Badge) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, existing := range registry {
+		if existing.ID == b.ID {
+			panic(fmt.Sprintf("badges: duplicate badge %q", b.ID))
+		}
+	}
+	registry = append(registry, b)
+}
+
+// All returns every registered badge.
+func All() []Badge {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Badge(nil), registry...)
+}
+
+// Evaluate returns the badges earned by the given contributions ([year][week][day]),
+// in registration order. Days without a valid date are ignored.
+func Evaluate(contributions [][][]types.ContributionDay) []Badge {
+	days := flatten(contributions)
+
+	var earned []Badge
+	for _, b := range All() {
+		if b.Earned(days) {
+			earned = append(earned, b)
+		}
+	}
+	return earned
+}
+
+// LoadIcon decodes the badge's embedded icon. White, opaque pixels are embossed.
+func (b Badge) LoadIcon() (image.Image, error) {
+	data, err := assets.ReadFile("assets/" + b.Icon)
+	if err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to read icon for badge %s", b.ID), err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to decode icon for badge %s", b.ID), err)
+	}
+	return img, nil
+}
+
+// flatten converts the contribution grid into a chronologically sorted list of dated days.
+func flatten(contributions [][][]types.ContributionDay) []Day {
+	var days []Day
+	for _, year := range contributions {
+		for _, week := range year {
+			for _, day := range week {
+				date, err := time.Parse(dateLayout, day.Date)
+				if err != nil {
+					continue
+				}
+				days = append(days, Day{Date: date, Count: day.ContributionCount})
+			}
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days
+}
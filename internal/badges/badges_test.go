@@ -0,0 +1,139 @@
+package badges
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// makeYears builds a contribution grid from start for n days, using count for each day.
+func makeYears(start time.Time, n int, count func(i int) int) [][][]types.ContributionDay {
+	var years [][][]types.ContributionDay
+	var week []types.ContributionDay
+	var weeks [][]types.ContributionDay
+	year := start.Year()
+	for i := 0; i < n; i++ {
+		date := start.AddDate(0, 0, i)
+		if date.Year() != year {
+			if len(week) > 0 {
+				weeks = append(weeks, week)
+			}
+			years = append(years, weeks)
+			weeks, week, year = nil, nil, date.Year()
+		}
+		week = append(week, types.ContributionDay{Date: date.Format(dateLayout), ContributionCount: count(i)})
+		if date.Weekday() == time.Saturday {
+			weeks = append(weeks, week)
+			week = nil
+		}
+	}
+	if len(week) > 0 {
+		weeks = append(weeks, week)
+	}
+	return append(years, weeks)
+}
+
+// skipDay returns a count function with one contribution per day except on day n.
+func skipDay(n int) func(int) int {
+	return func(i int) int {
+		if i == n {
+			return 0
+		}
+		return 1
+	}
+}
+
+func earnedIDs(contributions [][][]types.ContributionDay) map[string]bool {
+	ids := map[string]bool{}
+	for _, b := range Evaluate(contributions) {
+		ids[b.ID] = true
+	}
+	return ids
+}
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		data  [][][]types.ContributionDay
+		want  []string
+		avoid []string
+	}{
+		{
+			name:  "streak across a year boundary",
+			data:  makeYears(start, 400, func(int) int { return 1 }),
+			want:  []string{"streak-365", "anniversary"},
+			avoid: []string{"10k-year"},
+		},
+		{
+			name:  "streak broken by a gap",
+			data:  makeYears(start, 400, skipDay(200)),
+			avoid: []string{"streak-365"},
+		},
+		{
+			name:  "big year",
+			data:  makeYears(start, 100, func(int) int { return 120 }),
+			want:  []string{"10k-year"},
+			avoid: []string{"streak-365", "anniversary"},
+		},
+		{
+			name:  "no anniversary contribution",
+			data:  makeYears(start, 400, skipDay(365)),
+			avoid: []string{"anniversary"},
+		},
+		{
+			name:  "undated days are ignored",
+			data:  [][][]types.ContributionDay{{{{ContributionCount: 50000}}}},
+			avoid: []string{"10k-year"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := earnedIDs(tt.data)
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("expected badge %s, got %v", id, got)
+				}
+			}
+			for _, id := range tt.avoid {
+				if got[id] {
+					t.Errorf("did not expect badge %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	all := All()
+	if len(all) < 3 {
+		t.Fatalf("expected built-in badges, got %d", len(all))
+	}
+
+	for _, b := range all {
+		icon, err := b.LoadIcon()
+		if err != nil {
+			t.Errorf("LoadIcon(%s) error = %v", b.ID, err)
+			continue
+		}
+		if icon.Bounds().Dx() == 0 || icon.Bounds().Dy() == 0 {
+			t.Errorf("icon for %s is empty", b.ID)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with a duplicate ID should panic")
+		}
+	}()
+	Register(all[0])
+}
+
+func TestLoadIconMissing(t *testing.T) {
+	if _, err := (Badge{ID: "missing", Icon: "missing.png"}).LoadIcon(); err == nil {
+		t.Error("LoadIcon() for a missing asset should fail")
+	}
+}
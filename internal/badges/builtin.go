@@ -0,0 +1,53 @@
+package badges
+
+const (
+	// streakLength is the number of consecutive contribution days needed for the streak badge.
+	streakLength = 365
+
+	// bigYearContributions is the yearly total needed for the big-year badge.
+	bigYearContributions = 10000
+)
+
+func init() {
+	Register(Badge{ID: "streak-365", Name: "365-day streak", Icon: "streak.png", Earned: hasStreak})
+	Register(Badge{ID: "10k-year", Name: "10k contributions in a year", Icon: "star.png", Earned: hasBigYear})
+	Register(Badge{ID: "anniversary", Name: "First contribution anniversary", Icon: "cake.png", Earned: hasAnniversary})
+}
+
+// hasStreak reports whether there are streakLength consecutive days with contributions.
+func hasStreak(days []Day) bool {
+	return longestStreak(days) >= streakLength
+}
+
+// hasBigYear reports whether any calendar year totals bigYearContributions or more.
+func hasBigYear(days []Day) bool {
+	totals := map[int]int{}
+	for _, day := range days {
+		totals[day.Date.Year()] += day.Count
+		if totals[day.Date.Year()] >= bigYearContributions {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnniversary reports whether the user contributed again on the anniversary of the
+// first contribution in the history.
+func hasAnniversary(days []Day) bool {
+	var first *Day
+	for i, day := range days {
+		if day.Count <= 0 {
+			continue
+		}
+		if first == nil {
+			first = &days[i]
+			continue
+		}
+		if day.Date.Year() > first.Date.Year() &&
+			day.Date.Month() == first.Date.Month() &&
+			day.Date.Day() == first.Date.Day() {
+			return true
+		}
+	}
+	return false
+}
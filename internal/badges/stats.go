@@ -0,0 +1,50 @@
+package badges
+
+import (
+	"github.com/github/gh-skyline/internal/i18n"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Stats summarizes a contribution history.
+type Stats struct {
+	Total         int // Sum of every day's count
+	LongestStreak int // Most consecutive days with at least one contribution
+}
+
+// ComputeStats totals the given contributions ([year][week][day]) and finds their longest
+// streak. Days without a valid date are ignored.
+func ComputeStats(contributions [][][]types.ContributionDay) Stats {
+	days := flatten(contributions)
+	stats := Stats{LongestStreak: longestStreak(days)}
+	for _, day := range days {
+		stats.Total += day.Count
+	}
+	return stats
+}
+
+// Line formats the stats as a single compact line, such as
+// "4,321 contributions · 212 day streak", counting the total in the given unit.
+// The line and unit are translated and the numbers grouped for the active language.
+func (s Stats) Line(unit string) string {
+	return i18n.Sprintf("%s %s · %s day streak", i18n.FormatInt(s.Total), i18n.T(unit), i18n.FormatInt(s.LongestStreak))
+}
+
+// longestStreak returns the most consecutive days with contributions.
+func longestStreak(days []Day) int {
+	longest, streak := 0, 0
+	var previous Day
+	for _, day := range days {
+		if day.Count <= 0 {
+			streak = 0
+			continue
+		}
+		if streak > 0 && day.Date.Equal(previous.Date.AddDate(0, 0, 1)) {
+			streak++
+		} else {
+			streak = 1
+		}
+		longest = max(longest, streak)
+		previous = day
+	}
+	return longest
+}
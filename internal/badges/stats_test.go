@@ -0,0 +1,35 @@
+package badges
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC)
+	// Two contributions a day for 40 days across the new year, with days 10 and 15 empty.
+	years := makeYears(start, 40, func(i int) int {
+		if i == 10 || i == 15 {
+			return 0
+		}
+		return 2
+	})
+
+	stats := ComputeStats(years)
+	if stats.Total != 76 {
+		t.Errorf("Total = %d, want 76", stats.Total)
+	}
+	if stats.LongestStreak != 24 {
+		t.Errorf("LongestStreak = %d, want 24", stats.LongestStreak)
+	}
+	if got := ComputeStats(nil); got != (Stats{}) {
+		t.Errorf("ComputeStats(nil) = %+v, want zero", got)
+	}
+}
+
+func TestStatsLine(t *testing.T) {
+	got := Stats{Total: 4321, LongestStreak: 212}.Line("contributions")
+	if want := "4,321 contributions · 212 day streak"; got != want {
+		t.Errorf("Line() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,425 @@
+// Package bundle packages generated skyline artifacts into a zip archive together with
+// a manifest describing the contribution data and the SHA-256 of every file, optionally
+// signed so recipients can verify that neither the data nor the model was edited.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Well-known entry names inside a bundle.
+const (
+	ManifestName      = "manifest.json"
+	SignatureName     = "manifest.sig"
+	ContributionsName = "contributions.json"
+	SummaryName       = "summary.json"
+	PreviewName       = "preview.png"
+)
+
+// manifestVersion is bumped whenever the manifest layout changes incompatibly.
+const manifestVersion = 1
+
+// YearContributions holds the contribution grid for a single year.
+type YearContributions struct {
+	Year  int                       `json:"year"`
+	Weeks [][]types.ContributionDay `json:"weeks"`
+}
+
+// Contributions is the underlying data a skyline was generated from.
+type Contributions struct {
+	User  string              `json:"user"`
+	Years []YearContributions `json:"years"`
+}
+
+// YearSummary totals a single year.
+type YearSummary struct {
+	Year  int `json:"year"`
+	Total int `json:"total"`
+}
+
+// Summary is a human-readable overview of the contributions, for services and people who
+// look at the bundle without generating from it.
+type Summary struct {
+	User          string        `json:"user"`
+	StartYear     int           `json:"startYear"`
+	EndYear       int           `json:"endYear"`
+	Total         int           `json:"total"`
+	LongestStreak int           `json:"longestStreak"`
+	Achievements  []string      `json:"achievements,omitempty"`
+	Years         []YearSummary `json:"years"`
+
+	// RateLimit is the GitHub API budget left once the contributions were fetched; nil
+	// when they were read offline or the server does not limit requests.
+	RateLimit *types.RateLimit `json:"rateLimit,omitempty"`
+
+	// Print is a rough print time and filament estimate from the model's volume, made
+	// without a slicer; nil when none was made.
+	Print *types.PrintEstimate `json:"print,omitempty"`
+
+	// Slice is the slicer's print time and filament estimate for the model; nil when the
+	// model was not sliced.
+	Slice *types.PrintEstimate `json:"slice,omitempty"`
+}
+
+// File describes a single entry of the bundle.
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest lists every file in the bundle along with how it was produced.
+type Manifest struct {
+	Version   int       `json:"version"`
+	Generator string    `json:"generator"`
+	User      string    `json:"user"`
+	StartYear int       `json:"startYear"`
+	EndYear   int       `json:"endYear"`
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []File    `json:"files"`
+}
+
+// Options controls how a bundle is written.
+type Options struct {
+	Files         []string      // Paths of generated artifacts to include
+	Contributions Contributions // Data the artifacts were generated from
+	StartYear     int
+	EndYear       int
+	Signer        *Signer // Optional signer for the manifest
+
+	Summary *Summary // Optional overview stored as summary.json
+	Preview []byte   // Optional PNG rendering of the model stored as preview.png
+}
+
+// entry is an in-memory file destined for the archive.
+type entry struct {
+	name string
+	data []byte
+}
+
+// Write creates a zip archive at path containing the artifacts, the contribution data,
+// the manifest and, when a signer is configured, the manifest signature.
+func Write(path string, opts Options) (err error) {
+	if path == "" {
+		return errors.New(errors.ValidationError, "archive path cannot be empty", nil)
+	}
+
+	entries, err := collectEntries(opts)
+	if err != nil {
+		return err
+	}
+
+	manifest := Manifest{
+		Version:   manifestVersion,
+		Generator: "gh-skyline",
+		User:      opts.Contributions.User,
+		StartYear: opts.StartYear,
+		EndYear:   opts.EndYear,
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, e := range entries {
+		manifest.Files = append(manifest.Files, describe(e.name, e.data))
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode manifest", err)
+	}
+	entries = append(entries, entry{name: ManifestName, data: manifestData})
+
+	if opts.Signer != nil {
+		signature, err := opts.Signer.Sign(manifestData)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{name: SignatureName, data: signature})
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create archive", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close archive", cerr)
+		}
+	}()
+
+	zw := zip.NewWriter(file)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to add archive entry", err)
+		}
+		if _, err := w.Write(e.data); err != nil {
+			return errors.New(errors.IOError, "failed to write archive entry", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to finalize archive", err)
+	}
+	return nil
+}
+
+// WriteGzip compresses the file at source into a gzip file at path, recording its name
+// and modification time, e.g. to shrink a raw STL for uploading.
+func WriteGzip(path, source string) (err error) {
+	if path == "" {
+		return errors.New(errors.ValidationError, "archive path cannot be empty", nil)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to read archive input", err)
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to read archive input", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create archive", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close archive", cerr)
+		}
+	}()
+
+	zw, err := gzip.NewWriterLevel(file, gzip.BestCompression)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create gzip writer", err)
+	}
+	zw.Name = filepath.Base(source)
+	zw.ModTime = info.ModTime()
+	if _, err := zw.Write(data); err != nil {
+		return errors.New(errors.IOError, "failed to write archive", err)
+	}
+	if err := zw.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to finalize archive", err)
+	}
+	return nil
+}
+
+// collectEntries reads the artifacts from disk and serializes the contribution data.
+func collectEntries(opts Options) ([]entry, error) {
+	var entries []entry
+	seen := map[string]bool{ContributionsName: true, ManifestName: true, SignatureName: true, SummaryName: true, PreviewName: true}
+
+	for _, path := range opts.Files {
+		name := filepath.Base(path)
+		if seen[name] {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("duplicate archive entry %q", name), nil)
+		}
+		seen[name] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to read archive input", err)
+		}
+		entries = append(entries, entry{name: name, data: data})
+	}
+
+	data, err := json.Marshal(opts.Contributions)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to encode contribution data", err)
+	}
+	entries = append(entries, entry{name: ContributionsName, data: data})
+
+	if opts.Summary != nil {
+		data, err := json.MarshalIndent(opts.Summary, "", "  ")
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to encode summary", err)
+		}
+		entries = append(entries, entry{name: SummaryName, data: data})
+	}
+	if opts.Preview != nil {
+		entries = append(entries, entry{name: PreviewName, data: opts.Preview})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries, nil
+}
+
+// describe hashes a file's content for the manifest.
+func describe(name string, data []byte) File {
+	sum := sha256.Sum256(data)
+	return File{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+}
+
+// Result summarizes the outcome of verifying a bundle.
+type Result struct {
+	Manifest Manifest
+	Signed   bool   // Whether the bundle carried a signature
+	Trusted  bool   // Whether the signature was made by the trusted key passed to Verify
+	KeyID    string // Fingerprint of the key that signed the manifest
+}
+
+// Verify checks every file in the archive against the manifest and, if the bundle is
+// signed, validates the signature. When trusted is non-nil the signature must have been
+// made by that key. Otherwise the signer is unverified: anyone who edits the archive can
+// re-sign it with a key of their own, so callers must not treat the result as attested.
+func Verify(path string, trusted *PublicKey) (*Result, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open archive", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	entries, err := indexEntries(zr.File)
+	if err != nil {
+		return nil, err
+	}
+	contents := map[string][]byte{}
+	for name, f := range entries {
+		data, err := readEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		contents[name] = data
+	}
+
+	manifestData, ok := contents[ManifestName]
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "archive has no manifest", nil)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, errors.New(errors.ValidationError, "manifest is not valid JSON", err)
+	}
+
+	if err := checkFiles(manifest, contents); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Manifest: manifest}
+	signature, signed := contents[SignatureName]
+	if !signed {
+		if trusted != nil {
+			return nil, errors.New(errors.ValidationError, "archive is not signed", nil)
+		}
+		return result, nil
+	}
+
+	signer, err := verifySignature(manifestData, signature)
+	if err != nil {
+		return nil, err
+	}
+	if trusted != nil && !trusted.Equal(signer) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("archive was signed by %s, not the trusted key %s", signer.KeyID(), trusted.KeyID()), nil)
+	}
+	result.Signed = true
+	result.Trusted = trusted != nil
+	result.KeyID = signer.KeyID()
+	return result, nil
+}
+
+// checkFiles ensures the archive holds exactly the files listed in the manifest, unmodified.
+func checkFiles(manifest Manifest, contents map[string][]byte) error {
+	listed := map[string]bool{ManifestName: true, SignatureName: true}
+	for _, f := range manifest.Files {
+		listed[f.Name] = true
+		data, ok := contents[f.Name]
+		if !ok {
+			return errors.New(errors.ValidationError, fmt.Sprintf("file %q listed in manifest is missing", f.Name), nil)
+		}
+		if describe(f.Name, data) != f {
+			return errors.New(errors.ValidationError, fmt.Sprintf("file %q does not match the manifest", f.Name), nil)
+		}
+	}
+	for name := range contents {
+		if !listed[name] {
+			return errors.New(errors.ValidationError, fmt.Sprintf("file %q is not listed in the manifest", name), nil)
+		}
+	}
+	return nil
+}
+
+// indexEntries maps the archive's entries by name. Archives naming an entry twice are
+// rejected, as readers disagree on which of the two counts: one could be verified while
+// the other is read.
+func indexEntries(files []*zip.File) (map[string]*zip.File, error) {
+	entries := make(map[string]*zip.File, len(files))
+	for _, f := range files {
+		if _, ok := entries[f.Name]; ok {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("archive holds %q more than once", f.Name), nil)
+		}
+		entries[f.Name] = f
+	}
+	return entries, nil
+}
+
+// maxEntrySize guards against decompression bombs when reading archive entries.
+const maxEntrySize = 512 << 20
+
+// readEntry reads a single archive entry into memory.
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open archive entry", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read archive entry", err)
+	}
+	if n > maxEntrySize {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("archive entry %q is too large", f.Name), nil)
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadContributions reads the contribution data of a bundle, from either the archive
+// itself or a contributions.json extracted from one.
+func ReadContributions(path string) (*Contributions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read contributions", err)
+	}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to open archive", err)
+		}
+		// The entries are indexed as Verify indexes them, so the data read is the data it
+		// checked against the manifest.
+		entries, err := indexEntries(zr.File)
+		if err != nil {
+			return nil, err
+		}
+		f, ok := entries[ContributionsName]
+		if !ok {
+			return nil, errors.New(errors.ValidationError, "archive has no "+ContributionsName, nil)
+		}
+		if data, err = readEntry(f); err != nil {
+			return nil, err
+		}
+	}
+
+	var contributions Contributions
+	if err := json.Unmarshal(data, &contributions); err != nil {
+		return nil, errors.New(errors.ValidationError, "contributions are not valid JSON", err)
+	}
+	if contributions.User == "" {
+		return nil, errors.New(errors.ValidationError, "contributions do not name a user", nil)
+	}
+	return &contributions, nil
+}
@@ -0,0 +1,348 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// writeTestKeys creates a PKCS#8 private key and PKIX public key in dir.
+func writeTestKeys(t *testing.T, dir string) (privPath, pubPath string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privPath = filepath.Join(dir, "key.pem")
+	pubPath = filepath.Join(dir, "key.pub.pem")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath
+}
+
+// writeTestBundle writes a bundle containing a fake STL file.
+func writeTestBundle(t *testing.T, dir string, signer *Signer) string {
+	t.Helper()
+	stlPath := filepath.Join(dir, "mona-2024-github-skyline.stl")
+	if err := os.WriteFile(stlPath, []byte("solid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(dir, "bundle.zip")
+	err := Write(archive, Options{
+		Files: []string{stlPath},
+		Contributions: Contributions{
+			User: "mona",
+			Years: []YearContributions{{
+				Year:  2024,
+				Weeks: [][]types.ContributionDay{{{ContributionCount: 3, Date: "2024-01-01"}}},
+			}},
+		},
+		StartYear: 2024,
+		EndYear:   2024,
+		Signer:    signer,
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return archive
+}
+
+func TestWriteAndVerifyUnsigned(t *testing.T) {
+	archive := writeTestBundle(t, t.TempDir(), nil)
+
+	result, err := Verify(archive, nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Signed {
+		t.Error("Verify() reported an unsigned bundle as signed")
+	}
+	if result.Manifest.User != "mona" || len(result.Manifest.Files) != 2 {
+		t.Errorf("unexpected manifest: %+v", result.Manifest)
+	}
+}
+
+func TestWriteAndVerifySigned(t *testing.T) {
+	dir := t.TempDir()
+	privPath, pubPath := writeTestKeys(t, dir)
+
+	signer, err := LoadSigner(privPath)
+	if err != nil {
+		t.Fatalf("LoadSigner() error = %v", err)
+	}
+	trusted, err := LoadPublicKey(pubPath)
+	if err != nil {
+		t.Fatalf("LoadPublicKey() error = %v", err)
+	}
+
+	archive := writeTestBundle(t, dir, signer)
+
+	result, err := Verify(archive, trusted)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !result.Signed || !result.Trusted || result.KeyID != trusted.KeyID() {
+		t.Errorf("Verify() = %+v, want signed by %s", result, trusted.KeyID())
+	}
+
+	// Without a trusted key the signature is checked, but the signer is not vouched for.
+	result, err = Verify(archive, nil)
+	if err != nil {
+		t.Fatalf("Verify() without a trusted key error = %v", err)
+	}
+	if !result.Signed || result.Trusted {
+		t.Errorf("Verify() without a trusted key = %+v, want an unverified signer", result)
+	}
+
+	// A different trusted key must be rejected.
+	_, otherPub := writeTestKeys(t, t.TempDir())
+	other, err := LoadPublicKey(otherPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(archive, other); err == nil {
+		t.Error("Verify() accepted a signature from an untrusted key")
+	}
+}
+
+func TestVerifyUnsignedWithTrustedKey(t *testing.T) {
+	dir := t.TempDir()
+	_, pubPath := writeTestKeys(t, dir)
+	trusted, err := LoadPublicKey(pubPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := writeTestBundle(t, dir, nil)
+	if _, err := Verify(archive, trusted); err == nil {
+		t.Error("Verify() expected error when a key is required but the bundle is unsigned")
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	archive := writeTestBundle(t, dir, nil)
+
+	// Rewrite the archive with modified contribution data but the original manifest.
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := filepath.Join(dir, "tampered.zip")
+	out, err := os.Create(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(out)
+	for _, f := range zr.File {
+		data, err := readEntry(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name == ContributionsName {
+			data = []byte(`{"user":"mona","years":[]}`)
+		}
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = out.Close()
+	_ = zr.Close()
+
+	if _, err := Verify(tampered, nil); err == nil {
+		t.Error("Verify() accepted tampered contribution data")
+	}
+}
+
+func TestDuplicateEntriesRejected(t *testing.T) {
+	dir := t.TempDir()
+	archive := writeTestBundle(t, dir, nil)
+
+	// A forged contributions.json ahead of the genuine one, which the manifest hashes.
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = zr.Close() }()
+	forged := filepath.Join(dir, "forged.zip")
+	out, err := os.Create(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(out)
+	w, err := zw.Create(ContributionsName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(`{"user":"mona","years":[{"year":2024,"weeks":[[{"contributionCount":999,"date":"2024-01-01"}]]}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if err := zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = out.Close()
+
+	if _, err := Verify(forged, nil); err == nil {
+		t.Error("Verify() accepted an archive holding contributions.json twice")
+	}
+	if _, err := ReadContributions(forged); err == nil {
+		t.Error("ReadContributions() read an archive holding contributions.json twice")
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	dir := t.TempDir()
+	if err := Write("", Options{}); err == nil {
+		t.Error("Write() expected error for empty path")
+	}
+	if err := Write(filepath.Join(dir, "b.zip"), Options{Files: []string{filepath.Join(dir, "missing.stl")}}); err == nil {
+		t.Error("Write() expected error for missing input file")
+	}
+}
+
+func TestReadContributions(t *testing.T) {
+	dir := t.TempDir()
+	archive := writeTestBundle(t, dir, nil)
+
+	fromArchive, err := ReadContributions(archive)
+	if err != nil {
+		t.Fatalf("ReadContributions(archive) error = %v", err)
+	}
+	if fromArchive.User != "mona" || len(fromArchive.Years) != 1 || fromArchive.Years[0].Year != 2024 {
+		t.Errorf("ReadContributions(archive) = %+v", fromArchive)
+	}
+
+	jsonPath := filepath.Join(dir, ContributionsName)
+	if err := os.WriteFile(jsonPath, []byte(`{"user": "mona", "years": [{"year": 2023, "weeks": [[{"contributionCount": 1, "date": "2023-01-01"}]]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ReadContributions(jsonPath)
+	if err != nil {
+		t.Fatalf("ReadContributions(json) error = %v", err)
+	}
+	if fromJSON.Years[0].Year != 2023 || fromJSON.Years[0].Weeks[0][0].ContributionCount != 1 {
+		t.Errorf("ReadContributions(json) = %+v", fromJSON)
+	}
+
+	for name, content := range map[string]string{"invalid.json": "{", "anonymous.json": `{"years": []}`} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadContributions(path); err == nil {
+			t.Errorf("ReadContributions(%s) expected an error", name)
+		}
+	}
+}
+
+func TestWriteSummaryAndPreview(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "bundle.zip")
+	summary := &Summary{User: "mona", StartYear: 2024, EndYear: 2024, Total: 3, LongestStreak: 1, Years: []YearSummary{{Year: 2024, Total: 3}}}
+	if err := Write(archive, Options{Contributions: Contributions{User: "mona"}, Summary: summary, Preview: []byte("png")}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	result, err := Verify(archive, nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	names := map[string]bool{}
+	for _, f := range result.Manifest.Files {
+		names[f.Name] = true
+	}
+	if !names[SummaryName] || !names[PreviewName] {
+		t.Errorf("manifest lists %v, want the summary and preview", result.Manifest.Files)
+	}
+
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = zr.Close() }()
+	for _, f := range zr.File {
+		if f.Name != SummaryName {
+			continue
+		}
+		data, err := readEntry(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Summary
+		if err := json.Unmarshal(data, &got); err != nil || got.Total != 3 || len(got.Years) != 1 {
+			t.Errorf("summary = %+v, %v", got, err)
+		}
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "mona-2024-github-skyline.stl")
+	content := bytes.Repeat([]byte("solid skyline\n"), 100)
+	if err := os.WriteFile(source, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := source + ".gz"
+	if err := WriteGzip(path, source); err != nil {
+		t.Fatalf("WriteGzip() error = %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) || zr.Name != filepath.Base(source) {
+		t.Errorf("decompressed %d bytes named %q, want the %d source bytes named %q", len(data), zr.Name, len(content), filepath.Base(source))
+	}
+
+	if err := WriteGzip(path, filepath.Join(dir, "missing.stl")); err == nil {
+		t.Error("WriteGzip() expected error for a missing source")
+	}
+}
@@ -0,0 +1,191 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	stderrors "errors"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signatures use OpenSSH's SSHSIG format (PROTOCOL.sshsig), so an archive's manifest can
+// also be checked with ssh-keygen -Y verify -n gh-skyline.
+const (
+	// SignatureNamespace keeps manifest signatures from being mistaken for, or reused as,
+	// signatures made for another purpose with the same key.
+	SignatureNamespace = "gh-skyline"
+
+	sshsigMagic     = "SSHSIG"
+	sshsigVersion   = 1
+	sshsigHash      = "sha512"
+	sshsigPEMHeader = "SSH SIGNATURE"
+)
+
+// sshsigSigned is the blob that is actually signed: the namespace and a hash of the message.
+type sshsigSigned struct {
+	Namespace string
+	Reserved  string
+	Hash      string
+	Digest    string
+}
+
+// sshsigBlob is the armored content of manifest.sig.
+type sshsigBlob struct {
+	Version   uint32
+	PublicKey string
+	Namespace string
+	Reserved  string
+	Hash      string
+	Signature string
+}
+
+// Signer signs bundle manifests with an SSH private key.
+type Signer struct {
+	signer ssh.Signer
+}
+
+// PublicKey is an SSH public key trusted to have signed a bundle.
+type PublicKey struct {
+	key ssh.PublicKey
+}
+
+// LoadSigner reads an unencrypted private key, such as one from ssh-keygen -t ed25519
+// or a PKCS#8 PEM key from openssl genpkey -algorithm ed25519.
+func LoadSigner(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read signing key", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if stderrors.As(err, &missing) {
+			return nil, errors.New(errors.ValidationError, "encrypted signing keys are not supported; use an unencrypted key", nil)
+		}
+		return nil, errors.New(errors.ValidationError, "failed to parse signing key", err)
+	}
+	return &Signer{signer: signer}, nil
+}
+
+// Sign produces the manifest.sig document for the given manifest bytes.
+func (s *Signer) Sign(manifest []byte) ([]byte, error) {
+	signed := signedData(manifest)
+
+	var sig *ssh.Signature
+	var err error
+	// SSHSIG requires RSA keys to sign with SHA-512 rather than the SHA-1 ssh-rsa default.
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		sig, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signed, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signed)
+	}
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to sign manifest", err)
+	}
+
+	blob := ssh.Marshal(sshsigBlob{
+		Version:   sshsigVersion,
+		PublicKey: string(s.signer.PublicKey().Marshal()),
+		Namespace: SignatureNamespace,
+		Hash:      sshsigHash,
+		Signature: string(ssh.Marshal(sig)),
+	})
+	return pem.EncodeToMemory(&pem.Block{Type: sshsigPEMHeader, Bytes: append([]byte(sshsigMagic), blob...)}), nil
+}
+
+// LoadPublicKey reads a public key from an OpenSSH line (as found in .pub and
+// authorized_keys files) or a PKIX PEM file.
+func LoadPublicKey(path string) (*PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read public key", err)
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to parse PEM public key", err)
+		}
+		key, err := ssh.NewPublicKey(parsed)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, "unsupported public key type", err)
+		}
+		return &PublicKey{key: key}, nil
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "public key must be PEM or an OpenSSH public key line", err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// KeyID returns the SHA256 fingerprint of the key, as ssh-keygen -l reports it.
+func (p *PublicKey) KeyID() string {
+	return ssh.FingerprintSHA256(p.key)
+}
+
+// Equal reports whether p and other are the same key.
+func (p *PublicKey) Equal(other *PublicKey) bool {
+	return bytes.Equal(p.key.Marshal(), other.key.Marshal())
+}
+
+// verifySignature checks that the manifest signature is a valid SSHSIG made in the
+// gh-skyline namespace, and returns the key that made it. The caller decides whether
+// that key is trusted.
+func verifySignature(manifest, sigData []byte) (*PublicKey, error) {
+	block, _ := pem.Decode(sigData)
+	if block == nil || block.Type != sshsigPEMHeader {
+		return nil, errors.New(errors.ValidationError, "signature is not an SSH signature", nil)
+	}
+	raw, ok := bytes.CutPrefix(block.Bytes, []byte(sshsigMagic))
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "signature is not an SSH signature", nil)
+	}
+
+	var blob sshsigBlob
+	if err := ssh.Unmarshal(raw, &blob); err != nil {
+		return nil, errors.New(errors.ValidationError, "malformed SSH signature", err)
+	}
+	if blob.Version != sshsigVersion {
+		return nil, errors.New(errors.ValidationError, "unsupported SSH signature version", nil)
+	}
+	if blob.Namespace != SignatureNamespace {
+		return nil, errors.New(errors.ValidationError, "signature was made for namespace "+blob.Namespace+", not "+SignatureNamespace, nil)
+	}
+	if blob.Hash != sshsigHash {
+		return nil, errors.New(errors.ValidationError, "unsupported signature hash "+blob.Hash, nil)
+	}
+
+	key, err := ssh.ParsePublicKey([]byte(blob.PublicKey))
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "invalid public key in signature", err)
+	}
+	var sig ssh.Signature
+	if err := ssh.Unmarshal([]byte(blob.Signature), &sig); err != nil {
+		return nil, errors.New(errors.ValidationError, "malformed SSH signature", err)
+	}
+	if sig.Format == ssh.KeyAlgoRSA {
+		return nil, errors.New(errors.ValidationError, "RSA signatures must use rsa-sha2-512", nil)
+	}
+	if err := key.Verify(signedData(manifest), &sig); err != nil {
+		return nil, errors.New(errors.ValidationError, "manifest signature does not match", err)
+	}
+	return &PublicKey{key: key}, nil
+}
+
+// signedData builds the SSHSIG blob signed for the manifest.
+func signedData(manifest []byte) []byte {
+	digest := sha512.Sum512(manifest)
+	return append([]byte(sshsigMagic), ssh.Marshal(sshsigSigned{
+		Namespace: SignatureNamespace,
+		Hash:      sshsigHash,
+		Digest:    string(digest[:]),
+	})...)
+}
@@ -0,0 +1,144 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// writeOpenSSHKeys writes an OpenSSH private key and its .pub line, as ssh-keygen -t ed25519 does.
+func writeOpenSSHKeys(t *testing.T, dir, passphrase string) (privPath, pubPath string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "test@example")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "test@example", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privPath = filepath.Join(dir, "id_ed25519")
+	pubPath = filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, ssh.MarshalAuthorizedKey(sshPub), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath
+}
+
+func TestOpenSSHKeys(t *testing.T) {
+	privPath, pubPath := writeOpenSSHKeys(t, t.TempDir(), "")
+
+	signer, err := LoadSigner(privPath)
+	if err != nil {
+		t.Fatalf("LoadSigner() error = %v", err)
+	}
+	trusted, err := LoadPublicKey(pubPath)
+	if err != nil {
+		t.Fatalf("LoadPublicKey() error = %v", err)
+	}
+
+	manifest := []byte(`{"version":1}`)
+	sig, err := signer.Sign(manifest)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if !strings.HasPrefix(string(sig), "-----BEGIN SSH SIGNATURE-----") {
+		t.Errorf("Sign() = %q, want an armored SSH signature", sig)
+	}
+	key, err := verifySignature(manifest, sig)
+	if err != nil {
+		t.Fatalf("verifySignature() error = %v", err)
+	}
+	if !key.Equal(trusted) || !strings.HasPrefix(key.KeyID(), "SHA256:") {
+		t.Errorf("verifySignature() key = %s, want %s", key.KeyID(), trusted.KeyID())
+	}
+
+	if _, err := verifySignature([]byte(`{"version":2}`), sig); err == nil {
+		t.Error("verifySignature() accepted a modified manifest")
+	}
+}
+
+// TestSignatureMatchesSSHKeygen checks manifests signed here verify with ssh-keygen -Y verify.
+func TestSignatureMatchesSSHKeygen(t *testing.T) {
+	sshKeygen, err := exec.LookPath("ssh-keygen")
+	if err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+	dir := t.TempDir()
+	privPath, pubPath := writeOpenSSHKeys(t, dir, "")
+	signer, err := LoadSigner(privPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := []byte(`{"version":1}`)
+	sig, err := signer.Sign(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigPath := filepath.Join(dir, SignatureName)
+	pub, err := os.ReadFile(pubPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signersPath := filepath.Join(dir, "allowed_signers")
+	if err := os.WriteFile(sigPath, sig, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(signersPath, append([]byte("mona "), pub...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(sshKeygen, "-Y", "verify", "-n", SignatureNamespace, "-f", signersPath, "-I", "mona", "-s", sigPath) // #nosec G204 -- test input
+	cmd.Stdin = strings.NewReader(string(manifest))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("ssh-keygen -Y verify error = %v: %s", err, out)
+	}
+}
+
+func TestEncryptedOpenSSHKeyRejected(t *testing.T) {
+	privPath, _ := writeOpenSSHKeys(t, t.TempDir(), "secret")
+
+	if _, err := LoadSigner(privPath); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("LoadSigner() error = %v, want encrypted key error", err)
+	}
+}
+
+func TestLoadKeyErrors(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage")
+	if err := os.WriteFile(garbage, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadSigner(garbage); err == nil {
+		t.Error("LoadSigner() expected error for garbage input")
+	}
+	if _, err := LoadPublicKey(garbage); err == nil {
+		t.Error("LoadPublicKey() expected error for garbage input")
+	}
+	if _, err := LoadSigner(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadSigner() expected error for missing file")
+	}
+}
@@ -0,0 +1,148 @@
+// Package cache persists fetched contribution grids on disk so that interrupted
+// multi-year runs can be resumed without refetching the years already completed.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// dirName is the directory created under the user cache (or temp) directory.
+const dirName = "gh-skyline"
+
+// DefaultHost is the host of entries saved without one.
+const DefaultHost = "github.com"
+
+// MaxAge is how long an entry for a year that had not ended when it was fetched is trusted
+// to hold the year's contributions; later ones may have been added since.
+const MaxAge = 24 * time.Hour
+
+// validUsername matches GitHub login names, which also keeps cache paths safe.
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+
+// validHost matches host names with an optional port, which also keeps cache paths safe.
+var validHost = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9.-]*[a-z0-9])?(?::[0-9]+)?$`)
+
+// Entry is the on-disk representation of a single year's contribution grid.
+type Entry struct {
+	Host        string                    `json:"host"`
+	User        string                    `json:"user"`
+	Year        int                       `json:"year"`
+	FetchedAt   time.Time                 `json:"fetchedAt"`
+	Approximate bool                      `json:"approximate,omitempty"` // Rebuilt from REST endpoints, without private contributions
+	Weeks       [][]types.ContributionDay `json:"weeks"`
+}
+
+// Fresh reports whether the entry still holds all of its year's contributions at now: the
+// year had ended when it was fetched, or it was fetched less than MaxAge ago.
+func (e *Entry) Fresh(now time.Time) bool {
+	yearEnd := time.Date(e.Year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return !e.FetchedAt.Before(yearEnd) || now.Sub(e.FetchedAt) < MaxAge
+}
+
+// Cache stores per-year contribution grids below a root directory.
+type Cache struct {
+	dir string
+}
+
+// New creates a cache rooted at dir.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Default returns a cache in the user's cache directory, falling back to the
+// system temp directory when no user cache directory is available.
+func Default() *Cache {
+	base, err := os.UserCacheDir()
+	if err != nil || base == "" {
+		base = os.TempDir()
+	}
+	return New(filepath.Join(base, dirName))
+}
+
+// Dir returns the root directory of the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// path returns the file used to store the given user's year on host. Entries are kept
+// apart by host, as the same login may belong to different people on GitHub and on a
+// GitHub Enterprise Server.
+func (c *Cache) path(host, username string, year int) (string, error) {
+	if !validUsername.MatchString(username) {
+		return "", errors.New(errors.ValidationError, fmt.Sprintf("invalid username for cache: %q", username), nil)
+	}
+	host = strings.ToLower(host)
+	if host == "" {
+		host = DefaultHost
+	}
+	if !validHost.MatchString(host) {
+		return "", errors.New(errors.ValidationError, fmt.Sprintf("invalid host for cache: %q", host), nil)
+	}
+	// GitHub logins are case-insensitive, so normalize to keep one entry per user.
+	return filepath.Join(c.dir, strings.ReplaceAll(host, ":", "_"), strings.ToLower(username), fmt.Sprintf("%d.json", year)), nil
+}
+
+// Save writes the contribution grid of entry's user and year on its host, stamped with the
+// current time. The file is written to a temporary name first and renamed, so an
+// interrupted write never leaves a truncated entry behind.
+func (c *Cache) Save(entry Entry) error {
+	path, err := c.path(entry.Host, entry.User, entry.Year)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.New(errors.IOError, "failed to create cache directory", err)
+	}
+
+	entry.FetchedAt = time.Now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode cache entry", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return errors.New(errors.IOError, "failed to write cache entry", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.New(errors.IOError, "failed to finalize cache entry", err)
+	}
+	return nil
+}
+
+// Load reads the cached contribution grid for the given user and year on host.
+// The boolean result reports whether an entry was found.
+func (c *Cache) Load(host, username string, year int) (*Entry, bool, error) {
+	path, err := c.path(host, username, year)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.New(errors.IOError, "failed to read cache entry", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, errors.New(errors.IOError, "failed to decode cache entry", err)
+	}
+	if len(entry.Weeks) == 0 {
+		return nil, false, nil
+	}
+	return &entry, true, nil
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func testWeeks() [][]types.ContributionDay {
+	return [][]types.ContributionDay{
+		{
+			{ContributionCount: 1, Date: "2024-01-01"},
+			{ContributionCount: 0, Date: "2024-01-02"},
+		},
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	c := New(t.TempDir())
+
+	if err := c.Save(Entry{Host: "GHE.example.com", User: "MonaLisa", Year: 2024, Approximate: true, Weeks: testWeeks()}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	entry, ok, err := c.Load("ghe.example.com", "monalisa", 2024)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Load() expected cached entry to be found")
+	}
+	if entry.Year != 2024 || entry.User != "MonaLisa" || !entry.Approximate {
+		t.Errorf("Load() entry = %+v, want approximate year 2024 for MonaLisa", entry)
+	}
+	if len(entry.Weeks) != 1 || entry.Weeks[0][0].ContributionCount != 1 {
+		t.Errorf("Load() weeks = %+v, want round-tripped data", entry.Weeks)
+	}
+
+	// The same login on another host is someone else.
+	if _, ok, err := c.Load("", "monalisa", 2024); err != nil || ok {
+		t.Errorf("Load() on github.com = %v, %v, want no entry", ok, err)
+	}
+}
+
+func TestEntryFresh(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for name, tt := range map[string]struct {
+		year      int
+		fetchedAt time.Time
+		want      bool
+	}{
+		"ended year":         {2023, time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), true},
+		"current year today": {2024, now.Add(-time.Hour), true},
+		"current year stale": {2024, now.Add(-MaxAge), false},
+		"fetched mid-year":   {2023, time.Date(2023, time.November, 1, 0, 0, 0, 0, time.UTC), false},
+	} {
+		entry := Entry{Year: tt.year, FetchedAt: tt.fetchedAt}
+		if got := entry.Fresh(now); got != tt.want {
+			t.Errorf("%s: Fresh() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	c := New(t.TempDir())
+
+	_, ok, err := c.Load("", "mona", 2023)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if ok {
+		t.Error("Load() expected no entry for missing year")
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir)
+
+	path := filepath.Join(dir, DefaultHost, "mona", "2023.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.Load("", "mona", 2023); err == nil {
+		t.Error("Load() expected error for corrupt entry")
+	}
+}
+
+func TestInvalidUsername(t *testing.T) {
+	c := New(t.TempDir())
+
+	tests := []string{"", "../etc", "mona/lisa", "-mona"}
+	for _, username := range tests {
+		t.Run(username, func(t *testing.T) {
+			if err := c.Save(Entry{User: username, Year: 2024, Weeks: testWeeks()}); err == nil {
+				t.Errorf("Save(%q) expected error", username)
+			}
+		})
+	}
+	if _, _, err := c.Load("../etc", "mona", 2024); err == nil {
+		t.Error("Load() expected error for an invalid host")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if Default().Dir() == "" {
+		t.Error("Default() returned a cache without a directory")
+	}
+}
@@ -0,0 +1,115 @@
+// Package decorate runs decoration plugins: external commands that add custom geometry,
+// such as a company logo or an event badge, to generated models before they are written,
+// so organizers can brand bulk-generated skylines without changing the tool.
+//
+// A plugin reads the model as binary STL on its standard input and writes the geometry to
+// add, as binary or ASCII STL in the model's millimeter coordinates, to its standard
+// output; writing nothing adds nothing. The model's metadata is passed in SKYLINE_*
+// environment variables, such as SKYLINE_USER and SKYLINE_RANGE, and the rest of the
+// environment is withheld but for the few variables listed in inherited.
+package decorate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// timeout is how long a plugin may take to decorate a model.
+const timeout = 2 * time.Minute
+
+// inherited lists the variables plugins inherit besides the locale's LC_* ones: enough to
+// find programs and a home and temporary directory. The rest, such as GH_TOKEN or
+// OCTOPRINT_API_KEY, is withheld from plugins, which are often someone else's code.
+var inherited = []string{"PATH", "HOME", "TMPDIR", "LANG", "LANGUAGE", "SYSTEMROOT"}
+
+// Plugin is a decoration command.
+type Plugin struct {
+	Name string   // Name of the executable, which names the component it adds
+	Args []string // Executable and its arguments, run without a shell
+}
+
+// Parse splits a command line on whitespace into a plugin, checking that its executable
+// can be found.
+func Parse(command string) (*Plugin, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New(errors.ValidationError, "decoration command cannot be empty", nil)
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("decoration command %s was not found", args[0]), err)
+	}
+	args[0] = path
+	return &Plugin{Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), Args: args}, nil
+}
+
+// Decorate runs the plugin on the model and returns its geometry as a single label
+// component, or none when the plugin wrote nothing. It implements stl.Decorator.
+func (p *Plugin) Decorate(model *types.Model) ([]types.Component, error) {
+	var input bytes.Buffer
+	if err := stl.EncodeBinary(&input, model.Triangles()); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.Args[0], p.Args[1:]...)
+	cmd.Env = append(inheritedEnvironment(), environment(model.Metadata)...)
+	cmd.Stdin = &input
+	var output, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, errors.New(errors.GeneralError, fmt.Sprintf("decoration %s failed", p.Name), fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes())))
+	}
+	if output.Len() == 0 {
+		return nil, nil
+	}
+
+	f, err := stl.ParseSTL(output.Bytes())
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("decoration %s wrote no readable STL", p.Name), err)
+	}
+	if f.Truncated {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("decoration %s wrote a truncated STL", p.Name), nil)
+	}
+	return []types.Component{{Name: "decoration-" + p.Name, Material: types.MaterialLabel, Triangles: f.Triangles}}, nil
+}
+
+// environment returns the model's metadata as SKYLINE_* variables, such as
+// SKYLINE_USER=mona for the "user" field, sorted by name.
+func environment(metadata map[string]string) []string {
+	env := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		env = append(env, "SKYLINE_"+strings.ToUpper(key)+"="+value)
+	}
+	slices.Sort(env)
+	return env
+}
+
+// inheritedEnvironment returns the variables of the current environment that plugins
+// inherit. Names are matched in upper case, as Windows treats them without regard to case.
+func inheritedEnvironment() []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		name = strings.ToUpper(name)
+		if slices.Contains(inherited, name) || strings.HasPrefix(name, "LC_") {
+			env = append(env, variable)
+		}
+	}
+	return env
+}
@@ -0,0 +1,112 @@
+package decorate
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// writePlugin writes a shell script plugin to dir and puts dir on the PATH.
+func writePlugin(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDecorate(t *testing.T) {
+	// The plugin checks it was sent the model's triangle, then adds one above it.
+	writePlugin(t, "badge", `[ "$(wc -c)" -eq 134 ] || exit 1
+printf 'solid %s\nfacet normal 0 0 1\nouter loop\nvertex 0 0 %s\nvertex 1 0 %s\nvertex 0 1 %s\nendloop\nendfacet\nendsolid\n' "$SKYLINE_USER" "$1" "$1" "$1"
+`)
+	plugin, err := Parse("badge 5")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	model := &types.Model{
+		Components: []types.Component{{Name: "base", Triangles: []types.Triangle{{V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}}}}},
+		Metadata:   map[string]string{"user": "mona"},
+	}
+
+	components, err := plugin.Decorate(model)
+	if err != nil {
+		t.Fatalf("Decorate() error = %v", err)
+	}
+	if len(components) != 1 || components[0].Name != "decoration-badge" || components[0].Material != types.MaterialLabel {
+		t.Fatalf("Decorate() = %+v, want a single decoration-badge label", components)
+	}
+	if tris := components[0].Triangles; len(tris) != 1 || tris[0].V1.Z != 5 {
+		t.Errorf("decoration = %v, want one triangle at Z 5", tris)
+	}
+}
+
+func TestDecorateFailures(t *testing.T) {
+	writePlugin(t, "silent", "cat > /dev/null\n")
+	writePlugin(t, "broken", "echo 'no logo configured' >&2\nexit 3\n")
+	writePlugin(t, "garbled", "echo oops\n")
+
+	model := &types.Model{Components: []types.Component{{Name: "base"}}}
+	silent, err := Parse("silent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components, err := silent.Decorate(model); err != nil || len(components) != 0 {
+		t.Errorf("silent plugin: Decorate() = %v, %v, want nothing added", components, err)
+	}
+	for _, name := range []string{"broken", "garbled"} {
+		plugin, err := Parse(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := plugin.Decorate(model); err == nil {
+			t.Errorf("%s plugin: Decorate() expected an error", name)
+		}
+	}
+
+	for _, command := range []string{"", "no-such-decoration"} {
+		if _, err := Parse(command); err == nil {
+			t.Errorf("Parse(%q) expected an error", command)
+		}
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	got := environment(map[string]string{"user": "mona", "range": "2024"})
+	if want := []string{"SKYLINE_RANGE=2024", "SKYLINE_USER=mona"}; !slices.Equal(got, want) {
+		t.Errorf("environment() = %v, want %v", got, want)
+	}
+}
+
+func TestDecorateWithholdsSecrets(t *testing.T) {
+	// The plugin fails naming any credential it can see.
+	writePlugin(t, "snoop", `cat > /dev/null
+for name in GH_TOKEN GITHUB_TOKEN SKYLINE_APP_TOKEN THINGIVERSE_TOKEN OCTOPRINT_API_KEY; do
+	eval "value=\${$name:-}"
+	[ -z "$value" ] || { echo "$name is set" >&2; exit 1; }
+done
+[ "$LC_ALL" = C ] && [ -n "$HOME" ] && [ "$SKYLINE_USER" = mona ] || { echo "missing variables" >&2; exit 1; }
+`)
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "SKYLINE_APP_TOKEN", "THINGIVERSE_TOKEN", "OCTOPRINT_API_KEY"} {
+		t.Setenv(name, "secret")
+	}
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("HOME", t.TempDir())
+
+	plugin, err := Parse("snoop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &types.Model{Components: []types.Component{{Name: "base"}}, Metadata: map[string]string{"user": "mona"}}
+	if _, err := plugin.Decorate(model); err != nil {
+		t.Errorf("Decorate() error = %v, want credentials withheld and the locale, home and metadata passed", err)
+	}
+}
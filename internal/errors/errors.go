@@ -4,7 +4,10 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/github/gh-skyline/internal/i18n"
 )
 
 // ErrorType represents categories of errors that can occur in the application
@@ -16,10 +19,35 @@ const (
 	IOError         ErrorType = "IO"         // File/network I/O errors
 	NetworkError    ErrorType = "NETWORK"    // Network communication errors
 	GraphQLError    ErrorType = "GRAPHQL"    // GitHub GraphQL API errors
+	AuthError       ErrorType = "AUTH"       // Missing or rejected GitHub credentials
+	RateLimitError  ErrorType = "RATE_LIMIT" // GitHub API rate limit exhausted
 	STLError        ErrorType = "STL"        // STL file generation errors
 	GeneralError    ErrorType = "GENERAL"    // General errors not fitting other categories
 )
 
+// Process exit codes reported by the CLI for each error category.
+// These values are part of the documented command-line interface; scripts may rely on them.
+const (
+	ExitOK         = 0 // Successful run
+	ExitGeneral    = 1 // Unclassified failure
+	ExitAuth       = 2 // Authentication failure
+	ExitRateLimit  = 3 // GitHub API rate limit exhausted
+	ExitValidation = 4 // Bad flags, arguments or input data
+	ExitNetwork    = 5 // Network or GitHub API failure
+	ExitIO         = 6 // File system failure
+)
+
+// exitCodes maps error categories onto process exit codes.
+// Categories not listed here exit with ExitGeneral.
+var exitCodes = map[ErrorType]int{
+	AuthError:       ExitAuth,
+	RateLimitError:  ExitRateLimit,
+	ValidationError: ExitValidation,
+	NetworkError:    ExitNetwork,
+	GraphQLError:    ExitNetwork,
+	IOError:         ExitIO,
+}
+
 // SkylineError provides structured error information including type and context
 type SkylineError struct {
 	Type    ErrorType // Category of the error
@@ -35,22 +63,25 @@ func (e *SkylineError) Error() string {
 	return fmt.Sprintf("[%s] %s", e.Type, e.Message)
 }
 
-// New creates a new SkylineError with the specified type, message, and wrapped error
+// New creates a new SkylineError with the specified type, message, and wrapped error.
+// The message is translated into the active language.
 func New(errType ErrorType, message string, err error) *SkylineError {
 	return &SkylineError{
 		Type:    errType,
-		Message: message,
+		Message: i18n.T(message),
 		Err:     err,
 	}
 }
 
 // Wrap enhances an existing error with additional context while preserving its type
-// If the original error is nil, returns nil
+// If the original error is nil, returns nil. The context is translated into the active language.
 func Wrap(err error, message string) error {
 	if err == nil {
 		return nil
 	}
 
+	message = i18n.T(message)
+
 	// If it's already a SkylineError, preserve the error type
 	if skylineErr, ok := err.(*SkylineError); ok {
 		return &SkylineError{
@@ -81,3 +112,22 @@ func (e *SkylineError) Is(target error) bool {
 func (e *SkylineError) Unwrap() error {
 	return e.Err
 }
+
+// ExitCode returns the process exit code for err.
+// The error chain is searched for the first SkylineError with a specific category,
+// so a GeneralError wrapping a more specific failure reports the specific code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		skylineErr, ok := e.(*SkylineError)
+		if !ok {
+			continue
+		}
+		if code, ok := exitCodes[skylineErr.Type]; ok {
+			return code
+		}
+	}
+	return ExitGeneral
+}
@@ -2,6 +2,7 @@ package errors_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	skylineerrors "github.com/github/gh-skyline/internal/errors"
@@ -215,3 +216,45 @@ func TestSkylineError_Unwrap(t *testing.T) {
 		})
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil error", nil, skylineerrors.ExitOK},
+		{"plain error", errors.New("boom"), skylineerrors.ExitGeneral},
+		{"general error", skylineerrors.New(skylineerrors.GeneralError, "general", nil), skylineerrors.ExitGeneral},
+		{"auth error", skylineerrors.New(skylineerrors.AuthError, "no token", nil), skylineerrors.ExitAuth},
+		{"rate limit error", skylineerrors.New(skylineerrors.RateLimitError, "slow down", nil), skylineerrors.ExitRateLimit},
+		{"validation error", skylineerrors.New(skylineerrors.ValidationError, "bad flag", nil), skylineerrors.ExitValidation},
+		{"network error", skylineerrors.New(skylineerrors.NetworkError, "timeout", nil), skylineerrors.ExitNetwork},
+		{"graphql error", skylineerrors.New(skylineerrors.GraphQLError, "query failed", nil), skylineerrors.ExitNetwork},
+		{"io error", skylineerrors.New(skylineerrors.IOError, "disk full", nil), skylineerrors.ExitIO},
+		{"stl error", skylineerrors.New(skylineerrors.STLError, "bad mesh", nil), skylineerrors.ExitGeneral},
+		{
+			name: "wrapped by fmt",
+			err:  fmt.Errorf("context: %w", skylineerrors.New(skylineerrors.AuthError, "no token", nil)),
+			want: skylineerrors.ExitAuth,
+		},
+		{
+			name: "general error wrapping a specific one",
+			err:  skylineerrors.New(skylineerrors.GeneralError, "outer", skylineerrors.New(skylineerrors.RateLimitError, "inner", nil)),
+			want: skylineerrors.ExitRateLimit,
+		},
+		{
+			name: "preserved by Wrap",
+			err:  skylineerrors.Wrap(skylineerrors.New(skylineerrors.IOError, "write failed", nil), "saving"),
+			want: skylineerrors.ExitIO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := skylineerrors.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
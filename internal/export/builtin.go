@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func init() {
+	Register(stlExporter{})
+	Register(objExporter{})
+	Register(glbExporter{})
+}
+
+// stlExporter writes binary STL, the format every slicer reads.
+type stlExporter struct{}
+
+func (stlExporter) Extension() string { return "stl" }
+
+func (stlExporter) Write(model *types.Model, w io.Writer) error {
+	return stl.EncodeBinary(w, model.Triangles())
+}
+
+// objExporter writes Wavefront OBJ, which modelling tools such as Blender import with
+// shared vertices and a named object per component, so the model can be edited before
+// printing.
+type objExporter struct{}
+
+func (objExporter) Extension() string { return "obj" }
+
+// Write records the metadata as comments, then each component as an object. Each
+// distinct vertex is listed once, in order of first use, and faces refer to vertices by
+// their 1-based index across the whole file.
+func (objExporter) Write(model *types.Model, w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "# Generated by GitHub Contributions Skyline Generator")
+	keys := make([]string, 0, len(model.Metadata))
+	for key := range model.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(writer, "# %s: %s\n", key, model.Metadata[key])
+	}
+
+	index := map[types.Point3D]int{}
+	for _, c := range model.Components {
+		fmt.Fprintf(writer, "o %s\n", c.Name)
+		mesh := c.Mesh()
+		faces := make([][3]int, len(mesh))
+		for i, tri := range mesh {
+			for j, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				n, ok := index[v]
+				if !ok {
+					n = len(index) + 1
+					index[v] = n
+					fmt.Fprintf(writer, "v %g %g %g\n", v.X, v.Y, v.Z)
+				}
+				faces[i][j] = n
+			}
+		}
+		for _, f := range faces {
+			fmt.Fprintf(writer, "f %d %d %d\n", f[0], f[1], f[2])
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to write OBJ file", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// square returns a model of two triangles sharing an edge, four distinct vertices in
+// all, in a component of their own.
+func square() *types.Model {
+	a := types.Point3D{X: 0, Y: 0, Z: 0}
+	b := types.Point3D{X: 1, Y: 0, Z: 0}
+	c := types.Point3D{X: 1, Y: 1, Z: 0}
+	d := types.Point3D{X: 0, Y: 1, Z: 0}
+	normal := types.Point3D{Z: 1}
+	return &types.Model{
+		Components: []types.Component{{Name: "square", Triangles: []types.Triangle{{Normal: normal, V1: a, V2: b, V3: c}, {Normal: normal, V1: a, V2: c, V3: d}}}},
+		Metadata:   map[string]string{"user": "mona", "range": "2024"},
+	}
+}
+
+func TestSTLExporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (stlExporter{}).Write(square(), &buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// An 80-byte header, the count, and 50 bytes per triangle.
+	if want := 84 + 2*50; buf.Len() != want {
+		t.Errorf("STL size = %d, want %d", buf.Len(), want)
+	}
+}
+
+func TestOBJExporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (objExporter{}).Write(square(), &buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// Vertices shared between the triangles are listed once.
+	want := `# Generated by GitHub Contributions Skyline Generator
+# range: 2024
+# user: mona
+o square
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+f 1 2 3
+f 1 3 4
+`
+	if got := buf.String(); got != want {
+		t.Errorf("OBJ =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOBJExporterComponents(t *testing.T) {
+	model := square()
+	// A second, raised copy shares no vertices with the first, so it lists its own.
+	raised := model.Components[0]
+	raised.Name, raised.Transform = "raised", types.Transform{Offset: types.Point3D{Z: 1}}
+	model.Components = append(model.Components, raised)
+
+	var buf bytes.Buffer
+	if err := (objExporter{}).Write(model, &buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	obj := buf.String()
+	if !strings.Contains(obj, "o raised\nv 0 0 1\n") || !strings.HasSuffix(obj, "f 5 6 7\nf 5 7 8\n") {
+		t.Errorf("OBJ should place the raised component by its transform:\n%s", obj)
+	}
+}
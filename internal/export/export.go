@@ -0,0 +1,78 @@
+// Package export writes generated models in the file formats offered by --format. Each
+// format registers an Exporter at init, so the flag's completions and the extension of
+// generated filenames follow from the registry alone.
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Default is the format used when none is asked for.
+const Default = "stl"
+
+// Exporter writes a whole model in one file format. The format is named after its
+// extension, which is lower case and has no leading dot, e.g. "stl". Formats without
+// components or metadata write the model's placed triangles alone.
+type Exporter interface {
+	Extension() string
+	Write(model *types.Model, w io.Writer) error
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Exporter{}
+)
+
+// Register adds an exporter to the registry under its extension. Registering an
+// extension twice panics, as it is a programming error.
+func Register(e Exporter) {
+	mu.Lock()
+	defer mu.Unlock()
+	ext := e.Extension()
+	if _, ok := registry[ext]; ok {
+		panic(fmt.Sprintf("export: duplicate format %q", ext))
+	}
+	registry[ext] = e
+}
+
+// Names returns the registered formats in alphabetical order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the exporter for a format name, ignoring case.
+func Lookup(name string) (Exporter, error) {
+	mu.RLock()
+	e, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	mu.RUnlock()
+	if !ok {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("unknown format %q (expected %s)", name, strings.Join(Names(), ", ")), nil)
+	}
+	return e, nil
+}
+
+// ForPath returns the exporter whose extension a path ends in, reporting false when no
+// registered format matches.
+func ForPath(path string) (Exporter, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return nil, false
+	}
+	e, err := Lookup(ext)
+	return e, err == nil
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	if got := Names(); !slices.Equal(got, []string{"glb", "obj", "stl"}) {
+		t.Errorf("Names() = %v, want [glb obj stl]", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	e, err := Lookup(" OBJ ")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if e.Extension() != "obj" {
+		t.Errorf("Lookup(OBJ) extension = %q, want obj", e.Extension())
+	}
+	if _, err := Lookup("3mf"); err == nil {
+		t.Error("Lookup(3mf) expected error, got nil")
+	}
+}
+
+func TestForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string // Extension of the exporter found; empty when none is
+	}{
+		{"out/skyline.obj", "obj"},
+		{"skyline.STL", "stl"},
+		{"skyline.png", ""},
+		{"skyline", ""},
+	}
+	for _, tt := range tests {
+		e, ok := ForPath(tt.path)
+		if ok != (tt.want != "") || ok && e.Extension() != tt.want {
+			t.Errorf("ForPath(%q) = %v, %v, want %q", tt.path, e, ok, tt.want)
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with a duplicate format should panic")
+		}
+	}()
+	Register(objExporter{})
+}
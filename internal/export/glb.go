@@ -0,0 +1,210 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// GLB chunk markers and the header's magic number, "glTF" read as a little-endian word.
+const (
+	glbMagic     = 0x46546C67
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A
+	glbChunkBin  = 0x004E4942
+)
+
+// glbColors are the base colors of each material, linear RGBA. Components of other
+// materials, such as those read back from an STL file, are colored as the skyline.
+var glbColors = map[types.Material][4]float64{
+	types.MaterialBase:    {0.2, 0.2, 0.22, 1},
+	types.MaterialSkyline: {0.04, 0.65, 0.09, 1},
+	types.MaterialLabel:   {0.9, 0.9, 0.9, 1},
+}
+
+// glbExporter writes binary glTF, which web viewers and AR previews display with a color
+// per material.
+type glbExporter struct{}
+
+func (glbExporter) Extension() string { return "glb" }
+
+// Write lays out the model as a single mesh with one flat-shaded primitive per material,
+// in order of first use. The model is modelled in millimeters with Z up, so its node is
+// turned to glTF's Y up and scaled to meters. The metadata is kept in the asset's extras.
+func (glbExporter) Write(model *types.Model, w io.Writer) error {
+	var order []types.Material
+	meshes := map[types.Material][]types.Triangle{}
+	for _, c := range model.Components {
+		material := c.Material
+		if _, ok := glbColors[material]; !ok {
+			material = types.MaterialSkyline
+		}
+		if len(c.Triangles) == 0 {
+			continue
+		}
+		if _, ok := meshes[material]; !ok {
+			order = append(order, material)
+		}
+		meshes[material] = append(meshes[material], c.Mesh()...)
+	}
+
+	doc := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "gh-skyline", Extras: model.Metadata},
+		Scenes: []gltfScene{{Nodes: []int{}}},
+	}
+	if len(order) > 0 {
+		doc.Scenes[0].Nodes = []int{0}
+		doc.Nodes = []gltfNode{{
+			Mesh:     0,
+			Rotation: []float64{-math.Sqrt2 / 2, 0, 0, math.Sqrt2 / 2},
+			Scale:    []float64{0.001, 0.001, 0.001},
+		}}
+		doc.Meshes = []gltfMesh{{Name: "skyline"}}
+	}
+	var bin bytes.Buffer
+	for i, material := range order {
+		positions, normals, lo, hi := glbVertices(meshes[material])
+		doc.Materials = append(doc.Materials, gltfMaterial{Name: string(material), PBR: gltfPBR{BaseColor: glbColors[material], Metallic: 0, Roughness: 0.8}})
+		doc.Meshes[0].Primitives = append(doc.Meshes[0].Primitives, gltfPrimitive{
+			Attributes: map[string]int{"POSITION": 2 * i, "NORMAL": 2*i + 1},
+			Material:   i,
+		})
+		for j, data := range [][]float32{positions, normals} {
+			doc.BufferViews = append(doc.BufferViews, gltfBufferView{ByteOffset: bin.Len(), ByteLength: 4 * len(data), Target: 34962})
+			accessor := gltfAccessor{BufferView: len(doc.BufferViews) - 1, ComponentType: 5126, Count: len(data) / 3, Type: "VEC3"}
+			if j == 0 {
+				accessor.Min, accessor.Max = lo[:], hi[:]
+			}
+			doc.Accessors = append(doc.Accessors, accessor)
+			_ = binary.Write(&bin, binary.LittleEndian, data)
+		}
+	}
+	if bin.Len() > 0 {
+		doc.Buffers = []gltfBuffer{{ByteLength: bin.Len()}}
+	}
+
+	header, err := json.Marshal(doc)
+	if err != nil {
+		return errors.New(errors.GeneralError, "failed to encode glTF document", err)
+	}
+	header = append(header, bytes.Repeat([]byte(" "), pad4(len(header)))...)
+	body := append(bin.Bytes(), make([]byte, pad4(bin.Len()))...)
+
+	var out bytes.Buffer
+	size := 12 + 8 + len(header)
+	if len(body) > 0 {
+		size += 8 + len(body)
+	}
+	_ = binary.Write(&out, binary.LittleEndian, [3]uint32{glbMagic, glbVersion, uint32(size)})
+	_ = binary.Write(&out, binary.LittleEndian, [2]uint32{uint32(len(header)), glbChunkJSON})
+	out.Write(header)
+	if len(body) > 0 {
+		_ = binary.Write(&out, binary.LittleEndian, [2]uint32{uint32(len(body)), glbChunkBin})
+		out.Write(body)
+	}
+	if _, err := out.WriteTo(w); err != nil {
+		return errors.New(errors.IOError, "failed to write GLB file", err)
+	}
+	return nil
+}
+
+// glbVertices returns the vertex positions of the triangles and a normal for each vertex,
+// the face normal worked out from the winding, along with the positions' bounds.
+func glbVertices(triangles []types.Triangle) (positions, normals []float32, lo, hi [3]float64) {
+	positions = make([]float32, 0, 9*len(triangles))
+	normals = make([]float32, 0, 9*len(triangles))
+	lo = [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi = [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, tri := range triangles {
+		u := types.Point3D{X: tri.V2.X - tri.V1.X, Y: tri.V2.Y - tri.V1.Y, Z: tri.V2.Z - tri.V1.Z}
+		v := types.Point3D{X: tri.V3.X - tri.V1.X, Y: tri.V3.Y - tri.V1.Y, Z: tri.V3.Z - tri.V1.Z}
+		n := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+		if length := math.Sqrt(n.X*n.X + n.Y*n.Y + n.Z*n.Z); length > 0 {
+			n = types.Point3D{X: n.X / length, Y: n.Y / length, Z: n.Z / length}
+		} else {
+			n = tri.Normal
+		}
+		for _, p := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			positions = append(positions, float32(p.X), float32(p.Y), float32(p.Z))
+			normals = append(normals, float32(n.X), float32(n.Y), float32(n.Z))
+			for axis, value := range [3]float64{p.X, p.Y, p.Z} {
+				// Bounds are taken at the precision written, so they match the data exactly.
+				value = float64(float32(value))
+				lo[axis], hi[axis] = min(lo[axis], value), max(hi[axis], value)
+			}
+		}
+	}
+	return positions, normals, lo, hi
+}
+
+// pad4 returns the padding that brings n bytes up to a multiple of four.
+func pad4(n int) int {
+	return (4 - n%4) % 4
+}
+
+// The parts of a glTF 2.0 document written for a model.
+type (
+	gltfDocument struct {
+		Asset       gltfAsset        `json:"asset"`
+		Scene       int              `json:"scene"`
+		Scenes      []gltfScene      `json:"scenes"`
+		Nodes       []gltfNode       `json:"nodes,omitempty"`
+		Meshes      []gltfMesh       `json:"meshes,omitempty"`
+		Materials   []gltfMaterial   `json:"materials,omitempty"`
+		Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+		BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+		Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+	}
+	gltfAsset struct {
+		Version   string            `json:"version"`
+		Generator string            `json:"generator"`
+		Extras    map[string]string `json:"extras,omitempty"`
+	}
+	gltfScene struct {
+		Nodes []int `json:"nodes"`
+	}
+	gltfNode struct {
+		Mesh     int       `json:"mesh"`
+		Rotation []float64 `json:"rotation"`
+		Scale    []float64 `json:"scale"`
+	}
+	gltfMesh struct {
+		Name       string          `json:"name"`
+		Primitives []gltfPrimitive `json:"primitives"`
+	}
+	gltfPrimitive struct {
+		Attributes map[string]int `json:"attributes"`
+		Material   int            `json:"material"`
+	}
+	gltfMaterial struct {
+		Name string  `json:"name"`
+		PBR  gltfPBR `json:"pbrMetallicRoughness"`
+	}
+	gltfPBR struct {
+		BaseColor [4]float64 `json:"baseColorFactor"`
+		Metallic  float64    `json:"metallicFactor"`
+		Roughness float64    `json:"roughnessFactor"`
+	}
+	gltfAccessor struct {
+		BufferView    int       `json:"bufferView"`
+		ComponentType int       `json:"componentType"`
+		Count         int       `json:"count"`
+		Type          string    `json:"type"`
+		Min           []float64 `json:"min,omitempty"`
+		Max           []float64 `json:"max,omitempty"`
+	}
+	gltfBufferView struct {
+		Buffer     int `json:"buffer"`
+		ByteOffset int `json:"byteOffset"`
+		ByteLength int `json:"byteLength"`
+		Target     int `json:"target"`
+	}
+	gltfBuffer struct {
+		ByteLength int `json:"byteLength"`
+	}
+)
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGLBExporter(t *testing.T) {
+	model := square()
+	model.Components[0].Material = types.MaterialBase
+	label := model.Components[0]
+	label.Name, label.Material, label.Transform = "label", types.MaterialLabel, types.Transform{Offset: types.Point3D{Z: 1}}
+	model.Components = append(model.Components, label)
+
+	var buf bytes.Buffer
+	if err := (glbExporter{}).Write(model, &buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data := buf.Bytes()
+	var header [5]uint32
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header[0] != glbMagic || header[1] != glbVersion || int(header[2]) != len(data) || header[4] != glbChunkJSON {
+		t.Fatalf("GLB header = %x, want glTF 2 of %d bytes starting with its JSON", header, len(data))
+	}
+	if len(data)%4 != 0 || header[3]%4 != 0 {
+		t.Errorf("GLB chunks are not padded to four bytes")
+	}
+
+	var doc gltfDocument
+	if err := json.Unmarshal(data[20:20+header[3]], &doc); err != nil {
+		t.Fatalf("JSON chunk does not parse: %v", err)
+	}
+	if doc.Asset.Version != "2.0" || doc.Asset.Extras["user"] != "mona" {
+		t.Errorf("asset = %+v, want glTF 2.0 with the model's metadata", doc.Asset)
+	}
+	// A primitive per material, each of two triangles with a position and normal per vertex.
+	primitives := doc.Meshes[0].Primitives
+	if len(primitives) != 2 || doc.Materials[0].Name != "base" || doc.Materials[1].Name != "label" {
+		t.Fatalf("primitives = %+v, materials = %+v, want base then label", primitives, doc.Materials)
+	}
+	for _, accessor := range doc.Accessors {
+		if accessor.Count != 6 {
+			t.Errorf("accessor = %+v, want 6 vertices", accessor)
+		}
+	}
+	if position := doc.Accessors[primitives[1].Attributes["POSITION"]]; position.Min[2] != 1 || position.Max[0] != 1 {
+		t.Errorf("label bounds = %v to %v, want the square raised by 1", position.Min, position.Max)
+	}
+	if want := 4 * 6 * 3 * 4; doc.Buffers[0].ByteLength != want {
+		t.Errorf("buffer = %d bytes, want %d", doc.Buffers[0].ByteLength, want)
+	}
+}
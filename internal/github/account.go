@@ -0,0 +1,30 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// Account is a user on a GitHub host, such as a GitHub Enterprise Server account whose
+// contributions are merged into a github.com skyline.
+type Account struct {
+	Host string // Hostname the account lives on, e.g. github.com or ghe.example.com
+	User string // Login on that host
+}
+
+// ParseAccount converts a flag value of the form "host:user" into an Account.
+func ParseAccount(value string) (Account, error) {
+	host, user, ok := strings.Cut(value, ":")
+	host, user = strings.TrimSpace(host), strings.TrimSpace(user)
+	if !ok || host == "" || user == "" || strings.ContainsAny(user, ":/") {
+		return Account{}, fmt.Errorf("account %q must be host:user, e.g. ghe.example.com:mona", value)
+	}
+	return Account{Host: auth.NormalizeHostname(strings.ToLower(host)), User: user}, nil
+}
+
+// String returns the account in its flag form.
+func (a Account) String() string {
+	return a.Host + ":" + a.User
+}
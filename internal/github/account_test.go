@@ -0,0 +1,33 @@
+package github
+
+import "testing"
+
+func TestParseAccount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Account
+		wantErr bool
+	}{
+		{"github.com:mona", Account{Host: "github.com", User: "mona"}, false},
+		{"GHE.example.com:octocat", Account{Host: "ghe.example.com", User: "octocat"}, false},
+		{"api.github.com:mona", Account{Host: "github.com", User: "mona"}, false},
+		{"mona", Account{}, true},
+		{":mona", Account{}, true},
+		{"github.com:", Account{}, true},
+		{"github.com:mona:extra", Account{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseAccount(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAccount() = %+v, want %+v", got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.want.Host+":"+tt.want.User {
+				t.Errorf("String() = %q", got.String())
+			}
+		})
+	}
+}
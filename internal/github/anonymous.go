@@ -0,0 +1,71 @@
+package github
+
+import (
+	stderrors "errors"
+	"net/http"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// AppTokenEnv is the environment variable holding an optional token, such as a GitHub App
+// installation token, that anonymous clients send to raise their rate limits. It is not
+// used to read private contributions.
+const AppTokenEnv = "SKYLINE_APP_TOKEN"
+
+// errSignedOut is returned for every GraphQL query of an anonymous client, as the GraphQL
+// API requires authentication.
+var errSignedOut = stderrors.New("the GraphQL API requires authentication")
+
+// signedOutAPI stands in for the GraphQL API of anonymous clients, so calendars fall back
+// to the REST approximation and other queries fail with a clear error.
+type signedOutAPI struct{}
+
+// Do implements APIClient.
+func (signedOutAPI) Do(string, map[string]interface{}, interface{}) error {
+	return errSignedOut
+}
+
+// NewAnonymousClientInitializer returns an initializer for clients that read public data
+// only, without the GitHub CLI's credentials, whose API requests go through transport. A
+// nil transport uses the default. Contribution calendars are approximated from REST
+// endpoints, so private contributions are left out, and requests are sent without
+// credentials unless AppTokenEnv holds a token.
+func NewAnonymousClientInitializer(transport http.RoundTripper) ClientInitializer {
+	return func() (*Client, error) {
+		// Each client wraps its own copy, so the shared transport is never reassigned.
+		rt := transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		host := DefaultHost()
+		// go-gh looks up the CLI's credentials whenever no token is given, so signed out
+		// requests carry a placeholder that is removed before they are sent.
+		token := os.Getenv(AppTokenEnv)
+		if token == "" {
+			token, rt = "anonymous", signedOut{rt}
+		}
+		restClient, err := api.NewRESTClient(api.ClientOptions{Host: host, AuthToken: token, Transport: rt})
+		if err != nil {
+			return nil, errors.New(errors.NetworkError, "failed to create REST client", err)
+		}
+		client := NewClientWithFallback(signedOutAPI{}, tracedREST{restClient})
+		client.host = host
+		client.avatars = newAvatarClient(transport)
+		return client, nil
+	}
+}
+
+// signedOut sends requests without an Authorization header.
+type signedOut struct {
+	rt http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (s signedOut) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	return s.rt.RoundTrip(req)
+}
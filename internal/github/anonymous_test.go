@@ -0,0 +1,111 @@
+package github
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestAnonymousClient(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tt := range map[string]struct {
+		token string
+		want  string
+	}{
+		"signed out": {"", ""},
+		"app token":  {"ghs_app", "token ghs_app"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(AppTokenEnv, tt.token)
+			var authorization, requested []string
+			client, err := NewAnonymousClientInitializer(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				// Avatars are downloaded through the same transport, outside the API.
+				if req.URL.Host == "avatars.example" {
+					requested = append(requested, req.URL.String())
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(encoded.Bytes())), Request: req}, nil
+				}
+				authorization = append(authorization, req.Header.Get("Authorization"))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"login": "mona", "avatar_url": "https://avatars.example/u/1", "created_at": "2011-01-25T18:44:36Z"}`)),
+					Request:    req,
+				}, nil
+			}))()
+			if err != nil {
+				t.Fatalf("NewAnonymousClientInitializer() error = %v", err)
+			}
+
+			if !client.SignedOut() || client.Host() == "" {
+				t.Errorf("client SignedOut() = %v, Host() = %q, want a signed-out client of the default host", client.SignedOut(), client.Host())
+			}
+
+			// The join year and avatar come from the public profile instead of GraphQL.
+			year, err := client.GetUserJoinYear("mona")
+			if err != nil {
+				t.Fatalf("GetUserJoinYear() error = %v", err)
+			}
+			if year != 2011 {
+				t.Errorf("GetUserJoinYear() = %d, want 2011", year)
+			}
+			if _, err := client.FetchAvatar("mona"); err != nil {
+				t.Fatalf("FetchAvatar() error = %v", err)
+			}
+			if len(requested) != 1 || requested[0] != "https://avatars.example/u/1" {
+				t.Errorf("downloaded %q, want the profile's avatar", requested)
+			}
+			for _, sent := range authorization {
+				if sent != tt.want {
+					t.Errorf("request sent with Authorization %q, want %q", sent, tt.want)
+				}
+			}
+
+			// Nobody is signed in to be the authenticated user.
+			if _, err := client.GetAuthenticatedUser(); errors.ExitCode(err) != errors.ExitAuth {
+				t.Errorf("GetAuthenticatedUser() error = %v, want an authentication error", err)
+			}
+		})
+	}
+}
+
+func TestAnonymousClientInitializerReused(t *testing.T) {
+	t.Setenv(AppTokenEnv, "")
+	initialize := NewAnonymousClientInitializer(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"login": "mona", "created_at": "2011-01-25T18:44:36Z"}`)),
+			Request:    req,
+		}, nil
+	}))
+
+	// Clients are created concurrently, as for merged accounts, each wrapping the
+	// transport it was given once.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := initialize()
+			if err != nil {
+				t.Errorf("initializer error = %v", err)
+				return
+			}
+			if _, err := client.GetUserJoinYear("mona"); err != nil {
+				t.Errorf("GetUserJoinYear() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
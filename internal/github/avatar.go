@@ -0,0 +1,100 @@
+package github
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Register the GIF decoder for avatars
+	_ "image/jpeg" // Register the JPEG decoder for avatars
+	_ "image/png"  // Register the PNG decoder for avatars
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// avatarSize is the edge length in pixels of the avatar requested; the embossed relief
+	// keeps far fewer, so larger downloads would be wasted.
+	avatarSize = 96
+
+	// maxAvatarBytes caps the size of a downloaded avatar.
+	maxAvatarBytes = 4 << 20
+
+	// avatarTimeout bounds each avatar download.
+	avatarTimeout = 30 * time.Second
+)
+
+// avatarClient downloads avatar images, which are served outside the API, for clients
+// built without a transport of their own; replaced in tests.
+var avatarClient = &http.Client{Timeout: avatarTimeout}
+
+// newAvatarClient returns the client downloading avatars through transport, such as one
+// trusting a GitHub Enterprise Server's certificates, which serves avatars from its own
+// host. A nil transport uses the default.
+func newAvatarClient(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		return nil
+	}
+	return &http.Client{Timeout: avatarTimeout, Transport: transport}
+}
+
+// FetchAvatar downloads a user's avatar image.
+func (c *Client) FetchAvatar(username string) (image.Image, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+
+	// GraphQL query to fetch the address of the user's avatar at the size needed.
+	query := `
+    query Avatar($username: String!, $size: Int!) {
+        user(login: $username) {
+            avatarUrl(size: $size)
+        }
+    }`
+	variables := map[string]interface{}{
+		"username": username,
+		"size":     avatarSize,
+	}
+
+	var response types.AvatarResponse
+	if err := c.api.Do(query, variables, &response); err != nil {
+		if c.rest == nil || !graphQLUnavailable(err) {
+			return nil, classifyAPIError("failed to fetch avatar", err)
+		}
+		// The public profile links the avatar at its full size, which is scaled down anyway.
+		var user types.UserResponse
+		if err := c.rest.Get("users/"+url.PathEscape(username), &user); err != nil {
+			return nil, classifyAPIError("failed to fetch avatar", err)
+		}
+		response.User.AvatarURL = user.AvatarURL
+	}
+	if response.User.AvatarURL == "" {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("user %s has no avatar", username), nil)
+	}
+	return c.downloadImage(response.User.AvatarURL)
+}
+
+// downloadImage fetches and decodes a PNG, JPEG or GIF image.
+func (c *Client) downloadImage(url string) (image.Image, error) {
+	downloads := c.avatars
+	if downloads == nil {
+		downloads = avatarClient
+	}
+	resp, err := downloads.Get(url)
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to download avatar", explainTLSError(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(errors.NetworkError, "failed to download avatar", fmt.Errorf("download returned %s", resp.Status))
+	}
+
+	img, _, err := image.Decode(io.LimitReader(resp.Body, maxAvatarBytes))
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to decode avatar", err)
+	}
+	return img, nil
+}
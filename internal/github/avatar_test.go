@@ -0,0 +1,76 @@
+package github
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// roundTripFunc answers HTTP requests with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// serveAvatars answers avatar downloads with the given status and body for the test.
+func serveAvatars(t *testing.T, status int, body []byte) *[]string {
+	t.Helper()
+	var requested []string
+	original := avatarClient
+	avatarClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.String())
+		return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(bytes.NewReader(body)), Request: req}, nil
+	})}
+	t.Cleanup(func() { avatarClient = original })
+	return &requested
+}
+
+func TestFetchAvatar(t *testing.T) {
+	var encoded bytes.Buffer
+	avatar := image.NewGray(image.Rect(0, 0, 2, 2))
+	avatar.Set(1, 1, color.White)
+	if err := png.Encode(&encoded, avatar); err != nil {
+		t.Fatal(err)
+	}
+
+	requested := serveAvatars(t, http.StatusOK, encoded.Bytes())
+	img, err := newFixtureClient(t).FetchAvatar("octocat")
+	if err != nil {
+		t.Fatalf("FetchAvatar() error = %v", err)
+	}
+	if img.Bounds().Dx() != 2 || color.GrayModel.Convert(img.At(1, 1)) != (color.Gray{Y: 0xff}) {
+		t.Errorf("FetchAvatar() = %v, want the served 2x2 image", img.Bounds())
+	}
+	if want := "https://avatars.githubusercontent.com/u/583231?s=96&v=4"; len(*requested) != 1 || (*requested)[0] != want {
+		t.Errorf("downloaded %q, want %q", *requested, want)
+	}
+
+	for name, fetch := range map[string]func() error{
+		"empty username": func() error { _, err := newFixtureClient(t).FetchAvatar(""); return err },
+		"unknown user": func() error {
+			_, err := NewClient(&mocks.MockGitHubClient{}).FetchAvatar("ghost")
+			return err
+		},
+		"missing image": func() error {
+			serveAvatars(t, http.StatusNotFound, nil)
+			_, err := NewClient(&mocks.MockGitHubClient{Avatar: "https://avatars.example/1"}).FetchAvatar("octocat")
+			return err
+		},
+		"not an image": func() error {
+			serveAvatars(t, http.StatusOK, []byte("<html>"))
+			_, err := NewClient(&mocks.MockGitHubClient{Avatar: "https://avatars.example/1"}).FetchAvatar("octocat")
+			return err
+		},
+	} {
+		if err := fetch(); err == nil {
+			t.Errorf("%s: FetchAvatar() expected an error", name)
+		}
+	}
+}
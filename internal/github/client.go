@@ -3,9 +3,14 @@
 package github
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/cli/go-gh/v2/pkg/api"
+
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -17,7 +22,11 @@ type APIClient interface {
 
 // Client holds the API client
 type Client struct {
-	api APIClient
+	api       APIClient
+	host      string           // Host the client talks to; empty for clients built from an APIClient alone
+	rest      RESTClient       // Optional fallback for contribution calendars; nil disables it
+	rateLimit *types.RateLimit // Budget reported by the latest query; nil until one reports it
+	avatars   *http.Client     // Downloads avatars through the client's transport; nil uses the default
 }
 
 // NewClient creates a new GitHub client
@@ -25,6 +34,18 @@ func NewClient(apiClient APIClient) *Client {
 	return &Client{api: apiClient}
 }
 
+// Host returns the host the client talks to, such as github.com, or "" when unknown.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// SignedOut reports whether the client reads public data without credentials, so that its
+// contribution calendars are always approximated.
+func (c *Client) SignedOut() bool {
+	_, ok := c.api.(signedOutAPI)
+	return ok
+}
+
 // GetAuthenticatedUser fetches the authenticated user's login name from GitHub.
 func (c *Client) GetAuthenticatedUser() (string, error) {
 	// GraphQL query to fetch the authenticated user's login.
@@ -44,7 +65,7 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 	// Execute the GraphQL query.
 	err := c.api.Do(query, nil, &response)
 	if err != nil {
-		return "", errors.New(errors.NetworkError, "failed to fetch authenticated user", err)
+		return "", classifyAPIError("failed to fetch authenticated user", err)
 	}
 
 	if response.Viewer.Login == "" {
@@ -56,16 +77,39 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 
 // FetchContributions retrieves the contribution data for a given username and year from GitHub.
 func (c *Client) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
-	if username == "" {
-		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
-	}
-
 	if year < 2008 {
 		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
 	}
+	return c.FetchContributionsForDateRange(username, time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC))
+}
 
-	startDate := fmt.Sprintf("%d-01-01T00:00:00Z", year)
-	endDate := fmt.Sprintf("%d-12-31T23:59:59Z", year)
+// FetchContributionsForDateRange retrieves a user's contribution calendar for the days from
+// start to end, both included, over any number of days. Ranges longer than the year the
+// API serves per calendar are fetched a year at a time and stitched together by a
+// ContributionIterator.
+func (c *Client) FetchContributionsForDateRange(username string, start, end time.Time) (*types.ContributionsResponse, error) {
+	it := c.Contributions(username, start, end)
+	response := &types.ContributionsResponse{}
+	calendar := &response.User.ContributionsCollection.ContributionCalendar
+	for it.Next() {
+		calendar.Weeks = append(calendar.Weeks, struct {
+			ContributionDays []types.ContributionDay `json:"contributionDays"`
+		}{it.Week()})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	response.User.Login = it.Login()
+	calendar.TotalContributions = it.Total()
+	response.Approximate = it.Approximate()
+	return response, nil
+}
+
+// fetchWindow retrieves a user's contribution calendar for the days from start to end,
+// both included, which must span at most a year.
+func (c *Client) fetchWindow(username string, start, end time.Time) (*types.ContributionsResponse, error) {
+	startDate := start.Format(time.DateOnly) + "T00:00:00Z"
+	endDate := end.Format(time.DateOnly) + "T23:59:59Z"
 
 	// GraphQL query to fetch the user's contributions within the specified date range.
 	query := `
@@ -83,7 +127,7 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
                     }
                 }
             }
-        }
+        }` + rateLimitField + `
     }`
 
 	variables := map[string]interface{}{
@@ -95,10 +139,18 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 	var response types.ContributionsResponse
 
 	// Execute the GraphQL query.
+	if err := c.throttle(); err != nil {
+		return nil, err
+	}
 	err := c.api.Do(query, variables, &response)
 	if err != nil {
-		return nil, errors.New(errors.NetworkError, "failed to fetch contributions", err)
+		if c.rest != nil && graphQLUnavailable(err) {
+			return c.approximateContributions(username, start, end)
+		}
+		return nil, classifyAPIError("failed to fetch contributions", err)
 	}
+	c.recordRateLimit(response.RateLimit)
+	response.RateLimit = nil
 
 	if response.User.Login == "" {
 		return nil, errors.New(errors.ValidationError, "received empty username from GitHub API", nil)
@@ -107,6 +159,199 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 	return &response, nil
 }
 
+// contributionConnection is a contributionsCollection connection listing dated
+// contributions of a single type.
+type contributionConnection struct {
+	operation string // GraphQL operation name
+	field     string // Connection field on contributionsCollection
+}
+
+// Connections for the contribution types counted individually.
+var (
+	pullRequestConnection = contributionConnection{"PullRequestContributions", "pullRequestContributions"}
+	issueConnection       = contributionConnection{"IssueContributions", "issueContributions"}
+	reviewConnection      = contributionConnection{"PullRequestReviewContributions", "pullRequestReviewContributions"}
+)
+
+// FetchContributionBreakdown counts a user's pull requests, issues and reviews per day
+// ("YYYY-MM-DD", in UTC) for a year. Commits are not listed individually by the API;
+// ApplyBreakdown derives them from the calendar total.
+func (c *Client) FetchContributionBreakdown(username string, year int) (map[string]types.Breakdown, error) {
+	if err := validateFetch(username, year); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]types.Breakdown{}
+	for _, typed := range []struct {
+		conn  contributionConnection
+		count func(b *types.Breakdown) *int
+	}{
+		{pullRequestConnection, func(b *types.Breakdown) *int { return &b.PullRequests }},
+		{issueConnection, func(b *types.Breakdown) *int { return &b.Issues }},
+		{reviewConnection, func(b *types.Breakdown) *int { return &b.Reviews }},
+	} {
+		daily, err := c.fetchDailyCounts(username, year, typed.conn)
+		if err != nil {
+			return nil, classifyAPIError("failed to fetch contribution breakdown", err)
+		}
+		for date, n := range daily {
+			b := counts[date]
+			*typed.count(&b) = n
+			counts[date] = b
+		}
+	}
+
+	return counts, nil
+}
+
+// fetchDailyCounts pages through one type of contribution within a year and counts them
+// per day ("YYYY-MM-DD", in UTC). API errors are returned unclassified.
+func (c *Client) fetchDailyCounts(username string, year int, conn contributionConnection) (map[string]int, error) {
+	// GraphQL query to page through one type of contribution within the year.
+	query := fmt.Sprintf(`
+    query %s($username: String!, $from: DateTime!, $to: DateTime!, $cursor: String) {
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to) {
+                contributions: %s(first: 100, after: $cursor) {
+                    nodes {
+                        occurredAt
+                    }
+                    pageInfo {
+                        hasNextPage
+                        endCursor
+                    }
+                }
+            }
+        }%s
+    }`, conn.operation, conn.field, rateLimitField)
+
+	counts := map[string]int{}
+	var cursor interface{}
+	for {
+		variables := map[string]interface{}{
+			"username": username,
+			"from":     fmt.Sprintf("%d-01-01T00:00:00Z", year),
+			"to":       fmt.Sprintf("%d-12-31T23:59:59Z", year),
+			"cursor":   cursor,
+		}
+
+		if err := c.throttle(); err != nil {
+			return nil, err
+		}
+		var response types.ContributionEventsResponse
+		if err := c.api.Do(query, variables, &response); err != nil {
+			return nil, err
+		}
+		c.recordRateLimit(response.RateLimit)
+
+		contributions := response.User.ContributionsCollection.Contributions
+		for _, node := range contributions.Nodes {
+			counts[node.OccurredAt.UTC().Format("2006-01-02")]++
+		}
+
+		if !contributions.PageInfo.HasNextPage || contributions.PageInfo.EndCursor == "" {
+			return counts, nil
+		}
+		cursor = contributions.PageInfo.EndCursor
+	}
+}
+
+// validateFetch checks the arguments shared by the contribution queries.
+func validateFetch(username string, year int) error {
+	if username == "" {
+		return errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+
+	if year < 2008 {
+		return errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+	}
+	return nil
+}
+
+// FetchStargazers returns when each stargazer starred owner/repo, oldest first,
+// paging through every stargazer of the repository.
+func (c *Client) FetchStargazers(owner, repo string) ([]time.Time, error) {
+	if owner == "" || repo == "" {
+		return nil, errors.New(errors.ValidationError, "repository owner and name cannot be empty", nil)
+	}
+
+	// GraphQL query to page through a repository's stargazers in the order they starred it.
+	query := `
+    query Stargazers($owner: String!, $name: String!, $cursor: String) {
+        repository(owner: $owner, name: $name) {
+            stargazers(first: 100, after: $cursor, orderBy: {field: STARRED_AT, direction: ASC}) {
+                edges {
+                    starredAt
+                }
+                pageInfo {
+                    hasNextPage
+                    endCursor
+                }
+            }
+        }` + rateLimitField + `
+    }`
+
+	var starredAt []time.Time
+	var cursor interface{}
+	for {
+		variables := map[string]interface{}{
+			"owner":  owner,
+			"name":   repo,
+			"cursor": cursor,
+		}
+
+		if err := c.throttle(); err != nil {
+			return nil, err
+		}
+		var response types.StargazersResponse
+		if err := c.api.Do(query, variables, &response); err != nil {
+			return nil, classifyAPIError("failed to fetch stargazers", err)
+		}
+		c.recordRateLimit(response.RateLimit)
+
+		stargazers := response.Repository.Stargazers
+		for _, edge := range stargazers.Edges {
+			starredAt = append(starredAt, edge.StarredAt)
+		}
+
+		if !stargazers.PageInfo.HasNextPage || stargazers.PageInfo.EndCursor == "" {
+			return starredAt, nil
+		}
+		cursor = stargazers.PageInfo.EndCursor
+	}
+}
+
+// FetchLatestRelease fetches the tag and URL of a repository's most recent published release.
+func (c *Client) FetchLatestRelease(owner, repo string) (tag, url string, err error) {
+	if owner == "" || repo == "" {
+		return "", "", errors.New(errors.ValidationError, "repository owner and name cannot be empty", nil)
+	}
+
+	query := `
+    query LatestRelease($owner: String!, $name: String!) {
+        repository(owner: $owner, name: $name) {
+            latestRelease {
+                tagName
+                url
+            }
+        }
+    }`
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+	}
+
+	var response types.LatestReleaseResponse
+	if err := c.api.Do(query, variables, &response); err != nil {
+		return "", "", classifyAPIError("failed to fetch latest release", err)
+	}
+	release := response.Repository.LatestRelease
+	if release.TagName == "" {
+		return "", "", errors.New(errors.ValidationError, fmt.Sprintf("%s/%s has no published releases", owner, repo), nil)
+	}
+	return release.TagName, release.URL, nil
+}
+
 // GetUserJoinYear fetches the year a user joined GitHub using the GitHub API.
 func (c *Client) GetUserJoinYear(username string) (int, error) {
 	if username == "" {
@@ -134,7 +379,10 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 	// Execute the GraphQL query.
 	err := c.api.Do(query, variables, &response)
 	if err != nil {
-		return 0, errors.New(errors.NetworkError, "failed to fetch user's join date", err)
+		if c.rest != nil && graphQLUnavailable(err) {
+			return c.userJoinYear(username)
+		}
+		return 0, classifyAPIError("failed to fetch user's join date", err)
 	}
 
 	// Validate that the API returned a real creation date
@@ -147,3 +395,46 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 
 	return joinYear, nil
 }
+
+// classifyAPIError wraps an API failure in the error category matching its cause,
+// so that rejected credentials and exhausted rate limits surface with distinct exit codes.
+func classifyAPIError(message string, err error) error {
+	if stderrors.Is(err, errSignedOut) {
+		return errors.New(errors.AuthError, message+"; anonymous mode reads public calendars only", err)
+	}
+
+	var httpErr *api.HTTPError
+	if stderrors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusUnauthorized:
+			return errors.New(errors.AuthError, message+"; run 'gh auth login' to authenticate", err)
+		case httpErr.StatusCode == http.StatusTooManyRequests,
+			httpErr.StatusCode == http.StatusForbidden && isRateLimited(httpErr):
+			return errors.New(errors.RateLimitError, message+"; GitHub API rate limit exceeded", err)
+		}
+		return errors.New(errors.NetworkError, message, err)
+	}
+
+	var gqlErr *api.GraphQLError
+	if stderrors.As(err, &gqlErr) {
+		for _, item := range gqlErr.Errors {
+			switch item.Type {
+			case "RATE_LIMITED":
+				return errors.New(errors.RateLimitError, message+"; GitHub API rate limit exceeded", err)
+			case "NOT_FOUND":
+				return errors.New(errors.ValidationError, message, err)
+			}
+		}
+		return errors.New(errors.GraphQLError, message, err)
+	}
+
+	return errors.New(errors.NetworkError, message, explainTLSError(err))
+}
+
+// isRateLimited reports whether a 403 response was caused by rate limiting rather than permissions.
+func isRateLimited(httpErr *api.HTTPError) bool {
+	if httpErr.Headers.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(httpErr.Message), "rate limit")
+}
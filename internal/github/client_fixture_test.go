@@ -0,0 +1,184 @@
+package github
+
+import (
+	stderrors "errors"
+	"slices"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+)
+
+// newFixtureClient returns a client whose requests are answered from testdata/fixtures.
+// Fixtures can be refreshed against the live API with `gh skyline --record-fixtures DIR`.
+func newFixtureClient(t *testing.T) *Client {
+	t.Helper()
+	replayer, err := fixtures.NewReplayer("testdata/fixtures")
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	apiClient, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "fixture-token",
+		Transport: replayer,
+	})
+	if err != nil {
+		t.Fatalf("failed to create GraphQL client: %v", err)
+	}
+	return NewClient(apiClient)
+}
+
+func TestFixtureGetAuthenticatedUser(t *testing.T) {
+	login, err := newFixtureClient(t).GetAuthenticatedUser()
+	if err != nil {
+		t.Fatalf("GetAuthenticatedUser() error = %v", err)
+	}
+	if login != "octocat" {
+		t.Errorf("GetAuthenticatedUser() = %q, want octocat", login)
+	}
+}
+
+func TestFixtureFetchContributions(t *testing.T) {
+	client := newFixtureClient(t)
+
+	response, err := client.FetchContributions("octocat", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+
+	calendar := response.User.ContributionsCollection.ContributionCalendar
+	if got := len(calendar.Weeks); got != 53 {
+		t.Errorf("weeks = %d, want 53", got)
+	}
+	// The calendar starts mid-week, so the first week is partial.
+	if got := len(calendar.Weeks[0].ContributionDays); got != 6 {
+		t.Errorf("first week days = %d, want 6", got)
+	}
+	if got := calendar.Weeks[0].ContributionDays[0].Date; got != "2024-01-01" {
+		t.Errorf("first day = %s, want 2024-01-01", got)
+	}
+
+	sum := 0
+	for _, week := range calendar.Weeks {
+		for _, day := range week.ContributionDays {
+			sum += day.ContributionCount
+		}
+	}
+	if sum != calendar.TotalContributions {
+		t.Errorf("sum of days = %d, want totalContributions %d", sum, calendar.TotalContributions)
+	}
+
+	_, err = client.FetchContributions("ghost-404", 2024)
+	var skylineErr *errors.SkylineError
+	if !stderrors.As(err, &skylineErr) || skylineErr.Type != errors.ValidationError {
+		t.Errorf("FetchContributions() for unknown user error = %v, want validation error", err)
+	}
+}
+
+func TestFixtureGetUserJoinYear(t *testing.T) {
+	client := newFixtureClient(t)
+
+	year, err := client.GetUserJoinYear("octocat")
+	if err != nil {
+		t.Fatalf("GetUserJoinYear() error = %v", err)
+	}
+	if year != 2011 {
+		t.Errorf("GetUserJoinYear() = %d, want 2011", year)
+	}
+
+	_, err = client.GetUserJoinYear("rate-limited")
+	if got := errors.ExitCode(err); got != errors.ExitRateLimit {
+		t.Errorf("GetUserJoinYear() rate limited exit code = %d, want %d (error %v)", got, errors.ExitRateLimit, err)
+	}
+
+	if _, err := client.GetUserJoinYear("unrecorded"); err == nil {
+		t.Error("GetUserJoinYear() for a request without a fixture should fail")
+	}
+}
+
+func TestFixtureFetchContributionBreakdown(t *testing.T) {
+	counts, err := newFixtureClient(t).FetchContributionBreakdown("octocat", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributionBreakdown() error = %v", err)
+	}
+
+	// Pull requests span two pages of results.
+	if got := counts["2024-03-04"]; got.PullRequests != 2 || got.Issues != 1 || got.Reviews != 1 {
+		t.Errorf("2024-03-04 breakdown = %+v, want 2 pull requests, 1 issue and 1 review", got)
+	}
+	if got := counts["2024-06-11"].PullRequests; got != 1 {
+		t.Errorf("2024-06-11 pull requests = %d, want 1", got)
+	}
+	if len(counts) != 2 {
+		t.Errorf("breakdown has %d days, want 2", len(counts))
+	}
+}
+
+func TestFixtureFetchStargazers(t *testing.T) {
+	client := newFixtureClient(t)
+
+	starredAt, err := client.FetchStargazers("octocat", "Hello-World")
+	if err != nil {
+		t.Fatalf("FetchStargazers() error = %v", err)
+	}
+	// Stargazers span two pages of results.
+	if len(starredAt) != 4 {
+		t.Fatalf("stargazers = %d, want 4", len(starredAt))
+	}
+	if got := starredAt[3].Format("2006-01-02"); got != "2012-05-14" {
+		t.Errorf("last star = %s, want 2012-05-14", got)
+	}
+
+	_, err = client.FetchStargazers("octocat", "missing")
+	var skylineErr *errors.SkylineError
+	if !stderrors.As(err, &skylineErr) || skylineErr.Type != errors.ValidationError {
+		t.Errorf("FetchStargazers() for unknown repository error = %v, want validation error", err)
+	}
+
+	if _, err := client.FetchStargazers("", "Hello-World"); err == nil {
+		t.Error("FetchStargazers() expected error for empty owner")
+	}
+}
+
+func TestFixtureFetchLatestRelease(t *testing.T) {
+	client := newFixtureClient(t)
+
+	tag, url, err := client.FetchLatestRelease("github", "gh-skyline")
+	if err != nil {
+		t.Fatalf("FetchLatestRelease() error = %v", err)
+	}
+	if tag != "v1.4.0" || url != "https://github.com/github/gh-skyline/releases/tag/v1.4.0" {
+		t.Errorf("FetchLatestRelease() = %q, %q", tag, url)
+	}
+
+	if _, _, err := client.FetchLatestRelease("octocat", "Hello-World"); err == nil {
+		t.Error("FetchLatestRelease() expected error for a repository without releases")
+	}
+	if _, _, err := client.FetchLatestRelease("", "gh-skyline"); err == nil {
+		t.Error("FetchLatestRelease() expected error for empty owner")
+	}
+}
+
+func TestFixtureFetchOrgMembers(t *testing.T) {
+	client := newFixtureClient(t)
+
+	// Members span two pages of results.
+	members, err := client.FetchOrgMembers("github")
+	if err != nil {
+		t.Fatalf("FetchOrgMembers() error = %v", err)
+	}
+	if want := []string{"octocat", "hubot", "monalisa"}; !slices.Equal(members, want) {
+		t.Errorf("FetchOrgMembers() = %v, want %v", members, want)
+	}
+
+	_, err = client.FetchOrgMembers("missing")
+	var skylineErr *errors.SkylineError
+	if !stderrors.As(err, &skylineErr) || skylineErr.Type != errors.ValidationError {
+		t.Errorf("FetchOrgMembers() for unknown organization error = %v, want validation error", err)
+	}
+
+	if _, err := client.FetchOrgMembers(""); err == nil {
+		t.Error("FetchOrgMembers() expected error for empty organization")
+	}
+}
@@ -1,8 +1,12 @@
 package github
 
 import (
+	stderrors "errors"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
 	"github.com/github/gh-skyline/internal/types"
@@ -207,3 +211,83 @@ func TestFetchContributions(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchContributionsForDateRange(t *testing.T) {
+	client := NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+	start := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	if _, err := client.FetchContributionsForDateRange("testuser", start, end); err != nil {
+		t.Errorf("FetchContributionsForDateRange() error = %v", err)
+	}
+
+	if _, err := client.FetchContributionsForDateRange("", start, end); err == nil {
+		t.Error("expected error for empty username")
+	}
+	if _, err := client.FetchContributionsForDateRange("testuser", end, start); err == nil {
+		t.Error("expected error for a reversed range")
+	}
+	if _, err := client.FetchContributionsForDateRange("testuser", start.AddDate(-16, 0, 0), end); err == nil {
+		t.Error("expected error for dates before 2008")
+	}
+}
+
+func TestFetchContributionBreakdown(t *testing.T) {
+	client := NewClient(&mocks.MockGitHubClient{Username: "testuser"})
+	counts, err := client.FetchContributionBreakdown("testuser", 2023)
+	if err != nil {
+		t.Fatalf("FetchContributionBreakdown() error = %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("expected no counts from an empty response, got %v", counts)
+	}
+
+	if _, err := client.FetchContributionBreakdown("", 2023); err == nil {
+		t.Error("expected error for empty username")
+	}
+	if _, err := client.FetchContributionBreakdown("testuser", 2007); err == nil {
+		t.Error("expected error for year before 2008")
+	}
+
+	failing := NewClient(&mocks.MockGitHubClient{Err: errors.New(errors.NetworkError, "network error", nil)})
+	if _, err := failing.FetchContributionBreakdown("testuser", 2023); err == nil {
+		t.Error("expected error when the API fails")
+	}
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	rateLimitHeaders := http.Header{}
+	rateLimitHeaders.Set("X-RateLimit-Remaining", "0")
+
+	tests := []struct {
+		name string
+		err  error
+		want errors.ErrorType
+	}{
+		{"unauthorized", &api.HTTPError{StatusCode: http.StatusUnauthorized}, errors.AuthError},
+		{"too many requests", &api.HTTPError{StatusCode: http.StatusTooManyRequests}, errors.RateLimitError},
+		{"forbidden with exhausted quota", &api.HTTPError{StatusCode: http.StatusForbidden, Headers: rateLimitHeaders}, errors.RateLimitError},
+		{"forbidden with rate limit message", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "API rate limit exceeded"}, errors.RateLimitError},
+		{"forbidden permissions", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible"}, errors.NetworkError},
+		{"server error", &api.HTTPError{StatusCode: http.StatusBadGateway}, errors.NetworkError},
+		{"graphql rate limited", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "RATE_LIMITED"}}}, errors.RateLimitError},
+		{"graphql unknown user", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}}}, errors.ValidationError},
+		{"graphql other", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Message: "bad query"}}}, errors.GraphQLError},
+		{"transport failure", stderrors.New("connection refused"), errors.NetworkError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyAPIError("request failed", tt.err)
+			var skylineErr *errors.SkylineError
+			if !stderrors.As(err, &skylineErr) {
+				t.Fatalf("classifyAPIError() = %T, want *SkylineError", err)
+			}
+			if skylineErr.Type != tt.want {
+				t.Errorf("classifyAPIError() type = %v, want %v", skylineErr.Type, tt.want)
+			}
+			if !stderrors.Is(err, tt.err) {
+				t.Error("classifyAPIError() should wrap the original error")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// maxCommitRepositories is the most repositories the API lists commit contributions for
+// in one collection; commits to any others are left out of the hour counts.
+const maxCommitRepositories = 100
+
+// FetchCommitHours counts the commits a user authored in a year by the hour of the day
+// they were authored, in the time zone each commit records. Commits are read from the
+// default branch of each repository the user's contribution calendar counts commits to,
+// so the totals match the calendar's commit contributions up to the repository limit.
+func (c *Client) FetchCommitHours(username string, year int) ([24]int, error) {
+	var hours [24]int
+	if err := validateFetch(username, year); err != nil {
+		return hours, err
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0).Add(-time.Second)
+
+	// GraphQL query to list the repositories the user committed to within the year.
+	query := `
+    query CommitRepositories($username: String!, $from: DateTime!, $to: DateTime!) {
+        user(login: $username) {
+            id
+            contributionsCollection(from: $from, to: $to) {
+                commitContributionsByRepository(maxRepositories: ` + fmt.Sprint(maxCommitRepositories) + `) {
+                    repository {
+                        owner {
+                            login
+                        }
+                        name
+                    }
+                }
+            }
+        }` + rateLimitField + `
+    }`
+	variables := map[string]interface{}{
+		"username": username,
+		"from":     from.Format(time.RFC3339),
+		"to":       to.Format(time.RFC3339),
+	}
+
+	if err := c.throttle(); err != nil {
+		return hours, err
+	}
+	var response types.CommitRepositoriesResponse
+	if err := c.api.Do(query, variables, &response); err != nil {
+		return hours, classifyAPIError("failed to fetch commit repositories", err)
+	}
+	c.recordRateLimit(response.RateLimit)
+
+	for _, contribution := range response.User.ContributionsCollection.CommitContributionsByRepository {
+		repository := contribution.Repository
+		if err := c.countCommitHours(repository.Owner.Login, repository.Name, response.User.ID, from, to, &hours); err != nil {
+			return hours, classifyAPIError(fmt.Sprintf("failed to fetch commits of %s/%s", repository.Owner.Login, repository.Name), err)
+		}
+	}
+	return hours, nil
+}
+
+// countCommitHours pages through the commits authorID authored to the default branch of
+// owner/name between from and to, adding each to the hour it was authored at. API errors
+// are returned unclassified.
+func (c *Client) countCommitHours(owner, name, authorID string, from, to time.Time, hours *[24]int) error {
+	// GraphQL query to page through an author's commits to the default branch.
+	query := `
+    query CommitHistory($owner: String!, $name: String!, $author: ID!, $since: GitTimestamp!, $until: GitTimestamp!, $cursor: String) {
+        repository(owner: $owner, name: $name) {
+            defaultBranchRef {
+                target {
+                    ... on Commit {
+                        history(first: 100, after: $cursor, author: {id: $author}, since: $since, until: $until) {
+                            nodes {
+                                authoredDate
+                            }
+                            pageInfo {
+                                hasNextPage
+                                endCursor
+                            }
+                        }
+                    }
+                }
+            }
+        }` + rateLimitField + `
+    }`
+
+	var cursor interface{}
+	for {
+		variables := map[string]interface{}{
+			"owner":  owner,
+			"name":   name,
+			"author": authorID,
+			"since":  from.Format(time.RFC3339),
+			"until":  to.Format(time.RFC3339),
+			"cursor": cursor,
+		}
+
+		if err := c.throttle(); err != nil {
+			return err
+		}
+		var response types.CommitHistoryResponse
+		if err := c.api.Do(query, variables, &response); err != nil {
+			return err
+		}
+		c.recordRateLimit(response.RateLimit)
+
+		branch := response.Repository.DefaultBranchRef
+		if branch == nil {
+			return nil
+		}
+		history := branch.Target.History
+		// Git timestamps keep the author's UTC offset, so the hour is their local time.
+		for _, node := range history.Nodes {
+			hours[node.AuthoredDate.Hour()]++
+		}
+
+		if !history.PageInfo.HasNextPage || history.PageInfo.EndCursor == "" {
+			return nil
+		}
+		cursor = history.PageInfo.EndCursor
+	}
+}
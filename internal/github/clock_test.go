@@ -0,0 +1,29 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func TestFetchCommitHours(t *testing.T) {
+	hours, err := newFixtureClient(t).FetchCommitHours("octocat", 2024)
+	if err != nil {
+		t.Fatalf("FetchCommitHours() error = %v", err)
+	}
+	// Commits count at the hour of their own time zone, across every page of every
+	// repository; the empty repository has no default branch.
+	want := [24]int{9: 2, 23: 1}
+	if hours != want {
+		t.Errorf("FetchCommitHours() = %v, want %v", hours, want)
+	}
+
+	if _, err := newFixtureClient(t).FetchCommitHours("", 2024); err == nil {
+		t.Error("expected error for empty username")
+	}
+	failing := NewClient(&mocks.MockGitHubClient{Err: errors.New(errors.NetworkError, "network error", nil)})
+	if _, err := failing.FetchCommitHours("octocat", 2024); err == nil {
+		t.Error("expected error when the API fails")
+	}
+}
@@ -0,0 +1,218 @@
+// Package fixtures records GitHub API exchanges as JSON fixtures, for the hidden
+// --record-fixtures flag; the tests replay them with internal/testutil/fixtures.
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+)
+
+// recordedHeaders lists the response headers kept in fixtures. Everything else,
+// including anything that could identify the recording account, is dropped.
+var recordedHeaders = []string{
+	"Content-Type",
+	"X-Ratelimit-Limit",
+	"X-Ratelimit-Remaining",
+	"X-Ratelimit-Reset",
+	"X-Ratelimit-Resource",
+	"X-Ratelimit-Used",
+}
+
+// operationPattern extracts the operation name from a GraphQL document.
+var operationPattern = regexp.MustCompile(`^\s*(?:query|mutation)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Fixture is a single recorded GraphQL exchange.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest identifies the request a fixture answers. Requests are matched on
+// method, operation name and variables; the query text is kept for reference only.
+type FixtureRequest struct {
+	Method    string                 `json:"method"`
+	Operation string                 `json:"operation"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Query     string                 `json:"query,omitempty"`
+}
+
+// FixtureResponse is the recorded reply. JSON bodies are stored inline in Body so
+// fixtures stay readable; anything else is kept verbatim in Text.
+type FixtureResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Text    string            `json:"text,omitempty"`
+}
+
+// Content returns the raw response body.
+func (r FixtureResponse) Content() []byte {
+	if len(r.Body) > 0 {
+		return r.Body
+	}
+	return []byte(r.Text)
+}
+
+// graphQLRequest mirrors the JSON body sent by the GraphQL client.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// ParseRequest reads a GraphQL request body into a FixtureRequest and restores the body
+// so the request can still be sent.
+func ParseRequest(req *http.Request) (FixtureRequest, error) {
+	fr := FixtureRequest{Method: req.Method}
+	if req.Body == nil {
+		return fr, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fr, fmt.Errorf("failed to read request body: %w", err)
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var gql graphQLRequest
+	if err := json.Unmarshal(body, &gql); err != nil {
+		return fr, fmt.Errorf("request body is not a GraphQL request: %w", err)
+	}
+	fr.Query = gql.Query
+	fr.Variables = gql.Variables
+	if m := operationPattern.FindStringSubmatch(gql.Query); m != nil {
+		fr.Operation = m[1]
+	}
+	return fr, nil
+}
+
+// Matches reports whether two requests are the same call. Variables are compared
+// after a JSON round trip so recorded and live values have identical types.
+func (r FixtureRequest) Matches(other FixtureRequest) bool {
+	if r.Method != other.Method || r.Operation != other.Operation {
+		return false
+	}
+	return reflect.DeepEqual(normalize(r.Variables), normalize(other.Variables))
+}
+
+func normalize(variables map[string]interface{}) interface{} {
+	if len(variables) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return variables
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return variables
+	}
+	return out
+}
+
+// Recorder is an http.RoundTripper that forwards requests to a real transport and
+// saves each GraphQL exchange as a JSON fixture in Dir.
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// NewRecorder returns a Recorder writing fixtures to dir. A nil next uses http.DefaultTransport.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{Dir: dir, Next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	fr, err := ParseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Request: fr,
+		Response: FixtureResponse{
+			Status:  resp.StatusCode,
+			Headers: map[string]string{},
+		},
+	}
+	if json.Valid(body) {
+		fixture.Response.Body = body
+	} else {
+		fixture.Response.Text = string(body)
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			fixture.Response.Headers[name] = value
+		}
+	}
+
+	if err := r.save(fixture); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// save writes a fixture named after its operation and a hash of its variables.
+// Repeated identical requests get increasing suffixes so their order is preserved.
+func (r *Recorder) save(fixture Fixture) error {
+	if err := os.MkdirAll(r.Dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	vars, _ := json.Marshal(fixture.Request.Variables)
+	sum := sha256.Sum256(vars)
+	name := fixture.Request.Operation
+	if name == "" {
+		name = "anonymous"
+	}
+	base := fmt.Sprintf("%s-%s", name, hex.EncodeToString(sum[:4]))
+
+	for i := 0; ; i++ {
+		path := filepath.Join(r.Dir, fmt.Sprintf("%s-%d.json", base, i))
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create fixture: %w", err)
+		}
+		_, werr := file.Write(append(data, '\n'))
+		if cerr := file.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			return fmt.Errorf("failed to write fixture: %w", werr)
+		}
+		return nil
+	}
+}
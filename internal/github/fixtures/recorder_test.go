@@ -0,0 +1,54 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Github-Request-Id", "secret")
+		w.Header().Set("X-Ratelimit-Remaining", "42")
+		_, _ = w.Write([]byte("not json"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: NewRecorder(dir, nil)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"query Viewer { viewer { login } }","variables":{}}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "Viewer-*-0.json"))
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("expected a Viewer fixture, got %v (%v)", paths, err)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("fixture is not JSON: %v", err)
+	}
+
+	// Bodies that are not JSON are kept verbatim, and only the allowed headers are kept.
+	if string(fixture.Response.Content()) != "not json" || len(fixture.Response.Body) != 0 {
+		t.Errorf("recorded response = %+v, want the body as text", fixture.Response)
+	}
+	if fixture.Response.Headers["X-Ratelimit-Remaining"] != "42" || strings.Contains(string(data), "secret") {
+		t.Errorf("recorded headers = %v, want rate limit headers only", fixture.Response.Headers)
+	}
+
+	if _, err := client.Post(server.URL, "application/json", strings.NewReader("{")); err == nil {
+		t.Error("expected an error for a request that is not GraphQL")
+	}
+}
@@ -3,18 +3,69 @@ package github
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/github/gh-skyline/internal/errors"
 )
 
 // ClientInitializer is a function type for initializing GitHub clients
 type ClientInitializer func() (*Client, error)
 
+// HostClientInitializer is a function type for initializing GitHub clients for a given host
+type HostClientInitializer func(host string) (*Client, error)
+
 // InitializeGitHubClient is the default client initializer
-var InitializeGitHubClient ClientInitializer = func() (*Client, error) {
-	apiClient, err := api.DefaultGraphQLClient()
+var InitializeGitHubClient = NewClientInitializer(nil)
+
+// InitializeGitHubClientForHost is the default initializer for clients of other hosts,
+// such as the accounts merged into a skyline
+var InitializeGitHubClientForHost = NewHostClientInitializer(nil)
+
+// NewClientInitializer returns an initializer for a client authenticated through the
+// GitHub CLI whose API requests go through transport. A nil transport uses the default.
+func NewClientInitializer(transport http.RoundTripper) ClientInitializer {
+	return func() (*Client, error) {
+		host := DefaultHost()
+		if token, _ := auth.TokenForHost(host); token == "" {
+			return nil, errors.New(errors.AuthError, "no GitHub credentials found; run 'gh auth login' to authenticate", nil)
+		}
+		return newClient(host, transport)
+	}
+}
+
+// NewHostClientInitializer returns an initializer for clients of any host, each
+// authenticated with the GitHub CLI's credentials for that host, whose API requests go
+// through transport. A nil transport uses the default.
+func NewHostClientInitializer(transport http.RoundTripper) HostClientInitializer {
+	return func(host string) (*Client, error) {
+		if token, _ := auth.TokenForHost(host); token == "" {
+			return nil, errors.New(errors.AuthError, fmt.Sprintf("no GitHub credentials found for %s; run 'gh auth login --hostname %s' to authenticate", host, host), nil)
+		}
+		return newClient(host, transport)
+	}
+}
+
+// newClient creates a client for host, with a REST fallback for contribution calendars.
+func newClient(host string, transport http.RoundTripper) (*Client, error) {
+	apiClient, err := api.NewGraphQLClient(api.ClientOptions{Host: host, Transport: transport})
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to create GraphQL client", err)
+	}
+	restClient, err := api.NewRESTClient(api.ClientOptions{Host: host, Transport: transport})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
+		return nil, errors.New(errors.NetworkError, "failed to create REST client", err)
 	}
-	return NewClient(apiClient), nil
+	client := NewClientWithFallback(tracedAPI{apiClient}, tracedREST{restClient})
+	client.host = host
+	client.avatars = newAvatarClient(transport)
+	return client, nil
+}
+
+// DefaultHost returns the host the GitHub CLI talks to by default, such as github.com or
+// the GH_HOST environment variable.
+func DefaultHost() string {
+	host, _ := auth.DefaultHost()
+	return host
 }
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// ContributionIterator walks a user's contribution calendar a week at a time, over any
+// number of days. The API serves at most a year of days per calendar, so the iterator
+// fetches a year-long window at a time as it goes, joining the partial weeks where one
+// window ends and the next begins, so callers see one continuous calendar of weeks
+// starting on Sunday.
+//
+//	it := client.Contributions("octocat", start, end)
+//	for it.Next() {
+//		week := it.Week()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ContributionIterator struct {
+	client   *Client
+	username string
+	next     time.Time // First day of the next window to fetch
+	end      time.Time // Last day of the range
+
+	pending     [][]types.ContributionDay // Fetched weeks not yet yielded
+	week        []types.ContributionDay   // Week yielded by the latest call to Next
+	login       string
+	total       int
+	approximate bool
+	err         error
+}
+
+// Contributions returns an iterator over a user's contribution calendar for the days from
+// start to end, both included. Nothing is fetched until the first call to Next.
+func (c *Client) Contributions(username string, start, end time.Time) *ContributionIterator {
+	it := &ContributionIterator{client: c, username: username, next: start, end: end}
+	switch {
+	case username == "":
+		it.err = errors.New(errors.ValidationError, "username cannot be empty", nil)
+	case start.Year() < 2008:
+		it.err = errors.New(errors.ValidationError, "dates cannot be before GitHub's launch (2008)", nil)
+	case end.Before(start):
+		it.err = errors.New(errors.ValidationError, "start date cannot be after end date", nil)
+	}
+	return it
+}
+
+// Next advances to the next week of the calendar, fetching the next window when needed.
+// It returns false at the end of the range or when a fetch fails, which Err then reports.
+func (it *ContributionIterator) Next() bool {
+	it.week = nil
+	for it.err == nil {
+		// The last pending week may continue into the next window, so it is held back
+		// until that window is fetched.
+		if len(it.pending) > 1 || (len(it.pending) == 1 && it.next.After(it.end)) {
+			it.week, it.pending = it.pending[0], it.pending[1:]
+			return true
+		}
+		if it.next.After(it.end) {
+			return false
+		}
+		it.err = it.fetch()
+	}
+	return false
+}
+
+// fetch retrieves the next window of up to a year and queues its weeks.
+func (it *ContributionIterator) fetch() error {
+	start := it.next
+	end := start.AddDate(1, 0, -1)
+	if end.After(it.end) {
+		end = it.end
+	}
+	response, err := it.client.fetchWindow(it.username, start, end)
+	if err != nil {
+		return err
+	}
+	it.next = end.AddDate(0, 0, 1)
+
+	calendar := response.User.ContributionsCollection.ContributionCalendar
+	it.login = response.User.Login
+	it.total += calendar.TotalContributions
+	it.approximate = it.approximate || response.Approximate
+	for i, week := range calendar.Weeks {
+		if i == 0 && len(it.pending) > 0 && start.Weekday() != time.Sunday {
+			last := len(it.pending) - 1
+			it.pending[last] = append(it.pending[last], week.ContributionDays...)
+			continue
+		}
+		it.pending = append(it.pending, week.ContributionDays)
+	}
+	return nil
+}
+
+// Week returns the week of days the latest call to Next advanced to. Only the first and
+// last weeks of the range may hold fewer than seven days.
+func (it *ContributionIterator) Week() []types.ContributionDay {
+	return it.week
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ContributionIterator) Err() error {
+	return it.err
+}
+
+// Login returns the user's login as reported by the API, once a window has been fetched.
+func (it *ContributionIterator) Login() string {
+	return it.login
+}
+
+// Total returns the contributions in the windows fetched so far.
+func (it *ContributionIterator) Total() int {
+	return it.total
+}
+
+// Approximate reports whether any window fetched so far was approximated from the REST
+// API because GraphQL is unavailable.
+func (it *ContributionIterator) Approximate() bool {
+	return it.approximate
+}
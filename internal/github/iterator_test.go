@@ -0,0 +1,101 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// calendarAPI answers contribution queries with a generated calendar for the requested
+// days, recording each window requested.
+func calendarAPI(windows *[]string) apiFunc {
+	return func(_ string, variables map[string]interface{}, response interface{}) error {
+		from, _ := time.Parse(time.RFC3339, variables["from"].(string))
+		to, _ := time.Parse(time.RFC3339, variables["to"].(string))
+		*windows = append(*windows, from.Format(time.DateOnly)+".."+to.Format(time.DateOnly))
+		*response.(*types.ContributionsResponse) = *fixtures.GenerateContributionsResponseForDateRange("octocat", from, to.Truncate(24*time.Hour))
+		return nil
+	}
+}
+
+func TestContributionIterator(t *testing.T) {
+	var windows []string
+	client := NewClient(calendarAPI(&windows))
+
+	// Eighteen months from a Wednesday take two windows, the second starting on a Monday.
+	start, end := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)
+	it := client.Contributions("octocat", start, end)
+	var weeks [][]types.ContributionDay
+	for it.Next() {
+		weeks = append(weeks, it.Week())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if want := []string{"2023-03-01..2024-02-29", "2024-03-01..2024-08-31"}; fmt.Sprint(windows) != fmt.Sprint(want) {
+		t.Errorf("fetched windows %v, want %v", windows, want)
+	}
+
+	// The weeks run on without a gap or a split week at the window boundary.
+	day, total := start, 0
+	for i, week := range weeks {
+		if i > 0 && i < len(weeks)-1 && len(week) != 7 {
+			t.Errorf("week %d has %d days, want 7", i, len(week))
+		}
+		for _, d := range week {
+			if d.Date != day.Format(time.DateOnly) {
+				t.Fatalf("week %d holds %s, want %s", i, d.Date, day.Format(time.DateOnly))
+			}
+			if i > 0 && d == week[0] && day.Weekday() != time.Sunday {
+				t.Errorf("week %d starts on a %s", i, day.Weekday())
+			}
+			day = day.AddDate(0, 0, 1)
+			total += d.ContributionCount
+		}
+	}
+	if !day.Equal(end.AddDate(0, 0, 1)) {
+		t.Errorf("weeks end before %s, want them through %s", day.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+	if it.Total() != total || it.Login() != "octocat" || it.Approximate() {
+		t.Errorf("Total() = %d, Login() = %q, Approximate() = %v, want %d, octocat, false", it.Total(), it.Login(), it.Approximate(), total)
+	}
+
+	// FetchContributionsForDateRange drains the iterator into a single calendar.
+	response, err := client.FetchContributionsForDateRange("octocat", start, end)
+	if err != nil {
+		t.Fatalf("FetchContributionsForDateRange() error = %v", err)
+	}
+	if calendar := response.User.ContributionsCollection.ContributionCalendar; len(calendar.Weeks) != len(weeks) || calendar.TotalContributions != total {
+		t.Errorf("calendar has %d weeks and %d contributions, want %d and %d", len(calendar.Weeks), calendar.TotalContributions, len(weeks), total)
+	}
+}
+
+func TestContributionIteratorErrors(t *testing.T) {
+	var windows []string
+	client := NewClient(calendarAPI(&windows))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, it := range map[string]*ContributionIterator{
+		"no user":         client.Contributions("", start, start),
+		"before launch":   client.Contributions("octocat", time.Date(2007, 1, 1, 0, 0, 0, 0, time.UTC), start),
+		"end after start": client.Contributions("octocat", start, start.AddDate(0, 0, -1)),
+	} {
+		if it.Next() || errors.ExitCode(it.Err()) != errors.ExitValidation {
+			t.Errorf("%s: Next() ran or Err() = %v, want a validation error", name, it.Err())
+		}
+	}
+	if len(windows) != 0 {
+		t.Errorf("invalid ranges fetched %v", windows)
+	}
+
+	failing := NewClient(apiFunc(func(string, map[string]interface{}, interface{}) error {
+		return errors.New(errors.NetworkError, "network error", nil)
+	}))
+	it := failing.Contributions("octocat", start, start.AddDate(2, 0, 0))
+	if it.Next() || it.Err() == nil {
+		t.Errorf("Next() on a failing API = true or Err() = nil")
+	}
+}
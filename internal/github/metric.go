@@ -0,0 +1,167 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Metric is a daily activity count that can be rendered as a skyline.
+type Metric int
+
+// Supported metrics.
+const (
+	MetricContributions Metric = iota // Contribution calendar totals
+	MetricReviews                     // Pull request reviews
+	MetricDiscussions                 // Issue comments, discussions and discussion comments
+)
+
+// metricNames lists the flag value of each metric.
+var metricNames = map[Metric]string{
+	MetricContributions: "contributions",
+	MetricReviews:       "reviews",
+	MetricDiscussions:   "discussions",
+}
+
+// ParseMetric converts a flag value ("contributions", "reviews" or "discussions") into a
+// Metric.
+func ParseMetric(name string) (Metric, error) {
+	if name == "" {
+		return MetricContributions, nil
+	}
+	for metric, metricName := range metricNames {
+		if strings.EqualFold(name, metricName) {
+			return metric, nil
+		}
+	}
+	return MetricContributions, fmt.Errorf("unknown metric %q (expected contributions, reviews or discussions)", name)
+}
+
+// String returns the metric's flag value.
+func (m Metric) String() string {
+	return metricNames[m]
+}
+
+// ApplyMetric fetches a metric's daily counts for a year and returns a copy of the year's
+// contribution grid with each day's count replaced by them. The calendar grid supplies the
+// days, so every metric shares its layout; MetricContributions returns the grid unchanged
+// without any further requests.
+func (c *Client) ApplyMetric(weeks [][]types.ContributionDay, username string, year int, metric Metric) ([][]types.ContributionDay, error) {
+	var fetch func() (map[string]int, error)
+	switch metric {
+	case MetricContributions:
+		return weeks, nil
+	case MetricReviews:
+		fetch = func() (map[string]int, error) { return c.fetchDailyCounts(username, year, reviewConnection) }
+	case MetricDiscussions:
+		fetch = func() (map[string]int, error) { return c.fetchPostCounts(username, year) }
+	default:
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("unsupported metric %d", metric), nil)
+	}
+
+	if err := validateFetch(username, year); err != nil {
+		return nil, err
+	}
+	counts, err := fetch()
+	if err != nil {
+		return nil, classifyAPIError(fmt.Sprintf("failed to fetch %s", metric), err)
+	}
+	return types.ReplaceCounts(weeks, counts), nil
+}
+
+// postConnection is a User connection listing things the user wrote. Unlike contribution
+// connections, it cannot be limited to a year, so it is paged newest first where the API
+// can order it, stopping once the year is passed.
+type postConnection struct {
+	operation string // GraphQL operation name
+	field     string // Connection field on User
+	order     string // Field ordering the connection newest first (CREATED_AT or UPDATED_AT), or empty when it cannot be ordered
+}
+
+// postConnections are the posts counted by MetricDiscussions.
+var postConnections = []postConnection{
+	{"IssueComments", "issueComments", "UPDATED_AT"},
+	{"RepositoryDiscussions", "repositoryDiscussions", "CREATED_AT"},
+	{"RepositoryDiscussionComments", "repositoryDiscussionComments", ""},
+}
+
+// fetchPostCounts counts a user's issue comments, discussions and discussion comments per
+// day ("YYYY-MM-DD", in UTC) for a year, by when they were created. API errors are
+// returned unclassified.
+func (c *Client) fetchPostCounts(username string, year int) (map[string]int, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	counts := map[string]int{}
+	for _, conn := range postConnections {
+		if err := c.countPosts(username, conn, from, to, counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// countPosts pages through one post connection, adding the posts created in [from, to)
+// to counts.
+func (c *Client) countPosts(username string, conn postConnection, from, to time.Time, counts map[string]int) error {
+	orderBy := ""
+	if conn.order != "" {
+		orderBy = fmt.Sprintf(", orderBy: {field: %s, direction: DESC}", conn.order)
+	}
+	// GraphQL query to page through the posts, newest first when they can be ordered.
+	query := fmt.Sprintf(`
+    query %s($username: String!, $cursor: String) {
+        user(login: $username) {
+            posts: %s(first: 100, after: $cursor%s) {
+                nodes {
+                    createdAt
+                    updatedAt
+                }
+                pageInfo {
+                    hasNextPage
+                    endCursor
+                }
+            }
+        }%s
+    }`, conn.operation, conn.field, orderBy, rateLimitField)
+
+	var cursor interface{}
+	for {
+		variables := map[string]interface{}{
+			"username": username,
+			"cursor":   cursor,
+		}
+
+		if err := c.throttle(); err != nil {
+			return err
+		}
+		var response types.PostsResponse
+		if err := c.api.Do(query, variables, &response); err != nil {
+			return err
+		}
+		c.recordRateLimit(response.RateLimit)
+
+		posts := response.User.Posts
+		passed := false
+		for _, node := range posts.Nodes {
+			if !node.CreatedAt.Before(from) && node.CreatedAt.Before(to) {
+				counts[node.CreatedAt.UTC().Format("2006-01-02")]++
+			}
+			// A post is never updated before it is created, so once the ordering field
+			// falls before the year, so does every later post's creation.
+			ordered := node.CreatedAt
+			if conn.order == "UPDATED_AT" {
+				ordered = node.UpdatedAt
+			}
+			passed = conn.order != "" && ordered.Before(from)
+		}
+
+		if passed || !posts.PageInfo.HasNextPage || posts.PageInfo.EndCursor == "" {
+			return nil
+		}
+		cursor = posts.PageInfo.EndCursor
+	}
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseMetric(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Metric
+		wantErr bool
+	}{
+		{"", MetricContributions, false},
+		{"contributions", MetricContributions, false},
+		{"Reviews", MetricReviews, false},
+		{"discussions", MetricDiscussions, false},
+		{"stars", MetricContributions, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMetric(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMetric() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMetric() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := MetricReviews.String(); got != "reviews" {
+		t.Errorf("MetricReviews.String() = %q, want reviews", got)
+	}
+}
+
+func TestApplyMetric(t *testing.T) {
+	weeks := [][]types.ContributionDay{{
+		{ContributionCount: 7, Date: "2024-03-04", Breakdown: types.Breakdown{Commits: 7}},
+		{ContributionCount: 3, Date: "2024-03-05"},
+	}}
+
+	client := NewClient(&mocks.MockGitHubClient{Username: "octocat"})
+	unchanged, err := client.ApplyMetric(weeks, "octocat", 2024, MetricContributions)
+	if err != nil {
+		t.Fatalf("ApplyMetric() error = %v", err)
+	}
+	if unchanged[0][0].ContributionCount != 7 {
+		t.Errorf("contributions metric changed the grid: %v", unchanged)
+	}
+
+	reviews, err := newFixtureClient(t).ApplyMetric(weeks, "octocat", 2024, MetricReviews)
+	if err != nil {
+		t.Fatalf("ApplyMetric() error = %v", err)
+	}
+	if got := reviews[0][0]; got.ContributionCount != 1 || got.Breakdown.Total() != 0 {
+		t.Errorf("2024-03-04 = %+v, want 1 review and no breakdown", got)
+	}
+	if got := reviews[0][1].ContributionCount; got != 0 {
+		t.Errorf("2024-03-05 reviews = %d, want 0", got)
+	}
+	if weeks[0][0].ContributionCount != 7 {
+		t.Error("ApplyMetric() modified its input")
+	}
+
+	failing := NewClient(&mocks.MockGitHubClient{Err: errors.New(errors.NetworkError, "network error", nil)})
+	if _, err := failing.ApplyMetric(weeks, "octocat", 2024, MetricReviews); err == nil {
+		t.Error("expected error when the API fails")
+	}
+	if _, err := client.ApplyMetric(weeks, "", 2024, MetricReviews); err == nil {
+		t.Error("expected error for empty username")
+	}
+}
+
+func TestApplyMetricDiscussions(t *testing.T) {
+	weeks := [][]types.ContributionDay{{
+		{ContributionCount: 7, Date: "2024-03-04"},
+		{ContributionCount: 3, Date: "2024-03-05"},
+		{ContributionCount: 5, Date: "2024-03-06"},
+	}, {
+		{ContributionCount: 2, Date: "2024-12-30"},
+	}}
+
+	// The fixtures page issue comments and discussions newest first, so the first issue
+	// comment from 2023 ends its paging; a second page of that connection would fail.
+	posts, err := newFixtureClient(t).ApplyMetric(weeks, "octocat", 2024, MetricDiscussions)
+	if err != nil {
+		t.Fatalf("ApplyMetric() error = %v", err)
+	}
+	want := map[string]int{"2024-03-04": 3, "2024-03-05": 1, "2024-03-06": 0, "2024-12-30": 2}
+	for _, week := range posts {
+		for _, day := range week {
+			if day.ContributionCount != want[day.Date] {
+				t.Errorf("%s posts = %d, want %d", day.Date, day.ContributionCount, want[day.Date])
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// FetchOrgMembers returns the logins of an organization's members, paging through every
+// member. Members who keep their membership private are only listed to other members.
+func (c *Client) FetchOrgMembers(org string) ([]string, error) {
+	if org == "" {
+		return nil, errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+
+	// GraphQL query to page through an organization's members.
+	query := `
+    query OrgMembers($org: String!, $cursor: String) {
+        organization(login: $org) {
+            membersWithRole(first: 100, after: $cursor) {
+                nodes {
+                    login
+                }
+                pageInfo {
+                    hasNextPage
+                    endCursor
+                }
+            }
+        }` + rateLimitField + `
+    }`
+
+	var members []string
+	var cursor interface{}
+	for {
+		variables := map[string]interface{}{
+			"org":    org,
+			"cursor": cursor,
+		}
+
+		if err := c.throttle(); err != nil {
+			return nil, err
+		}
+		var response types.OrgMembersResponse
+		if err := c.api.Do(query, variables, &response); err != nil {
+			return nil, classifyAPIError("failed to fetch organization members", err)
+		}
+		c.recordRateLimit(response.RateLimit)
+
+		page := response.Organization.MembersWithRole
+		for _, node := range page.Nodes {
+			members = append(members, node.Login)
+		}
+
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return members, nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// rateLimitField asks a query to report the budget it leaves, so runs that send many
+	// queries, such as a --full range or paged breakdowns, can pace themselves.
+	rateLimitField = `
+        rateLimit {
+            limit
+            cost
+            remaining
+            resetAt
+        }`
+
+	// lowBudgetShare is the share of the hourly budget below which queries are spread
+	// evenly over the time left until it resets.
+	lowBudgetShare = 0.1
+
+	// maxThrottle caps the pause before a single query.
+	maxThrottle = time.Minute
+)
+
+// now and sleep are replaced in tests.
+var (
+	now   = time.Now
+	sleep = time.Sleep
+)
+
+// RateLimit returns the GraphQL API budget reported by the client's latest query,
+// reporting false until a query has reported one.
+func (c *Client) RateLimit() (types.RateLimit, bool) {
+	if c.rateLimit == nil {
+		return types.RateLimit{}, false
+	}
+	return *c.rateLimit, true
+}
+
+// recordRateLimit keeps the budget a query reported; servers with rate limiting disabled
+// report none.
+func (c *Client) recordRateLimit(limit *types.RateLimit) {
+	if limit != nil {
+		budget := *limit
+		c.rateLimit = &budget
+	}
+}
+
+// throttle runs before each query that reports its budget. Once less than lowBudgetShare
+// of it is left, it pauses so the remaining points last until the budget resets; when
+// too few are left for another query, it fails instead of waiting up to an hour.
+func (c *Client) throttle() error {
+	if c.rateLimit == nil || c.rateLimit.Limit <= 0 {
+		return nil
+	}
+	budget := *c.rateLimit
+	untilReset := budget.ResetAt.Sub(now())
+	if untilReset <= 0 {
+		return nil
+	}
+
+	cost := max(budget.Cost, 1)
+	if budget.Remaining < cost {
+		return errors.New(errors.RateLimitError, fmt.Sprintf("GitHub API rate limit exhausted until %s", budget.ResetAt.Local().Format(time.TimeOnly)), nil)
+	}
+	if float64(budget.Remaining) >= lowBudgetShare*float64(budget.Limit) {
+		return nil
+	}
+
+	pause := min(untilReset/time.Duration(budget.Remaining/cost), maxThrottle)
+	if err := githubLog.Info("GitHub API rate limit low: %d of %d points left; pausing %s", budget.Remaining, budget.Limit, pause.Round(time.Millisecond)); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	sleep(pause)
+	return nil
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestFixtureRateLimit(t *testing.T) {
+	client := newFixtureClient(t)
+	if _, ok := client.RateLimit(); ok {
+		t.Fatal("RateLimit() reported a budget before any query")
+	}
+
+	response, err := client.FetchContributions("octocat", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+	if response.RateLimit != nil {
+		t.Error("response kept its rateLimit; it belongs to the client")
+	}
+	budget, ok := client.RateLimit()
+	if !ok || budget.Limit != 5000 || budget.Remaining != 4990 || !budget.ResetAt.Equal(time.Date(2024, 12, 31, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("RateLimit() = %+v, %v, want 4990 of 5000 points", budget, ok)
+	}
+
+	// Later queries replace the budget; ones that report none leave it.
+	if _, err := client.FetchStargazers("octocat", "Hello-World"); err != nil {
+		t.Fatalf("FetchStargazers() error = %v", err)
+	}
+	if budget, _ := client.RateLimit(); budget.Remaining != 4984 {
+		t.Errorf("remaining after stargazers = %d, want 4984", budget.Remaining)
+	}
+	if _, err := client.GetAuthenticatedUser(); err != nil {
+		t.Fatalf("GetAuthenticatedUser() error = %v", err)
+	}
+	if budget, _ := client.RateLimit(); budget.Remaining != 4984 {
+		t.Errorf("remaining after viewer = %d, want it unchanged", budget.Remaining)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	clock := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var paused time.Duration
+	now, sleep = func() time.Time { return clock }, func(d time.Duration) { paused += d }
+	defer func() { now, sleep = time.Now, time.Sleep }()
+
+	tests := []struct {
+		name    string
+		budget  *types.RateLimit
+		want    time.Duration
+		wantErr bool
+	}{
+		{"unknown budget", nil, 0, false},
+		{"plenty left", &types.RateLimit{Limit: 5000, Cost: 1, Remaining: 4000, ResetAt: clock.Add(time.Hour)}, 0, false},
+		{"low budget", &types.RateLimit{Limit: 5000, Cost: 1, Remaining: 400, ResetAt: clock.Add(20 * time.Minute)}, 3 * time.Second, false},
+		{"costly queries", &types.RateLimit{Limit: 5000, Cost: 2, Remaining: 400, ResetAt: clock.Add(20 * time.Minute)}, 6 * time.Second, false},
+		{"nearly out", &types.RateLimit{Limit: 5000, Cost: 1, Remaining: 2, ResetAt: clock.Add(time.Hour)}, maxThrottle, false},
+		{"already reset", &types.RateLimit{Limit: 5000, Cost: 1, Remaining: 0, ResetAt: clock.Add(-time.Second)}, 0, false},
+		{"exhausted", &types.RateLimit{Limit: 5000, Cost: 1, Remaining: 0, ResetAt: clock.Add(time.Hour)}, 0, true},
+	}
+	for _, tt := range tests {
+		paused = 0
+		client := NewClient(nil)
+		client.recordRateLimit(tt.budget)
+		err := client.throttle()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: throttle() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && errors.ExitCode(err) != errors.ExitRateLimit {
+			t.Errorf("%s: throttle() error = %v, want a rate limit error", tt.name, err)
+		}
+		if paused != tt.want {
+			t.Errorf("%s: paused %s, want %s", tt.name, paused, tt.want)
+		}
+	}
+}
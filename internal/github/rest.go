@@ -0,0 +1,191 @@
+package github
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// restPageSize is the number of results requested per REST page, the API's maximum.
+	restPageSize = 100
+
+	// searchResultLimit is the number of results the search API returns for a query,
+	// however many match.
+	searchResultLimit = 1000
+
+	// eventPageLimit is the number of pages of recent events the API serves.
+	eventPageLimit = 3
+)
+
+// RESTClient is the subset of the REST API client used to approximate contribution
+// calendars when the GraphQL API is unavailable.
+type RESTClient interface {
+	Get(path string, response interface{}) error
+}
+
+// NewClientWithFallback creates a GitHub client that approximates contribution calendars
+// from REST endpoints when the GraphQL API is unavailable, as on GitHub Enterprise Server
+// instances that restrict it.
+func NewClientWithFallback(apiClient APIClient, restClient RESTClient) *Client {
+	return &Client{api: apiClient, rest: restClient}
+}
+
+// graphQLUnavailable reports whether err means the GraphQL API, or the contribution
+// calendar within it, is not offered by the server or to an anonymous client, rather than
+// a failed request.
+func graphQLUnavailable(err error) bool {
+	if stderrors.Is(err, errSignedOut) {
+		return true
+	}
+
+	var httpErr *api.HTTPError
+	if stderrors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusNotFound, http.StatusGone, http.StatusNotImplemented:
+			return true
+		case http.StatusForbidden:
+			return !isRateLimited(httpErr) && strings.Contains(strings.ToLower(httpErr.Message), "graphql")
+		}
+		return false
+	}
+
+	var gqlErr *api.GraphQLError
+	if stderrors.As(err, &gqlErr) {
+		for _, item := range gqlErr.Errors {
+			if code, _ := item.Extensions["code"].(string); code == "undefinedField" || code == "undefinedType" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// approximateContributions rebuilds the contribution calendar for the days from start to
+// end from REST endpoints:
+// commits and issues or pull requests opened, found by search, repositories created and
+// pull request reviews among the user's recent events. Search returns at most 1000
+// results per query and events only cover the last 90 days, and private contributions
+// are left out, so the counts are a lower bound.
+func (c *Client) approximateContributions(username string, start, end time.Time) (*types.ContributionsResponse, error) {
+	counts := map[string]int{}
+	tally := func(t time.Time) {
+		if date := t.UTC().Format(time.DateOnly); date >= start.Format(time.DateOnly) && date <= end.Format(time.DateOnly) {
+			counts[date]++
+		}
+	}
+
+	span := start.Format(time.DateOnly) + ".." + end.Format(time.DateOnly)
+	for page := 1; ; page++ {
+		var response types.SearchCommitsResponse
+		query := url.QueryEscape(fmt.Sprintf("author:%s author-date:%s", username, span))
+		if err := c.rest.Get(fmt.Sprintf("search/commits?q=%s&per_page=%d&page=%d", query, restPageSize, page), &response); err != nil {
+			return nil, classifyAPIError("failed to search commits", err)
+		}
+		for _, item := range response.Items {
+			tally(item.Commit.Author.Date)
+		}
+		if lastSearchPage(page, len(response.Items), response.TotalCount) {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		var response types.SearchIssuesResponse
+		query := url.QueryEscape(fmt.Sprintf("author:%s created:%s", username, span))
+		if err := c.rest.Get(fmt.Sprintf("search/issues?q=%s&per_page=%d&page=%d", query, restPageSize, page), &response); err != nil {
+			return nil, classifyAPIError("failed to search issues and pull requests", err)
+		}
+		for _, item := range response.Items {
+			tally(item.CreatedAt)
+		}
+		if lastSearchPage(page, len(response.Items), response.TotalCount) {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		var repositories []types.RepositoryResponse
+		if err := c.rest.Get(fmt.Sprintf("users/%s/repos?type=owner&per_page=%d&page=%d", url.PathEscape(username), restPageSize, page), &repositories); err != nil {
+			return nil, classifyAPIError("failed to list repositories", err)
+		}
+		for _, repository := range repositories {
+			if !repository.Fork {
+				tally(repository.CreatedAt)
+			}
+		}
+		if len(repositories) < restPageSize {
+			break
+		}
+	}
+
+	for page := 1; page <= eventPageLimit; page++ {
+		var events []types.EventResponse
+		if err := c.rest.Get(fmt.Sprintf("users/%s/events?per_page=%d&page=%d", url.PathEscape(username), restPageSize, page), &events); err != nil {
+			return nil, classifyAPIError("failed to list events", err)
+		}
+		for _, event := range events {
+			if event.Type == "PullRequestReviewEvent" {
+				tally(event.CreatedAt)
+			}
+		}
+		if len(events) < restPageSize {
+			break
+		}
+	}
+
+	return calendarResponse(username, start, end, counts), nil
+}
+
+// userJoinYear reads the year a user joined from their public REST profile.
+func (c *Client) userJoinYear(username string) (int, error) {
+	var response types.UserResponse
+	if err := c.rest.Get("users/"+url.PathEscape(username), &response); err != nil {
+		return 0, classifyAPIError("failed to fetch user's join date", err)
+	}
+	if response.CreatedAt.IsZero() {
+		return 0, errors.New(errors.ValidationError, "invalid join date received from GitHub API", nil)
+	}
+	return response.CreatedAt.Year(), nil
+}
+
+// lastSearchPage reports whether page, holding n of total results, is the last one the
+// search API will return.
+func lastSearchPage(page, n, total int) bool {
+	return n < restPageSize || page*restPageSize >= min(total, searchResultLimit)
+}
+
+// calendarResponse lays out daily counts ("YYYY-MM-DD") as an approximate contribution
+// calendar for the days from start to end, in weeks starting on Sunday like the GraphQL
+// calendar.
+func calendarResponse(username string, start, end time.Time, counts map[string]int) *types.ContributionsResponse {
+	response := &types.ContributionsResponse{Approximate: true}
+	response.User.Login = username
+	calendar := &response.User.ContributionsCollection.ContributionCalendar
+
+	var weeks [][]types.ContributionDay
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if len(weeks) == 0 || day.Weekday() == time.Sunday {
+			weeks = append(weeks, nil)
+		}
+		date := day.Format(time.DateOnly)
+		weeks[len(weeks)-1] = append(weeks[len(weeks)-1], types.ContributionDay{ContributionCount: counts[date], Date: date})
+		calendar.TotalContributions += counts[date]
+	}
+
+	calendar.Weeks = make([]struct {
+		ContributionDays []types.ContributionDay `json:"contributionDays"`
+	}, len(weeks))
+	for i, days := range weeks {
+		calendar.Weeks[i].ContributionDays = days
+	}
+	return response
+}
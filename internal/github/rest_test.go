@@ -0,0 +1,168 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// fakeREST answers REST requests with the JSON body registered for the longest matching
+// path prefix, recording each path requested.
+type fakeREST struct {
+	bodies    map[string]string
+	requested []string
+}
+
+func (f *fakeREST) Get(path string, response interface{}) error {
+	f.requested = append(f.requested, path)
+	match := ""
+	for prefix := range f.bodies {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return &api.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+	return json.Unmarshal([]byte(f.bodies[match]), response)
+}
+
+func TestGraphQLUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not found", &api.HTTPError{StatusCode: http.StatusNotFound}, true},
+		{"not implemented", &api.HTTPError{StatusCode: http.StatusNotImplemented}, true},
+		{"disabled", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "GraphQL API is disabled"}, true},
+		{"forbidden", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible"}, false},
+		{"rate limited", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "GraphQL rate limit exceeded", Headers: http.Header{"X-Ratelimit-Remaining": {"0"}}}, false},
+		{"server error", &api.HTTPError{StatusCode: http.StatusBadGateway}, false},
+		{"missing field", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Message: "Field 'contributionsCollection' doesn't exist on type 'User'", Extensions: map[string]interface{}{"code": "undefinedField"}}}}, true},
+		{"unknown user", &api.GraphQLError{Errors: []api.GraphQLErrorItem{{Type: "NOT_FOUND"}}}, false},
+		{"signed out", errSignedOut, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graphQLUnavailable(tt.err); got != tt.want {
+				t.Errorf("graphQLUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchContributionsRESTFallback(t *testing.T) {
+	rest := &fakeREST{bodies: map[string]string{
+		"search/commits": `{"total_count": 3, "items": [
+			{"commit": {"author": {"date": "2024-03-05T10:00:00Z"}}},
+			{"commit": {"author": {"date": "2024-03-05T18:00:00Z"}}},
+			{"commit": {"author": {"date": "2024-12-31T23:00:00-02:00"}}}]}`,
+		"search/issues":     `{"total_count": 1, "items": [{"created_at": "2024-03-05T12:00:00Z"}]}`,
+		"users/mona/repos":  `[{"fork": false, "created_at": "2024-07-01T00:00:00Z"}, {"fork": true, "created_at": "2024-07-01T00:00:00Z"}]`,
+		"users/mona/events": `[{"type": "PullRequestReviewEvent", "created_at": "2024-07-01T09:00:00Z"}, {"type": "WatchEvent", "created_at": "2024-07-01T09:00:00Z"}]`,
+	}}
+	client := NewClientWithFallback(&mocks.MockGitHubClient{Err: &api.HTTPError{StatusCode: http.StatusNotFound}}, rest)
+
+	response, err := client.FetchContributions("mona", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+	if !response.Approximate {
+		t.Error("fallback response should be marked approximate")
+	}
+	if response.User.Login != "mona" {
+		t.Errorf("login = %q, want mona", response.User.Login)
+	}
+
+	calendar := response.User.ContributionsCollection.ContributionCalendar
+	// 2024 starts on a Monday, so the first week is partial.
+	if len(calendar.Weeks) != 53 || len(calendar.Weeks[0].ContributionDays) != 6 {
+		t.Errorf("got %d weeks, the first of %d days; want 53 and 6", len(calendar.Weeks), len(calendar.Weeks[0].ContributionDays))
+	}
+	counts := map[string]int{}
+	for _, week := range calendar.Weeks {
+		for _, day := range week.ContributionDays {
+			counts[day.Date] = day.ContributionCount
+		}
+	}
+	// The last commit falls on New Year's Day in UTC, outside the year.
+	if counts["2024-03-05"] != 3 || counts["2024-07-01"] != 2 || calendar.TotalContributions != 5 {
+		t.Errorf("counts = %d on 2024-03-05 and %d on 2024-07-01, total %d; want 3, 2 and 5", counts["2024-03-05"], counts["2024-07-01"], calendar.TotalContributions)
+	}
+	if !strings.Contains(rest.requested[0], "q=author%3Amona+author-date%3A2024-01-01..2024-12-31") {
+		t.Errorf("commit search = %q", rest.requested[0])
+	}
+}
+
+func TestFetchContributionsWithoutFallback(t *testing.T) {
+	unavailable := &api.HTTPError{StatusCode: http.StatusNotFound}
+	if _, err := NewClient(&mocks.MockGitHubClient{Err: unavailable}).FetchContributions("mona", 2024); err == nil {
+		t.Error("expected an error without a REST fallback")
+	}
+
+	rest := &fakeREST{}
+	failing := &api.HTTPError{StatusCode: http.StatusBadGateway}
+	if _, err := NewClientWithFallback(&mocks.MockGitHubClient{Err: failing}, rest).FetchContributions("mona", 2024); err == nil {
+		t.Error("expected a server error to be returned")
+	}
+	if len(rest.requested) > 0 {
+		t.Errorf("server errors should not fall back to REST, requested %v", rest.requested)
+	}
+}
+
+func TestLastSearchPage(t *testing.T) {
+	tests := []struct {
+		page, n, total int
+		want           bool
+	}{
+		{1, 40, 40, true},
+		{1, 100, 250, false},
+		{3, 50, 250, true},
+		{2, 100, 200, true},
+		{10, 100, 5000, true},
+	}
+	for _, tt := range tests {
+		if got := lastSearchPage(tt.page, tt.n, tt.total); got != tt.want {
+			t.Errorf("lastSearchPage(%d, %d, %d) = %v, want %v", tt.page, tt.n, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestFetchContributionsForDateRangeRESTFallback(t *testing.T) {
+	rest := &fakeREST{bodies: map[string]string{
+		"search/commits": `{"total_count": 2, "items": [
+			{"commit": {"author": {"date": "2023-12-30T10:00:00Z"}}},
+			{"commit": {"author": {"date": "2024-03-05T10:00:00Z"}}}]}`,
+		"search/issues":     `{"total_count": 0, "items": []}`,
+		"users/mona/repos":  `[]`,
+		"users/mona/events": `[]`,
+	}}
+	client := NewClientWithFallback(&mocks.MockGitHubClient{Err: &api.HTTPError{StatusCode: http.StatusNotFound}}, rest)
+
+	start := time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	response, err := client.FetchContributionsForDateRange("mona", start, end)
+	if err != nil {
+		t.Fatalf("FetchContributionsForDateRange() error = %v", err)
+	}
+
+	calendar := response.User.ContributionsCollection.ContributionCalendar
+	weeks := calendar.Weeks
+	first, last := weeks[0].ContributionDays[0], weeks[len(weeks)-1].ContributionDays
+	if first.Date != "2023-12-15" || last[len(last)-1].Date != "2024-01-15" {
+		t.Errorf("calendar runs from %s to %s, want the window", first.Date, last[len(last)-1].Date)
+	}
+	// The March commit falls outside the window.
+	if calendar.TotalContributions != 1 {
+		t.Errorf("total = %d, want 1", calendar.TotalContributions)
+	}
+	if !strings.Contains(rest.requested[0], "2023-12-15..2024-01-15") {
+		t.Errorf("commit search = %q, want the window", rest.requested[0])
+	}
+}
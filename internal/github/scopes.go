@@ -0,0 +1,47 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// PrivateContributionsScope is the OAuth scope a token needs for the contributions the
+// user made to private repositories to count in their calendar.
+const PrivateContributionsScope = "repo"
+
+// TokenScopes returns the OAuth scopes of the GitHub CLI's token for host, as GitHub
+// reports them for a request to the REST API root. known is false for tokens that have
+// no scopes to report, such as fine-grained personal access tokens and app tokens.
+func TokenScopes(host string) (scopes []string, known bool, err error) {
+	client, err := api.NewRESTClient(api.ClientOptions{Host: host})
+	if err != nil {
+		return nil, false, classifyAPIError("failed to create REST client", err)
+	}
+	resp, err := client.Request(http.MethodGet, "", nil)
+	if err != nil {
+		return nil, false, classifyAPIError("failed to read token scopes", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scopes, known = parseScopes(resp.Header)
+	return scopes, known, nil
+}
+
+// parseScopes reads the comma-separated X-OAuth-Scopes header; known is false when the
+// response does not carry it.
+func parseScopes(header http.Header) (scopes []string, known bool) {
+	values, known := header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !known {
+		return nil, false
+	}
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, true
+}
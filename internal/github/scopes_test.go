@@ -0,0 +1,26 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantScopes []string
+		wantKnown  bool
+	}{
+		{"classic token", http.Header{"X-Oauth-Scopes": {"gist, read:org, repo"}}, []string{"gist", "read:org", "repo"}, true},
+		{"no scopes", http.Header{"X-Oauth-Scopes": {""}}, nil, true},
+		{"fine-grained token", http.Header{}, nil, false},
+	}
+	for _, tt := range tests {
+		scopes, known := parseScopes(tt.header)
+		if !reflect.DeepEqual(scopes, tt.wantScopes) || known != tt.wantKnown {
+			t.Errorf("%s: parseScopes() = %v, %v; want %v, %v", tt.name, scopes, known, tt.wantScopes, tt.wantKnown)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/logger"
+)
+
+// githubLog logs on behalf of the GitHub clients.
+var githubLog = logger.GetLogger().Component("github")
+
+// tracedAPI logs every GraphQL request it forwards, and how long it took, at trace level.
+type tracedAPI struct {
+	APIClient
+}
+
+// Do implements APIClient.
+func (t tracedAPI) Do(query string, variables map[string]interface{}, response interface{}) error {
+	start := time.Now()
+	err := t.APIClient.Do(query, variables, response)
+	if logErr := githubLog.Trace("GraphQL %s %v: %s", operationName(query), variables, outcome(start, err)); logErr != nil && err == nil {
+		return logErr
+	}
+	return err
+}
+
+// tracedREST logs every REST request it forwards, and how long it took, at trace level.
+type tracedREST struct {
+	RESTClient
+}
+
+// Get implements RESTClient.
+func (t tracedREST) Get(path string, response interface{}) error {
+	start := time.Now()
+	err := t.RESTClient.Get(path, response)
+	if logErr := githubLog.Trace("GET %s: %s", path, outcome(start, err)); logErr != nil && err == nil {
+		return logErr
+	}
+	return err
+}
+
+// operationName returns the name of a GraphQL operation, such as ContributionGraph for
+// "query ContributionGraph($username: String!) {...}", or "query" when it has none.
+func operationName(query string) string {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(query), "query")
+	if !ok {
+		return "query"
+	}
+	rest = strings.TrimSpace(rest)
+	end := strings.IndexAny(rest, "({ \n\t")
+	if end < 0 {
+		end = len(rest)
+	}
+	if end == 0 {
+		return "query"
+	}
+	return rest[:end]
+}
+
+// outcome describes how a request that began at start ended.
+func outcome(start time.Time, err error) string {
+	elapsed := time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		return "failed after " + elapsed + ": " + err.Error()
+	}
+	return "took " + elapsed
+}
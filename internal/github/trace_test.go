@@ -0,0 +1,59 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/logger"
+)
+
+// apiFunc answers GraphQL requests with a function.
+type apiFunc func(query string, variables map[string]interface{}, response interface{}) error
+
+func (f apiFunc) Do(query string, variables map[string]interface{}, response interface{}) error {
+	return f(query, variables, response)
+}
+
+func TestTracedAPI(t *testing.T) {
+	log := logger.GetLogger()
+	var file bytes.Buffer
+	log.SetFile(&file, logger.TextFormat)
+	defer log.SetFile(nil, logger.JSONFormat)
+	log.SetQuiet(true)
+	defer log.SetQuiet(false)
+
+	failure := fmt.Errorf("boom")
+	api := tracedAPI{apiFunc(func(string, map[string]interface{}, interface{}) error { return failure })}
+	query := "query ContributionGraph($username: String!) { user(login: $username) { login } }"
+
+	log.SetLevel(logger.DEBUG)
+	if err := api.Do(query, nil, nil); err != failure {
+		t.Errorf("Do() error = %v, want the client's error", err)
+	}
+	if file.Len() > 0 {
+		t.Errorf("request traced at DEBUG level: %q", file.String())
+	}
+
+	log.SetLevel(logger.TRACE)
+	defer log.SetLevel(logger.INFO)
+	if err := api.Do(query, map[string]interface{}{"username": "octocat"}, nil); err != failure {
+		t.Errorf("Do() error = %v, want the client's error", err)
+	}
+	if got := file.String(); !strings.Contains(got, "TRACE   [github] GraphQL ContributionGraph map[username:octocat]: failed after") || !strings.Contains(got, "boom") {
+		t.Errorf("trace record = %q", got)
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	for query, want := range map[string]string{
+		"\n    query Avatar($username: String!) {": "Avatar",
+		"query{viewer{login}}":                     "query",
+		"{ viewer { login } }":                     "query",
+	} {
+		if got := operationName(query); got != want {
+			t.Errorf("operationName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// TransportOptions configures how requests reach GitHub, for networks behind a proxy or
+// hosts such as GitHub Enterprise Server whose certificates a private authority signs.
+type TransportOptions struct {
+	CABundle string // PEM file of certificates trusted besides the system's; empty trusts the system's alone
+	Insecure bool   // Skip verifying the server's certificate; for testing only
+}
+
+// NewTransport returns a transport that goes through the proxy named by HTTPS_PROXY or
+// HTTP_PROXY, except for hosts listed in NO_PROXY, and verifies servers with the options'
+// certificates.
+func NewTransport(opts TransportOptions) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: opts.Insecure} //nolint:gosec // Skipping verification is the user's explicit choice
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to read CA bundle", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("CA bundle %s holds no PEM certificates", opts.CABundle), nil)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// explainTLSError adds a hint on how to trust the server to certificate verification
+// failures, and returns other errors unchanged.
+func explainTLSError(err error) error {
+	host := "the server"
+	var urlErr *url.Error
+	if stderrors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil && u.Hostname() != "" {
+			host = u.Hostname()
+		}
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case stderrors.As(err, &unknownAuthority):
+		return fmt.Errorf("%w; the certificate of %s is signed by an authority this system does not trust: pass its certificates with --ca-bundle, or check that a proxy is not intercepting the connection", err, host)
+	case stderrors.As(err, &hostname):
+		return fmt.Errorf("%w; the certificate presented is not for %s: check the host name, and the proxy settings in HTTPS_PROXY and NO_PROXY", err, host)
+	case stderrors.As(err, &invalid):
+		return fmt.Errorf("%w; the certificate of %s is not valid: check the system clock, or renew the certificate", err, host)
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"bytes"
+	"encoding/pem"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+// get requests url with transport, closing any response.
+func get(transport http.RoundTripper, url string) error {
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func TestNewTransport(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // The rejected handshake is expected
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's certificate is self-signed, so the system does not trust it.
+	transport, err := NewTransport(TransportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = explainTLSError(get(transport, server.URL))
+	if err == nil || !strings.Contains(err.Error(), "--ca-bundle") || !strings.Contains(err.Error(), "127.0.0.1") {
+		t.Errorf("untrusted certificate error = %v, want a --ca-bundle hint naming the host", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, opts := range map[string]TransportOptions{
+		"bundle":   {CABundle: bundle},
+		"insecure": {Insecure: true},
+	} {
+		transport, err := NewTransport(opts)
+		if err != nil {
+			t.Fatalf("%s: NewTransport() error = %v", name, err)
+		}
+		if err := get(transport, server.URL); err != nil {
+			t.Errorf("%s: request error = %v", name, err)
+		}
+	}
+}
+
+func TestAvatarTransport(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(encoded.Bytes())
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // The rejected handshake is expected
+	server.StartTLS()
+	defer server.Close()
+
+	// A GitHub Enterprise Server serves avatars from its own host, behind its certificate.
+	client := NewClient(&mocks.MockGitHubClient{Avatar: server.URL + "/avatars/u/1"})
+	if _, err := client.FetchAvatar("mona"); err == nil || !strings.Contains(err.Error(), "--ca-bundle") {
+		t.Errorf("FetchAvatar() error = %v, want a --ca-bundle hint", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	transport, err := NewTransport(TransportOptions{CABundle: bundle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.avatars = newAvatarClient(transport)
+	if _, err := client.FetchAvatar("mona"); err != nil {
+		t.Errorf("FetchAvatar() with the --ca-bundle transport error = %v", err)
+	}
+}
+
+func TestNewTransportInvalidBundle(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewTransport(TransportOptions{CABundle: filepath.Join(dir, "missing.pem")}); errors.ExitCode(err) != errors.ExitIO {
+		t.Errorf("missing bundle error = %v, want an IO error", err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("no certificates here"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTransport(TransportOptions{CABundle: empty}); errors.ExitCode(err) != errors.ExitValidation {
+		t.Errorf("empty bundle error = %v, want a validation error", err)
+	}
+}
+
+func TestExplainTLSErrorKeepsOtherErrors(t *testing.T) {
+	err := errors.New(errors.NetworkError, "connection refused", nil)
+	if got := explainTLSError(err); got != err {
+		t.Errorf("explainTLSError() = %v, want the error unchanged", got)
+	}
+}
@@ -0,0 +1,119 @@
+// Package i18n translates user-facing messages and formats numbers for the user's locale.
+//
+// Messages are looked up by their English text, so untranslated messages and languages
+// without a catalog fall back to English. Catalogs are JSON files in locales/, named by
+// language code, mapping each English message or format string to its translation.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+// DefaultLanguage is the language messages are written in.
+const DefaultLanguage = "en"
+
+// separators maps languages onto their thousands separator. Languages not listed use a
+// comma. Languages that group with a thin space use a plain one, which every font has.
+var separators = map[string]string{
+	"da": ".", "de": ".", "es": ".", "id": ".", "it": ".", "nl": ".", "pt": ".", "tr": ".",
+	"cs": " ", "fi": " ", "fr": " ", "nb": " ", "pl": " ", "ru": " ", "sv": " ", "uk": " ",
+}
+
+// locale is the active language, its translations and its thousands separator.
+type locale struct {
+	language  string
+	messages  map[string]string
+	separator string
+}
+
+// active is the locale used by T, Sprintf and FormatInt. It starts as the default
+// language so that output does not depend on the environment until SetLanguage is called.
+var active atomic.Pointer[locale]
+
+func init() {
+	active.Store(&locale{language: DefaultLanguage, separator: ","})
+}
+
+// Detect returns the language of the user's locale from LC_ALL, LC_MESSAGES or LANG, in
+// that order, such as "de" for "de_DE.UTF-8". The C and POSIX locales are English.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return ParseLanguage(value)
+		}
+	}
+	return DefaultLanguage
+}
+
+// ParseLanguage extracts the lower-case language code from a locale name such as
+// "pt_BR.UTF-8", "de-AT" or "sr@latin".
+func ParseLanguage(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	name, _, _ = strings.Cut(strings.ReplaceAll(name, "-", "_"), "_")
+	name = strings.ToLower(name)
+	if name == "" || name == "c" || name == "posix" {
+		return DefaultLanguage
+	}
+	return name
+}
+
+// SetLanguage switches messages and number formatting to language. A language without a
+// catalog keeps English messages but still uses its own number formatting.
+func SetLanguage(language string) error {
+	next := &locale{language: language, separator: ","}
+	if separator, ok := separators[language]; ok {
+		next.separator = separator
+	}
+	if language != DefaultLanguage {
+		data, err := catalogs.ReadFile("locales/" + language + ".json")
+		if err == nil {
+			if err := json.Unmarshal(data, &next.messages); err != nil {
+				return fmt.Errorf("invalid %s catalog: %w", language, err)
+			}
+		}
+	}
+	active.Store(next)
+	return nil
+}
+
+// Language returns the active language code.
+func Language() string {
+	return active.Load().language
+}
+
+// T returns the translation of message, or message itself when it has none.
+func T(message string) string {
+	if translated, ok := active.Load().messages[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats args with the translation of format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// FormatInt formats n with the active language's thousands separator.
+func FormatInt(n int) string {
+	separator := active.Load().separator
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + separator + digits[i:]
+	}
+	return sign + digits
+}
@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// useLanguage switches to language for the rest of the test.
+func useLanguage(t *testing.T, language string) {
+	t.Helper()
+	if err := SetLanguage(language); err != nil {
+		t.Fatalf("SetLanguage(%q) error = %v", language, err)
+	}
+	t.Cleanup(func() {
+		if err := SetLanguage(DefaultLanguage); err != nil {
+			t.Errorf("SetLanguage(%q) error = %v", DefaultLanguage, err)
+		}
+	})
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := map[string]string{
+		"de_DE.UTF-8": "de",
+		"pt_BR":       "pt",
+		"fr-CA":       "fr",
+		"sr@latin":    "sr",
+		"EN_us":       "en",
+		"C":           "en",
+		"C.UTF-8":     "en",
+		"POSIX":       "en",
+		"":            "en",
+	}
+	for name, want := range tests {
+		if got := ParseLanguage(name); got != want {
+			t.Errorf("ParseLanguage(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	if got := Detect(); got != DefaultLanguage {
+		t.Errorf("Detect() without a locale = %q, want %q", got, DefaultLanguage)
+	}
+
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	if got := Detect(); got != "fr" {
+		t.Errorf("Detect() from LANG = %q, want fr", got)
+	}
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	if got := Detect(); got != "de" {
+		t.Errorf("Detect() from LC_MESSAGES = %q, want de", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := Detect(); got != DefaultLanguage {
+		t.Errorf("Detect() from LC_ALL = %q, want %q", got, DefaultLanguage)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := T("invalid flags"); got != "invalid flags" {
+		t.Errorf("T() in English = %q, want the message itself", got)
+	}
+
+	useLanguage(t, "de")
+	if got := Language(); got != "de" {
+		t.Errorf("Language() = %q, want de", got)
+	}
+	if got := T("invalid flags"); got != "ungültige Optionen" {
+		t.Errorf("T() in German = %q, want ungültige Optionen", got)
+	}
+	if got := T("a message without a translation"); got != "a message without a translation" {
+		t.Errorf("T() of an untranslated message = %q, want the message itself", got)
+	}
+	if got := Sprintf("%s %s · %s day streak", "4.321", T("contributions"), "212"); got != "4.321 Beiträge · 212 Tage in Folge" {
+		t.Errorf("Sprintf() in German = %q", got)
+	}
+
+	// A language without a catalog keeps English messages.
+	useLanguage(t, "pt")
+	if got := T("invalid flags"); got != "invalid flags" {
+		t.Errorf("T() in Portuguese = %q, want the English message", got)
+	}
+}
+
+func TestFormatInt(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -12345: "-12,345"}
+	for n, want := range tests {
+		if got := FormatInt(n); got != want {
+			t.Errorf("FormatInt(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	for language, want := range map[string]string{"de": "1.234.567", "pt": "1.234.567", "fr": "1 234 567", "ja": "1,234,567"} {
+		useLanguage(t, language)
+		if got := FormatInt(1234567); got != want {
+			t.Errorf("FormatInt() in %s = %q, want %q", language, got, want)
+		}
+	}
+}
+
+// TestCatalogs verifies every catalog parses and keeps the format verbs of each message,
+// so a translation cannot break Sprintf.
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+	entries, err := catalogs.ReadDir("locales")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, entry := range entries {
+		data, err := catalogs.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", entry.Name(), err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			t.Fatalf("%s does not parse: %v", entry.Name(), err)
+		}
+		for message, translated := range messages {
+			if translated == "" {
+				t.Errorf("%s: %q has an empty translation", entry.Name(), message)
+			}
+			if !slices.Equal(verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q changes the format verbs of %q", entry.Name(), translated, message)
+			}
+		}
+	}
+}
@@ -1,11 +1,16 @@
-// Package logger provides thread-safe logging capabilities with different severity levels.
+// Package logger provides thread-safe logging capabilities with different severity levels,
+// optionally on behalf of a named component such as github, geometry or export.
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"sync"
+	"time"
 )
 
 // LogLevel represents the severity level of a log message
@@ -13,7 +18,8 @@ type LogLevel int
 
 // Log levels ordered by increasing severity
 const (
-	DEBUG LogLevel = iota
+	TRACE LogLevel = iota // Step-by-step detail, such as every API request
+	DEBUG
 	INFO
 	WARNING
 	ERROR
@@ -21,7 +27,55 @@ const (
 
 // String returns the string representation of a LogLevel
 func (l LogLevel) String() string {
-	return [...]string{"DEBUG", "INFO", "WARNING", "ERROR"}[l]
+	return [...]string{"TRACE", "DEBUG", "INFO", "WARNING", "ERROR"}[l]
+}
+
+// ParseLevel converts a verbosity name (error, warn, info, debug or trace) into the
+// least severe LogLevel it shows
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "error":
+		return ERROR, nil
+	case "warn", "warning":
+		return WARNING, nil
+	case "info":
+		return INFO, nil
+	case "debug":
+		return DEBUG, nil
+	case "trace":
+		return TRACE, nil
+	default:
+		return INFO, fmt.Errorf("unknown verbosity %q (expected error, warn, info, debug or trace)", name)
+	}
+}
+
+// Format selects how records are written to the log file destination
+type Format int
+
+// Supported log file formats
+const (
+	JSONFormat Format = iota // One JSON object per line
+	TextFormat               // Timestamped plain text lines
+)
+
+// ParseFormat converts a format name ("json" or "text") into a Format
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return JSONFormat, nil
+	case "text":
+		return TextFormat, nil
+	default:
+		return JSONFormat, fmt.Errorf("unknown log format %q (expected json or text)", name)
+	}
+}
+
+// record is the structured representation of a single log entry
+type record struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component,omitempty"`
+	Message   string `json:"message"`
 }
 
 // Logger provides thread-safe logging capabilities with different severity levels
@@ -31,6 +85,9 @@ type Logger struct {
 	warning *log.Logger
 	error   *log.Logger
 	level   LogLevel
+	quiet   bool      // Whether records below ERROR are kept off the console
+	file    io.Writer // Optional destination receiving a copy of every record
+	format  Format    // Format used for the file destination
 	mu      sync.Mutex
 }
 
@@ -62,8 +119,64 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
-// logf is an internal helper that handles mutex locking and level checking
-func (l *Logger) logf(level LogLevel, format string, v ...interface{}) error {
+// SetQuiet keeps records below ERROR off the console while still writing them to the
+// file destination, if any.
+// Thread-safe through mutex locking
+func (l *Logger) SetQuiet(quiet bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.quiet = quiet
+}
+
+// SetFile directs a copy of every logged record to w using the given format.
+// Passing a nil writer disables the file destination.
+// Thread-safe through mutex locking
+func (l *Logger) SetFile(w io.Writer, format Format) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file = w
+	l.format = format
+}
+
+// writeRecord writes a structured record to the file destination, if any.
+// Callers must hold the mutex.
+func (l *Logger) writeRecord(level LogLevel, component, msg string) error {
+	if l.file == nil {
+		return nil
+	}
+
+	rec := record{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     level.String(),
+		Component: component,
+		Message:   msg,
+	}
+
+	if l.format == TextFormat {
+		_, err := fmt.Fprintf(l.file, "%s %-7s %s\n", rec.Time, rec.Level, prefixed(component, rec.Message))
+		return err
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// prefixed returns msg tagged with the component that logged it, if any.
+func prefixed(component, msg string) string {
+	if component == "" {
+		return msg
+	}
+	return "[" + component + "] " + msg
+}
+
+// logf is an internal helper that handles mutex locking and level checking. Records of
+// a component are tagged with its name in the file destination and, at DEBUG and
+// below, on the console; messages meant for users keep their plain wording.
+func (l *Logger) logf(level LogLevel, component, format string, v ...interface{}) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -71,37 +184,94 @@ func (l *Logger) logf(level LogLevel, format string, v ...interface{}) error {
 		msg := fmt.Sprintf(format, v...)
 		var err error
 
-		switch level {
-		case DEBUG:
-			err = l.debug.Output(3, msg)
-		case INFO:
+		switch {
+		case l.quiet && level < ERROR:
+		case level <= DEBUG:
+			err = l.debug.Output(3, prefixed(component, msg))
+		case level == INFO:
 			err = l.info.Output(2, msg)
-		case WARNING:
+		case level == WARNING:
 			err = l.warning.Output(2, msg)
-		case ERROR:
+		case level == ERROR:
 			err = l.error.Output(2, msg)
 		}
+		if fileErr := l.writeRecord(level, component, msg); err == nil {
+			err = fileErr
+		}
 		return err
 	}
 	return nil
 }
 
+// Trace logs a trace-level message
+func (l *Logger) Trace(format string, v ...interface{}) error {
+	return l.logf(TRACE, "", format, v...)
+}
+
 // Debug logs a debug-level message
 func (l *Logger) Debug(format string, v ...interface{}) error {
-	return l.logf(DEBUG, format, v...)
+	return l.logf(DEBUG, "", format, v...)
+}
+
+// Timing logs how long a phase of the run took, as a debug-level message
+func (l *Logger) Timing(phase string, elapsed time.Duration) error {
+	return l.logf(DEBUG, "", "Timing: %s took %s", phase, elapsed.Round(time.Microsecond))
 }
 
 // Info logs an info-level message
 func (l *Logger) Info(format string, v ...interface{}) error {
-	return l.logf(INFO, format, v...)
+	return l.logf(INFO, "", format, v...)
 }
 
 // Warning logs a warning-level message
 func (l *Logger) Warning(format string, v ...interface{}) error {
-	return l.logf(WARNING, format, v...)
+	return l.logf(WARNING, "", format, v...)
 }
 
 // Error logs an error-level message
 func (l *Logger) Error(format string, v ...interface{}) error {
-	return l.logf(ERROR, format, v...)
+	return l.logf(ERROR, "", format, v...)
+}
+
+// Component logs through a Logger on behalf of one part of the program, tagging its
+// records with the part's name. It shares the Logger's level, destinations and mutex,
+// so components running concurrently never interleave their records.
+type Component struct {
+	logger *Logger
+	name   string
+}
+
+// Component returns a logger for the named part of the program, such as "github".
+func (l *Logger) Component(name string) *Component {
+	return &Component{logger: l, name: name}
+}
+
+// Trace logs a trace-level message
+func (c *Component) Trace(format string, v ...interface{}) error {
+	return c.logger.logf(TRACE, c.name, format, v...)
+}
+
+// Debug logs a debug-level message
+func (c *Component) Debug(format string, v ...interface{}) error {
+	return c.logger.logf(DEBUG, c.name, format, v...)
+}
+
+// Timing logs how long a phase of the run took, as a debug-level message
+func (c *Component) Timing(phase string, elapsed time.Duration) error {
+	return c.logger.logf(DEBUG, c.name, "Timing: %s took %s", phase, elapsed.Round(time.Microsecond))
+}
+
+// Info logs an info-level message
+func (c *Component) Info(format string, v ...interface{}) error {
+	return c.logger.logf(INFO, c.name, format, v...)
+}
+
+// Warning logs a warning-level message
+func (c *Component) Warning(format string, v ...interface{}) error {
+	return c.logger.logf(WARNING, c.name, format, v...)
+}
+
+// Error logs an error-level message
+func (c *Component) Error(format string, v ...interface{}) error {
+	return c.logger.logf(ERROR, c.name, format, v...)
 }
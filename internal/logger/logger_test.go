@@ -2,8 +2,11 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // testLogCapture helps capture log output for testing
@@ -191,6 +194,7 @@ func TestLogLevelString(t *testing.T) {
 		level    LogLevel
 		expected string
 	}{
+		{"TRACE level string", TRACE, "TRACE"},
 		{"DEBUG level string", DEBUG, "DEBUG"},
 		{"INFO level string", INFO, "INFO"},
 		{"WARNING level string", WARNING, "WARNING"},
@@ -205,3 +209,228 @@ func TestLogLevelString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"json", "json", JSONFormat, false},
+		{"text uppercase", "TEXT", TextFormat, false},
+		{"unknown", "xml", JSONFormat, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"error", ERROR, false},
+		{"warn", WARNING, false},
+		{"Info", INFO, false},
+		{"debug", DEBUG, false},
+		{"trace", TRACE, false},
+		{"verbose", INFO, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	defer logger.SetLevel(INFO)
+
+	logger.SetLevel(DEBUG)
+	if err := logger.Trace("hidden"); err != nil {
+		t.Fatalf("Trace() error = %v", err)
+	}
+	if capture.stdout.Len() > 0 {
+		t.Errorf("Trace() logged at DEBUG level: %q", capture.stdout.String())
+	}
+
+	logger.SetLevel(TRACE)
+	if err := logger.Trace("request %d", 1); err != nil {
+		t.Fatalf("Trace() error = %v", err)
+	}
+	if !strings.Contains(capture.stdout.String(), "request 1") {
+		t.Errorf("Trace() output = %q", capture.stdout.String())
+	}
+}
+
+func TestComponent(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	logger.SetLevel(DEBUG)
+	defer logger.SetLevel(INFO)
+	var file bytes.Buffer
+	logger.SetFile(&file, JSONFormat)
+	defer logger.SetFile(nil, JSONFormat)
+	github := logger.Component("github")
+
+	if err := github.Debug("fetching %d", 2024); err != nil {
+		t.Fatalf("Debug() error = %v", err)
+	}
+	if !strings.Contains(capture.stdout.String(), "[github] fetching 2024") {
+		t.Errorf("debug output = %q, want the component prefix", capture.stdout.String())
+	}
+
+	// Messages meant for users keep their wording on the console.
+	capture.stdout.Reset()
+	if err := github.Info("done"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if got := capture.stdout.String(); got != "done\n" {
+		t.Errorf("info output = %q, want the plain message", got)
+	}
+
+	decoder := json.NewDecoder(&file)
+	for _, want := range []string{"fetching 2024", "done"} {
+		var rec record
+		if err := decoder.Decode(&rec); err != nil {
+			t.Fatalf("expected a JSON record: %v", err)
+		}
+		if rec.Component != "github" || rec.Message != want {
+			t.Errorf("record = %+v, want component github and message %q", rec, want)
+		}
+	}
+}
+
+func TestComponentConcurrentWrites(t *testing.T) {
+	logger, _ := setupTestLogger(t)
+	logger.SetLevel(INFO)
+	var file bytes.Buffer
+	logger.SetFile(&file, TextFormat)
+	defer logger.SetFile(nil, JSONFormat)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"github", "geometry", "export"} {
+		component := logger.Component(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_ = component.Info("record %d", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 150 {
+		t.Fatalf("got %d records, want 150", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "INFO    [") || !strings.Contains(line, "] record ") {
+			t.Errorf("interleaved record %q", line)
+		}
+	}
+}
+
+func TestSetFile(t *testing.T) {
+	logger, _ := setupTestLogger(t)
+	logger.SetLevel(INFO)
+	defer logger.SetFile(nil, JSONFormat)
+
+	t.Run("json records", func(t *testing.T) {
+		var file bytes.Buffer
+		logger.SetFile(&file, JSONFormat)
+
+		if err := logger.Info("hello %s", "json"); err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		if err := logger.Debug("filtered"); err != nil {
+			t.Fatalf("Debug() error = %v", err)
+		}
+
+		var rec record
+		if err := json.Unmarshal(file.Bytes(), &rec); err != nil {
+			t.Fatalf("expected a single JSON record, got %q: %v", file.String(), err)
+		}
+		if rec.Level != "INFO" || rec.Message != "hello json" || rec.Time == "" {
+			t.Errorf("unexpected record: %+v", rec)
+		}
+	})
+
+	t.Run("text records", func(t *testing.T) {
+		var file bytes.Buffer
+		logger.SetFile(&file, TextFormat)
+
+		if err := logger.Warning("careful"); err != nil {
+			t.Fatalf("Warning() error = %v", err)
+		}
+		if !strings.Contains(file.String(), "WARNING careful") {
+			t.Errorf("text record = %q, want level and message", file.String())
+		}
+	})
+}
+
+func TestTiming(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	logger.SetLevel(DEBUG)
+
+	if err := logger.Timing("geometry", 1500*time.Millisecond+42); err != nil {
+		t.Fatalf("Timing() error = %v", err)
+	}
+	if !strings.Contains(capture.stdout.String(), "Timing: geometry took 1.5s") {
+		t.Errorf("Timing() output = %q", capture.stdout.String())
+	}
+
+	capture.stdout.Reset()
+	logger.SetLevel(INFO)
+	if err := logger.Timing("geometry", time.Second); err != nil {
+		t.Fatalf("Timing() error = %v", err)
+	}
+	if capture.stdout.Len() > 0 {
+		t.Errorf("Timing() logged at INFO level: %q", capture.stdout.String())
+	}
+}
+
+func TestSetQuiet(t *testing.T) {
+	logger, capture := setupTestLogger(t)
+	logger.SetLevel(INFO)
+	logger.SetQuiet(true)
+	defer logger.SetQuiet(false)
+	var file bytes.Buffer
+	logger.SetFile(&file, TextFormat)
+	defer logger.SetFile(nil, JSONFormat)
+
+	if err := logger.Info("progress"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if err := logger.Warning("careful"); err != nil {
+		t.Fatalf("Warning() error = %v", err)
+	}
+	if capture.stdout.Len() > 0 {
+		t.Errorf("quiet logger wrote to the console: %q", capture.stdout.String())
+	}
+	if !strings.Contains(file.String(), "INFO    progress") || !strings.Contains(file.String(), "WARNING careful") {
+		t.Errorf("file records = %q, want both records", file.String())
+	}
+
+	if err := logger.Error("failed"); err != nil {
+		t.Fatalf("Error() error = %v", err)
+	}
+	if !strings.Contains(capture.stderr.String(), "failed") {
+		t.Errorf("quiet logger dropped an error: %q", capture.stderr.String())
+	}
+}
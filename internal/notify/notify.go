@@ -0,0 +1,82 @@
+// Package notify posts a JSON summary of each finished run to a webhook, so chat bots
+// and automation can react to new skylines produced by batch or scheduled runs.
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Event names the kind of run a payload reports.
+const Event = "skyline.generated"
+
+// Webhook is a configured notification endpoint.
+type Webhook struct {
+	URL     string       // Absolute http or https URL the payload is posted to
+	Preview bool         // Embed the rendered preview image in the payload
+	Client  *http.Client // HTTP client used for posting
+}
+
+// Payload is the JSON body posted when a run finishes.
+type Payload struct {
+	Event       string          `json:"event"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Summary     *bundle.Summary `json:"summary"`
+	Files       []string        `json:"files"` // Paths of every file the run wrote
+
+	// Preview is the rendered preview as a PNG data URL, when the webhook asks for it.
+	Preview string `json:"preview,omitempty"`
+}
+
+// New configures a webhook posting to rawURL, which must be an absolute http or https URL.
+func New(rawURL string, preview bool) (*Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New(errors.ValidationError, "invalid --notify-url", fmt.Errorf("expected an http or https URL, got %q", rawURL))
+	}
+	return &Webhook{URL: rawURL, Preview: preview, Client: http.DefaultClient}, nil
+}
+
+// PreviewDataURL encodes a PNG image as a data URL for Payload.Preview.
+func PreviewDataURL(png []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
+
+// Send posts the payload as JSON, filling in the event name and time when unset. Any
+// response outside 2xx is an error.
+func (w *Webhook) Send(payload Payload) error {
+	if payload.Event == "" {
+		payload.Event = Event
+	}
+	if payload.GeneratedAt.IsZero() {
+		payload.GeneratedAt = time.Now().UTC()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.New(errors.GeneralError, "failed to encode notification", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to create notification request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to reach the --notify-url webhook", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New(errors.NetworkError, "the --notify-url webhook rejected the notification", fmt.Errorf("webhook returned %s", resp.Status))
+	}
+	return nil
+}
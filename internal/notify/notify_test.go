@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+func TestNew(t *testing.T) {
+	for _, rawURL := range []string{"https://hooks.example.com/skyline", "http://localhost:8080/hook"} {
+		if _, err := New(rawURL, false); err != nil {
+			t.Errorf("New(%q) error = %v", rawURL, err)
+		}
+	}
+	for _, rawURL := range []string{"", "hooks.example.com/skyline", "ftp://example.com", "https://"} {
+		if _, err := New(rawURL, false); errors.ExitCode(err) != errors.ExitValidation {
+			t.Errorf("New(%q) error = %v, want a validation error", rawURL, err)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook, err := New(server.URL, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := Payload{
+		Summary: &bundle.Summary{User: "octocat", StartYear: 2024, EndYear: 2024, Total: 42},
+		Files:   []string{"octocat-2024-github-skyline.stl"},
+		Preview: PreviewDataURL([]byte{0x89, 'P', 'N', 'G'}),
+	}
+	if err := webhook.Send(payload); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Event != Event || got.GeneratedAt.IsZero() {
+		t.Errorf("Send() posted event %q at %v, want %q and a time", got.Event, got.GeneratedAt, Event)
+	}
+	if got.Summary == nil || got.Summary.Total != 42 || len(got.Files) != 1 {
+		t.Errorf("Send() posted %+v", got)
+	}
+	if !strings.HasPrefix(got.Preview, "data:image/png;base64,") {
+		t.Errorf("Send() posted preview %q, want a PNG data URL", got.Preview)
+	}
+}
+
+func TestSendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook, err := New(server.URL, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := webhook.Send(Payload{}); errors.ExitCode(err) != errors.ExitNetwork {
+		t.Errorf("Send() error = %v, want a network error", err)
+	}
+}
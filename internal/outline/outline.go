@@ -0,0 +1,170 @@
+// Package outline generates the 2D front-elevation silhouette of a skyline and
+// writes it as vector paths (SVG or DXF) sized in millimeters for laser cutting.
+package outline
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Point is a 2D point in millimeters, with X growing to the right and Y growing upward.
+type Point struct {
+	X, Y float64
+}
+
+// Profile computes the closed silhouette of the model as seen from the front.
+// The polygon starts at the bottom-left corner of the base and runs counter-clockwise.
+// Each week's column height is the tallest day across all years, matching what is
+// visible when the printed model is viewed head-on.
+func Profile(contributions [][][]types.ContributionDay) []Point {
+	width, _ := geometry.CalculateGridDimensions(geometry.GridWeeks(contributions), len(contributions))
+	heights := geometry.ProfileHeights(contributions)
+
+	points := []Point{
+		{X: 0, Y: 0},
+		{X: width, Y: 0},
+		{X: width, Y: geometry.BaseHeight},
+	}
+
+	// Walk the skyline from right to left, emitting a step wherever the height changes.
+	current := 0.0
+	for weekIdx := len(heights) - 1; weekIdx >= 0; weekIdx-- {
+		x, _ := geometry.CellPosition(weekIdx, 0, 0)
+		right := x + geometry.CellSize
+		if heights[weekIdx] != current {
+			points = appendStep(points, right, current, heights[weekIdx])
+			current = heights[weekIdx]
+		}
+		if weekIdx == 0 && current != 0 {
+			points = appendStep(points, x, current, 0)
+		}
+	}
+
+	return append(points, Point{X: 0, Y: geometry.BaseHeight})
+}
+
+// Mirror reflects a profile left to right, for models whose weeks run right to left. The
+// result still starts at the bottom-left corner of the base and runs counter-clockwise.
+func Mirror(points []Point) []Point {
+	if len(points) == 0 {
+		return nil
+	}
+	width, _ := bounds(points)
+	mirrored := make([]Point, len(points))
+	for i, p := range points {
+		// Reflecting reverses the direction, so the order is reversed too, starting from the
+		// reflection of the bottom-right corner.
+		mirrored[(len(points)+1-i)%len(points)] = Point{X: width - p.X, Y: p.Y}
+	}
+	return mirrored
+}
+
+// appendStep adds the two corners of a vertical step at x from one height to another.
+func appendStep(points []Point, x, from, to float64) []Point {
+	return append(points,
+		Point{X: x, Y: geometry.BaseHeight + from},
+		Point{X: x, Y: geometry.BaseHeight + to},
+	)
+}
+
+// bounds returns the width and height of the polygon's bounding box.
+func bounds(points []Point) (width, height float64) {
+	for _, p := range points {
+		width = max(width, p.X)
+		height = max(height, p.Y)
+	}
+	return width, height
+}
+
+// Write writes the outline to path, choosing SVG or DXF from the file extension.
+func Write(path string, points []Point) (err error) {
+	var write func(io.Writer, []Point) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		write = WriteSVG
+	case ".dxf":
+		write = WriteDXF
+	default:
+		return errors.New(errors.ValidationError, "outline path must end in .svg or .dxf", nil)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create outline file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close outline file", cerr)
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+	if err := write(writer, points); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush outline file", err)
+	}
+	return nil
+}
+
+// WriteSVG writes the outline as a single closed SVG path.
+// The document is sized in millimeters so laser software imports it at true scale.
+func WriteSVG(w io.Writer, points []Point) error {
+	if len(points) < 3 {
+		return errors.New(errors.ValidationError, "outline needs at least three points", nil)
+	}
+
+	width, height := bounds(points)
+
+	var path strings.Builder
+	for i, p := range points {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		// SVG's Y axis points down, so flip the profile vertically.
+		fmt.Fprintf(&path, "%s%.3f %.3f ", cmd, p.X, height-p.Y)
+	}
+	path.WriteString("Z")
+
+	_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%.3fmm" height="%.3fmm" viewBox="0 0 %.3f %.3f">
+  <path d="%s" fill="none" stroke="#ff0000" stroke-width="0.1"/>
+</svg>
+`, width, height, width, height, path.String())
+	if err != nil {
+		return errors.New(errors.IOError, "failed to write SVG outline", err)
+	}
+	return nil
+}
+
+// WriteDXF writes the outline as a closed R12 POLYLINE entity in millimeter units.
+// R12 entities are used because they are understood by practically every CAD and laser tool.
+func WriteDXF(w io.Writer, points []Point) error {
+	if len(points) < 3 {
+		return errors.New(errors.ValidationError, "outline needs at least three points", nil)
+	}
+
+	var b strings.Builder
+	b.WriteString("0\nSECTION\n2\nHEADER\n9\n$INSUNITS\n70\n4\n0\nENDSEC\n")
+	b.WriteString("0\nSECTION\n2\nENTITIES\n")
+	b.WriteString("0\nPOLYLINE\n8\nOUTLINE\n66\n1\n70\n1\n")
+	for _, p := range points {
+		fmt.Fprintf(&b, "0\nVERTEX\n8\nOUTLINE\n10\n%.4f\n20\n%.4f\n30\n0.0\n", p.X, p.Y)
+	}
+	b.WriteString("0\nSEQEND\n0\nENDSEC\n0\nEOF\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errors.New(errors.IOError, "failed to write DXF outline", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package outline
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const epsilon = 0.0001
+
+func singleColumnGrid() [][][]types.ContributionDay {
+	return [][][]types.ContributionDay{{
+		{{ContributionCount: 0}},
+		{{ContributionCount: 4}, {ContributionCount: 2}},
+	}}
+}
+
+func TestProfile(t *testing.T) {
+	points := Profile(singleColumnGrid())
+
+	width, _ := geometry.CalculateMultiYearDimensions(1)
+	x, _ := geometry.CellPosition(1, 0, 0)
+	top := geometry.BaseHeight + geometry.MaxHeight
+
+	want := []Point{
+		{0, 0},
+		{width, 0},
+		{width, geometry.BaseHeight},
+		{x + geometry.CellSize, geometry.BaseHeight},
+		{x + geometry.CellSize, top},
+		{x, top},
+		{x, geometry.BaseHeight},
+		{0, geometry.BaseHeight},
+	}
+
+	if len(points) != len(want) {
+		t.Fatalf("Profile() returned %d points, want %d: %v", len(points), len(want), points)
+	}
+	for i := range want {
+		if math.Abs(points[i].X-want[i].X) > epsilon || math.Abs(points[i].Y-want[i].Y) > epsilon {
+			t.Errorf("point %d = %v, want %v", i, points[i], want[i])
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	points := Profile(singleColumnGrid())
+	width, _ := bounds(points)
+	mirrored := Mirror(points)
+
+	x, _ := geometry.CellPosition(1, 0, 0)
+	top := geometry.BaseHeight + geometry.MaxHeight
+	want := []Point{
+		{0, 0},
+		{width, 0},
+		{width, geometry.BaseHeight},
+		{width - x, geometry.BaseHeight},
+		{width - x, top},
+		{width - x - geometry.CellSize, top},
+		{width - x - geometry.CellSize, geometry.BaseHeight},
+		{0, geometry.BaseHeight},
+	}
+	if len(mirrored) != len(want) {
+		t.Fatalf("Mirror() returned %d points, want %d: %v", len(mirrored), len(want), mirrored)
+	}
+	for i := range want {
+		if math.Abs(mirrored[i].X-want[i].X) > epsilon || math.Abs(mirrored[i].Y-want[i].Y) > epsilon {
+			t.Errorf("point %d = %v, want %v", i, mirrored[i], want[i])
+		}
+	}
+	if Mirror(nil) != nil {
+		t.Error("Mirror(nil) returned points")
+	}
+}
+
+func TestProfileEmpty(t *testing.T) {
+	points := Profile([][][]types.ContributionDay{{}})
+	if len(points) != 4 {
+		t.Errorf("Profile() of empty grid returned %d points, want base rectangle of 4", len(points))
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, Profile(singleColumnGrid())); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"<svg", `width="142.500mm"`, "<path d=\"M0.000", "Z\""} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteSVG() output missing %q", want)
+		}
+	}
+}
+
+func TestWriteDXF(t *testing.T) {
+	var buf bytes.Buffer
+	points := Profile(singleColumnGrid())
+	if err := WriteDXF(&buf, points); err != nil {
+		t.Fatalf("WriteDXF() error = %v", err)
+	}
+
+	out := buf.String()
+	if got := strings.Count(out, "\nVERTEX\n"); got != len(points) {
+		t.Errorf("WriteDXF() wrote %d vertices, want %d", got, len(points))
+	}
+	if !strings.HasSuffix(out, "0\nEOF\n") {
+		t.Error("WriteDXF() output missing EOF marker")
+	}
+}
+
+func TestWriteTooFewPoints(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, []Point{{0, 0}}); err == nil {
+		t.Error("WriteSVG() expected error for degenerate outline")
+	}
+	if err := WriteDXF(&buf, nil); err == nil {
+		t.Error("WriteDXF() expected error for empty outline")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	points := Profile(singleColumnGrid())
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{"svg", "skyline.svg", false},
+		{"dxf uppercase", "skyline.DXF", false},
+		{"unsupported", "skyline.pdf", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			err := Write(path, points)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("expected outline file to exist: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package printserver sends finished models to a local print server, such as OctoPrint
+// or Moonraker, optionally slicing them to G-code first.
+package printserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Kind identifies the print server software.
+type Kind int
+
+// Supported print servers.
+const (
+	KindOctoPrint Kind = iota
+	KindMoonraker
+)
+
+// kindNames lists the flag value of each print server.
+var kindNames = map[Kind]string{
+	KindOctoPrint: "octoprint",
+	KindMoonraker: "moonraker",
+}
+
+// uploadPaths is the file upload endpoint of each print server.
+var uploadPaths = map[Kind]string{
+	KindOctoPrint: "/api/files/local",
+	KindMoonraker: "/server/files/upload",
+}
+
+// SlicerEnv is the environment variable holding the optional slicer command.
+const SlicerEnv = "SKYLINE_SLICER"
+
+// ParseKind converts a flag value ("octoprint" or "moonraker") into a Kind.
+func ParseKind(name string) (Kind, error) {
+	name = strings.ToLower(name)
+	for kind, kindName := range kindNames {
+		if name == kindName {
+			return kind, nil
+		}
+	}
+	return KindOctoPrint, fmt.Errorf("unknown print server %q (expected octoprint or moonraker)", name)
+}
+
+// String returns the print server's flag value.
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// HostEnv is the environment variable holding the print server's address.
+func (k Kind) HostEnv() string {
+	return strings.ToUpper(k.String()) + "_HOST"
+}
+
+// KeyEnv is the environment variable holding the print server's API key.
+func (k Kind) KeyEnv() string {
+	return strings.ToUpper(k.String()) + "_API_KEY"
+}
+
+// Server is a configured print server.
+type Server struct {
+	Kind   Kind
+	Host   string       // Base URL, e.g. "http://octopi.local"
+	APIKey string       // API key; Moonraker servers without authentication need none
+	Slicer string       // Optional slicer command using {input} and {output}, unless sliced with --slice
+	Client *http.Client // HTTP client used for uploads
+}
+
+// FromEnv configures a print server of the given kind from the environment: the host and
+// API key variables of the kind, and SlicerEnv. OctoPrint always requires an API key.
+func FromEnv(kind Kind) (*Server, error) {
+	server := &Server{
+		Kind:   kind,
+		Host:   os.Getenv(kind.HostEnv()),
+		APIKey: os.Getenv(kind.KeyEnv()),
+		Slicer: os.Getenv(SlicerEnv),
+		Client: http.DefaultClient,
+	}
+	if server.Host == "" {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%s must be set to send to %s", kind.HostEnv(), kind), nil)
+	}
+	if !strings.Contains(server.Host, "://") {
+		server.Host = "http://" + server.Host
+	}
+	if kind == KindOctoPrint && server.APIKey == "" {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%s must be set to send to %s", kind.KeyEnv(), kind), nil)
+	}
+	if server.Slicer != "" && (!strings.Contains(server.Slicer, "{input}") || !strings.Contains(server.Slicer, "{output}")) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%s must contain {input} and {output}", SlicerEnv), nil)
+	}
+	return server, nil
+}
+
+// Send uploads the model at path to the print server. A model already sliced with --slice
+// has sliced set to the slicer's estimate, and the G-code it names is uploaded; otherwise
+// the model is sliced first when a slicer command is configured. It returns the name of
+// the uploaded file.
+func (s *Server) Send(path string, sliced *types.PrintEstimate) (string, error) {
+	switch {
+	case sliced != nil:
+		path = sliced.GCode
+	case s.Slicer != "":
+		gcodePath, err := s.slice(path)
+		if err != nil {
+			return "", err
+		}
+		path = gcodePath
+	}
+	if err := s.upload(path); err != nil {
+		return "", err
+	}
+	return filepath.Base(path), nil
+}
+
+// slice runs the slicer command on an STL and returns the path of the G-code it wrote.
+// The command is split on whitespace and run without a shell.
+func (s *Server) slice(stlPath string) (string, error) {
+	gcodePath := strings.TrimSuffix(stlPath, filepath.Ext(stlPath)) + ".gcode"
+	placeholders := strings.NewReplacer("{input}", stlPath, "{output}", gcodePath)
+	args := strings.Fields(s.Slicer)
+	for i, arg := range args {
+		args[i] = placeholders.Replace(arg)
+	}
+
+	output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return "", errors.New(errors.GeneralError, "slicer failed", fmt.Errorf("%w: %s", err, bytes.TrimSpace(output)))
+	}
+	if _, err := os.Stat(gcodePath); err != nil {
+		return "", errors.New(errors.IOError, "slicer did not write G-code", err)
+	}
+	return gcodePath, nil
+}
+
+// upload posts a file to the print server's upload endpoint as multipart form data.
+func (s *Server) upload(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.New(errors.IOError, fmt.Sprintf("failed to open %s", path), err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return errors.New(errors.IOError, "failed to build upload form", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return errors.New(errors.IOError, fmt.Sprintf("failed to read %s", path), err)
+	}
+	if err := writer.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to build upload form", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(s.Host, "/")+uploadPaths[s.Kind], &body)
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to create upload request", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if s.APIKey != "" {
+		req.Header.Set("X-Api-Key", s.APIKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to reach %s", s.Kind), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errors.New(errors.AuthError, fmt.Sprintf("%s rejected the API key", s.Kind), fmt.Errorf("upload returned %s", resp.Status))
+	case resp.StatusCode >= 400:
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to upload to %s", s.Kind), fmt.Errorf("upload returned %s", resp.Status))
+	}
+	return nil
+}
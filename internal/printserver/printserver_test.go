@@ -0,0 +1,170 @@
+package printserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Kind
+		wantErr bool
+	}{
+		{"octoprint", KindOctoPrint, false},
+		{"Moonraker", KindMoonraker, false},
+		{"mainsail", KindOctoPrint, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseKind(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("OCTOPRINT_HOST", "octopi.local")
+	t.Setenv("OCTOPRINT_API_KEY", "")
+	t.Setenv("MOONRAKER_HOST", "")
+	t.Setenv("MOONRAKER_API_KEY", "")
+	t.Setenv(SlicerEnv, "")
+
+	if _, err := FromEnv(KindOctoPrint); err == nil {
+		t.Error("FromEnv() expected error without an OctoPrint API key")
+	}
+	if _, err := FromEnv(KindMoonraker); err == nil {
+		t.Error("FromEnv() expected error without a Moonraker host")
+	}
+
+	t.Setenv("OCTOPRINT_API_KEY", "key")
+	server, err := FromEnv(KindOctoPrint)
+	if err != nil {
+		t.Fatalf("FromEnv() error = %v", err)
+	}
+	if server.Host != "http://octopi.local" || server.APIKey != "key" {
+		t.Errorf("FromEnv() = %+v", server)
+	}
+
+	// Moonraker servers often run without authentication.
+	t.Setenv("MOONRAKER_HOST", "https://voron.local:7125")
+	if server, err = FromEnv(KindMoonraker); err != nil || server.Host != "https://voron.local:7125" {
+		t.Errorf("FromEnv() = %+v, %v", server, err)
+	}
+
+	t.Setenv(SlicerEnv, "prusa-slicer --export-gcode {input}")
+	if _, err := FromEnv(KindMoonraker); err == nil {
+		t.Error("FromEnv() expected error for a slicer command without {output}")
+	}
+}
+
+// fakeServer accepts uploads on path and records the uploaded file.
+func fakeServer(t *testing.T, path string) (*httptest.Server, *map[string]string) {
+	t.Helper()
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Api-Key") == "wrong" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("reading upload: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		uploaded[header.Filename] = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	return server, &uploaded
+}
+
+func writeModel(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "skyline.stl")
+	if err := os.WriteFile(path, []byte("solid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSend(t *testing.T) {
+	for kind, path := range uploadPaths {
+		t.Run(kind.String(), func(t *testing.T) {
+			fake, uploaded := fakeServer(t, path)
+			server := &Server{Kind: kind, Host: fake.URL, APIKey: "key", Client: fake.Client()}
+
+			name, err := server.Send(writeModel(t), nil)
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if name != "skyline.stl" || (*uploaded)["skyline.stl"] != "solid" {
+				t.Errorf("Send() = %q, uploaded %v", name, *uploaded)
+			}
+
+			server.APIKey = "wrong"
+			if _, err := server.Send(writeModel(t), nil); errors.ExitCode(err) != errors.ExitAuth {
+				t.Errorf("Send() error = %v, want an auth error", err)
+			}
+		})
+	}
+}
+
+func TestSendSliced(t *testing.T) {
+	if _, err := exec.LookPath("cp"); err != nil {
+		t.Skip("cp is not available")
+	}
+	fake, uploaded := fakeServer(t, uploadPaths[KindOctoPrint])
+	server := &Server{Kind: KindOctoPrint, Host: fake.URL, APIKey: "key", Slicer: "cp {input} {output}", Client: fake.Client()}
+
+	name, err := server.Send(writeModel(t), nil)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if name != "skyline.gcode" || (*uploaded)["skyline.gcode"] != "solid" {
+		t.Errorf("Send() = %q, uploaded %v", name, *uploaded)
+	}
+
+	server.Slicer = "false {input} {output}"
+	if _, err := server.Send(writeModel(t), nil); err == nil {
+		t.Error("Send() expected error when the slicer fails")
+	}
+}
+
+func TestSendSlicedWithSlice(t *testing.T) {
+	fake, uploaded := fakeServer(t, uploadPaths[KindMoonraker])
+	// The slicer command is left unused: the model was already sliced with --slice.
+	server := &Server{Kind: KindMoonraker, Host: fake.URL, Slicer: "false {input} {output}", Client: fake.Client()}
+	model := writeModel(t)
+	gcode := filepath.Join(filepath.Dir(model), "skyline.gcode")
+	if err := os.WriteFile(gcode, []byte("G28"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := server.Send(model, &types.PrintEstimate{GCode: gcode})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if name != "skyline.gcode" || (*uploaded)["skyline.gcode"] != "G28" {
+		t.Errorf("Send() = %q, uploaded %v", name, *uploaded)
+	}
+}
@@ -0,0 +1,94 @@
+// Package profiling captures CPU, heap and execution trace profiles of a run, so the cost
+// of changes to the geometry pipeline can be measured with go tool pprof and go tool trace.
+package profiling
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Options selects the profiles to write; empty paths skip a profile.
+type Options struct {
+	CPUProfile string // pprof CPU profile of the whole run
+	MemProfile string // pprof heap profile taken when profiling stops
+	Trace      string // Execution trace of the whole run
+}
+
+// Start begins the CPU profile and execution trace requested in opts. The returned stop
+// function ends them and writes the heap profile; it must be called once the run is done.
+func Start(opts Options) (stop func() error, err error) {
+	var stops []func() error
+	stopAll := func() error {
+		var first error
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	if opts.CPUProfile != "" {
+		file, err := os.Create(opts.CPUProfile)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to create CPU profile", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			_ = file.Close()
+			return nil, errors.New(errors.IOError, "failed to start CPU profile", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return closeProfile(file, "CPU profile")
+		})
+	}
+
+	if opts.Trace != "" {
+		file, err := os.Create(opts.Trace)
+		if err != nil {
+			_ = stopAll()
+			return nil, errors.New(errors.IOError, "failed to create trace", err)
+		}
+		if err := trace.Start(file); err != nil {
+			_ = file.Close()
+			_ = stopAll()
+			return nil, errors.New(errors.IOError, "failed to start trace", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return closeProfile(file, "trace")
+		})
+	}
+
+	if opts.MemProfile != "" {
+		stops = append(stops, func() error { return writeHeapProfile(opts.MemProfile) })
+	}
+	return stopAll, nil
+}
+
+// writeHeapProfile writes a heap profile after a garbage collection, so it reflects the
+// memory still in use.
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create memory profile", err)
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		_ = file.Close()
+		return errors.New(errors.IOError, "failed to write memory profile", err)
+	}
+	return closeProfile(file, "memory profile")
+}
+
+// closeProfile closes a profile file, reporting failures as IO errors.
+func closeProfile(file *os.File, name string) error {
+	if err := file.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to close "+name, err)
+	}
+	return nil
+}
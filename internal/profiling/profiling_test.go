@@ -0,0 +1,59 @@
+package profiling
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{
+		CPUProfile: filepath.Join(dir, "cpu.pprof"),
+		MemProfile: filepath.Join(dir, "mem.pprof"),
+		Trace:      filepath.Join(dir, "trace.out"),
+	}
+
+	stop, err := Start(opts)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+
+	for _, path := range []string{opts.CPUProfile, opts.MemProfile, opts.Trace} {
+		info, err := os.Stat(path)
+		if err != nil || info.Size() == 0 {
+			t.Errorf("expected %s to be written: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestStartNothing(t *testing.T) {
+	stop, err := Start(Options{})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Errorf("stop() error = %v", err)
+	}
+}
+
+func TestStartErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing", "profile")
+	if _, err := Start(Options{CPUProfile: missing}); err == nil {
+		t.Error("Start() expected error for an unwritable CPU profile")
+	}
+	// A failed trace stops the CPU profile it already started, so profiling can restart.
+	if _, err := Start(Options{CPUProfile: filepath.Join(t.TempDir(), "cpu.pprof"), Trace: missing}); err == nil {
+		t.Error("Start() expected error for an unwritable trace")
+	}
+	stop, err := Start(Options{CPUProfile: filepath.Join(t.TempDir(), "cpu.pprof"), MemProfile: missing})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stop(); err == nil {
+		t.Error("stop() expected error for an unwritable memory profile")
+	}
+}
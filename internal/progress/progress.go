@@ -0,0 +1,41 @@
+// Package progress defines hooks that let front-ends follow a skyline generation run
+// without parsing log output.
+package progress
+
+// Observer receives events as a skyline is generated. Methods are called synchronously
+// from the generating goroutine, so implementations should return quickly.
+type Observer interface {
+	// OnFetchStart is called once before any contribution data is fetched.
+	OnFetchStart(user string, startYear, endYear int)
+	// OnYearFetched is called after each year's contributions are available.
+	// cached reports whether the year was loaded from the cache instead of the API.
+	OnYearFetched(year int, cached bool)
+	// OnGeometryProgress is called as each model component finishes generating.
+	// done counts the finished components out of total.
+	OnGeometryProgress(component string, done, total int)
+	// OnWriteComplete is called after each output file has been written.
+	OnWriteComplete(path string)
+}
+
+// Nop is an Observer that ignores every event.
+type Nop struct{}
+
+// OnFetchStart implements Observer.
+func (Nop) OnFetchStart(string, int, int) {}
+
+// OnYearFetched implements Observer.
+func (Nop) OnYearFetched(int, bool) {}
+
+// OnGeometryProgress implements Observer.
+func (Nop) OnGeometryProgress(string, int, int) {}
+
+// OnWriteComplete implements Observer.
+func (Nop) OnWriteComplete(string) {}
+
+// OrNop returns o, or a Nop observer when o is nil.
+func OrNop(o Observer) Observer {
+	if o == nil {
+		return Nop{}
+	}
+	return o
+}
@@ -0,0 +1,33 @@
+package progress
+
+import "testing"
+
+type recorder struct {
+	Nop
+	writes []string
+}
+
+func (r *recorder) OnWriteComplete(path string) {
+	r.writes = append(r.writes, path)
+}
+
+func TestOrNop(t *testing.T) {
+	if _, ok := OrNop(nil).(Nop); !ok {
+		t.Error("OrNop(nil) should return a Nop observer")
+	}
+
+	rec := &recorder{}
+	observer := OrNop(rec)
+	if observer != rec {
+		t.Error("OrNop() should return a non-nil observer unchanged")
+	}
+
+	// Embedding Nop lets observers implement only the events they care about.
+	observer.OnFetchStart("octocat", 2020, 2024)
+	observer.OnYearFetched(2020, false)
+	observer.OnGeometryProgress("base", 1, 4)
+	observer.OnWriteComplete("skyline.stl")
+	if len(rec.writes) != 1 || rec.writes[0] != "skyline.stl" {
+		t.Errorf("writes = %v, want [skyline.stl]", rec.writes)
+	}
+}
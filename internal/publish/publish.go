@@ -0,0 +1,70 @@
+// Package publish uploads finished models to model-sharing services.
+package publish
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Service identifies a model-sharing service.
+type Service int
+
+// Supported services.
+const (
+	ServiceThingiverse Service = iota
+)
+
+// serviceNames lists the flag value of each service.
+var serviceNames = map[Service]string{
+	ServiceThingiverse: "thingiverse",
+}
+
+// ParseService converts a flag value into a Service.
+func ParseService(name string) (Service, error) {
+	name = strings.ToLower(name)
+	for service, serviceName := range serviceNames {
+		if name == serviceName {
+			return service, nil
+		}
+	}
+	if name == "printables" {
+		return ServiceThingiverse, fmt.Errorf("printables has no public upload API (expected thingiverse)")
+	}
+	return ServiceThingiverse, fmt.Errorf("unknown service %q (expected thingiverse)", name)
+}
+
+// String returns the service's flag value.
+func (s Service) String() string {
+	return serviceNames[s]
+}
+
+// TokenEnv is the environment variable holding the service's API token.
+func (s Service) TokenEnv() string {
+	return strings.ToUpper(s.String()) + "_TOKEN"
+}
+
+// Model is a finished model and its listing details.
+type Model struct {
+	Title       string   // Listing title
+	Description string   // Listing description
+	Tags        []string // Listing tags
+	Files       []string // STL files to upload
+	Images      []string // Preview images to upload
+	Draft       bool     // Leave the listing unpublished
+}
+
+// Publisher uploads models to a service.
+type Publisher interface {
+	// Publish creates a listing for the model, uploads its files and returns the listing's URL.
+	Publish(model Model) (string, error)
+}
+
+// publishers creates an authenticated Publisher for each service.
+var publishers = map[Service]func(token string) Publisher{
+	ServiceThingiverse: func(token string) Publisher { return NewThingiverse(token) },
+}
+
+// NewPublisher returns a Publisher for the service, authenticated with token.
+func NewPublisher(service Service, token string) Publisher {
+	return publishers[service](token)
+}
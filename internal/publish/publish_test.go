@@ -0,0 +1,44 @@
+package publish
+
+import "testing"
+
+func TestParseService(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Service
+		wantErr bool
+	}{
+		{"thingiverse", ServiceThingiverse, false},
+		{"Thingiverse", ServiceThingiverse, false},
+		{"printables", ServiceThingiverse, true},
+		{"myminifactory", ServiceThingiverse, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseService(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseService() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceTokenEnv(t *testing.T) {
+	if got := ServiceThingiverse.TokenEnv(); got != "THINGIVERSE_TOKEN" {
+		t.Errorf("TokenEnv() = %q, want THINGIVERSE_TOKEN", got)
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	publisher, ok := NewPublisher(ServiceThingiverse, "token").(*Thingiverse)
+	if !ok {
+		t.Fatal("NewPublisher() did not return a Thingiverse publisher")
+	}
+	if publisher.Token != "token" || publisher.BaseURL != ThingiverseAPI {
+		t.Errorf("NewPublisher() = %+v", publisher)
+	}
+}
@@ -0,0 +1,190 @@
+package publish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+const (
+	// ThingiverseAPI is the base URL of the Thingiverse REST API.
+	ThingiverseAPI = "https://api.thingiverse.com"
+
+	thingiverseLicense  = "cc"    // Creative Commons - Attribution
+	thingiverseCategory = "Other" // Skylines have no dedicated category
+)
+
+// Thingiverse publishes models as things on Thingiverse.
+type Thingiverse struct {
+	Token   string       // App or user access token
+	BaseURL string       // API base URL; tests point it at a local server
+	Client  *http.Client // HTTP client used for every request
+}
+
+// NewThingiverse returns a Thingiverse publisher for the public API.
+func NewThingiverse(token string) *Thingiverse {
+	return &Thingiverse{Token: token, BaseURL: ThingiverseAPI, Client: http.DefaultClient}
+}
+
+// thing is the part of a Thingiverse thing returned on creation.
+type thing struct {
+	ID        int    `json:"id"`
+	PublicURL string `json:"public_url"`
+}
+
+// uploadForm is the pre-signed storage form a file is posted to.
+type uploadForm struct {
+	Action string            `json:"action"`
+	Fields map[string]string `json:"fields"`
+}
+
+// Publish creates a thing, uploads the model's files and images to it and, unless the
+// model is a draft, publishes it.
+func (t *Thingiverse) Publish(model Model) (string, error) {
+	var created thing
+	err := t.call(http.MethodPost, "/things/", map[string]any{
+		"name":        model.Title,
+		"description": model.Description,
+		"tags":        model.Tags,
+		"license":     thingiverseLicense,
+		"category":    thingiverseCategory,
+		"is_wip":      model.Draft,
+	}, &created)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create thing")
+	}
+
+	for _, path := range append(append([]string(nil), model.Files...), model.Images...) {
+		if err := t.upload(created.ID, path); err != nil {
+			return "", err
+		}
+	}
+
+	if !model.Draft {
+		if err := t.call(http.MethodPost, fmt.Sprintf("/things/%d/publish", created.ID), nil, nil); err != nil {
+			return "", errors.Wrap(err, "failed to publish thing")
+		}
+	}
+	return created.PublicURL, nil
+}
+
+// upload adds a file to a thing: it requests an upload form, posts the file to storage
+// and finalizes the upload.
+func (t *Thingiverse) upload(thingID int, path string) error {
+	var form uploadForm
+	if err := t.call(http.MethodPost, fmt.Sprintf("/things/%d/files", thingID), map[string]string{"filename": filepath.Base(path)}, &form); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to prepare upload of %s", path))
+	}
+
+	body, contentType, err := multipartFile(form.Fields, path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, form.Action, body)
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to create upload request", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	// Storage answers with a redirect to the finalize URL, which is called explicitly below.
+	client := *t.Client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to upload %s", path), err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to upload %s", path), fmt.Errorf("storage returned %s", resp.Status))
+	}
+
+	finalize := form.Fields["success_action_redirect"]
+	if finalize == "" {
+		return nil
+	}
+	if err := t.call(http.MethodPost, finalize, nil, nil); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to finalize upload of %s", path))
+	}
+	return nil
+}
+
+// multipartFile builds a storage upload body: the form fields followed by the file.
+func multipartFile(fields map[string]string, path string) (io.Reader, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", errors.New(errors.IOError, fmt.Sprintf("failed to open %s", path), err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", errors.New(errors.IOError, "failed to build upload form", err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, "", errors.New(errors.IOError, "failed to build upload form", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, "", errors.New(errors.IOError, fmt.Sprintf("failed to read %s", path), err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", errors.New(errors.IOError, "failed to build upload form", err)
+	}
+	return &body, writer.FormDataContentType(), nil
+}
+
+// call sends an authenticated JSON request to the API and decodes the response into out.
+// Paths starting with a scheme are used as-is.
+func (t *Thingiverse) call(method, path string, in, out any) error {
+	url := path
+	if !strings.Contains(path, "://") {
+		url = strings.TrimSuffix(t.BaseURL, "/") + path
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.New(errors.GeneralError, "failed to encode request", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return errors.New(errors.NetworkError, "failed to create request", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return errors.New(errors.NetworkError, "request failed", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errors.New(errors.AuthError, "Thingiverse rejected the token", fmt.Errorf("%s %s returned %s", method, path, resp.Status))
+	case resp.StatusCode >= 400:
+		return errors.New(errors.NetworkError, "Thingiverse API error", fmt.Errorf("%s %s returned %s", method, path, resp.Status))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return errors.New(errors.NetworkError, "failed to decode response", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// fakeThingiverse records the uploads made to an in-process Thingiverse API.
+type fakeThingiverse struct {
+	created   map[string]any
+	uploaded  map[string]string // File name to content
+	finalized int
+	published bool
+}
+
+func newFakeThingiverse(t *testing.T) (*fakeThingiverse, *httptest.Server) {
+	t.Helper()
+	fake := &fakeThingiverse{uploaded: map[string]string{}}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("POST /things/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&fake.created); err != nil {
+			t.Errorf("decoding thing: %v", err)
+		}
+		_, _ = fmt.Fprint(w, `{"id": 42, "public_url": "https://www.thingiverse.com/thing:42"}`)
+	})
+	mux.HandleFunc("POST /things/42/files", func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Filename string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = fmt.Fprintf(w, `{"action": %q, "fields": {"key": %q, "success_action_redirect": %q}}`,
+			server.URL+"/storage", req.Filename, server.URL+"/finalize")
+	})
+	mux.HandleFunc("POST /storage", func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("reading upload: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		fake.uploaded[r.FormValue("key")] = string(data)
+		http.Redirect(w, r, server.URL+"/finalize", http.StatusSeeOther)
+	})
+	mux.HandleFunc("POST /finalize", func(http.ResponseWriter, *http.Request) { fake.finalized++ })
+	mux.HandleFunc("POST /things/42/publish", func(http.ResponseWriter, *http.Request) { fake.published = true })
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func TestThingiversePublish(t *testing.T) {
+	fake, server := newFakeThingiverse(t)
+	dir := t.TempDir()
+	stlPath := filepath.Join(dir, "mona-2024.stl")
+	previewPath := filepath.Join(dir, "mona-2024-preview.png")
+	for path, content := range map[string]string{stlPath: "solid", previewPath: "png"} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publisher := &Thingiverse{Token: "secret", BaseURL: server.URL, Client: server.Client()}
+	url, err := publisher.Publish(Model{
+		Title:       "mona's GitHub Skyline 2024",
+		Description: "A year of contributions",
+		Tags:        []string{"github", "skyline"},
+		Files:       []string{stlPath},
+		Images:      []string{previewPath},
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://www.thingiverse.com/thing:42" {
+		t.Errorf("Publish() = %q", url)
+	}
+	if fake.created["name"] != "mona's GitHub Skyline 2024" || fake.created["is_wip"] != false {
+		t.Errorf("created thing = %v", fake.created)
+	}
+	want := map[string]string{"mona-2024.stl": "solid", "mona-2024-preview.png": "png"}
+	for name, content := range want {
+		if fake.uploaded[name] != content {
+			t.Errorf("uploaded %s = %q, want %q", name, fake.uploaded[name], content)
+		}
+	}
+	if fake.finalized != 2 || !fake.published {
+		t.Errorf("finalized %d uploads, published %v; want 2, true", fake.finalized, fake.published)
+	}
+}
+
+func TestThingiversePublishDraft(t *testing.T) {
+	fake, server := newFakeThingiverse(t)
+	publisher := &Thingiverse{Token: "secret", BaseURL: server.URL, Client: server.Client()}
+	if _, err := publisher.Publish(Model{Title: "draft", Draft: true}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if fake.published || fake.created["is_wip"] != true {
+		t.Errorf("draft was published: %v", fake.created)
+	}
+}
+
+func TestThingiversePublishErrors(t *testing.T) {
+	_, server := newFakeThingiverse(t)
+
+	publisher := &Thingiverse{Token: "wrong", BaseURL: server.URL, Client: server.Client()}
+	if _, err := publisher.Publish(Model{Title: "rejected"}); errors.ExitCode(err) != errors.ExitAuth {
+		t.Errorf("Publish() error = %v, want an auth error", err)
+	}
+
+	publisher.Token = "secret"
+	if _, err := publisher.Publish(Model{Title: "missing", Files: []string{filepath.Join(t.TempDir(), "missing.stl")}}); err == nil {
+		t.Error("Publish() expected error for a missing file")
+	}
+}
@@ -0,0 +1,76 @@
+// Package report writes a skyline as a single, self-contained HTML page: the model with
+// an interactive viewer, the contribution heatmap, the statistics and the ASCII previews,
+// all embedded in the file so it can be shared on its own.
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"html/template"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/export"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+//go:embed report.html.tmpl
+var pageSource string
+
+// page lays out the report; its viewer draws the embedded GLB with WebGL, so the report
+// needs no network access or scripts from elsewhere.
+var page = template.Must(template.New("report").Parse(pageSource))
+
+// Report is everything a report shows.
+type Report struct {
+	Summary  *bundle.Summary // Totals, streak, achievements and estimates
+	Label    string          // Range of the model, such as "2020-24" or a date window
+	Heatmap  image.Image     // Contribution calendar; nil leaves it out
+	Previews []string        // ASCII preview of each year, in order
+	Model    *types.Model    // Model shown in the viewer and offered for download as GLB
+}
+
+// Write renders the report to path as HTML.
+func Write(path string, r Report) error {
+	if path == "" {
+		return errors.New(errors.ValidationError, "report path cannot be empty", nil)
+	}
+	if r.Summary == nil || r.Model == nil {
+		return errors.New(errors.ValidationError, "report needs a summary and a model", nil)
+	}
+
+	glb, err := export.Lookup("glb")
+	if err != nil {
+		return err
+	}
+	var model bytes.Buffer
+	if err := glb.Write(r.Model, &model); err != nil {
+		return err
+	}
+	var heatmap template.URL
+	if r.Heatmap != nil {
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, r.Heatmap); err != nil {
+			return errors.New(errors.GeneralError, "failed to encode heatmap", err)
+		}
+		heatmap = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(encoded.Bytes())) //nolint:gosec // The URL holds base64 alone
+	}
+
+	var out bytes.Buffer
+	err = page.Execute(&out, struct {
+		Report
+		HeatmapURL template.URL
+		GLB        string
+	}{r, heatmap, base64.StdEncoding.EncodeToString(model.Bytes())})
+	if err != nil {
+		return errors.New(errors.GeneralError, "failed to render report", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		return errors.New(errors.IOError, "failed to write report", err)
+	}
+	return nil
+}
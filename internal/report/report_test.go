@@ -0,0 +1,66 @@
+package report
+
+import (
+	"encoding/base64"
+	"image"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/bundle"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestWrite(t *testing.T) {
+	model := &types.Model{
+		Components: []types.Component{{Name: "base", Material: types.MaterialBase, Triangles: []types.Triangle{{V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}}}}},
+		Metadata:   map[string]string{"user": "mona"},
+	}
+	path := filepath.Join(t.TempDir(), "report.html")
+	err := Write(path, Report{
+		Summary:  &bundle.Summary{User: "mona", Total: 1234, LongestStreak: 12, Achievements: []string{"Streak <7>"}, Years: []bundle.YearSummary{{Year: 2024, Total: 1234}}},
+		Label:    "2024",
+		Heatmap:  image.NewRGBA(image.Rect(0, 0, 4, 4)),
+		Previews: []string{"mona <2024> ▁▂▃"},
+		Model:    model,
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		"<title>mona's GitHub Skyline 2024</title>",
+		`src="data:image/png;base64,`,
+		"<td>1234</td>",
+		"Streak &lt;7&gt;",
+		"<pre>mona &lt;2024&gt; ▁▂▃</pre>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+
+	// The model is embedded as GLB, so the report needs nothing else to show it.
+	match := regexp.MustCompile(`data-model="([A-Za-z0-9+/=]+)"`).FindStringSubmatch(html)
+	if match == nil {
+		t.Fatal("report does not embed the model")
+	}
+	glb, err := base64.StdEncoding.DecodeString(match[1])
+	if err != nil || string(glb[:4]) != "glTF" {
+		t.Errorf("embedded model is not a GLB: %v", err)
+	}
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("report loads resources from the network")
+	}
+
+	if err := Write(path, Report{Model: model}); err == nil {
+		t.Error("Write() expected error without a summary")
+	}
+}
@@ -0,0 +1,51 @@
+package slicer
+
+import (
+	"math"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Settings are the print settings a native estimate assumes in place of a slicer profile.
+type Settings struct {
+	LayerHeight      float64       // Height of each layer in mm
+	LineWidth        float64       // Width of an extruded line in mm
+	Perimeters       int           // Solid lines around every surface, walls and skins alike
+	Infill           float64       // Fraction of the interior filled, from 0 to 1
+	FilamentDiameter float64       // Filament diameter in mm
+	Density          float64       // Filament density in g/cm³
+	Flow             float64       // Average volume extruded in mm³/s, slowed by short moves
+	LayerChange      time.Duration // Time lost to travel and retraction on every layer
+}
+
+// DefaultSettings are typical settings for PLA through a 0.4 mm nozzle.
+func DefaultSettings() Settings {
+	return Settings{
+		LayerHeight:      0.2,
+		LineWidth:        0.45,
+		Perimeters:       2,
+		Infill:           0.15,
+		FilamentDiameter: 1.75,
+		Density:          1.24,
+		Flow:             5,
+		LayerChange:      2 * time.Second,
+	}
+}
+
+// Estimate predicts the filament use and print time of a model from its enclosed volume
+// and surface area in mm³ and mm², and its height in mm, without slicing it. Every surface
+// is taken as a solid shell of the perimeters' thickness and the rest as infill; the
+// print time is the material at the average flow plus a layer change per layer. It is a
+// rough guide for comparing models, not a replacement for a slicer's estimate.
+func (s Settings) Estimate(volume, area, height float64) types.PrintEstimate {
+	shell := min(volume, area*float64(s.Perimeters)*s.LineWidth)
+	material := shell + s.Infill*(volume-shell)
+	layers := math.Ceil(height / s.LayerHeight)
+	radius := s.FilamentDiameter / 2
+	return types.PrintEstimate{
+		PrintTime:      (time.Duration(material/s.Flow*float64(time.Second)) + time.Duration(layers)*s.LayerChange).Round(time.Second),
+		FilamentLength: material / (math.Pi * radius * radius),
+		FilamentWeight: material / 1000 * s.Density,
+	}
+}
@@ -0,0 +1,36 @@
+package slicer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSettingsEstimate(t *testing.T) {
+	s := Settings{LayerHeight: 0.5, LineWidth: 1, Perimeters: 1, Infill: 0.5, FilamentDiameter: 2, Density: 1, Flow: 10, LayerChange: time.Second}
+
+	// A 10 mm cube: a 600 mm³ shell and half of the remaining 400 mm³ filled.
+	estimate := s.Estimate(1000, 600, 10)
+	if estimate.FilamentWeight != 0.8 {
+		t.Errorf("FilamentWeight = %v, want 0.8 g", estimate.FilamentWeight)
+	}
+	if want := 800 / math.Pi; math.Abs(estimate.FilamentLength-want) > 1e-9 {
+		t.Errorf("FilamentLength = %v, want %v", estimate.FilamentLength, want)
+	}
+	// 80 s extruding and 20 layer changes.
+	if estimate.PrintTime != 100*time.Second {
+		t.Errorf("PrintTime = %v, want 1m40s", estimate.PrintTime)
+	}
+
+	// Thin parts are solid throughout.
+	if thin := s.Estimate(10, 100, 1); thin.FilamentWeight != 0.01 {
+		t.Errorf("thin FilamentWeight = %v, want the whole 10 mm³", thin.FilamentWeight)
+	}
+
+	// More infill uses more filament.
+	dense := s
+	dense.Infill = 1
+	if dense.Estimate(1000, 600, 10).FilamentWeight <= estimate.FilamentWeight {
+		t.Error("full infill should use more filament than half")
+	}
+}
@@ -0,0 +1,128 @@
+package slicer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gcodeTail is the end of a G-code file as PrusaSlicer writes it.
+const gcodeTail = `G1 X10 Y10
+; filament used [mm] = 4050.25
+; filament used [cm3] = 9.74
+; filament used [g] = 12.08
+; estimated printing time (normal mode) = 1d 2h 14m 3s
+; estimated printing time (silent mode) = 1d 3h 0m 0s
+`
+
+func TestParseKind(t *testing.T) {
+	for name, want := range map[string]Kind{"prusaslicer": KindPrusaSlicer, "SuperSlicer": KindSuperSlicer} {
+		if got, err := ParseKind(name); err != nil || got != want {
+			t.Errorf("ParseKind(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseKind("cura"); err == nil {
+		t.Error("ParseKind(cura) expected an error")
+	}
+}
+
+func TestArgs(t *testing.T) {
+	profile := filepath.Join(t.TempDir(), "mini.ini")
+	if err := os.WriteFile(profile, []byte("layer_height = 0.2\nbed_shape = 0x0,180x0,180x180,0x180\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &Slicer{Kind: KindPrusaSlicer, Command: "prusa-slicer", Profile: profile}
+	args, err := s.Args("model.stl", "model.gcode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"--export-gcode", "--load", profile, "--center", "90,90", "--ensure-on-bed", "--output", "model.gcode", "model.stl"}
+	if !slices.Equal(args, want) {
+		t.Errorf("Args() = %v, want %v", args, want)
+	}
+
+	// Without a profile, the slicer places the model with its own defaults.
+	s.Profile = ""
+	if args, err := s.Args("model.stl", "model.gcode"); err != nil || slices.Contains(args, "--center") {
+		t.Errorf("Args() without a profile = %v, %v, want no --center", args, err)
+	}
+
+	if err := os.WriteFile(profile, []byte("bed_shape = 0x0,wide\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.Profile = profile
+	if _, err := s.Args("model.stl", "model.gcode"); err == nil {
+		t.Error("Args() expected an error for a malformed bed_shape")
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	estimate, err := ParseEstimate(strings.NewReader(gcodeTail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 26*time.Hour + 14*time.Minute + 3*time.Second; estimate.PrintTime != want {
+		t.Errorf("PrintTime = %v, want %v", estimate.PrintTime, want)
+	}
+	if estimate.FilamentLength != 4050.25 || estimate.FilamentWeight != 12.08 {
+		t.Errorf("filament = %v mm, %v g, want 4050.25 mm, 12.08 g", estimate.FilamentLength, estimate.FilamentWeight)
+	}
+	if got, want := estimate.String(), "26h14m3s, 12.1 g (4.05 m) of filament"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	estimate.GCode = filepath.Join("out", "model.gcode")
+	data, err := json.Marshal(estimate)
+	if want := `{"gcode":"model.gcode","printSeconds":94443,"filamentLength":4050.25,"filamentWeight":12.08}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", data, err, want)
+	}
+
+	// Multi-extruder prints list an amount per extruder.
+	estimate, err = ParseEstimate(strings.NewReader("; filament used [mm] = 100.0, 50.5\n; estimated printing time (normal mode) = 5m 0s\n"))
+	if err != nil || estimate.FilamentLength != 150.5 || estimate.PrintTime != 5*time.Minute {
+		t.Errorf("ParseEstimate() = %+v, %v, want 150.5 mm over 5m", estimate, err)
+	}
+
+	if _, err := ParseEstimate(strings.NewReader("G28\n")); err == nil {
+		t.Error("ParseEstimate() expected an error for G-code without estimates")
+	}
+}
+
+func TestSlice(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake slicer is a shell script")
+	}
+	dir := t.TempDir()
+	// The fake slicer writes the G-code to the path after --output.
+	command := filepath.Join(dir, "prusa-slicer")
+	script := "#!/bin/sh\nwhile [ \"$1\" != --output ]; do shift; done\nprintf '%s' \"$GCODE\" > \"$2\"\n"
+	if err := os.WriteFile(command, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GCODE", gcodeTail)
+	t.Setenv("PATH", dir)
+
+	s, err := New(KindPrusaSlicer, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	model := filepath.Join(dir, "model.stl")
+	estimate, err := s.Slice(model)
+	if err != nil {
+		t.Fatalf("Slice() error = %v", err)
+	}
+	if estimate.GCode != filepath.Join(dir, "model.gcode") || estimate.FilamentWeight != 12.08 {
+		t.Errorf("Slice() = %+v, want the estimate of model.gcode", estimate)
+	}
+
+	if _, err := New(KindSuperSlicer, ""); err == nil {
+		t.Error("New() expected an error for a slicer missing from the PATH")
+	}
+	if _, err := New(KindPrusaSlicer, filepath.Join(dir, "missing.ini")); err == nil {
+		t.Error("New() expected an error for a missing profile")
+	}
+}
@@ -0,0 +1,91 @@
+package stl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// BreakdownMode selects how contribution types are shown in the columns.
+type BreakdownMode int
+
+// Supported breakdown modes.
+const (
+	BreakdownOff     BreakdownMode = iota // Plain columns
+	BreakdownStacked                      // Columns segmented by type in the model
+	BreakdownSplit                        // One STL of column segments per type, next to the model
+)
+
+// ParseBreakdownMode converts a flag value ("off", "stacked" or "split") into a BreakdownMode.
+func ParseBreakdownMode(name string) (BreakdownMode, error) {
+	switch strings.ToLower(name) {
+	case "", "off":
+		return BreakdownOff, nil
+	case "stacked":
+		return BreakdownStacked, nil
+	case "split":
+		return BreakdownSplit, nil
+	default:
+		return BreakdownOff, fmt.Errorf("unknown breakdown mode %q (expected off, stacked or split)", name)
+	}
+}
+
+// GenerateBreakdownSTLs writes each contribution type's column segments to its own STL next
+// to outputPath, for a model of the given rows ([row][week][day]) as arranged by
+// ArrangeContributions. The files share the model's coordinates, so loading them together
+// with a model generated with BreakdownSplit assembles the full skyline for multi-colour
+// printing. heightScale and mirror match the model's Options.HeightScale and Mirror.
+// Types without contributions are skipped; the written paths are returned.
+func GenerateBreakdownSTLs(contributions [][][]types.ContributionDay, outputPath string, heightScale float64, mirror bool) ([]string, error) {
+	log := exportLog
+
+	if len(contributions) == 0 {
+		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if outputPath == "" {
+		return nil, errors.New(errors.ValidationError, "output path cannot be empty", nil)
+	}
+
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+	perType := make([][]types.Triangle, len(types.ContributionTypes))
+	for i := len(contributions) - 1; i >= 0; i-- {
+		segments, err := geometry.CreateBreakdownGeometry(contributions[i], len(contributions)-1-i, maxContrib)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate breakdown geometry")
+		}
+		scale := Options{HeightScale: heightScale}.columnScale()
+		for t := range perType {
+			if mirror {
+				geometry.MirrorX(segments[t], mirrorWidth(contributions))
+			}
+			if scale != 1 {
+				if err := geometry.ScaleHeights(segments[t], scale); err != nil {
+					return nil, errors.Wrap(err, "failed to scale breakdown geometry")
+				}
+			}
+			sortTriangles(segments[t])
+			perType[t] = slices.Concat(perType[t], segments[t])
+		}
+	}
+
+	var paths []string
+	for t, name := range types.ContributionTypes {
+		if len(perType[t]) == 0 {
+			if err := log.Info("No %s contributions; skipping their STL", name); err != nil {
+				return nil, errors.Wrap(err, "failed to log info message")
+			}
+			continue
+		}
+		path := utils.BreakdownFilename(outputPath, name)
+		if err := WriteSTLBinary(path, perType[t]); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to write %s STL file", name))
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,113 @@
+package stl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+func TestParseBreakdownMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BreakdownMode
+		wantErr bool
+	}{
+		{"", BreakdownOff, false},
+		{"off", BreakdownOff, false},
+		{"Stacked", BreakdownStacked, false},
+		{"split", BreakdownSplit, false},
+		{"layered", BreakdownOff, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBreakdownMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBreakdownMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBreakdownMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// createBreakdownContributions returns a year where every active day mixes commits and reviews.
+func createBreakdownContributions() [][][]types.ContributionDay {
+	year := createTestContributions()
+	for _, week := range year {
+		for j := range week {
+			if count := week[j].ContributionCount; count > 1 {
+				week[j].Breakdown = types.Breakdown{Commits: count - 1, Reviews: 1}
+			}
+		}
+	}
+	return [][][]types.ContributionDay{year}
+}
+
+func TestGenerateBreakdownSTLs(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "skyline.stl")
+	paths, err := GenerateBreakdownSTLs(createBreakdownContributions(), outputPath, 0, false)
+	if err != nil {
+		t.Fatalf("GenerateBreakdownSTLs() error = %v", err)
+	}
+
+	// Only commits and reviews have contributions.
+	want := []string{utils.BreakdownFilename(outputPath, "commits"), utils.BreakdownFilename(outputPath, "reviews")}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Errorf("GenerateBreakdownSTLs() = %q, want %q", paths, want)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+
+	if _, err := GenerateBreakdownSTLs(nil, outputPath, 0, false); err == nil {
+		t.Error("expected error for empty contributions")
+	}
+	if _, err := GenerateBreakdownSTLs(createBreakdownContributions(), "", 0, false); err == nil {
+		t.Error("expected error for empty output path")
+	}
+}
+
+func TestGenerateSTLRangeWithBreakdown(t *testing.T) {
+	contributions := createBreakdownContributions()
+	tempDir := t.TempDir()
+
+	plainPath := filepath.Join(tempDir, "plain.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, plainPath, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatalf("plain generation failed: %v", err)
+	}
+	stackedPath := filepath.Join(tempDir, "stacked.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, stackedPath, "testuser", 2024, 2024, Options{Breakdown: BreakdownStacked}); err != nil {
+		t.Fatalf("stacked breakdown generation failed: %v", err)
+	}
+	plain, err := os.Stat(plainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stacked, err := os.Stat(stackedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stacked.Size() <= plain.Size() {
+		t.Errorf("segmented columns should add triangles: %d <= %d bytes", stacked.Size(), plain.Size())
+	}
+
+	// Split mode leaves the columns to the per-type files.
+	observer := &mocks.MockObserver{}
+	splitPath := filepath.Join(tempDir, "split.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, splitPath, "testuser", 2024, 2024, Options{Observer: observer, Breakdown: BreakdownSplit}); err != nil {
+		t.Fatalf("split breakdown generation failed: %v", err)
+	}
+	want := []string{"geometry base 1/3", "geometry text 2/3", "geometry image 3/3", "write " + splitPath}
+	if strings.Join(observer.Events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, want)
+	}
+}
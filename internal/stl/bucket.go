@@ -0,0 +1,78 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Bucketing selects how daily counts map to column heights.
+type Bucketing int
+
+// Supported bucketings.
+const (
+	BucketSqrt       Bucketing = iota // Height follows the square root of the count
+	BucketPercentile                  // Height follows the count's percentile among active days
+)
+
+// percentileResolution is the count given to the busiest day by BucketContributions. The
+// square-root normalization of the geometry turns counts on this scale back into heights
+// proportional to the percentiles, to well within a printable step.
+const percentileResolution = 1 << 20
+
+// ParseBucketing converts a flag value ("sqrt" or "percentile") into a Bucketing.
+func ParseBucketing(name string) (Bucketing, error) {
+	switch strings.ToLower(name) {
+	case "", "sqrt":
+		return BucketSqrt, nil
+	case "percentile":
+		return BucketPercentile, nil
+	default:
+		return BucketSqrt, fmt.Errorf("unknown bucketing %q (expected sqrt or percentile)", name)
+	}
+}
+
+// BucketContributions returns the contributions ([year][week][day]) with their counts
+// replaced so that the model's columns follow the bucketing. With BucketPercentile each
+// active day's height above the minimum is proportional to the share of active days in the
+// whole range with the same count or less, so the busiest days still stand out while the
+// rest fill out. Days without contributions stay empty and the type breakdown keeps its
+// shares. The input is not modified; BucketSqrt returns it unchanged.
+func BucketContributions(contributions [][][]types.ContributionDay, mode Bucketing) [][][]types.ContributionDay {
+	if mode != BucketPercentile {
+		return contributions
+	}
+
+	var counts []int
+	for _, year := range contributions {
+		for _, week := range year {
+			for _, day := range week {
+				if day.ContributionCount > 0 {
+					counts = append(counts, day.ContributionCount)
+				}
+			}
+		}
+	}
+	slices.Sort(counts)
+
+	bucketed := make([][][]types.ContributionDay, len(contributions))
+	for i, year := range contributions {
+		bucketed[i] = make([][]types.ContributionDay, len(year))
+		for j, week := range year {
+			bucketed[i][j] = slices.Clone(week)
+			for k, day := range week {
+				if day.ContributionCount <= 0 {
+					continue
+				}
+				// Days with this count or less: the index of the first larger count.
+				atMost, _ := slices.BinarySearch(counts, day.ContributionCount+1)
+				percentile := float64(atMost) / float64(len(counts))
+				bucketed[i][j][k].ContributionCount = max(1, int(math.Round(percentile*percentile*percentileResolution)))
+			}
+		}
+	}
+	return bucketed
+}
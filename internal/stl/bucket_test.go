@@ -0,0 +1,80 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseBucketing(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Bucketing
+		wantErr bool
+	}{
+		{"", BucketSqrt, false},
+		{"sqrt", BucketSqrt, false},
+		{"Percentile", BucketPercentile, false},
+		{"log", BucketSqrt, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBucketing(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBucketing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBucketing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketContributions(t *testing.T) {
+	// One spiky day among quiet ones, split across two years.
+	week := func(counts ...int) []types.ContributionDay {
+		days := make([]types.ContributionDay, len(counts))
+		for i, c := range counts {
+			days[i].ContributionCount = c
+		}
+		return days
+	}
+	contributions := [][][]types.ContributionDay{
+		{week(0, 1, 1, 2)},
+		{week(100, 0, 2, 3)},
+	}
+
+	if got := BucketContributions(contributions, BucketSqrt); &got[0] != &contributions[0] {
+		t.Error("BucketSqrt changed the contributions")
+	}
+
+	bucketed := BucketContributions(contributions, BucketPercentile)
+	if contributions[1][0][0].ContributionCount != 100 {
+		t.Fatal("BucketContributions() modified its input")
+	}
+
+	maxContrib := findMaxContributionsAcrossYears(bucketed)
+	heightRange := geometry.MaxHeight - geometry.MinHeight
+	// Six active days: two with 1, two with 2, then 3 and 100.
+	tests := []struct {
+		year, day  int
+		percentile float64
+	}{
+		{0, 1, 2.0 / 6},
+		{0, 3, 4.0 / 6},
+		{1, 3, 5.0 / 6},
+		{1, 0, 1},
+	}
+	for _, tt := range tests {
+		got := geometry.NormalizeContribution(bucketed[tt.year][0][tt.day].ContributionCount, maxContrib)
+		if want := geometry.MinHeight + tt.percentile*heightRange; math.Abs(got-want) > 1e-3 {
+			t.Errorf("day %d of year %d: height = %v, want %v", tt.day, tt.year, got, want)
+		}
+	}
+	if bucketed[0][0][0].ContributionCount != 0 || bucketed[1][0][1].ContributionCount != 0 {
+		t.Error("days without contributions gained columns")
+	}
+}
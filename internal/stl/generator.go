@@ -2,13 +2,183 @@ package stl
 
 import (
 	"fmt"
+	"image"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/progress"
 	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
 )
 
+// Encoder writes a whole model to w in one file format, such as those of the export
+// package.
+type Encoder interface {
+	Write(model *types.Model, w io.Writer) error
+}
+
+// Decorator adds custom geometry, such as a logo or an event badge, to a finished model
+// before it is written. It returns the components to append, in the model's coordinates.
+type Decorator interface {
+	Decorate(model *types.Model) ([]types.Component, error)
+}
+
+// MaxHeightScale is the largest supported Options.HeightScale; taller columns would no longer
+// print without support.
+const MaxHeightScale = 4.0
+
+// MaxSnap is the largest supported Options.Snap; a coarser grid would visibly distort the
+// text and round shapes.
+const MaxSnap = 0.1
+
+// Loggers for meshing a model and for writing it and the other generated files.
+var (
+	geometryLog = logger.GetLogger().Component("geometry")
+	exportLog   = logger.GetLogger().Component("export")
+)
+
+// Options tunes how a model is generated.
+type Options struct {
+	// MaxMemory caps the estimated triangle memory in bytes. When assembling the whole
+	// model in memory would exceed it, components are generated and streamed to the file
+	// one at a time instead. Zero disables the cap.
+	MaxMemory uint64
+
+	// Observer is notified as model components finish and when the file is written.
+	// A nil Observer ignores every event.
+	Observer progress.Observer
+
+	// Encoder writes the model in another format than binary STL. The model is then
+	// always assembled in memory, as only STL files can be streamed. Nil writes binary
+	// STL with the run's metadata in its header.
+	Encoder Encoder
+
+	// Decorators add their geometry to the model, in order, before it is written. The
+	// model is then always assembled in memory, as decorators receive it whole.
+	Decorators []Decorator
+
+	// Badges are icons embossed in a row along the back edge of the base.
+	Badges []image.Image
+
+	// Avatar is embossed on the front face where the username starts, with the username
+	// moved right to make room; nil leaves it out.
+	Avatar image.Image
+
+	// Text controls the faces and size of the embossed username and year.
+	Text geometry.TextOptions
+
+	// Label replaces the year range embossed after the username, e.g. with a date range;
+	// empty formats the years.
+	Label string
+
+	// Braille also embosses the username and year in Grade-1 Braille on a free face.
+	Braille bool
+
+	// OmitText leaves out the visual username and year, e.g. when Braille replaces them.
+	OmitText bool
+
+	// EngraveText recesses the username and year into the base instead of raising them.
+	EngraveText bool
+
+	// Base shapes the base slab; the zero value is a plain cuboid.
+	Base geometry.BaseOptions
+
+	// MonthLabels engraves month initials into the top of the base in front of the
+	// columns, each over the week the month starts in.
+	MonthLabels bool
+
+	// Layout arranges multiple years on the base; the zero value stacks them.
+	Layout Layout
+
+	// Wrap splits each stacked year into rows of about this many weeks, as
+	// WrapContributions does, on a base only as wide as the rows; zero keeps a row per
+	// year. Rows are no longer years, so they are not labelled with years or months.
+	Wrap int
+
+	// Separators raises a low ridge between neighbouring wrapped rows.
+	Separators bool
+
+	// DoubleSided turns the back row of two stacked years half a turn, so it reads from
+	// the back of the base, for then-and-now prints. The username and BackLabel are
+	// embossed on the back face; the front keeps Label.
+	DoubleSided bool
+	BackLabel   string
+
+	// YearLabels engraves each row's year beside it on a stacked model. Labels on the
+	// front widen the base on the left to make room.
+	YearLabels YearLabels
+
+	// Mirror flips the week axis so time runs right to left, the first week at the right.
+	// Only the columns and month labels move; text, the logo and badges keep their places
+	// and read normally. The spiral layout, which has no left or right, ignores it.
+	Mirror bool
+
+	// Highlight holds the dates of days whose columns are capped with a pyramid, such as
+	// the busiest days of the range. Only styles built of separate columns are capped;
+	// smooth surfaces, bricks, lithophanes, molds, deltas and streak ridges ignore it.
+	Highlight map[string]bool
+
+	// TowerCap tops each day's column with a pyramid or dome, accenting the last active
+	// day of each week with a taller one; flat caps leave the columns as they are. Days
+	// in Highlight keep their own cap, and the styles Highlight ignores ignore it too.
+	TowerCap geometry.TowerCap
+
+	// Flags are the command-line flags recorded in the STL header with the tool version,
+	// username, year range and a hash of the model.
+	Flags string
+
+	// Breakdown segments the columns by contribution type, or leaves them out of the model
+	// when they are written to separate files by GenerateBreakdownSTLs.
+	Breakdown BreakdownMode
+
+	// Style shapes the contribution columns; the zero value is separate towers. Bricks
+	// also add stud sockets under the base, a pen holder replaces the base with a hollow
+	// cylinder that the columns wrap around, a plaque replaces it with a wall plate, and a
+	// lithophane or silhouette replaces the whole model with a single panel or profile.
+	Style Style
+
+	// MergeStreaks fuses each run of consecutive active days in a week into a single ridge
+	// instead of a column per day. It applies to the styles built from plain columns.
+	MergeStreaks bool
+
+	// Granularity is what each plain tower stands for. Weekly contributions, summed with
+	// types.Aggregate, become a single tower per week instead of a column per day, and
+	// monthly ones a tower per month with its initial engraved in front, as MonthLabels.
+	Granularity types.Granularity
+
+	// Inverted subtracts the columns from a solid block on the base, so contribution days
+	// become valleys in a mold-like model.
+	Inverted bool
+
+	// Delta renders signed counts, such as the change between two years: days that grew
+	// stand as columns on a deck laid over the base and days that declined sink into it.
+	Delta bool
+
+	// Template marks every dated day with a low dot instead of a column, for a blank
+	// calendar of a year still to come.
+	Template bool
+
+	// HeightScale multiplies the column heights after normalization; zero leaves them as
+	// they are. Bricks keep whole modules and lithophanes their thickness, so neither is
+	// scaled.
+	HeightScale float64
+
+	// Workers bounds how many components and years are meshed concurrently. Zero uses
+	// every CPU; a memory cap may lower it when streaming.
+	Workers int
+
+	// Snap rounds every vertex to a grid this many millimetres apart as each component is
+	// meshed, welding seams that floating-point error left open, whether the model is
+	// assembled or streamed. Zero writes vertices as they are computed.
+	Snap float64
+}
+
 // GenerateSTL creates a 3D model from GitHub contribution data and writes it to an STL file.
 // It's a convenience wrapper around GenerateSTLRange for single year processing.
 func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username string, year int) error {
@@ -26,7 +196,13 @@ func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username s
 //   - startYear: first year in the range
 //   - endYear: last year in the range
 func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int) error {
-	log := logger.GetLogger()
+	return GenerateSTLRangeWithOptions(contributions, outputPath, username, startYear, endYear, Options{})
+}
+
+// GenerateSTLRangeWithOptions is GenerateSTLRange with explicit generation options.
+func GenerateSTLRangeWithOptions(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, opts Options) error {
+	log := geometryLog
+	observer := progress.OrNop(opts.Observer)
 	if err := log.Debug("Starting STL generation for user %s, years %d-%d", username, startYear, endYear); err != nil {
 		return errors.Wrap(err, "failed to log debug message")
 	}
@@ -45,54 +221,212 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 		if len(contributions[i]) == 0 {
 			return errors.New(errors.ValidationError, fmt.Sprintf("contributions data for year index %d cannot be empty", i), nil)
 		}
-		if len(contributions[i]) > geometry.GridSize {
+		if len(contributions[i]) > geometry.MaxYearWeeks {
 			return errors.New(errors.ValidationError, fmt.Sprintf("contributions data for year index %d exceeds maximum grid size", i), nil)
 		}
 	}
 
-	dimensions, err := calculateDimensions(len(contributions))
+	// Brick models clip onto baseplates through sockets under the base.
+	if opts.Style == StyleBricks {
+		opts.Base.StudSockets = true
+	}
+
+	estimateInput := contributions
+	contributions, dimensions, maxContribution, err := modelLayout(contributions, opts)
 	if err != nil {
-		return errors.Wrap(err, "failed to calculate dimensions")
+		return err
 	}
 
-	// Find global max contribution across all years
-	maxContribution := findMaxContributionsAcrossYears(contributions)
+	stream := StreamsByYear(len(contributions), opts.Layout) && opts.Encoder == nil && len(opts.Decorators) == 0
+	if opts.MaxMemory > 0 {
+		estimate := EstimateModelWithOptions(estimateInput, username, startYear, endYear, opts)
+		overCap := estimate.InMemoryBytes > opts.MaxMemory
+		if overCap && opts.Encoder != nil {
+			return errors.New(errors.ValidationError, fmt.Sprintf("estimated memory %s exceeds the %s cap, and only STL files can be streamed",
+				utils.FormatByteSize(estimate.InMemoryBytes), utils.FormatByteSize(opts.MaxMemory)), nil)
+		}
+		if overCap && len(opts.Decorators) > 0 {
+			return errors.New(errors.ValidationError, fmt.Sprintf("estimated memory %s exceeds the %s cap, and decorated models cannot be streamed",
+				utils.FormatByteSize(estimate.InMemoryBytes), utils.FormatByteSize(opts.MaxMemory)), nil)
+		}
+		if (stream || overCap) && estimate.StreamingBytes > opts.MaxMemory {
+			return errors.New(errors.ValidationError, fmt.Sprintf("estimated memory %s exceeds the %s cap even when streaming",
+				utils.FormatByteSize(estimate.StreamingBytes), utils.FormatByteSize(opts.MaxMemory)), nil)
+		}
+		if !stream && overCap {
+			if err := log.Info("Estimated memory %s exceeds the %s cap; streaming geometry to the STL file",
+				utils.FormatByteSize(estimate.InMemoryBytes), utils.FormatByteSize(opts.MaxMemory)); err != nil {
+				return errors.Wrap(err, "failed to log info message")
+			}
+			stream = true
+		}
+		if stream {
+			// Each worker may hold one component, so run only as many as fit under the cap.
+			perWorker := max(estimate.StreamingBytes-bufferSize, 1)
+			opts.Workers = max(1, min(opts.workerCount(), int((opts.MaxMemory-bufferSize)/perWorker)))
+		}
+	}
 
-	modelTriangles, err := generateModelGeometry(contributions, dimensions, maxContribution, username, startYear, endYear)
-	if err != nil {
-		return errors.Wrap(err, "failed to generate geometry")
+	if stream {
+		if err := log.Debug("Streaming geometry to %s one component and year at a time", outputPath); err != nil {
+			return errors.Wrap(err, "failed to log debug message")
+		}
+		if err := streamModelGeometry(outputPath, contributions, dimensions, maxContribution, username, startYear, endYear, opts); err != nil {
+			return err
+		}
+		observer.OnWriteComplete(outputPath)
+		return nil
 	}
 
-	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
-		return errors.Wrap(err, "failed to log info message")
+	model, err := assembleModel(contributions, dimensions, maxContribution, username, startYear, endYear, opts)
+	if err != nil {
+		return err
+	}
+	meta := modelMetadata(username, startYear, endYear, opts)
+	if opts.Encoder != nil {
+		return encodeModel(outputPath, model, opts.Encoder, observer)
 	}
-	if err := log.Debug("Writing STL file to: %s", outputPath); err != nil {
+	if err := exportLog.Debug("Writing STL file to: %s", outputPath); err != nil {
 		return errors.Wrap(err, "failed to log debug message")
 	}
 
-	if err := WriteSTLBinary(outputPath, modelTriangles); err != nil {
+	encodeStart := time.Now()
+	if err := WriteSTLBinaryWithMetadata(outputPath, model.Triangles(), meta); err != nil {
 		return errors.Wrap(err, "failed to write STL file")
 	}
+	if err := exportLog.Timing("encode", time.Since(encodeStart)); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+
+	observer.OnWriteComplete(outputPath)
 
-	if err := log.Info("STL file written successfully to: %s", outputPath); err != nil {
+	if err := exportLog.Info("STL file written successfully to: %s", outputPath); err != nil {
 		return errors.Wrap(err, "failed to log info message")
 	}
 	return nil
 }
 
+// GenerateModel assembles the model GenerateSTLRangeWithOptions writes without writing it,
+// for outputs that embed the model, such as reports. The model is always assembled in
+// memory, so the memory cap and encoder are ignored.
+func GenerateModel(contributions [][][]types.ContributionDay, username string, startYear, endYear int, opts Options) (*types.Model, error) {
+	if len(contributions) == 0 {
+		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	if opts.Style == StyleBricks {
+		opts.Base.StudSockets = true
+	}
+	contributions, dimensions, maxContribution, err := modelLayout(contributions, opts)
+	if err != nil {
+		return nil, err
+	}
+	return assembleModel(contributions, dimensions, maxContribution, username, startYear, endYear, opts)
+}
+
+// assembleModel generates the model's geometry in memory, records its metadata and adds
+// the decorations.
+func assembleModel(contributions [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts Options) (*types.Model, error) {
+	log := geometryLog
+	geometryStart := time.Now()
+	model, err := generateModelGeometry(contributions, dims, maxContrib, username, startYear, endYear, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate geometry")
+	}
+	if err := log.Timing("geometry", time.Since(geometryStart)); err != nil {
+		return nil, errors.Wrap(err, "failed to log debug message")
+	}
+
+	if err := log.Info("Model generation complete: %d total triangles", model.TriangleCount()); err != nil {
+		return nil, errors.Wrap(err, "failed to log info message")
+	}
+	model.Metadata = modelMetadata(username, startYear, endYear, opts).fields()
+	if err := decorate(model, opts.Decorators); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// decorate appends the components each decorator adds to the model, so later decorators
+// see the earlier decorations.
+func decorate(model *types.Model, decorators []Decorator) error {
+	for _, decorator := range decorators {
+		components, err := decorator.Decorate(model)
+		if err != nil {
+			return errors.Wrap(err, "failed to decorate model")
+		}
+		for _, c := range components {
+			if err := geometryLog.Debug("Decoration %s added %d triangles", c.Name, len(c.Triangles)); err != nil {
+				return errors.Wrap(err, "failed to log debug message")
+			}
+		}
+		model.Components = append(model.Components, components...)
+	}
+	return nil
+}
+
+// encodeModel writes an assembled model to outputPath with encoder.
+func encodeModel(outputPath string, model *types.Model, encoder Encoder, observer progress.Observer) (err error) {
+	log := exportLog
+	encodeStart := time.Now()
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create model file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close model file", cerr)
+		}
+	}()
+	if err := encoder.Write(model, file); err != nil {
+		return errors.Wrap(err, "failed to write model file")
+	}
+	if err := log.Timing("encode", time.Since(encodeStart)); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+
+	observer.OnWriteComplete(outputPath)
+	return log.Info("Model written successfully to: %s", outputPath)
+}
+
+// StreamsByYear reports whether a model with the given number of rows is always streamed.
+// Stacked years are separate slabs, so their columns are generated and written one year
+// at a time and peak memory stays bounded however long the range is. A single row gains
+// nothing from streaming and is assembled in memory unless Options.MaxMemory requires otherwise.
+func StreamsByYear(rows int, layout Layout) bool {
+	return layout == LayoutStacked && rows > 1
+}
+
+// modelMetadata describes how the model is being produced, for its STL header.
+func modelMetadata(username string, startYear, endYear int, opts Options) Metadata {
+	return Metadata{
+		Version:   utils.ToolVersion(),
+		User:      username,
+		StartYear: startYear,
+		EndYear:   endYear,
+		Flags:     opts.Flags,
+	}
+}
+
 // modelDimensions represents the core measurements of the 3D model.
 // All measurements are in millimeters.
 type modelDimensions struct {
 	innerWidth float64 // Width of the contribution grid
 	innerDepth float64 // Depth of the contribution grid
 	imagePath  string  // Path to the logo image
+
+	// offsetX and offsetY shift the columns from their usual place, centring them on a
+	// base larger than the grid.
+	offsetX, offsetY float64
 }
 
 func validateInput(contributions [][]types.ContributionDay, outputPath, username string) error {
 	if len(contributions) == 0 {
 		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
-	if len(contributions) > geometry.GridSize {
+	if len(contributions) > geometry.MaxYearWeeks {
 		return errors.New(errors.ValidationError, "contributions data exceeds maximum grid size", nil)
 	}
 	if outputPath == "" {
@@ -105,12 +439,17 @@ func validateInput(contributions [][]types.ContributionDay, outputPath, username
 }
 
 func calculateDimensions(yearCount int) (modelDimensions, error) {
-	if yearCount <= 0 {
+	return calculateGridDimensions(geometry.GridSize, yearCount)
+}
+
+// calculateGridDimensions sizes the model for rowCount rows of up to weekCount weeks.
+func calculateGridDimensions(weekCount, rowCount int) (modelDimensions, error) {
+	if rowCount <= 0 {
 		return modelDimensions{}, errors.New(errors.ValidationError, "year count must be positive", nil)
 	}
 
 	var width, depth float64
-	width, depth = geometry.CalculateMultiYearDimensions(yearCount)
+	width, depth = geometry.CalculateGridDimensions(weekCount, rowCount)
 
 	dims := modelDimensions{
 		innerWidth: width,
@@ -125,6 +464,47 @@ func calculateDimensions(yearCount int) (modelDimensions, error) {
 	return dims, nil
 }
 
+// wrapDimensions narrows the base to rows wrapped at the given number of weeks.
+func wrapDimensions(dims modelDimensions, weeks int) modelDimensions {
+	dims.innerWidth, _ = geometry.CalculateWrappedDimensions(weeks, 1)
+	return dims
+}
+
+// gridfinityDimensions grows the base to whole Gridfinity units and centres the columns on it.
+func gridfinityDimensions(dims modelDimensions) modelDimensions {
+	width := geometry.GridfinitySize(geometry.GridfinityUnits(dims.innerWidth))
+	depth := geometry.GridfinitySize(geometry.GridfinityUnits(dims.innerDepth))
+	dims.offsetX += (width - dims.innerWidth) / 2
+	dims.offsetY += (depth - dims.innerDepth) / 2
+	dims.innerWidth, dims.innerDepth = width, depth
+	return dims
+}
+
+// penHolderDimensions raises a pen holder to its least height, centring the columns up its
+// wall. The width becomes the holder's circumference and the depth its height.
+func penHolderDimensions(dims modelDimensions) modelDimensions {
+	height := max(dims.innerDepth, geometry.PenHolderMinHeight)
+	dims.offsetY += (height - dims.innerDepth) / 2
+	dims.innerDepth = height
+	return dims
+}
+
+// yearMarginDimensions widens the base on the left for the year of each row and moves the
+// columns along with it.
+func yearMarginDimensions(dims modelDimensions) modelDimensions {
+	dims.innerWidth += geometry.YearMargin
+	dims.offsetX += geometry.YearMargin
+	return dims
+}
+
+// spiralDimensions sizes the base to the round base of a spiral of the given number of weeks.
+// The columns are placed by the spiral, so they are not offset.
+func spiralDimensions(dims modelDimensions, weeks int) modelDimensions {
+	spiral := geometry.NewSpiral(weeks)
+	dims.innerWidth, dims.innerDepth = 2*spiral.Radius, 2*spiral.Radius
+	return dims
+}
+
 func findMaxContributions(contributions [][]types.ContributionDay) int {
 	maxContrib := 0
 	for _, week := range contributions {
@@ -149,6 +529,20 @@ func findMaxContributionsAcrossYears(contributionsPerYear [][][]types.Contributi
 	return maxContrib
 }
 
+// findMaxChange finds the largest count either side of zero across all years, which scales
+// both the rises and the declines of a delta model.
+func findMaxChange(contributionsPerYear [][][]types.ContributionDay) int {
+	maxChange := 0
+	for _, year := range contributionsPerYear {
+		for _, week := range year {
+			for _, day := range week {
+				maxChange = max(maxChange, day.ContributionCount, -day.ContributionCount)
+			}
+		}
+	}
+	return maxChange
+}
+
 // geometryResult holds the output of geometry generation operations.
 // It includes both the generated triangles and any errors that occurred.
 type geometryResult struct {
@@ -156,57 +550,251 @@ type geometryResult struct {
 	err       error
 }
 
-// generateModelGeometry orchestrates the concurrent generation of all model components.
-// It manages four parallel processes for generating the base, columns, text, and logo.
-// Channels are buffered so every goroutine can send and exit even if an error causes
-// an early return, preventing goroutine leaks.
-func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int) ([]types.Triangle, error) {
+// modelComponent is an independently generated part of the model.
+type modelComponent struct {
+	name     string
+	generate func(ch chan<- geometryResult)
+}
+
+// componentMaterial is what the component with the given name prints as.
+func componentMaterial(name string) types.Material {
+	switch name {
+	case "base":
+		return types.MaterialBase
+	case "columns", "lithophane", "silhouette":
+		return types.MaterialSkyline
+	case "separators":
+		return types.MaterialBase
+	default:
+		return types.MaterialLabel
+	}
+}
+
+// modelComponents lists the parts of the model in output order:
+// base → columns → separators → text → back-text → image, followed by the avatar, Braille
+// and badges when requested; separators only divide wrapped rows and back-text only
+// labels double-sided models.
+// Columns are left out when the breakdown is split into separate files. A spiral layout
+// has only its round base, the columns and the text inside the spiral.
+func modelComponents(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts Options) []modelComponent {
+	label := opts.Label
+	if label == "" {
+		label = embossedYear(startYear, endYear)
+	}
+	columns := modelComponent{"columns", func(ch chan<- geometryResult) {
+		generateColumnsForYearRange(contributionsPerYear, maxContrib, dims, opts, ch)
+	}}
+	// These styles have no flat base faces for text, the logo or badges.
+	switch opts.Style {
+	case StylePenholder:
+		return []modelComponent{{"base", func(ch chan<- geometryResult) { generatePenHolder(dims, ch) }}, columns}
+	case StylePlaque:
+		return []modelComponent{{"base", func(ch chan<- geometryResult) { generatePlaque(dims, ch) }}, columns}
+	case StyleLithophane:
+		return []modelComponent{{"lithophane", func(ch chan<- geometryResult) {
+			generateLithophane(contributionsPerYear, maxContrib, dims, opts.Mirror, ch)
+		}}}
+	case StyleSilhouette:
+		return []modelComponent{{"silhouette", func(ch chan<- geometryResult) {
+			generateSilhouette(contributionsPerYear, dims, opts.Mirror, ch)
+		}}}
+	}
+
+	if opts.Layout == LayoutSpiral && len(contributionsPerYear) > 0 {
+		// A round base has no flat faces for the logo; the text goes inside the spiral.
+		spiral := geometry.NewSpiral(len(contributionsPerYear[0]))
+		components := []modelComponent{{"base", func(ch chan<- geometryResult) { generateSpiralBase(spiral, ch) }}, columns}
+		if !opts.OmitText {
+			components = append(components, modelComponent{"text", func(ch chan<- geometryResult) {
+				generateSpiralLabel(username, label, spiral, ch)
+			}})
+		}
+		return components
+	}
+
+	if opts.monthLabelled() && len(contributionsPerYear) > 0 {
+		// The most recent row is at the front of the base.
+		opts.Text.Months = monthTicks(contributionsPerYear, dims, opts)
+	}
+	if opts.yearLabelled() && len(contributionsPerYear) > 0 {
+		years := make([]string, len(contributionsPerYear))
+		for i := range years {
+			years[i] = strconv.Itoa(startYear + i)
+		}
+		opts.Text.Years = geometry.YearTicks(years, dims.offsetY)
+		if opts.YearLabels == YearLabelsFront {
+			// The strip stops a cell short of the columns.
+			opts.Text.YearStrip = dims.offsetX + geometry.CellSize
+		}
+	}
+
+	opts.Text.Avatar = opts.Avatar != nil
+	engrave := opts.EngraveText && !opts.OmitText
+	base := func(ch chan<- geometryResult) { generateBase(dims, opts.Base, ch) }
+	if engrave {
+		base = func(ch chan<- geometryResult) {
+			generateEngravedBase(username, label, dims, opts.Text, opts.Base, ch)
+		}
+	} else if opts.Text.Stats != "" || len(opts.Text.Months) > 0 || len(opts.Text.Years) > 0 {
+		base = func(ch chan<- geometryResult) { generateStatsBase(dims, opts.Text, opts.Base, ch) }
+	}
+
+	components := []modelComponent{{"base", base}}
+	if opts.Breakdown != BreakdownSplit {
+		components = append(components, columns)
+	}
+	if opts.wrapped() && opts.Separators && len(contributionsPerYear) > 1 {
+		components = append(components, modelComponent{"separators", func(ch chan<- geometryResult) {
+			generateSeparators(contributionsPerYear, dims, ch)
+		}})
+	}
+	if !opts.OmitText && !engrave {
+		components = append(components, modelComponent{"text", func(ch chan<- geometryResult) { generateText(username, label, dims, opts.Text, ch) }})
+		if opts.doubleSided() {
+			back := geometry.TextOptions{UsernameFace: geometry.FaceBack, YearFace: geometry.FaceBack, Scale: opts.Text.Scale}
+			components = append(components, modelComponent{"back-text", func(ch chan<- geometryResult) {
+				generateText(username, opts.BackLabel, dims, back, ch)
+			}})
+		}
+	}
+	components = append(components, modelComponent{"image", func(ch chan<- geometryResult) { generateLogo(dims, ch) }})
+	if opts.Avatar != nil {
+		components = append(components, modelComponent{"avatar", func(ch chan<- geometryResult) { generateAvatar(opts.Avatar, dims, ch) }})
+	}
+	if opts.Braille {
+		components = append(components, modelComponent{"braille", func(ch chan<- geometryResult) { generateBraille(username, label, dims, opts, ch) }})
+	}
+	if len(opts.Badges) > 0 {
+		components = append(components, modelComponent{"badges", func(ch chan<- geometryResult) { generateBadges(opts.Badges, dims, ch) }})
+	}
+	return components
+}
+
+// generateModelGeometry meshes all model components across the worker pool and assembles
+// them into a model in declaration order, giving reproducible STL output for any number
+// of workers.
+func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts Options) (*types.Model, error) {
 	if len(contributionsPerYear) == 0 {
 		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
+	observer := progress.OrNop(opts.Observer)
+	components := modelComponents(contributionsPerYear, dims, maxContrib, username, startYear, endYear, opts)
+	jobs := modelJobs(components, contributionsPerYear, maxContrib, dims, opts)
+
+	model := &types.Model{Components: make([]types.Component, len(components))}
+	for i, c := range components {
+		model.Components[i] = types.Component{Name: c.name, Material: componentMaterial(c.name)}
+	}
+	err := runOrdered(jobs, opts.workerCount(), func(job geometryJob, triangles []types.Triangle) error {
+		component := &model.Components[job.component]
+		component.Triangles = append(component.Triangles, triangles...)
+		if job.last {
+			observer.OnGeometryProgress(job.name, job.component+1, len(components))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// modelLayout arranges contributions ([year][week][day]) into the rows of the model and
+// sizes the base for them, returning the rows, the base's dimensions and the count the
+// tallest column stands for.
+func modelLayout(contributions [][][]types.ContributionDay, opts Options) ([][][]types.ContributionDay, modelDimensions, int, error) {
+	rows := opts.arrange(contributions)
 
-	// componentChannel pairs a name with its buffered result channel.
-	// Using a slice (not a map) preserves a stable iteration order so that
-	// triangles are always appended base → columns → text → image, giving
-	// reproducible STL output across runs.
-	type componentChannel struct {
-		name string
-		ch   chan geometryResult
+	dimensions, err := calculateGridDimensions(geometry.GridWeeks(rows), len(rows))
+	if err != nil {
+		return nil, modelDimensions{}, 0, errors.Wrap(err, "failed to calculate dimensions")
+	}
+	if opts.wrapped() {
+		dimensions = wrapDimensions(dimensions, geometry.GridWeeks(rows))
 	}
 
-	// Buffered channels (size 1) allow each goroutine to send its result and exit
-	// regardless of whether the main goroutine reads or returns early on error.
-	components := []componentChannel{
-		{"base", make(chan geometryResult, 1)},
-		{"columns", make(chan geometryResult, 1)},
-		{"text", make(chan geometryResult, 1)},
-		{"image", make(chan geometryResult, 1)},
+	if opts.Base.Footprint == geometry.FootprintGridfinity {
+		dimensions = gridfinityDimensions(dimensions)
+	}
+	if opts.Style == StylePenholder {
+		dimensions = penHolderDimensions(dimensions)
+	}
+	if opts.Layout == LayoutSpiral {
+		dimensions = spiralDimensions(dimensions, geometry.GridWeeks(rows))
+	}
+	if opts.yearLabelled() && opts.YearLabels == YearLabelsFront {
+		dimensions = yearMarginDimensions(dimensions)
 	}
 
-	// Launch goroutines for each component
-	go generateBase(dims, components[0].ch)
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, components[1].ch)
-	go generateText(username, startYear, endYear, dims, components[2].ch)
-	go generateLogo(dims, components[3].ch)
+	// Find global max contribution across all years
+	maxContribution := findMaxContributionsAcrossYears(rows)
+	if opts.Delta {
+		maxContribution = findMaxChange(rows)
+	}
+	return rows, dimensions, maxContribution, nil
+}
+
+// streamModelGeometry meshes the model components across the worker pool and writes each
+// to the STL file as soon as it and everything before it are ready, in the same order as
+// generateModelGeometry. Columns are streamed per year, so peak memory is bounded by the
+// largest single component times the number of workers.
+func streamModelGeometry(outputPath string, contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts Options) (err error) {
+	log := geometryLog
+	observer := progress.OrNop(opts.Observer)
+
+	meta := modelMetadata(username, startYear, endYear, opts)
+	stream, err := createSTLStream(outputPath, &meta)
+	if err != nil {
+		return errors.Wrap(err, "failed to write STL file")
+	}
+	defer func() {
+		if cerr := stream.Close(); cerr != nil && err == nil {
+			err = errors.Wrap(cerr, "failed to write STL file")
+		}
+	}()
+
+	// Time spent writing is tracked separately so geometry and encode timings stay
+	// comparable with the in-memory path.
+	start := time.Now()
+	var encodeTime time.Duration
+	write := func(triangles []types.Triangle) error {
+		writeStart := time.Now()
+		defer func() { encodeTime += time.Since(writeStart) }()
+		return stream.Write(triangles)
+	}
 
-	// Collect results in declaration order for a reproducible triangle sequence.
-	modelTriangles := make([]types.Triangle, 0, estimateTriangleCount(contributionsPerYear[0])*len(contributionsPerYear))
-	for _, component := range components {
-		result := <-component.ch
-		if result.err != nil {
-			return nil, errors.Wrap(result.err, fmt.Sprintf("failed to generate %s geometry", component.name))
+	components := modelComponents(contributionsPerYear, dims, maxContrib, username, startYear, endYear, opts)
+	jobs := modelJobs(components, contributionsPerYear, maxContrib, dims, opts)
+	err = runOrdered(jobs, opts.workerCount(), func(job geometryJob, triangles []types.Triangle) error {
+		if err := write(triangles); err != nil {
+			return err
 		}
-		modelTriangles = append(modelTriangles, result.triangles...)
+		if job.last {
+			observer.OnGeometryProgress(job.name, job.component+1, len(components))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := log.Timing("geometry", time.Since(start)-encodeTime); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+	if err := exportLog.Timing("encode", encodeTime); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
 	}
 
-	return modelTriangles, nil
+	if err := exportLog.Info("Model streamed to %s: %d total triangles", outputPath, stream.count); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
 }
 
-func generateBase(dims modelDimensions, ch chan<- geometryResult) {
-	baseTriangles, err := geometry.CreateCuboidBase(dims.innerWidth, dims.innerDepth)
+func generateBase(dims modelDimensions, base geometry.BaseOptions, ch chan<- geometryResult) {
+	baseTriangles, err := geometry.CreateStyledBase(dims.innerWidth, dims.innerDepth, base)
 
 	if err != nil {
-		if logErr := logger.GetLogger().Warning("Failed to generate base geometry: %v. Continuing without base.", err); logErr != nil {
+		if logErr := geometryLog.Warning("Failed to generate base geometry: %v. Continuing without base.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
 			return
 		}
@@ -217,19 +805,124 @@ func generateBase(dims modelDimensions, ch chan<- geometryResult) {
 	ch <- geometryResult{triangles: baseTriangles}
 }
 
-// generateText creates 3D text geometry for the model
-func generateText(username string, startYear int, endYear int, dims modelDimensions, ch chan<- geometryResult) {
-	embossedYear := fmt.Sprintf("%d", endYear)
+// generatePenHolder creates the hollow cylinder that a pen holder's columns wrap around.
+func generatePenHolder(dims modelDimensions, ch chan<- geometryResult) {
+	holderTriangles, err := geometry.CreatePenHolder(dims.innerWidth, dims.innerDepth)
+	if err != nil {
+		ch <- geometryResult{err: errors.New(errors.STLError, "failed to generate pen holder", err)}
+		return
+	}
+	ch <- geometryResult{triangles: holderTriangles}
+}
+
+// generatePlaque creates the wall plate that a plaque's columns are embossed on.
+func generatePlaque(dims modelDimensions, ch chan<- geometryResult) {
+	plateTriangles, err := geometry.CreatePlaque(dims.innerWidth, dims.innerDepth)
+	if err != nil {
+		ch <- geometryResult{err: errors.New(errors.STLError, "failed to generate plaque", err)}
+		return
+	}
+	ch <- geometryResult{triangles: plateTriangles}
+}
+
+// generateSpiralBase creates the round base under a spiral layout.
+func generateSpiralBase(spiral geometry.Spiral, ch chan<- geometryResult) {
+	baseTriangles, err := geometry.CreateSpiralBase(spiral)
+	if err != nil {
+		ch <- geometryResult{err: errors.New(errors.STLError, "failed to generate round base", err)}
+		return
+	}
+	ch <- geometryResult{triangles: baseTriangles}
+}
+
+// generateSpiralLabel embosses the username and year label inside a spiral layout.
+func generateSpiralLabel(username, label string, spiral geometry.Spiral, ch chan<- geometryResult) {
+	labelTriangles, err := geometry.CreateSpiralLabel(username, label, spiral)
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
+			ch <- geometryResult{err: logErr}
+			return
+		}
+		ch <- geometryResult{}
+		return
+	}
+	ch <- geometryResult{triangles: labelTriangles}
+}
+
+// generateLithophane creates a lithophane panel covering the model from the contribution
+// heatmap, flipped left to right when mirrored.
+func generateLithophane(contributionsPerYear [][][]types.ContributionDay, maxContrib int, dims modelDimensions, mirror bool, ch chan<- geometryResult) {
+	heatmap := geometry.LithophaneHeatmap(contributionsPerYear, maxContrib)
+	panelTriangles, err := geometry.CreateLithophane(heatmap, dims.innerWidth, dims.innerDepth)
+	if err != nil {
+		ch <- geometryResult{err: errors.New(errors.STLError, "failed to generate lithophane", err)}
+		return
+	}
+	if mirror {
+		geometry.MirrorX(panelTriangles, dims.innerWidth)
+	}
+	ch <- geometryResult{triangles: panelTriangles}
+}
+
+// generateSilhouette builds the front profile of the skyline as a flat plate as wide as
+// the base, reflected when the weeks run right to left.
+func generateSilhouette(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, mirror bool, ch chan<- geometryResult) {
+	plateTriangles, err := geometry.CreateSilhouette(geometry.ProfileHeights(contributionsPerYear), dims.innerWidth)
+	if err != nil {
+		ch <- geometryResult{err: errors.New(errors.STLError, "failed to generate silhouette", err)}
+		return
+	}
+	if mirror {
+		geometry.MirrorX(plateTriangles, dims.innerWidth)
+	}
+	ch <- geometryResult{triangles: plateTriangles}
+}
+
+// generateEngravedBase creates the base with the username and year label recessed into it.
+// If the text cannot be rendered, a plain base is used instead.
+func generateEngravedBase(username, label string, dims modelDimensions, textOpts geometry.TextOptions, base geometry.BaseOptions, ch chan<- geometryResult) {
+	baseTriangles, err := geometry.CreateEngravedBase(username, label, dims.innerWidth, dims.innerDepth, geometry.BaseHeight, textOpts, base)
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to engrave text: %v. Continuing without text.", err); logErr != nil {
+			ch <- geometryResult{err: logErr}
+			return
+		}
+		generateBase(dims, base, ch)
+		return
+	}
+	ch <- geometryResult{triangles: baseTriangles}
+}
+
+// generateStatsBase creates the base with the stats line and month initials recessed into it.
+// If they cannot be rendered, a plain base is used instead.
+func generateStatsBase(dims modelDimensions, textOpts geometry.TextOptions, base geometry.BaseOptions, ch chan<- geometryResult) {
+	baseTriangles, err := geometry.CreateStatsBase(dims.innerWidth, dims.innerDepth, geometry.BaseHeight, textOpts, base)
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to engrave stats or months: %v. Continuing without them.", err); logErr != nil {
+			ch <- geometryResult{err: logErr}
+			return
+		}
+		generateBase(dims, base, ch)
+		return
+	}
+	ch <- geometryResult{triangles: baseTriangles}
+}
 
+// embossedYear formats the year range shown on the base.
+func embossedYear(startYear, endYear int) string {
 	// If start year and end year are the same, only show one year
-	if startYear != endYear {
-		// Make the year 'YYYY-YY'
-		embossedYear = fmt.Sprintf("%04d-%02d", startYear, endYear%100)
+	if startYear == endYear {
+		return fmt.Sprintf("%d", endYear)
 	}
+	// Make the year 'YYYY-YY'
+	return fmt.Sprintf("%04d-%02d", startYear, endYear%100)
+}
 
-	textTriangles, err := geometry.Create3DText(username, embossedYear, dims.innerWidth, geometry.BaseHeight)
+// generateText creates 3D text geometry for the username and year label
+func generateText(username, label string, dims modelDimensions, textOpts geometry.TextOptions, ch chan<- geometryResult) {
+	textTriangles, err := geometry.Create3DTextWithOptions(username, label, dims.innerWidth, dims.innerDepth, geometry.BaseHeight, textOpts)
 	if err != nil {
-		if logErr := logger.GetLogger().Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
+		if logErr := geometryLog.Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
 			return
 		}
@@ -239,12 +932,86 @@ func generateText(username string, startYear int, endYear int, dims modelDimensi
 	ch <- geometryResult{triangles: textTriangles}
 }
 
+// brailleFace picks the face for Braille: the back, unless the visual text or stats use it,
+// then the front. It reports false when both are occupied.
+func brailleFace(opts Options) (geometry.Face, bool) {
+	for _, face := range []geometry.Face{geometry.FaceBack, geometry.FaceFront} {
+		if face == geometry.FaceBack && opts.Text.Stats != "" {
+			continue
+		}
+		if opts.OmitText || (opts.Text.UsernameFace != face && opts.Text.YearFace != face) {
+			return face, true
+		}
+	}
+	return geometry.FaceFront, false
+}
+
+// generateBraille embosses the username and year label in Grade-1 Braille.
+func generateBraille(username, label string, dims modelDimensions, opts Options, ch chan<- geometryResult) {
+	var brailleTriangles []types.Triangle
+	var err error
+	if face, ok := brailleFace(opts); ok {
+		brailleTriangles, err = geometry.CreateBrailleGeometry(username+" "+label, face, dims.innerWidth, dims.innerDepth, geometry.BaseHeight)
+	} else {
+		err = errors.New(errors.ValidationError, "text already covers the front and back faces", nil)
+	}
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to generate Braille geometry: %v. Continuing without Braille.", err); logErr != nil {
+			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
+			return
+		}
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	ch <- geometryResult{triangles: brailleTriangles}
+}
+
+// generateBadges embosses earned badge icons along the back edge of the base
+func generateBadges(icons []image.Image, dims modelDimensions, ch chan<- geometryResult) {
+	badgeTriangles, err := geometry.CreateBadgeGeometry(icons, dims.innerWidth, dims.innerDepth)
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to generate badge geometry: %v. Continuing without badges.", err); logErr != nil {
+			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
+			return
+		}
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	ch <- geometryResult{triangles: badgeTriangles}
+}
+
+// generateSeparators raises the ridges between wrapped rows, on the same offset as the
+// columns.
+func generateSeparators(rows [][][]types.ContributionDay, dims modelDimensions, ch chan<- geometryResult) {
+	triangles, err := geometry.CreateRowSeparators(len(rows), geometry.GridWeeks(rows))
+	if err != nil {
+		ch <- geometryResult{err: errors.Wrap(err, "failed to generate row separators")}
+		return
+	}
+	offsetTriangles(triangles, dims.offsetX, dims.offsetY)
+	ch <- geometryResult{triangles: triangles}
+}
+
+// generateAvatar embosses the user's avatar on the front face before the username.
+func generateAvatar(avatar image.Image, dims modelDimensions, ch chan<- geometryResult) {
+	avatarTriangles, err := geometry.CreateAvatarGeometry(avatar, dims.innerWidth, geometry.BaseHeight)
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to generate avatar geometry: %v. Continuing without avatar.", err); logErr != nil {
+			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
+			return
+		}
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	ch <- geometryResult{triangles: avatarTriangles}
+}
+
 // generateLogo handles the generation of the GitHub logo geometry
 func generateLogo(dims modelDimensions, ch chan<- geometryResult) {
 	logoTriangles, err := geometry.GenerateImageGeometry(dims.innerWidth, geometry.BaseHeight)
 	if err != nil {
 		// Log warning and continue without logo instead of failing
-		if logErr := logger.GetLogger().Warning("Failed to generate logo geometry: %v. Continuing without logo.", err); logErr != nil {
+		if logErr := geometryLog.Warning("Failed to generate logo geometry: %v. Continuing without logo.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
 			return
 		}
@@ -270,24 +1037,258 @@ func estimateTriangleCount(contributions [][]types.ContributionDay) int {
 	return baseTrianglesCount + columnsTrianglesCount + textTrianglesEstimate
 }
 
-// generateColumnsForYearRange generates contribution columns for multiple years
-func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, maxContrib int, ch chan<- geometryResult) {
+// generateColumnsForYearRange generates contribution columns for multiple years in the
+// style and breakdown selected by opts.
+func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, maxContrib int, dims modelDimensions, opts Options, ch chan<- geometryResult) {
 	var yearTriangles []types.Triangle
 
 	// Process years in reverse order so most recent year is at the front
 	for i := len(contributionsPerYear) - 1; i >= 0; i-- {
-		yearOffset := len(contributionsPerYear) - 1 - i
-		triangles, err := geometry.CreateContributionGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+		triangles, err := columnsForYear(contributionsPerYear, i, maxContrib, dims, opts)
 		if err != nil {
-			if logErr := logger.GetLogger().Warning("Failed to generate column geometry for year %d: %v. Skipping year.", i, err); logErr != nil {
-				// logErr is secondary; report the original geometry error to the caller.
-				ch <- geometryResult{triangles: []types.Triangle{}, err: err}
-				return
-			}
-			continue
+			ch <- geometryResult{triangles: []types.Triangle{}, err: err}
+			return
 		}
 		yearTriangles = append(yearTriangles, triangles...)
 	}
 
 	ch <- geometryResult{triangles: yearTriangles}
 }
+
+// columnsForYear generates the contribution columns for the year at index i, segmented by
+// contribution type, merged into streak ridges, subtracted from a block, as a smooth
+// surface, as bricks or as the hours of a clock when requested. Pen holder columns
+// are wrapped around the holder's wall, plaque columns lowered to a relief and spiral
+// layouts bent around the round base.
+// A year whose geometry fails is logged and skipped by returning no triangles.
+func columnsForYear(contributionsPerYear [][][]types.ContributionDay, i, maxContrib int, dims modelDimensions, opts Options) ([]types.Triangle, error) {
+	yearOffset := len(contributionsPerYear) - 1 - i
+	var triangles []types.Triangle
+	var err error
+	switch {
+	case opts.Template:
+		triangles, err = geometry.CreateTemplateGeometry(contributionsPerYear[i], yearOffset)
+	case opts.Style == StyleSmooth:
+		triangles, err = geometry.CreateSurfaceGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	case opts.Style == StyleBricks:
+		triangles, err = geometry.CreateBrickGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	case opts.Style == StyleClock:
+		triangles, err = geometry.CreateClockGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	case opts.Breakdown == BreakdownStacked:
+		var segments [][]types.Triangle
+		segments, err = geometry.CreateBreakdownGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+		triangles = slices.Concat(segments...)
+	case opts.Delta:
+		weeks := geometry.GridWeeks(contributionsPerYear)
+		triangles, err = geometry.CreateDeltaGeometry(contributionsPerYear[i], yearOffset, len(contributionsPerYear), weeks, maxContrib)
+	case opts.Inverted:
+		weeks := geometry.GridWeeks(contributionsPerYear)
+		triangles, err = geometry.CreateMoldGeometry(contributionsPerYear[i], yearOffset, len(contributionsPerYear), weeks, maxContrib)
+	case opts.MergeStreaks:
+		triangles, err = geometry.CreateStreakGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	case opts.Granularity == types.GranularityWeek:
+		triangles, err = geometry.CreateTowerGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	case opts.Granularity == types.GranularityMonth:
+		triangles, err = geometry.CreateMonthGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	default:
+		triangles, err = geometry.CreateContributionGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+	}
+	if err == nil && opts.highlighted() {
+		var caps []types.Triangle
+		caps, err = geometry.CreateHighlightCaps(contributionsPerYear[i], yearOffset, maxContrib, opts.Highlight)
+		triangles = append(triangles, caps...)
+	}
+	if err == nil && opts.capped() {
+		var caps []types.Triangle
+		caps, err = geometry.CreateTowerCaps(contributionsPerYear[i], yearOffset, maxContrib, opts.TowerCap, opts.capSkipped())
+		triangles = append(triangles, caps...)
+	}
+	if err != nil {
+		if logErr := geometryLog.Warning("Failed to generate column geometry for year %d: %v. Skipping year.", i, err); logErr != nil {
+			// logErr is secondary; report the original geometry error to the caller.
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err := placeColumns(triangles, contributionsPerYear, i, dims, opts); err != nil {
+		return nil, err
+	}
+	sortTriangles(triangles)
+	return triangles, nil
+}
+
+// placeColumns moves the columns of row i, meshed on the flat grid, into place on the
+// model: mirrored, turned, offset, scaled and wrapped as the options ask.
+func placeColumns(triangles []types.Triangle, contributionsPerYear [][][]types.ContributionDay, i int, dims modelDimensions, opts Options) error {
+	if opts.mirrored() {
+		geometry.MirrorX(triangles, opts.mirrorWidth(contributionsPerYear))
+	}
+	if opts.doubleSided() && len(contributionsPerYear) == 2 && i == 0 {
+		// The back row turns about its own centre, so it stays in its half of the base.
+		_, y := geometry.CellPosition(0, 0, len(contributionsPerYear)-1)
+		geometry.RotateHalfTurn(triangles, mirrorWidth(contributionsPerYear)/2, y+geometry.YearOffset/2)
+	}
+	offsetTriangles(triangles, dims.offsetX, dims.offsetY)
+	if scale := opts.columnScale(); scale != 1 {
+		if err := geometry.ScaleHeights(triangles, scale); err != nil {
+			return err
+		}
+	}
+	if opts.Style == StylePenholder {
+		if err := geometry.WrapCylinder(triangles, dims.innerWidth); err != nil {
+			return err
+		}
+	}
+	if opts.Layout == LayoutSpiral {
+		if err := geometry.NewSpiral(len(contributionsPerYear[i])).Wrap(triangles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yearLabelled reports whether the rows are labelled with their years: only stacked rows
+// on a plain base have a year each.
+func (o Options) yearLabelled() bool {
+	switch o.Style {
+	case StylePenholder, StylePlaque, StyleLithophane, StyleSilhouette, StyleClock:
+		return false
+	}
+	return o.YearLabels != YearLabelsNone && o.Layout == LayoutStacked && !o.wrapped()
+}
+
+// monthLabelled reports whether month initials are engraved: when asked for, and always
+// for monthly towers on a base with a strip in front of them, unless the rows are wrapped.
+func (o Options) monthLabelled() bool {
+	if o.wrapped() {
+		return false
+	}
+	if o.MonthLabels {
+		return true
+	}
+	switch o.Style {
+	case StylePenholder, StylePlaque, StyleLithophane, StyleSilhouette, StyleClock:
+		return false
+	}
+	return o.Granularity == types.GranularityMonth && !o.Inverted && !o.Delta
+}
+
+// monthTicks places the month labels over the weeks of the most recent row, at the front
+// of the base, following the columns when they are mirrored. Monthly towers are labelled
+// at their centres.
+func monthTicks(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, opts Options) []geometry.MonthTick {
+	row := contributionsPerYear[len(contributionsPerYear)-1]
+	ticks := geometry.MonthTicks(row, dims.offsetX)
+	if opts.Granularity == types.GranularityMonth {
+		ticks = geometry.MonthTowerTicks(row, dims.offsetX)
+	}
+	if opts.mirrored() {
+		width := opts.mirrorWidth(contributionsPerYear)
+		for i := range ticks {
+			ticks[i].X = 2*dims.offsetX + width - ticks[i].X
+		}
+	}
+	return ticks
+}
+
+// highlighted reports whether highlighted days are capped.
+func (o Options) highlighted() bool {
+	return len(o.Highlight) > 0 && o.dayColumns()
+}
+
+// capped reports whether columns are topped with TowerCap.
+func (o Options) capped() bool {
+	return o.TowerCap != geometry.CapFlat && o.dayColumns()
+}
+
+// capSkipped returns the days whose columns carry a highlight cap instead of TowerCap.
+func (o Options) capSkipped() map[string]bool {
+	if o.highlighted() {
+		return o.Highlight
+	}
+	return nil
+}
+
+// dayColumns reports whether the model is built of a separate column per day, with a
+// column top for a cap to stand on.
+func (o Options) dayColumns() bool {
+	switch {
+	case o.Template, o.Delta, o.Inverted, o.MergeStreaks, o.Granularity != types.GranularityDay:
+		return false
+	}
+	switch o.Style {
+	case StyleSmooth, StyleBricks, StyleLithophane, StyleSilhouette, StyleClock:
+		return false
+	}
+	return true
+}
+
+// mirrored reports whether the week axis is flipped: layouts without a left and right,
+// such as the spiral, keep it.
+func (o Options) mirrored() bool {
+	return o.Mirror && o.Layout != LayoutSpiral
+}
+
+// mirrorWidth is the width of the column grid of rows ([row][week][day]) before it is
+// offset, margins included, across which mirrored columns are reflected. Rows shorter than
+// the grid end up at its right, keeping their first week at the same edge as every other row.
+func mirrorWidth(rows [][][]types.ContributionDay) float64 {
+	width, _ := geometry.CalculateGridDimensions(geometry.GridWeeks(rows), len(rows))
+	return width
+}
+
+// mirrorWidth is the width mirrored columns of rows arranged with the options are
+// reflected across; wrapped rows have a grid only as wide as themselves.
+func (o Options) mirrorWidth(rows [][][]types.ContributionDay) float64 {
+	if o.wrapped() {
+		width, _ := geometry.CalculateWrappedDimensions(geometry.GridWeeks(rows), len(rows))
+		return width
+	}
+	return mirrorWidth(rows)
+}
+
+// doubleSided reports whether the back row is turned to read from the back: only stacked
+// layouts have a back row.
+func (o Options) doubleSided() bool {
+	return o.DoubleSided && o.Layout == LayoutStacked && !o.Style.ReplacesBase()
+}
+
+// wrapped reports whether the years are wrapped into rows: only stacked layouts wrap.
+func (o Options) wrapped() bool {
+	return o.Wrap > 0 && o.Layout == LayoutStacked
+}
+
+// arrange regroups contributions ([year][week][day]) into the rows of the model, wrapped
+// when requested and otherwise as ArrangeContributions lays them out.
+func (o Options) arrange(contributions [][][]types.ContributionDay) [][][]types.ContributionDay {
+	if o.wrapped() {
+		return WrapContributions(contributions, o.Wrap)
+	}
+	return ArrangeContributions(contributions, o.Layout)
+}
+
+// columnScale is the factor applied to the height of the columns: HeightScale, lowered to a
+// relief on a plaque.
+func (o Options) columnScale() float64 {
+	scale := 1.0
+	if o.HeightScale > 0 && o.Style != StyleBricks {
+		scale = o.HeightScale
+	}
+	if o.Style == StylePlaque {
+		scale *= geometry.PlaqueRelief
+	}
+	return scale
+}
+
+// offsetTriangles moves triangles by (dx, dy) in place.
+func offsetTriangles(triangles []types.Triangle, dx, dy float64) {
+	if dx == 0 && dy == 0 {
+		return
+	}
+	for i := range triangles {
+		for _, v := range []*types.Point3D{&triangles[i].V1, &triangles[i].V2, &triangles[i].V3} {
+			v.X += dx
+			v.Y += dy
+		}
+	}
+}
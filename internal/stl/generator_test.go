@@ -1,12 +1,22 @@
 package stl
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -29,9 +39,8 @@ func TestGenerateSTL(t *testing.T) {
 
 	err := GenerateSTL(contributions, outputPath, "testuser", 2023)
 	if err != nil {
-		// Check if error is due to missing resources
-		if strings.Contains(err.Error(), "failed to open image") ||
-			strings.Contains(err.Error(), "failed to load fonts") {
+		// Check if error is due to missing resources; fonts are parsed from the binary
+		if strings.Contains(err.Error(), "failed to open image") {
 			t.Skip("Skipping test due to missing required resources")
 		}
 		t.Errorf("GenerateSTL failed: %v", err)
@@ -158,9 +167,9 @@ func TestGenerateSTLRange(t *testing.T) {
 			wantErr:       true,
 		},
 		{
-			name: "year index 1 exceeds GridSize",
+			name: "year index 1 exceeds MaxYearWeeks",
 			contributions: func() [][][]types.ContributionDay {
-				oversized := make([][]types.ContributionDay, geometry.GridSize+1)
+				oversized := make([][]types.ContributionDay, geometry.MaxYearWeeks+1)
 				for i := range oversized {
 					oversized[i] = make([]types.ContributionDay, 7)
 				}
@@ -340,7 +349,7 @@ func TestGenerateBase(t *testing.T) {
 	}
 	ch := make(chan geometryResult, 1)
 
-	go generateBase(dims, ch)
+	go generateBase(dims, geometry.BaseOptions{}, ch)
 
 	result := <-ch
 	if result.err != nil {
@@ -358,7 +367,7 @@ func TestGenerateText(t *testing.T) {
 	}
 	ch := make(chan geometryResult, 1)
 
-	go generateText("testuser", 2023, 2023, dims, ch)
+	go generateText("testuser", "2023", dims, geometry.TextOptions{}, ch)
 
 	result := <-ch
 	if result.err != nil {
@@ -388,7 +397,7 @@ func TestGenerateColumnsForYearRange(t *testing.T) {
 	maxContrib := 10 // Set a known max contribution value
 
 	// Test the goroutine
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, ch)
+	go generateColumnsForYearRange(contributionsPerYear, maxContrib, modelDimensions{}, Options{}, ch)
 
 	// Collect the result
 	result := <-ch
@@ -444,22 +453,33 @@ func TestGenerateModelGeometry(t *testing.T) {
 	startYear := 2022
 	endYear := 2023
 
-	triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, username, startYear, endYear)
+	model, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, username, startYear, endYear, Options{})
 	if err != nil {
-		t.Errorf("generateModelGeometry() error = %v", err)
+		t.Fatalf("generateModelGeometry() error = %v", err)
 	}
-	if len(triangles) == 0 {
+	if model.TriangleCount() == 0 {
 		t.Error("generateModelGeometry() returned no triangles")
 	}
+	// Components keep their names, in output order, with the material they print as.
+	var names []string
+	for _, c := range model.Components {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "base,columns,text,image" {
+		t.Errorf("components = %s, want base,columns,text,image", got)
+	}
+	if columns, _ := model.Component("columns"); columns.Material != types.MaterialSkyline || len(columns.Triangles) == 0 {
+		t.Errorf("columns component = %d triangles of %q, want the skyline", len(columns.Triangles), columns.Material)
+	}
 
 	// Test error case with nil contributions
-	_, err = generateModelGeometry(nil, dims, maxContrib, username, startYear, endYear)
+	_, err = generateModelGeometry(nil, dims, maxContrib, username, startYear, endYear, Options{})
 	if err == nil {
 		t.Error("generateModelGeometry() should return error for nil contributions")
 	}
 
 	// Test with empty username
-	_, err = generateModelGeometry(contributionsPerYear, dims, maxContrib, "", startYear, endYear)
+	_, err = generateModelGeometry(contributionsPerYear, dims, maxContrib, "", startYear, endYear, Options{})
 	if err != nil {
 		t.Error("generateModelGeometry() should handle empty username")
 	}
@@ -481,6 +501,27 @@ func TestGenerateLogo(t *testing.T) {
 	}
 }
 
+func TestGridfinityDimensions(t *testing.T) {
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := gridfinityDimensions(dims)
+	if got.innerWidth != geometry.GridfinitySize(4) || got.innerDepth != geometry.GridfinitySize(1) {
+		t.Errorf("base = %vx%v, want 4x1 Gridfinity units", got.innerWidth, got.innerDepth)
+	}
+	// The columns keep equal margins either side.
+	if got.offsetX*2 != got.innerWidth-dims.innerWidth || got.offsetY*2 != got.innerDepth-dims.innerDepth {
+		t.Errorf("offset = (%v, %v), want the columns centred", got.offsetX, got.offsetY)
+	}
+
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	opts := Options{Base: geometry.BaseOptions{Footprint: geometry.FootprintGridfinity}}
+	if err := GenerateSTLRangeWithOptions(contributions, filepath.Join(t.TempDir(), "gridfinity.stl"), "testuser", 2024, 2024, opts); err != nil {
+		t.Errorf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
 func TestCalculateDimensions(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -531,7 +572,7 @@ func TestGenerateText_WithYearRange(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ch := make(chan geometryResult, 1)
 
-			go generateText(tt.username, tt.startYear, tt.endYear, dims, ch)
+			go generateText(tt.username, embossedYear(tt.startYear, tt.endYear), dims, geometry.TextOptions{}, ch)
 
 			result := <-ch
 			// Even if font generation fails, result should not be nil
@@ -566,7 +607,7 @@ func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
 
 			ch := make(chan geometryResult, 1)
 
-			go generateColumnsForYearRange(contributionsPerYear, tt.maxContrib, ch)
+			go generateColumnsForYearRange(contributionsPerYear, tt.maxContrib, modelDimensions{}, Options{}, ch)
 
 			result := <-ch
 			if tt.expectTriangles && len(result.triangles) == 0 {
@@ -588,7 +629,7 @@ func TestResourceHandling(t *testing.T) {
 		ch := make(chan geometryResult, 1)
 
 		// This should log a warning but continue
-		go generateText("testuser", 2023, 2023, dims, ch)
+		go generateText("testuser", "2023", dims, geometry.TextOptions{}, ch)
 
 		result := <-ch
 		// Even with missing fonts, we should get a valid (possibly empty) result
@@ -629,13 +670,13 @@ func TestResourceHandling(t *testing.T) {
 		maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
 
 		// This should complete successfully even with missing resources
-		triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023)
+		model, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023, Options{})
 		if err != nil {
-			t.Errorf("generateModelGeometry() failed with missing resources: %v", err)
+			t.Fatalf("generateModelGeometry() failed with missing resources: %v", err)
 		}
 
 		// Should still generate base geometry and contribution columns
-		if len(triangles) == 0 {
+		if model.TriangleCount() == 0 {
 			t.Error("generateModelGeometry() returned no triangles with missing resources")
 		}
 	})
@@ -668,3 +709,819 @@ func TestCalculateDimensionsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamsByYear(t *testing.T) {
+	tests := []struct {
+		rows   int
+		layout Layout
+		want   bool
+	}{
+		{1, LayoutStacked, false},
+		{17, LayoutStacked, true},
+		{1, LayoutStrip, false},
+	}
+	for _, tt := range tests {
+		if got := StreamsByYear(tt.rows, tt.layout); got != tt.want {
+			t.Errorf("StreamsByYear(%d, %v) = %v, want %v", tt.rows, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSTLRangeWithOptions(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	tempDir := t.TempDir()
+
+	// Stacked years always stream, so build the in-memory reference directly.
+	inMemoryPath := filepath.Join(tempDir, "in-memory.stl")
+	dims, err := calculateDimensions(len(contributions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := generateModelGeometry(contributions, dims, findMaxContributionsAcrossYears(contributions), "testuser", 2023, 2024, Options{})
+	if err != nil {
+		t.Fatalf("in-memory generation failed: %v", err)
+	}
+	if err := WriteSTLBinaryWithMetadata(inMemoryPath, model.Triangles(), modelMetadata("testuser", 2023, 2024, Options{})); err != nil {
+		t.Fatal(err)
+	}
+
+	estimate := EstimateModel(contributions, "testuser", 2023, 2024)
+	streamedPath := filepath.Join(tempDir, "streamed.stl")
+	observer := &mocks.MockObserver{}
+	if err := GenerateSTLRangeWithOptions(contributions, streamedPath, "testuser", 2023, 2024, Options{MaxMemory: estimate.StreamingBytes, Observer: observer}); err != nil {
+		t.Fatalf("streamed generation failed: %v", err)
+	}
+
+	wantEvents := []string{"geometry base 1/4", "geometry columns 2/4", "geometry text 3/4", "geometry image 4/4", "write " + streamedPath}
+	if strings.Join(observer.Events, "\n") != strings.Join(wantEvents, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, wantEvents)
+	}
+
+	inMemory, err := os.ReadFile(inMemoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamed, err := os.ReadFile(streamedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(inMemory, streamed) {
+		t.Error("streamed STL differs from in-memory STL")
+	}
+
+	defaultPath := filepath.Join(tempDir, "default.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, defaultPath, "testuser", 2023, 2024, Options{}); err != nil {
+		t.Fatalf("default generation failed: %v", err)
+	}
+	if got, err := os.ReadFile(defaultPath); err != nil || !bytes.Equal(got, inMemory) {
+		t.Errorf("default STL differs from in-memory STL (err %v)", err)
+	}
+
+	err = GenerateSTLRangeWithOptions(contributions, filepath.Join(tempDir, "capped.stl"), "testuser", 2023, 2024, Options{MaxMemory: 1024})
+	if err == nil || !strings.Contains(err.Error(), "even when streaming") {
+		t.Errorf("expected cap error, got %v", err)
+	}
+}
+
+func TestGenerateSTLRangeWithBadges(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	icon := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			icon.Set(x, y, color.White)
+		}
+	}
+
+	tempDir := t.TempDir()
+	observer := &mocks.MockObserver{}
+	path := filepath.Join(tempDir, "badges.stl")
+	opts := Options{Observer: observer, Badges: []image.Image{icon}}
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("generation with badges failed: %v", err)
+	}
+	if got := observer.Events[4]; got != "geometry badges 5/5" {
+		t.Errorf("badges event = %q, want %q", got, "geometry badges 5/5")
+	}
+
+	plainPath := filepath.Join(tempDir, "plain.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, plainPath, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatalf("generation without badges failed: %v", err)
+	}
+	withBadges, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := os.Stat(plainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withBadges.Size() <= plain.Size() {
+		t.Errorf("badges should add triangles: %d <= %d bytes", withBadges.Size(), plain.Size())
+	}
+}
+
+func TestGenerateSTLRangeWithAvatar(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	avatar := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range avatar.Pix {
+		avatar.Pix[i] = 0xff
+	}
+
+	observer := &mocks.MockObserver{}
+	opts := Options{Observer: observer, Avatar: avatar}
+	components := modelComponents(contributions, modelDimensions{innerWidth: 100, innerDepth: 30}, 5, "testuser", 2024, 2024, opts)
+	if got := components[len(components)-1].name; got != "avatar" {
+		t.Errorf("last component = %q, want the avatar after the logo", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "avatar.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("generation with an avatar failed: %v", err)
+	}
+	if got := observer.Events[4]; got != "geometry avatar 5/5" {
+		t.Errorf("avatar event = %q, want %q", got, "geometry avatar 5/5")
+	}
+}
+
+func TestGenerateSTLRangeWithBraille(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	observer := &mocks.MockObserver{}
+	path := filepath.Join(t.TempDir(), "braille.stl")
+	opts := Options{Observer: observer, Braille: true, OmitText: true}
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("generation with Braille failed: %v", err)
+	}
+
+	want := []string{"geometry base 1/4", "geometry columns 2/4", "geometry image 3/4", "geometry braille 4/4", "write " + path}
+	if strings.Join(observer.Events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, want)
+	}
+}
+
+func TestBrailleFace(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		wantFace geometry.Face
+		wantOK   bool
+	}{
+		{"default text", Options{}, geometry.FaceBack, true},
+		{"text on back", Options{Text: geometry.TextOptions{UsernameFace: geometry.FaceBack, YearFace: geometry.FaceBack}}, geometry.FaceFront, true},
+		{"text on both", Options{Text: geometry.TextOptions{UsernameFace: geometry.FaceFront, YearFace: geometry.FaceBack}}, geometry.FaceFront, false},
+		{"text omitted", Options{OmitText: true, Text: geometry.TextOptions{UsernameFace: geometry.FaceBack}}, geometry.FaceBack, true},
+		{"stats on back", Options{OmitText: true, Text: geometry.TextOptions{Stats: "1 contributions · 1 day streak"}}, geometry.FaceFront, true},
+		{"stats and text", Options{Text: geometry.TextOptions{Stats: "1 contributions · 1 day streak"}}, geometry.FaceFront, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			face, ok := brailleFace(tt.opts)
+			if face != tt.wantFace || ok != tt.wantOK {
+				t.Errorf("brailleFace() = %v, %v, want %v, %v", face, ok, tt.wantFace, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSTLRangeWithEngravedText(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	observer := &mocks.MockObserver{}
+	path := filepath.Join(t.TempDir(), "engraved.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2024, 2024, Options{Observer: observer, EngraveText: true}); err != nil {
+		t.Fatalf("generation with engraved text failed: %v", err)
+	}
+
+	// The text is part of the base, so there is no separate text component.
+	want := []string{"geometry base 1/3", "geometry columns 2/3", "geometry image 3/3", "write " + path}
+	if strings.Join(observer.Events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, want)
+	}
+}
+
+func TestGenerateSTLRangeWithStats(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	observer := &mocks.MockObserver{}
+	path := filepath.Join(t.TempDir(), "stats.stl")
+	opts := Options{Observer: observer, Text: geometry.TextOptions{Stats: "4,321 contributions · 212 day streak"}}
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("generation with stats failed: %v", err)
+	}
+
+	// The stats are recessed into the base while the username and year stay raised.
+	want := []string{"geometry base 1/4", "geometry columns 2/4", "geometry text 3/4", "geometry image 4/4", "write " + path}
+	if strings.Join(observer.Events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, want)
+	}
+}
+
+func TestGenerateSTLRangeHeader(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	estimate := EstimateModel(contributions, "testuser", 2023, 2024)
+	for name, maxMemory := range map[string]uint64{"in memory": 0, "streamed": estimate.StreamingBytes} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "header.stl")
+			opts := Options{Flags: "--stand=true", MaxMemory: maxMemory}
+			if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2023, 2024, opts); err != nil {
+				t.Fatalf("generation failed: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := string(data[:headerSize])
+			if !strings.HasPrefix(header, "gh-skyline/") || !strings.Contains(header, " testuser 2023-24 sha256:") || !strings.Contains(header, " --stand=true") {
+				t.Errorf("header = %q", header)
+			}
+		})
+	}
+}
+
+func TestColumnScale(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want float64
+	}{
+		{"unset", Options{}, 1},
+		{"towers", Options{HeightScale: 1.5}, 1.5},
+		{"bricks keep whole modules", Options{HeightScale: 1.5, Style: StyleBricks}, 1},
+		{"plaque relief", Options{HeightScale: 2, Style: StylePlaque}, 2 * geometry.PlaqueRelief},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.columnScale(); got != tt.want {
+				t.Errorf("columnScale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeightScale(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions()}
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	for _, style := range []Style{StyleTowers, StyleSmooth} {
+		triangles, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{Style: style, HeightScale: 1.5})
+		if err != nil {
+			t.Fatalf("columnsForYear() error = %v", err)
+		}
+		peak := 0.0
+		for _, tri := range triangles {
+			peak = max(peak, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+		}
+		if want := 1.5 * geometry.MaxHeight; peak > want+1e-9 || peak < want-0.5 {
+			t.Errorf("style %v: tallest column = %v, want about %v", style, peak, want)
+		}
+	}
+}
+
+func TestMergeStreaks(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions()}
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	towers, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	ridges, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{MergeStreaks: true})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if len(ridges) == 0 || len(ridges) >= len(towers) {
+		t.Errorf("merged streaks have %d triangles, want fewer than the %d of separate columns", len(ridges), len(towers))
+	}
+
+	// The estimate follows the merged geometry.
+	plain := EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{})
+	merged := EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{MergeStreaks: true})
+	if got, want := plain.Triangles-merged.Triangles, len(towers)-len(ridges); got != want {
+		t.Errorf("estimate drops by %d triangles, want %d", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	year := createTestContributions()
+	for w := range year {
+		for d := range year[w] {
+			year[w][d].Date = time.Date(2024, 1, 7+w*7+d, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		}
+	}
+	rows := [][][]types.ContributionDay{year}
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	top := types.TopDays(rows, 5)
+
+	plain, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	capped, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{Highlight: top})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if got, want := len(capped)-len(plain), 5*geometry.HighlightCapTriangles; got != want {
+		t.Errorf("highlighting adds %d triangles, want %d for five caps", got, want)
+	}
+
+	// The estimate counts the caps, and styles without column tops leave them out.
+	estimate := EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{Highlight: top}).Triangles -
+		EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{}).Triangles
+	if estimate != 5*geometry.HighlightCapTriangles {
+		t.Errorf("estimate grows by %d triangles, want %d", estimate, 5*geometry.HighlightCapTriangles)
+	}
+	ridges, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{MergeStreaks: true, Highlight: top})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if plainRidges, _ := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{MergeStreaks: true}); len(ridges) != len(plainRidges) {
+		t.Errorf("merged streaks have %d triangles with highlights, want the %d without", len(ridges), len(plainRidges))
+	}
+}
+
+func TestTowerCap(t *testing.T) {
+	year := createTestContributions()
+	for w := range year {
+		for d := range year[w] {
+			year[w][d].Date = time.Date(2024, 1, 7+w*7+d, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		}
+	}
+	rows := [][][]types.ContributionDay{year}
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	active := 0
+	for _, week := range year {
+		for _, day := range week {
+			if day.ContributionCount > 0 {
+				active++
+			}
+		}
+	}
+
+	plain, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, Options{})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	// Highlighted days keep their pyramid, so every active day has exactly one cap.
+	top := types.TopDays(rows, 5)
+	opts := Options{TowerCap: geometry.CapDome, Highlight: top}
+	capped, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, opts)
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	want := 5*geometry.HighlightCapTriangles + (active-5)*geometry.CapDome.Triangles()
+	if got := len(capped) - len(plain); got != want {
+		t.Errorf("domes and highlights add %d triangles, want %d", got, want)
+	}
+	estimate := EstimateModelWithOptions(rows, "testuser", 2024, 2024, opts).Triangles -
+		EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{}).Triangles
+	if estimate != want {
+		t.Errorf("estimate grows by %d triangles, want %d", estimate, want)
+	}
+
+	// Styles without separate day columns have nothing to cap.
+	for name, opts := range map[string]Options{"smooth": {Style: StyleSmooth}, "weekly": {Granularity: types.GranularityWeek}, "inverted": {Inverted: true}} {
+		opts.TowerCap = geometry.CapPyramid
+		if opts.capped() {
+			t.Errorf("%s model should leave the columns uncapped", name)
+		}
+	}
+}
+
+func TestGranularityWeek(t *testing.T) {
+	rows := types.Aggregate([][][]types.ContributionDay{createTestContributions()}, types.GranularityWeek)
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	opts := Options{Granularity: types.GranularityWeek}
+
+	towers, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, opts)
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	// Every week of the test data has contributions, so each gets one tower.
+	if want := len(rows[0]) * geometry.TowerTriangles; len(towers) != want {
+		t.Errorf("weekly towers = %d triangles, want %d", len(towers), want)
+	}
+	if got := EstimateModelWithOptions(rows, "testuser", 2024, 2024, opts).Triangles - EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{}).Triangles; got != len(towers)-7*len(towers) {
+		t.Errorf("estimate differs from daily columns by %d triangles, want %d", got, len(towers)-7*len(towers))
+	}
+}
+
+func TestGranularityMonth(t *testing.T) {
+	start := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	year := make([][]types.ContributionDay, 52)
+	for w := range year {
+		year[w] = make([]types.ContributionDay, 7)
+		for d := range year[w] {
+			year[w][d] = types.ContributionDay{Date: start.AddDate(0, 0, 7*w+d).Format("2006-01-02"), ContributionCount: (w + d) % 5}
+		}
+	}
+	rows := types.Aggregate([][][]types.ContributionDay{year}, types.GranularityMonth)
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	opts := Options{Granularity: types.GranularityMonth}
+
+	towers, err := columnsForYear(rows, 0, maxContrib, modelDimensions{}, opts)
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if want := 12 * geometry.TowerTriangles; len(towers) != want {
+		t.Errorf("monthly towers = %d triangles, want %d for twelve towers", len(towers), want)
+	}
+	if got := columnTriangles(rows[0], maxContrib, opts); got != len(towers) {
+		t.Errorf("estimated %d triangles, want %d", got, len(towers))
+	}
+
+	// Monthly towers are labelled at their centres, even without --month-labels.
+	if !opts.monthLabelled() || (Options{Granularity: types.GranularityMonth, Style: StylePlaque}).monthLabelled() {
+		t.Error("monthLabelled() should label monthly towers on a plain base only")
+	}
+	ticks := monthTicks(rows, modelDimensions{}, opts)
+	if want := geometry.MonthTowerTicks(rows[0], 0); !slices.Equal(ticks, want) {
+		t.Errorf("monthTicks() = %v, want %v", ticks, want)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "monthly.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestInverted(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	maxContrib := findMaxContributionsAcrossYears(rows)
+	opts := Options{Inverted: true, HeightScale: 1.5}
+	for i := range rows {
+		triangles, err := columnsForYear(rows, i, maxContrib, modelDimensions{}, opts)
+		if err != nil {
+			t.Fatalf("columnsForYear() error = %v", err)
+		}
+		peak := 0.0
+		for _, tri := range triangles {
+			peak = max(peak, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+		}
+		// The block is as tall as the tallest column would be.
+		if want := 1.5 * geometry.MaxHeight; math.Abs(peak-want) > 1e-9 {
+			t.Errorf("year %d: block height = %v, want %v", i, peak, want)
+		}
+		if want := geometry.MoldTriangleCount(len(rows[i])); len(triangles) > want {
+			t.Errorf("year %d: got %d triangles, more than the estimated %d", i, len(triangles), want)
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "inverted.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions()}
+	opts := Options{Wrap: 26, Separators: true, Mirror: true, YearLabels: YearLabelsFront, MonthLabels: true}
+	arranged := opts.arrange(rows)
+	if len(arranged) != 2 || geometry.GridWeeks(arranged) != 26 {
+		t.Fatalf("arranged %d rows of %d weeks, want 2 rows of 26", len(arranged), geometry.GridWeeks(arranged))
+	}
+	// Wrapped rows are not years, so they are not labelled.
+	if opts.yearLabelled() || opts.monthLabelled() {
+		t.Error("wrapped rows should not be labelled with years or months")
+	}
+
+	dims, err := calculateGridDimensions(26, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dims = wrapDimensions(dims, 26)
+	if want := 30 * geometry.CellSize; math.Abs(dims.innerWidth-want) > 1e-9 || opts.mirrorWidth(arranged) != dims.innerWidth {
+		t.Errorf("wrapped base is %v wide, mirrored across %v, want %v", dims.innerWidth, opts.mirrorWidth(arranged), want)
+	}
+
+	components := modelComponents(arranged, dims, 4, "testuser", 2024, 2024, opts)
+	if len(components) < 3 || components[2].name != "separators" {
+		t.Errorf("modelComponents() = %v, want separators after the columns", components)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "wrapped.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	triangles, err := ReadSTLBinary(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := meshtest.Measure(triangles)
+	if inv.Max.X-inv.Min.X > dims.innerWidth+1e-9 {
+		t.Errorf("wrapped model is %v wide, want at most %v", inv.Max.X-inv.Min.X, dims.innerWidth)
+	}
+	if estimate := EstimateModelWithOptions(rows, "testuser", 2024, 2024, opts).Triangles - EstimateModelWithOptions(rows, "testuser", 2024, 2024, Options{}).Triangles; estimate != geometry.SeparatorTriangleCount(2) {
+		t.Errorf("separators add %d triangles to the estimate, want %d", estimate, geometry.SeparatorTriangleCount(2))
+	}
+}
+
+func TestDoubleSided(t *testing.T) {
+	// A then-and-now pair: the back row holds a single week, so turning it moves its
+	// columns from the left of the base to the right.
+	then := createTestContributions()[:1]
+	rows := [][][]types.ContributionDay{then, createTestContributions()}
+	opts := Options{DoubleSided: true, Label: "2025", BackLabel: "2015"}
+	dims, err := calculateGridDimensions(geometry.GridWeeks(rows), len(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := columnsForYear(rows, 0, 4, dims, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	turned, err := columnsForYear(rows, 0, 4, dims, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, after := meshtest.Measure(plain), meshtest.Measure(turned)
+	// The row turns about the centre of its half: across the grid width and its own depth.
+	_, rowY := geometry.CellPosition(0, 0, 1)
+	sumX, sumY := 2*dims.offsetX+mirrorWidth(rows), 2*(dims.offsetY+rowY)+geometry.YearOffset
+	if math.Abs(after.Min.X-(sumX-before.Max.X)) > 1e-9 || math.Abs(after.Min.Y-(sumY-before.Max.Y)) > 1e-9 || math.Abs(after.Max.Y-(sumY-before.Min.Y)) > 1e-9 {
+		t.Errorf("turned back row spans %v to %v, want %v to %v turned in its own half", after.Min, after.Max, before.Min, before.Max)
+	}
+	// The front row reads from the front as usual.
+	front, err := columnsForYear(rows, 1, 4, dims, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, err := columnsForYear(rows, 1, 4, dims, Options{}); err != nil || meshtest.Measure(front) != meshtest.Measure(want) {
+		t.Errorf("front row = %+v, want it unchanged", meshtest.Measure(front))
+	}
+
+	components := modelComponents(rows, dims, 4, "testuser", 2015, 2025, opts)
+	names := make([]string, len(components))
+	for i, c := range components {
+		names[i] = c.name
+	}
+	if !slices.Contains(names, "back-text") {
+		t.Errorf("modelComponents() = %v, want back-text", names)
+	}
+	if estimate := EstimateModelWithOptions(rows, "testuser", 2015, 2025, opts).Triangles - EstimateModelWithOptions(rows, "testuser", 2015, 2025, Options{}).Triangles; estimate != 12*textTrianglesPerGlyph {
+		t.Errorf("back text adds %d triangles to the estimate, want %d", estimate, 12*textTrianglesPerGlyph)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "double-sided.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2015, 2025, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	// A single busy day in the first week of the year.
+	start := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	year := make([][]types.ContributionDay, geometry.GridSize)
+	for w := range year {
+		year[w] = make([]types.ContributionDay, 7)
+		for d := range year[w] {
+			year[w][d].Date = start.AddDate(0, 0, 7*w+d).Format("2006-01-02")
+		}
+	}
+	year[0][3].ContributionCount = 5
+	rows := [][][]types.ContributionDay{year}
+	dims := modelDimensions{offsetX: 10}
+
+	extent := func(opts Options) (minX, maxX float64) {
+		t.Helper()
+		triangles, err := columnsForYear(rows, 0, 5, dims, opts)
+		if err != nil {
+			t.Fatalf("columnsForYear() error = %v", err)
+		}
+		minX, maxX = math.Inf(1), math.Inf(-1)
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				minX, maxX = min(minX, v.X), max(maxX, v.X)
+			}
+		}
+		return minX, maxX
+	}
+
+	width := mirrorWidth(rows)
+	first, _ := geometry.CellPosition(0, 0, 0)
+	if minX, maxX := extent(Options{}); minX != dims.offsetX+first || maxX != dims.offsetX+first+geometry.CellSize {
+		t.Errorf("unmirrored first week spans x %v to %v, want it at the left", minX, maxX)
+	}
+	if minX, maxX := extent(Options{Mirror: true}); math.Abs(minX-(dims.offsetX+width-first-geometry.CellSize)) > 1e-9 || math.Abs(maxX-(dims.offsetX+width-first)) > 1e-9 {
+		t.Errorf("mirrored first week spans x %v to %v, want it at the right", minX, maxX)
+	}
+	if (Options{Mirror: true, Layout: LayoutSpiral}).mirrored() {
+		t.Error("spiral layout mirrored, want it left as is")
+	}
+
+	// Month labels follow their weeks.
+	plain := monthTicks(rows, dims, Options{})
+	mirrored := monthTicks(rows, dims, Options{Mirror: true})
+	if len(plain) == 0 || len(mirrored) != len(plain) {
+		t.Fatalf("monthTicks() = %v and %v mirrored, want the same months", plain, mirrored)
+	}
+	for i := range plain {
+		if got, want := mirrored[i].X, 2*dims.offsetX+width-plain[i].X; math.Abs(got-want) > 1e-9 || mirrored[i].Initial != plain[i].Initial {
+			t.Errorf("mirrored %s at x %v, want %v", mirrored[i].Initial, got, want)
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "mirrored.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2024, 2024, Options{Mirror: true, MonthLabels: true}); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestDelta(t *testing.T) {
+	year := createTestContributions()
+	year[0][0].ContributionCount = -20
+	year[1][3].ContributionCount = 12
+	rows := [][][]types.ContributionDay{year}
+	if got := findMaxChange(rows); got != 20 {
+		t.Fatalf("findMaxChange() = %d, want 20", got)
+	}
+
+	opts := Options{Delta: true}
+	triangles, err := columnsForYear(rows, 0, findMaxChange(rows), modelDimensions{}, opts)
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	peak := 0.0
+	for _, tri := range triangles {
+		peak = max(peak, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+	}
+	// The biggest rise is smaller than the biggest decline, so it stands short of MaxHeight.
+	if want := geometry.DeckHeight + geometry.NormalizeContribution(12, 20); math.Abs(peak-want) > 1e-9 {
+		t.Errorf("model peak = %v, want %v", peak, want)
+	}
+	if estimate := columnTriangles(year, 20, opts); len(triangles) > estimate {
+		t.Errorf("got %d triangles, more than the estimated %d", len(triangles), estimate)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "delta.stl")
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "testuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestGenerateSTLRangeWithLabel(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	dir := t.TempDir()
+	plain, labelled := filepath.Join(dir, "plain.stl"), filepath.Join(dir, "label.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, plain, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateSTLRangeWithOptions(contributions, labelled, "testuser", 2024, 2024, Options{Label: "2024-03-01/06-30"}); err != nil {
+		t.Fatalf("generation with a label failed: %v", err)
+	}
+
+	// The longer label embosses more glyphs than the year alone.
+	plainInfo, err := os.Stat(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labelInfo, err := os.Stat(labelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labelInfo.Size() <= plainInfo.Size() {
+		t.Errorf("label should replace the year: %d <= %d bytes", labelInfo.Size(), plainInfo.Size())
+	}
+}
+
+// countingEncoder writes the number of triangles it is given instead of a model.
+type countingEncoder struct{}
+
+func (countingEncoder) Write(model *types.Model, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%d triangles", model.TriangleCount())
+	return err
+}
+
+func TestGenerateSTLRangeWithEncoder(t *testing.T) {
+	// Stacked years are assembled in memory rather than streamed when an encoder is set.
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	dir := t.TempDir()
+	plain, encoded := filepath.Join(dir, "plain.stl"), filepath.Join(dir, "model.txt")
+	if err := GenerateSTLRangeWithOptions(contributions, plain, "testuser", 2023, 2024, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateSTLRangeWithOptions(contributions, encoded, "testuser", 2023, 2024, Options{Encoder: countingEncoder{}}); err != nil {
+		t.Fatalf("generation with an encoder failed: %v", err)
+	}
+
+	triangles, err := ReadSTLBinary(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%d triangles", len(triangles)); string(data) != want {
+		t.Errorf("encoded model = %q, want %q", data, want)
+	}
+
+	// Only STL can be streamed, so a model over the memory cap is rejected.
+	err = GenerateSTLRangeWithOptions(contributions, encoded, "testuser", 2023, 2024, Options{Encoder: countingEncoder{}, MaxMemory: 1})
+	if err == nil || !strings.Contains(err.Error(), "only STL files can be streamed") {
+		t.Errorf("encoding over the memory cap error = %v", err)
+	}
+}
+
+// plinthDecorator adds a triangle above the model, recording the user it was told about.
+type plinthDecorator struct {
+	user *string
+}
+
+func (d plinthDecorator) Decorate(model *types.Model) ([]types.Component, error) {
+	*d.user = model.Metadata["user"]
+	top := geometry.BaseHeight + geometry.MaxHeight + 1
+	return []types.Component{{Name: "plinth", Material: types.MaterialLabel, Triangles: []types.Triangle{
+		{V1: types.Point3D{Z: top}, V2: types.Point3D{X: 1, Z: top}, V3: types.Point3D{Y: 1, Z: top}},
+	}}}, nil
+}
+
+func TestGenerateSTLRangeWithDecorators(t *testing.T) {
+	// Stacked years are assembled in memory rather than streamed when decorated.
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	dir := t.TempDir()
+	plain, decorated := filepath.Join(dir, "plain.stl"), filepath.Join(dir, "decorated.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, plain, "testuser", 2023, 2024, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	var user string
+	opts := Options{Decorators: []Decorator{plinthDecorator{&user}}}
+	if err := GenerateSTLRangeWithOptions(contributions, decorated, "testuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("generation with a decorator failed: %v", err)
+	}
+
+	before, err := ReadSTLBinary(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := ReadSTLBinary(decorated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before)+1 || after[len(after)-1].V1.Z != geometry.BaseHeight+geometry.MaxHeight+1 {
+		t.Errorf("decorated model has %d triangles, want the %d of the plain model and the plinth last", len(after), len(before))
+	}
+	if user != "testuser" {
+		t.Errorf("decorator saw user %q, want testuser", user)
+	}
+
+	opts.MaxMemory = 1
+	if err := GenerateSTLRangeWithOptions(contributions, decorated, "testuser", 2023, 2024, opts); err == nil || !strings.Contains(err.Error(), "decorated models cannot be streamed") {
+		t.Errorf("decorating over the memory cap error = %v", err)
+	}
+}
+
+func TestGenerateSTLRangeTemplate(t *testing.T) {
+	// A year still to come: dated days without contributions.
+	year := [][]types.ContributionDay{
+		{{}, {Date: "2027-01-01"}, {Date: "2027-01-02"}},
+		{{Date: "2027-01-03"}, {Date: "2027-01-04"}},
+	}
+	dir := t.TempDir()
+	plain, template := filepath.Join(dir, "plain.stl"), filepath.Join(dir, "template.stl")
+	if err := GenerateSTLRangeWithOptions([][][]types.ContributionDay{year}, plain, "testuser", 2027, 2027, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateSTLRangeWithOptions([][][]types.ContributionDay{year}, template, "testuser", 2027, 2027, Options{Template: true}); err != nil {
+		t.Fatalf("template generation failed: %v", err)
+	}
+
+	before, err := ReadSTLBinary(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := ReadSTLBinary(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(before) + 4*trianglesPerColumn; len(after) != want {
+		t.Errorf("template has %d triangles, want %d: the blank model and a dot per dated day", len(after), want)
+	}
+	if estimate := EstimateModelWithOptions([][][]types.ContributionDay{year}, "testuser", 2027, 2027, Options{Template: true}); estimate.Triangles < len(after) {
+		t.Errorf("estimate of %d triangles is below the %d generated", estimate.Triangles, len(after))
+	}
+}
+
+func TestGenerateModel(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	outputPath := filepath.Join(t.TempDir(), "model.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	written, err := ReadSTLBinary(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The model matches the file, with its parts and metadata kept.
+	model, err := GenerateModel(contributions, "testuser", 2024, 2024, Options{})
+	if err != nil {
+		t.Fatalf("GenerateModel() error = %v", err)
+	}
+	if model.TriangleCount() != len(written) || model.Metadata["user"] != "testuser" {
+		t.Errorf("GenerateModel() = %d triangles for %q, want the %d written for testuser", model.TriangleCount(), model.Metadata["user"], len(written))
+	}
+	if _, ok := model.Component("base"); !ok {
+		t.Error("GenerateModel() has no base component")
+	}
+
+	if _, err := GenerateModel(nil, "testuser", 2024, 2024, Options{}); err == nil {
+		t.Error("GenerateModel() expected error for empty contributions")
+	}
+}
@@ -4,42 +4,89 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/golang/freetype/truetype"
 )
 
 //go:embed assets/*
 var embeddedAssets embed.FS
 
-// writeTempFont writes the embedded font to a temporary file and returns its path.
-// The caller is responsible for cleaning up the temporary file.
-func writeTempFont(fontName string) (string, func(), error) {
+// Parsed fonts are shared by every text component, which render concurrently.
+var (
+	fontMu       sync.Mutex
+	parsedFonts  = map[string]*truetype.Font{}
+	fontOverride *truetype.Font
+)
+
+// embeddedFont returns the named font from the binary, parsing it on first use.
+func embeddedFont(fontName string) (*truetype.Font, error) {
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	if f, ok := parsedFonts[fontName]; ok {
+		return f, nil
+	}
+
 	fontBytes, err := embeddedAssets.ReadFile("assets/" + fontName)
 	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to read embedded font", err)
+		return nil, errors.New(errors.IOError, "failed to read embedded font", err)
 	}
-
-	// Create temp file with .ttf extension to ensure proper font loading
-	tmpFile, err := os.CreateTemp("", "skyline-font-*.ttf")
+	f, err := truetype.Parse(fontBytes)
 	if err != nil {
-		return "", nil, errors.New(errors.IOError, "failed to create temp font file", err)
+		return nil, errors.New(errors.IOError, "failed to parse embedded font", err)
 	}
+	parsedFonts[fontName] = f
+	return f, nil
+}
 
-	if _, err := tmpFile.Write(fontBytes); err != nil {
-		closeErr := tmpFile.Close()
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to write font to temp file", fmt.Errorf("%w; close error: %v; remove error: %v", err, closeErr, removeErr))
+// CheckFonts reports whether both fonts bundled in the binary can be loaded.
+func CheckFonts() error {
+	for _, name := range []string{PrimaryFont, FallbackFont} {
+		if _, err := embeddedFont(name); err != nil {
+			return errors.Wrap(err, "failed to load bundled fonts")
+		}
 	}
-	if err := tmpFile.Close(); err != nil {
-		removeErr := os.Remove(tmpFile.Name())
-		return "", nil, errors.New(errors.IOError, "failed to close temp font file", fmt.Errorf("%w; remove error: %v", err, removeErr))
+	return nil
+}
+
+// UseFont renders all text in the TrueType font at path instead of the embedded fonts.
+// An empty path restores the embedded fonts.
+func UseFont(path string) error {
+	var f *truetype.Font
+	if path != "" {
+		fontBytes, err := os.ReadFile(path)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to read font", err)
+		}
+		if f, err = truetype.Parse(fontBytes); err != nil {
+			return errors.New(errors.ValidationError, fmt.Sprintf("%s is not a TrueType font", path), err)
+		}
 	}
 
-	cleanup := func() {
-		_ = os.Remove(tmpFile.Name()) // Ignore cleanup errors in defer
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	fontOverride = f
+	return nil
+}
+
+// textFont returns the font used for text: the override if one is set, otherwise the
+// embedded primary font, falling back to the regular weight.
+func textFont() (*truetype.Font, error) {
+	fontMu.Lock()
+	override := fontOverride
+	fontMu.Unlock()
+	if override != nil {
+		return override, nil
 	}
 
-	return tmpFile.Name(), cleanup, nil
+	f, err := embeddedFont(PrimaryFont)
+	if err != nil {
+		if f, err = embeddedFont(FallbackFont); err != nil {
+			return nil, errors.New(errors.IOError, "failed to load any fonts", err)
+		}
+	}
+	return f, nil
 }
 
 // getEmbeddedImage returns a temporary file path for the embedded image.
@@ -6,43 +6,65 @@ import (
 	"testing"
 )
 
-// TestWriteTempFont verifies temporary font file creation and cleanup
-func TestWriteTempFont(t *testing.T) {
-	t.Run("verify valid font extraction", func(t *testing.T) {
-		fontPath, cleanup, err := writeTempFont("monasans-medium.ttf")
+// TestEmbeddedFont verifies the embedded fonts parse without touching the filesystem
+func TestEmbeddedFont(t *testing.T) {
+	for _, name := range []string{PrimaryFont, FallbackFont} {
+		f, err := embeddedFont(name)
 		if err != nil {
-			t.Fatalf("writeTempFont failed: %v", err)
+			t.Fatalf("embeddedFont(%q) failed: %v", name, err)
 		}
-		defer cleanup()
-
-		// Verify file exists and has content
-		content, err := os.ReadFile(fontPath)
-		if err != nil {
-			t.Errorf("Failed to read temp font file: %v", err)
-		}
-		if len(content) == 0 {
-			t.Error("Temp font file is empty")
+		if again, _ := embeddedFont(name); again != f {
+			t.Errorf("embeddedFont(%q) parsed the font again", name)
 		}
+	}
 
-		// Verify file extension
-		if filepath.Ext(fontPath) != ".ttf" {
-			t.Errorf("Expected .ttf extension, got %s", filepath.Ext(fontPath))
-		}
+	if _, err := embeddedFont("nonexistent.ttf"); err == nil {
+		t.Error("Expected error for nonexistent font")
+	}
+	if err := CheckFonts(); err != nil {
+		t.Errorf("CheckFonts() error = %v", err)
+	}
+}
 
-		// Verify cleanup works
-		cleanup()
-		if _, err := os.Stat(fontPath); !os.IsNotExist(err) {
-			t.Error("Temp font file not cleaned up properly")
-		}
-	})
+// TestUseFont verifies a font file overrides the embedded fonts until cleared
+func TestUseFont(t *testing.T) {
+	defer func() { _ = UseFont("") }()
+
+	fontBytes, err := embeddedAssets.ReadFile("assets/" + FallbackFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "custom.ttf")
+	if err := os.WriteFile(path, fontBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
 
-	t.Run("verify nonexistent font handling", func(t *testing.T) {
-		_, cleanup, err := writeTempFont("nonexistent.ttf")
-		if err == nil {
-			defer cleanup()
-			t.Error("Expected error for nonexistent font")
+	primary, err := textFont()
+	if err != nil {
+		t.Fatalf("textFont() failed: %v", err)
+	}
+	if err := UseFont(path); err != nil {
+		t.Fatalf("UseFont() failed: %v", err)
+	}
+	if custom, _ := textFont(); custom == primary {
+		t.Error("textFont() still returns the embedded font after UseFont()")
+	}
+	if err := UseFont(""); err != nil {
+		t.Fatalf("UseFont(\"\") failed: %v", err)
+	}
+	if restored, _ := textFont(); restored != primary {
+		t.Error("UseFont(\"\") did not restore the embedded font")
+	}
+
+	notAFont := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(notAFont, []byte("not a font"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{notAFont, filepath.Join(t.TempDir(), "missing.ttf")} {
+		if err := UseFont(bad); err == nil {
+			t.Errorf("UseFont(%q) expected an error", bad)
 		}
-	})
+	}
 }
 
 // TestGetEmbeddedImage verifies temporary image file creation and cleanup
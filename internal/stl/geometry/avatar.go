@@ -0,0 +1,108 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	AvatarSize   = 7.0 // Edge length of the embossed avatar in model units
+	avatarGap    = 1.5 // Gap between the avatar and the username
+	avatarPixels = 20  // Dots along each edge of the dithered avatar, about a nozzle wide each
+)
+
+// CreateAvatarGeometry embosses an avatar on the front face of the base where the username
+// would start, vertically centred; TextOptions.Avatar moves the username right to make
+// room. The image is reduced to avatarPixels dots a side and dithered, so shades become
+// patterns of raised dots. Light dots are raised, as with the logo.
+func CreateAvatarGeometry(avatar image.Image, baseWidth, baseHeight float64) ([]types.Triangle, error) {
+	if avatar == nil || avatar.Bounds().Empty() {
+		return nil, errors.New(errors.ValidationError, "avatar image is empty", nil)
+	}
+	dots := ditherImage(avatar, avatarPixels)
+	left := usernameLeftOffset * min(baseWidth, maxPanelWidth)
+	top := -(baseHeight - AvatarSize) / 2
+	dot := AvatarSize / avatarPixels
+
+	var triangles []types.Triangle
+	for y, row := range dots {
+		// Runs of raised dots in a row are merged into one box to keep the triangle count low.
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			box, err := CreateCube(left+float64(start)*dot, -voxelDepth, top-float64(y+1)*dot, float64(x-start)*dot, voxelDepth, dot)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to emboss avatar")
+			}
+			triangles = append(triangles, box...)
+		}
+	}
+	return triangles, nil
+}
+
+// avatarShift is how far the username moves right, as a share of the panel it is laid out
+// across, to make room for the avatar.
+func avatarShift(panelWidth float64) float64 {
+	return (AvatarSize + avatarGap) / panelWidth
+}
+
+// ditherImage reduces img to a size x size grid of dots, top row first, by averaging the
+// brightness of the pixels under each dot and diffusing the error of rounding it to on or
+// off onto its neighbours (Floyd-Steinberg). Transparent pixels count as dark.
+func ditherImage(img image.Image, size int) [][]bool {
+	bounds := img.Bounds()
+	levels := make([][]float64, size)
+	for y := range levels {
+		levels[y] = make([]float64, size)
+		y0, y1 := span(bounds.Min.Y, bounds.Dy(), y, size)
+		for x := range levels[y] {
+			x0, x1 := span(bounds.Min.X, bounds.Dx(), x, size)
+			sum := 0.0
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					sum += pixelIntensity(color.GrayModel.Convert(img.At(px, py)))
+				}
+			}
+			levels[y][x] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+
+	dots := make([][]bool, size)
+	for y := range dots {
+		dots[y] = make([]bool, size)
+		for x := range dots[y] {
+			level := levels[y][x]
+			dots[y][x] = level > 0.5
+			if dots[y][x] {
+				level--
+			}
+			diffuse := func(dx, dy int, share float64) {
+				if nx, ny := x+dx, y+dy; nx >= 0 && nx < size && ny < size {
+					levels[ny][nx] += level * share
+				}
+			}
+			diffuse(1, 0, 7.0/16)
+			diffuse(-1, 1, 3.0/16)
+			diffuse(0, 1, 5.0/16)
+			diffuse(1, 1, 1.0/16)
+		}
+	}
+	return dots
+}
+
+// span returns the pixels [lo, hi) of an axis starting at origin and length pixels long
+// that fall under dot i of n, at least one pixel wide.
+func span(origin, length, i, n int) (lo, hi int) {
+	lo = origin + i*length/n
+	hi = max(origin+(i+1)*length/n, lo+1)
+	return lo, min(hi, origin+length)
+}
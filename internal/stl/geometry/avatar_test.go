@@ -0,0 +1,118 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// uniformImage returns a size x size image of a single colour.
+func uniformImage(size int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDitherImage(t *testing.T) {
+	count := func(dots [][]bool) int {
+		n := 0
+		for _, row := range dots {
+			for _, on := range row {
+				if on {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	tests := []struct {
+		name     string
+		img      image.Image
+		min, max int
+	}{
+		{"white", uniformImage(64, color.White), 100, 100},
+		{"black", uniformImage(64, color.Black), 0, 0},
+		{"transparent", uniformImage(64, color.Transparent), 0, 0},
+		{"mid grey", uniformImage(64, color.Gray{Y: 128}), 45, 55},
+		{"smaller than the grid", uniformImage(3, color.White), 100, 100},
+	}
+	for _, tt := range tests {
+		dots := ditherImage(tt.img, 10)
+		if len(dots) != 10 || len(dots[9]) != 10 {
+			t.Fatalf("%s: ditherImage() returned %d rows, want 10x10", tt.name, len(dots))
+		}
+		if n := count(dots); n < tt.min || n > tt.max {
+			t.Errorf("%s: %d dots raised, want %d to %d", tt.name, n, tt.min, tt.max)
+		}
+	}
+}
+
+func TestCreateAvatarGeometry(t *testing.T) {
+	width, _ := CalculateMultiYearDimensions(1)
+	triangles, err := CreateAvatarGeometry(uniformImage(32, color.White), width, BaseHeight)
+	if err != nil {
+		t.Fatalf("CreateAvatarGeometry() error = %v", err)
+	}
+	// A white avatar is one box per row of dots.
+	if len(triangles) != 12*avatarPixels {
+		t.Errorf("got %d triangles, want %d", len(triangles), 12*avatarPixels)
+	}
+
+	minP := types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxP := types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			minP = types.Point3D{X: min(minP.X, v.X), Y: min(minP.Y, v.Y), Z: min(minP.Z, v.Z)}
+			maxP = types.Point3D{X: max(maxP.X, v.X), Y: max(maxP.Y, v.Y), Z: max(maxP.Z, v.Z)}
+		}
+	}
+	left := usernameLeftOffset * width
+	if math.Abs(minP.X-left) > epsilon || math.Abs(maxP.X-left-AvatarSize) > epsilon {
+		t.Errorf("avatar spans x %v to %v, want %v wide from %v", minP.X, maxP.X, AvatarSize, left)
+	}
+	if math.Abs(minP.Y+voxelDepth) > epsilon || math.Abs(maxP.Y) > epsilon {
+		t.Errorf("avatar spans y %v to %v, want it raised %v off the front face", minP.Y, maxP.Y, voxelDepth)
+	}
+	if margin := (BaseHeight - AvatarSize) / 2; math.Abs(maxP.Z+margin) > epsilon || math.Abs(minP.Z+BaseHeight-margin) > epsilon {
+		t.Errorf("avatar spans z %v to %v, want it centred on the face", minP.Z, maxP.Z)
+	}
+
+	if _, err := CreateAvatarGeometry(image.NewRGBA(image.Rectangle{}), width, BaseHeight); err == nil {
+		t.Error("expected error for an empty avatar")
+	}
+}
+
+func TestLayoutTextAvatar(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	plain, err := layoutText("test", "2023", width, depth, TextOptions{})
+	if err != nil {
+		t.Fatalf("layoutText() error = %v", err)
+	}
+	shifted, err := layoutText("test", "2023", width, depth, TextOptions{Avatar: true})
+	if err != nil {
+		t.Fatalf("layoutText() error = %v", err)
+	}
+	if got, want := (shifted[0].offset-plain[0].offset)*width, AvatarSize+avatarGap; math.Abs(got-want) > epsilon {
+		t.Errorf("username moved %v, want %v", got, want)
+	}
+	if shifted[1].offset != plain[1].offset {
+		t.Errorf("year offset = %v, want it left at %v", shifted[1].offset, plain[1].offset)
+	}
+
+	// A username on another face is not next to the avatar.
+	aside, err := layoutText("test", "2023", width, depth, TextOptions{Avatar: true, UsernameFace: FaceBack})
+	if err != nil {
+		t.Fatalf("layoutText() error = %v", err)
+	}
+	if aside[0].offset != 0.5 {
+		t.Errorf("username on the back offset = %v, want it centred", aside[0].offset)
+	}
+}
@@ -0,0 +1,77 @@
+package geometry
+
+import (
+	"image"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Badge layout constants. Badges sit in a row on the margin strip behind the
+// contribution grid, right-aligned with the grid's last week.
+const (
+	BadgeSize    float64 = 4.0 // Edge length of a badge icon in millimeters
+	BadgeSpacing float64 = 1.0 // Gap between neighbouring badges
+	BadgeRelief  float64 = 0.6 // Height the icon is raised above the base
+)
+
+// CreateBadgeGeometry embosses badge icons on the top of the base along its back edge.
+// White, opaque icon pixels are raised; each row of pixels is merged into as few boxes as
+// possible to keep the triangle count low. Icons that no longer fit the strip are skipped.
+func CreateBadgeGeometry(icons []image.Image, baseWidth, baseDepth float64) ([]types.Triangle, error) {
+	margin := 2 * CellSize
+	y := baseDepth - margin + (margin-BadgeSize)/2
+	right := baseWidth - margin
+
+	var triangles []types.Triangle
+	for i, icon := range icons {
+		x := right - float64(i+1)*BadgeSize - float64(i)*BadgeSpacing
+		if x < margin {
+			break
+		}
+		iconTriangles, err := embossIcon(icon, x, y, BadgeSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to emboss badge")
+		}
+		triangles = append(triangles, iconTriangles...)
+	}
+	return triangles, nil
+}
+
+// embossIcon raises the active pixels of an icon scaled into a size x size square whose
+// front-left corner is at (x, y). The top row of the image ends up at the back.
+func embossIcon(icon image.Image, x, y, size float64) ([]types.Triangle, error) {
+	bounds := icon.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, errors.New(errors.ValidationError, "badge icon is empty", nil)
+	}
+	pixelW := size / float64(bounds.Dx())
+	pixelH := size / float64(bounds.Dy())
+
+	var triangles []types.Triangle
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		rowY := y + float64(bounds.Max.Y-1-py)*pixelH
+		for px := bounds.Min.X; px < bounds.Max.X; {
+			if !isIconPixelActive(icon, px, py) {
+				px++
+				continue
+			}
+			start := px
+			for px < bounds.Max.X && isIconPixelActive(icon, px, py) {
+				px++
+			}
+			box, err := createBox(x+float64(start-bounds.Min.X)*pixelW, rowY, 0, float64(px-start)*pixelW, pixelH, BadgeRelief)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, box...)
+		}
+	}
+	return triangles, nil
+}
+
+// isIconPixelActive applies the same white-and-opaque test as the embossed logo.
+func isIconPixelActive(img image.Image, x, y int) bool {
+	r, _, _, a := img.At(x, y).RGBA()
+	return a > 32768 && r > 32768
+}
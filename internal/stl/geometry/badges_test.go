@@ -0,0 +1,76 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// testIcon returns a 4x4 icon whose first row is fully white and whose other rows are empty.
+func testIcon() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.White)
+	}
+	return img
+}
+
+func TestCreateBadgeGeometry(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	triangles, err := CreateBadgeGeometry([]image.Image{testIcon(), testIcon()}, width, depth)
+	if err != nil {
+		t.Fatalf("CreateBadgeGeometry() error = %v", err)
+	}
+	// Each icon's white row merges into a single box.
+	if len(triangles) != 2*12 {
+		t.Fatalf("got %d triangles, want %d", len(triangles), 2*12)
+	}
+
+	minX, maxX, minY, maxY := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+			minY, maxY = math.Min(minY, v.Y), math.Max(maxY, v.Y)
+			if v.Z < 0 || v.Z > BadgeRelief+epsilon {
+				t.Errorf("vertex z = %v, want within [0, %v]", v.Z, BadgeRelief)
+			}
+		}
+	}
+
+	// Badges stay on the back margin strip, right-aligned with the grid.
+	if math.Abs(maxX-(width-2*CellSize)) > epsilon {
+		t.Errorf("rightmost badge edge = %v, want %v", maxX, width-2*CellSize)
+	}
+	if minX < 2*CellSize || minY < depth-2*CellSize || maxY > depth {
+		t.Errorf("badges outside back strip: x [%v, %v], y [%v, %v]", minX, maxX, minY, maxY)
+	}
+	// The top image row is at the back of the icon.
+	if math.Abs(maxY-(depth-2*CellSize+(2*CellSize+BadgeSize)/2)) > epsilon {
+		t.Errorf("icon top row should be at the back, max y = %v", maxY)
+	}
+}
+
+func TestCreateBadgeGeometryOverflow(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	icons := make([]image.Image, 100)
+	for i := range icons {
+		icons[i] = testIcon()
+	}
+
+	triangles, err := CreateBadgeGeometry(icons, width, depth)
+	if err != nil {
+		t.Fatalf("CreateBadgeGeometry() error = %v", err)
+	}
+	fit := int((width - 4*CellSize + BadgeSpacing) / (BadgeSize + BadgeSpacing))
+	if len(triangles) != fit*12 {
+		t.Errorf("got %d triangles, want %d badges that fit", len(triangles)/12, fit)
+	}
+
+	if _, err := CreateBadgeGeometry([]image.Image{image.NewNRGBA(image.Rect(0, 0, 0, 0))}, width, depth); err == nil {
+		t.Error("expected error for an empty icon")
+	}
+}
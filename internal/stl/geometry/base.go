@@ -0,0 +1,276 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// BaseStyle selects how the vertical corners of the base slab are finished.
+type BaseStyle int
+
+// Supported base styles.
+const (
+	BaseSharp   BaseStyle = iota // Square corners
+	BaseChamfer                  // 45° chamfered corners
+	BaseRounded                  // Filleted corners
+)
+
+const (
+	// BaseCornerRadius is the size of a chamfer or fillet. It stays within the margin
+	// around the contribution grid, so columns are never clipped.
+	BaseCornerRadius float64 = CellSize
+
+	// filletSegments is the number of flat sides approximating each rounded corner.
+	filletSegments = 8
+)
+
+// ParseBaseStyle converts a flag value ("sharp", "chamfer" or "rounded") into a BaseStyle.
+func ParseBaseStyle(name string) (BaseStyle, error) {
+	switch strings.ToLower(name) {
+	case "", "sharp":
+		return BaseSharp, nil
+	case "chamfer":
+		return BaseChamfer, nil
+	case "rounded":
+		return BaseRounded, nil
+	default:
+		return BaseSharp, fmt.Errorf("unknown base style %q (expected sharp, chamfer or rounded)", name)
+	}
+}
+
+// BaseFootprint selects what the base stands on.
+type BaseFootprint int
+
+// Supported base footprints.
+const (
+	FootprintFlat       BaseFootprint = iota // A flat underside sized to the contribution grid
+	FootprintGridfinity                      // Whole Gridfinity units with a foot under each
+)
+
+// ParseBaseFootprint converts a flag value ("flat" or "gridfinity") into a BaseFootprint.
+func ParseBaseFootprint(name string) (BaseFootprint, error) {
+	switch strings.ToLower(name) {
+	case "", "flat":
+		return FootprintFlat, nil
+	case "gridfinity":
+		return FootprintGridfinity, nil
+	default:
+		return FootprintFlat, fmt.Errorf("unknown base %q (expected flat or gridfinity)", name)
+	}
+}
+
+// BaseOptions describes how the base slab is shaped. The zero value is a plain cuboid.
+type BaseOptions struct {
+	Style       BaseStyle     // Corner finish
+	Connectors  bool          // Pegs on the right face and matching sockets in the left face
+	StudSockets bool          // Anti-stud sockets underneath, so the base clips onto brick baseplates
+	Footprint   BaseFootprint // Underside of the base; Gridfinity bases must be sized with GridfinitySize
+}
+
+// CreateStyledBase generates triangles for the base slab with the given shape.
+// A sharp base without connectors is identical to CreateCuboidBase.
+func CreateStyledBase(width, depth float64, opts BaseOptions) ([]types.Triangle, error) {
+	return createSlab(width, depth, BaseHeight, opts, slabInsets{})
+}
+
+// slabInsets are the thicknesses left out of each side of the slab, where engraved
+// skins are added instead.
+type slabInsets struct {
+	front, back, left, right float64
+
+	// strip is the depth of a strip along the front of the top face left out voxelDepth
+	// deep, between the side insets or corners and behind the front inset.
+	strip float64
+
+	// margin is the width of a strip along the left of the top face left out voxelDepth
+	// deep, right of the left inset or corners and between the front and back insets.
+	margin float64
+}
+
+// createSlab builds the base slab between Z = -baseHeight and Z = 0, adding connectors
+// and Gridfinity feet when requested. The sides in insets are left out for the caller to fill.
+func createSlab(width, depth, baseHeight float64, opts BaseOptions, in slabInsets) ([]types.Triangle, error) {
+	r := opts.Style.cornerRadius()
+	if 2*r >= width || 2*r >= depth {
+		return nil, errors.New(errors.ValidationError, "base is too small for its corner style", nil)
+	}
+
+	if opts.Footprint == FootprintGridfinity {
+		if opts.StudSockets {
+			return nil, errors.New(errors.ValidationError, "stud sockets cannot be combined with Gridfinity feet", nil)
+		}
+		feet, err := createGridfinityFeet(width, depth, -baseHeight)
+		if err != nil {
+			return nil, err
+		}
+		opts.Footprint = FootprintFlat
+		slab, err := createSlab(width, depth, baseHeight, opts, in)
+		if err != nil {
+			return nil, err
+		}
+		return append(slab, feet...), nil
+	}
+
+	if !opts.Connectors {
+		return createBody(width, depth, baseHeight, opts, in)
+	}
+	if in.left > 0 || in.right > 0 {
+		return nil, errors.New(errors.ValidationError, "connectors need the left and right faces free of text", nil)
+	}
+
+	in.left = socketDepth
+	triangles, err := createBody(width, depth, baseHeight, opts, in)
+	if err != nil {
+		return nil, err
+	}
+	connectors, err := createConnectors(width, depth, baseHeight, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create connectors")
+	}
+	return append(triangles, connectors...), nil
+}
+
+// createBody builds the slab without the inset sides, leaving a layer of stud sockets
+// across the bottom when requested. Front and left strips are left out of a separate top
+// layer.
+func createBody(width, depth, baseHeight float64, opts BaseOptions, in slabInsets) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	zTop := 0.0
+	if in.strip > 0 || in.margin > 0 {
+		top := in
+		top.front = max(in.front, in.strip)
+		layer, err := createCore(width, depth, -voxelDepth, 0, opts.Style, top, socketGrid{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create base top")
+		}
+		triangles, zTop = layer, -voxelDepth
+		in.margin = 0
+	}
+
+	if !opts.StudSockets {
+		core, err := createCore(width, depth, -baseHeight, zTop, opts.Style, in, socketGrid{})
+		if err != nil {
+			return nil, err
+		}
+		return append(triangles, core...), nil
+	}
+
+	r := opts.Style.cornerRadius()
+	grid, err := studSocketGrid(max(r, in.left), width-max(r, in.right), in.front, depth-in.back)
+	if err != nil {
+		return nil, err
+	}
+	layerTop := -baseHeight + StudSocketDepth
+	core, err := createCore(width, depth, layerTop, zTop, opts.Style, in, socketGrid{})
+	if err != nil {
+		return nil, err
+	}
+	layer, err := createCore(width, depth, -baseHeight, layerTop, opts.Style, in, grid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create stud sockets")
+	}
+	return slices.Concat(triangles, core, layer), nil
+}
+
+// createCore builds the part of the slab between zBottom and zTop without the inset sides,
+// with the sockets of grid left out of its central box. Styled slabs are split into a
+// central cross of boxes and four convex corner pieces, so each side's flat span can be
+// inset independently of the corners.
+func createCore(width, depth, zBottom, zTop float64, style BaseStyle, in slabInsets, grid socketGrid) ([]types.Triangle, error) {
+	height := zTop - zBottom
+	r := style.cornerRadius()
+	if r == 0 {
+		left := max(in.left, in.margin)
+		return createPerforatedBox(left, in.front, zBottom, width-left-in.right, depth-in.front-in.back, height, grid)
+	}
+
+	left := max(r, in.margin)
+	triangles, err := createPerforatedBox(left, in.front, zBottom, width-left-r, depth-in.front-in.back, height, grid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create base slab")
+	}
+	boxes := [][6]float64{
+		{in.left, r, zBottom, r - in.left, depth - 2*r, height},
+		{width - r, r, zBottom, r - in.right, depth - 2*r, height},
+	}
+	for _, b := range boxes {
+		box, err := createBox(b[0], b[1], b[2], b[3], b[4], b[5])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create base slab")
+		}
+		triangles = append(triangles, box...)
+	}
+
+	segments := 1
+	if style == BaseRounded {
+		segments = filletSegments
+	}
+	corners := []struct {
+		x, y, angle float64 // Center of the corner piece and the start of its arc
+	}{
+		{r, r, math.Pi},               // front left
+		{width - r, r, 1.5 * math.Pi}, // front right
+		{width - r, depth - r, 0},     // back right
+		{r, depth - r, 0.5 * math.Pi}, // back left
+	}
+	for _, c := range corners {
+		profile := []point2XY{{c.x, c.y}}
+		for k := 0; k <= segments; k++ {
+			angle := c.angle + float64(k)*math.Pi/2/float64(segments)
+			profile = append(profile, point2XY{c.x + r*math.Cos(angle), c.y + r*math.Sin(angle)})
+		}
+		corner, err := extrudeConvex(profile, zBottom, zTop)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create base corner")
+		}
+		triangles = append(triangles, corner...)
+	}
+	return triangles, nil
+}
+
+// cornerRadius returns the size of the corner finish, or 0 for square corners.
+func (s BaseStyle) cornerRadius() float64 {
+	if s == BaseSharp {
+		return 0
+	}
+	return BaseCornerRadius
+}
+
+// point2XY is a point in the XY plane.
+type point2XY struct {
+	X, Y float64
+}
+
+// extrudeConvex extrudes a convex polygon, counter-clockwise when viewed from above,
+// between zBottom and zTop. Caps are fanned from the first vertex.
+func extrudeConvex(profile []point2XY, zBottom, zTop float64) ([]types.Triangle, error) {
+	at := func(p point2XY, z float64) types.Point3D { return types.Point3D{X: p.X, Y: p.Y, Z: z} }
+
+	var triangles []types.Triangle
+	for i := 1; i+1 < len(profile); i++ {
+		for _, tri := range [][3]types.Point3D{
+			{at(profile[0], zTop), at(profile[i], zTop), at(profile[i+1], zTop)},
+			{at(profile[0], zBottom), at(profile[i+1], zBottom), at(profile[i], zBottom)},
+		} {
+			normal, err := calculateNormal(tri[0], tri[1], tri[2])
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, types.Triangle{Normal: normal, V1: tri[0], V2: tri[1], V3: tri[2]})
+		}
+	}
+	for i := range profile {
+		next := profile[(i+1)%len(profile)]
+		side, err := CreateQuad(at(profile[i], zBottom), at(next, zBottom), at(next, zTop), at(profile[i], zTop))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, side...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,127 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateStyledBase(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	sharp, err := CreateStyledBase(width, depth, BaseOptions{})
+	if err != nil {
+		t.Fatalf("CreateStyledBase(sharp) error = %v", err)
+	}
+	cuboid, err := CreateCuboidBase(width, depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sharp) != len(cuboid) {
+		t.Errorf("sharp base has %d triangles, want %d", len(sharp), len(cuboid))
+	}
+
+	tests := []struct {
+		style         BaseStyle
+		wantTriangles int
+	}{
+		// Three boxes plus four corner prisms; a chamfer is a triangle, a fillet a fan.
+		{BaseChamfer, 3*12 + 4*(2*1+2*3)},
+		{BaseRounded, 3*12 + 4*(2*filletSegments+2*(filletSegments+2))},
+	}
+	for _, tt := range tests {
+		triangles, err := CreateStyledBase(width, depth, BaseOptions{Style: tt.style})
+		if err != nil {
+			t.Fatalf("CreateStyledBase(%d) error = %v", tt.style, err)
+		}
+		if len(triangles) != tt.wantTriangles {
+			t.Errorf("style %d: got %d triangles, want %d", tt.style, len(triangles), tt.wantTriangles)
+		}
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				// No vertex lies in the corner the finish cuts away.
+				dx := math.Max(BaseCornerRadius-v.X, v.X-(width-BaseCornerRadius))
+				dy := math.Max(BaseCornerRadius-v.Y, v.Y-(depth-BaseCornerRadius))
+				if dx > epsilon && dy > epsilon && math.Hypot(dx, dy) > BaseCornerRadius+epsilon {
+					t.Fatalf("style %d: vertex %v lies outside the corner finish", tt.style, v)
+				}
+			}
+		}
+	}
+
+	if _, err := CreateStyledBase(4, 4, BaseOptions{Style: BaseRounded}); err == nil {
+		t.Error("expected error for a base smaller than its corners")
+	}
+}
+
+func TestExtrudeConvexNormals(t *testing.T) {
+	profile := []point2XY{{0, 0}, {2, 0}, {3, 2}, {1, 3}}
+	triangles, err := extrudeConvex(profile, -1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	center := types.Point3D{X: 1.5, Y: 1.25, Z: 0}
+	for i, tri := range triangles {
+		mid := types.Point3D{
+			X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3,
+			Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3,
+			Z: (tri.V1.Z + tri.V2.Z + tri.V3.Z) / 3,
+		}
+		outward := vectorSubtract(mid, center)
+		if dot := tri.Normal.X*outward.X + tri.Normal.Y*outward.Y + tri.Normal.Z*outward.Z; dot <= 0 {
+			t.Errorf("triangle %d normal %v points inward", i, tri.Normal)
+		}
+	}
+}
+
+func TestParseBaseStyle(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BaseStyle
+		wantErr bool
+	}{
+		{"", BaseSharp, false},
+		{"sharp", BaseSharp, false},
+		{"Chamfer", BaseChamfer, false},
+		{"rounded", BaseRounded, false},
+		{"beveled", BaseSharp, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBaseStyle(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBaseStyle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBaseStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBaseFootprint(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BaseFootprint
+		wantErr bool
+	}{
+		{"", FootprintFlat, false},
+		{"flat", FootprintFlat, false},
+		{"Gridfinity", FootprintGridfinity, false},
+		{"pegboard", FootprintFlat, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBaseFootprint(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBaseFootprint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBaseFootprint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
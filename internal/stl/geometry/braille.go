@@ -0,0 +1,160 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Braille dimensions follow common signage guidelines. All measurements are in millimeters.
+const (
+	BrailleDotDiameter float64 = 1.5 // Diameter of a dot where it meets the face
+	BrailleDotHeight   float64 = 0.6 // Distance a dot rises out of the face
+	BrailleDotSpacing  float64 = 2.5 // Distance between dot centers within a cell
+	BrailleCellSpacing float64 = 6.0 // Distance between the first dots of neighbouring cells
+	BrailleMargin      float64 = 2 * CellSize
+
+	brailleDotSegments = 8 // Sides of the polygon approximating each dot
+)
+
+// Braille cells are bitmasks of dots 1-6, with dot n stored in bit n-1.
+// Dots 1-3 run down the left column and dots 4-6 down the right column.
+const (
+	brailleNumberSign uint8 = 0b111100 // Dots 3-4-5-6
+	brailleLetterSign uint8 = 0b110000 // Dots 5-6, ends number mode before a-j
+	brailleHyphen     uint8 = 0b100100 // Dots 3-6
+	brailleSpace      uint8 = 0
+)
+
+// brailleLetters maps lowercase letters to their Grade-1 Braille cells.
+var brailleLetters = map[rune]uint8{
+	'a': 0b000001, 'b': 0b000011, 'c': 0b001001, 'd': 0b011001, 'e': 0b010001,
+	'f': 0b001011, 'g': 0b011011, 'h': 0b010011, 'i': 0b001010, 'j': 0b011010,
+	'k': 0b000101, 'l': 0b000111, 'm': 0b001101, 'n': 0b011101, 'o': 0b010101,
+	'p': 0b001111, 'q': 0b011111, 'r': 0b010111, 's': 0b001110, 't': 0b011110,
+	'u': 0b100101, 'v': 0b100111, 'w': 0b111010, 'x': 0b101101, 'y': 0b111101,
+	'z': 0b110101,
+}
+
+// BrailleCells transcribes text into Grade-1 (uncontracted) Braille cells.
+// Letters are case-insensitive; digits use the number sign and a letter sign is
+// inserted when a letter a-j directly follows a number. Only letters, digits,
+// hyphens and spaces are supported, which covers GitHub usernames and year ranges.
+func BrailleCells(text string) ([]uint8, error) {
+	var cells []uint8
+	numberMode := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= '0' && r <= '9':
+			if !numberMode {
+				cells = append(cells, brailleNumberSign)
+				numberMode = true
+			}
+			// Digits 1-9 and 0 share the cells of letters a-j.
+			cells = append(cells, brailleLetters[rune('a'+(r-'0'+9)%10)])
+		case brailleLetters[r] != 0:
+			if numberMode && r <= 'j' {
+				cells = append(cells, brailleLetterSign)
+			}
+			numberMode = false
+			cells = append(cells, brailleLetters[r])
+		case r == '-':
+			numberMode = false
+			cells = append(cells, brailleHyphen)
+		case r == ' ':
+			numberMode = false
+			cells = append(cells, brailleSpace)
+		default:
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("character %q has no Braille transcription", r), nil)
+		}
+	}
+	return cells, nil
+}
+
+// CreateBrailleGeometry embosses text in Grade-1 Braille, centered on the given face of the base.
+// baseDepth is only needed for the left and right faces.
+func CreateBrailleGeometry(text string, face Face, baseWidth, baseDepth, baseHeight float64) ([]types.Triangle, error) {
+	cells, err := BrailleCells(text)
+	if err != nil {
+		return nil, err
+	}
+
+	faceWidth := baseWidth
+	if face == FaceLeft || face == FaceRight {
+		faceWidth = baseDepth
+	}
+	// The last cell only adds the width of its own two columns.
+	lineWidth := float64(len(cells)-1)*BrailleCellSpacing + BrailleDotSpacing + BrailleDotDiameter
+	if len(cells) == 0 || lineWidth > faceWidth-2*BrailleMargin {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("%q does not fit on the base in Braille", text), nil)
+	}
+	if 2*BrailleDotSpacing+BrailleDotDiameter > baseHeight {
+		return nil, errors.New(errors.ValidationError, "base is too thin for Braille", nil)
+	}
+
+	// Front-face coordinates: X runs left to right, Z from the top of the base down.
+	left := (faceWidth-lineWidth)/2 + BrailleDotDiameter/2
+	top := -(baseHeight - 2*BrailleDotSpacing) / 2
+
+	var triangles []types.Triangle
+	for i, cell := range cells {
+		for dot := 0; dot < 6; dot++ {
+			if cell&(1<<dot) == 0 {
+				continue
+			}
+			x := left + float64(i)*BrailleCellSpacing + float64(dot/3)*BrailleDotSpacing
+			z := top - float64(dot%3)*BrailleDotSpacing
+			dotTriangles, err := createBrailleDot(x, z)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create Braille dot")
+			}
+			triangles = append(triangles, dotTriangles...)
+		}
+	}
+
+	return placeOnFace(triangles, face, baseWidth, baseDepth), nil
+}
+
+// createBrailleDot builds a rounded-off dot centered at (x, z) on the front face, as a
+// polygonal frustum rising towards -Y with a top half as wide as its foot.
+func createBrailleDot(x, z float64) ([]types.Triangle, error) {
+	ring := func(radius, y float64) []types.Point3D {
+		points := make([]types.Point3D, brailleDotSegments)
+		for k := range points {
+			angle := 2 * math.Pi * float64(k) / brailleDotSegments
+			points[k] = types.Point3D{X: x + radius*math.Cos(angle), Y: y, Z: z + radius*math.Sin(angle)}
+		}
+		return points
+	}
+	bottom := ring(BrailleDotDiameter/2, 0)
+	top := ring(BrailleDotDiameter/4, -BrailleDotHeight)
+	topCenter := types.Point3D{X: x, Y: -BrailleDotHeight, Z: z}
+	bottomCenter := types.Point3D{X: x, Y: 0, Z: z}
+
+	triangles := make([]types.Triangle, 0, 4*brailleDotSegments)
+	for k := 0; k < brailleDotSegments; k++ {
+		next := (k + 1) % brailleDotSegments
+
+		side, err := CreateQuad(bottom[k], bottom[next], top[next], top[k])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, side...)
+
+		// Caps are fanned from their centers: the top faces the viewer, the bottom the base.
+		for _, tri := range [][3]types.Point3D{
+			{topCenter, top[k], top[next]},
+			{bottomCenter, bottom[next], bottom[k]},
+		} {
+			normal, err := calculateNormal(tri[0], tri[1], tri[2])
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, types.Triangle{Normal: normal, V1: tri[0], V2: tri[1], V3: tri[2]})
+		}
+	}
+	return triangles, nil
+}
@@ -0,0 +1,96 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestBrailleCells(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []uint8
+		wantErr bool
+	}{
+		{"ab", []uint8{0b000001, 0b000011}, false},
+		{"AB", []uint8{0b000001, 0b000011}, false},
+		{"2024", []uint8{brailleNumberSign, 0b000011, 0b011010, 0b000011, 0b011001}, false},
+		{"2020-24", []uint8{brailleNumberSign, 0b000011, 0b011010, 0b000011, 0b011010, brailleHyphen, brailleNumberSign, 0b000011, 0b011001}, false},
+		{"a1b", []uint8{0b000001, brailleNumberSign, 0b000001, brailleLetterSign, 0b000011}, false},
+		{"a1z", []uint8{0b000001, brailleNumberSign, 0b000001, 0b110101}, false},
+		{"a b", []uint8{0b000001, brailleSpace, 0b000011}, false},
+		{"a_b", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := BrailleCells(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BrailleCells() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("BrailleCells() = %06b, want %06b", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("cell %d = %06b, want %06b", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateBrailleGeometry(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	// "a" is a single dot.
+	triangles, err := CreateBrailleGeometry("a", FaceFront, width, depth, BaseHeight)
+	if err != nil {
+		t.Fatalf("CreateBrailleGeometry() error = %v", err)
+	}
+	if len(triangles) != 4*brailleDotSegments {
+		t.Fatalf("got %d triangles, want %d", len(triangles), 4*brailleDotSegments)
+	}
+
+	triangles, err = CreateBrailleGeometry("octocat 2024", FaceBack, width, depth, BaseHeight)
+	if err != nil {
+		t.Fatalf("CreateBrailleGeometry() error = %v", err)
+	}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Y < depth-epsilon || v.Y > depth+BrailleDotHeight+epsilon {
+				t.Fatalf("vertex %v is not on the back face", v)
+			}
+			if v.Z > epsilon || v.Z < -BaseHeight-epsilon || v.X < 0 || v.X > width {
+				t.Fatalf("vertex %v is outside the back face", v)
+			}
+		}
+	}
+
+	if _, err := CreateBrailleGeometry("a-username-that-is-far-too-long", FaceFront, width, depth, BaseHeight); err == nil {
+		t.Error("expected error for text that does not fit")
+	}
+}
+
+func TestCreateBrailleDotNormals(t *testing.T) {
+	triangles, err := createBrailleDot(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	center := types.Point3D{X: 0, Y: -BrailleDotHeight / 2, Z: 0}
+	for i, tri := range triangles {
+		mid := types.Point3D{
+			X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3,
+			Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3,
+			Z: (tri.V1.Z + tri.V2.Z + tri.V3.Z) / 3,
+		}
+		outward := vectorSubtract(mid, center)
+		if dot := tri.Normal.X*outward.X + tri.Normal.Y*outward.Y + tri.Normal.Z*outward.Z; dot <= 0 {
+			t.Errorf("triangle %d normal %v points inward", i, tri.Normal)
+		}
+		if length := math.Sqrt(tri.Normal.X*tri.Normal.X + tri.Normal.Y*tri.Normal.Y + tri.Normal.Z*tri.Normal.Z); math.Abs(length-1) > epsilon {
+			t.Errorf("triangle %d normal is not unit length", i)
+		}
+	}
+}
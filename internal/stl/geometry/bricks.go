@@ -0,0 +1,168 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Brick module dimensions. Columns are built from stacked modules with the proportions of
+// a toy brick, scaled to a cell, and topped with a stud.
+// All measurements are in millimeters.
+const (
+	BrickModuleHeight float64 = 1.2 * CellSize // Height of one stacked module
+	BrickStudDiameter float64 = 0.6 * CellSize // Diameter of the stud on top of a column
+	BrickStudHeight   float64 = 0.2 * CellSize // Height of the stud on top of a column
+
+	// brickJoint is the depth and height of the groove between stacked modules.
+	brickJoint = 0.1 * CellSize
+
+	// brickStudSides is the number of flat sides approximating a stud.
+	brickStudSides = 8
+)
+
+// Stud socket dimensions. Square sockets on the standard brick pitch fit over the round
+// studs of a baseplate, so a printed base clips on.
+const (
+	StudPitch       float64 = 8.0 // Distance between neighbouring studs on a baseplate
+	StudSocketSize  float64 = 4.9 // Width of a socket: a stud's diameter plus clearance
+	StudSocketDepth float64 = 1.8 // Depth of a socket: a stud's height plus clearance
+
+	// studSocketWall is the least material left between a socket and the side of the base.
+	studSocketWall = 1.0
+)
+
+// BrickModules returns the number of modules stacked for a column of the given height,
+// or 0 for an empty day.
+func BrickModules(height float64) int {
+	if height <= 0 {
+		return 0
+	}
+	return max(1, int(math.Round(height/BrickModuleHeight)))
+}
+
+// BrickTriangleCount returns the number of triangles CreateBrickGeometry produces for a
+// column of the given height.
+func BrickTriangleCount(height float64) int {
+	modules := BrickModules(height)
+	if modules == 0 {
+		return 0
+	}
+	// Each module and the groove below it are boxes; the stud is a prism with fanned caps.
+	return modules*12 + (modules-1)*12 + 2*(brickStudSides-2) + 2*brickStudSides
+}
+
+// CreateBrickGeometry generates a single year's contributions as columns of stacked brick
+// modules, each column rounded to a whole number of modules and topped with a stud.
+func CreateBrickGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			modules := BrickModules(NormalizeContribution(day.ContributionCount, maxContrib))
+			if modules == 0 {
+				continue
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+
+			for k := range modules {
+				z := float64(k) * BrickModuleHeight
+				height := BrickModuleHeight
+				if k > 0 {
+					groove, err := createBox(x+brickJoint, y+brickJoint, z, CellSize-2*brickJoint, CellSize-2*brickJoint, brickJoint)
+					if err != nil {
+						return nil, err
+					}
+					triangles = append(triangles, groove...)
+					z += brickJoint
+					height -= brickJoint
+				}
+				module, err := createBox(x, y, z, CellSize, CellSize, height)
+				if err != nil {
+					return nil, err
+				}
+				triangles = append(triangles, module...)
+			}
+
+			profile := make([]point2XY, brickStudSides)
+			for i := range profile {
+				angle := 2 * math.Pi * float64(i) / brickStudSides
+				profile[i] = point2XY{x + CellSize/2 + BrickStudDiameter/2*math.Cos(angle), y + CellSize/2 + BrickStudDiameter/2*math.Sin(angle)}
+			}
+			top := float64(modules) * BrickModuleHeight
+			stud, err := extrudeConvex(profile, top, top+BrickStudHeight)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, stud...)
+		}
+	}
+
+	return triangles, nil
+}
+
+// socketGrid is a grid of square sockets, one at each combination of the centre
+// coordinates. The zero value has no sockets.
+type socketGrid struct {
+	xs, ys []float64
+	size   float64
+}
+
+// studSocketGrid centres as many stud sockets as fit on the brick pitch within the given
+// bounds, keeping studSocketWall clear of each side.
+func studSocketGrid(x0, x1, y0, y1 float64) (socketGrid, error) {
+	centres := func(lo, hi float64) []float64 {
+		span := hi - lo - 2*studSocketWall - StudSocketSize
+		if span < 0 {
+			return nil
+		}
+		n := int(span/StudPitch) + 1
+		first := (lo+hi)/2 - float64(n-1)*StudPitch/2
+		positions := make([]float64, n)
+		for i := range positions {
+			positions[i] = first + float64(i)*StudPitch
+		}
+		return positions
+	}
+
+	grid := socketGrid{xs: centres(x0, x1), ys: centres(y0, y1), size: StudSocketSize}
+	if len(grid.xs) == 0 || len(grid.ys) == 0 {
+		return socketGrid{}, errors.New(errors.ValidationError, "base is too small for stud sockets", nil)
+	}
+	return grid, nil
+}
+
+// createPerforatedBox builds a box with the sockets of grid cut through it from bottom to
+// top. The box is split into bands between the rows of sockets and blocks between the
+// sockets of each row; without sockets it is a plain box.
+func createPerforatedBox(x, y, z, width, depth, height float64, grid socketGrid) ([]types.Triangle, error) {
+	if len(grid.xs) == 0 || len(grid.ys) == 0 {
+		return createBox(x, y, z, width, depth, height)
+	}
+
+	half := grid.size / 2
+	var boxes [][6]float64
+	cursorY := y
+	for _, cy := range grid.ys {
+		boxes = append(boxes, [6]float64{x, cursorY, z, width, cy - half - cursorY, height})
+		cursorX := x
+		for _, cx := range grid.xs {
+			boxes = append(boxes, [6]float64{cursorX, cy - half, z, cx - half - cursorX, grid.size, height})
+			cursorX = cx + half
+		}
+		boxes = append(boxes, [6]float64{cursorX, cy - half, z, x + width - cursorX, grid.size, height})
+		cursorY = cy + half
+	}
+	boxes = append(boxes, [6]float64{x, cursorY, z, width, y + depth - cursorY, height})
+
+	var triangles []types.Triangle
+	for _, b := range boxes {
+		box, err := createBox(b[0], b[1], b[2], b[3], b[4], b[5])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, box...)
+	}
+	return triangles, nil
+}
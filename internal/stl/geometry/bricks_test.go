@@ -0,0 +1,92 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestBrickModules(t *testing.T) {
+	tests := []struct {
+		height float64
+		want   int
+	}{
+		{0, 0},
+		{MinHeight, 1},
+		{3.4 * BrickModuleHeight, 3},
+		{MaxHeight, 8},
+	}
+	for _, tt := range tests {
+		if got := BrickModules(tt.height); got != tt.want {
+			t.Errorf("BrickModules(%v) = %d, want %d", tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestCreateBrickGeometry(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{{ContributionCount: 0}, {ContributionCount: 10}},
+		{{ContributionCount: 1}},
+	}
+
+	triangles, err := CreateBrickGeometry(contributions, 0, 10)
+	if err != nil {
+		t.Fatalf("CreateBrickGeometry() error = %v", err)
+	}
+	want := BrickTriangleCount(NormalizeContribution(10, 10)) + BrickTriangleCount(NormalizeContribution(1, 10))
+	if len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+
+	top := 0.0
+	for _, tri := range triangles {
+		top = math.Max(top, math.Max(tri.V1.Z, math.Max(tri.V2.Z, tri.V3.Z)))
+	}
+	if want := float64(BrickModules(MaxHeight))*BrickModuleHeight + BrickStudHeight; math.Abs(top-want) > epsilon {
+		t.Errorf("tallest column reaches %v, want %v", top, want)
+	}
+}
+
+func TestCreateStyledBaseStudSockets(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	for _, style := range []BaseStyle{BaseSharp, BaseRounded} {
+		triangles, err := CreateStyledBase(width, depth, BaseOptions{Style: style, StudSockets: true})
+		if err != nil {
+			t.Fatalf("CreateStyledBase(%d) error = %v", style, err)
+		}
+
+		grid, err := studSocketGrid(style.cornerRadius(), width-style.cornerRadius(), 0, depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(grid.xs) != 17 || len(grid.ys) != 3 {
+			t.Errorf("style %d: %dx%d sockets, want 17x3", style, len(grid.xs), len(grid.ys))
+		}
+
+		// No bottom face may cover a socket.
+		for _, tri := range triangles {
+			if tri.V1.Z != -BaseHeight || tri.V2.Z != -BaseHeight || tri.V3.Z != -BaseHeight {
+				continue
+			}
+			for _, x := range grid.xs {
+				for _, y := range grid.ys {
+					if insideTriangle(tri, x, y) {
+						t.Fatalf("style %d: socket at (%v, %v) is covered by a bottom face", style, x, y)
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := CreateStyledBase(6, 6, BaseOptions{StudSockets: true}); err == nil {
+		t.Error("expected an error for a base too small for stud sockets")
+	}
+}
+
+// insideTriangle reports whether (x, y) lies strictly inside tri projected onto the XY plane.
+func insideTriangle(tri types.Triangle, x, y float64) bool {
+	side := func(a, b types.Point3D) float64 { return (b.X-a.X)*(y-a.Y) - (b.Y-a.Y)*(x-a.X) }
+	d1, d2, d3 := side(tri.V1, tri.V2), side(tri.V2, tri.V3), side(tri.V3, tri.V1)
+	return (d1 > 0 && d2 > 0 && d3 > 0) || (d1 < 0 && d2 < 0 && d3 < 0)
+}
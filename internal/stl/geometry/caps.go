@@ -0,0 +1,150 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TowerCap selects the shape each day's column is topped with.
+type TowerCap int
+
+// Supported tower caps.
+const (
+	CapFlat    TowerCap = iota // Columns end in their flat top face
+	CapPyramid                 // A square pyramid on each column
+	CapDome                    // A dome on each column, square at its foot and round in profile
+)
+
+const (
+	// TowerCapHeight is the height of the cap on a column.
+	TowerCapHeight = CellSize / 3
+
+	// AccentCapHeight is the height of the cap on the last active day of each week, the
+	// day the ASCII preview draws with a top block. It stays below HighlightCapHeight, so
+	// personal records still stand out.
+	AccentCapHeight = 2 * CellSize / 3
+
+	// domeBands is the number of bands the profile of a dome is approximated with.
+	domeBands = 4
+)
+
+// ParseTowerCap converts a flag value ("flat", "pyramid" or "dome") into a TowerCap.
+func ParseTowerCap(name string) (TowerCap, error) {
+	switch strings.ToLower(name) {
+	case "", "flat":
+		return CapFlat, nil
+	case "pyramid":
+		return CapPyramid, nil
+	case "dome":
+		return CapDome, nil
+	default:
+		return CapFlat, fmt.Errorf("unknown tower cap %q (expected flat, pyramid or dome)", name)
+	}
+}
+
+// Triangles returns the number of triangles in each cap of the shape.
+func (c TowerCap) Triangles() int {
+	switch c {
+	case CapPyramid:
+		return HighlightCapTriangles
+	case CapDome:
+		// The foot, a band of four quads below each ring but the top, and the crown.
+		return 2 + 8*(domeBands-1) + 4
+	default:
+		return 0
+	}
+}
+
+// CreateTowerCaps generates a cap of the given shape on top of the column of each active
+// day of a year, except days in skip, which carry a cap of their own. The last active day
+// of each week, which tops the week's column in the ASCII preview, is accented with a cap
+// twice as tall. Flat caps generate nothing. Each cap is a closed solid standing on the
+// column's top face.
+func CreateTowerCaps(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, shape TowerCap, skip map[string]bool) ([]types.Triangle, error) {
+	if shape == CapFlat {
+		return nil, nil
+	}
+	var triangles []types.Triangle
+	for weekIdx, week := range contributions {
+		last := -1
+		for dayIdx, day := range week {
+			if day.ContributionCount > 0 {
+				last = dayIdx
+			}
+		}
+		for dayIdx, day := range week {
+			if day.ContributionCount <= 0 || skip[day.Date] {
+				continue
+			}
+			height := TowerCapHeight
+			if dayIdx == last {
+				height = AccentCapHeight
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+			z := NormalizeContribution(day.ContributionCount, maxContrib)
+			var mesh []types.Triangle
+			var err error
+			if shape == CapDome {
+				mesh, err = createDome(x, y, z, CellSize, height)
+			} else {
+				mesh, err = createPyramid(x, y, z, CellSize, height)
+			}
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, mesh...)
+		}
+	}
+	return triangles, nil
+}
+
+// createDome generates a dome whose square foot, size wide with its front-left corner at
+// (x, y), lies at height z. Its cross-sections are squares that shrink like the slices of
+// a hemisphere, height tall, to a point above the centre.
+func createDome(x, y, z, size, height float64) ([]types.Triangle, error) {
+	cx, cy := x+size/2, y+size/2
+	// ring returns the corners of the square i bands up, counter-clockwise seen from above.
+	ring := func(i int) [4]types.Point3D {
+		angle := float64(i) * math.Pi / 2 / domeBands
+		half, level := size/2*math.Cos(angle), z+height*math.Sin(angle)
+		return [4]types.Point3D{
+			{X: cx - half, Y: cy - half, Z: level},
+			{X: cx + half, Y: cy - half, Z: level},
+			{X: cx + half, Y: cy + half, Z: level},
+			{X: cx - half, Y: cy + half, Z: level},
+		}
+	}
+	apex := types.Point3D{X: cx, Y: cy, Z: z + height}
+
+	foot := ring(0)
+	faces := [][3]types.Point3D{
+		{foot[0], foot[2], foot[1]},
+		{foot[0], foot[3], foot[2]},
+	}
+	lower := foot
+	for i := 1; i < domeBands; i++ {
+		upper := ring(i)
+		for j := range lower {
+			k := (j + 1) % 4
+			faces = append(faces, [3]types.Point3D{lower[j], lower[k], upper[k]}, [3]types.Point3D{lower[j], upper[k], upper[j]})
+		}
+		lower = upper
+	}
+	for j := range lower {
+		faces = append(faces, [3]types.Point3D{lower[j], lower[(j+1)%4], apex})
+	}
+
+	triangles := make([]types.Triangle, 0, len(faces))
+	for _, face := range faces {
+		normal, err := calculateNormal(face[0], face[1], face[2])
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create tower cap", err)
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: face[0], V2: face[1], V3: face[2]})
+	}
+	return triangles, nil
+}
@@ -0,0 +1,66 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseTowerCap(t *testing.T) {
+	for name, want := range map[string]TowerCap{"": CapFlat, "flat": CapFlat, "Pyramid": CapPyramid, "dome": CapDome} {
+		if got, err := ParseTowerCap(name); err != nil || got != want {
+			t.Errorf("ParseTowerCap(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseTowerCap("spire"); err == nil {
+		t.Error("ParseTowerCap() expected error for an unknown cap")
+	}
+}
+
+func TestCreateTowerCaps(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{{Date: "2024-03-03", ContributionCount: 10}, {Date: "2024-03-04", ContributionCount: 5}, {Date: "2024-03-05"}},
+		{{Date: "2024-03-10", ContributionCount: 10}, {Date: "2024-03-11", ContributionCount: 10}},
+	}
+
+	if triangles, err := CreateTowerCaps(contributions, 0, 10, CapFlat, nil); err != nil || len(triangles) != 0 {
+		t.Errorf("CreateTowerCaps(flat) = %d triangles, %v, want none", len(triangles), err)
+	}
+
+	for _, shape := range []TowerCap{CapPyramid, CapDome} {
+		// The highlighted day carries a highlight cap instead.
+		triangles, err := CreateTowerCaps(contributions, 0, 10, shape, map[string]bool{"2024-03-10": true})
+		if err != nil {
+			t.Fatalf("CreateTowerCaps(%v) error = %v", shape, err)
+		}
+		if want := 3 * shape.Triangles(); len(triangles) != want {
+			t.Fatalf("CreateTowerCaps(%v) = %d triangles, want %d for three caps", shape, len(triangles), want)
+		}
+
+		// The first day of the first week has a plain cap; the second, the week's last
+		// active day, the taller accent.
+		caps := [3]meshtest.Invariants{}
+		for i := range caps {
+			caps[i] = meshtest.Measure(triangles[i*shape.Triangles() : (i+1)*shape.Triangles()])
+		}
+		x, y := CellPosition(0, 0, 0)
+		if caps[0].Min.X != x || caps[0].Min.Y != y || caps[0].Min.Z != MaxHeight || math.Abs(caps[0].Max.Z-(MaxHeight+TowerCapHeight)) > 1e-9 {
+			t.Errorf("%v cap spans %v to %v, want it on the busiest column", shape, caps[0].Min, caps[0].Max)
+		}
+		if got := caps[1].Max.Z - caps[1].Min.Z; math.Abs(got-AccentCapHeight) > 1e-9 {
+			t.Errorf("%v accent cap is %g tall, want %g", shape, got, AccentCapHeight)
+		}
+
+		// Each cap is closed and faces out, a dome holding more than a pyramid and less
+		// than a box of the same height.
+		pyramid := CellSize * CellSize * TowerCapHeight / 3
+		if v := caps[0].Volume; v < pyramid-1e-9 || v > 3*pyramid {
+			t.Errorf("%v cap volume = %g, want between %g and %g", shape, v, pyramid, 3*pyramid)
+		}
+		if shape == CapDome && caps[0].Volume <= pyramid {
+			t.Errorf("dome volume = %g, want more than a pyramid's %g", caps[0].Volume, pyramid)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Connector dimensions. Square pegs on the right face of the base fit sockets in the
+// left face of the next model, so separately printed years line up end to end.
+// All measurements are in millimeters.
+const (
+	ConnectorSize      float64 = 4.0 // Width and height of a peg
+	ConnectorLength    float64 = 2.0 // Distance a peg sticks out of the right face
+	ConnectorClearance float64 = 0.2 // Gap around a peg once inserted into a socket
+
+	// socketDepth is how far sockets reach into the left face.
+	socketDepth = ConnectorLength + ConnectorClearance
+)
+
+// connectorCenters returns the Y positions of the pegs and sockets, at a third and two
+// thirds of the base depth so models of the same depth always line up.
+func connectorCenters(depth float64) []float64 {
+	return []float64{depth / 3, 2 * depth / 3}
+}
+
+// createConnectors builds the pegs on the right face and a socketDepth thick layer for the
+// left face with the sockets left out. The layer spans the flat part of the face between
+// corners of radius r.
+func createConnectors(width, depth, baseHeight, r float64) ([]types.Triangle, error) {
+	centers := connectorCenters(depth)
+	socket := ConnectorSize/2 + ConnectorClearance
+	if centers[0]-socket < r || centers[1]+socket > depth-r || 2*socket > baseHeight || centers[1]-centers[0] < 2*socket {
+		return nil, errors.New(errors.ValidationError, "base is too small for connectors", nil)
+	}
+
+	zCenter := -baseHeight / 2
+	socketBottom, socketTop := zCenter-socket, zCenter+socket
+
+	// The socket layer is split into a band below the sockets, a band above them and the
+	// blocks between them.
+	boxes := [][6]float64{
+		{0, r, -baseHeight, socketDepth, depth - 2*r, socketBottom + baseHeight},
+		{0, r, socketTop, socketDepth, depth - 2*r, -socketTop},
+	}
+	cursor := r
+	for _, y := range centers {
+		boxes = append(boxes, [6]float64{0, cursor, socketBottom, socketDepth, y - socket - cursor, socketTop - socketBottom})
+		cursor = y + socket
+	}
+	boxes = append(boxes, [6]float64{0, cursor, socketBottom, socketDepth, depth - r - cursor, socketTop - socketBottom})
+
+	for _, y := range centers {
+		boxes = append(boxes, [6]float64{width, y - ConnectorSize/2, zCenter - ConnectorSize/2, ConnectorLength, ConnectorSize, ConnectorSize})
+	}
+
+	var triangles []types.Triangle
+	for _, b := range boxes {
+		box, err := createBox(b[0], b[1], b[2], b[3], b[4], b[5])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, box...)
+	}
+	return triangles, nil
+}
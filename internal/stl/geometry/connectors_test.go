@@ -0,0 +1,54 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateStyledBaseConnectors(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	for _, style := range []BaseStyle{BaseSharp, BaseRounded} {
+		triangles, err := CreateStyledBase(width, depth, BaseOptions{Style: style, Connectors: true})
+		if err != nil {
+			t.Fatalf("style %d: CreateStyledBase() error = %v", style, err)
+		}
+
+		maxX := math.Inf(-1)
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				maxX = math.Max(maxX, v.X)
+				// Nothing sits inside a socket.
+				for _, y := range connectorCenters(depth) {
+					socket := ConnectorSize/2 + ConnectorClearance
+					if v.X < socketDepth-epsilon && math.Abs(v.Y-y) < socket-epsilon && math.Abs(v.Z+BaseHeight/2) < socket-epsilon {
+						t.Fatalf("style %d: vertex %v lies inside a socket", style, v)
+					}
+				}
+			}
+		}
+		if math.Abs(maxX-(width+ConnectorLength)) > epsilon {
+			t.Errorf("style %d: pegs should reach x = %v, got %v", style, width+ConnectorLength, maxX)
+		}
+	}
+}
+
+func TestCreateConnectorsTooSmall(t *testing.T) {
+	if _, err := createConnectors(10, 8, BaseHeight, 0); err == nil {
+		t.Error("expected error for a base too shallow for connectors")
+	}
+}
+
+func TestCreateEngravedBaseConnectors(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	if _, err := CreateEngravedBase("test", "2023", width, depth, BaseHeight, TextOptions{}, BaseOptions{Connectors: true}); err != nil {
+		t.Errorf("front text with connectors should work: %v", err)
+	}
+	_, err := CreateEngravedBase("test", "2023", width, depth, BaseHeight, TextOptions{YearFace: FaceRight}, BaseOptions{Connectors: true})
+	if err == nil {
+		t.Error("expected error for text on a connector face")
+	}
+}
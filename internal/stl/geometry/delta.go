@@ -0,0 +1,43 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// DeckHeight is the thickness of the deck a delta model lays over its base. Days whose
+// count fell are recessed into it.
+const DeckHeight = 8.0
+
+// CreateDeltaGeometry generates a single year of signed changes in daily counts, such as
+// one year's contributions less another's. The row is covered by a deck DeckHeight thick,
+// with the rims described for CreateMoldGeometry so the rows together cover the grid.
+// Days that grew stand on the deck as columns of their usual height for the change; days
+// that declined sink into it as pits, deepest where the change matches maxChange, the
+// largest change either way.
+func CreateDeltaGeometry(contributions [][]types.ContributionDay, yearIndex, rows, weeks, maxChange int) ([]types.Triangle, error) {
+	triangles, err := createRecessedBlock(contributions, yearIndex, rows, weeks, DeckHeight, func(count int) float64 {
+		if count >= 0 {
+			return 0
+		}
+		return NormalizeContribution(-count, maxChange) * DeckHeight / MaxHeight
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create delta deck")
+	}
+
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if day.ContributionCount <= 0 {
+				continue
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+			column, err := createBox(x, y, DeckHeight, CellSize, CellSize, NormalizeContribution(day.ContributionCount, maxChange))
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, column...)
+		}
+	}
+	return triangles, nil
+}
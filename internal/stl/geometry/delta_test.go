@@ -0,0 +1,50 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateDeltaGeometry(t *testing.T) {
+	year := [][]types.ContributionDay{
+		streakWeek(0, 9, -4, 0, 16, -16, 1),
+		streakWeek(-1),
+	}
+	triangles, err := CreateDeltaGeometry(year, 0, 1, len(year), 16)
+	if err != nil {
+		t.Fatalf("CreateDeltaGeometry() error = %v", err)
+	}
+
+	// The deck fills the grid less the pits, and the columns stand on top of it.
+	width, depth := CalculateGridDimensions(len(year), 1)
+	want := width * depth * DeckHeight
+	for _, week := range year {
+		for _, day := range week {
+			if day.ContributionCount < 0 {
+				want -= CellSize * CellSize * NormalizeContribution(-day.ContributionCount, 16) * DeckHeight / MaxHeight
+			} else {
+				want += CellSize * CellSize * NormalizeContribution(day.ContributionCount, 16)
+			}
+		}
+	}
+	if got := signedVolume(triangles); math.Abs(got-want) > 1e-6 {
+		t.Errorf("delta volume = %v, want %v", got, want)
+	}
+
+	// The largest decline reaches the base, and the largest rise stands MaxHeight tall.
+	x, y := CellPosition(0, 5, 0)
+	for _, tri := range triangles {
+		if insideTriangle(tri, x+CellSize/2, y+CellSize/2) {
+			t.Fatalf("triangle %v covers the largest decline", tri)
+		}
+	}
+	top := 0.0
+	for _, tri := range triangles {
+		top = max(top, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+	}
+	if math.Abs(top-(DeckHeight+MaxHeight)) > epsilon {
+		t.Errorf("model top = %v, want %v", top, DeckHeight+MaxHeight)
+	}
+}
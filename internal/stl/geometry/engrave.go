@@ -0,0 +1,210 @@
+package geometry
+
+import (
+	"math"
+	"slices"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// pixelRun is a horizontal span [start, end) of pixels in a face image.
+type pixelRun struct {
+	start, end int
+}
+
+// CreateEngravedBase generates the base with the username and year recessed into the
+// faces chosen in opts rather than raised off them. Each face carrying text gets a skin
+// voxelDepth thick with the glyphs left out; the rest of the base is a solid core.
+// On chamfered or rounded bases only the flat span of each face is engraved.
+// The stats line, month initials and row years in opts, if any, are engraved as well.
+func CreateEngravedBase(username, year string, baseWidth, baseDepth, baseHeight float64, opts TextOptions, base BaseOptions) ([]types.Triangle, error) {
+	labels, err := layoutText(username, year, baseWidth, baseDepth, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Stats != "" {
+		stats, err := layoutStats(baseWidth, opts)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, stats)
+	}
+	return engraveLabels(labels, opts, baseWidth, baseDepth, baseHeight, base)
+}
+
+// CreateStatsBase generates the base with only the stats line, month initials and row
+// years in opts recessed into it, for models whose username and year are raised.
+func CreateStatsBase(baseWidth, baseDepth, baseHeight float64, opts TextOptions, base BaseOptions) ([]types.Triangle, error) {
+	var labels []textLabel
+	if opts.Stats != "" {
+		stats, err := layoutStats(baseWidth, opts)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, stats)
+	}
+	return engraveLabels(labels, opts, baseWidth, baseDepth, baseHeight, base)
+}
+
+// engraveLabels builds a base with every label recessed into its face, and the month
+// initials and row years in opts recessed into the top or left face.
+func engraveLabels(labels []textLabel, opts TextOptions, baseWidth, baseDepth, baseHeight float64, base BaseOptions) ([]types.Triangle, error) {
+	byFace := map[Face][]textLabel{}
+	for _, label := range labels {
+		byFace[label.face] = append(byFace[label.face], label)
+	}
+	yearsSide := len(opts.Years) > 0 && opts.YearStrip == 0
+	if yearsSide && len(byFace[FaceLeft]) > 0 {
+		return nil, errors.New(errors.ValidationError, "year labels need the left face free of text", nil)
+	}
+	inset := func(face Face) float64 {
+		if len(byFace[face]) > 0 || (face == FaceLeft && yearsSide) {
+			return voxelDepth
+		}
+		return 0
+	}
+	front, back, left, right := inset(FaceFront), inset(FaceBack), inset(FaceLeft), inset(FaceRight)
+	strip, margin := 0.0, 0.0
+	if len(opts.Months) > 0 {
+		strip = monthStripDepth
+	}
+	if len(opts.Years) > 0 {
+		margin = opts.YearStrip
+	}
+
+	triangles, err := createSlab(baseWidth, baseDepth, baseHeight, base, slabInsets{front, back, left, right, strip, margin})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create base core")
+	}
+	r := base.Style.cornerRadius()
+
+	// The top strips fit between the corners and side skins; connectors take the left side.
+	lo := max(r, left)
+	if base.Connectors {
+		lo = max(lo, socketDepth)
+	}
+	if len(opts.Months) > 0 {
+		skin, err := engraveMonths(opts.Months, baseWidth, front, max(lo, margin), baseWidth-max(r, right))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, skin...)
+	}
+	if margin > 0 {
+		skin, err := engraveYearsTop(opts.Years, margin, lo, front, baseDepth-back)
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, skin...)
+	}
+	if yearsSide {
+		skin, err := engraveYearsSide(opts.Years, baseDepth, baseHeight, max(back, r), baseDepth-max(front, r))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, placeOnFace(skin, FaceLeft, baseWidth, baseDepth)...)
+	}
+
+	for _, face := range []Face{FaceFront, FaceBack, FaceLeft, FaceRight} {
+		faceLabels := byFace[face]
+		if len(faceLabels) == 0 {
+			continue
+		}
+
+		// Front and back skins span the flat width; side skins fit between them.
+		faceWidth, lo, hi := baseWidth, r, baseWidth-r
+		switch face {
+		case FaceLeft:
+			faceWidth, lo, hi = baseDepth, max(back, r), baseDepth-max(front, r)
+		case FaceRight:
+			faceWidth, lo, hi = baseDepth, max(front, r), baseDepth-max(back, r)
+		}
+
+		// Render the whole face at the labels' panel resolution so long faces keep detail.
+		pixelsPerUnit := baseWidthVoxelResolution / faceLabels[0].panelWidth
+		dc := newFaceContext(int(math.Round(faceWidth*pixelsPerUnit)), faceWidth, baseHeight)
+		for _, label := range faceLabels {
+			x := (label.panelOffset + label.offset*label.panelWidth) * pixelsPerUnit
+			if err := drawText(dc, label.text, label.justification, x, label.fontSize); err != nil {
+				return nil, err
+			}
+		}
+
+		skin, err := engraveSkin(dc, faceWidth, baseHeight, lo, hi)
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, placeOnFace(skin, face, baseWidth, baseDepth)...)
+	}
+
+	return triangles, nil
+}
+
+// engraveSkin builds the outer voxelDepth layer of a face, in front-face coordinates, from
+// the pixels not covered by text, clipped to [lo, hi] along the face. Consecutive rows with
+// the same gaps are merged into a single band of boxes.
+func engraveSkin(dc *gg.Context, faceWidth, baseHeight, lo, hi float64) ([]types.Triangle, error) {
+	pixel := faceWidth / float64(dc.Width())
+	rows := dc.Height()
+
+	var triangles []types.Triangle
+	emit := func(runs []pixelRun, firstRow, lastRow int) error {
+		top := -float64(firstRow) * pixel
+		bottom := -float64(lastRow+1) * pixel
+		if lastRow == rows-1 {
+			// The image height is rounded down, so the last band reaches the bottom of the base.
+			bottom = -baseHeight
+		}
+		for _, run := range runs {
+			x0 := max(float64(run.start)*pixel, lo)
+			x1 := min(float64(run.end)*pixel, hi)
+			if x1 <= x0 {
+				continue
+			}
+			box, err := createBox(x0, 0, bottom, x1-x0, voxelDepth, top-bottom)
+			if err != nil {
+				return errors.New(errors.STLError, "failed to create engraved skin", err)
+			}
+			triangles = append(triangles, box...)
+		}
+		return nil
+	}
+
+	var band []pixelRun
+	bandStart := 0
+	for y := 0; y < rows; y++ {
+		runs := emptyRuns(dc, y)
+		if y > 0 && !slices.Equal(runs, band) {
+			if err := emit(band, bandStart, y-1); err != nil {
+				return nil, err
+			}
+			bandStart = y
+		}
+		band = runs
+	}
+	if rows > 0 {
+		if err := emit(band, bandStart, rows-1); err != nil {
+			return nil, err
+		}
+	}
+	return triangles, nil
+}
+
+// emptyRuns returns the spans of row y that contain no text.
+func emptyRuns(dc *gg.Context, y int) []pixelRun {
+	var runs []pixelRun
+	for x := 0; x < dc.Width(); {
+		if isPixelActive(dc, x, y) {
+			x++
+			continue
+		}
+		start := x
+		for x < dc.Width() && !isPixelActive(dc, x, y) {
+			x++
+		}
+		runs = append(runs, pixelRun{start, x})
+	}
+	return runs
+}
@@ -0,0 +1,121 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateEngravedBase(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	triangles, err := CreateEngravedBase("test", "2023", width, depth, BaseHeight, TextOptions{}, BaseOptions{})
+	if err != nil {
+		t.Fatalf("CreateEngravedBase() error = %v", err)
+	}
+
+	// Nothing protrudes from the base and the front face has recesses.
+	recessed := false
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.X < -epsilon || v.X > width+epsilon || v.Y < -epsilon || v.Y > depth+epsilon || v.Z < -BaseHeight-epsilon || v.Z > epsilon {
+				t.Fatalf("vertex %v lies outside the base", v)
+			}
+		}
+		if math.Abs(tri.Normal.Y+1) < epsilon && math.Abs(tri.V1.Y-voxelDepth) < epsilon {
+			recessed = true
+		}
+	}
+	if !recessed {
+		t.Error("expected recessed glyph floors on the front face")
+	}
+
+	// Only faces carrying text are engraved, so the back stays flat.
+	triangles, err = CreateEngravedBase("test", "2023", width, depth, BaseHeight, TextOptions{UsernameFace: FaceLeft, YearFace: FaceLeft}, BaseOptions{})
+	if err != nil {
+		t.Fatalf("CreateEngravedBase() error = %v", err)
+	}
+	for _, tri := range triangles {
+		if tri.V1.X > voxelDepth+epsilon && tri.V1.X < width-epsilon && math.Abs(tri.Normal.Y+1) < epsilon && tri.V1.Y > epsilon {
+			t.Fatalf("front face should not be engraved: %v", tri)
+		}
+	}
+}
+
+func TestEngraveSkin(t *testing.T) {
+	dc := gg.NewContext(10, 4)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	dc.SetPixel(4, 1)
+
+	triangles, err := engraveSkin(dc, 10, 4.5, 0, 10)
+	if err != nil {
+		t.Fatalf("engraveSkin() error = %v", err)
+	}
+	// Row 0, rows 2-3 and the two runs either side of the glyph pixel in row 1.
+	if len(triangles) != 4*12 {
+		t.Fatalf("got %d triangles, want %d", len(triangles), 4*12)
+	}
+
+	minZ := 0.0
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			minZ = math.Min(minZ, v.Z)
+		}
+	}
+	if math.Abs(minZ+4.5) > epsilon {
+		t.Errorf("skin should reach the bottom of the base, min z = %v", minZ)
+	}
+}
+
+func TestCreateEngravedBaseRounded(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	triangles, err := CreateEngravedBase("test", "2023", width, depth, BaseHeight, TextOptions{}, BaseOptions{Style: BaseRounded})
+	if err != nil {
+		t.Fatalf("CreateEngravedBase() error = %v", err)
+	}
+	// The front skin stays on the flat span between the rounded corners.
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Y < epsilon && (v.X < BaseCornerRadius-epsilon || v.X > width-BaseCornerRadius+epsilon) {
+				t.Fatalf("vertex %v lies in a rounded corner", v)
+			}
+		}
+	}
+}
+
+func TestCreateStatsBase(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	opts := TextOptions{Stats: "4,321 contributions · 212 day streak"}
+
+	triangles, err := CreateStatsBase(width, depth, BaseHeight, opts, BaseOptions{})
+	if err != nil {
+		t.Fatalf("CreateStatsBase() error = %v", err)
+	}
+	// The back face has recessed glyph floors; the front is left flat for raised text.
+	recessed := false
+	for _, tri := range triangles {
+		if math.Abs(tri.Normal.Y-1) < epsilon && math.Abs(tri.V1.Y-(depth-voxelDepth)) < epsilon {
+			recessed = true
+		}
+		if math.Abs(tri.Normal.Y+1) < epsilon && math.Abs(tri.V1.Y-voxelDepth) < epsilon {
+			t.Fatalf("front face should not be engraved: %v", tri)
+		}
+	}
+	if !recessed {
+		t.Error("expected recessed stats on the back face")
+	}
+
+	// Engraved usernames and years can share the base with the stats, but not the back face.
+	if _, err := CreateEngravedBase("test", "2023", width, depth, BaseHeight, opts, BaseOptions{}); err != nil {
+		t.Errorf("CreateEngravedBase() with stats error = %v", err)
+	}
+	opts.YearFace = FaceBack
+	if _, err := CreateStatsBase(width, depth, BaseHeight, opts, BaseOptions{}); err == nil {
+		t.Error("CreateStatsBase() expected error when the year is on the back face")
+	}
+}
@@ -0,0 +1,161 @@
+package geometry
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"unicode"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/text/unicode/norm"
+)
+
+// unrenderable replaces characters no available font can draw.
+const unrenderable = '?'
+
+// systemFontPaths are installed fonts, by operating system, tried in order for text the
+// bundled fonts cannot draw, such as CJK, Cyrillic or Greek names. Only fonts with
+// TrueType outlines can be rendered, so CFF-based fonts like Noto Sans CJK are left out.
+var systemFontPaths = map[string][]string{
+	"darwin": {
+		"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+		"/Library/Fonts/Arial Unicode.ttf",
+		"/System/Library/Fonts/AppleSDGothicNeo.ttc",
+	},
+	"windows": {
+		`C:\Windows\Fonts\msyh.ttc`,
+		`C:\Windows\Fonts\msgothic.ttc`,
+		`C:\Windows\Fonts\malgun.ttf`,
+		`C:\Windows\Fonts\arial.ttf`,
+	},
+	"linux": {
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/truetype/noto/NotoSans-Regular.ttf",
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+		"/usr/share/fonts/truetype/unifont/unifont.ttf",
+	},
+}
+
+// Installed fonts are parsed on first use; paths that are missing or cannot be parsed
+// map to nil. Texts already warned about are not reported again.
+var (
+	systemFonts  = map[string]*truetype.Font{}
+	warnedTexts  = map[string]bool{}
+	systemFontOS = runtime.GOOS
+)
+
+// fontFor returns the font to draw text in and the text to draw. The first font in the
+// chain that covers every character is used: the --font override, the bundled fonts,
+// then installed system fonts. When none does, accents are stripped and any remaining
+// characters replaced, with a warning, so the text still embosses legibly.
+func fontFor(text string) (*truetype.Font, string, error) {
+	primary, err := textFont()
+	if err != nil {
+		return nil, "", err
+	}
+	if covers(primary, text) {
+		return primary, text, nil
+	}
+
+	chain := []*truetype.Font{}
+	if fallback, err := embeddedFont(FallbackFont); err == nil && fallback != primary {
+		chain = append(chain, fallback)
+	}
+	chain = append(chain, installedFonts()...)
+	for _, f := range chain {
+		if covers(f, text) {
+			return f, text, nil
+		}
+	}
+
+	transliterated := transliterate(primary, text)
+	if err := warnOnce(text, transliterated); err != nil {
+		return nil, "", err
+	}
+	return primary, transliterated, nil
+}
+
+// covers reports whether f has a glyph for every visible character of text.
+func covers(f *truetype.Font, text string) bool {
+	for _, r := range text {
+		if !unicode.IsSpace(r) && f.Index(r) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// transliterate rewrites the characters of text that f cannot draw: accented letters
+// lose their accents, e.g. "é" becomes "e", and anything else becomes unrenderable.
+func transliterate(f *truetype.Font, text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if unicode.IsSpace(r) || f.Index(r) != 0 {
+			b.WriteRune(r)
+			continue
+		}
+		base := []rune(norm.NFD.String(string(r)))[0]
+		if base != r && f.Index(base) != 0 {
+			b.WriteRune(base)
+		} else {
+			b.WriteRune(unrenderable)
+		}
+	}
+	return b.String()
+}
+
+// installedFonts returns the system fonts of this operating system that could be parsed.
+func installedFonts() []*truetype.Font {
+	fontMu.Lock()
+	defer fontMu.Unlock()
+
+	var fonts []*truetype.Font
+	for _, path := range systemFontPaths[systemFontOS] {
+		f, ok := systemFonts[path]
+		if !ok {
+			if data, err := os.ReadFile(path); err == nil {
+				f, _ = truetype.Parse(data)
+			}
+			systemFonts[path] = f
+		}
+		if f != nil {
+			fonts = append(fonts, f)
+		}
+	}
+	return fonts
+}
+
+// InstalledFontPaths returns the system fonts of this operating system that can be
+// used for text the bundled fonts cannot draw, in the order they are tried.
+func InstalledFontPaths() []string {
+	installedFonts()
+
+	fontMu.Lock()
+	defer fontMu.Unlock()
+	var paths []string
+	for _, path := range systemFontPaths[systemFontOS] {
+		if systemFonts[path] != nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// warnOnce logs that text is embossed as transliterated, the first time it happens.
+func warnOnce(text, transliterated string) error {
+	fontMu.Lock()
+	warned := warnedTexts[text]
+	warnedTexts[text] = true
+	fontMu.Unlock()
+	if warned {
+		return nil
+	}
+
+	if err := logger.GetLogger().Component("geometry").Warning("No available font can draw %q; embossing %q instead. Pass --font with a font that covers it", text, transliterated); err != nil {
+		return errors.Wrap(err, "failed to log warning")
+	}
+	return nil
+}
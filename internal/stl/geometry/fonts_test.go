@@ -0,0 +1,121 @@
+package geometry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withSystemFonts replaces the installed fonts tried for uncovered text for one test.
+func withSystemFonts(t *testing.T, paths []string) {
+	t.Helper()
+	originalPaths, originalOS := systemFontPaths, systemFontOS
+	systemFontPaths, systemFontOS = map[string][]string{"test": paths}, "test"
+	t.Cleanup(func() { systemFontPaths, systemFontOS = originalPaths, originalOS })
+}
+
+func TestFontForCoveredText(t *testing.T) {
+	primary, err := textFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"mona", "2023-24", "José Müller"} {
+		f, drawn, err := fontFor(text)
+		if err != nil {
+			t.Fatalf("fontFor(%q) error = %v", text, err)
+		}
+		if f != primary || drawn != text {
+			t.Errorf("fontFor(%q) = %q in another font, want the primary font unchanged", text, drawn)
+		}
+	}
+}
+
+func TestFontForTransliterates(t *testing.T) {
+	// Candidates that are missing or not fonts are skipped.
+	notAFont := filepath.Join(t.TempDir(), "notes.ttf")
+	if err := os.WriteFile(notAFont, []byte("not a font"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withSystemFonts(t, []string{notAFont, filepath.Join(t.TempDir(), "missing.ttf")})
+
+	_, drawn, err := fontFor("山田 2024")
+	if err != nil {
+		t.Fatalf("fontFor() error = %v", err)
+	}
+	if drawn != "?? 2024" {
+		t.Errorf("fontFor() drew %q, want %q", drawn, "?? 2024")
+	}
+}
+
+func TestFontForSystemFallback(t *testing.T) {
+	primary, err := textFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if covers(primary, "山") {
+		t.Skip("the bundled font covers CJK")
+	}
+	var installed []string
+	for _, paths := range systemFontPaths {
+		installed = append(installed, paths...)
+	}
+	withSystemFonts(t, installed)
+	for _, f := range installedFonts() {
+		if covers(f, "山") {
+			got, drawn, err := fontFor("山")
+			if err != nil || got == primary || drawn != "山" {
+				t.Errorf("fontFor() = %q, %v; want an installed font drawing the text", drawn, err)
+			}
+			return
+		}
+	}
+	t.Skip("no installed font covers CJK")
+}
+
+func TestTransliterate(t *testing.T) {
+	primary, err := textFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ text, want string }{
+		{"mona", "mona"},
+		{"a b", "a b"},
+		{"山", "?"},
+	}
+	for _, tt := range tests {
+		if got := transliterate(primary, tt.text); got != tt.want {
+			t.Errorf("transliterate(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCreate3DTextUnicode(t *testing.T) {
+	withSystemFonts(t, nil)
+	triangles, err := Create3DText("山田", "2024", 150, 10)
+	if err != nil {
+		t.Fatalf("Create3DText() error = %v", err)
+	}
+	if len(triangles) == 0 {
+		t.Error("Create3DText() produced no geometry for uncovered text")
+	}
+}
+
+func TestInstalledFontPaths(t *testing.T) {
+	fontBytes, err := embeddedAssets.ReadFile("assets/" + FallbackFont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	font, notAFont := filepath.Join(dir, "font.ttf"), filepath.Join(dir, "notes.ttf")
+	if err := os.WriteFile(font, fontBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notAFont, []byte("not a font"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	withSystemFonts(t, []string{notAFont, filepath.Join(dir, "missing.ttf"), font})
+
+	if got := InstalledFontPaths(); len(got) != 1 || got[0] != font {
+		t.Errorf("InstalledFontPaths() = %v, want only %s", got, font)
+	}
+}
@@ -29,6 +29,10 @@ const (
 	FallbackFont = "monasans-regular.ttf"
 )
 
+// MaxYearWeeks is the most calendar weeks a single year can touch: a leap year that
+// starts on the last day of a week ends on the first day of its 54th week.
+const MaxYearWeeks int = GridSize + 1
+
 // YearOffset defines the depth spacing between successive years in a multi-year model.
 const YearOffset float64 = 7.0 * CellSize
 
@@ -63,15 +67,11 @@ func NormalizeContribution(count, maxCount int) float64 {
 func CreateContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
 	var triangles []types.Triangle
 
-	// Base Y offset includes padding and positions each year accordingly
-	baseYOffset := 2*CellSize + float64(yearIndex)*YearOffset
-
 	for weekIdx, week := range contributions {
 		for dayIdx, day := range week {
 			if day.ContributionCount > 0 {
 				height := NormalizeContribution(day.ContributionCount, maxContrib)
-				x := 2*CellSize + float64(weekIdx)*CellSize
-				y := baseYOffset + float64(dayIdx)*CellSize
+				x, y := CellPosition(weekIdx, dayIdx, yearIndex)
 
 				columnTriangles, err := CreateColumn(x, y, height, CellSize)
 				if err != nil {
@@ -85,11 +85,85 @@ func CreateContributionGeometry(contributions [][]types.ContributionDay, yearInd
 	return triangles, nil
 }
 
+// CreateBreakdownGeometry generates a single year's columns split into stacked segments,
+// one per contribution type, with each segment's share of the column height matching the
+// type's share of the day's contributions. The result holds one slice of triangles per
+// type in types.ContributionTypes order, so each type can be coloured or exported on its own.
+// Days without a breakdown are attributed entirely to commits.
+func CreateBreakdownGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([][]types.Triangle, error) {
+	segments := make([][]types.Triangle, len(types.ContributionTypes))
+
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if day.ContributionCount <= 0 {
+				continue
+			}
+			height := NormalizeContribution(day.ContributionCount, maxContrib)
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+
+			counts := day.Breakdown.Counts()
+			total := day.Breakdown.Total()
+			if total == 0 {
+				counts[0], total = 1, 1
+			}
+
+			z := 0.0
+			for i, count := range counts {
+				if count == 0 {
+					continue
+				}
+				segmentHeight := height * float64(count) / float64(total)
+				box, err := createBox(x, y, z, CellSize, CellSize, segmentHeight)
+				if err != nil {
+					return nil, err
+				}
+				segments[i] = append(segments[i], box...)
+				z += segmentHeight
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// CellPosition returns the front-left corner of the cell for the given week and day.
+// The base Y offset includes padding and positions each year accordingly, with
+// yearIndex 0 closest to the front of the model.
+func CellPosition(weekIdx, dayIdx, yearIndex int) (x, y float64) {
+	baseYOffset := 2*CellSize + float64(yearIndex)*YearOffset
+	x = 2*CellSize + float64(weekIdx)*CellSize
+	y = baseYOffset + float64(dayIdx)*CellSize
+	return x, y
+}
+
 // CalculateMultiYearDimensions calculates dimensions for multiple years
 func CalculateMultiYearDimensions(yearCount int) (width, depth float64) {
+	return CalculateGridDimensions(GridSize, yearCount)
+}
+
+// CalculateGridDimensions calculates dimensions for rowCount rows of weekCount weeks.
+// Rows shorter than a year still get a full year's width.
+func CalculateGridDimensions(weekCount, rowCount int) (width, depth float64) {
 	// Total width: grid size + padding on both sides
-	width = float64(GridSize)*CellSize + 4*CellSize
-	// Total depth: (7 days * number of years) + padding on both sides
-	depth = float64(7*yearCount)*CellSize + 4*CellSize
+	width = float64(max(weekCount, GridSize))*CellSize + 4*CellSize
+	// Total depth: (7 days * number of rows) + padding on both sides
+	depth = float64(7*rowCount)*CellSize + 4*CellSize
 	return width, depth
 }
+
+// CalculateWrappedDimensions calculates dimensions for rowCount rows of weekCount weeks
+// wrapped from longer years. Unlike CalculateGridDimensions, the base is only as wide as
+// the rows, which is what makes a wrapped model squarer.
+func CalculateWrappedDimensions(weekCount, rowCount int) (width, depth float64) {
+	_, depth = CalculateGridDimensions(weekCount, rowCount)
+	return float64(weekCount)*CellSize + 4*CellSize, depth
+}
+
+// GridWeeks returns the number of weeks in the longest row of contributions ([row][week][day]).
+func GridWeeks(contributions [][][]types.ContributionDay) int {
+	weeks := 0
+	for _, row := range contributions {
+		weeks = max(weeks, len(row))
+	}
+	return weeks
+}
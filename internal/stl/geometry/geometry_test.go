@@ -11,6 +11,24 @@ const (
 	epsilon = 0.0001 // Tolerance for floating-point comparisons
 )
 
+// assertClosedMesh fails the test unless the triangles form a closed, consistently wound
+// mesh, in which every edge is used once in each direction.
+func assertClosedMesh(t *testing.T, triangles []types.Triangle) {
+	t.Helper()
+	type edge struct{ from, to types.Point3D }
+	edges := make(map[edge]int)
+	for _, tri := range triangles {
+		for _, e := range []edge{{tri.V1, tri.V2}, {tri.V2, tri.V3}, {tri.V3, tri.V1}} {
+			edges[e]++
+		}
+	}
+	for e, n := range edges {
+		if n != 1 || edges[edge{e.to, e.from}] != 1 {
+			t.Fatalf("edge %v used %d times, reverse %d times; mesh is not closed", e, n, edges[edge{e.to, e.from}])
+		}
+	}
+}
+
 // TestNormalizeContribution verifies contribution normalization logic
 func TestNormalizeContribution(t *testing.T) {
 	tests := []struct {
@@ -82,6 +100,40 @@ func TestCreateContributionGeometry(t *testing.T) {
 	}
 }
 
+// TestCreateBreakdownGeometry verifies that columns are split into stacked segments by type
+func TestCreateBreakdownGeometry(t *testing.T) {
+	contribs := [][]types.ContributionDay{{
+		{ContributionCount: 4, Date: "2023-01-01", Breakdown: types.Breakdown{Commits: 3, Reviews: 1}},
+		{ContributionCount: 2, Date: "2023-01-02"},
+		{ContributionCount: 0, Date: "2023-01-03"},
+	}}
+
+	segments, err := CreateBreakdownGeometry(contribs, 0, 4)
+	if err != nil {
+		t.Fatalf("CreateBreakdownGeometry() error = %v", err)
+	}
+	if len(segments) != len(types.ContributionTypes) {
+		t.Fatalf("got %d segment groups, want %d", len(segments), len(types.ContributionTypes))
+	}
+	// Two commit segments (one from the day without a breakdown) and one review segment.
+	if got := []int{len(segments[0]), len(segments[1]), len(segments[2]), len(segments[3])}; got[0] != 24 || got[1] != 0 || got[2] != 0 || got[3] != 12 {
+		t.Errorf("segment triangle counts = %v, want [24 0 0 12]", got)
+	}
+
+	// The review segment sits on top of the commits and reaches the full column height.
+	top, bottom := math.Inf(-1), math.Inf(1)
+	for _, tri := range segments[3] {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			top = math.Max(top, v.Z)
+			bottom = math.Min(bottom, v.Z)
+		}
+	}
+	height := NormalizeContribution(4, 4)
+	if math.Abs(top-height) > epsilon || math.Abs(bottom-height*0.75) > epsilon {
+		t.Errorf("review segment spans z %v-%v, want %v-%v", bottom, top, height*0.75, height)
+	}
+}
+
 // TestCalculateMultiYearDimensions verifies dimension calculations
 func TestCalculateMultiYearDimensions(t *testing.T) {
 	tests := []struct {
@@ -107,3 +159,58 @@ func TestCalculateMultiYearDimensions(t *testing.T) {
 		})
 	}
 }
+
+// TestCalculateGridDimensions verifies that long rows widen the model and short rows do not shrink it
+func TestCalculateGridDimensions(t *testing.T) {
+	w, d := CalculateGridDimensions(3*GridSize, 1)
+	if want := float64(3*GridSize)*CellSize + 4*CellSize; math.Abs(w-want) > epsilon {
+		t.Errorf("CalculateGridDimensions() width = %v, want %v", w, want)
+	}
+	if want := 7*CellSize + 4*CellSize; math.Abs(d-want) > epsilon {
+		t.Errorf("CalculateGridDimensions() depth = %v, want %v", d, want)
+	}
+
+	w, _ = CalculateGridDimensions(10, 1)
+	if want, _ := CalculateMultiYearDimensions(1); math.Abs(w-want) > epsilon {
+		t.Errorf("CalculateGridDimensions() width for a short row = %v, want %v", w, want)
+	}
+
+	// Wrapped rows keep their own width.
+	w, d = CalculateWrappedDimensions(27, 2)
+	if want := 31 * CellSize; math.Abs(w-want) > epsilon {
+		t.Errorf("CalculateWrappedDimensions() width = %v, want %v", w, want)
+	}
+	if _, want := CalculateGridDimensions(27, 2); math.Abs(d-want) > epsilon {
+		t.Errorf("CalculateWrappedDimensions() depth = %v, want %v", d, want)
+	}
+
+	rows := [][][]types.ContributionDay{make([][]types.ContributionDay, 52), make([][]types.ContributionDay, 106)}
+	if got := GridWeeks(rows); got != 106 {
+		t.Errorf("GridWeeks() = %d, want 106", got)
+	}
+}
+
+// TestCellPosition verifies cell placement within the model
+func TestCellPosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		week      int
+		day       int
+		yearIndex int
+		wantX     float64
+		wantY     float64
+	}{
+		{"origin cell", 0, 0, 0, 2 * CellSize, 2 * CellSize},
+		{"later week and day", 3, 2, 0, 5 * CellSize, 4 * CellSize},
+		{"second year row", 0, 0, 1, 2 * CellSize, 2*CellSize + YearOffset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := CellPosition(tt.week, tt.day, tt.yearIndex)
+			if math.Abs(x-tt.wantX) > epsilon || math.Abs(y-tt.wantY) > epsilon {
+				t.Errorf("CellPosition() = (%v, %v), want (%v, %v)", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
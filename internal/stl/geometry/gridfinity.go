@@ -0,0 +1,124 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Gridfinity dimensions, from the published bin specification. A base of whole grid units
+// stands on one foot per unit, each shaped to seat in a baseplate pocket.
+// All measurements are in millimeters.
+const (
+	GridfinityPitch      float64 = 42.0 // Size of one grid unit
+	GridfinityClearance  float64 = 0.5  // Gap left between neighbouring bins
+	GridfinityFootHeight float64 = 4.75 // Height of the feet under the base
+
+	// gridfinityFootRadius is the corner radius at the top of a foot.
+	gridfinityFootRadius = 3.75
+
+	// gridfinitySegments is the number of flat sides approximating each corner of a foot.
+	gridfinitySegments = 8
+)
+
+// gridfinityProfile lists the inset of a foot's sides from the edge of its unit footprint,
+// from the bottom of the foot to the top: a 0.8 mm chamfer, a 1.8 mm straight section and
+// a 2.15 mm chamfer.
+var gridfinityProfile = []struct{ height, inset float64 }{
+	{0, 0.8 + 2.15},
+	{0.8, 2.15},
+	{0.8 + 1.8, 2.15},
+	{GridfinityFootHeight, 0},
+}
+
+// GridfinityUnits returns the number of grid units needed to cover length.
+func GridfinityUnits(length float64) int {
+	return max(1, int(math.Ceil((length+GridfinityClearance)/GridfinityPitch)))
+}
+
+// GridfinitySize returns the length of a base spanning the given number of grid units.
+func GridfinitySize(units int) float64 {
+	return float64(units)*GridfinityPitch - GridfinityClearance
+}
+
+// createGridfinityFeet builds one foot for each grid unit of a base of the given size,
+// hanging below zTop. The base must be sized with GridfinitySize.
+func createGridfinityFeet(width, depth, zTop float64) ([]types.Triangle, error) {
+	unitsX, unitsY := GridfinityUnits(width), GridfinityUnits(depth)
+	if math.Abs(GridfinitySize(unitsX)-width) > 1e-6 || math.Abs(GridfinitySize(unitsY)-depth) > 1e-6 {
+		return nil, errors.New(errors.ValidationError, "base is not sized in whole Gridfinity units", nil)
+	}
+
+	unit := GridfinitySize(1)
+	zBottom := zTop - GridfinityFootHeight
+	var triangles []types.Triangle
+	for i := range unitsX {
+		for j := range unitsY {
+			cx := float64(i)*GridfinityPitch + unit/2
+			cy := float64(j)*GridfinityPitch + unit/2
+
+			rings := make([][]types.Point3D, len(gridfinityProfile))
+			for k, step := range gridfinityProfile {
+				half := unit/2 - step.inset
+				radius := gridfinityFootRadius - step.inset
+				rings[k] = roundedRectRing(cx, cy, half, radius, zBottom+step.height)
+			}
+			foot, err := loftRings(rings)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create Gridfinity foot")
+			}
+			triangles = append(triangles, foot...)
+		}
+	}
+	return triangles, nil
+}
+
+// roundedRectRing returns the outline of a square of the given half size and corner radius
+// centred on (cx, cy) at height z, counter-clockwise when viewed from above.
+func roundedRectRing(cx, cy, half, radius, z float64) []types.Point3D {
+	corners := []struct{ x, y, angle float64 }{
+		{cx + half - radius, cy - half + radius, 1.5 * math.Pi}, // front right
+		{cx + half - radius, cy + half - radius, 0},             // back right
+		{cx - half + radius, cy + half - radius, 0.5 * math.Pi}, // back left
+		{cx - half + radius, cy - half + radius, math.Pi},       // front left
+	}
+	ring := make([]types.Point3D, 0, 4*(gridfinitySegments+1))
+	for _, c := range corners {
+		for k := 0; k <= gridfinitySegments; k++ {
+			angle := c.angle + float64(k)*math.Pi/2/gridfinitySegments
+			ring = append(ring, types.Point3D{X: c.x + radius*math.Cos(angle), Y: c.y + radius*math.Sin(angle), Z: z})
+		}
+	}
+	return ring
+}
+
+// loftRings joins convex rings of equal length, ordered from bottom to top, into a closed
+// solid. The bottom and top rings are capped with fans from their first vertex.
+func loftRings(rings [][]types.Point3D) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	bottom, top := rings[0], rings[len(rings)-1]
+	for i := 1; i+1 < len(bottom); i++ {
+		for _, tri := range [][3]types.Point3D{
+			{bottom[0], bottom[i+1], bottom[i]},
+			{top[0], top[i], top[i+1]},
+		} {
+			var err error
+			if triangles, err = appendTriangle(triangles, tri); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for k := 0; k+1 < len(rings); k++ {
+		lower, upper := rings[k], rings[k+1]
+		for i := range lower {
+			next := (i + 1) % len(lower)
+			side, err := CreateQuad(lower[i], lower[next], upper[next], upper[i])
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, side...)
+		}
+	}
+	return triangles, nil
+}
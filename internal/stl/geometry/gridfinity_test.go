@@ -0,0 +1,71 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGridfinityUnits(t *testing.T) {
+	tests := []struct {
+		length float64
+		want   int
+	}{
+		{0, 1},
+		{41.5, 1},
+		{41.6, 2},
+		{142.5, 4},
+	}
+	for _, tt := range tests {
+		if got := GridfinityUnits(tt.length); got != tt.want {
+			t.Errorf("GridfinityUnits(%v) = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+	if got := GridfinitySize(4); got != 167.5 {
+		t.Errorf("GridfinitySize(4) = %v, want 167.5", got)
+	}
+}
+
+func TestCreateStyledBaseGridfinity(t *testing.T) {
+	width, depth := GridfinitySize(4), GridfinitySize(1)
+	triangles, err := CreateStyledBase(width, depth, BaseOptions{Footprint: FootprintGridfinity})
+	if err != nil {
+		t.Fatalf("CreateStyledBase() error = %v", err)
+	}
+	flat, err := CreateStyledBase(width, depth, BaseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ring := 4 * (gridfinitySegments + 1)
+	perFoot := 2*(ring-2) + 2*ring*(len(gridfinityProfile)-1)
+	if want := len(flat) + 4*perFoot; len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+
+	// The feet hang below the slab, narrowing from the full unit to the pocket floor.
+	bottom := -BaseHeight - GridfinityFootHeight
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Z < bottom-epsilon {
+				t.Fatalf("vertex %v below the feet", v)
+			}
+			if math.Abs(v.Z-bottom) < epsilon {
+				minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+			}
+		}
+	}
+	inset := gridfinityProfile[0].inset
+	if math.Abs(minX-inset) > epsilon || math.Abs(maxX-(width-inset)) > epsilon {
+		t.Errorf("feet bottoms span %v..%v, want %v..%v", minX, maxX, inset, width-inset)
+	}
+
+	if _, err := CreateStyledBase(100, depth, BaseOptions{Footprint: FootprintGridfinity}); err == nil {
+		t.Error("expected an error for a base not sized in whole units")
+	}
+	if _, err := CreateStyledBase(width, depth, BaseOptions{Footprint: FootprintGridfinity, StudSockets: true}); err == nil {
+		t.Error("expected an error combining Gridfinity feet with stud sockets")
+	}
+}
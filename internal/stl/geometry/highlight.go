@@ -0,0 +1,66 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// HighlightCapHeight is the height of the pyramid capping a highlighted day's column.
+	HighlightCapHeight = CellSize
+
+	// HighlightCapTriangles is the number of triangles in each cap.
+	HighlightCapTriangles = 6
+)
+
+// CreateHighlightCaps generates a pyramid on top of the column of each day of a year whose
+// date is in highlight, marking personal records so they stand out on the print. Each cap
+// is a closed solid standing on the column's top face.
+func CreateHighlightCaps(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, highlight map[string]bool) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if day.ContributionCount <= 0 || !highlight[day.Date] {
+				continue
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+			pyramid, err := createPyramid(x, y, NormalizeContribution(day.ContributionCount, maxContrib), CellSize, HighlightCapHeight)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, pyramid...)
+		}
+	}
+	return triangles, nil
+}
+
+// createPyramid generates a square pyramid whose base, size wide with its front-left
+// corner at (x, y), lies at height z, rising to an apex height above its centre.
+func createPyramid(x, y, z, size, height float64) ([]types.Triangle, error) {
+	// Base corners, counter-clockwise seen from above.
+	corners := [4]types.Point3D{
+		{X: x, Y: y, Z: z},
+		{X: x + size, Y: y, Z: z},
+		{X: x + size, Y: y + size, Z: z},
+		{X: x, Y: y + size, Z: z},
+	}
+	apex := types.Point3D{X: x + size/2, Y: y + size/2, Z: z + height}
+
+	faces := [][3]types.Point3D{
+		{corners[0], corners[2], corners[1]},
+		{corners[0], corners[3], corners[2]},
+	}
+	for i := range corners {
+		faces = append(faces, [3]types.Point3D{corners[i], corners[(i+1)%4], apex})
+	}
+
+	triangles := make([]types.Triangle, 0, len(faces))
+	for _, face := range faces {
+		normal, err := calculateNormal(face[0], face[1], face[2])
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create highlight cap", err)
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: face[0], V2: face[1], V3: face[2]})
+	}
+	return triangles, nil
+}
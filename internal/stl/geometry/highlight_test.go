@@ -0,0 +1,35 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateHighlightCaps(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{{Date: "2024-03-03", ContributionCount: 10}, {Date: "2024-03-04", ContributionCount: 2}},
+		{{Date: "2024-03-10", ContributionCount: 0}},
+	}
+	highlight := map[string]bool{"2024-03-03": true, "2024-03-10": true}
+
+	triangles, err := CreateHighlightCaps(contributions, 0, 10, highlight)
+	if err != nil {
+		t.Fatalf("CreateHighlightCaps() error = %v", err)
+	}
+	// Only the busy day gets a cap: the highlighted quiet day has no column to stand on.
+	if len(triangles) != 6 {
+		t.Fatalf("CreateHighlightCaps() = %d triangles, want 6 for one pyramid", len(triangles))
+	}
+
+	inv := meshtest.Measure(triangles)
+	if want := CellSize * CellSize * HighlightCapHeight / 3; math.Abs(inv.Volume-want) > 1e-9 {
+		t.Errorf("cap volume = %g, want %g for a closed, outward-facing pyramid", inv.Volume, want)
+	}
+	x, y := CellPosition(0, 0, 0)
+	if inv.Min.X != x || inv.Min.Y != y || inv.Min.Z != MaxHeight || inv.Max.Z != MaxHeight+HighlightCapHeight {
+		t.Errorf("cap spans %v to %v, want it on top of the busiest column at (%g, %g)", inv.Min, inv.Max, x, y)
+	}
+}
@@ -0,0 +1,103 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Lithophane dimensions. Brighter heatmap pixels are printed thicker, so busy days let less
+// light through and show dark when the panel is held up to a light.
+// All measurements are in millimeters.
+const (
+	LithophaneMinThickness float64 = 0.8 // Thickness of a day without contributions
+	LithophaneMaxThickness float64 = 3.0 // Thickness of the busiest day and of the frame
+
+	// LithophaneSubdivisions is the number of samples along each side of a heatmap pixel.
+	LithophaneSubdivisions = 4
+)
+
+// LithophaneTriangleCount returns the number of triangles CreateLithophane produces for
+// rows of the given number of weeks: the top and bottom grids plus the four walls.
+func LithophaneTriangleCount(weeks, rows int) int {
+	width, depth := lithophanePixels(weeks, rows)
+	cols, samples := width*LithophaneSubdivisions, depth*LithophaneSubdivisions
+	return 4*cols*samples + 4*(cols+samples)
+}
+
+// lithophanePixels returns the size of the heatmap for rows of the given number of weeks:
+// one pixel per cell, including the margin around the grid.
+func lithophanePixels(weeks, rows int) (width, depth int) {
+	return weeks + 4, 7*rows + 4
+}
+
+// LithophaneHeatmap renders contributions ([row][week][day]) as a grayscale image with one
+// pixel per cell of the model, laid out like the columns: image X follows model X and image
+// Y follows model Y, with the first row at the back. Each day's brightness is its column
+// height relative to MaxHeight; the margin around the grid is white, so it prints as a
+// solid frame.
+func LithophaneHeatmap(contributions [][][]types.ContributionDay, maxContrib int) *image.Gray {
+	width, depth := lithophanePixels(GridWeeks(contributions), len(contributions))
+	heatmap := image.NewGray(image.Rect(0, 0, width, depth))
+	for i := range heatmap.Pix {
+		heatmap.Pix[i] = 0xff
+	}
+
+	for row, year := range contributions {
+		yearIndex := len(contributions) - 1 - row
+		for weekIdx, week := range year {
+			for dayIdx, day := range week {
+				if dayIdx >= 7 {
+					continue
+				}
+				intensity := NormalizeContribution(day.ContributionCount, maxContrib) / MaxHeight
+				heatmap.SetGray(2+weekIdx, 2+7*yearIndex+dayIdx, color.Gray{Y: uint8(math.Round(255 * intensity))})
+			}
+		}
+	}
+	return heatmap
+}
+
+// CreateLithophane builds a panel of the given size standing on Z = 0 whose thickness
+// follows the intensity of the heatmap's pixels, stretched across the panel. Each pixel is
+// a flat plateau, sloping to its neighbours within one sample of its edges.
+func CreateLithophane(heatmap image.Image, width, depth float64) ([]types.Triangle, error) {
+	bounds := heatmap.Bounds()
+	if bounds.Empty() || width <= 0 || depth <= 0 {
+		return nil, errors.New(errors.ValidationError, "lithophane is empty", nil)
+	}
+
+	// thickness averages the pixels touching sample (i, j), counted along each axis in
+	// steps of 1/LithophaneSubdivisions of a pixel.
+	thickness := func(i, j int) float64 {
+		pixels := func(k, count int) []int {
+			if k%LithophaneSubdivisions == 0 {
+				return []int{max(k/LithophaneSubdivisions-1, 0), min(k/LithophaneSubdivisions, count-1)}
+			}
+			return []int{k / LithophaneSubdivisions}
+		}
+		sum, n := 0.0, 0
+		for _, x := range pixels(i, bounds.Dx()) {
+			for _, y := range pixels(j, bounds.Dy()) {
+				sum += pixelIntensity(heatmap.At(bounds.Min.X+x, bounds.Min.Y+y))
+				n++
+			}
+		}
+		return LithophaneMinThickness + sum/float64(n)*(LithophaneMaxThickness-LithophaneMinThickness)
+	}
+
+	cols, rows := bounds.Dx()*LithophaneSubdivisions, bounds.Dy()*LithophaneSubdivisions
+	stepX, stepY := width/float64(cols), depth/float64(rows)
+	top := make([][]types.Point3D, cols+1)
+	for i := range top {
+		top[i] = make([]types.Point3D, rows+1)
+		for j := range top[i] {
+			top[i][j] = types.Point3D{X: float64(i) * stepX, Y: float64(j) * stepY, Z: thickness(i, j)}
+		}
+	}
+
+	return createHeightfield(top, 4*cols*rows+4*(cols+rows))
+}
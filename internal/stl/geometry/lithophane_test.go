@@ -0,0 +1,88 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestLithophaneHeatmap verifies days are shaded by contributions inside a white frame
+func TestLithophaneHeatmap(t *testing.T) {
+	year := make([][]types.ContributionDay, 2)
+	for w := range year {
+		year[w] = make([]types.ContributionDay, 7)
+	}
+	year[1][3].ContributionCount = 10
+
+	heatmap := LithophaneHeatmap([][][]types.ContributionDay{year, year}, 10)
+	if got := heatmap.Bounds().Size(); got != image.Pt(2+4, 14+4) {
+		t.Fatalf("heatmap size = %v, want 6x18", got)
+	}
+	tests := []struct {
+		name string
+		x, y int
+		want uint8
+	}{
+		{"frame", 0, 0, 0xff},
+		{"quiet day", 2, 2, 0},
+		{"busiest day, front row", 3, 2 + 3, 0xff},
+		{"busiest day, back row", 3, 2 + 7 + 3, 0xff},
+	}
+	for _, tt := range tests {
+		if got := heatmap.GrayAt(tt.x, tt.y).Y; got != tt.want {
+			t.Errorf("%s: pixel (%d, %d) = %d, want %d", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+// TestCreateLithophane verifies the panel is a closed solid whose thickness follows the heatmap
+func TestCreateLithophane(t *testing.T) {
+	heatmap := image.NewGray(image.Rect(0, 0, 3, 2))
+	heatmap.SetGray(1, 1, color.Gray{Y: 0xff})
+
+	triangles, err := CreateLithophane(heatmap, 3*CellSize, 2*CellSize)
+	if err != nil {
+		t.Fatalf("CreateLithophane() error = %v", err)
+	}
+	cols, rows := 3*LithophaneSubdivisions, 2*LithophaneSubdivisions
+	if want := 4*cols*rows + 4*(cols+rows); len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+
+	assertClosedMesh(t, triangles)
+
+	thickest, thinnest := 0.0, math.Inf(1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Z > 0 {
+				thickest, thinnest = math.Max(thickest, v.Z), math.Min(thinnest, v.Z)
+			}
+		}
+	}
+	if math.Abs(thickest-LithophaneMaxThickness) > epsilon || math.Abs(thinnest-LithophaneMinThickness) > epsilon {
+		t.Errorf("thickness ranges %v to %v, want %v to %v", thinnest, thickest, LithophaneMinThickness, LithophaneMaxThickness)
+	}
+
+	if _, err := CreateLithophane(image.NewGray(image.Rectangle{}), 10, 10); err == nil {
+		t.Error("CreateLithophane() accepted an empty heatmap")
+	}
+}
+
+// TestLithophaneTriangleCount verifies the count matches a generated panel
+func TestLithophaneTriangleCount(t *testing.T) {
+	year := make([][]types.ContributionDay, 5)
+	for w := range year {
+		year[w] = make([]types.ContributionDay, 7)
+	}
+	heatmap := LithophaneHeatmap([][][]types.ContributionDay{year}, 1)
+	triangles, err := CreateLithophane(heatmap, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := LithophaneTriangleCount(5, 1); len(triangles) != want {
+		t.Errorf("LithophaneTriangleCount() = %d, generated %d", want, len(triangles))
+	}
+}
@@ -0,0 +1,76 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// MoldTriangleCount returns the most triangles CreateMoldGeometry generates for a row of
+// the given number of weeks: a box for every cell and for each piece of the rim.
+func MoldTriangleCount(weeks int) int {
+	return (max(weeks, GridSize)*7 + 4) * 12
+}
+
+// CreateMoldGeometry generates a single year's contributions subtracted from a solid
+// block MaxHeight tall, for a mold-like model in which every active day is a valley as
+// deep as its column would be tall. The block spans weeks weeks, at least a year, so rows
+// of different lengths line up; days without contributions and weeks without data stay
+// solid. The row at yearIndex also fills the margin on either side of it, and the front
+// and back rows the margins in front of and behind the grid, so together the rows cover
+// the CalculateGridDimensions of the model's rows and every valley is walled in.
+func CreateMoldGeometry(contributions [][]types.ContributionDay, yearIndex, rows, weeks, maxContrib int) ([]types.Triangle, error) {
+	return createRecessedBlock(contributions, yearIndex, rows, weeks, MaxHeight, func(count int) float64 {
+		return NormalizeContribution(count, maxContrib)
+	})
+}
+
+// createRecessedBlock builds a block height tall over a row of the grid, with each day's
+// cell lowered by the depth of its count, and the rims described for CreateMoldGeometry.
+func createRecessedBlock(contributions [][]types.ContributionDay, yearIndex, rows, weeks int, top float64, depth func(count int) float64) ([]types.Triangle, error) {
+	weeks = max(weeks, GridSize)
+	triangles := make([]types.Triangle, 0, MoldTriangleCount(weeks))
+	add := func(x, y, width, depth, height float64) error {
+		if height <= 0 {
+			return nil
+		}
+		box, err := createBox(x, y, 0, width, depth, height)
+		if err != nil {
+			return errors.Wrap(err, "failed to create block")
+		}
+		triangles = append(triangles, box...)
+		return nil
+	}
+
+	for weekIdx := range weeks {
+		for dayIdx := range 7 {
+			height := top
+			if weekIdx < len(contributions) && dayIdx < len(contributions[weekIdx]) {
+				height -= depth(contributions[weekIdx][dayIdx].ContributionCount)
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+			if err := add(x, y, CellSize, CellSize, height); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	width, _ := CalculateGridDimensions(weeks, rows)
+	margin := 2 * CellSize
+	_, y := CellPosition(0, 0, yearIndex)
+	rim := [][4]float64{
+		{0, y, margin, YearOffset},
+		{width - margin, y, margin, YearOffset},
+	}
+	if yearIndex == 0 {
+		rim = append(rim, [4]float64{0, 0, width, margin})
+	}
+	if yearIndex == rows-1 {
+		rim = append(rim, [4]float64{0, y + YearOffset, width, margin})
+	}
+	for _, r := range rim {
+		if err := add(r[0], r[1], r[2], r[3], top); err != nil {
+			return nil, err
+		}
+	}
+	return triangles, nil
+}
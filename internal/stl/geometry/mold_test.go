@@ -0,0 +1,56 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateMoldGeometry(t *testing.T) {
+	rows := [][][]types.ContributionDay{
+		{streakWeek(1, 4, 9, 16, 9, 4, 1), streakWeek(0, 0, 2)},
+		{streakWeek(0, 16, 0, 0, 0, 0, 0)},
+	}
+	weeks := 2
+
+	// The rows together fill the block over the whole grid, less a column's volume for
+	// every active day.
+	width, depth := CalculateGridDimensions(weeks, len(rows))
+	want := width * depth * MaxHeight
+	var mold []types.Triangle
+	for i, row := range rows {
+		triangles, err := CreateMoldGeometry(row, len(rows)-1-i, len(rows), weeks, 16)
+		if err != nil {
+			t.Fatalf("CreateMoldGeometry() error = %v", err)
+		}
+		if len(triangles) > MoldTriangleCount(weeks) {
+			t.Errorf("got %d triangles, more than MoldTriangleCount() = %d", len(triangles), MoldTriangleCount(weeks))
+		}
+		mold = append(mold, triangles...)
+		for _, week := range row {
+			for _, day := range week {
+				want -= CellSize * CellSize * NormalizeContribution(day.ContributionCount, 16)
+			}
+		}
+	}
+	if got := signedVolume(mold); math.Abs(got-want) > 1e-6 {
+		t.Errorf("mold volume = %v, want %v", got, want)
+	}
+
+	for _, tri := range mold {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.X < -epsilon || v.X > width+epsilon || v.Y < -epsilon || v.Y > depth+epsilon || v.Z < 0 || v.Z > MaxHeight+epsilon {
+				t.Fatalf("vertex %v outside the %v x %v block", v, width, depth)
+			}
+		}
+	}
+
+	// The busiest day is a valley down to the base, so nothing stands over it.
+	x, y := CellPosition(0, 1, 0)
+	for _, tri := range mold {
+		if insideTriangle(tri, x+CellSize/2, y+CellSize/2) {
+			t.Fatalf("triangle %v covers the busiest day", tri)
+		}
+	}
+}
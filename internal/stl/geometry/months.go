@@ -0,0 +1,98 @@
+package geometry
+
+import (
+	"math"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// monthStripDepth is the depth of the strip along the front of the top face that month
+	// initials are engraved into: the margin in front of the contribution grid.
+	monthStripDepth = 2 * CellSize
+
+	// monthFontSize is the font size of the month initials at the front panel's resolution.
+	monthFontSize = 45.0
+)
+
+// MonthTick is a month's initial and where it is engraved along the front of the base.
+type MonthTick struct {
+	X       float64 // Centre of the week column the month starts in
+	Initial string  // First letter of the month's name
+}
+
+// MonthTicks returns a tick for each week of a row that holds the first day of a month,
+// centred on that week's column. offsetX is the shift of the columns from their usual place.
+// Days without a date, such as padding, are ignored.
+func MonthTicks(weeks [][]types.ContributionDay, offsetX float64) []MonthTick {
+	var ticks []MonthTick
+	for weekIdx, week := range weeks {
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err != nil || date.Day() != 1 {
+				continue
+			}
+			x, _ := CellPosition(weekIdx, 0, 0)
+			ticks = append(ticks, MonthTick{X: offsetX + x + CellSize/2, Initial: date.Month().String()[:1]})
+			break
+		}
+	}
+	return ticks
+}
+
+// MonthTowerTicks returns a tick for each month of a row aggregated by month, centred on
+// the month's tower as CreateMonthGeometry sizes it rather than on the week it starts in.
+// offsetX is the shift of the columns from their usual place.
+func MonthTowerTicks(weeks [][]types.ContributionDay, offsetX float64) []MonthTick {
+	var ticks []MonthTick
+	for _, span := range MonthSpans(weeks) {
+		month, err := time.Parse("2006-01", span.Month)
+		if err != nil {
+			continue
+		}
+		first, _ := CellPosition(span.First, 0, 0)
+		last, _ := CellPosition(span.Last, 0, 0)
+		ticks = append(ticks, MonthTick{X: offsetX + (first+last+CellSize)/2, Initial: month.Month().String()[:1]})
+	}
+	return ticks
+}
+
+// engraveMonths builds the top skin of the month strip with the initials of ticks left
+// out, spanning [lo, hi] across the base and from front to the back of the strip.
+func engraveMonths(ticks []MonthTick, baseWidth, front, lo, hi float64) ([]types.Triangle, error) {
+	height := monthStripDepth - front
+	pixelsPerUnit := baseWidthVoxelResolution / maxPanelWidth
+	dc := newFaceContext(int(math.Round(baseWidth*pixelsPerUnit)), baseWidth, height)
+	for _, tick := range ticks {
+		if err := drawText(dc, tick.Initial, "center", tick.X*pixelsPerUnit, monthFontSize); err != nil {
+			return nil, err
+		}
+	}
+
+	skin, err := engraveSkin(dc, baseWidth, height, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	return placeOnTop(skin, monthStripDepth), nil
+}
+
+// placeOnTop lays geometry built on the front face flat into the top face, rotating it
+// about X so the top of the face lies at depth y and what protruded towards -Y points up.
+func placeOnTop(triangles []types.Triangle, y float64) []types.Triangle {
+	transform := func(p types.Point3D, translate bool) types.Point3D {
+		if !translate {
+			return types.Point3D{X: p.X, Y: p.Z, Z: -p.Y}
+		}
+		return types.Point3D{X: p.X, Y: y + p.Z, Z: -p.Y}
+	}
+	for i, t := range triangles {
+		triangles[i] = types.Triangle{
+			Normal: transform(t.Normal, false),
+			V1:     transform(t.V1, true),
+			V2:     transform(t.V2, true),
+			V3:     transform(t.V3, true),
+		}
+	}
+	return triangles
+}
@@ -0,0 +1,102 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// yearWeeks lays out the days of year in Sunday-first weeks, as GitHub returns them.
+func yearWeeks(year int) [][]types.ContributionDay {
+	var weeks [][]types.ContributionDay
+	for d := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		if len(weeks) == 0 || d.Weekday() == time.Sunday {
+			weeks = append(weeks, nil)
+		}
+		weeks[len(weeks)-1] = append(weeks[len(weeks)-1], types.ContributionDay{Date: d.Format("2006-01-02")})
+	}
+	return weeks
+}
+
+func TestMonthTicks(t *testing.T) {
+	ticks := MonthTicks(yearWeeks(2024), 0)
+	if len(ticks) != 12 {
+		t.Fatalf("got %d ticks, want 12", len(ticks))
+	}
+	var initials string
+	for _, tick := range ticks {
+		initials += tick.Initial
+	}
+	if initials != "JFMAMJJASOND" {
+		t.Errorf("initials = %q, want JFMAMJJASOND", initials)
+	}
+
+	// 2024-01-01 is a Monday in the first week; 2024-02-01 is a Thursday in the fifth.
+	if want := 2*CellSize + CellSize/2; math.Abs(ticks[0].X-want) > epsilon {
+		t.Errorf("January at x = %v, want %v", ticks[0].X, want)
+	}
+	if want := 2*CellSize + 4*CellSize + CellSize/2; math.Abs(ticks[1].X-want) > epsilon {
+		t.Errorf("February at x = %v, want %v", ticks[1].X, want)
+	}
+
+	shifted := MonthTicks(yearWeeks(2024), 10)
+	if math.Abs(shifted[0].X-ticks[0].X-10) > epsilon {
+		t.Errorf("offset tick at x = %v, want %v", shifted[0].X, ticks[0].X+10)
+	}
+
+	if ticks := MonthTicks([][]types.ContributionDay{{{}, {Date: "2024-03-05"}}}, 0); len(ticks) != 0 {
+		t.Errorf("got %d ticks for a week without a first of the month, want 0", len(ticks))
+	}
+}
+
+func TestCreateStatsBaseMonths(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	opts := TextOptions{Months: MonthTicks(yearWeeks(2024), 0)}
+
+	for _, base := range []BaseOptions{{}, {Style: BaseRounded}, {Connectors: true}, {StudSockets: true}} {
+		triangles, err := CreateStatsBase(width, depth, BaseHeight, opts, base)
+		if err != nil {
+			t.Fatalf("CreateStatsBase(%+v) error = %v", base, err)
+		}
+
+		// The top of the strip in front of the grid is covered except where the initials
+		// are cut into it, and nothing rises above the top of the base.
+		covered := 0.0
+		for _, tri := range triangles {
+			inStrip := tri.V1.Y < monthStripDepth+epsilon && tri.V2.Y < monthStripDepth+epsilon && tri.V3.Y < monthStripDepth+epsilon
+			if inStrip && math.Abs(tri.Normal.Z-1) < epsilon && math.Abs(tri.V1.Z) < epsilon {
+				covered += math.Abs((tri.V2.X-tri.V1.X)*(tri.V3.Y-tri.V1.Y)-(tri.V3.X-tri.V1.X)*(tri.V2.Y-tri.V1.Y)) / 2
+			}
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if v.Z > epsilon || v.Y < -epsilon || v.Y > depth+epsilon {
+					t.Fatalf("vertex %v lies outside the base", v)
+				}
+			}
+		}
+		if strip := width * monthStripDepth; covered < strip/2 || covered > strip-1 {
+			t.Errorf("CreateStatsBase(%+v) covers %.1f of the %.1f strip; want the initials cut out", base, covered, strip)
+		}
+	}
+
+	// Without months or stats the base is the plain slab.
+	plain, err := CreateStatsBase(width, depth, BaseHeight, TextOptions{}, BaseOptions{})
+	if err != nil {
+		t.Fatalf("CreateStatsBase() error = %v", err)
+	}
+	if want, _ := CreateStyledBase(width, depth, BaseOptions{}); len(plain) != len(want) {
+		t.Errorf("got %d triangles, want the %d of a plain base", len(plain), len(want))
+	}
+}
+
+func TestMonthTowerTicks(t *testing.T) {
+	ticks := MonthTowerTicks(yearWeeks(2024), 0)
+	if len(ticks) != 12 || ticks[0].Initial != "J" || ticks[11].Initial != "D" {
+		t.Fatalf("MonthTowerTicks() = %v, want twelve months from January", ticks)
+	}
+	// January spans the first four weeks, so its initial sits between the second and third.
+	if want := 2*CellSize + 2*CellSize; math.Abs(ticks[0].X-want) > epsilon {
+		t.Errorf("January at x = %v, want %v", ticks[0].X, want)
+	}
+}
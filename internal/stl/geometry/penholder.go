@@ -0,0 +1,95 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Pen holder dimensions. The contribution grid is wrapped around a hollow cylinder whose
+// circumference is the width of the flat model, so each cell keeps its size.
+// All measurements are in millimeters.
+const (
+	PenHolderWall      float64 = 2.0  // Thickness of the cylinder wall
+	PenHolderFloor     float64 = 3.0  // Thickness of the closed bottom
+	PenHolderMinHeight float64 = 80.0 // Least height, so pens stand up in the holder
+	PenHolderRelief    float64 = 0.4  // Scale of the column heights standing out from the wall
+)
+
+// PenHolderRadius returns the outer radius of a pen holder whose circumference is width.
+func PenHolderRadius(width float64) float64 {
+	return width / (2 * math.Pi)
+}
+
+// CreatePenHolder builds a closed-bottom hollow cylinder standing on Z = 0 whose outer
+// circumference is width, with one flat side per cell so wrapped columns sit flush on it.
+func CreatePenHolder(width, height float64) ([]types.Triangle, error) {
+	segments := int(math.Round(width / CellSize))
+	if segments < 3 || height <= PenHolderFloor {
+		return nil, errors.New(errors.ValidationError, "pen holder is too small", nil)
+	}
+	outer := PenHolderRadius(width)
+	inner := outer - PenHolderWall
+	if inner <= 0 {
+		return nil, errors.New(errors.ValidationError, "pen holder is too narrow for its wall", nil)
+	}
+
+	at := func(i int, r, z float64) types.Point3D {
+		angle := 2 * math.Pi * float64(i%segments) / float64(segments)
+		return types.Point3D{X: r * math.Cos(angle), Y: r * math.Sin(angle), Z: z}
+	}
+	bottomCentre := types.Point3D{}
+	floorCentre := types.Point3D{Z: PenHolderFloor}
+
+	var triangles []types.Triangle
+	for i := range segments {
+		quads := [][4]types.Point3D{
+			{at(i, outer, 0), at(i+1, outer, 0), at(i+1, outer, height), at(i, outer, height)},                           // Outer wall
+			{at(i+1, inner, PenHolderFloor), at(i, inner, PenHolderFloor), at(i, inner, height), at(i+1, inner, height)}, // Inner wall
+			{at(i, inner, height), at(i, outer, height), at(i+1, outer, height), at(i+1, inner, height)},                 // Rim
+		}
+		for _, q := range quads {
+			quad, err := CreateQuad(q[0], q[1], q[2], q[3])
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create pen holder wall")
+			}
+			triangles = append(triangles, quad...)
+		}
+
+		var err error
+		for _, tri := range [][3]types.Point3D{
+			{bottomCentre, at(i+1, outer, 0), at(i, outer, 0)},                          // Underside
+			{floorCentre, at(i, inner, PenHolderFloor), at(i+1, inner, PenHolderFloor)}, // Floor inside
+		} {
+			if triangles, err = appendTriangle(triangles, tri); err != nil {
+				return nil, errors.Wrap(err, "failed to create pen holder floor")
+			}
+		}
+	}
+	return triangles, nil
+}
+
+// WrapCylinder bends triangles laid out on a flat model of the given width around the
+// outside of a pen holder of that circumference, in place. X runs around the cylinder,
+// Y up its wall and Z outwards from its surface, scaled by PenHolderRelief. Normals are
+// recomputed for the bent triangles.
+func WrapCylinder(triangles []types.Triangle, width float64) error {
+	radius := PenHolderRadius(width)
+	wrap := func(p types.Point3D) types.Point3D {
+		angle := p.X / radius
+		r := radius + p.Z*PenHolderRelief
+		return types.Point3D{X: r * math.Cos(angle), Y: r * math.Sin(angle), Z: p.Y}
+	}
+
+	for i := range triangles {
+		tri := &triangles[i]
+		tri.V1, tri.V2, tri.V3 = wrap(tri.V1), wrap(tri.V2), wrap(tri.V3)
+		normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+		if err != nil {
+			return errors.Wrap(err, "failed to wrap triangle")
+		}
+		tri.Normal = normal
+	}
+	return nil
+}
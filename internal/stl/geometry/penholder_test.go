@@ -0,0 +1,65 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestCreatePenHolder verifies the holder is a closed solid of the expected size
+func TestCreatePenHolder(t *testing.T) {
+	width := 20 * CellSize
+	triangles, err := CreatePenHolder(width, PenHolderMinHeight)
+	if err != nil {
+		t.Fatalf("CreatePenHolder() error = %v", err)
+	}
+	if want := 20 * 8; len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+
+	assertClosedMesh(t, triangles)
+
+	radius := PenHolderRadius(width)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if math.Hypot(v.X, v.Y) > radius+epsilon || v.Z < 0 || v.Z > PenHolderMinHeight {
+				t.Fatalf("vertex %v outside the holder", v)
+			}
+		}
+	}
+
+	if _, err := CreatePenHolder(3*CellSize, PenHolderMinHeight); err == nil {
+		t.Error("CreatePenHolder() accepted a holder too narrow for its wall")
+	}
+}
+
+// TestWrapCylinder verifies flat coordinates map onto the holder's wall
+func TestWrapCylinder(t *testing.T) {
+	width := 20 * CellSize
+	radius := PenHolderRadius(width)
+	box, err := createBox(5*CellSize, 10, 0, CellSize, CellSize, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WrapCylinder(box, width); err != nil {
+		t.Fatalf("WrapCylinder() error = %v", err)
+	}
+
+	for _, tri := range box {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			r := math.Hypot(v.X, v.Y)
+			if math.Abs(r-radius) > epsilon && math.Abs(r-radius-10*PenHolderRelief) > epsilon {
+				t.Fatalf("vertex %v at radius %v, want %v or %v", v, r, radius, radius+10*PenHolderRelief)
+			}
+			if v.Z < 10-epsilon || v.Z > 10+CellSize+epsilon {
+				t.Fatalf("vertex %v outside the box's height on the wall", v)
+			}
+		}
+		// Faces keep pointing out of the solid: the top of the box faces away from the axis.
+		centre := types.Point3D{X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3, Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3}
+		if math.Hypot(centre.X, centre.Y) > radius+9*PenHolderRelief && tri.Normal.X*centre.X+tri.Normal.Y*centre.Y <= 0 {
+			t.Errorf("outer face %v points towards the axis", tri)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package geometry
+
+import (
+	"slices"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Plaque dimensions. A plaque is a plate hung on a wall, with the contribution columns
+// embossed on its face at a reduced relief and keyhole slots recessed into its back. When
+// it hangs, the back edge of the model is at the top.
+// All measurements are in millimeters.
+const (
+	PlaqueThickness float64 = 6.0 // Thickness of the plate below the columns
+	PlaqueRelief    float64 = 0.2 // Scale of the column heights embossed on the face
+
+	KeyholeHead float64 = 9.0  // Width of the opening a screw head passes through
+	KeyholeNeck float64 = 4.5  // Width of the slot the screw shank slides up
+	KeyholeSlot float64 = 10.0 // Length of the slot above the head opening
+
+	// keyholeNeckDepth is the depth of the narrow slot from the back of the plate; the
+	// channel behind it, as wide as the head opening, reaches keyholeDepth.
+	keyholeNeckDepth = 2.0
+	keyholeDepth     = 4.0
+
+	// keyholeMargin is the least material left between a keyhole and the side or top edge.
+	keyholeMargin = 3.0
+
+	// keyholeSpan is the least plate width that gets two keyholes instead of one.
+	keyholeSpan = 80.0
+)
+
+// rect is an axis-aligned rectangle on the XY plane.
+type rect struct{ x0, y0, x1, y1 float64 }
+
+// keyholeCentres returns the X positions of a plaque's keyholes: one in the middle of a
+// narrow plate, or one a quarter of the way in from each side of a wide one.
+func keyholeCentres(width float64) []float64 {
+	if width < keyholeSpan {
+		return []float64{width / 2}
+	}
+	return []float64{width / 4, 3 * width / 4}
+}
+
+// CreatePlaque builds a plate of the given size from Z = -PlaqueThickness to 0, with keyhole
+// slots recessed into its underside near the back edge.
+func CreatePlaque(width, depth float64) ([]types.Triangle, error) {
+	length := KeyholeHead + KeyholeSlot
+	if width < KeyholeHead+2*keyholeMargin || depth < length+2*keyholeMargin {
+		return nil, errors.New(errors.ValidationError, "plaque is too small for keyhole slots", nil)
+	}
+
+	var heads, necks, channels []rect
+	y0 := depth - keyholeMargin - length
+	for _, cx := range keyholeCentres(width) {
+		heads = append(heads, rect{cx - KeyholeHead/2, y0, cx + KeyholeHead/2, y0 + KeyholeHead})
+		necks = append(necks, rect{cx - KeyholeNeck/2, y0 + KeyholeHead, cx + KeyholeNeck/2, y0 + length})
+		channels = append(channels, rect{cx - KeyholeHead/2, y0, cx + KeyholeHead/2, y0 + length})
+	}
+
+	bottom := -PlaqueThickness
+	var triangles []types.Triangle
+	for _, layer := range []struct {
+		z, height float64
+		holes     []rect
+	}{
+		{bottom, keyholeNeckDepth, slices.Concat(heads, necks)},
+		{bottom + keyholeNeckDepth, keyholeDepth - keyholeNeckDepth, channels},
+		{bottom + keyholeDepth, PlaqueThickness - keyholeDepth, nil},
+	} {
+		slab, err := createSlabWithHoles(rect{0, 0, width, depth}, layer.z, layer.height, layer.holes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create plaque")
+		}
+		triangles = append(triangles, slab...)
+	}
+	return triangles, nil
+}
+
+// createSlabWithHoles builds a horizontal slab over bounds with the holes cut through it.
+// The slab is divided along every hole edge and the solid cells of each band are merged
+// into boxes.
+func createSlabWithHoles(bounds rect, z, height float64, holes []rect) ([]types.Triangle, error) {
+	xs, ys := []float64{bounds.x0, bounds.x1}, []float64{bounds.y0, bounds.y1}
+	for _, h := range holes {
+		xs = append(xs, h.x0, h.x1)
+		ys = append(ys, h.y0, h.y1)
+	}
+	slices.Sort(xs)
+	slices.Sort(ys)
+	xs, ys = slices.Compact(xs), slices.Compact(ys)
+
+	solid := func(x, y float64) bool {
+		for _, h := range holes {
+			if x > h.x0 && x < h.x1 && y > h.y0 && y < h.y1 {
+				return false
+			}
+		}
+		return true
+	}
+
+	var triangles []types.Triangle
+	for j := 0; j+1 < len(ys); j++ {
+		cy := (ys[j] + ys[j+1]) / 2
+		for i := 0; i+1 < len(xs); {
+			if !solid((xs[i]+xs[i+1])/2, cy) {
+				i++
+				continue
+			}
+			end := i + 1
+			for end+1 < len(xs) && solid((xs[end]+xs[end+1])/2, cy) {
+				end++
+			}
+			box, err := createBox(xs[i], ys[j], z, xs[end]-xs[i], ys[j+1]-ys[j], height)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, box...)
+			i = end
+		}
+	}
+	return triangles, nil
+}
@@ -0,0 +1,115 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// boxBounds returns the footprint of each box in triangles built from createBox.
+func boxBounds(triangles []types.Triangle) []rect {
+	var boxes []rect
+	for i := 0; i+12 <= len(triangles); i += 12 {
+		b := rect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+		for _, tri := range triangles[i : i+12] {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				b.x0, b.y0 = math.Min(b.x0, v.X), math.Min(b.y0, v.Y)
+				b.x1, b.y1 = math.Max(b.x1, v.X), math.Max(b.y1, v.Y)
+			}
+		}
+		boxes = append(boxes, b)
+	}
+	return boxes
+}
+
+// TestCreateSlabWithHoles verifies the boxes cover the slab except for its holes
+func TestCreateSlabWithHoles(t *testing.T) {
+	holes := []rect{{2, 2, 4, 6}, {4, 4, 5, 8}}
+	triangles, err := createSlabWithHoles(rect{0, 0, 10, 10}, 0, 1, holes)
+	if err != nil {
+		t.Fatalf("createSlabWithHoles() error = %v", err)
+	}
+
+	area := 0.0
+	for _, b := range boxBounds(triangles) {
+		area += (b.x1 - b.x0) * (b.y1 - b.y0)
+		for _, h := range holes {
+			if b.x0 < h.x1 && b.x1 > h.x0 && b.y0 < h.y1 && b.y1 > h.y0 {
+				t.Errorf("box %v overlaps hole %v", b, h)
+			}
+		}
+	}
+	if want := 100.0 - 8 - 4; math.Abs(area-want) > epsilon {
+		t.Errorf("solid area = %v, want %v", area, want)
+	}
+}
+
+// TestCreatePlaque verifies the plate's extent and its keyhole recesses
+func TestCreatePlaque(t *testing.T) {
+	width, depth := 100.0, 30.0
+	triangles, err := CreatePlaque(width, depth)
+	if err != nil {
+		t.Fatalf("CreatePlaque() error = %v", err)
+	}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.X < 0 || v.X > width || v.Y < 0 || v.Y > depth || v.Z < -PlaqueThickness || v.Z > 0 {
+				t.Fatalf("vertex %v outside the plate", v)
+			}
+		}
+	}
+
+	// Probe the material at points of a keyhole: the head opening is open through both
+	// recessed layers, the slot is narrow at the back and as wide as the head behind it.
+	bottom := -PlaqueThickness
+	y0 := depth - keyholeMargin - KeyholeHead - KeyholeSlot
+	slotY := y0 + KeyholeHead + KeyholeSlot/2
+	beside := (KeyholeNeck + KeyholeHead) / 4
+	for _, cx := range keyholeCentres(width) {
+		for _, probe := range []struct {
+			name  string
+			p     types.Point3D
+			solid bool
+		}{
+			{"head opening", types.Point3D{X: cx, Y: y0 + KeyholeHead/2, Z: bottom + 1}, false},
+			{"head channel", types.Point3D{X: cx, Y: y0 + KeyholeHead/2, Z: bottom + 3}, false},
+			{"slot", types.Point3D{X: cx, Y: slotY, Z: bottom + 1}, false},
+			{"beside slot", types.Point3D{X: cx + beside, Y: slotY, Z: bottom + 1}, true},
+			{"channel beside slot", types.Point3D{X: cx + beside, Y: slotY, Z: bottom + 3}, false},
+			{"above keyhole", types.Point3D{X: cx, Y: slotY, Z: bottom + 5}, true},
+		} {
+			if got := solidAt(triangles, probe.p); got != probe.solid {
+				t.Errorf("%s at %v: solid = %v, want %v", probe.name, probe.p, got, probe.solid)
+			}
+		}
+	}
+
+	if got := len(keyholeCentres(width)); got != 2 {
+		t.Errorf("%v mm plaque has %d keyholes, want 2", width, got)
+	}
+	if got := len(keyholeCentres(40)); got != 1 {
+		t.Errorf("40 mm plaque has %d keyholes, want 1", got)
+	}
+	if _, err := CreatePlaque(width, 15); err == nil {
+		t.Error("CreatePlaque() accepted a plate too shallow for its keyholes")
+	}
+}
+
+// solidAt reports whether any of the boxes in triangles built from createBox contains p.
+func solidAt(triangles []types.Triangle, p types.Point3D) bool {
+	for i := 0; i+12 <= len(triangles); i += 12 {
+		lo := types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+		hi := types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+		for _, tri := range triangles[i : i+12] {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				lo = types.Point3D{X: math.Min(lo.X, v.X), Y: math.Min(lo.Y, v.Y), Z: math.Min(lo.Z, v.Z)}
+				hi = types.Point3D{X: math.Max(hi.X, v.X), Y: math.Max(hi.Y, v.Y), Z: math.Max(hi.Z, v.Z)}
+			}
+		}
+		if p.X > lo.X && p.X < hi.X && p.Y > lo.Y && p.Y < hi.Y && p.Z > lo.Z && p.Z < hi.Z {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// SeparatorWidth is the thickness of the ridge between neighbouring rows, centred on
+	// the line where their columns meet.
+	SeparatorWidth = CellSize / 2
+
+	// SeparatorHeight is how far the ridges between rows stand above the base, low enough
+	// to leave the shortest columns standing clear of them.
+	SeparatorHeight = MinHeight / 2
+)
+
+// CreateRowSeparators generates a low ridge along each line between rowCount rows of
+// weekCount weeks, so rows wrapped from one year read as separate lines.
+func CreateRowSeparators(rowCount, weekCount int) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	x, _ := CellPosition(0, 0, 0)
+	for row := 1; row < rowCount; row++ {
+		_, y := CellPosition(0, 0, row)
+		ridge, err := createBox(x, y-SeparatorWidth/2, 0, float64(weekCount)*CellSize, SeparatorWidth, SeparatorHeight)
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, ridge...)
+	}
+	return triangles, nil
+}
+
+// SeparatorTriangleCount returns the number of triangles CreateRowSeparators generates
+// for rowCount rows.
+func SeparatorTriangleCount(rowCount int) int {
+	return TowerTriangles * max(rowCount-1, 0)
+}
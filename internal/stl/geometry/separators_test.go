@@ -0,0 +1,34 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+)
+
+func TestCreateRowSeparators(t *testing.T) {
+	triangles, err := CreateRowSeparators(3, 26)
+	if err != nil {
+		t.Fatalf("CreateRowSeparators() error = %v", err)
+	}
+	if len(triangles) != SeparatorTriangleCount(3) || SeparatorTriangleCount(3) != 2*TowerTriangles {
+		t.Fatalf("CreateRowSeparators() = %d triangles, want two ridges", len(triangles))
+	}
+
+	// The ridges straddle the lines between rows and span their weeks.
+	inv := meshtest.Measure(triangles)
+	x, _ := CellPosition(0, 0, 0)
+	_, first := CellPosition(0, 0, 1)
+	_, second := CellPosition(0, 0, 2)
+	if inv.Min.X != x || math.Abs(inv.Max.X-(x+26*CellSize)) > 1e-9 || inv.Max.Z != SeparatorHeight {
+		t.Errorf("ridges span %v to %v, want 26 weeks wide and %g tall", inv.Min, inv.Max, SeparatorHeight)
+	}
+	if math.Abs(inv.Min.Y-(first-SeparatorWidth/2)) > 1e-9 || math.Abs(inv.Max.Y-(second+SeparatorWidth/2)) > 1e-9 {
+		t.Errorf("ridges run from y %g to %g, want them centred on %g and %g", inv.Min.Y, inv.Max.Y, first, second)
+	}
+
+	if single, err := CreateRowSeparators(1, 26); err != nil || len(single) != 0 {
+		t.Errorf("CreateRowSeparators() of a single row = %d triangles, %v, want none", len(single), err)
+	}
+}
@@ -2,6 +2,8 @@
 package geometry
 
 import (
+	"math"
+
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -107,3 +109,75 @@ func createBox(x, y, z, width, height, depth float64) ([]types.Triangle, error)
 
 	return triangles, nil
 }
+
+// ScaleHeights multiplies the Z coordinates of triangles standing on Z = 0 in place and
+// recomputes their normals, which tilt on sloped faces.
+func ScaleHeights(triangles []types.Triangle, scale float64) error {
+	for i := range triangles {
+		tri := &triangles[i]
+		tri.V1.Z *= scale
+		tri.V2.Z *= scale
+		tri.V3.Z *= scale
+		normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+		if err != nil {
+			return errors.Wrap(err, "failed to scale triangle")
+		}
+		tri.Normal = normal
+	}
+	return nil
+}
+
+// MirrorX reflects triangles across the plane X = width/2 in place, so what ran from 0 to
+// width runs from width to 0. Each triangle's winding is reversed to keep it facing out.
+func MirrorX(triangles []types.Triangle, width float64) {
+	for i := range triangles {
+		tri := &triangles[i]
+		tri.V1.X, tri.V2.X, tri.V3.X = width-tri.V1.X, width-tri.V2.X, width-tri.V3.X
+		tri.V2, tri.V3 = tri.V3, tri.V2
+		tri.Normal.X = -tri.Normal.X
+	}
+}
+
+// RotateHalfTurn turns triangles half a turn about the vertical axis through (cx, cy) in
+// place, so geometry reading from the front reads from the back. A rotation keeps each
+// triangle's winding, so only the normal turns with it.
+func RotateHalfTurn(triangles []types.Triangle, cx, cy float64) {
+	turn := func(p *types.Point3D) {
+		p.X, p.Y = 2*cx-p.X, 2*cy-p.Y
+	}
+	for i := range triangles {
+		tri := &triangles[i]
+		turn(&tri.V1)
+		turn(&tri.V2)
+		turn(&tri.V3)
+		tri.Normal.X, tri.Normal.Y = -tri.Normal.X, -tri.Normal.Y
+	}
+}
+
+// SnapVertices rounds every coordinate of triangles to the nearest multiple of step, in
+// place, welding vertices that floating-point error left a hair apart so slicers see
+// closed seams. Normals of moved triangles are recomputed, and triangles that collapse
+// to a line or point are dropped; the kept triangles are returned in their order.
+func SnapVertices(triangles []types.Triangle, step float64) []types.Triangle {
+	snap := func(p *types.Point3D) {
+		p.X = math.Round(p.X/step) * step
+		p.Y = math.Round(p.Y/step) * step
+		p.Z = math.Round(p.Z/step) * step
+	}
+	kept := triangles[:0]
+	for _, tri := range triangles {
+		original := tri
+		snap(&tri.V1)
+		snap(&tri.V2)
+		snap(&tri.V3)
+		if tri != original {
+			normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+			if err != nil {
+				continue
+			}
+			tri.Normal = normal
+		}
+		kept = append(kept, tri)
+	}
+	return kept
+}
@@ -2,6 +2,7 @@ package geometry
 
 import (
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/github/gh-skyline/internal/types"
@@ -191,3 +192,84 @@ func TestCreateBox(t *testing.T) {
 		}
 	})
 }
+
+// TestScaleHeights verifies heights are multiplied and normals follow the new slopes
+func TestScaleHeights(t *testing.T) {
+	ramp := []types.Triangle{{V1: types.Point3D{}, V2: types.Point3D{X: 1}, V3: types.Point3D{X: 1, Y: 1, Z: 1}}}
+	if err := ScaleHeights(ramp, 2); err != nil {
+		t.Fatalf("ScaleHeights() error = %v", err)
+	}
+	if ramp[0].V3.Z != 2 {
+		t.Errorf("scaled height = %v, want 2", ramp[0].V3.Z)
+	}
+	want := types.Point3D{X: 0, Y: -2 / math.Sqrt(5), Z: 1 / math.Sqrt(5)}
+	if n := ramp[0].Normal; math.Abs(n.X-want.X) > epsilon || math.Abs(n.Y-want.Y) > epsilon || math.Abs(n.Z-want.Z) > epsilon {
+		t.Errorf("normal = %v, want %v", n, want)
+	}
+}
+
+func TestMirrorX(t *testing.T) {
+	cube, err := CreateCuboidBase(2, 1)
+	if err != nil {
+		t.Fatalf("CreateCuboidBase() error = %v", err)
+	}
+	mirrored := slices.Clone(cube)
+	MirrorX(mirrored, 10)
+	for i, tri := range mirrored {
+		if tri.V1.X != 10-cube[i].V1.X || tri.V2 != (types.Point3D{X: 10 - cube[i].V3.X, Y: cube[i].V3.Y, Z: cube[i].V3.Z}) {
+			t.Fatalf("triangle %d = %v, want %v reflected across X = 5", i, tri, cube[i])
+		}
+		normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(normal.X-tri.Normal.X) > epsilon || math.Abs(normal.Y-tri.Normal.Y) > epsilon || math.Abs(normal.Z-tri.Normal.Z) > epsilon {
+			t.Errorf("triangle %d normal = %v, want %v from its winding", i, tri.Normal, normal)
+		}
+	}
+}
+
+func TestRotateHalfTurn(t *testing.T) {
+	cube, err := CreateCuboidBase(2, 1)
+	if err != nil {
+		t.Fatalf("CreateCuboidBase() error = %v", err)
+	}
+	turned := slices.Clone(cube)
+	RotateHalfTurn(turned, 5, 3)
+	for i, tri := range turned {
+		want := types.Point3D{X: 10 - cube[i].V1.X, Y: 6 - cube[i].V1.Y, Z: cube[i].V1.Z}
+		if tri.V1 != want {
+			t.Fatalf("triangle %d V1 = %v, want %v turned about (5, 3)", i, tri.V1, want)
+		}
+		normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(normal.X-tri.Normal.X) > epsilon || math.Abs(normal.Y-tri.Normal.Y) > epsilon || math.Abs(normal.Z-tri.Normal.Z) > epsilon {
+			t.Errorf("triangle %d normal = %v, want %v from its winding", i, tri.Normal, normal)
+		}
+	}
+}
+
+func TestSnapVertices(t *testing.T) {
+	triangles := []types.Triangle{
+		// Corners a hair off the grid are welded onto it.
+		{V1: types.Point3D{X: 1e-7}, V2: types.Point3D{X: 1.0000004}, V3: types.Point3D{Y: 0.9999996}, Normal: types.Point3D{Z: 1}},
+		// A sliver thinner than the step collapses and is dropped.
+		{V1: types.Point3D{X: 2}, V2: types.Point3D{X: 3}, V3: types.Point3D{X: 2.5, Y: 2e-4}, Normal: types.Point3D{Z: 1}},
+		// A triangle on the grid keeps its vertices and normal.
+		{V1: types.Point3D{X: 5}, V2: types.Point3D{Y: 5}, V3: types.Point3D{Z: 5}, Normal: types.Point3D{X: 0.5, Y: 0.5, Z: 0.5}},
+	}
+	onGrid := triangles[2]
+
+	got := SnapVertices(triangles, 0.001)
+	if len(got) != 2 {
+		t.Fatalf("SnapVertices() kept %d triangles, want 2 without the sliver", len(got))
+	}
+	if want := (types.Triangle{V1: types.Point3D{}, V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}, Normal: types.Point3D{Z: 1}}); got[0] != want {
+		t.Errorf("SnapVertices() = %+v, want %+v", got[0], want)
+	}
+	if got[1] != onGrid {
+		t.Errorf("SnapVertices() moved a triangle on the grid to %+v", got[1])
+	}
+}
@@ -0,0 +1,87 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// SilhouetteThickness is the thickness of a silhouette plate, thin enough to cut from
+// sheet stock and to glow when lit from behind.
+const SilhouetteThickness = 3.0
+
+// ProfileHeights returns the tallest column height per week across every row of
+// contributions ([row][week][day]): the skyline's top profile as seen from the front.
+func ProfileHeights(contributions [][][]types.ContributionDay) []float64 {
+	maxContrib := 0
+	weeks := 0
+	for _, year := range contributions {
+		weeks = max(weeks, len(year))
+		for _, week := range year {
+			for _, day := range week {
+				maxContrib = max(maxContrib, day.ContributionCount)
+			}
+		}
+	}
+
+	heights := make([]float64, weeks)
+	for _, year := range contributions {
+		for weekIdx, week := range year {
+			for _, day := range week {
+				heights[weekIdx] = max(heights[weekIdx], NormalizeContribution(day.ContributionCount, maxContrib))
+			}
+		}
+	}
+	return heights
+}
+
+// CreateSilhouette builds the front profile of a skyline as a flat plate lying on Z = 0,
+// SilhouetteThickness thick: a strip as tall as the base across the given width, with
+// each week's column rising from it along Y to its height. Runs of weeks of equal height
+// share a single block, so the plate traces the same outline as the outline package.
+func CreateSilhouette(heights []float64, width float64) ([]types.Triangle, error) {
+	if width <= 0 {
+		return nil, errors.New(errors.ValidationError, "silhouette is empty", nil)
+	}
+	triangles, err := createBox(0, 0, 0, width, BaseHeight, SilhouetteThickness)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range silhouetteRuns(heights) {
+		x, _ := CellPosition(run.first, 0, 0)
+		block, err := createBox(x, BaseHeight, 0, float64(run.last-run.first+1)*CellSize, run.height, SilhouetteThickness)
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, block...)
+	}
+	return triangles, nil
+}
+
+// SilhouetteTriangleCount returns the number of triangles CreateSilhouette generates for
+// the given heights.
+func SilhouetteTriangleCount(heights []float64) int {
+	return TowerTriangles * (1 + len(silhouetteRuns(heights)))
+}
+
+// silhouetteRun is a run of consecutive weeks of the same, non-zero height.
+type silhouetteRun struct {
+	first, last int
+	height      float64
+}
+
+// silhouetteRuns groups the weeks of a profile into runs of equal height, leaving out
+// weeks without a column.
+func silhouetteRuns(heights []float64) []silhouetteRun {
+	var runs []silhouetteRun
+	for i, h := range heights {
+		if h <= 0 {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].last == i-1 && runs[n-1].height == h {
+			runs[n-1].last = i
+			continue
+		}
+		runs = append(runs, silhouetteRun{first: i, last: i, height: h})
+	}
+	return runs
+}
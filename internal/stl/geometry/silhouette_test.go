@@ -0,0 +1,51 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestProfileHeights(t *testing.T) {
+	contributions := [][][]types.ContributionDay{
+		{{{ContributionCount: 4}}, {{ContributionCount: 1}}},
+		{{{ContributionCount: 1}}, {{ContributionCount: 9}}, {}},
+	}
+	heights := ProfileHeights(contributions)
+	want := []float64{NormalizeContribution(4, 9), MaxHeight, 0}
+	if len(heights) != len(want) {
+		t.Fatalf("ProfileHeights() = %v, want %v", heights, want)
+	}
+	for i := range want {
+		if math.Abs(heights[i]-want[i]) > epsilon {
+			t.Errorf("week %d height = %v, want the tallest day's %v", i, heights[i], want[i])
+		}
+	}
+}
+
+func TestCreateSilhouette(t *testing.T) {
+	heights := []float64{0, 5, 5, 10, 0}
+	triangles, err := CreateSilhouette(heights, 20)
+	if err != nil {
+		t.Fatalf("CreateSilhouette() error = %v", err)
+	}
+	// The base strip and a block for each run of equal heights.
+	if want := 3 * TowerTriangles; len(triangles) != want || SilhouetteTriangleCount(heights) != want {
+		t.Fatalf("CreateSilhouette() = %d triangles, want %d", len(triangles), want)
+	}
+
+	inv := meshtest.Measure(triangles)
+	if inv.Min != (types.Point3D{}) || inv.Max != (types.Point3D{X: 20, Y: BaseHeight + 10, Z: SilhouetteThickness}) {
+		t.Errorf("silhouette spans %v to %v", inv.Min, inv.Max)
+	}
+	// The plate's area is the base strip plus each column: two weeks at 5 and one at 10.
+	if want := (20*BaseHeight + 2*CellSize*5 + CellSize*10) * SilhouetteThickness; math.Abs(inv.Volume-want) > 1e-9 {
+		t.Errorf("silhouette volume = %g, want %g", inv.Volume, want)
+	}
+
+	if _, err := CreateSilhouette(heights, 0); err == nil {
+		t.Error("CreateSilhouette() with no width should fail")
+	}
+}
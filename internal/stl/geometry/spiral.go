@@ -0,0 +1,119 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// spiralSegments is the number of flat sides approximating the round base.
+	spiralSegments = 128
+
+	// spiralLabelResolution is the width in pixels the centre label is rendered at.
+	spiralLabelResolution = 400
+
+	// spiralLabelFontSize is the largest font size of the centre label at its resolution.
+	spiralLabelFontSize = 120.0
+)
+
+// Spiral places a row of weeks around a round base, clockwise from twelve o'clock, with
+// each week's days running outwards from the centre. A row of up to a year closes into a
+// circle; longer rows follow an Archimedean spiral that moves out by a year's depth each
+// turn, so successive years sit side by side.
+type Spiral struct {
+	Centre      float64 // X and Y of the centre of the base
+	Radius      float64 // Radius of the base
+	InnerRadius float64 // Distance from the centre to the first day of the first week
+	TurnWeeks   int     // Weeks in one turn
+	Pitch       float64 // Outward step per turn
+}
+
+// NewSpiral lays out a row of the given number of weeks. The inner circle is sized so that
+// a turn's innermost cells keep their width; cells widen towards the outside.
+func NewSpiral(weeks int) Spiral {
+	s := Spiral{TurnWeeks: max(weeks, GridSize)}
+	if weeks > MaxYearWeeks {
+		s.TurnWeeks, s.Pitch = GridSize, YearOffset
+	}
+	s.InnerRadius = float64(s.TurnWeeks) * CellSize / (2 * math.Pi)
+	outer := s.InnerRadius + 7*CellSize + s.Pitch*float64(weeks)/float64(s.TurnWeeks)
+	s.Radius = outer + 2*CellSize
+	s.Centre = s.Radius
+	return s
+}
+
+// place maps a point of a flat row, laid out with CellPosition in the first row and
+// without offsets, onto the spiral.
+func (s Spiral) place(p types.Point3D) types.Point3D {
+	week := (p.X - 2*CellSize) / CellSize
+	r := s.InnerRadius + (p.Y - 2*CellSize) + s.Pitch*week/float64(s.TurnWeeks)
+	angle := math.Pi/2 - 2*math.Pi*week/float64(s.TurnWeeks)
+	return types.Point3D{X: s.Centre + r*math.Cos(angle), Y: s.Centre + r*math.Sin(angle), Z: p.Z}
+}
+
+// Wrap bends the triangles of a flat row onto the spiral, in place. Normals are
+// recomputed for the bent triangles.
+func (s Spiral) Wrap(triangles []types.Triangle) error {
+	for i := range triangles {
+		tri := &triangles[i]
+		tri.V1, tri.V2, tri.V3 = s.place(tri.V1), s.place(tri.V2), s.place(tri.V3)
+		normal, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+		if err != nil {
+			return errors.Wrap(err, "failed to wrap triangle")
+		}
+		tri.Normal = normal
+	}
+	return nil
+}
+
+// CreateSpiralBase builds the round base under a spiral, between Z = -BaseHeight and Z = 0.
+func CreateSpiralBase(s Spiral) ([]types.Triangle, error) {
+	profile := make([]point2XY, spiralSegments)
+	for i := range profile {
+		angle := 2 * math.Pi * float64(i) / spiralSegments
+		profile[i] = point2XY{s.Centre + s.Radius*math.Cos(angle), s.Centre + s.Radius*math.Sin(angle)}
+	}
+	triangles, err := extrudeConvex(profile, -BaseHeight, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create round base")
+	}
+	return triangles, nil
+}
+
+// CreateSpiralLabel embosses the username above the year on the top of the base, inside
+// the inner circle of the spiral. Each line is shrunk to fit the width of the circle.
+func CreateSpiralLabel(username, year string, s Spiral) ([]types.Triangle, error) {
+	// The largest square inside the inner circle, leaving a cell clear of the columns.
+	size := (s.InnerRadius - CellSize) * math.Sqrt2
+	if size <= 0 {
+		return nil, errors.New(errors.ValidationError, "spiral is too small for a label", nil)
+	}
+
+	dc := gg.NewContext(spiralLabelResolution, spiralLabelResolution)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	width := float64(spiralLabelResolution)
+	for i, line := range []string{username, year} {
+		line, err := loadFont(dc, line, spiralLabelFontSize)
+		if err != nil {
+			return nil, err
+		}
+		if w, _ := dc.MeasureString(line); w > 0.9*width {
+			if line, err = loadFont(dc, line, spiralLabelFontSize*0.9*width/w); err != nil {
+				return nil, err
+			}
+		}
+		dc.DrawStringAnchored(line, width/2, width*(0.3+0.4*float64(i)), 0.5, 0.5)
+	}
+
+	half := size / 2
+	triangles, err := embossIcon(dc.Image(), s.Centre-half, s.Centre-half, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to emboss label")
+	}
+	return triangles, nil
+}
@@ -0,0 +1,111 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestNewSpiral(t *testing.T) {
+	// A year closes into a circle whose inner cells keep their width.
+	year := NewSpiral(GridSize)
+	if year.Pitch != 0 || year.TurnWeeks != GridSize {
+		t.Errorf("year spiral = %+v, want a circle of %d weeks", year, GridSize)
+	}
+	if got := 2 * math.Pi * year.InnerRadius; math.Abs(got-float64(GridSize)*CellSize) > epsilon {
+		t.Errorf("inner circumference = %v, want %v", got, float64(GridSize)*CellSize)
+	}
+	if want := year.InnerRadius + 9*CellSize; math.Abs(year.Radius-want) > epsilon || year.Centre != year.Radius {
+		t.Errorf("year spiral radius = %v centred at %v, want %v", year.Radius, year.Centre, want)
+	}
+
+	// A year with a 54th week widens the circle rather than overlapping its first week.
+	if long := NewSpiral(MaxYearWeeks); long.TurnWeeks != MaxYearWeeks || long.Pitch != 0 {
+		t.Errorf("long year spiral = %+v, want a circle of %d weeks", long, MaxYearWeeks)
+	}
+
+	// Several years spiral out by a year's depth each turn.
+	years := NewSpiral(3 * GridSize)
+	if years.Pitch != YearOffset || years.TurnWeeks != GridSize {
+		t.Errorf("multi-year spiral = %+v, want a pitch of %v", years, YearOffset)
+	}
+	if want := years.InnerRadius + 9*CellSize + 3*YearOffset; math.Abs(years.Radius-want) > epsilon {
+		t.Errorf("multi-year spiral radius = %v, want %v", years.Radius, want)
+	}
+}
+
+func TestSpiralWrap(t *testing.T) {
+	s := NewSpiral(3 * GridSize)
+
+	// The first day of the first week starts at twelve o'clock on the inner circle.
+	x0, y := CellPosition(0, 0, 0)
+	if got := s.place(types.Point3D{X: x0, Y: y, Z: 1}); math.Abs(got.X-s.Centre) > epsilon || math.Abs(got.Y-(s.Centre+s.InnerRadius)) > epsilon || got.Z != 1 {
+		t.Errorf("first cell at %v, want above the centre at radius %v", got, s.InnerRadius)
+	}
+	// A quarter turn later the weeks have moved clockwise to three o'clock.
+	x, _ := CellPosition(GridSize/4, 0, 0)
+	quarter := float64(GridSize/4) / float64(GridSize)
+	if got := s.place(types.Point3D{X: x, Y: y}); got.X <= s.Centre || math.Abs(math.Atan2(got.Y-s.Centre, got.X-s.Centre)-(math.Pi/2-2*math.Pi*quarter)) > epsilon {
+		t.Errorf("week %d at %v, want a quarter turn clockwise", GridSize/4, got)
+	}
+	// A turn later the next year's first day sits just outside the last day of the first.
+	x, _ = CellPosition(GridSize, 0, 0)
+	_, lastDay := CellPosition(0, 7, 0)
+	inner := s.place(types.Point3D{X: x, Y: y})
+	outer := s.place(types.Point3D{X: x0, Y: lastDay})
+	if math.Abs(inner.Y-outer.Y) > epsilon || math.Abs(inner.X-outer.X) > epsilon {
+		t.Errorf("second turn starts at %v, want %v", inner, outer)
+	}
+
+	column, err := CreateColumn(x, y, 5, CellSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Wrap(column); err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+	for _, tri := range column {
+		// Normals still point out of the wedge: the top faces up.
+		if tri.V1.Z == 5 && tri.V2.Z == 5 && tri.V3.Z == 5 && tri.Normal.Z < 1-epsilon {
+			t.Errorf("top face normal = %v, want up", tri.Normal)
+		}
+	}
+}
+
+func TestCreateSpiralBase(t *testing.T) {
+	s := NewSpiral(GridSize)
+	triangles, err := CreateSpiralBase(s)
+	if err != nil {
+		t.Fatalf("CreateSpiralBase() error = %v", err)
+	}
+	if want := 2*(spiralSegments-2) + 2*spiralSegments; len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if r := math.Hypot(v.X-s.Centre, v.Y-s.Centre); r > s.Radius+epsilon || v.Z < -BaseHeight-epsilon || v.Z > epsilon {
+				t.Fatalf("vertex %v outside the round base", v)
+			}
+		}
+	}
+}
+
+func TestCreateSpiralLabel(t *testing.T) {
+	s := NewSpiral(GridSize)
+	triangles, err := CreateSpiralLabel("a-rather-long-username-for-a-label", "2014-24", s)
+	if err != nil {
+		t.Fatalf("CreateSpiralLabel() error = %v", err)
+	}
+	if len(triangles) == 0 {
+		t.Fatal("label has no triangles")
+	}
+	// The label stays inside the spiral, clear of the first day of each week.
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if r := math.Hypot(v.X-s.Centre, v.Y-s.Centre); r > s.InnerRadius-CellSize+epsilon || v.Z < 0 || v.Z > BadgeRelief+epsilon {
+				t.Fatalf("vertex %v outside the centre of the spiral", v)
+			}
+		}
+	}
+}
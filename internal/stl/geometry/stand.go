@@ -0,0 +1,96 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Display stand constants. The stand holds the model by its base, leaning back from
+// vertical so the skyline faces the viewer. All measurements are in millimeters.
+const (
+	StandAngle         float64 = 15.0 // Lean of the slot from vertical, in degrees
+	StandClearance     float64 = 0.4  // Extra slot width so the base slides in after printing
+	StandFootHeight    float64 = 4.0  // Thickness of the plate the stand rests on
+	StandLipHeight     float64 = 6.0  // Height of the wall in front of the model
+	StandWallThickness float64 = 4.0  // Horizontal thickness of the front lip and back support
+	StandSupportRatio  float64 = 0.4  // Height of the back support as a fraction of the base depth
+)
+
+// point2D is a point in the stand's YZ profile: Y runs front to back, Z upward.
+type point2D struct {
+	Y, Z float64
+}
+
+// CreateStand generates an angled display stand for a base of the given dimensions.
+// The stand is as wide as the base and has a slot sized to baseThickness, so the model
+// can be displayed upright. The front of the stand is at Y = 0 and it rests on Z = 0.
+func CreateStand(baseWidth, baseDepth, baseThickness float64) ([]types.Triangle, error) {
+	if baseWidth <= 0 || baseDepth <= 0 || baseThickness <= 0 {
+		return nil, errors.New(errors.ValidationError, "stand dimensions must be positive", nil)
+	}
+
+	lean := math.Tan(StandAngle * math.Pi / 180)
+	// The slot is measured horizontally, which is wider than the base when tilted.
+	slotWidth := (baseThickness + StandClearance) / math.Cos(StandAngle*math.Pi/180)
+	supportHeight := baseDepth * StandSupportRatio
+	floor := StandFootHeight
+
+	lipBack := StandWallThickness
+	supportFront := lipBack + slotWidth
+	supportBack := supportFront + StandWallThickness
+	footDepth := supportBack + supportHeight*lean
+
+	triangles, err := createBox(0, 0, 0, baseWidth, footDepth, StandFootHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create stand foot")
+	}
+
+	walls := [][4]point2D{
+		// Front lip
+		{{0, floor}, {lipBack, floor}, {lipBack + StandLipHeight*lean, floor + StandLipHeight}, {StandLipHeight * lean, floor + StandLipHeight}},
+		// Back support
+		{{supportFront, floor}, {supportBack, floor}, {supportBack + supportHeight*lean, floor + supportHeight}, {supportFront + supportHeight*lean, floor + supportHeight}},
+	}
+	for _, profile := range walls {
+		wall, err := createPrism(0, baseWidth, profile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create stand wall")
+		}
+		triangles = append(triangles, wall...)
+	}
+
+	return triangles, nil
+}
+
+// createPrism extrudes a convex quadrilateral profile along the X axis from x to x+width.
+// The profile must be counter-clockwise when Y points right and Z points up, which
+// keeps every face normal pointing outward.
+func createPrism(x, width float64, profile [4]point2D) ([]types.Triangle, error) {
+	left := make([]types.Point3D, len(profile))
+	right := make([]types.Point3D, len(profile))
+	for i, p := range profile {
+		left[i] = types.Point3D{X: x, Y: p.Y, Z: p.Z}
+		right[i] = types.Point3D{X: x + width, Y: p.Y, Z: p.Z}
+	}
+
+	quads := [][4]types.Point3D{
+		{right[0], right[1], right[2], right[3]}, // right end
+		{left[3], left[2], left[1], left[0]},     // left end
+	}
+	for i := range profile {
+		next := (i + 1) % len(profile)
+		quads = append(quads, [4]types.Point3D{left[i], left[next], right[next], right[i]})
+	}
+
+	triangles := make([]types.Triangle, 0, 2*len(quads))
+	for _, q := range quads {
+		quad, err := CreateQuad(q[0], q[1], q[2], q[3])
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create quad", err)
+		}
+		triangles = append(triangles, quad...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,95 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateStand(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	triangles, err := CreateStand(width, depth, BaseHeight)
+	if err != nil {
+		t.Fatalf("CreateStand() error = %v", err)
+	}
+	// A foot plate plus two walls, each with six faces.
+	if len(triangles) != 3*12 {
+		t.Fatalf("got %d triangles, want %d", len(triangles), 3*12)
+	}
+
+	minX, maxX, minZ := math.Inf(1), math.Inf(-1), math.Inf(1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+			minZ = math.Min(minZ, v.Z)
+		}
+	}
+	if math.Abs(minX) > epsilon || math.Abs(maxX-width) > epsilon {
+		t.Errorf("stand spans x [%v, %v], want [0, %v]", minX, maxX, width)
+	}
+	if math.Abs(minZ) > epsilon {
+		t.Errorf("stand should rest on z = 0, got %v", minZ)
+	}
+
+	// The slot between the walls is wider than the base so it slides in.
+	gap := minFloorY(triangles[24:36]) - maxFloorY(triangles[12:24])
+	if gap <= BaseHeight {
+		t.Errorf("slot width %v should exceed base thickness %v", gap, BaseHeight)
+	}
+
+	if _, err := CreateStand(width, depth, 0); err == nil {
+		t.Error("expected error for zero base thickness")
+	}
+}
+
+func TestCreatePrismNormals(t *testing.T) {
+	profile := [4]point2D{{0, 0}, {2, 0}, {3, 4}, {1, 4}}
+	triangles, err := createPrism(0, 5, profile)
+	if err != nil {
+		t.Fatalf("createPrism() error = %v", err)
+	}
+	if len(triangles) != 12 {
+		t.Fatalf("got %d triangles, want 12", len(triangles))
+	}
+
+	center := types.Point3D{X: 2.5, Y: 1.5, Z: 2}
+	for i, tri := range triangles {
+		mid := types.Point3D{
+			X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3,
+			Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3,
+			Z: (tri.V1.Z + tri.V2.Z + tri.V3.Z) / 3,
+		}
+		outward := vectorSubtract(mid, center)
+		if dot := tri.Normal.X*outward.X + tri.Normal.Y*outward.Y + tri.Normal.Z*outward.Z; dot <= 0 {
+			t.Errorf("triangle %d normal %v points inward", i, tri.Normal)
+		}
+	}
+}
+
+// minFloorY returns the smallest Y among the vertices at the floor of the stand.
+func minFloorY(triangles []types.Triangle) float64 {
+	y := math.Inf(1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if math.Abs(v.Z-StandFootHeight) < epsilon {
+				y = math.Min(y, v.Y)
+			}
+		}
+	}
+	return y
+}
+
+// maxFloorY returns the largest Y among the vertices at the floor of the stand.
+func maxFloorY(triangles []types.Triangle) float64 {
+	y := math.Inf(-1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if math.Abs(v.Z-StandFootHeight) < epsilon {
+				y = math.Max(y, v.Y)
+			}
+		}
+	}
+	return y
+}
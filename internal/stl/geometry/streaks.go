@@ -0,0 +1,118 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// crestPoint is a point on the crest of a ridge: a height at a distance along the week's
+// column, in cells from its first day.
+type crestPoint struct {
+	t, height float64
+}
+
+// CreateStreakGeometry generates a single year's contributions as ridges: each run of
+// consecutive active days in a week becomes one solid instead of a column per day. The
+// crest starts at the first day's height on the run's front edge, passes through the
+// centre of each day in between and ends at the last day's height on its back edge.
+// Days of equal height merge into a flat stretch, so a lone day or a run of equal days
+// is a single box. A streak that carries over into the next week continues as a new
+// ridge in the adjacent column.
+func CreateStreakGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	triangles := make([]types.Triangle, 0, StreakTriangleCount(contributions, maxContrib))
+	for weekIdx, week := range contributions {
+		x, y := CellPosition(weekIdx, 0, yearIndex)
+		for _, crest := range weekCrests(week, maxContrib) {
+			ridge, err := createRidge(x, y, crest)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, ridge...)
+		}
+	}
+	return triangles, nil
+}
+
+// StreakTriangleCount returns the number of triangles CreateStreakGeometry generates for a
+// year's contributions.
+func StreakTriangleCount(contributions [][]types.ContributionDay, maxContrib int) int {
+	count := 0
+	for _, week := range contributions {
+		for _, crest := range weekCrests(week, maxContrib) {
+			count += 8*(len(crest)-1) + 4
+		}
+	}
+	return count
+}
+
+// weekCrests returns the crest of each run of consecutive active days in a week, front to
+// back, with the points that only continue a flat stretch left out.
+func weekCrests(week []types.ContributionDay, maxContrib int) [][]crestPoint {
+	var crests [][]crestPoint
+	for start := 0; start < len(week); {
+		if week[start].ContributionCount <= 0 {
+			start++
+			continue
+		}
+		end := start
+		for end+1 < len(week) && week[end+1].ContributionCount > 0 {
+			end++
+		}
+
+		var crest []crestPoint
+		add := func(t, height float64) {
+			n := len(crest)
+			if n >= 2 && crest[n-1].height == height && crest[n-2].height == height {
+				crest[n-1].t = t
+				return
+			}
+			crest = append(crest, crestPoint{t, height})
+		}
+		add(float64(start), NormalizeContribution(week[start].ContributionCount, maxContrib))
+		for day := start + 1; day < end; day++ {
+			add(float64(day)+0.5, NormalizeContribution(week[day].ContributionCount, maxContrib))
+		}
+		add(float64(end+1), NormalizeContribution(week[end].ContributionCount, maxContrib))
+
+		crests = append(crests, crest)
+		start = end + 1
+	}
+	return crests
+}
+
+// createRidge builds the closed solid under a crest, one cell wide, standing on Z = 0 with
+// its front-left corner at (x, y). Each stretch of the crest gets its own strip of the top,
+// bottom and sides, so neighbouring strips share whole edges.
+func createRidge(x, y float64, crest []crestPoint) ([]types.Triangle, error) {
+	if len(crest) < 2 {
+		return nil, errors.New(errors.ValidationError, "a ridge needs at least two crest points", nil)
+	}
+	triangles := make([]types.Triangle, 0, 8*(len(crest)-1)+4)
+	x1 := x + CellSize
+	point := func(x float64, p crestPoint, z float64) types.Point3D {
+		return types.Point3D{X: x, Y: y + p.t*CellSize, Z: z}
+	}
+
+	first, last := crest[0], crest[len(crest)-1]
+	quads := [][4]types.Point3D{
+		{point(x, first, 0), point(x1, first, 0), point(x1, first, first.height), point(x, first, first.height)}, // front
+		{point(x1, last, 0), point(x, last, 0), point(x, last, last.height), point(x1, last, last.height)},       // back
+	}
+	for i := 1; i < len(crest); i++ {
+		a, b := crest[i-1], crest[i]
+		quads = append(quads,
+			[4]types.Point3D{point(x, a, a.height), point(x1, a, a.height), point(x1, b, b.height), point(x, b, b.height)}, // top
+			[4]types.Point3D{point(x, a, 0), point(x, b, 0), point(x1, b, 0), point(x1, a, 0)},                             // bottom
+			[4]types.Point3D{point(x, a, 0), point(x, a, a.height), point(x, b, b.height), point(x, b, 0)},                 // left
+			[4]types.Point3D{point(x1, a, 0), point(x1, b, 0), point(x1, b, b.height), point(x1, a, a.height)},             // right
+		)
+	}
+	for _, q := range quads {
+		quad, err := CreateQuad(q[0], q[1], q[2], q[3])
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create ridge face", err)
+		}
+		triangles = append(triangles, quad...)
+	}
+	return triangles, nil
+}
@@ -0,0 +1,108 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// signedVolume returns the volume enclosed by a closed mesh with outward normals.
+func signedVolume(triangles []types.Triangle) float64 {
+	volume := 0.0
+	for _, tri := range triangles {
+		volume += dotProduct(tri.V1, vectorCross(tri.V2, tri.V3)) / 6
+	}
+	return volume
+}
+
+func dotProduct(a, b types.Point3D) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func streakWeek(counts ...int) []types.ContributionDay {
+	week := make([]types.ContributionDay, len(counts))
+	for i, count := range counts {
+		week[i].ContributionCount = count
+	}
+	return week
+}
+
+func TestWeekCrests(t *testing.T) {
+	h := func(count int) float64 { return NormalizeContribution(count, 16) }
+	tests := []struct {
+		name string
+		week []types.ContributionDay
+		want [][]crestPoint
+	}{
+		{"empty", streakWeek(0, 0, 0, 0, 0, 0, 0), nil},
+		{"lone days", streakWeek(4, 0, 0, 9, 0, 0, 0), [][]crestPoint{{{0, h(4)}, {1, h(4)}}, {{3, h(9)}, {4, h(9)}}}},
+		{"ramp", streakWeek(0, 1, 4, 9, 0, 0, 16), [][]crestPoint{{{1, h(1)}, {2.5, h(4)}, {4, h(9)}}, {{6, h(16)}, {7, h(16)}}}},
+		{"flat week", streakWeek(4, 4, 4, 4, 4, 4, 4), [][]crestPoint{{{0, h(4)}, {7, h(4)}}}},
+		{"plateau", streakWeek(1, 4, 4, 4, 4, 1, 0), [][]crestPoint{{{0, h(1)}, {1.5, h(4)}, {4.5, h(4)}, {6, h(1)}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weekCrests(tt.week, 16)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d crests %v, want %v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if len(got[i]) != len(tt.want[i]) {
+					t.Fatalf("crest %d = %v, want %v", i, got[i], tt.want[i])
+				}
+				for j := range got[i] {
+					if math.Abs(got[i][j].t-tt.want[i][j].t) > epsilon || math.Abs(got[i][j].height-tt.want[i][j].height) > epsilon {
+						t.Errorf("crest %d = %v, want %v", i, got[i], tt.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateStreakGeometry(t *testing.T) {
+	year := [][]types.ContributionDay{
+		streakWeek(1, 4, 9, 16, 9, 4, 1),
+		streakWeek(2, 2, 2, 2, 2, 2, 2),
+		streakWeek(0, 3, 0, 8, 8, 0, 5),
+	}
+	triangles, err := CreateStreakGeometry(year, 1, 16)
+	if err != nil {
+		t.Fatalf("CreateStreakGeometry() error = %v", err)
+	}
+	if want := StreakTriangleCount(year, 16); len(triangles) != want {
+		t.Errorf("got %d triangles, StreakTriangleCount() = %d", len(triangles), want)
+	}
+	towers, err := CreateContributionGeometry(year, 1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triangles) >= len(towers)/2 {
+		t.Errorf("got %d triangles, want well under the %d of separate columns", len(triangles), len(towers))
+	}
+
+	// Every ridge is closed with outward faces, so the mesh encloses the area under the
+	// crests: a trapezoid per stretch, one cell wide.
+	want := 0.0
+	for _, week := range year {
+		for _, crest := range weekCrests(week, 16) {
+			for i := 1; i < len(crest); i++ {
+				want += CellSize * CellSize * (crest[i].t - crest[i-1].t) * (crest[i].height + crest[i-1].height) / 2
+			}
+		}
+	}
+	if got := signedVolume(triangles); math.Abs(got-want) > 1e-6 {
+		t.Errorf("enclosed volume = %v, want %v", got, want)
+	}
+
+	x0, y0 := CellPosition(0, 0, 1)
+	x1, y1 := CellPosition(len(year), 7, 1)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.X < x0-epsilon || v.X > x1+epsilon || v.Y < y0-epsilon || v.Y > y1+epsilon || v.Z < 0 || v.Z > MaxHeight+epsilon {
+				t.Fatalf("vertex %v outside the year's grid", v)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// SurfaceSubdivisions is the number of surface samples along each side of a cell.
+	SurfaceSubdivisions = 4
+
+	// SurfaceFloor is the thickness of the surface where there are no contributions. It
+	// keeps every wall of the surface solid, so the mesh stays closed above the base.
+	SurfaceFloor float64 = 0.5
+)
+
+// SurfaceTriangleCount returns the number of triangles CreateSurfaceGeometry produces for
+// a row of the given number of weeks: the top and bottom grids plus the four walls.
+func SurfaceTriangleCount(weeks int) int {
+	cols, rows := weeks*SurfaceSubdivisions, 7*SurfaceSubdivisions
+	return 4*cols*rows + 4*(cols+rows)
+}
+
+// CreateSurfaceGeometry generates a single year's contributions as one continuous
+// "mountain range" instead of separate columns. Column heights are taken at the centre
+// of each cell and interpolated with a Catmull-Rom spline, then tessellated into a closed
+// solid standing on the base over the same footprint as the columns would cover.
+func CreateSurfaceGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	weeks := len(contributions)
+	if weeks == 0 {
+		return nil, nil
+	}
+
+	heights := make([][7]float64, weeks)
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if dayIdx < 7 {
+				heights[weekIdx][dayIdx] = NormalizeContribution(day.ContributionCount, maxContrib)
+			}
+		}
+	}
+
+	cols, rows := weeks*SurfaceSubdivisions, 7*SurfaceSubdivisions
+	x0, y0 := CellPosition(0, 0, yearIndex)
+	step := CellSize / SurfaceSubdivisions
+	top := make([][]types.Point3D, cols+1)
+	for i := range top {
+		top[i] = make([]types.Point3D, rows+1)
+		for j := range top[i] {
+			// Sample positions in cell units, relative to the centre of the first cell.
+			u := float64(i)/SurfaceSubdivisions - 0.5
+			v := float64(j)/SurfaceSubdivisions - 0.5
+			z := min(max(sampleSurface(heights, u, v), SurfaceFloor), MaxHeight)
+			top[i][j] = types.Point3D{X: x0 + float64(i)*step, Y: y0 + float64(j)*step, Z: z}
+		}
+	}
+
+	return createHeightfield(top, SurfaceTriangleCount(weeks))
+}
+
+// createHeightfield closes a grid of top points ([column][row], X increasing with the column
+// and Y with the row) into a solid standing on Z = 0, with capacity preallocated triangles.
+func createHeightfield(top [][]types.Point3D, capacity int) ([]types.Triangle, error) {
+	cols, rows := len(top)-1, len(top[0])-1
+	floor := func(p types.Point3D) types.Point3D { return types.Point3D{X: p.X, Y: p.Y} }
+
+	triangles := make([]types.Triangle, 0, capacity)
+	var err error
+	for i := 0; i < cols; i++ {
+		for j := 0; j < rows; j++ {
+			a, b, c, d := top[i][j], top[i+1][j], top[i+1][j+1], top[i][j+1]
+			for _, tri := range [][3]types.Point3D{
+				{a, b, c}, {a, c, d}, // Top, counter-clockwise from above
+				{floor(a), floor(c), floor(b)}, {floor(a), floor(d), floor(c)}, // Bottom, facing down
+			} {
+				if triangles, err = appendTriangle(triangles, tri); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Walls, each quad wound to face outwards.
+	var walls [][4]types.Point3D
+	for i := 0; i < cols; i++ {
+		front0, front1 := top[i][0], top[i+1][0]
+		back0, back1 := top[i][rows], top[i+1][rows]
+		walls = append(walls,
+			[4]types.Point3D{floor(front0), floor(front1), front1, front0},
+			[4]types.Point3D{floor(back1), floor(back0), back0, back1})
+	}
+	for j := 0; j < rows; j++ {
+		left0, left1 := top[0][j], top[0][j+1]
+		right0, right1 := top[cols][j], top[cols][j+1]
+		walls = append(walls,
+			[4]types.Point3D{floor(left1), floor(left0), left0, left1},
+			[4]types.Point3D{floor(right0), floor(right1), right1, right0})
+	}
+	for _, wall := range walls {
+		quad, err := CreateQuad(wall[0], wall[1], wall[2], wall[3])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, quad...)
+	}
+
+	return triangles, nil
+}
+
+// appendTriangle appends tri with its own normal, for faces that need not be planar quads.
+func appendTriangle(triangles []types.Triangle, tri [3]types.Point3D) ([]types.Triangle, error) {
+	normal, err := calculateNormal(tri[0], tri[1], tri[2])
+	if err != nil {
+		return nil, err
+	}
+	return append(triangles, types.Triangle{Normal: normal, V1: tri[0], V2: tri[1], V3: tri[2]}), nil
+}
+
+// sampleSurface interpolates the cell heights ([week][day]) at (u, v) in cell units, where
+// whole numbers fall on cell centres. Cells beyond the grid repeat the nearest edge cell.
+func sampleSurface(heights [][7]float64, u, v float64) float64 {
+	at := func(week, day int) float64 {
+		week = min(max(week, 0), len(heights)-1)
+		day = min(max(day, 0), 6)
+		return heights[week][day]
+	}
+
+	week, day := int(math.Floor(u)), int(math.Floor(v))
+	tu, tv := u-float64(week), v-float64(day)
+	var column [4]float64
+	for k := range column {
+		d := day - 1 + k
+		column[k] = catmullRom(at(week-1, d), at(week, d), at(week+1, d), at(week+2, d), tu)
+	}
+	return catmullRom(column[0], column[1], column[2], column[3], tv)
+}
+
+// catmullRom evaluates the uniform Catmull-Rom spline through p1 and p2 at t in [0, 1].
+func catmullRom(p0, p1, p2, p3, t float64) float64 {
+	return 0.5 * (2*p1 +
+		(p2-p0)*t +
+		(2*p0-5*p1+4*p2-p3)*t*t +
+		(3*p1-p0-3*p2+p3)*t*t*t)
+}
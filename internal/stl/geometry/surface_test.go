@@ -0,0 +1,67 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TestCreateSurfaceGeometry verifies the surface is a closed solid over the columns' footprint
+func TestCreateSurfaceGeometry(t *testing.T) {
+	contributions := make([][]types.ContributionDay, 3)
+	for w := range contributions {
+		contributions[w] = make([]types.ContributionDay, 7)
+	}
+	contributions[1][3].ContributionCount = 10
+	contributions[2][0].ContributionCount = 4
+	// A partial week, as at the start or end of a year.
+	contributions = append(contributions, []types.ContributionDay{{ContributionCount: 1}})
+
+	triangles, err := CreateSurfaceGeometry(contributions, 1, 10)
+	if err != nil {
+		t.Fatalf("CreateSurfaceGeometry() error = %v", err)
+	}
+	if want := SurfaceTriangleCount(len(contributions)); len(triangles) != want {
+		t.Errorf("got %d triangles, want %d", len(triangles), want)
+	}
+
+	assertClosedMesh(t, triangles)
+
+	minX, minY := CellPosition(0, 0, 1)
+	maxX, maxY := CellPosition(len(contributions), 7, 1)
+	peak := 0.0
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.X < minX-epsilon || v.X > maxX+epsilon || v.Y < minY-epsilon || v.Y > maxY+epsilon {
+				t.Fatalf("vertex %v outside the footprint", v)
+			}
+			if v.Z < 0 || v.Z > MaxHeight {
+				t.Fatalf("vertex %v outside the height range", v)
+			}
+			peak = math.Max(peak, v.Z)
+		}
+	}
+	// The spline passes through the busiest cell's centre at its column height.
+	if math.Abs(peak-MaxHeight) > epsilon {
+		t.Errorf("peak = %v, want %v", peak, MaxHeight)
+	}
+
+	empty, err := CreateSurfaceGeometry(nil, 0, 10)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("CreateSurfaceGeometry(nil) = %d triangles, %v; want none", len(empty), err)
+	}
+}
+
+// TestCatmullRom verifies the spline passes through its control points
+func TestCatmullRom(t *testing.T) {
+	if got := catmullRom(0, 1, 3, 4, 0); got != 1 {
+		t.Errorf("catmullRom at t=0 = %v, want 1", got)
+	}
+	if got := catmullRom(0, 1, 3, 4, 1); got != 3 {
+		t.Errorf("catmullRom at t=1 = %v, want 3", got)
+	}
+	if got := catmullRom(2, 2, 2, 2, 0.3); math.Abs(got-2) > epsilon {
+		t.Errorf("catmullRom of a constant = %v, want 2", got)
+	}
+}
@@ -0,0 +1,47 @@
+package geometry
+
+import "github.com/github/gh-skyline/internal/types"
+
+const (
+	// TemplateDotSize is the width of the square dot marking each day of a template.
+	TemplateDotSize = CellSize / 2
+
+	// TemplateDotHeight is how far a template's dots stand above the base: enough to feel
+	// and paint over, low enough not to read as contributions.
+	TemplateDotHeight = 0.4
+)
+
+// CreateTemplateGeometry generates a low dot centred in the cell of every dated day of a
+// year, whatever its contributions, marking out the calendar of a year still to come.
+// Days without a date, which pad partial weeks, are left bare.
+func CreateTemplateGeometry(contributions [][]types.ContributionDay, yearIndex int) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	inset := (CellSize - TemplateDotSize) / 2
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if day.Date == "" {
+				continue
+			}
+			x, y := CellPosition(weekIdx, dayIdx, yearIndex)
+			dot, err := createBox(x+inset, y+inset, 0, TemplateDotSize, TemplateDotSize, TemplateDotHeight)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, dot...)
+		}
+	}
+	return triangles, nil
+}
+
+// TemplateDotCount returns the number of dots CreateTemplateGeometry generates for a year.
+func TemplateDotCount(contributions [][]types.ContributionDay) int {
+	count := 0
+	for _, week := range contributions {
+		for _, day := range week {
+			if day.Date != "" {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,35 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateTemplateGeometry(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{{}, {Date: "2027-01-01"}, {Date: "2027-01-02"}},
+		{{Date: "2027-01-03"}},
+	}
+
+	triangles, err := CreateTemplateGeometry(contributions, 0)
+	if err != nil {
+		t.Fatalf("CreateTemplateGeometry() error = %v", err)
+	}
+	if got := TemplateDotCount(contributions); got != 3 {
+		t.Fatalf("TemplateDotCount() = %d, want 3", got)
+	}
+	if len(triangles) != 3*12 {
+		t.Fatalf("CreateTemplateGeometry() = %d triangles, want three dots", len(triangles))
+	}
+
+	// The first dot marks the second day of the first week, centred in its cell.
+	dot := meshtest.Measure(triangles[:12])
+	x, y := CellPosition(0, 1, 0)
+	inset := (CellSize - TemplateDotSize) / 2
+	if math.Abs(dot.Min.X-(x+inset)) > 1e-9 || math.Abs(dot.Min.Y-(y+inset)) > 1e-9 || dot.Min.Z != 0 || dot.Max.Z != TemplateDotHeight {
+		t.Errorf("first dot spans %v to %v, want it centred in the cell of 2027-01-01", dot.Min, dot.Max)
+	}
+}
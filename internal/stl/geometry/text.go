@@ -2,12 +2,17 @@ package geometry
 
 import (
 	"fmt"
+	"image/color"
 	"image/png"
 	"os"
+	"strings"
 
 	"github.com/fogleman/gg"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/types"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 )
 
 const (
@@ -25,39 +30,304 @@ const (
 	yearFontSize      = 100.0
 	yearJustification = "right" // "left", "center", "right"
 	yearLeftOffset    = 0.97    // Percent
+
+	statsFontSize = 70.0 // Stats are centered on the back face
+
+	// A long year label, such as a date range, shrinks to keep yearGap of its panel clear
+	// of the username beside it, or of the edges when it has a face to itself, but never
+	// below yearMinFit of its size. A username that already fills the face leaves no room
+	// to fit into, so the year keeps its size.
+	yearGap    = 0.03 // Percent
+	yearMinFit = 0.4
 )
 
-// Create3DText generates 3D text geometry for the username and year.
+// Face identifies a side face of the base that text can be placed on.
+// Faces are named as seen from the front of the model.
+type Face int
+
+// Supported text faces.
+const (
+	FaceFront Face = iota
+	FaceBack
+	FaceLeft
+	FaceRight
+)
+
+// ParseFace converts a flag value ("front", "back", "left" or "right") into a Face.
+func ParseFace(name string) (Face, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "front":
+		return FaceFront, nil
+	case "back":
+		return FaceBack, nil
+	case "left":
+		return FaceLeft, nil
+	case "right":
+		return FaceRight, nil
+	default:
+		return FaceFront, fmt.Errorf("unknown face %q (expected front, back, left or right)", name)
+	}
+}
+
+// ParseTextPosition parses a text position flag. A single face places both the username
+// and the year on it; "username-face,year-face" places them separately.
+func ParseTextPosition(value string) (usernameFace, yearFace Face, err error) {
+	parts := strings.Split(value, ",")
+	if len(parts) > 2 {
+		return FaceFront, FaceFront, fmt.Errorf("invalid text position %q (expected FACE or USERNAME-FACE,YEAR-FACE)", value)
+	}
+	if usernameFace, err = ParseFace(parts[0]); err != nil {
+		return FaceFront, FaceFront, err
+	}
+	yearFace = usernameFace
+	if len(parts) == 2 {
+		if yearFace, err = ParseFace(parts[1]); err != nil {
+			return FaceFront, FaceFront, err
+		}
+	}
+	return usernameFace, yearFace, nil
+}
+
+// maxPanelWidth is the widest span text and the logo are laid out across: the front of a
+// single-year model. Longer faces, such as a strip layout, keep their labels this size at
+// the face's ends instead of stretching them.
+var maxPanelWidth, _ = CalculateMultiYearDimensions(1)
+
+// MaxTextScale is the largest supported TextOptions.Scale; larger text no longer fits the base.
+const MaxTextScale = 3.0
+
+// TextOptions controls where the username and year are embossed and how large they are.
+// The zero value places both on the front face at the default size.
+type TextOptions struct {
+	UsernameFace Face    // Face for the username
+	YearFace     Face    // Face for the year
+	Scale        float64 // Font size multiplier; zero means 1
+
+	// Stats is a line engraved on the back face, such as contribution totals. It is always
+	// recessed, even when the username and year are raised, and needs the back face to
+	// itself. Empty leaves it out.
+	Stats string
+
+	// Months are month initials engraved into the top of the base in front of the
+	// contribution grid. Empty leaves them out.
+	Months []MonthTick
+
+	// Years are the years of the rows of a stacked model, engraved beside each row into
+	// the left face, or into a strip YearStrip wide along the left of the top face when
+	// YearStrip is set. Empty leaves them out.
+	Years     []YearTick
+	YearStrip float64
+
+	// Avatar moves a username on the front face right to make room for the avatar embossed
+	// before it by CreateAvatarGeometry.
+	Avatar bool
+}
+
+// textLabel is a single piece of text and where it goes on its face.
+type textLabel struct {
+	text          string
+	face          Face
+	justification string
+	offset        float64
+	fontSize      float64 // Font size at the panel's rendering resolution
+	faceWidth     float64 // Width of the face the label is drawn on
+	panelWidth    float64 // Width of the span of the face the label is laid out across
+	panelOffset   float64 // Distance from the left of the face to the panel
+}
+
+// Create3DText generates 3D text geometry for the username and year on the front face.
 func Create3DText(username string, year string, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
+	return Create3DTextWithOptions(username, year, baseWidth, 0, baseHeight, TextOptions{})
+}
+
+// Create3DTextWithOptions generates 3D text geometry for the username and year on the
+// faces chosen in opts. baseDepth is only needed when text is placed on the left or right face.
+// The front face keeps the username left and the year right; on other faces a label is
+// centered unless it shares the face, in which case the front layout is used.
+func Create3DTextWithOptions(username, year string, baseWidth, baseDepth, baseHeight float64, opts TextOptions) ([]types.Triangle, error) {
+	labels, err := layoutText(username, year, baseWidth, baseDepth, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var triangles []types.Triangle
+	for _, label := range labels {
+		labelTriangles, err := renderText(
+			label.text,
+			label.justification,
+			label.offset,
+			label.fontSize,
+			label.panelWidth,
+			baseHeight,
+		)
+		if err != nil {
+			return nil, err
+		}
+		translateX(labelTriangles, label.panelOffset)
+		triangles = append(triangles, placeOnFace(labelTriangles, label.face, baseWidth, baseDepth)...)
+	}
+
+	return triangles, nil
+}
+
+// layoutText decides the face, alignment and font size of the username and year labels.
+func layoutText(username, year string, baseWidth, baseDepth float64, opts TextOptions) ([]textLabel, error) {
 	if username == "" {
 		username = "anonymous"
 	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	if scale < 0 || scale > MaxTextScale {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("text scale must be between 0 and %g", MaxTextScale), nil)
+	}
 
-	usernameTriangles, err := renderText(
-		username,
-		usernameJustification,
-		usernameLeftOffset,
-		usernameFontSize,
-		baseWidth,
-		baseHeight,
-	)
-	if err != nil {
+	labels := []textLabel{
+		{text: username, face: opts.UsernameFace, justification: usernameJustification, offset: usernameLeftOffset, fontSize: usernameFontSize},
+		{text: year, face: opts.YearFace, justification: yearJustification, offset: yearLeftOffset, fontSize: yearFontSize},
+	}
+	for i := range labels {
+		label := &labels[i]
+		if opts.UsernameFace != opts.YearFace && label.face != FaceFront {
+			label.justification, label.offset = "center", 0.5
+		}
+
+		label.faceWidth = baseWidth
+		if label.face == FaceLeft || label.face == FaceRight {
+			if baseDepth <= 0 {
+				return nil, errors.New(errors.ValidationError, "base depth is required for text on the left or right face", nil)
+			}
+			label.faceWidth = baseDepth
+		}
+
+		// Labels are laid out across a panel no wider than a single-year front, anchored to
+		// the side of the face they are justified to.
+		label.panelWidth = min(label.faceWidth, maxPanelWidth)
+		label.panelOffset = (label.faceWidth - label.panelWidth) * justificationPercent(label.justification)
+
+		// Every panel is rendered at the same horizontal resolution, so scale the font by the
+		// panel width to keep the lettering the same physical size as on the front.
+		label.fontSize *= scale * min(baseWidth, maxPanelWidth) / label.panelWidth
+
+		if opts.Avatar && i == 0 && label.face == FaceFront {
+			label.offset += avatarShift(label.panelWidth)
+		}
+	}
+	if err := fitYear(&labels[1], labels[0]); err != nil {
 		return nil, err
 	}
+	return labels, nil
+}
 
-	yearTriangles, err := renderText(
-		year,
-		yearJustification,
-		yearLeftOffset,
-		yearFontSize,
-		baseWidth,
-		baseHeight,
-	)
+// fitYear shrinks the font of a year label too wide for the space it has: from the end of
+// the username to its right-justified anchor when they share a face, or the width of its
+// panel when it is centered alone. Years short enough to fit keep their size.
+func fitYear(year *textLabel, username textLabel) error {
+	width, err := measureText(year.text, year.fontSize)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	// Widths are in pixels of the year's panel, rendered baseWidthVoxelResolution wide.
+	available := baseWidthVoxelResolution * (1 - 2*yearGap)
+	if year.face == username.face {
+		usernameWidth, err := measureText(username.text, username.fontSize)
+		if err != nil {
+			return err
+		}
+		// The username's end, from the left of the face, in the year panel's pixels.
+		usernameEnd := username.panelOffset + (username.offset+usernameWidth/baseWidthVoxelResolution)*username.panelWidth
+		usernameEnd = (usernameEnd - year.panelOffset) / year.panelWidth * baseWidthVoxelResolution
+		available = (year.offset-yearGap)*baseWidthVoxelResolution - max(usernameEnd, 0)
+	}
+	if width > available && available > 0 {
+		year.fontSize *= max(available/width, yearMinFit)
+	}
+	return nil
+}
+
+// measureText returns the width of text drawn at fontSize, in pixels of a face context.
+func measureText(text string, fontSize float64) (float64, error) {
+	dc := gg.NewContext(1, 1)
+	text, err := loadFont(dc, text, fontSize)
+	if err != nil {
+		return 0, err
+	}
+	width, _ := dc.MeasureString(text)
+	return width, nil
+}
+
+// layoutStats places the stats line centered on the back face. It is not scaled with the
+// username and year so that long lines still fit.
+func layoutStats(baseWidth float64, opts TextOptions) (textLabel, error) {
+	if opts.UsernameFace == FaceBack || opts.YearFace == FaceBack {
+		return textLabel{}, errors.New(errors.ValidationError, "stats need the back face free of the username and year", nil)
+	}
+	panelWidth := min(baseWidth, maxPanelWidth)
+	return textLabel{
+		text:          opts.Stats,
+		face:          FaceBack,
+		justification: "center",
+		offset:        0.5,
+		fontSize:      statsFontSize,
+		faceWidth:     baseWidth,
+		panelWidth:    panelWidth,
+		panelOffset:   (baseWidth - panelWidth) * 0.5,
+	}, nil
+}
+
+// translateX shifts front-face geometry along the face by dx.
+func translateX(triangles []types.Triangle, dx float64) {
+	if dx == 0 {
+		return
+	}
+	for i := range triangles {
+		triangles[i].V1.X += dx
+		triangles[i].V2.X += dx
+		triangles[i].V3.X += dx
+	}
+}
+
+// placeOnFace moves geometry built on the front face onto another face of the base.
+// Front-face geometry runs left to right along X and protrudes towards -Y; each face is
+// reached by rotating about Z, which keeps normals pointing outward.
+func placeOnFace(triangles []types.Triangle, face Face, baseWidth, baseDepth float64) []types.Triangle {
+	var transform func(p types.Point3D, translate bool) types.Point3D
+	switch face {
+	case FaceBack:
+		transform = func(p types.Point3D, translate bool) types.Point3D {
+			if !translate {
+				return types.Point3D{X: -p.X, Y: -p.Y, Z: p.Z}
+			}
+			return types.Point3D{X: baseWidth - p.X, Y: baseDepth - p.Y, Z: p.Z}
+		}
+	case FaceLeft:
+		transform = func(p types.Point3D, translate bool) types.Point3D {
+			if !translate {
+				return types.Point3D{X: p.Y, Y: -p.X, Z: p.Z}
+			}
+			return types.Point3D{X: p.Y, Y: baseDepth - p.X, Z: p.Z}
+		}
+	case FaceRight:
+		transform = func(p types.Point3D, translate bool) types.Point3D {
+			if !translate {
+				return types.Point3D{X: -p.Y, Y: p.X, Z: p.Z}
+			}
+			return types.Point3D{X: baseWidth - p.Y, Y: p.X, Z: p.Z}
+		}
+	default:
+		return triangles
 	}
 
-	return append(usernameTriangles, yearTriangles...), nil
+	for i, t := range triangles {
+		triangles[i] = types.Triangle{
+			Normal: transform(t.Normal, false),
+			V1:     transform(t.V1, true),
+			V2:     transform(t.V2, true),
+			V3:     transform(t.V3, true),
+		}
+	}
+	return triangles
 }
 
 // renderText places text on the face of a skyline, offset from the left and vertically-aligned.
@@ -74,54 +344,15 @@ func Create3DText(username string, year string, baseWidth float64, baseHeight fl
 //
 //	([]types.Triangle, error): A slice of triangles representing text.
 func renderText(text string, justification string, leftOffsetPercent float64, fontSize float64, baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
-	// Create a rendering context for the face of the skyline
-	faceWidthRes := baseWidthVoxelResolution
-	faceHeightRes := int(float64(faceWidthRes) * baseHeight / baseWidth)
-
-	// Create image representing the skyline face
-	dc := gg.NewContext(faceWidthRes, faceHeightRes)
-	dc.SetRGB(0, 0, 0)
-	dc.Clear()
-	dc.SetRGB(1, 1, 1)
-
-	// Load font into context
-	fontPath, cleanup, err := writeTempFont(PrimaryFont)
-	if err != nil {
-		// Try fallback font
-		fontPath, cleanup, err = writeTempFont(FallbackFont)
-		if err != nil {
-			return nil, errors.New(errors.IOError, "failed to load any fonts", err)
-		}
-	}
-	if err := dc.LoadFontFace(fontPath, fontSize); err != nil {
-		return nil, errors.New(errors.IOError, "failed to load font", err)
-	}
-
-	// Draw text on image at desired location
-	var triangles []types.Triangle
-
-	// Convert justification to a number
-	var justificationPercent float64
-	switch justification {
-	case "center":
-		justificationPercent = 0.5
-	case "right":
-		justificationPercent = 1.0
-	default:
-		justificationPercent = 0.0
+	dc := newFaceContext(baseWidthVoxelResolution, baseWidth, baseHeight)
+	if err := drawText(dc, text, justification, float64(dc.Width())*leftOffsetPercent, fontSize); err != nil {
+		return nil, err
 	}
 
-	dc.DrawStringAnchored(
-		text,
-		float64(faceWidthRes)*leftOffsetPercent, // Offset from right
-		float64(faceHeightRes)*0.5,              // Offset from top
-		justificationPercent,                    // Justification (0.0=left, 0.5=center, 1.0=right)
-		0.5,                                     // Vertically aligned
-	)
-
 	// Convert context image pixels into voxels
-	for x := 0; x < faceWidthRes; x++ {
-		for y := 0; y < faceHeightRes; y++ {
+	var triangles []types.Triangle
+	for x := 0; x < dc.Width(); x++ {
+		for y := 0; y < dc.Height(); y++ {
 			if isPixelActive(dc, x, y) {
 				voxel, err := createVoxelOnFace(
 					float64(x),
@@ -139,11 +370,85 @@ func renderText(text string, justification string, leftOffsetPercent float64, fo
 		}
 	}
 
-	defer cleanup()
-
 	return triangles, nil
 }
 
+// newFaceContext creates a black image representing a face of the skyline, faceWidthRes
+// voxels across.
+func newFaceContext(faceWidthRes int, faceWidth float64, baseHeight float64) *gg.Context {
+	faceHeightRes := int(float64(faceWidthRes) * baseHeight / faceWidth)
+
+	dc := gg.NewContext(faceWidthRes, faceHeightRes)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+	return dc
+}
+
+// drawText draws white text onto a face context, anchored x pixels from the left and
+// vertically centered.
+func drawText(dc *gg.Context, text string, justification string, x float64, fontSize float64) error {
+	return drawTextAt(dc, text, justification, x, float64(dc.Height())*0.5, fontSize)
+}
+
+// drawTextAt draws white text onto a face context, anchored x pixels from the left and
+// centered y pixels from the top.
+func drawTextAt(dc *gg.Context, text string, justification string, x, y float64, fontSize float64) error {
+	text, err := loadFont(dc, text, fontSize)
+	if err != nil {
+		return err
+	}
+
+	dc.DrawStringAnchored(
+		text,
+		x,                                   // Offset from left
+		y,                                   // Offset from top
+		justificationPercent(justification), // Justification (0.0=left, 0.5=center, 1.0=right)
+		0.5,                                 // Vertically aligned
+	)
+	return nil
+}
+
+// loadFont sets the context's font face to a font that can draw text, at fontSize, and
+// returns the text to draw, transliterated if no font covers it. The primary fonts are
+// embedded in the binary, so text renders the same on every install.
+func loadFont(dc *gg.Context, text string, fontSize float64) (string, error) {
+	f, text, err := fontFor(text)
+	if err != nil {
+		return "", err
+	}
+	dc.SetFontFace(pointFace{truetype.NewFace(f, &truetype.Options{Size: fontSize}), fontSize})
+	return text, nil
+}
+
+// pointFace reports the line height gg assigns to faces loaded from a file, three
+// quarters of the point size, rather than the font's own, so anchored text lands where
+// it always has.
+type pointFace struct {
+	font.Face
+	points float64
+}
+
+// Metrics implements font.Face.
+func (f pointFace) Metrics() font.Metrics {
+	metrics := f.Face.Metrics()
+	metrics.Height = fixed.Int26_6(f.points * 72 / 96 * 64)
+	return metrics
+}
+
+// justificationPercent converts a justification into the fraction of the text's width
+// that lies left of its anchor.
+func justificationPercent(justification string) float64 {
+	switch justification {
+	case "center":
+		return 0.5
+	case "right":
+		return 1.0
+	default:
+		return 0.0
+	}
+}
+
 // createVoxelOnFace creates a voxel on the face of a skyline by generating a cube at the specified coordinates.
 // The function takes in the x, y coordinates and height.
 // It returns a slice of types.Triangle representing the cube and an error if the cube creation fails.
@@ -186,7 +491,8 @@ func createVoxelOnFace(x float64, y float64, height float64, baseWidth float64,
 	return cube, err
 }
 
-// GenerateImageGeometry creates 3D geometry from the embedded logo image.
+// GenerateImageGeometry creates 3D geometry from the embedded logo image at the left of
+// the front face. Faces wider than a single-year model keep the logo at its usual size.
 func GenerateImageGeometry(baseWidth float64, baseHeight float64) ([]types.Triangle, error) {
 	// Get temporary image file
 	imgPath, cleanup, err := getEmbeddedImage()
@@ -202,7 +508,7 @@ func GenerateImageGeometry(baseWidth float64, baseHeight float64) ([]types.Trian
 		voxelDepth,
 		logoLeftOffset,
 		logoTopOffset,
-		baseWidth,
+		min(baseWidth, maxPanelWidth),
 		baseHeight,
 	)
 }
@@ -240,11 +546,8 @@ func renderImage(filePath string, scale float64, height float64, leftOffsetPerce
 	var triangles []types.Triangle
 	for x := 0; x < logoWidth; x++ {
 		for y := logoHeight - 1; y >= 0; y-- {
-			// Get pixel color and alpha
-			r, _, _, a := img.At(x, y).RGBA()
-
 			// If pixel is active (white) and not fully transparent, create a voxel
-			if a > 32768 && r > 32768 {
+			if pixelIntensity(img.At(x, y)) > 0.5 {
 
 				voxel, err := createVoxelOnFace(
 					(leftOffsetPercent*float64(faceWidthRes))+float64(x)*scale,
@@ -268,6 +571,12 @@ func renderImage(filePath string, scale float64, height float64, leftOffsetPerce
 
 // isPixelActive checks if a pixel is active (white) in the given context.
 func isPixelActive(dc *gg.Context, x, y int) bool {
-	r, _, _, _ := dc.Image().At(x, y).RGBA()
-	return r > 32768
+	return pixelIntensity(dc.Image().At(x, y)) > 0.5
+}
+
+// pixelIntensity returns the brightness of a pixel's red channel from 0 to 1. The channel is
+// premultiplied, so transparent pixels are dark.
+func pixelIntensity(c color.Color) float64 {
+	r, _, _, _ := c.RGBA()
+	return float64(r) / 0xffff
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/types"
 )
 
 // TestCreate3DText verifies text geometry generation functionality.
@@ -57,6 +58,190 @@ func TestCreate3DText(t *testing.T) {
 	})
 }
 
+func TestCreate3DTextWithOptions(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+
+	tests := []struct {
+		name   string
+		face   Face
+		inside func(v types.Point3D) bool
+	}{
+		{"front", FaceFront, func(v types.Point3D) bool { return v.Y <= epsilon }},
+		{"back", FaceBack, func(v types.Point3D) bool { return v.Y >= depth-epsilon }},
+		{"left", FaceLeft, func(v types.Point3D) bool { return v.X <= epsilon && v.Y >= -epsilon && v.Y <= depth+epsilon }},
+		{"right", FaceRight, func(v types.Point3D) bool { return v.X >= width-epsilon && v.Y >= -epsilon && v.Y <= depth+epsilon }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triangles, err := Create3DTextWithOptions("test", "2023", width, depth, BaseHeight, TextOptions{
+				UsernameFace: tt.face,
+				YearFace:     tt.face,
+				Scale:        0.5,
+			})
+			if err != nil {
+				t.Fatalf("Create3DTextWithOptions() error = %v", err)
+			}
+			if len(triangles) == 0 {
+				t.Fatal("expected text triangles")
+			}
+			for _, tri := range triangles {
+				for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+					if !tt.inside(v) {
+						t.Fatalf("vertex %v is not on the %s face", v, tt.name)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("split faces", func(t *testing.T) {
+		triangles, err := Create3DTextWithOptions("test", "2023", width, depth, BaseHeight, TextOptions{
+			UsernameFace: FaceFront,
+			YearFace:     FaceBack,
+		})
+		if err != nil {
+			t.Fatalf("Create3DTextWithOptions() error = %v", err)
+		}
+		var front, back int
+		for _, tri := range triangles {
+			if tri.V1.Y < 0 {
+				front++
+			} else if tri.V1.Y > depth {
+				back++
+			}
+		}
+		if front == 0 || back == 0 {
+			t.Errorf("expected text on both faces, got %d front and %d back triangles", front, back)
+		}
+	})
+
+	t.Run("side face needs depth", func(t *testing.T) {
+		if _, err := Create3DTextWithOptions("test", "2023", width, 0, BaseHeight, TextOptions{YearFace: FaceLeft}); err == nil {
+			t.Error("expected error without base depth")
+		}
+	})
+
+	t.Run("scale out of range", func(t *testing.T) {
+		if _, err := Create3DTextWithOptions("test", "2023", width, depth, BaseHeight, TextOptions{Scale: MaxTextScale + 1}); err == nil {
+			t.Error("expected error for oversized text")
+		}
+	})
+}
+
+func TestLayoutTextWideFace(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	standard, err := layoutText("test", "2023", width, depth, TextOptions{})
+	if err != nil {
+		t.Fatalf("layoutText() error = %v", err)
+	}
+	wide, err := layoutText("test", "2023", 3*width, depth, TextOptions{})
+	if err != nil {
+		t.Fatalf("layoutText() error = %v", err)
+	}
+
+	// Labels keep their size and sit at the ends of a face longer than a single year.
+	for i := range wide {
+		if math.Abs(wide[i].fontSize-standard[i].fontSize) > epsilon {
+			t.Errorf("%s font size = %v, want %v", wide[i].text, wide[i].fontSize, standard[i].fontSize)
+		}
+		if math.Abs(wide[i].panelWidth-width) > epsilon {
+			t.Errorf("%s panel width = %v, want %v", wide[i].text, wide[i].panelWidth, width)
+		}
+	}
+	if wide[0].panelOffset != 0 {
+		t.Errorf("username panel offset = %v, want 0", wide[0].panelOffset)
+	}
+	if want := 2 * width; math.Abs(wide[1].panelOffset-want) > epsilon {
+		t.Errorf("year panel offset = %v, want %v", wide[1].panelOffset, want)
+	}
+}
+
+func TestLayoutTextFitsYear(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(1)
+	const span = "2024-03-01 → 2025-02-28"
+	tests := []struct {
+		name     string
+		username string
+		opts     TextOptions
+		fontSize float64 // Size of a plain year, which fits
+	}{
+		{"beside username", "monalisa-octocat", TextOptions{}, yearFontSize},
+		{"own face", "test", TextOptions{YearFace: FaceBack, Scale: 2}, 2 * yearFontSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, err := layoutText(tt.username, "2023", width, depth, tt.opts)
+			if err != nil {
+				t.Fatalf("layoutText() error = %v", err)
+			}
+			long, err := layoutText(tt.username, span, width, depth, tt.opts)
+			if err != nil {
+				t.Fatalf("layoutText() error = %v", err)
+			}
+			// A plain year fits and keeps its size.
+			full := plain[1].fontSize
+			if math.Abs(full-tt.fontSize) > epsilon {
+				t.Errorf("year font size = %v, want %v", full, tt.fontSize)
+			}
+			if got := long[1].fontSize; got >= full || got < yearMinFit*full {
+				t.Errorf("date span font size = %v, want below %v and at least %v", got, full, yearMinFit*full)
+			}
+			if got := long[0].fontSize; got != plain[0].fontSize {
+				t.Errorf("username font size = %v, want %v", got, plain[0].fontSize)
+			}
+		})
+	}
+}
+
+func TestParseTextPosition(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantUsername Face
+		wantYear     Face
+		wantErr      bool
+	}{
+		{"", FaceFront, FaceFront, false},
+		{"back", FaceBack, FaceBack, false},
+		{"Left", FaceLeft, FaceLeft, false},
+		{"front,back", FaceFront, FaceBack, false},
+		{"left, right", FaceLeft, FaceRight, false},
+		{"top", FaceFront, FaceFront, true},
+		{"front,back,left", FaceFront, FaceFront, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			username, year, err := ParseTextPosition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTextPosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if username != tt.wantUsername || year != tt.wantYear {
+				t.Errorf("ParseTextPosition() = %v, %v, want %v, %v", username, year, tt.wantUsername, tt.wantYear)
+			}
+		})
+	}
+}
+
+func TestPlaceOnFaceNormals(t *testing.T) {
+	box, err := createBox(1, -1, 0, 2, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, face := range []Face{FaceBack, FaceLeft, FaceRight} {
+		placed := placeOnFace(append([]types.Triangle(nil), box...), face, 10, 5)
+		for i, tri := range placed {
+			computed, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dot := computed.X*tri.Normal.X + computed.Y*tri.Normal.Y + computed.Z*tri.Normal.Z; dot < 1-epsilon {
+				t.Errorf("face %d triangle %d normal %v does not match winding %v", face, i, tri.Normal, computed)
+			}
+		}
+	}
+}
+
 // TestRenderText verifies internal text rendering functionality
 func TestRenderText(t *testing.T) {
 	t.Run("verify text renders", func(t *testing.T) {
@@ -119,6 +304,25 @@ func TestIsPixelActive(t *testing.T) {
 	})
 }
 
+// TestPixelIntensity verifies brightness is read from the premultiplied red channel
+func TestPixelIntensity(t *testing.T) {
+	tests := []struct {
+		name  string
+		pixel color.Color
+		want  float64
+	}{
+		{"white", color.White, 1},
+		{"black", color.Black, 0},
+		{"mid gray", color.Gray{Y: 0x80}, float64(0x8080) / 0xffff},
+		{"transparent white", color.NRGBA{R: 0xff, G: 0xff, B: 0xff}, 0},
+	}
+	for _, tt := range tests {
+		if got := pixelIntensity(tt.pixel); math.Abs(got-tt.want) > epsilon {
+			t.Errorf("%s: pixelIntensity() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 // createTestPNG creates a temporary PNG file for testing
 func createTestPNG(t *testing.T) string {
 	tmpfile, err := os.CreateTemp("", "test-*.png")
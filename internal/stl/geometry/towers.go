@@ -0,0 +1,140 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// TowerTriangles is the number of triangles in each tower.
+const TowerTriangles = 12
+
+// monthGap is the space left between neighbouring month towers, so a year reads as
+// twelve separate blocks rather than a stepped slab.
+const monthGap = CellSize / 2
+
+// CreateTowerGeometry generates a single tower per active week of a year, a cell wide and
+// as deep as the week's days, for skylines aggregated by week. Days of an aggregated week
+// all carry its total, so the tower is as tall as the week's busiest day.
+func CreateTowerGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	triangles := make([]types.Triangle, 0, TowerTriangles*TowerCount(contributions))
+	for weekIdx, week := range contributions {
+		count := weekPeak(week)
+		if count <= 0 {
+			continue
+		}
+		x, y := CellPosition(weekIdx, 0, yearIndex)
+		tower, err := createBox(x, y, 0, CellSize, float64(len(week))*CellSize, NormalizeContribution(count, maxContrib))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, tower...)
+	}
+	return triangles, nil
+}
+
+// TowerCount returns the number of towers CreateTowerGeometry generates for a year.
+func TowerCount(contributions [][]types.ContributionDay) int {
+	count := 0
+	for _, week := range contributions {
+		if weekPeak(week) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// CreateMonthGeometry generates a single tower per active month of a year, for skylines
+// aggregated by month. Each tower spans the weeks types.WeekMonth assigns to its month,
+// four or five cells wide less a gap to its neighbours, and is as deep as its longest
+// week. Weeks of an aggregated month all carry its total, which sets the height.
+func CreateMonthGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	spans := MonthSpans(contributions)
+	triangles := make([]types.Triangle, 0, TowerTriangles*len(spans))
+	for _, span := range spans {
+		if span.Peak <= 0 {
+			continue
+		}
+		x, y := CellPosition(span.First, 0, yearIndex)
+		width := float64(span.Last-span.First+1)*CellSize - monthGap
+		tower, err := createBox(x+monthGap/2, y, 0, width, float64(span.Days)*CellSize, NormalizeContribution(span.Peak, maxContrib))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, tower...)
+	}
+	return triangles, nil
+}
+
+// MonthTowerCount returns the number of towers CreateMonthGeometry generates for a year.
+func MonthTowerCount(contributions [][]types.ContributionDay) int {
+	count := 0
+	for _, span := range MonthSpans(contributions) {
+		if span.Peak > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// clockHourWeeks is the number of week cells each hour's tower of a clock spans, so the
+// day's 24 towers fill about as much of the base as a year of weeks.
+const clockHourWeeks = 2
+
+// CreateClockGeometry generates a single tower per active hour of a clock row, as built by
+// types.ClockRow, for skylines of the hour of day commits were authored at. The towers are
+// two cells wide less a gap to their neighbours, as deep as a week, and centred on a base
+// as wide as a year.
+func CreateClockGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
+	triangles := make([]types.Triangle, 0, TowerTriangles*TowerCount(contributions))
+	margin := float64(max(GridSize-clockHourWeeks*len(contributions), 0)) / 2 * CellSize
+	for hour, week := range contributions {
+		count := weekPeak(week)
+		if count <= 0 {
+			continue
+		}
+		x, y := CellPosition(hour*clockHourWeeks, 0, yearIndex)
+		width := clockHourWeeks*CellSize - monthGap
+		tower, err := createBox(x+margin+monthGap/2, y, 0, width, float64(len(week))*CellSize, NormalizeContribution(count, maxContrib))
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, tower...)
+	}
+	return triangles, nil
+}
+
+// MonthSpan is a run of consecutive weeks of a row that belong to the same month.
+type MonthSpan struct {
+	Month       string // Month as "2006-01"
+	First, Last int    // Indexes of the first and last week of the run
+	Days        int    // Number of days in the longest week of the run
+	Peak        int    // Highest count of the run's days
+}
+
+// MonthSpans groups the weeks of a row by the month types.WeekMonth assigns them to, in
+// week order. Weeks without dated days belong to no span.
+func MonthSpans(contributions [][]types.ContributionDay) []MonthSpan {
+	var spans []MonthSpan
+	for weekIdx, week := range contributions {
+		month := types.WeekMonth(week)
+		if month == "" {
+			continue
+		}
+		if n := len(spans); n > 0 && spans[n-1].Month == month && spans[n-1].Last == weekIdx-1 {
+			spans[n-1].Last = weekIdx
+			spans[n-1].Days = max(spans[n-1].Days, len(week))
+			spans[n-1].Peak = max(spans[n-1].Peak, weekPeak(week))
+			continue
+		}
+		spans = append(spans, MonthSpan{Month: month, First: weekIdx, Last: weekIdx, Days: len(week), Peak: weekPeak(week)})
+	}
+	return spans
+}
+
+// weekPeak returns the highest count of the days of a week.
+func weekPeak(week []types.ContributionDay) int {
+	peak := 0
+	for _, day := range week {
+		peak = max(peak, day.ContributionCount)
+	}
+	return peak
+}
@@ -0,0 +1,111 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateTowerGeometry(t *testing.T) {
+	full := make([]types.ContributionDay, 7)
+	for i := range full {
+		full[i].ContributionCount = 10
+	}
+	contributions := [][]types.ContributionDay{full, make([]types.ContributionDay, 7), {{ContributionCount: 3}, {ContributionCount: 3}}}
+
+	triangles, err := CreateTowerGeometry(contributions, 0, 10)
+	if err != nil {
+		t.Fatalf("CreateTowerGeometry() error = %v", err)
+	}
+	// The quiet week has no tower.
+	if want := 2 * TowerTriangles; len(triangles) != want || TowerCount(contributions) != 2 {
+		t.Fatalf("CreateTowerGeometry() = %d triangles, want %d for two towers", len(triangles), want)
+	}
+
+	// The full week's tower spans its seven days at the busiest height.
+	inv := meshtest.Measure(triangles[:TowerTriangles])
+	x, y := CellPosition(0, 0, 0)
+	if inv.Min.X != x || inv.Min.Y != y || inv.Max.Y != y+7*CellSize || inv.Max.Z != MaxHeight {
+		t.Errorf("tower spans %v to %v, want a week deep and %g tall", inv.Min, inv.Max, MaxHeight)
+	}
+	if want := CellSize * 7 * CellSize * MaxHeight; math.Abs(inv.Volume-want) > 1e-9 {
+		t.Errorf("tower volume = %g, want %g for a closed box", inv.Volume, want)
+	}
+
+	// A partial week's tower is as deep as its days.
+	partial := meshtest.Measure(triangles[TowerTriangles:])
+	if got := partial.Max.Y - partial.Min.Y; math.Abs(got-2*CellSize) > 1e-9 {
+		t.Errorf("partial week tower depth = %g, want %g", got, 2*CellSize)
+	}
+}
+
+func TestCreateMonthGeometry(t *testing.T) {
+	weeks := yearWeeks(2024)
+	for _, week := range weeks {
+		for d := range week {
+			week[d].ContributionCount = 1
+		}
+	}
+	// Only January is busy; its first week runs from Monday the first to Saturday the sixth.
+	monthly := types.Aggregate([][][]types.ContributionDay{weeks}, types.GranularityMonth)[0]
+	for w := 4; w < len(monthly); w++ {
+		for d := range monthly[w] {
+			monthly[w][d].ContributionCount = 0
+		}
+	}
+
+	spans := MonthSpans(monthly)
+	if len(spans) != 12 || spans[0].Month != "2024-01" || spans[0].First != 0 || spans[0].Last != 3 || spans[0].Peak != 31 {
+		t.Fatalf("MonthSpans() = %+v, want twelve months starting with January's four weeks", spans)
+	}
+
+	triangles, err := CreateMonthGeometry(monthly, 0, 31)
+	if err != nil {
+		t.Fatalf("CreateMonthGeometry() error = %v", err)
+	}
+	if len(triangles) != TowerTriangles || MonthTowerCount(monthly) != 1 {
+		t.Fatalf("CreateMonthGeometry() = %d triangles, want a single tower", len(triangles))
+	}
+
+	// January's tower spans its four weeks, less the gap to its neighbours; the fifth
+	// holds the first of February.
+	inv := meshtest.Measure(triangles)
+	x, _ := CellPosition(0, 0, 0)
+	if math.Abs(inv.Min.X-(x+monthGap/2)) > 1e-9 || math.Abs(inv.Max.X-(x+4*CellSize-monthGap/2)) > 1e-9 || inv.Max.Z != MaxHeight {
+		t.Errorf("tower spans %v to %v, want four weeks wide and %g tall", inv.Min, inv.Max, MaxHeight)
+	}
+	if got := inv.Max.Y - inv.Min.Y; math.Abs(got-7*CellSize) > 1e-9 {
+		t.Errorf("tower depth = %g, want a full week", got)
+	}
+}
+
+func TestCreateClockGeometry(t *testing.T) {
+	row := types.ClockRow([24]int{0: 2, 23: 4})
+	triangles, err := CreateClockGeometry(row, 0, 4)
+	if err != nil {
+		t.Fatalf("CreateClockGeometry() error = %v", err)
+	}
+	if len(triangles) != 2*TowerTriangles || TowerCount(row) != 2 {
+		t.Fatalf("CreateClockGeometry() = %d triangles, want a tower for each of the two active hours", len(triangles))
+	}
+
+	// The day is centred on a year-wide grid, so midnight and eleven at night stand as far
+	// from the edges of the grid as each other.
+	x, _ := CellPosition(0, 0, 0)
+	width, _ := CalculateGridDimensions(len(row), 1)
+	inv := meshtest.Measure(triangles)
+	if left, right := inv.Min.X-x, (width-x)-inv.Max.X; math.Abs(left-right) > 1e-9 {
+		t.Errorf("towers span %g to %g, want them centred with equal margins, got %g and %g", inv.Min.X, inv.Max.X, left, right)
+	}
+	if got := inv.Max.X - inv.Min.X; math.Abs(got-(48*CellSize-monthGap)) > 1e-9 {
+		t.Errorf("towers span %g wide, want two cells per hour less a gap", got)
+	}
+
+	// The busiest hour is the tallest.
+	late := meshtest.Measure(triangles[TowerTriangles:])
+	if late.Max.Z != MaxHeight || meshtest.Measure(triangles[:TowerTriangles]).Max.Z >= MaxHeight {
+		t.Errorf("eleven at night stands %g tall, want it the tallest at %g", late.Max.Z, MaxHeight)
+	}
+}
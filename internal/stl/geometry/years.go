@@ -0,0 +1,71 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// YearMargin is how much the base grows on the left to make room for year numbers
+	// engraved into its top beside each row.
+	YearMargin = 5 * CellSize
+
+	// yearTickFontSize is the font size of the year numbers at the front panel's resolution.
+	yearTickFontSize = 60.0
+)
+
+// YearTick is a row's year and where it is engraved along the depth of the base.
+type YearTick struct {
+	Y    float64 // Centre of the row
+	Year string  // Text engraved, usually the year number
+}
+
+// YearTicks returns a tick for each row of a stacked model, oldest first, centred on the
+// row. The most recent row is at the front. offsetY is the shift of the columns from
+// their usual place.
+func YearTicks(years []string, offsetY float64) []YearTick {
+	ticks := make([]YearTick, len(years))
+	for i, year := range years {
+		_, y := CellPosition(0, 0, len(years)-1-i)
+		ticks[i] = YearTick{Y: offsetY + y + YearOffset/2, Year: year}
+	}
+	return ticks
+}
+
+// engraveYearsSide builds the skin of the left face with the year of each tick left out,
+// beside its row, clipped to [lo, hi] along the face.
+func engraveYearsSide(ticks []YearTick, baseDepth, baseHeight, lo, hi float64) ([]types.Triangle, error) {
+	pixelsPerUnit := baseWidthVoxelResolution / maxPanelWidth
+	dc := newFaceContext(int(math.Round(baseDepth*pixelsPerUnit)), baseDepth, baseHeight)
+	for _, tick := range ticks {
+		// The left face runs from the back of the base to the front.
+		x := (baseDepth - tick.Y) * pixelsPerUnit
+		if err := drawText(dc, tick.Year, "center", x, yearTickFontSize); err != nil {
+			return nil, err
+		}
+	}
+	return engraveSkin(dc, baseDepth, baseHeight, lo, hi)
+}
+
+// engraveYearsTop builds the top skin of the strip along the left of the base with the
+// year of each tick left out, reading from the front. The skin spans [lo, stripWidth]
+// across the base and [front, back] along it.
+func engraveYearsTop(ticks []YearTick, stripWidth, lo, front, back float64) ([]types.Triangle, error) {
+	height := back - front
+	pixelsPerUnit := baseWidthVoxelResolution / maxPanelWidth
+	dc := newFaceContext(int(math.Round(stripWidth*pixelsPerUnit)), stripWidth, height)
+	for _, tick := range ticks {
+		// The top of the image lies at the back of the strip.
+		y := (back - tick.Y) * pixelsPerUnit
+		if err := drawTextAt(dc, tick.Year, "center", (lo+stripWidth)/2*pixelsPerUnit, y, yearTickFontSize); err != nil {
+			return nil, err
+		}
+	}
+
+	skin, err := engraveSkin(dc, stripWidth, height, lo, stripWidth)
+	if err != nil {
+		return nil, err
+	}
+	return placeOnTop(skin, back), nil
+}
@@ -0,0 +1,89 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestYearTicks(t *testing.T) {
+	ticks := YearTicks([]string{"2022", "2023", "2024"}, 0)
+	if len(ticks) != 3 {
+		t.Fatalf("got %d ticks, want 3", len(ticks))
+	}
+
+	// The most recent row is at the front, each a year's depth behind the next.
+	if want := 2*CellSize + YearOffset/2; math.Abs(ticks[2].Y-want) > epsilon {
+		t.Errorf("2024 at y = %v, want %v", ticks[2].Y, want)
+	}
+	if math.Abs(ticks[0].Y-ticks[2].Y-2*YearOffset) > epsilon || ticks[0].Year != "2022" {
+		t.Errorf("oldest tick = %+v, want 2022 two rows behind the front", ticks[0])
+	}
+
+	shifted := YearTicks([]string{"2024"}, 10)
+	if math.Abs(shifted[0].Y-ticks[2].Y-10) > epsilon {
+		t.Errorf("offset tick at y = %v, want %v", shifted[0].Y, ticks[2].Y+10)
+	}
+}
+
+func TestCreateStatsBaseYears(t *testing.T) {
+	width, depth := CalculateMultiYearDimensions(3)
+	ticks := YearTicks([]string{"2022", "2023", "2024"}, 0)
+
+	// area sums the triangles facing normal that lie in bounds.
+	area := func(triangles []types.Triangle, normal types.Point3D, bounds func(types.Point3D) bool) float64 {
+		total := 0.0
+		for _, tri := range triangles {
+			if math.Abs(tri.Normal.X-normal.X) > epsilon || math.Abs(tri.Normal.Z-normal.Z) > epsilon || !bounds(tri.V1) || !bounds(tri.V2) || !bounds(tri.V3) {
+				continue
+			}
+			u := types.Point3D{X: tri.V2.X - tri.V1.X, Y: tri.V2.Y - tri.V1.Y, Z: tri.V2.Z - tri.V1.Z}
+			v := types.Point3D{X: tri.V3.X - tri.V1.X, Y: tri.V3.Y - tri.V1.Y, Z: tri.V3.Z - tri.V1.Z}
+			cross := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+			total += math.Sqrt(cross.X*cross.X+cross.Y*cross.Y+cross.Z*cross.Z) / 2
+		}
+		return total
+	}
+	inside := func(triangles []types.Triangle, width float64) {
+		t.Helper()
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if v.Z > epsilon || v.X < -epsilon || v.X > width+epsilon || v.Y < -epsilon || v.Y > depth+epsilon {
+					t.Fatalf("vertex %v lies outside the base", v)
+				}
+			}
+		}
+	}
+
+	for _, base := range []BaseOptions{{}, {Style: BaseRounded}, {StudSockets: true}} {
+		triangles, err := CreateStatsBase(width, depth, BaseHeight, TextOptions{Years: ticks}, base)
+		if err != nil {
+			t.Fatalf("CreateStatsBase(%+v) on the side error = %v", base, err)
+		}
+		inside(triangles, width)
+		onLeft := func(p types.Point3D) bool { return math.Abs(p.X) < epsilon }
+		if covered, face := area(triangles, types.Point3D{X: -1}, onLeft), depth*BaseHeight; covered < face/2 || covered > face-1 {
+			t.Errorf("CreateStatsBase(%+v) covers %.1f of the %.1f left face; want the years cut out", base, covered, face)
+		}
+
+		strip := YearMargin + 2*CellSize - CellSize
+		wide := width + YearMargin
+		triangles, err = CreateStatsBase(wide, depth, BaseHeight, TextOptions{Years: ticks, YearStrip: strip, Months: []MonthTick{{X: 30, Initial: "J"}}}, base)
+		if err != nil {
+			t.Fatalf("CreateStatsBase(%+v) on the top error = %v", base, err)
+		}
+		inside(triangles, wide)
+		onStrip := func(p types.Point3D) bool { return math.Abs(p.Z) < epsilon && p.X < strip+epsilon }
+		if covered, top := area(triangles, types.Point3D{Z: 1}, onStrip), strip*depth; covered < top/2 || covered > top-1 {
+			t.Errorf("CreateStatsBase(%+v) covers %.1f of the %.1f strip; want the years cut out", base, covered, top)
+		}
+	}
+
+	if _, err := CreateStatsBase(width, depth, BaseHeight, TextOptions{Years: ticks}, BaseOptions{Connectors: true}); err == nil {
+		t.Error("expected an error for years on the side of a base with connectors")
+	}
+	if _, err := CreateEngravedBase("testuser", "2022-2024", width, depth, BaseHeight, TextOptions{UsernameFace: FaceLeft, Years: ticks}, BaseOptions{}); err == nil {
+		t.Error("expected an error for years on the side beside the username")
+	}
+}
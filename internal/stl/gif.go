@@ -0,0 +1,100 @@
+package stl
+
+import (
+	"bufio"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	gifPixelsPerMM = 3.0 // Default resolution of animation frames, lower than a still preview's to keep the file small
+	gifFrames      = 24  // Frames of the build-up, the last showing the finished model
+	gifFrameDelay  = 8   // Hundredths of a second each build-up frame shows for
+	gifHoldDelay   = 300 // Hundredths of a second the finished model shows for before the loop restarts
+)
+
+// GenerateGIF writes an animated GIF of the model being built up to outputPath: the weeks
+// of every row rise in turn, from the first to the last, and the finished model holds
+// before the animation loops. Frames are drawn like GeneratePreview, at a lower
+// resolution unless opts sets one, with columns as tall as in the finished model.
+func GenerateGIF(contributions [][][]types.ContributionDay, outputPath string, opts PreviewOptions) error {
+	log := exportLog
+
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "GIF path cannot be empty", nil)
+	}
+
+	animation, err := RenderGIF(contributions, opts)
+	if err != nil {
+		return err
+	}
+	if err := writeGIF(outputPath, animation); err != nil {
+		return err
+	}
+
+	if err := log.Info("Animation written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
+
+// RenderGIF draws the animation GenerateGIF writes, for callers that store it elsewhere.
+func RenderGIF(contributions [][][]types.ContributionDay, opts PreviewOptions) (*gif.GIF, error) {
+	if len(contributions) == 0 {
+		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if opts.PixelsPerMM <= 0 {
+		opts.PixelsPerMM = gifPixelsPerMM
+	}
+
+	weeks := geometry.GridWeeks(contributions)
+	animation := &gif.GIF{}
+	for frame := 1; frame <= gifFrames; frame++ {
+		opts.weeks = (weeks*frame + gifFrames - 1) / gifFrames
+		delay := gifFrameDelay
+		if frame == gifFrames {
+			opts.weeks, delay = 0, gifHoldDelay
+		}
+		animation.Image = append(animation.Image, paletted(renderPreview(contributions, opts).Image()))
+		animation.Delay = append(animation.Delay, delay)
+	}
+	return animation, nil
+}
+
+// paletted converts a frame to the fixed palette shared by every frame, taking the
+// nearest color of each pixel; dithering would make the static parts of the frames
+// shimmer and compress worse.
+func paletted(img image.Image) *image.Paletted {
+	frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.Draw(frame, frame.Rect, img, img.Bounds().Min, draw.Src)
+	return frame
+}
+
+// writeGIF encodes the animation to path.
+func writeGIF(path string, animation *gif.GIF) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create GIF file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close GIF file", cerr)
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+	if err := gif.EncodeAll(writer, animation); err != nil {
+		return errors.New(errors.IOError, "failed to encode GIF", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush GIF writer", err)
+	}
+	return nil
+}
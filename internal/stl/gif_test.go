@@ -0,0 +1,49 @@
+package stl
+
+import (
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGenerateGIF(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	path := filepath.Join(t.TempDir(), "skyline.gif")
+	if err := GenerateGIF(contributions, path, DefaultPreviewOptions()); err != nil {
+		t.Fatalf("GenerateGIF() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	animation, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("animation is not a GIF: %v", err)
+	}
+	if len(animation.Image) != gifFrames || animation.Delay[gifFrames-1] != gifHoldDelay {
+		t.Fatalf("animation has %d frames, want %d ending on a held frame", len(animation.Image), gifFrames)
+	}
+
+	// The frames share a size, and the finished model is the only one with every column.
+	first, last := animation.Image[0], animation.Image[gifFrames-1]
+	if first.Rect != last.Rect {
+		t.Errorf("frames are %v and %v, want one size", first.Rect, last.Rect)
+	}
+	if sameImage(first, last) || !sameImage(last, paletted(renderPreview(contributions, PreviewOptions{
+		Azimuth: DefaultCameraAzimuth, Elevation: DefaultCameraElevation, PixelsPerMM: gifPixelsPerMM,
+	}).Image())) {
+		t.Error("want the build-up to start without the later weeks and end on the full preview")
+	}
+
+	if err := GenerateGIF(nil, path, DefaultPreviewOptions()); err == nil {
+		t.Error("GenerateGIF() expected error for empty contributions")
+	}
+	if err := GenerateGIF(contributions, "", DefaultPreviewOptions()); err == nil {
+		t.Error("GenerateGIF() expected error for empty path")
+	}
+}
@@ -0,0 +1,174 @@
+package stl
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	heatmapCell   = 10 // Side of a day's square, in pixels
+	heatmapGap    = 3  // Space between squares
+	heatmapMargin = 16 // Empty space around the calendar and between years
+	heatmapRadius = 2  // Corner radius of the squares
+)
+
+// Theme selects the colors of 2D exports.
+type Theme int
+
+// Supported themes.
+const (
+	ThemeLight Theme = iota // GitHub's light contribution graph
+	ThemeDark               // GitHub's dark contribution graph
+)
+
+// ParseTheme converts a flag value ("light" or "dark") into a Theme.
+func ParseTheme(name string) (Theme, error) {
+	switch strings.ToLower(name) {
+	case "", "light":
+		return ThemeLight, nil
+	case "dark":
+		return ThemeDark, nil
+	default:
+		return ThemeLight, fmt.Errorf("unknown theme %q (expected light or dark)", name)
+	}
+}
+
+// heatmapPalette holds the colors of a theme: the background, labels, days without
+// contributions, and the levels from quiet to busy.
+type heatmapPalette struct {
+	background, text, empty color.RGBA
+	levels                  [4]color.RGBA
+}
+
+// heatmapPalettes are the colors of GitHub's contribution graph in each theme.
+var heatmapPalettes = map[Theme]heatmapPalette{
+	ThemeLight: {
+		background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		text:       color.RGBA{R: 0x24, G: 0x29, B: 0x2f, A: 0xff},
+		empty:      color.RGBA{R: 0xeb, G: 0xed, B: 0xf0, A: 0xff},
+		levels:     [4]color.RGBA{{R: 0x9b, G: 0xe9, B: 0xa8, A: 0xff}, {R: 0x40, G: 0xc4, B: 0x63, A: 0xff}, {R: 0x30, G: 0xa1, B: 0x4e, A: 0xff}, {R: 0x21, G: 0x6e, B: 0x39, A: 0xff}},
+	},
+	ThemeDark: {
+		background: color.RGBA{R: 0x0d, G: 0x11, B: 0x17, A: 0xff},
+		text:       color.RGBA{R: 0xe6, G: 0xed, B: 0xf3, A: 0xff},
+		empty:      color.RGBA{R: 0x16, G: 0x1b, B: 0x22, A: 0xff},
+		levels:     [4]color.RGBA{{R: 0x0e, G: 0x44, B: 0x29, A: 0xff}, {R: 0x00, G: 0x6d, B: 0x32, A: 0xff}, {R: 0x26, G: 0xa6, B: 0x41, A: 0xff}, {R: 0x39, G: 0xd3, B: 0x53, A: 0xff}},
+	},
+}
+
+// HeatmapOptions controls how GenerateHeatmap draws the calendar.
+type HeatmapOptions struct {
+	Theme Theme // Colors of the image
+
+	// Thresholds grade days into the levels; nil grades them by their share of the
+	// busiest day.
+	Thresholds types.Thresholds
+
+	// Labels are printed left of each year's calendar, e.g. the years; nil leaves them out.
+	Labels []string
+}
+
+// GenerateHeatmap writes a PNG of the classic contribution calendar to outputPath: a
+// square per day, seven rows per week and a column per week, shaded by the day's count.
+// Years ([year][week][day], oldest first) are drawn one under another.
+func GenerateHeatmap(contributions [][][]types.ContributionDay, outputPath string, opts HeatmapOptions) error {
+	log := exportLog
+
+	if len(contributions) == 0 {
+		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "heatmap path cannot be empty", nil)
+	}
+
+	dc := renderHeatmap(contributions, opts, time.Now())
+	if err := writePNG(outputPath, dc.Image()); err != nil {
+		return err
+	}
+
+	if err := log.Info("Heatmap written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
+
+// RenderHeatmap draws the calendar GenerateHeatmap writes, for outputs that embed it.
+func RenderHeatmap(contributions [][][]types.ContributionDay, opts HeatmapOptions) image.Image {
+	return renderHeatmap(contributions, opts, time.Now()).Image()
+}
+
+// renderHeatmap draws the calendar of every year, leaving days after now blank.
+func renderHeatmap(contributions [][][]types.ContributionDay, opts HeatmapOptions, now time.Time) *gg.Context {
+	palette := heatmapPalettes[opts.Theme]
+	step := heatmapCell + heatmapGap
+
+	weeks := 0
+	for _, year := range contributions {
+		weeks = max(weeks, len(year))
+	}
+	measure := gg.NewContext(1, 1)
+	labelWidth := 0.0
+	for _, label := range opts.Labels {
+		w, _ := measure.MeasureString(label)
+		labelWidth = max(labelWidth, w)
+	}
+	left := heatmapMargin
+	if labelWidth > 0 {
+		left += int(labelWidth) + heatmapMargin/2
+	}
+
+	yearHeight := 7*step - heatmapGap
+	width := left + weeks*step - heatmapGap + heatmapMargin
+	height := heatmapMargin + len(contributions)*(yearHeight+heatmapMargin)
+	dc := gg.NewContext(width, height)
+	dc.SetColor(palette.background)
+	dc.Clear()
+
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+	for i, year := range contributions {
+		top := heatmapMargin + i*(yearHeight+heatmapMargin)
+		if i < len(opts.Labels) {
+			dc.SetColor(palette.text)
+			dc.DrawStringAnchored(opts.Labels[i], heatmapMargin, float64(top), 0, 1)
+		}
+		for weekIdx, week := range year {
+			// A partial first week is missing its earliest days, so it sits at the bottom.
+			offset := 0
+			if weekIdx == 0 {
+				offset = 7 - len(week)
+			}
+			for dayIdx, day := range week {
+				if day.IsAfter(now) {
+					continue
+				}
+				x := left + weekIdx*step
+				y := top + (offset+dayIdx)*step
+				dc.DrawRoundedRectangle(float64(x), float64(y), heatmapCell, heatmapCell, heatmapRadius)
+				dc.SetColor(heatmapColor(palette, opts.Thresholds, day.ContributionCount, maxContrib))
+				dc.Fill()
+			}
+		}
+	}
+	return dc
+}
+
+// heatmapColor returns the square color for a day's count: empty without contributions,
+// otherwise the level its thresholds, or its share of the busiest day, reach.
+func heatmapColor(palette heatmapPalette, thresholds types.Thresholds, count, maxContrib int) color.RGBA {
+	if count <= 0 || maxContrib <= 0 {
+		return palette.empty
+	}
+	levels := len(palette.levels)
+	if len(thresholds) > 0 {
+		level := max(thresholds.Level(count), 1)
+		return palette.levels[(2*level-1)*levels/(2*len(thresholds))]
+	}
+	return palette.levels[min(levels-1, (count*levels-1)/maxContrib)]
+}
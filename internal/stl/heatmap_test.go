@@ -0,0 +1,110 @@
+package stl
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Theme
+		wantErr bool
+	}{
+		{"", ThemeLight, false},
+		{"light", ThemeLight, false},
+		{"Dark", ThemeDark, false},
+		{"sepia", ThemeLight, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTheme(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTheme(%q) = %v, %v, want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerateHeatmap(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "heatmap.png")
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	if err := GenerateHeatmap(contributions, outputPath, HeatmapOptions{Theme: ThemeDark, Labels: []string{"2023", "2024"}}); err != nil {
+		t.Fatalf("GenerateHeatmap() error = %v", err)
+	}
+
+	file, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatalf("failed to open heatmap: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("failed to decode heatmap: %v", err)
+	}
+
+	step := heatmapCell + heatmapGap
+	if got, want := img.Bounds().Dy(), heatmapMargin+2*(7*step-heatmapGap+heatmapMargin); got != want {
+		t.Errorf("heatmap height = %d, want %d for two years", got, want)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != heatmapPalettes[ThemeDark].background {
+		t.Errorf("corner = %v, want the dark background", got)
+	}
+
+	for _, path := range []string{"", outputPath} {
+		var input [][][]types.ContributionDay
+		if path == "" {
+			input = contributions
+		}
+		if err := GenerateHeatmap(input, path, HeatmapOptions{}); err == nil {
+			t.Errorf("GenerateHeatmap(%d years, %q) expected an error", len(input), path)
+		}
+	}
+}
+
+func TestRenderHeatmapSkipsFutureDays(t *testing.T) {
+	year := [][][]types.ContributionDay{{{
+		{ContributionCount: 3, Date: "2024-03-03"},
+		{ContributionCount: 3, Date: "2024-03-04"},
+	}}}
+	now := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)
+	img := renderHeatmap(year, HeatmapOptions{}, now).Image()
+
+	// The partial week sits at the bottom: Sunday on the sixth row, Monday on the seventh.
+	step := heatmapCell + heatmapGap
+	center := func(row int) color.Color {
+		return color.RGBAModel.Convert(img.At(heatmapMargin+heatmapCell/2, heatmapMargin+row*step+heatmapCell/2))
+	}
+	if got := center(5); got != heatmapPalettes[ThemeLight].levels[3] {
+		t.Errorf("past day = %v, want the busiest level", got)
+	}
+	if got := center(6); got != heatmapPalettes[ThemeLight].background {
+		t.Errorf("future day = %v, want it left blank", got)
+	}
+}
+
+func TestHeatmapColor(t *testing.T) {
+	palette := heatmapPalettes[ThemeLight]
+	tests := []struct {
+		name       string
+		thresholds types.Thresholds
+		count, max int
+		want       color.RGBA
+	}{
+		{"no contributions", nil, 0, 10, palette.empty},
+		{"quiet", nil, 1, 100, palette.levels[0]},
+		{"second quartile", nil, 26, 100, palette.levels[1]},
+		{"busiest", nil, 100, 100, palette.levels[3]},
+		{"below thresholds", types.Thresholds{5, 10}, 1, 100, palette.levels[1]},
+		{"above thresholds", types.Thresholds{5, 10}, 50, 100, palette.levels[3]},
+	}
+	for _, tt := range tests {
+		if got := heatmapColor(palette, tt.thresholds, tt.count, tt.max); got != tt.want {
+			t.Errorf("%s: heatmapColor() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
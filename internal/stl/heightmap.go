@@ -0,0 +1,130 @@
+package stl
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// heightmapPixelsPerMM defines the raster resolution of exported heightmaps.
+// At 10 pixels per millimeter a single year produces a ~1425x275 pixel image,
+// which is fine enough for CNC and laser-engraving toolpaths.
+const heightmapPixelsPerMM = 10.0
+
+// GenerateHeightmap writes a 16-bit grayscale PNG heightmap of the model to outputPath.
+// Each pixel encodes the height of the model's top surface when viewed from above,
+// scaled so that black is the bottom of the base and white is the tallest possible column.
+// The front of the model is at the bottom of the image.
+func GenerateHeightmap(contributions [][][]types.ContributionDay, outputPath string) error {
+	log := exportLog
+
+	if len(contributions) == 0 {
+		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "heightmap path cannot be empty", nil)
+	}
+
+	img := renderHeightmap(contributions)
+
+	if err := log.Debug("Writing heightmap (%dx%d) to: %s", img.Bounds().Dx(), img.Bounds().Dy(), outputPath); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+
+	if err := writePNG(outputPath, img); err != nil {
+		return err
+	}
+
+	if err := log.Info("Heightmap written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
+
+// renderHeightmap rasterizes the base and contribution columns into a 16-bit grayscale image.
+// Column placement mirrors CreateContributionGeometry so the heightmap lines up with the STL.
+func renderHeightmap(contributions [][][]types.ContributionDay) *image.Gray16 {
+	width, depth := geometry.CalculateGridDimensions(geometry.GridWeeks(contributions), len(contributions))
+	pxWidth := int(math.Ceil(width * heightmapPixelsPerMM))
+	pxDepth := int(math.Ceil(depth * heightmapPixelsPerMM))
+
+	img := image.NewGray16(image.Rect(0, 0, pxWidth, pxDepth))
+
+	totalHeight := geometry.BaseHeight + geometry.MaxHeight
+	baseLevel := heightToGray(geometry.BaseHeight, totalHeight)
+	for y := 0; y < pxDepth; y++ {
+		for x := 0; x < pxWidth; x++ {
+			img.SetGray16(x, y, baseLevel)
+		}
+	}
+
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+
+	// Match the STL layout: the most recent year sits at the front of the model.
+	for i := len(contributions) - 1; i >= 0; i-- {
+		yearIndex := len(contributions) - 1 - i
+		for weekIdx, week := range contributions[i] {
+			for dayIdx, day := range week {
+				if day.ContributionCount <= 0 {
+					continue
+				}
+				height := geometry.NormalizeContribution(day.ContributionCount, maxContrib)
+				x, y := geometry.CellPosition(weekIdx, dayIdx, yearIndex)
+				fillCell(img, x, y, geometry.CellSize, heightToGray(geometry.BaseHeight+height, totalHeight))
+			}
+		}
+	}
+
+	return img
+}
+
+// fillCell paints a square model-space cell into the image.
+// Model Y grows away from the viewer, so it is flipped to keep the front at the bottom.
+func fillCell(img *image.Gray16, x, y, size float64, level color.Gray16) {
+	pxDepth := img.Bounds().Dy()
+	x0 := int(math.Round(x * heightmapPixelsPerMM))
+	x1 := int(math.Round((x + size) * heightmapPixelsPerMM))
+	y0 := pxDepth - int(math.Round((y+size)*heightmapPixelsPerMM))
+	y1 := pxDepth - int(math.Round(y*heightmapPixelsPerMM))
+
+	for py := max(y0, 0); py < min(y1, pxDepth); py++ {
+		for px := max(x0, 0); px < min(x1, img.Bounds().Dx()); px++ {
+			img.SetGray16(px, py, level)
+		}
+	}
+}
+
+// heightToGray maps a model height in millimeters onto the full 16-bit gray range.
+func heightToGray(height, totalHeight float64) color.Gray16 {
+	ratio := math.Max(0, math.Min(1, height/totalHeight))
+	return color.Gray16{Y: uint16(math.Round(ratio * math.MaxUint16))}
+}
+
+// writePNG encodes an image to the given path as PNG.
+func writePNG(path string, img image.Image) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create PNG file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close PNG file", cerr)
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+	if err := png.Encode(writer, img); err != nil {
+		return errors.New(errors.IOError, "failed to encode PNG", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush PNG writer", err)
+	}
+	return nil
+}
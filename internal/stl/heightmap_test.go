@@ -0,0 +1,104 @@
+package stl
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGenerateHeightmap(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "depth.png")
+
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	if err := GenerateHeightmap(contributions, outputPath); err != nil {
+		t.Fatalf("GenerateHeightmap() error = %v", err)
+	}
+
+	file, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatalf("failed to open heightmap: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("failed to decode heightmap: %v", err)
+	}
+
+	width, depth := geometry.CalculateMultiYearDimensions(1)
+	if got, want := img.Bounds().Dx(), int(width*heightmapPixelsPerMM); got < want {
+		t.Errorf("heightmap width = %d, want at least %d", got, want)
+	}
+	if got, want := img.Bounds().Dy(), int(depth*heightmapPixelsPerMM); got < want {
+		t.Errorf("heightmap depth = %d, want at least %d", got, want)
+	}
+
+	if _, ok := img.(*image.Gray16); !ok {
+		t.Errorf("heightmap decoded as %T, want 16-bit grayscale", img)
+	}
+
+	errorTests := []struct {
+		name          string
+		contributions [][][]types.ContributionDay
+		outputPath    string
+	}{
+		{"empty contributions", nil, outputPath},
+		{"empty output path", contributions, ""},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := GenerateHeightmap(tt.contributions, tt.outputPath); err == nil {
+				t.Error("GenerateHeightmap() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestRenderHeightmap(t *testing.T) {
+	contributions := [][][]types.ContributionDay{{
+		{{ContributionCount: 10}},
+	}}
+
+	img := renderHeightmap(contributions)
+	totalHeight := geometry.BaseHeight + geometry.MaxHeight
+
+	// The padding corner only contains the base.
+	if got, want := img.Gray16At(0, img.Bounds().Dy()-1), heightToGray(geometry.BaseHeight, totalHeight); got != want {
+		t.Errorf("base pixel = %v, want %v", got, want)
+	}
+
+	// The first cell holds the tallest column, so it maps to full white.
+	x, y := geometry.CellPosition(0, 0, 0)
+	px := int((x + geometry.CellSize/2) * heightmapPixelsPerMM)
+	py := img.Bounds().Dy() - int((y+geometry.CellSize/2)*heightmapPixelsPerMM)
+	if got := img.Gray16At(px, py); got.Y != 0xffff {
+		t.Errorf("column pixel = %v, want full white", got)
+	}
+}
+
+func TestHeightToGray(t *testing.T) {
+	tests := []struct {
+		name   string
+		height float64
+		want   uint16
+	}{
+		{"zero height", 0, 0},
+		{"full height", 10, 0xffff},
+		{"clamped above", 20, 0xffff},
+		{"clamped below", -5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heightToGray(tt.height, 10); got.Y != tt.want {
+				t.Errorf("heightToGray(%v) = %v, want %v", tt.height, got.Y, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Layout selects how multiple years are arranged on the base.
+type Layout int
+
+// Supported layouts.
+const (
+	LayoutStacked Layout = iota // One row per year, most recent at the front
+	LayoutStrip                 // Every week end-to-end in a single row
+	LayoutSpiral                // Every week around a round base, spiralling out a turn per year
+)
+
+// ParseLayout converts a flag value ("stacked", "strip" or "spiral") into a Layout.
+func ParseLayout(name string) (Layout, error) {
+	switch strings.ToLower(name) {
+	case "", "stacked":
+		return LayoutStacked, nil
+	case "strip":
+		return LayoutStrip, nil
+	case "spiral":
+		return LayoutSpiral, nil
+	default:
+		return LayoutStacked, fmt.Errorf("unknown layout %q (expected stacked, strip or spiral)", name)
+	}
+}
+
+// YearLabels selects where each row of a stacked model is labelled with its year.
+type YearLabels int
+
+// Supported year label placements.
+const (
+	YearLabelsNone  YearLabels = iota // No year beside the rows
+	YearLabelsSide                    // Engraved into the left face of the base, beside each row
+	YearLabelsFront                   // Engraved into the top of a wider base left of each row, reading from the front
+)
+
+// ParseYearLabels converts a flag value ("side", "front" or "none") into YearLabels.
+func ParseYearLabels(name string) (YearLabels, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return YearLabelsNone, nil
+	case "side":
+		return YearLabelsSide, nil
+	case "front":
+		return YearLabelsFront, nil
+	default:
+		return YearLabelsNone, fmt.Errorf("unknown year labels %q (expected side, front or none)", name)
+	}
+}
+
+// ArrangeContributions regroups contributions ([year][week][day], oldest year first) into
+// the rows of the given layout. The stacked layout pads every year to a full GridSize-week
+// grid so stacked years line up exactly. The strip and spiral layouts join every year into
+// one row; the partial weeks either side of a new year are merged so the row has no gaps.
+// Partial weeks at the ends of a row are filled out with empty days so each day sits in
+// its weekday's position.
+func ArrangeContributions(contributions [][][]types.ContributionDay, layout Layout) [][][]types.ContributionDay {
+	if (layout == LayoutStrip || layout == LayoutSpiral) && len(contributions) > 1 {
+		return [][][]types.ContributionDay{types.PadWeeks(joinYears(contributions), 0)}
+	}
+
+	rows := make([][][]types.ContributionDay, len(contributions))
+	for i, year := range contributions {
+		rows[i] = types.PadWeeks(year, geometry.GridSize)
+	}
+	return rows
+}
+
+// MinWrapWeeks is the fewest weeks a wrapped row may hold, a quarter of a year, which
+// keeps the base wide enough for the username and logo on its front.
+const MinWrapWeeks = 13
+
+// WrapContributions splits each year of contributions ([year][week][day], oldest year
+// first) into rows of about weeks weeks on a squarer base, continuing each year on the
+// row in front of it. A year is split into as many rows as weeks fits most closely, and
+// its weeks shared evenly between them, so the extra partial week of a 53-week year
+// joins a row rather than starting one of its own. Every row is padded to the longest,
+// so the rows line up; partial first and last weeks are filled out as in the stacked
+// layout.
+func WrapContributions(contributions [][][]types.ContributionDay, weeks int) [][][]types.ContributionDay {
+	var rows [][][]types.ContributionDay
+	width := 0
+	for _, year := range contributions {
+		year = types.PadWeeks(year, 0)
+		count := max(1, int(math.Round(float64(len(year))/float64(max(weeks, 1)))))
+		size := (len(year) + count - 1) / count
+		for start := 0; start < len(year); start += size {
+			rows = append(rows, year[start:min(start+size, len(year))])
+		}
+		width = max(width, size)
+	}
+	for i, row := range rows {
+		rows[i] = types.PadWeeks(row, width)
+	}
+	return rows
+}
+
+// joinYears lays every week of every year end-to-end, merging partial boundary weeks.
+func joinYears(contributions [][][]types.ContributionDay) [][]types.ContributionDay {
+	var row [][]types.ContributionDay
+	for _, year := range contributions {
+		for i, week := range year {
+			if i == 0 && len(row) > 0 {
+				last := row[len(row)-1]
+				if len(last) < 7 && len(week) < 7 && len(last)+len(week) <= 7 {
+					row[len(row)-1] = slices.Concat(last, week)
+					continue
+				}
+			}
+			row = append(row, week)
+		}
+	}
+	return row
+}
@@ -0,0 +1,231 @@
+package stl
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Layout
+		wantErr bool
+	}{
+		{"", LayoutStacked, false},
+		{"stacked", LayoutStacked, false},
+		{"Strip", LayoutStrip, false},
+		{"Spiral", LayoutSpiral, false},
+		{"circle", LayoutStacked, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLayout(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLayout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// makeYear builds a year whose first and last weeks are partial, as returned by the API.
+func makeYear(firstDays, lastDays int) [][]types.ContributionDay {
+	year := make([][]types.ContributionDay, 53)
+	for i := range year {
+		days := 7
+		switch i {
+		case 0:
+			days = firstDays
+		case len(year) - 1:
+			days = lastDays
+		}
+		year[i] = make([]types.ContributionDay, days)
+		for j := range year[i] {
+			year[i][j] = types.ContributionDay{ContributionCount: i + 1}
+		}
+	}
+	return year
+}
+
+func TestWrapContributions(t *testing.T) {
+	years := [][][]types.ContributionDay{makeYear(5, 3), makeYear(7, 7)}
+
+	// Each 53-week year wraps into two rows rather than leaving a week on a third.
+	rows := WrapContributions(years, 26)
+	if len(rows) != 4 || geometry.GridWeeks(rows) != 27 {
+		t.Fatalf("wrapped into %d rows of %d weeks, want 4 rows of 27", len(rows), geometry.GridWeeks(rows))
+	}
+	// Rows continue in order, oldest at the back, and the shorter rows are padded.
+	if rows[0][0][2].ContributionCount != 1 || rows[1][0][0].ContributionCount != 28 || rows[2][0][0].ContributionCount != 1 {
+		t.Errorf("rows start with weeks %d, %d and %d, want 1, 28 and 1", rows[0][0][2].ContributionCount, rows[1][0][0].ContributionCount, rows[2][0][0].ContributionCount)
+	}
+	if rows[0][0][1].ContributionCount != 0 {
+		t.Errorf("partial first week not padded with leading days: %v", rows[0][0])
+	}
+	if len(rows[1]) != 27 || rows[1][26][0].ContributionCount != 0 || len(rows[1][25]) != 7 {
+		t.Errorf("second row = %d weeks ending with %v, want 27 with an empty week", len(rows[1]), rows[1][len(rows[1])-1])
+	}
+
+	if quarters := WrapContributions(years[:1], MinWrapWeeks); len(quarters) != 4 || geometry.GridWeeks(quarters) != 14 {
+		t.Errorf("wrapped into %d rows of %d weeks, want 4 rows of 14", len(quarters), geometry.GridWeeks(quarters))
+	}
+}
+
+func TestArrangeContributions(t *testing.T) {
+	years := [][][]types.ContributionDay{makeYear(7, 2), makeYear(5, 3), makeYear(4, 7)}
+
+	stacked := ArrangeContributions(years, LayoutStacked)
+	if len(stacked) != 3 {
+		t.Fatalf("stacked layout has %d rows, want 3", len(stacked))
+	}
+	// Every stacked year is padded to full weeks, with partial first weeks right-aligned.
+	for i, row := range stacked {
+		for j, week := range row {
+			if len(week) != 7 {
+				t.Fatalf("stacked year %d week %d has %d days, want 7", i, j, len(week))
+			}
+		}
+	}
+	if stacked[1][0][1].ContributionCount != 0 || stacked[1][0][2].ContributionCount != 1 {
+		t.Errorf("partial first week not padded with leading days: %v", stacked[1][0])
+	}
+
+	strip := ArrangeContributions(years, LayoutStrip)
+	if len(strip) != 1 {
+		t.Fatalf("strip layout has %d rows, want 1", len(strip))
+	}
+	// The first boundary (2+5 days) merges into one week; the second (3+4) does too.
+	if got, want := len(strip[0]), 3*53-2; got != want {
+		t.Errorf("strip has %d weeks, want %d", got, want)
+	}
+	for i, week := range strip[0][1 : len(strip[0])-1] {
+		if len(week) != 7 {
+			t.Errorf("week %d has %d days, want 7", i+1, len(week))
+		}
+	}
+	if len(years[0][52]) != 2 {
+		t.Error("ArrangeContributions modified its input")
+	}
+
+	// Boundary weeks that would overflow a week are kept separate.
+	unmerged := ArrangeContributions([][][]types.ContributionDay{makeYear(7, 5), makeYear(5, 7)}, LayoutStrip)
+	if got := len(unmerged[0]); got != 2*53 {
+		t.Errorf("strip without a mergeable boundary has %d weeks, want %d", got, 2*53)
+	}
+}
+
+func TestGenerateSTLRangeWithStripLayout(t *testing.T) {
+	contributions := [][][]types.ContributionDay{makeYear(7, 2), makeYear(5, 7)}
+	path := filepath.Join(t.TempDir(), "strip.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2023, 2024, Options{Layout: LayoutStrip}); err != nil {
+		t.Fatalf("strip generation failed: %v", err)
+	}
+
+	standPath := filepath.Join(t.TempDir(), "strip-stand.stl")
+	if err := GenerateStand(ArrangeContributions(contributions, LayoutStrip), standPath); err != nil {
+		t.Fatalf("strip stand generation failed: %v", err)
+	}
+}
+
+func TestGenerateSTLRangeWithSpiralLayout(t *testing.T) {
+	contributions := [][][]types.ContributionDay{makeYear(7, 2), makeYear(5, 7)}
+	rows := ArrangeContributions(contributions, LayoutSpiral)
+	if len(rows) != 1 {
+		t.Fatalf("spiral has %d rows, want 1", len(rows))
+	}
+	spiral := geometry.NewSpiral(len(rows[0]))
+	for _, style := range []Style{StyleTowers, StyleSmooth} {
+		triangles, err := columnsForYear(rows, 0, findMaxContributionsAcrossYears(rows), modelDimensions{}, Options{Style: style, Layout: LayoutSpiral})
+		if err != nil {
+			t.Fatalf("columnsForYear() error = %v", err)
+		}
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				if r := math.Hypot(v.X-spiral.Centre, v.Y-spiral.Centre); r < spiral.InnerRadius-1e-6 || r > spiral.Radius {
+					t.Fatalf("style %v: vertex %v at radius %v, off the round base", style, v, r)
+				}
+			}
+		}
+	}
+
+	observer := &mocks.MockObserver{}
+	path := filepath.Join(t.TempDir(), "spiral.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2023, 2024, Options{Layout: LayoutSpiral, Observer: observer}); err != nil {
+		t.Fatalf("spiral generation failed: %v", err)
+	}
+	// The round base has no face for the logo.
+	want := []string{"geometry base 1/3", "geometry columns 2/3", "geometry text 3/3", "write " + path}
+	if strings.Join(observer.Events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("observer events = %q, want %q", observer.Events, want)
+	}
+}
+
+func TestParseYearLabels(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    YearLabels
+		wantErr bool
+	}{
+		{"", YearLabelsNone, false},
+		{"none", YearLabelsNone, false},
+		{"Side", YearLabelsSide, false},
+		{"front", YearLabelsFront, false},
+		{"top", YearLabelsNone, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseYearLabels(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseYearLabels(%q) = %v, %v, want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerateSTLRangeWithYearLabels(t *testing.T) {
+	contributions := [][][]types.ContributionDay{makeYear(7, 2), makeYear(5, 7)}
+	extent := func(labels YearLabels) (minX, maxX float64) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "years.stl")
+		if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2023, 2024, Options{YearLabels: labels}); err != nil {
+			t.Fatalf("generation with year labels %v failed: %v", labels, err)
+		}
+		triangles, err := ReadSTLBinary(path)
+		if err != nil {
+			t.Fatalf("ReadSTLBinary() error = %v", err)
+		}
+		minX, maxX = math.Inf(1), math.Inf(-1)
+		for _, tri := range triangles {
+			for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+				minX, maxX = min(minX, v.X), max(maxX, v.X)
+			}
+		}
+		return minX, maxX
+	}
+
+	minX, maxX := extent(YearLabelsNone)
+	if sideMin, sideMax := extent(YearLabelsSide); math.Abs(sideMin-minX) > 1e-4 || math.Abs(sideMax-maxX) > 1e-4 {
+		t.Errorf("side labels span x %v to %v, want the unlabelled %v to %v", sideMin, sideMax, minX, maxX)
+	}
+	if frontMin, frontMax := extent(YearLabelsFront); math.Abs(frontMin-minX) > 1e-4 || math.Abs(frontMax-maxX-geometry.YearMargin) > 1e-4 {
+		t.Errorf("front labels span x %v to %v, want the base widened by %v", frontMin, frontMax, geometry.YearMargin)
+	}
+
+	dims := modelDimensions{innerWidth: 100, innerDepth: 60}
+	for _, opts := range []Options{{YearLabels: YearLabelsSide, Layout: LayoutStrip}, {YearLabels: YearLabelsFront, Style: StylePlaque}} {
+		if opts.yearLabelled() {
+			t.Errorf("%+v: rows labelled, want year labels ignored", opts)
+		}
+	}
+	if got := yearMarginDimensions(dims); got.innerWidth != 100+geometry.YearMargin || got.offsetX != geometry.YearMargin {
+		t.Errorf("yearMarginDimensions() = %+v, want the base and columns moved by the margin", got)
+	}
+}
@@ -0,0 +1,69 @@
+package stl
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// MeshSize is the printed bulk of a model, from which filament use and print time can be
+// estimated without a slicer.
+type MeshSize struct {
+	Volume float64 // Volume enclosed by the mesh in mm³
+	Area   float64 // Surface area of the mesh in mm²
+	Height float64 // Height of the tallest part in mm
+}
+
+// MeasureModel meshes the model GenerateSTLRangeWithOptions would build from the same
+// arguments and measures it. Embossed labels, such as the text and logo, are left out:
+// they add little material but take the longest to mesh. Columns split into separate
+// files by the breakdown are measured as part of the model, as they are printed too.
+func MeasureModel(contributions [][][]types.ContributionDay, username string, startYear, endYear int, opts Options) (MeshSize, error) {
+	if len(contributions) == 0 {
+		return MeshSize{}, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if opts.Style == StyleBricks {
+		opts.Base.StudSockets = true
+	}
+	if opts.Breakdown == BreakdownSplit {
+		opts.Breakdown = BreakdownStacked
+	}
+	rows, dims, maxContrib, err := modelLayout(contributions, opts)
+	if err != nil {
+		return MeshSize{}, err
+	}
+
+	var components []modelComponent
+	for _, c := range modelComponents(rows, dims, maxContrib, username, startYear, endYear, opts) {
+		if componentMaterial(c.name) != types.MaterialLabel {
+			components = append(components, c)
+		}
+	}
+	var size MeshSize
+	bottom, top := math.Inf(1), math.Inf(-1)
+	err = runOrdered(modelJobs(components, rows, maxContrib, dims, opts), opts.workerCount(), func(_ geometryJob, triangles []types.Triangle) error {
+		for _, tri := range triangles {
+			size.Volume += tetrahedronVolume(tri)
+			size.Area += triangleArea(tri)
+			bottom = min(bottom, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+			top = max(top, tri.V1.Z, tri.V2.Z, tri.V3.Z)
+		}
+		return nil
+	})
+	if err != nil {
+		return MeshSize{}, err
+	}
+	if top > bottom {
+		size.Height = top - bottom
+	}
+	return size, nil
+}
+
+// triangleArea returns the area of a triangle.
+func triangleArea(tri types.Triangle) float64 {
+	u := types.Point3D{X: tri.V2.X - tri.V1.X, Y: tri.V2.Y - tri.V1.Y, Z: tri.V2.Z - tri.V1.Z}
+	v := types.Point3D{X: tri.V3.X - tri.V1.X, Y: tri.V3.Y - tri.V1.Y, Z: tri.V3.Z - tri.V1.Z}
+	n := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+	return math.Sqrt(n.X*n.X+n.Y*n.Y+n.Z*n.Z) / 2
+}
@@ -0,0 +1,49 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestMeasureModel(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions()}
+	size, err := MeasureModel(rows, "testuser", 2024, 2024, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The measure covers the base and columns, and leaves the text and logo out.
+	dims, err := calculateGridDimensions(geometry.GridWeeks(rows), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := geometry.CreateCuboidBase(dims.innerWidth, dims.innerDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, err := columnsForYear(rows, 0, findMaxContributionsAcrossYears(rows), dims, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := meshtest.Measure(append(base, columns...))
+	if math.Abs(size.Volume-want.Volume) > 1e-6*want.Volume || math.Abs(size.Area-want.SurfaceArea) > 1e-6*want.SurfaceArea {
+		t.Errorf("MeasureModel() = %+v, want volume %v and area %v", size, want.Volume, want.SurfaceArea)
+	}
+	if size.Height != want.Max.Z-want.Min.Z {
+		t.Errorf("Height = %v, want %v", size.Height, want.Max.Z-want.Min.Z)
+	}
+
+	// Taller columns hold more material.
+	tall, err := MeasureModel(rows, "testuser", 2024, 2024, Options{HeightScale: 2})
+	if err != nil || tall.Volume <= size.Volume || tall.Height <= size.Height {
+		t.Errorf("MeasureModel(HeightScale 2) = %+v, %v, want more than %+v", tall, err, size)
+	}
+
+	if _, err := MeasureModel(nil, "testuser", 2024, 2024, Options{}); err == nil {
+		t.Error("MeasureModel() expected an error for empty contributions")
+	}
+}
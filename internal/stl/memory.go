@@ -0,0 +1,143 @@
+package stl
+
+import (
+	"unicode/utf8"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+const (
+	// triangleMemorySize is the in-memory size of a types.Triangle:
+	// a normal and three vertices of three float64 coordinates each.
+	triangleMemorySize = 4 * 3 * 8
+
+	// trianglesPerColumn is the number of triangles in a single contribution column.
+	trianglesPerColumn = 12
+
+	// textTrianglesPerGlyph approximates the voxelized triangles of one embossed character.
+	textTrianglesPerGlyph = 20000
+
+	// logoTriangles approximates the voxelized triangles of the embedded logo.
+	logoTriangles = 272000
+)
+
+// Estimate describes the predicted size of a generated model.
+// Memory figures cover triangle storage only; they are upper-bound approximations
+// intended for preflight checks rather than exact accounting.
+type Estimate struct {
+	Triangles      int    // Total triangles in the model
+	FileSize       uint64 // Size of the binary STL file in bytes
+	InMemoryBytes  uint64 // Peak triangle memory when the whole model is assembled before writing
+	StreamingBytes uint64 // Peak triangle memory per worker when components are written as they are generated
+}
+
+// EstimateModel predicts the size of the model GenerateSTLRange would build for the given data.
+func EstimateModel(contributions [][][]types.ContributionDay, username string, startYear, endYear int) Estimate {
+	return EstimateModelWithOptions(contributions, username, startYear, endYear, Options{})
+}
+
+// EstimateModelWithOptions is EstimateModel for a model generated with the given options.
+// Only the style, layout, wrapping, double-siding, streak merging and inversion change
+// the estimate.
+func EstimateModelWithOptions(contributions [][][]types.ContributionDay, username string, startYear, endYear int, opts Options) Estimate {
+	if username == "" {
+		username = "anonymous"
+	}
+	glyphs := utf8.RuneCountInString(username) + utf8.RuneCountInString(utils.FormatYearRange(startYear, endYear))
+	text, logo := glyphs*textTrianglesPerGlyph, logoTriangles
+	if opts.Style.ReplacesBase() {
+		text, logo = 0, 0
+	}
+	if opts.Layout == LayoutSpiral {
+		logo = 0
+	}
+	if opts.doubleSided() {
+		text += (utf8.RuneCountInString(username) + utf8.RuneCountInString(opts.BackLabel)) * textTrianglesPerGlyph
+	}
+
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+	columns, largestYear := 0, 0
+	for _, year := range contributions {
+		yearColumns := columnTriangles(year, maxContrib, opts)
+		columns += yearColumns
+		largestYear = max(largestYear, yearColumns)
+	}
+	if opts.Style == StyleLithophane {
+		// The panel covers the whole arrangement in one piece.
+		rows := opts.arrange(contributions)
+		columns = geometry.LithophaneTriangleCount(geometry.GridWeeks(rows), len(rows))
+		largestYear = columns
+	}
+	if opts.Style == StyleSilhouette {
+		// The plate traces the profile of every row at once.
+		columns = geometry.SilhouetteTriangleCount(geometry.ProfileHeights(opts.arrange(contributions)))
+		largestYear = columns
+	}
+
+	if opts.wrapped() && opts.Separators {
+		columns += geometry.SeparatorTriangleCount(len(opts.arrange(contributions)))
+	}
+
+	total := trianglesPerColumn + columns + text + logo
+	largestComponent := max(largestYear, text, logo)
+
+	return Estimate{
+		Triangles: total,
+		FileSize:  80 + 4 + uint64(total)*triangleSize,
+		// Every component is held until the model is assembled into a second slice.
+		InMemoryBytes: 2 * uint64(total) * triangleMemorySize,
+		// Only one component is alive at a time, plus the write buffer.
+		StreamingBytes: uint64(largestComponent)*triangleMemorySize + bufferSize,
+	}
+}
+
+// columnTriangles estimates the triangles in one year's columns.
+func columnTriangles(year [][]types.ContributionDay, maxContrib int, opts Options) int {
+	if opts.Template {
+		return trianglesPerColumn * geometry.TemplateDotCount(year)
+	}
+	style := opts.Style
+	if style == StyleSmooth {
+		// The surface covers a full grid of weeks whatever the contributions.
+		return geometry.SurfaceTriangleCount(max(len(year), geometry.GridSize))
+	}
+	if style == StyleClock {
+		return geometry.TowerTriangles * geometry.TowerCount(year)
+	}
+	if opts.Inverted {
+		return geometry.MoldTriangleCount(len(year))
+	}
+	if opts.MergeStreaks && style != StyleBricks && opts.Breakdown != BreakdownStacked {
+		return geometry.StreakTriangleCount(year, maxContrib)
+	}
+	if opts.Granularity == types.GranularityWeek && style != StyleBricks && opts.Breakdown != BreakdownStacked && !opts.Delta {
+		return geometry.TowerTriangles * geometry.TowerCount(year)
+	}
+	if opts.Granularity == types.GranularityMonth && style != StyleBricks && opts.Breakdown != BreakdownStacked && !opts.Delta {
+		return geometry.TowerTriangles * geometry.MonthTowerCount(year)
+	}
+	triangles := 0
+	if opts.Delta {
+		// Declines are pits in a deck as large as a mold's block.
+		triangles = geometry.MoldTriangleCount(len(year))
+	}
+	for _, week := range year {
+		for _, day := range week {
+			switch {
+			case day.ContributionCount <= 0:
+			case style == StyleBricks:
+				triangles += geometry.BrickTriangleCount(geometry.NormalizeContribution(day.ContributionCount, maxContrib))
+			default:
+				triangles += trianglesPerColumn
+				if opts.highlighted() && opts.Highlight[day.Date] {
+					triangles += geometry.HighlightCapTriangles
+				} else if opts.capped() {
+					triangles += opts.TowerCap.Triangles()
+				}
+			}
+		}
+	}
+	return triangles
+}
@@ -0,0 +1,65 @@
+package stl
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestEstimateModel(t *testing.T) {
+	oneYear := [][][]types.ContributionDay{{
+		{{ContributionCount: 1}, {ContributionCount: 0}},
+		{{ContributionCount: 3}},
+	}}
+
+	got := EstimateModel(oneYear, "octocat", 2024, 2024)
+
+	wantText := (len("octocat") + len("2024")) * textTrianglesPerGlyph
+	wantTriangles := trianglesPerColumn + 2*trianglesPerColumn + wantText + logoTriangles
+	if got.Triangles != wantTriangles {
+		t.Errorf("Triangles = %d, want %d", got.Triangles, wantTriangles)
+	}
+	if want := uint64(84 + wantTriangles*triangleSize); got.FileSize != want {
+		t.Errorf("FileSize = %d, want %d", got.FileSize, want)
+	}
+	if got.StreamingBytes >= got.InMemoryBytes {
+		t.Errorf("StreamingBytes = %d, want less than InMemoryBytes %d", got.StreamingBytes, got.InMemoryBytes)
+	}
+
+	// More years only grow the in-memory estimate; streaming is bounded by the largest component.
+	manyYears := make([][][]types.ContributionDay, 20)
+	for i := range manyYears {
+		manyYears[i] = createTestContributions()
+	}
+	large := EstimateModel(manyYears, "octocat", 2005, 2024)
+	if large.InMemoryBytes <= got.InMemoryBytes {
+		t.Errorf("InMemoryBytes for 20 years = %d, want more than %d", large.InMemoryBytes, got.InMemoryBytes)
+	}
+	if large.StreamingBytes != EstimateModel(manyYears[:1], "octocat", 2005, 2024).StreamingBytes {
+		t.Error("StreamingBytes should not grow with the number of years")
+	}
+}
+
+// BenchmarkGenerateSTLRange measures allocation for long year ranges, streamed year by
+// year when stacked and assembled in memory when laid out as a strip.
+// Run with: go test ./internal/stl -bench GenerateSTLRange -benchmem
+func BenchmarkGenerateSTLRange(b *testing.B) {
+	contributions := make([][][]types.ContributionDay, 15)
+	for i := range contributions {
+		contributions[i] = createTestContributions()
+	}
+
+	for _, layout := range []Layout{LayoutStacked, LayoutStrip} {
+		b.Run(fmt.Sprintf("layout=%d", layout), func(b *testing.B) {
+			outputPath := filepath.Join(b.TempDir(), "bench.stl")
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := GenerateSTLRangeWithOptions(contributions, outputPath, "benchuser", 2010, 2024, Options{Layout: layout}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
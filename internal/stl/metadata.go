@@ -0,0 +1,57 @@
+package stl
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// headerSize is the length of the binary STL header.
+const headerSize = 80
+
+// contentHashLength is the number of hex digits of the triangle data's SHA-256 kept in
+// the header.
+const contentHashLength = 16
+
+// Metadata records how a model was produced. It is written into the 80-byte STL header as
+// "gh-skyline/VERSION USER RANGE sha256:HASH FLAGS", where HASH is the start of the SHA-256
+// of the triangle data. Fields are written in that order and the header is cut at 80 bytes,
+// so long flag sets are truncated first.
+type Metadata struct {
+	Version   string // Tool version
+	User      string // GitHub username of the contributions
+	StartYear int    // First year of the range
+	EndYear   int    // Last year of the range
+	Flags     string // Command-line flags the model was generated with
+}
+
+// fields returns the metadata keyed by field name, as recorded on a types.Model.
+func (m Metadata) fields() map[string]string {
+	fields := map[string]string{
+		"generator": "gh-skyline/" + m.Version,
+		"user":      m.User,
+		"range":     utils.FormatYearRange(m.StartYear, m.EndYear),
+	}
+	if m.Flags != "" {
+		fields["flags"] = m.Flags
+	}
+	return fields
+}
+
+// header formats the metadata and content hash as an STL header.
+func (m Metadata) header(contentHash []byte) []byte {
+	fields := []string{
+		"gh-skyline/" + m.Version,
+		m.User,
+		utils.FormatYearRange(m.StartYear, m.EndYear),
+		"sha256:" + hex.EncodeToString(contentHash)[:contentHashLength],
+	}
+	if m.Flags != "" {
+		fields = append(fields, m.Flags)
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, strings.Join(fields, " "))
+	return header
+}
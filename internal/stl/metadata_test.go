@@ -0,0 +1,64 @@
+package stl
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestMetadataHeader(t *testing.T) {
+	hash := sha256.Sum256([]byte("triangles"))
+	meta := Metadata{Version: "v1.2.3", User: "mona", StartYear: 2020, EndYear: 2024, Flags: "--layout=strip"}
+
+	header := meta.header(hash[:])
+	if len(header) != headerSize {
+		t.Fatalf("header is %d bytes, want %d", len(header), headerSize)
+	}
+	want := "gh-skyline/v1.2.3 mona 2020-24 sha256:" + string(hexDigits(hash[:])) + " --layout=strip"
+	if got := strings.TrimRight(string(header), "\x00"); got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+
+	// Long flag sets are cut off at the end of the header.
+	meta.Flags = strings.Repeat("--stand=true ", 10)
+	header = meta.header(hash[:])
+	if len(header) != headerSize || !strings.HasPrefix(string(header), "gh-skyline/v1.2.3 mona 2020-24 sha256:") {
+		t.Errorf("truncated header = %q", header)
+	}
+}
+
+// hexDigits returns the header's prefix of a hash in hex.
+func hexDigits(hash []byte) []byte {
+	const digits = "0123456789abcdef"
+	out := make([]byte, 0, contentHashLength)
+	for _, b := range hash[:contentHashLength/2] {
+		out = append(out, digits[b>>4], digits[b&0x0f])
+	}
+	return out
+}
+
+func TestWriteSTLBinaryWithMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.stl")
+	triangles := []types.Triangle{{V2: types.Point3D{X: 1}, V3: types.Point3D{Y: 1}}}
+	if err := WriteSTLBinaryWithMetadata(path, triangles, Metadata{Version: "dev", User: "mona", StartYear: 2024, EndYear: 2024}); err != nil {
+		t.Fatalf("WriteSTLBinaryWithMetadata() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != headerSize+4+triangleSize {
+		t.Fatalf("file is %d bytes, want %d", len(data), headerSize+4+triangleSize)
+	}
+	// The hash covers the triangle records that follow the header and count.
+	hash := sha256.Sum256(data[headerSize+4:])
+	want := "gh-skyline/dev mona 2024 sha256:" + string(hexDigits(hash[:]))
+	if got := strings.TrimRight(string(data[:headerSize]), "\x00"); got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+}
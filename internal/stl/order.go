@@ -0,0 +1,49 @@
+package stl
+
+import (
+	"cmp"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// sortTriangles puts triangles into a canonical order, by their vertices and then their
+// normal, so a component's output does not depend on the order its geometry was built in.
+// Components are sorted separately, and columns year by year, which keeps streamed and
+// in-memory models byte-identical.
+func sortTriangles(triangles []types.Triangle) {
+	sort.Sort(byVertices(triangles))
+}
+
+// byVertices sorts triangles in place with compareTriangles, comparing them by pointer to
+// avoid copying each triangle on every comparison.
+type byVertices []types.Triangle
+
+func (t byVertices) Len() int           { return len(t) }
+func (t byVertices) Less(i, j int) bool { return compareTriangles(&t[i], &t[j]) < 0 }
+func (t byVertices) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+// compareTriangles orders triangles lexicographically by V1, V2, V3 and Normal.
+func compareTriangles(a, b *types.Triangle) int {
+	if c := comparePoints(&a.V1, &b.V1); c != 0 {
+		return c
+	}
+	if c := comparePoints(&a.V2, &b.V2); c != 0 {
+		return c
+	}
+	if c := comparePoints(&a.V3, &b.V3); c != 0 {
+		return c
+	}
+	return comparePoints(&a.Normal, &b.Normal)
+}
+
+// comparePoints orders points by X, then Y, then Z.
+func comparePoints(a, b *types.Point3D) int {
+	if c := cmp.Compare(a.X, b.X); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Y, b.Y); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Z, b.Z)
+}
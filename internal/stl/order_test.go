@@ -0,0 +1,153 @@
+package stl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/meshtest"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// updateGolden rewrites testdata/golden-hashes.json with the hashes of the current output.
+var updateGolden = flag.Bool("update-golden", false, "update the golden STL hashes")
+
+const goldenHashesPath = "testdata/golden-hashes.json"
+
+func TestSortTriangles(t *testing.T) {
+	a := types.Triangle{V1: types.Point3D{X: 1}}
+	b := types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{Z: -1}}
+	c := types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{Z: -1}, Normal: types.Point3D{Y: 1}}
+	d := types.Triangle{V1: types.Point3D{X: 2}}
+
+	triangles := []types.Triangle{d, c, a, b}
+	sortTriangles(triangles)
+	for i, want := range []types.Triangle{b, c, a, d} {
+		if triangles[i] != want {
+			t.Errorf("triangle %d = %v, want %v", i, triangles[i], want)
+		}
+	}
+}
+
+// goldenContributions returns two years of varied, fully deterministic contributions.
+func goldenContributions() [][][]types.ContributionDay {
+	return meshtest.Grid(2, geometry.GridSize)
+}
+
+// goldenConfigs are the generation settings whose output is pinned by golden hashes.
+var goldenConfigs = map[string]Options{
+	"stacked":   {},
+	"strip":     {Layout: LayoutStrip, Base: geometry.BaseOptions{Connectors: true}},
+	"engraved":  {EngraveText: true, Braille: true, Base: geometry.BaseOptions{Style: geometry.BaseRounded}, Text: geometry.TextOptions{Stats: "1,234 contributions · 5 day streak", UsernameFace: geometry.FaceLeft, YearFace: geometry.FaceLeft}},
+	"segmented": {Breakdown: BreakdownStacked},
+}
+
+// generateGolden writes the model for a golden config and returns its bytes.
+func generateGolden(t *testing.T, opts Options) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "golden.stl")
+	opts.Flags = "--golden"
+	if err := GenerateSTLRangeWithOptions(goldenContributions(), path, "goldenuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestReproducibleOutput(t *testing.T) {
+	for name, opts := range goldenConfigs {
+		t.Run(name, func(t *testing.T) {
+			if !bytes.Equal(generateGolden(t, opts), generateGolden(t, opts)) {
+				t.Error("identical inputs produced different STL files")
+			}
+		})
+	}
+}
+
+func TestGoldenHashes(t *testing.T) {
+	// Other architectures may fuse floating-point multiply-adds, which changes the
+	// last bits of some vertices; the hashes are recorded on amd64.
+	if runtime.GOARCH != "amd64" && !*updateGolden {
+		t.Skipf("golden hashes are recorded on amd64, not %s", runtime.GOARCH)
+	}
+
+	got := map[string]string{}
+	for name, opts := range goldenConfigs {
+		sum := sha256.Sum256(generateGolden(t, opts))
+		got[name] = hex.EncodeToString(sum[:])
+	}
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenHashesPath, append(data, '\n'), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(goldenHashesPath)
+	if err != nil {
+		t.Fatalf("reading golden hashes (run with -update-golden to create them): %v", err)
+	}
+	var want map[string]string
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	for name, hash := range got {
+		if want[name] != hash {
+			t.Errorf("%s model hash = %s, want %s; if the change is intended, run go test ./internal/stl -update-golden", name, hash, want[name])
+		}
+	}
+}
+
+// meshConfigs are the generation settings whose geometric invariants are pinned, covering
+// each style and layout. Unlike hashes, invariants survive reordering triangles and
+// last-bit rounding, so they are checked on every architecture.
+var meshConfigs = map[string]Options{
+	"towers":        {},
+	"smooth":        {Style: StyleSmooth},
+	"bricks":        {Style: StyleBricks},
+	"penholder":     {Style: StylePenholder},
+	"lithophane":    {Style: StyleLithophane},
+	"plaque":        {Style: StylePlaque},
+	"strip":         {Layout: LayoutStrip, Base: geometry.BaseOptions{Connectors: true}},
+	"spiral":        {Layout: LayoutSpiral},
+	"inverted":      {Inverted: true},
+	"merge-streaks": {MergeStreaks: true},
+	"segmented":     {Breakdown: BreakdownStacked},
+	"year-labels":   {YearLabels: YearLabelsFront},
+	"engraved":      {EngraveText: true, Braille: true, Base: geometry.BaseOptions{Style: geometry.BaseRounded}, Text: geometry.TextOptions{Stats: "1,234 contributions · 5 day streak", UsernameFace: geometry.FaceLeft, YearFace: geometry.FaceLeft}},
+}
+
+func TestGoldenMeshes(t *testing.T) {
+	got := map[string]meshtest.Invariants{}
+	for name, opts := range meshConfigs {
+		contributions := goldenContributions()
+		if opts.Layout != LayoutStacked {
+			contributions = contributions[1:]
+		}
+		path := filepath.Join(t.TempDir(), name+".stl")
+		if err := GenerateSTLRangeWithOptions(contributions, path, "goldenuser", 2024-len(contributions)+1, 2024, opts); err != nil {
+			t.Fatalf("%s: generation failed: %v", name, err)
+		}
+		triangles, err := ReadSTLBinary(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got[name] = meshtest.Measure(triangles)
+	}
+	meshtest.Check(t, "testdata/golden-meshes.json", got)
+}
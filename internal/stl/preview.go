@@ -0,0 +1,259 @@
+package stl
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	previewPixelsPerMM = 8.0 // Default raster resolution of preview images
+	previewMarginMM    = 5.0 // Empty space around the model
+
+	// DefaultCameraAzimuth and DefaultCameraElevation, in degrees, look at the model from
+	// in front and to the right, and from above.
+	DefaultCameraAzimuth   = 30.0
+	DefaultCameraElevation = 30.0
+)
+
+// Colors of the preview, shaded per face so the columns read as solid.
+var (
+	previewBackground = color.RGBA{R: 0xf6, G: 0xf8, B: 0xfa, A: 0xff}
+	previewBase       = [3]color.RGBA{{R: 0x42, G: 0x4a, B: 0x53, A: 0xff}, {R: 0x57, G: 0x60, B: 0x6a, A: 0xff}, {R: 0x32, G: 0x38, B: 0x3f, A: 0xff}}
+	previewColumn     = [3]color.RGBA{{R: 0x2d, G: 0xa4, B: 0x4e, A: 0xff}, {R: 0x40, G: 0xc4, B: 0x63, A: 0xff}, {R: 0x21, G: 0x6e, B: 0x39, A: 0xff}}
+)
+
+// previewLevels are the column colors, from quiet to busy, when the preview is graded by
+// thresholds. They are the greens of GitHub's contribution graph.
+var previewLevels = []color.RGBA{
+	{R: 0x9b, G: 0xe9, B: 0xa8, A: 0xff},
+	{R: 0x40, G: 0xc4, B: 0x63, A: 0xff},
+	{R: 0x30, G: 0xa1, B: 0x4e, A: 0xff},
+	{R: 0x21, G: 0x6e, B: 0x39, A: 0xff},
+}
+
+// PreviewOptions controls how GeneratePreview renders the model. Start from
+// DefaultPreviewOptions: the zero value looks at the model straight from the front.
+type PreviewOptions struct {
+	// Thresholds grade the columns into GitHub's greens; nil draws them a single green.
+	Thresholds types.Thresholds
+
+	// Azimuth swings the camera around the model, in degrees from straight in front,
+	// towards the right side when positive and the left side when negative.
+	Azimuth float64
+
+	// Elevation raises the camera, in degrees from level with the model (0) to straight
+	// above it (90).
+	Elevation float64
+
+	// Background fills the image around the model; nil uses a light grey.
+	Background color.Color
+
+	// Material colors the whole model as though printed in a single filament, in place of
+	// the grey base and green columns; nil keeps them.
+	Material color.Color
+
+	// PixelsPerMM is the resolution of the image; zero uses the default of 8.
+	PixelsPerMM float64
+
+	// weeks is the number of leading weeks of each row whose columns are drawn, for the
+	// frames of a build-up animation; zero draws them all.
+	weeks int
+}
+
+// DefaultPreviewOptions returns the options of the standard preview: the default camera,
+// background and colors.
+func DefaultPreviewOptions() PreviewOptions {
+	return PreviewOptions{Azimuth: DefaultCameraAzimuth, Elevation: DefaultCameraElevation}
+}
+
+// GeneratePreview writes a PNG rendering of the model to outputPath, as seen by an
+// orthographic camera placed by opts. Like GenerateHeightmap it is drawn from the
+// contribution rows, so it matches the columns of the STL without reading it back.
+func GeneratePreview(contributions [][][]types.ContributionDay, outputPath string, opts PreviewOptions) error {
+	log := exportLog
+
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "preview path cannot be empty", nil)
+	}
+
+	img, err := RenderPreview(contributions, opts)
+	if err != nil {
+		return err
+	}
+	if err := writePNG(outputPath, img); err != nil {
+		return err
+	}
+
+	if err := log.Info("Preview written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
+
+// RenderPreview draws the image GeneratePreview writes, for callers that store it
+// elsewhere, such as in an archive.
+func RenderPreview(contributions [][][]types.ContributionDay, opts PreviewOptions) (image.Image, error) {
+	if len(contributions) == 0 {
+		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	return renderPreview(contributions, opts).Image(), nil
+}
+
+// previewCamera projects model coordinates orthographically onto the image plane.
+type previewCamera struct {
+	right, up, view types.Point3D // Image axes and the direction towards the camera
+}
+
+// newPreviewCamera places the camera at the given azimuth and elevation, in degrees.
+func newPreviewCamera(azimuth, elevation float64) previewCamera {
+	a, e := azimuth*math.Pi/180, elevation*math.Pi/180
+	view := types.Point3D{X: math.Sin(a) * math.Cos(e), Y: -math.Cos(a) * math.Cos(e), Z: math.Sin(e)}
+	right := types.Point3D{X: math.Cos(a), Y: math.Sin(a)}
+	up := types.Point3D{
+		X: view.Y*right.Z - view.Z*right.Y,
+		Y: view.Z*right.X - view.X*right.Z,
+		Z: view.X*right.Y - view.Y*right.X,
+	}
+	return previewCamera{right: right, up: up, view: view}
+}
+
+// project returns the image coordinates of a point, with y up, and its distance towards
+// the camera.
+func (c previewCamera) project(x, y, z float64) (u, v, depth float64) {
+	dot := func(a types.Point3D) float64 { return a.X*x + a.Y*y + a.Z*z }
+	return dot(c.right), dot(c.up), dot(c.view)
+}
+
+// renderPreview draws the base and columns. Columns are painted from the farthest to the
+// nearest, so nearer faces cover farther ones.
+func renderPreview(contributions [][][]types.ContributionDay, opts PreviewOptions) *gg.Context {
+	width, depth := geometry.CalculateGridDimensions(geometry.GridWeeks(contributions), len(contributions))
+	totalHeight := geometry.BaseHeight + geometry.MaxHeight
+	camera := newPreviewCamera(opts.Azimuth, opts.Elevation)
+	scale := previewPixelsPerMM
+	if opts.PixelsPerMM > 0 {
+		scale = opts.PixelsPerMM
+	}
+
+	// Frame the box the model can fill.
+	minU, maxU, minV, maxV := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, x := range []float64{0, width} {
+		for _, y := range []float64{0, depth} {
+			for _, z := range []float64{0, totalHeight} {
+				u, v, _ := camera.project(x, y, z)
+				minU, maxU, minV, maxV = min(minU, u), max(maxU, u), min(minV, v), max(maxV, v)
+			}
+		}
+	}
+	pxWidth := int(math.Ceil((maxU - minU + 2*previewMarginMM) * scale))
+	pxHeight := int(math.Ceil((maxV - minV + 2*previewMarginMM) * scale))
+	dc := gg.NewContext(pxWidth, pxHeight)
+	background := color.Color(previewBackground)
+	if opts.Background != nil {
+		background = opts.Background
+	}
+	dc.SetColor(background)
+	dc.Clear()
+
+	// project maps model coordinates, with z up from the bottom of the base, onto the image.
+	project := func(x, y, z float64) (float64, float64) {
+		u, v, _ := camera.project(x, y, z)
+		return (previewMarginMM + u - minU) * scale, (previewMarginMM + maxV - v) * scale
+	}
+
+	baseShades, columnShades := previewBase, previewColumn
+	if opts.Material != nil {
+		material := color.RGBAModel.Convert(opts.Material).(color.RGBA)
+		baseShades, columnShades = shadeColor(material), shadeColor(material)
+	}
+	drawPreviewBox(dc, camera, project, 0, 0, 0, width, depth, geometry.BaseHeight, baseShades)
+
+	type column struct {
+		x, y, height, depth float64
+		shades              [3]color.RGBA
+	}
+	var columns []column
+	maxContrib := findMaxContributionsAcrossYears(contributions)
+	for i := len(contributions) - 1; i >= 0; i-- {
+		yearIndex := len(contributions) - 1 - i
+		for weekIdx, week := range contributions[i] {
+			if opts.weeks > 0 && weekIdx >= opts.weeks {
+				break
+			}
+			for dayIdx, day := range week {
+				if day.ContributionCount <= 0 {
+					continue
+				}
+				x, y := geometry.CellPosition(weekIdx, dayIdx, yearIndex)
+				shades := columnShades
+				if len(opts.Thresholds) > 0 && opts.Material == nil {
+					shades = previewShades(opts.Thresholds, day.ContributionCount)
+				}
+				_, _, distance := camera.project(x+geometry.CellSize/2, y+geometry.CellSize/2, 0)
+				columns = append(columns, column{x, y, geometry.NormalizeContribution(day.ContributionCount, maxContrib), distance, shades})
+			}
+		}
+	}
+	// Columns share a footprint size and stand on the same plane, so the distance of their
+	// centres orders them.
+	slices.SortStableFunc(columns, func(a, b column) int {
+		return cmp.Compare(a.depth, b.depth)
+	})
+	for _, c := range columns {
+		drawPreviewBox(dc, camera, project, c.x, c.y, geometry.BaseHeight, geometry.CellSize, geometry.CellSize, c.height, c.shades)
+	}
+	return dc
+}
+
+// previewShades returns the shades of an active day's column in the level color its count
+// reaches, spread evenly over previewLevels. Days below the first threshold take the
+// quietest color.
+func previewShades(thresholds types.Thresholds, count int) [3]color.RGBA {
+	level := max(thresholds.Level(count), 1)
+	return shadeColor(previewLevels[(2*level-1)*len(previewLevels)/(2*len(thresholds))])
+}
+
+// shadeColor returns the colors of the front and back, top, and side faces of a box in
+// color c, lit from above.
+func shadeColor(c color.RGBA) [3]color.RGBA {
+	scale := func(f float64) color.RGBA {
+		channel := func(v uint8) uint8 { return uint8(min(255, math.Round(float64(v)*f))) }
+		return color.RGBA{R: channel(c.R), G: channel(c.G), B: channel(c.B), A: c.A}
+	}
+	return [3]color.RGBA{c, scale(1.2), scale(0.75)}
+}
+
+// drawPreviewBox fills the faces of a box that face the camera, using the colors in shades
+// for the front and back, top, and side faces in that order.
+func drawPreviewBox(dc *gg.Context, camera previewCamera, project func(x, y, z float64) (float64, float64), x, y, z, w, d, h float64, shades [3]color.RGBA) {
+	faces := []struct {
+		facing  float64
+		shade   int
+		corners [4][3]float64
+	}{
+		{-camera.view.Y, 0, [4][3]float64{{x, y, z}, {x + w, y, z}, {x + w, y, z + h}, {x, y, z + h}}},                // Front
+		{camera.view.Y, 0, [4][3]float64{{x, y + d, z}, {x + w, y + d, z}, {x + w, y + d, z + h}, {x, y + d, z + h}}}, // Back
+		{camera.view.Z, 1, [4][3]float64{{x, y, z + h}, {x + w, y, z + h}, {x + w, y + d, z + h}, {x, y + d, z + h}}}, // Top
+		{-camera.view.X, 2, [4][3]float64{{x, y, z}, {x, y + d, z}, {x, y + d, z + h}, {x, y, z + h}}},                // Left
+		{camera.view.X, 2, [4][3]float64{{x + w, y, z}, {x + w, y + d, z}, {x + w, y + d, z + h}, {x + w, y, z + h}}}, // Right
+	}
+	for _, face := range faces {
+		if face.facing <= 0 {
+			continue
+		}
+		for _, p := range face.corners {
+			dc.LineTo(project(p[0], p[1], p[2]))
+		}
+		dc.ClosePath()
+		dc.SetColor(shades[face.shade])
+		dc.Fill()
+	}
+}
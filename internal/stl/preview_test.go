@@ -0,0 +1,143 @@
+package stl
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGeneratePreview(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	path := filepath.Join(t.TempDir(), "preview.png")
+	if err := GeneratePreview(contributions, path, DefaultPreviewOptions()); err != nil {
+		t.Fatalf("GeneratePreview() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("preview is not a PNG: %v", err)
+	}
+
+	// The model fills the middle of the image, inside the margin.
+	bounds := img.Bounds()
+	if bounds.Dx() <= bounds.Dy() {
+		t.Errorf("preview is %dx%d, want a landscape image", bounds.Dx(), bounds.Dy())
+	}
+	if got := img.At(bounds.Dx()/2, bounds.Dy()/2); got == img.At(0, 0) {
+		t.Error("expected the model in the middle of the preview")
+	}
+
+	if err := GeneratePreview(nil, path, DefaultPreviewOptions()); err == nil {
+		t.Error("GeneratePreview() expected error for empty contributions")
+	}
+	if err := GeneratePreview(contributions, "", DefaultPreviewOptions()); err == nil {
+		t.Error("GeneratePreview() expected error for empty path")
+	}
+}
+
+func TestPreviewShades(t *testing.T) {
+	quartiles := types.Thresholds{1, 5, 10, 20}
+	for count, want := range map[int]int{1: 0, 7: 1, 12: 2, 40: 3} {
+		if got := previewShades(quartiles, count)[0]; got != previewLevels[want] {
+			t.Errorf("count %d: front = %v, want level %d", count, got, want)
+		}
+	}
+	// Fewer levels are spread across the palette.
+	if got := previewShades(types.Thresholds{1, 10}, 10)[0]; got != previewLevels[3] {
+		t.Errorf("top of two levels = %v, want the busiest color", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "graded.png")
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	opts := DefaultPreviewOptions()
+	opts.Thresholds = quartiles
+	if err := GeneratePreview(contributions, path, opts); err != nil {
+		t.Fatalf("GeneratePreview() error = %v", err)
+	}
+}
+
+func TestPreviewCamera(t *testing.T) {
+	// Straight in front, level: x runs right, z up, and the camera looks along +y.
+	front := newPreviewCamera(0, 0)
+	if u, v, depth := front.project(1, 2, 3); math.Abs(u-1) > 1e-9 || math.Abs(v-3) > 1e-9 || math.Abs(depth+2) > 1e-9 {
+		t.Errorf("front camera projects (1, 2, 3) to (%v, %v) at depth %v", u, v, depth)
+	}
+	// Straight above: the back of the model is at the top of the image.
+	above := newPreviewCamera(0, 90)
+	if _, v, depth := above.project(0, 1, 1); math.Abs(v-1) > 1e-9 || math.Abs(depth-1) > 1e-9 {
+		t.Errorf("top camera projects (0, 1, 1) to v = %v at depth %v", v, depth)
+	}
+	// From the right, the right side faces the camera.
+	if right := newPreviewCamera(90, 0); right.view.X < 1-1e-9 {
+		t.Errorf("right camera looks from %v", right.view)
+	}
+}
+
+func TestRenderPreviewOptions(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	background := color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}
+	material := color.RGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0xff}
+
+	opts := DefaultPreviewOptions()
+	opts.Background, opts.Material = background, material
+	img := renderPreview(contributions, opts).Image()
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != background {
+		t.Errorf("corner = %v, want the background %v", got, background)
+	}
+	// Every model pixel is a shade of the material.
+	shades := shadeColor(material)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 7 {
+		for x := bounds.Min.X; x < bounds.Max.X; x += 7 {
+			got := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if got != background && got != shades[0] && got != shades[1] && got != shades[2] && !blended(got, background, shades) {
+				t.Fatalf("pixel (%d, %d) = %v, not a shade of the material", x, y, got)
+			}
+		}
+	}
+
+	// Swinging the camera to the other side mirrors which side faces are drawn.
+	left := DefaultPreviewOptions()
+	left.Azimuth = -DefaultCameraAzimuth
+	if sameImage(renderPreview(contributions, left).Image(), renderPreview(contributions, DefaultPreviewOptions()).Image()) {
+		t.Error("preview from the left matches the preview from the right")
+	}
+}
+
+// blended reports whether c lies between two of the given colors, as on anti-aliased edges.
+func blended(c, background color.RGBA, shades [3]color.RGBA) bool {
+	within := func(v, a, b uint8) bool { return v >= min(a, b) && v <= max(a, b) }
+	for _, a := range append(shades[:], background) {
+		for _, b := range append(shades[:], background) {
+			if within(c.R, a.R, b.R) && within(c.G, a.G, b.G) && within(c.B, a.B, b.B) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+		for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
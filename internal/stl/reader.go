@@ -0,0 +1,158 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// File is the contents of an STL file, binary or ASCII.
+type File struct {
+	ASCII     bool             // Whether the file is ASCII rather than binary
+	Header    string           // The binary header, or the name after "solid" in an ASCII file
+	Declared  int              // Triangle count recorded in a binary header; the facet count for ASCII
+	Truncated bool             // Whether a binary file's size disagrees with its declared count
+	Triangles []types.Triangle // The whole triangles the file holds
+}
+
+// Model returns the file's triangles as a model of a single component, as STL files do
+// not record the parts of the model they hold. The header, if any, is kept as metadata.
+func (f File) Model() *types.Model {
+	model := &types.Model{Components: []types.Component{{Name: "model", Triangles: f.Triangles}}}
+	if f.Header != "" {
+		model.Metadata = map[string]string{"header": f.Header}
+	}
+	return model
+}
+
+// ReadSTL reads an STL file in either format, such as one written by WriteSTLBinary or
+// exported by another tool. A binary file whose size does not match its triangle count is
+// read as far as it holds whole triangles and marked Truncated.
+func ReadSTL(filename string) (File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return File{}, errors.New(errors.IOError, "failed to read STL file", err)
+	}
+	return ParseSTL(data)
+}
+
+// ParseSTL reads the contents of an STL file in either format, as ReadSTL does.
+func ParseSTL(data []byte) (File, error) {
+	if isASCII(data) {
+		return parseASCII(data)
+	}
+	if len(data) < headerSize+4 {
+		return File{}, errors.New(errors.ValidationError, "file is too short to be a binary STL", nil)
+	}
+
+	count := uint64(binary.LittleEndian.Uint32(data[headerSize:]))
+	body := uint64(len(data) - headerSize - 4)
+	f := File{
+		Header:    strings.TrimRight(string(data[:headerSize]), "\x00 "),
+		Declared:  int(count),
+		Truncated: body != count*triangleSize,
+	}
+	f.Triangles = make([]types.Triangle, min(count, body/triangleSize))
+	offset := headerSize + 4
+	point := func() types.Point3D {
+		p := types.Point3D{
+			X: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset:]))),
+			Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+4:]))),
+			Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+8:]))),
+		}
+		offset += 12
+		return p
+	}
+	for i := range f.Triangles {
+		f.Triangles[i] = types.Triangle{Normal: point(), V1: point(), V2: point(), V3: point()}
+		offset += 2 // attribute count
+	}
+	return f, nil
+}
+
+// ReadSTLBinary reads the triangles of a binary STL file, such as one written by
+// WriteSTLBinary. ASCII STL files are not supported.
+func ReadSTLBinary(filename string) ([]types.Triangle, error) {
+	f, err := ReadSTL(filename)
+	if err != nil {
+		return nil, err
+	}
+	if f.ASCII || f.Truncated {
+		return nil, errors.New(errors.ValidationError, "file size does not match its triangle count; only binary STL files are supported", nil)
+	}
+	return f.Triangles, nil
+}
+
+// isASCII reports whether data looks like an ASCII STL. Binary headers may also start
+// with "solid", so the file must contain a facet or end its solid, too.
+func isASCII(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if !bytes.HasPrefix(trimmed, []byte("solid")) {
+		return false
+	}
+	if len(data) >= headerSize+4 {
+		count := uint64(binary.LittleEndian.Uint32(data[headerSize:]))
+		if uint64(len(data)) == headerSize+4+count*triangleSize {
+			return false
+		}
+	}
+	return bytes.Contains(trimmed, []byte("facet")) || bytes.Contains(trimmed, []byte("endsolid"))
+}
+
+// parseASCII reads the facets of an ASCII STL.
+func parseASCII(data []byte) (File, error) {
+	f := File{ASCII: true}
+	if line, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n"); len(line) > len("solid") {
+		f.Header = strings.TrimSpace(line[len("solid"):])
+	}
+
+	fields := strings.Fields(string(data))
+	vector := func(i int) (types.Point3D, error) {
+		if i+3 > len(fields) {
+			return types.Point3D{}, errors.New(errors.ValidationError, "ASCII STL ends inside a vector", nil)
+		}
+		var v [3]float64
+		for j := range v {
+			var err error
+			if v[j], err = strconv.ParseFloat(fields[i+j], 64); err != nil {
+				return types.Point3D{}, errors.New(errors.ValidationError, "invalid number in ASCII STL", err)
+			}
+		}
+		return types.Point3D{X: v[0], Y: v[1], Z: v[2]}, nil
+	}
+
+	var normal types.Point3D
+	var vertices []types.Point3D
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "normal":
+			v, err := vector(i + 1)
+			if err != nil {
+				return File{}, err
+			}
+			normal, vertices = v, vertices[:0]
+			i += 3
+		case "vertex":
+			v, err := vector(i + 1)
+			if err != nil {
+				return File{}, err
+			}
+			vertices = append(vertices, v)
+			i += 3
+		case "endfacet":
+			if len(vertices) != 3 {
+				return File{}, errors.New(errors.ValidationError, "ASCII STL facet does not have three vertices", nil)
+			}
+			f.Triangles = append(f.Triangles, types.Triangle{Normal: normal, V1: vertices[0], V2: vertices[1], V3: vertices[2]})
+			vertices = vertices[:0]
+		}
+	}
+	f.Declared = len(f.Triangles)
+	return f, nil
+}
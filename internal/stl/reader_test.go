@@ -0,0 +1,103 @@
+package stl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestReadSTLBinary(t *testing.T) {
+	triangles := []types.Triangle{
+		{Normal: types.Point3D{Z: 1}, V1: types.Point3D{X: 0, Y: 0}, V2: types.Point3D{X: 1.5, Y: 0}, V3: types.Point3D{X: 0, Y: 2.25}},
+		{Normal: types.Point3D{X: -1}, V1: types.Point3D{Z: 3}, V2: types.Point3D{Y: -4, Z: 3}, V3: types.Point3D{Y: -4}},
+	}
+	path := filepath.Join(t.TempDir(), "model.stl")
+	if err := WriteSTLBinary(path, triangles); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadSTLBinary(path)
+	if err != nil {
+		t.Fatalf("ReadSTLBinary() error = %v", err)
+	}
+	if len(got) != len(triangles) {
+		t.Fatalf("read %d triangles, want %d", len(got), len(triangles))
+	}
+	for i := range triangles {
+		if got[i] != triangles[i] {
+			t.Errorf("triangle %d = %+v, want %+v", i, got[i], triangles[i])
+		}
+	}
+
+	ascii := filepath.Join(t.TempDir(), "ascii.stl")
+	if err := os.WriteFile(ascii, []byte("solid model\nfacet normal 0 0 1\nendsolid model\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSTLBinary(ascii); err == nil {
+		t.Error("ReadSTLBinary() expected error for an ASCII STL")
+	}
+	if _, err := ReadSTLBinary(filepath.Join(t.TempDir(), "missing.stl")); err == nil {
+		t.Error("ReadSTLBinary() expected error for a missing file")
+	}
+}
+
+func TestReadSTL(t *testing.T) {
+	dir := t.TempDir()
+	ascii := filepath.Join(dir, "ascii.stl")
+	content := `solid cube face
+  facet normal 0 0 -1
+    outer loop
+      vertex 0 0 0
+      vertex 0 1 0
+      vertex 1 1e0 0
+    endloop
+  endfacet
+endsolid cube face
+`
+	if err := os.WriteFile(ascii, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadSTL(ascii)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	want := types.Triangle{Normal: types.Point3D{Z: -1}, V2: types.Point3D{Y: 1}, V3: types.Point3D{X: 1, Y: 1}}
+	if !f.ASCII || f.Header != "cube face" || f.Declared != 1 || len(f.Triangles) != 1 || f.Triangles[0] != want {
+		t.Errorf("ReadSTL() = %+v", f)
+	}
+	if model := f.Model(); model.TriangleCount() != 1 || model.Metadata["header"] != "cube face" {
+		t.Errorf("Model() = %+v", model)
+	}
+
+	broken := filepath.Join(dir, "broken.stl")
+	if err := os.WriteFile(broken, []byte("solid x\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nendloop\nendfacet\nendsolid x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSTL(broken); err == nil {
+		t.Error("ReadSTL() expected error for a facet with one vertex")
+	}
+
+	// A binary file cut short keeps its whole triangles and is marked truncated.
+	binary := filepath.Join(dir, "binary.stl")
+	if err := WriteSTLBinary(binary, []types.Triangle{want, want, want}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binary, data[:len(data)-triangleSize-10], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = ReadSTL(binary); err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	if f.ASCII || !f.Truncated || f.Declared != 3 || len(f.Triangles) != 1 {
+		t.Errorf("ReadSTL() truncated = %v, declared %d, read %d triangles", f.Truncated, f.Declared, len(f.Triangles))
+	}
+	if _, err := ReadSTLBinary(binary); err == nil {
+		t.Error("ReadSTLBinary() expected error for a truncated file")
+	}
+}
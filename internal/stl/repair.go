@@ -0,0 +1,134 @@
+package stl
+
+import (
+	"slices"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// RepairStats counts the changes RepairMesh makes.
+type RepairStats struct {
+	Degenerate int // Triangles with no area, removed
+	Duplicates int // Triangles repeating another's vertices, removed
+	Reoriented int // Triangles whose winding was reversed to agree with their neighbours
+	Normals    int // Stored normals replaced because they disagreed with the winding
+}
+
+// RepairMesh removes degenerate and duplicate triangles, makes the winding of triangles
+// sharing an edge agree, turns each closed shell's faces outward and recomputes every
+// normal from its winding. Triangles are walked across edges joining exactly two of them,
+// so shells touching along an edge are oriented separately; the touching edges themselves
+// are left as they are, as removing them would need the shells merged.
+func RepairMesh(triangles []types.Triangle) ([]types.Triangle, RepairStats) {
+	var stats RepairStats
+	repaired := make([]types.Triangle, 0, len(triangles))
+	seen := make(map[[3]types.Point3D]struct{}, len(triangles))
+	for _, tri := range triangles {
+		if _, ok := faceNormal(tri); !ok {
+			stats.Degenerate++
+			continue
+		}
+		key := [3]types.Point3D{tri.V1, tri.V2, tri.V3}
+		slices.SortFunc(key[:], func(a, b types.Point3D) int { return comparePoints(&a, &b) })
+		if _, ok := seen[key]; ok {
+			stats.Duplicates++
+			continue
+		}
+		seen[key] = struct{}{}
+		repaired = append(repaired, tri)
+	}
+
+	// Link each triangle to the neighbours it shares a manifold edge with, noting whether
+	// they run along the edge in the same direction and so disagree.
+	type link struct {
+		face     int
+		disagree bool
+	}
+	links := make([][]link, len(repaired))
+	edges := make([]meshEdge, 0, 3*len(repaired))
+	for i, tri := range repaired {
+		edges = appendEdges(edges, tri, i)
+	}
+	forEachSharedEdge(edges, func(shared []meshEdge) {
+		if len(shared) == 2 {
+			a, b := shared[0], shared[1]
+			disagree := a.forward == b.forward
+			links[a.face] = append(links[a.face], link{b.face, disagree})
+			links[b.face] = append(links[b.face], link{a.face, disagree})
+		}
+	})
+
+	// Walk each shell from its first triangle, flipping neighbours to agree with it. If the
+	// shell then closes up, flip it whole should it enclose a negative volume; open shells
+	// have no outside to face.
+	flip := make([]bool, len(repaired))
+	visited := make([]bool, len(repaired))
+	for seed := range repaired {
+		if visited[seed] {
+			continue
+		}
+		shell := []int{seed}
+		visited[seed] = true
+		for next := 0; next < len(shell); next++ {
+			face := shell[next]
+			for _, l := range links[face] {
+				if !visited[l.face] {
+					visited[l.face] = true
+					flip[l.face] = flip[face] != l.disagree
+					shell = append(shell, l.face)
+				}
+			}
+		}
+
+		edges = edges[:0]
+		for _, face := range shell {
+			edges = appendEdges(edges, oriented(repaired[face], flip[face]), face)
+		}
+		closed := true
+		forEachSharedEdge(edges, func(shared []meshEdge) {
+			closed = closed && balanced(shared)
+		})
+		if closed {
+			volume := 0.0
+			for _, face := range shell {
+				volume += tetrahedronVolume(oriented(repaired[face], flip[face]))
+			}
+			if volume < 0 {
+				for _, face := range shell {
+					flip[face] = !flip[face]
+				}
+			}
+		}
+	}
+
+	for i, tri := range repaired {
+		if flip[i] {
+			stats.Reoriented++
+		}
+		tri = oriented(tri, flip[i])
+		normal, _ := faceNormal(tri)
+		if normal.X*tri.Normal.X+normal.Y*tri.Normal.Y+normal.Z*tri.Normal.Z < 0.999 {
+			stats.Normals++
+		}
+		tri.Normal = normal
+		repaired[i] = tri
+	}
+	return repaired, stats
+}
+
+// oriented returns tri with its winding reversed if flip is set.
+func oriented(tri types.Triangle, flip bool) types.Triangle {
+	if flip {
+		tri.V2, tri.V3 = tri.V3, tri.V2
+	}
+	return tri
+}
+
+// tetrahedronVolume returns the signed volume of the tetrahedron between the origin and
+// tri, positive if tri faces away from the origin. Summed over a closed shell, it gives
+// the volume the shell encloses, negative if its faces point inward.
+func tetrahedronVolume(tri types.Triangle) float64 {
+	return (tri.V1.X*(tri.V2.Y*tri.V3.Z-tri.V2.Z*tri.V3.Y) +
+		tri.V1.Y*(tri.V2.Z*tri.V3.X-tri.V2.X*tri.V3.Z) +
+		tri.V1.Z*(tri.V2.X*tri.V3.Y-tri.V2.Y*tri.V3.X)) / 6
+}
@@ -0,0 +1,77 @@
+package stl
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestRepairMesh(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corner, err := geometry.CreateCube(2, 3, 0, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reversed turns triangles inside out, normals included.
+	reversed := func(triangles []types.Triangle) []types.Triangle {
+		out := make([]types.Triangle, len(triangles))
+		for i, tri := range triangles {
+			n := types.Point3D{X: -tri.Normal.X, Y: -tri.Normal.Y, Z: -tri.Normal.Z}
+			out[i] = types.Triangle{Normal: n, V1: tri.V1, V2: tri.V3, V3: tri.V2}
+		}
+		return out
+	}
+
+	// One face wound backwards, a repeat of another, and a sliver with no area.
+	damaged := append([]types.Triangle(nil), cube...)
+	damaged[5] = reversed(damaged[5:6])[0]
+	damaged = append(damaged, reversed(cube[:1])[0], types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{X: 2}, V3: types.Point3D{X: 3}})
+
+	tests := []struct {
+		name      string
+		triangles []types.Triangle
+		want      RepairStats
+		count     int
+	}{
+		{"clean cube", cube, RepairStats{}, 12},
+		{"damaged cube", damaged, RepairStats{Degenerate: 1, Duplicates: 1, Reoriented: 1, Normals: 1}, 12},
+		{"inside out", reversed(cube), RepairStats{Reoriented: 12, Normals: 12}, 12},
+		{"touching cubes", append(reversed(cube), corner...), RepairStats{Reoriented: 12, Normals: 12}, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repaired, stats := RepairMesh(tt.triangles)
+			if stats != tt.want {
+				t.Errorf("RepairMesh() stats = %+v, want %+v", stats, tt.want)
+			}
+			if len(repaired) != tt.count {
+				t.Errorf("RepairMesh() kept %d triangles, want %d", len(repaired), tt.count)
+			}
+			if report := ValidateMesh(repaired); !report.OK() {
+				t.Errorf("repaired mesh fails validation: %+v", report)
+			}
+			volume := 0.0
+			for _, tri := range repaired {
+				volume += tetrahedronVolume(tri)
+			}
+			if got := volume; got <= 0 {
+				t.Errorf("repaired mesh encloses volume %v, want it positive", got)
+			}
+		})
+	}
+
+	// An open shell is made consistent but has no outside to turn towards.
+	open := append([]types.Triangle(nil), cube[2:]...)
+	open[0] = reversed(open[:1])[0]
+	repaired, stats := RepairMesh(open)
+	if stats.Reoriented != 1 && stats.Reoriented != len(open)-1 {
+		t.Errorf("RepairMesh() reoriented %d triangles of the open shell", stats.Reoriented)
+	}
+	if report := ValidateMesh(repaired); report.OpenEdges != 4 || report.FlippedNormals != 0 {
+		t.Errorf("repaired open shell = %+v, want only the hole's 4 open edges", report)
+	}
+}
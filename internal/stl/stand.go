@@ -0,0 +1,38 @@
+package stl
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// GenerateStand writes an angled display stand sized for a model of the given contribution
+// rows ([row][week][day]), as arranged by ArrangeContributions.
+// The stand's slot matches the base thickness so the printed skyline can be shown upright.
+func GenerateStand(contributions [][][]types.ContributionDay, outputPath string) error {
+	log := exportLog
+
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "stand path cannot be empty", nil)
+	}
+
+	dims, err := calculateGridDimensions(geometry.GridWeeks(contributions), len(contributions))
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate dimensions")
+	}
+
+	triangles, err := geometry.CreateStand(dims.innerWidth, dims.innerDepth, geometry.BaseHeight)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate stand geometry")
+	}
+
+	sortTriangles(triangles)
+	if err := WriteSTLBinary(outputPath, triangles); err != nil {
+		return errors.Wrap(err, "failed to write stand STL file")
+	}
+
+	if err := log.Info("Stand written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package stl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestGenerateStand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stand.stl")
+	if err := GenerateStand(make([][][]types.ContributionDay, 2), path); err != nil {
+		t.Fatalf("GenerateStand() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stand file not written: %v", err)
+	}
+	// 80-byte header, 4-byte count and 50 bytes for each of the 36 triangles.
+	if want := int64(84 + 36*50); info.Size() != want {
+		t.Errorf("stand file size = %d, want %d", info.Size(), want)
+	}
+
+	if err := GenerateStand(nil, path); err == nil {
+		t.Error("expected error for zero years")
+	}
+	if err := GenerateStand(make([][][]types.ContributionDay, 1), ""); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
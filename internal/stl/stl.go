@@ -16,12 +16,14 @@ package stl
 
 import (
 	"bufio"
+	"crypto/sha256"
 	"encoding/binary"
+	"hash"
+	"io"
 	"math"
 	"os"
 
 	"github.com/github/gh-skyline/internal/errors"
-	"github.com/github/gh-skyline/internal/logger"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -65,7 +67,7 @@ func (w *bufferWriter) writePoint3D(p types.Point3DFloat32) {
 // writeSTLHeader writes the 80-byte header to the STL file.
 // The header typically contains version or generator information.
 func writeSTLHeader(writer *bufio.Writer) error {
-	header := make([]byte, 80)
+	header := make([]byte, headerSize)
 	copy(header, []byte("Generated by GitHub Contributions Skyline Generator"))
 	if _, err := writer.Write(header); err != nil {
 		return errors.New(errors.IOError, "failed to write STL header", err)
@@ -84,8 +86,8 @@ func writeTriangleCount(writer *bufio.Writer, count uint32) error {
 
 // writeTrianglesData writes all triangles to the STL file using a pre-allocated buffer.
 // Reports progress every 10000 triangles via the logger.
-func writeTrianglesData(writer *bufio.Writer, triangles []types.Triangle) error {
-	log := logger.GetLogger()
+func writeTrianglesData(writer io.Writer, triangles []types.Triangle) error {
+	log := exportLog
 	triangleBuffer := make([]byte, triangleSize)
 
 	for i, triangle := range triangles {
@@ -117,7 +119,7 @@ func writeTrianglesData(writer *bufio.Writer, triangles []types.Triangle) error
 //   - Vertex 2: 3 x float32 (12 bytes)
 //   - Vertex 3: 3 x float32 (12 bytes)
 //   - Attribute byte count: uint16 (2 bytes, usually 0)
-func WriteSTLBinary(filename string, triangles []types.Triangle) error {
+func WriteSTLBinary(filename string, triangles []types.Triangle) (err error) {
 	if filename == "" {
 		return errors.New(errors.ValidationError, "STL filename cannot be empty", nil)
 	}
@@ -127,40 +129,53 @@ func WriteSTLBinary(filename string, triangles []types.Triangle) error {
 		return errors.New(errors.IOError, "failed to create STL file", err)
 	}
 	defer func() {
-		if cerr := file.Close(); cerr != nil {
+		if cerr := file.Close(); cerr != nil && err == nil {
 			err = errors.New(errors.IOError, "failed to close STL file", cerr)
 		}
 	}()
+	return EncodeBinary(file, triangles)
+}
 
-	writer := bufio.NewWriterSize(file, bufferSize)
-	defer func() {
-		if ferr := writer.Flush(); ferr != nil {
-			err = errors.New(errors.IOError, "failed to flush writer", ferr)
-		}
-	}()
-
-	if err := writeSTLHeader(writer); err != nil {
-		return err
-	}
-
+// EncodeBinary writes triangles to w as a binary STL with the generic header, in the
+// layout described by WriteSTLBinary.
+func EncodeBinary(w io.Writer, triangles []types.Triangle) error {
 	triangleCount := uint64(len(triangles))
 	if triangleCount > maxTriangleCount {
 		return errors.New(errors.ValidationError, "triangle count exceeds valid range for STL format", nil)
 	}
 
+	writer := bufio.NewWriterSize(w, bufferSize)
+	if err := writeSTLHeader(writer); err != nil {
+		return err
+	}
 	// Now safely convert to uint32 since we know it's in range
-	triangleCountUint32 := uint32(triangleCount)
-	if err := writeTriangleCount(writer, triangleCountUint32); err != nil {
+	if err := writeTriangleCount(writer, uint32(triangleCount)); err != nil {
 		return err
 	}
-
 	if err := writeTrianglesData(writer, triangles); err != nil {
 		return err
 	}
-
+	if err := writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush writer", err)
+	}
 	return nil
 }
 
+// WriteSTLBinaryWithMetadata is WriteSTLBinary with meta, and a hash of the triangle data,
+// recorded in the header instead of the generic generator name.
+func WriteSTLBinaryWithMetadata(filename string, triangles []types.Triangle, meta Metadata) (err error) {
+	stream, err := createSTLStream(filename, &meta)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := stream.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	return stream.Write(triangles)
+}
+
 // writeTriangleToBuffer writes a triangle using an optimized buffer writer
 func writeTriangleToBuffer(buffer []byte, t types.TriangleFloat32) error {
 	if len(buffer) < triangleSize {
@@ -176,3 +191,90 @@ func writeTriangleToBuffer(buffer []byte, t types.TriangleFloat32) error {
 
 	return nil
 }
+
+// stlStream writes triangles to a binary STL file incrementally, so a model can be
+// written component by component without holding every triangle in memory.
+// The triangle count is written as a placeholder and patched when the stream is closed,
+// as is the header when the stream records metadata.
+type stlStream struct {
+	file   *os.File
+	writer *bufio.Writer
+	count  uint64
+	meta   *Metadata // Metadata for the header; nil keeps the generic header
+	hash   hash.Hash // Hash of the triangle data written so far
+	err    error     // First failed write; Close then removes the incomplete file
+}
+
+// createSTLStream creates the STL file and writes its header and a placeholder triangle count.
+// A nil meta keeps the generic header.
+func createSTLStream(filename string, meta *Metadata) (*stlStream, error) {
+	if filename == "" {
+		return nil, errors.New(errors.ValidationError, "STL filename cannot be empty", nil)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to create STL file", err)
+	}
+
+	stream := &stlStream{file: file, writer: bufio.NewWriterSize(file, bufferSize), meta: meta, hash: sha256.New()}
+	if err := writeSTLHeader(stream.writer); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := writeTriangleCount(stream.writer, 0); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Write appends triangles to the stream. Once a write fails, the stream keeps returning
+// that error.
+func (s *stlStream) Write(triangles []types.Triangle) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.count+uint64(len(triangles)) > maxTriangleCount {
+		s.err = errors.New(errors.ValidationError, "triangle count exceeds valid range for STL format", nil)
+		return s.err
+	}
+	if err := writeTrianglesData(io.MultiWriter(s.writer, s.hash), triangles); err != nil {
+		s.err = err
+		return err
+	}
+	s.count += uint64(len(triangles))
+	return nil
+}
+
+// Close flushes buffered triangles, patches the triangle count in the header and closes the file.
+// After a failed write, the incomplete file is closed and removed instead, and the write's
+// error returned.
+func (s *stlStream) Close() (err error) {
+	if s.err != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+		return s.err
+	}
+	defer func() {
+		if cerr := s.file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close STL file", cerr)
+		}
+	}()
+
+	if err := s.writer.Flush(); err != nil {
+		return errors.New(errors.IOError, "failed to flush writer", err)
+	}
+
+	count := make([]byte, 4)
+	binary.LittleEndian.PutUint32(count, uint32(s.count))
+	if _, err := s.file.WriteAt(count, headerSize); err != nil {
+		return errors.New(errors.IOError, "failed to write triangle count", err)
+	}
+	if s.meta != nil {
+		if _, err := s.file.WriteAt(s.meta.header(s.hash.Sum(nil)), 0); err != nil {
+			return errors.New(errors.IOError, "failed to write STL header", err)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,13 @@
 package stl
 
 import (
+	"bytes"
 	"encoding/binary"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -147,3 +149,94 @@ func TestWriteSTLBinary(t *testing.T) {
 	t.Run("handle empty triangle list", testEmptyTriangleList)
 	t.Run("handle nil triangle list", testNilTriangleList)
 }
+
+func TestEncodeBinary(t *testing.T) {
+	triangle := types.Triangle{
+		Normal: types.Point3D{X: 0, Y: 0, Z: 1},
+		V1:     types.Point3D{X: 0, Y: 0, Z: 0},
+		V2:     types.Point3D{X: 1, Y: 0, Z: 0},
+		V3:     types.Point3D{X: 0, Y: 1, Z: 0},
+	}
+	var buf bytes.Buffer
+	if err := EncodeBinary(&buf, []types.Triangle{triangle, triangle}); err != nil {
+		t.Fatalf("EncodeBinary() error = %v", err)
+	}
+	if want := 84 + 2*triangleSize; buf.Len() != want {
+		t.Fatalf("encoded size = %d, want %d", buf.Len(), want)
+	}
+	if got := binary.LittleEndian.Uint32(buf.Bytes()[80:]); got != 2 {
+		t.Errorf("triangle count = %d, want 2", got)
+	}
+}
+
+func TestSTLStream(t *testing.T) {
+	testFilePath := filepath.Join(t.TempDir(), "stream.stl")
+	triangle := types.Triangle{
+		Normal: types.Point3D{X: 0, Y: 0, Z: 1},
+		V1:     types.Point3D{X: 0, Y: 0, Z: 0},
+		V2:     types.Point3D{X: 1, Y: 0, Z: 0},
+		V3:     types.Point3D{X: 0, Y: 1, Z: 0},
+	}
+
+	stream, err := createSTLStream(testFilePath, nil)
+	if err != nil {
+		t.Fatalf("createSTLStream() error = %v", err)
+	}
+	for _, chunk := range [][]types.Triangle{{triangle}, {}, {triangle, triangle}} {
+		if err := stream.Write(chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	stlFile, err := os.Open(testFilePath)
+	if err != nil {
+		t.Fatalf("Cannot open generated STL file: %v", err)
+	}
+	defer func() { _ = stlFile.Close() }()
+
+	verifySTLHeader(t, stlFile)
+	verifyTriangleCount(t, stlFile, 3)
+
+	info, err := stlFile.Stat()
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if want := int64(84 + 3*triangleSize); info.Size() != want {
+		t.Errorf("file size = %d, want %d", info.Size(), want)
+	}
+
+	if _, err := createSTLStream("", nil); err == nil {
+		t.Error("createSTLStream(\"\") expected error, got nil")
+	}
+}
+
+func TestSTLStreamFailedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.stl")
+	stream, err := createSTLStream(path, &Metadata{User: "mona"})
+	if err != nil {
+		t.Fatalf("createSTLStream() error = %v", err)
+	}
+
+	// A write past the format's limit is rejected without counting its triangles.
+	stream.count = maxTriangleCount
+	if err := stream.Write([]types.Triangle{{}}); errors.ExitCode(err) != errors.ExitValidation {
+		t.Fatalf("Write() error = %v, want a validation error", err)
+	}
+	if stream.count != maxTriangleCount {
+		t.Errorf("count = %d after a failed write, want %d", stream.count, uint64(maxTriangleCount))
+	}
+	if err := stream.Write(nil); err == nil {
+		t.Error("Write() after a failed write expected the same error")
+	}
+
+	// The incomplete file is removed rather than finished with a header describing it.
+	if err := stream.Close(); err == nil {
+		t.Error("Close() after a failed write expected an error")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("incomplete STL file left behind: %v", err)
+	}
+}
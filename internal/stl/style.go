@@ -0,0 +1,52 @@
+package stl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style selects how the contribution grid is shaped above the base.
+type Style int
+
+// Supported styles.
+const (
+	StyleTowers     Style = iota // One column per day
+	StyleSmooth                  // A continuous surface interpolated through the column heights
+	StyleBricks                  // Columns of stacked, studded modules on a base that clips onto baseplates
+	StylePenholder               // Columns standing out from the wall of a hollow cylinder
+	StyleLithophane              // A thin panel whose thickness follows the contribution heatmap
+	StylePlaque                  // Low-relief columns on a plate with keyhole slots for hanging
+	StyleSilhouette              // A thin plate cut to the front profile of the skyline
+	StyleClock                   // A tower per hour of the day, as tall as the commits authored in it
+)
+
+// ParseStyle converts a flag value ("towers", "smooth", "bricks", "penholder", "lithophane",
+// "plaque", "silhouette" or "clock") into a Style.
+func ParseStyle(name string) (Style, error) {
+	switch strings.ToLower(name) {
+	case "", "towers":
+		return StyleTowers, nil
+	case "smooth":
+		return StyleSmooth, nil
+	case "bricks":
+		return StyleBricks, nil
+	case "penholder":
+		return StylePenholder, nil
+	case "lithophane":
+		return StyleLithophane, nil
+	case "plaque":
+		return StylePlaque, nil
+	case "silhouette":
+		return StyleSilhouette, nil
+	case "clock":
+		return StyleClock, nil
+	default:
+		return StyleTowers, fmt.Errorf("unknown style %q (expected towers, smooth, bricks, penholder, lithophane, plaque, silhouette or clock)", name)
+	}
+}
+
+// ReplacesBase reports whether the style builds its own body instead of the base, so the
+// model carries no text, logo or other decoration of the base.
+func (s Style) ReplacesBase() bool {
+	return s == StylePenholder || s == StyleLithophane || s == StylePlaque || s == StyleSilhouette
+}
@@ -0,0 +1,236 @@
+package stl
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Style
+		wantErr bool
+	}{
+		{"", StyleTowers, false},
+		{"towers", StyleTowers, false},
+		{"Smooth", StyleSmooth, false},
+		{"bricks", StyleBricks, false},
+		{"PenHolder", StylePenholder, false},
+		{"lithophane", StyleLithophane, false},
+		{"plaque", StylePlaque, false},
+		{"Silhouette", StyleSilhouette, false},
+		{"clock", StyleClock, false},
+		{"voxel", StyleTowers, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStyle(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStyle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSmoothStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	rows := ArrangeContributions(contributions, LayoutStacked)
+
+	triangles, err := columnsForYear(rows, 0, findMaxContributionsAcrossYears(rows), modelDimensions{}, Options{Style: StyleSmooth})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if want := geometry.SurfaceTriangleCount(len(rows[0])); len(triangles) != want {
+		t.Errorf("smooth year has %d triangles, want %d", len(triangles), want)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "smooth.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2023, 2024, Options{Style: StyleSmooth}); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate := EstimateModelWithOptions(contributions, "testuser", 2023, 2024, Options{Style: StyleSmooth})
+	if estimate.FileSize < uint64(info.Size())/2 {
+		t.Errorf("estimated %d bytes for a %d byte smooth model", estimate.FileSize, info.Size())
+	}
+}
+
+func TestGenerateBrickStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	towersPath := filepath.Join(t.TempDir(), "towers.stl")
+	bricksPath := filepath.Join(t.TempDir(), "bricks.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, towersPath, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateSTLRangeWithOptions(contributions, bricksPath, "testuser", 2024, 2024, Options{Style: StyleBricks}); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+
+	towers, err := os.Stat(towersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bricks, err := os.Stat(bricksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Studs, grooves and the socketed base all add triangles.
+	if bricks.Size() <= towers.Size() {
+		t.Errorf("brick model is %d bytes, want more than the %d byte tower model", bricks.Size(), towers.Size())
+	}
+}
+
+func TestGeneratePenholderStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	rows := ArrangeContributions(contributions, LayoutStacked)
+	dims, err := calculateGridDimensions(geometry.GridWeeks(rows), len(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dims = penHolderDimensions(dims)
+	if dims.innerDepth != geometry.PenHolderMinHeight {
+		t.Errorf("holder height = %v, want %v", dims.innerDepth, geometry.PenHolderMinHeight)
+	}
+
+	triangles, err := columnsForYear(rows, 0, findMaxContributionsAcrossYears(rows), dims, Options{Style: StylePenholder})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	if len(triangles) == 0 {
+		t.Fatal("pen holder has no columns")
+	}
+	radius := geometry.PenHolderRadius(dims.innerWidth)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			// Column bases sit on the flat sides of the holder, just inside its radius.
+			if r := math.Hypot(v.X, v.Y); r < radius*math.Cos(geometry.CellSize/radius)-1e-6 || r > radius+geometry.MaxHeight*geometry.PenHolderRelief+1e-6 {
+				t.Fatalf("vertex %v at radius %v, outside the holder's wall", v, r)
+			}
+			if v.Z < 0 || v.Z > dims.innerDepth {
+				t.Fatalf("vertex %v above or below the holder", v)
+			}
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "penholder.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2024, 2024, Options{Style: StylePenholder}); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without text and logo the holder is far smaller than the flat model's estimate.
+	flat := EstimateModel(contributions, "testuser", 2024, 2024)
+	if uint64(info.Size()) >= flat.FileSize {
+		t.Errorf("pen holder is %d bytes, want less than the %d bytes of a flat model", info.Size(), flat.FileSize)
+	}
+}
+
+func TestGenerateLithophaneStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	outputPath := filepath.Join(t.TempDir(), "lithophane.stl")
+	opts := Options{Style: StyleLithophane}
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The panel is the whole model; the estimate only adds a base's worth of triangles.
+	estimate := EstimateModelWithOptions(contributions, "testuser", 2023, 2024, opts)
+	if want := estimate.FileSize - trianglesPerColumn*triangleSize; uint64(info.Size()) != want {
+		t.Errorf("lithophane is %d bytes, want %d", info.Size(), want)
+	}
+}
+
+func TestGeneratePlaqueStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	rows := ArrangeContributions(contributions, LayoutStacked)
+	triangles, err := columnsForYear(rows, 0, findMaxContributionsAcrossYears(rows), modelDimensions{}, Options{Style: StylePlaque})
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	peak := 0.0
+	for _, tri := range triangles {
+		peak = math.Max(peak, math.Max(tri.V1.Z, math.Max(tri.V2.Z, tri.V3.Z)))
+	}
+	if want := geometry.MaxHeight * geometry.PlaqueRelief; math.Abs(peak-want) > 1e-9 {
+		t.Errorf("tallest relief = %v, want %v", peak, want)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "plaque.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2024, 2024, Options{Style: StylePlaque}); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+}
+
+func TestGenerateSilhouetteStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	outputPath := filepath.Join(t.TempDir(), "silhouette.stl")
+	opts := Options{Style: StyleSilhouette, Mirror: true}
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2023, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	triangles, err := ReadSTLBinary(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The plate is the whole model, lying flat and as wide as the base would be.
+	estimate := EstimateModelWithOptions(contributions, "testuser", 2023, 2024, opts)
+	if want := estimate.Triangles - trianglesPerColumn; len(triangles) != want {
+		t.Errorf("silhouette has %d triangles, want %d", len(triangles), want)
+	}
+	width, _ := geometry.CalculateMultiYearDimensions(2)
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			if v.Z < 0 || v.Z > geometry.SilhouetteThickness || v.X < 0 || v.X > width+1e-9 {
+				t.Fatalf("vertex %v lies outside the %g mm wide plate", v, width)
+			}
+		}
+	}
+}
+
+func TestGenerateClockStyle(t *testing.T) {
+	contributions := [][][]types.ContributionDay{types.ClockRow([24]int{8: 3, 9: 7, 14: 5, 22: 1})}
+	opts := Options{Style: StyleClock, YearLabels: YearLabelsSide, Highlight: map[string]bool{"": true}}
+
+	triangles, err := columnsForYear(contributions, 0, 7, modelDimensions{}, opts)
+	if err != nil {
+		t.Fatalf("columnsForYear() error = %v", err)
+	}
+	// A tower per active hour, uncapped even when the undated days match a highlight.
+	if want := 4 * geometry.TowerTriangles; len(triangles) != want {
+		t.Errorf("clock has %d column triangles, want %d", len(triangles), want)
+	}
+	if opts.highlighted() || opts.yearLabelled() || opts.monthLabelled() {
+		t.Error("a clock should have no highlight caps, year labels or month labels")
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "clock.stl")
+	if err := GenerateSTLRangeWithOptions(contributions, outputPath, "testuser", 2024, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+	}
+	written, err := ReadSTLBinary(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate := EstimateModelWithOptions(contributions, "testuser", 2024, 2024, opts); estimate.Triangles < len(written) {
+		t.Errorf("estimated %d triangles for a %d triangle clock", estimate.Triangles, len(written))
+	}
+}
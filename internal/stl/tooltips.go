@@ -0,0 +1,110 @@
+package stl
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// Tower is a day's column of the model with the box it occupies, in millimeters in the
+// model's coordinates, so a viewer can tell which day is under the pointer. The box
+// covers the column up to its flat top; caps stand within its footprint.
+type Tower struct {
+	Date  string     `json:"date"`
+	Count int        `json:"count"`
+	Min   [3]float64 `json:"min"`
+	Max   [3]float64 `json:"max"`
+}
+
+// Tooltips is the sidecar written next to a model for viewers that show hover tooltips.
+type Tooltips struct {
+	User   string  `json:"user"`
+	Range  string  `json:"range"`
+	Towers []Tower `json:"towers"`
+}
+
+// Towers returns the day columns of the model, in row, week and day order. The columns
+// are placed from model, the counts the geometry was built from, and labelled with the
+// counts of contributions, the real ones, which must have the same shape; they differ
+// when the counts are bucketed. Models without a column per day have no towers.
+func Towers(contributions, model [][][]types.ContributionDay, opts Options) ([]Tower, error) {
+	if len(model) == 0 {
+		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if !opts.dayColumns() {
+		return nil, errors.New(errors.ValidationError, "model has no separate day columns", nil)
+	}
+	rows, dims, maxContrib, err := modelLayout(model, opts)
+	if err != nil {
+		return nil, err
+	}
+	counts := opts.arrange(contributions)
+
+	var towers []Tower
+	for i, row := range rows {
+		yearOffset := len(rows) - 1 - i
+		for weekIdx, week := range row {
+			for dayIdx, day := range week {
+				if day.ContributionCount <= 0 {
+					continue
+				}
+				x, y := geometry.CellPosition(weekIdx, dayIdx, yearOffset)
+				column, err := geometry.CreateColumn(x, y, geometry.NormalizeContribution(day.ContributionCount, maxContrib), geometry.CellSize)
+				if err != nil {
+					return nil, err
+				}
+				if err := placeColumns(column, rows, i, dims, opts); err != nil {
+					return nil, err
+				}
+				tower := Tower{Date: day.Date, Count: counts[i][weekIdx][dayIdx].ContributionCount}
+				tower.Min, tower.Max = bounds(column)
+				towers = append(towers, tower)
+			}
+		}
+	}
+	return towers, nil
+}
+
+// bounds returns the corners of the axis-aligned box around triangles.
+func bounds(triangles []types.Triangle) (lo, hi [3]float64) {
+	lo = [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi = [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			lo = [3]float64{min(lo[0], v.X), min(lo[1], v.Y), min(lo[2], v.Z)}
+			hi = [3]float64{max(hi[0], v.X), max(hi[1], v.Y), max(hi[2], v.Z)}
+		}
+	}
+	return lo, hi
+}
+
+// GenerateTooltips writes the towers of the model to outputPath as JSON, for viewers that
+// identify the day each tower stands for. The arguments are as for Towers.
+func GenerateTooltips(contributions, model [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, opts Options) error {
+	log := exportLog
+
+	if outputPath == "" {
+		return errors.New(errors.ValidationError, "tooltips path cannot be empty", nil)
+	}
+	towers, err := Towers(contributions, model, opts)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(Tooltips{User: username, Range: utils.FormatYearRange(startYear, endYear), Towers: towers}, "", "  ")
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode tooltips", err)
+	}
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0o644); err != nil {
+		return errors.New(errors.IOError, "failed to write tooltips file", err)
+	}
+
+	if err := log.Info("Tooltips for %d towers written successfully to: %s", len(towers), outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
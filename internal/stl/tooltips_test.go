@@ -0,0 +1,92 @@
+package stl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestTowers(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	active := 0
+	for y, year := range contributions {
+		for w, week := range year {
+			for d := range week {
+				week[d].Date = fmt.Sprintf("%d-%02d-%d", 2023+y, w, d)
+				if week[d].ContributionCount > 0 {
+					active++
+				}
+			}
+		}
+	}
+	model := BucketContributions(contributions, BucketPercentile)
+	opts := Options{Mirror: true, HeightScale: 1.5}
+
+	towers, err := Towers(contributions, model, opts)
+	if err != nil {
+		t.Fatalf("Towers() error = %v", err)
+	}
+	if len(towers) != active {
+		t.Fatalf("got %d towers, want one per active day, %d", len(towers), active)
+	}
+	// Counts are the real ones, not the bucketed heights.
+	if first := towers[0]; first.Date != "2023-00-1" || first.Count != 1 {
+		t.Errorf("first tower = %+v, want 2023-00-1 with 1 contribution", first)
+	}
+
+	// Together the boxes cover exactly the columns of the model.
+	rows, dims, maxContrib, err := modelLayout(model, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var columns []types.Triangle
+	for i := range rows {
+		triangles, err := columnsForYear(rows, i, maxContrib, dims, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, triangles...)
+	}
+	wantLo, wantHi := bounds(columns)
+	lo, hi := towers[0].Min, towers[0].Max
+	for _, tower := range towers {
+		for axis := range 3 {
+			lo[axis], hi[axis] = min(lo[axis], tower.Min[axis]), max(hi[axis], tower.Max[axis])
+		}
+	}
+	for axis := range 3 {
+		if math.Abs(lo[axis]-wantLo[axis]) > 1e-9 || math.Abs(hi[axis]-wantHi[axis]) > 1e-9 {
+			t.Errorf("towers span %v to %v, want the columns' %v to %v", lo, hi, wantLo, wantHi)
+			break
+		}
+	}
+
+	if _, err := Towers(contributions, model, Options{Style: StyleSmooth}); err == nil {
+		t.Error("Towers() of a smooth surface should fail")
+	}
+}
+
+func TestGenerateTooltips(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	outputPath := filepath.Join(t.TempDir(), "towers.json")
+	if err := GenerateTooltips(contributions, contributions, outputPath, "testuser", 2024, 2024, Options{}); err != nil {
+		t.Fatalf("GenerateTooltips() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tooltips Tooltips
+	if err := json.Unmarshal(data, &tooltips); err != nil {
+		t.Fatalf("tooltips are not valid JSON: %v", err)
+	}
+	if tooltips.User != "testuser" || tooltips.Range != "2024" || len(tooltips.Towers) == 0 {
+		t.Errorf("tooltips = %s, want testuser's 2024 towers", data)
+	}
+}
@@ -0,0 +1,131 @@
+package stl
+
+import (
+	"math"
+	"slices"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Report counts the problems ValidateMesh finds in a mesh. Vertices are matched exactly,
+// as STL files store each shared corner with the same coordinates.
+type Report struct {
+	Triangles        int // Triangles checked
+	Degenerate       int // Triangles with no area
+	FlippedNormals   int // Triangles whose stored normal points against their winding
+	OpenEdges        int // Edges not run along as often in each direction: holes or flipped faces
+	NonManifoldEdges int // Edges shared by more than two triangles, where closed shells touch
+}
+
+// Watertight reports whether every edge is closed, each triangle running along it in one
+// direction met by another running back, so the mesh encloses a volume a slicer can fill.
+// Shells touching along an edge are still watertight.
+func (r Report) Watertight() bool {
+	return r.OpenEdges == 0
+}
+
+// OK reports whether the mesh is watertight with no degenerate triangles or flipped
+// normals.
+func (r Report) OK() bool {
+	return r.Watertight() && r.Degenerate == 0 && r.FlippedNormals == 0
+}
+
+// ValidateModel checks a model's placed triangles as ValidateMesh does. Components are
+// checked together, as separately closed parts may share edges where they touch.
+func ValidateModel(model *types.Model) Report {
+	return ValidateMesh(model.Triangles())
+}
+
+// meshEdge is an edge of the triangle at index face, with its vertices in sorted order;
+// forward records whether the triangle runs along it in that order.
+type meshEdge struct {
+	a, b    types.Point3D
+	face    int
+	forward bool
+}
+
+// ValidateMesh checks triangles for degenerate faces, normals that disagree with their
+// winding and edges that keep the mesh from being watertight or manifold. Degenerate
+// triangles are left out of the edge checks.
+func ValidateMesh(triangles []types.Triangle) Report {
+	r := Report{Triangles: len(triangles)}
+	edges := make([]meshEdge, 0, 3*len(triangles))
+	for i, tri := range triangles {
+		n, ok := faceNormal(tri)
+		if !ok {
+			r.Degenerate++
+			continue
+		}
+		if n.X*tri.Normal.X+n.Y*tri.Normal.Y+n.Z*tri.Normal.Z < 0 {
+			r.FlippedNormals++
+		}
+		edges = appendEdges(edges, tri, i)
+	}
+
+	forEachSharedEdge(edges, func(shared []meshEdge) {
+		if !balanced(shared) {
+			r.OpenEdges++
+		} else if len(shared) > 2 {
+			r.NonManifoldEdges++
+		}
+	})
+	return r
+}
+
+// faceNormal returns the unit normal of a triangle from its winding, or false if the
+// triangle has no area.
+func faceNormal(tri types.Triangle) (types.Point3D, bool) {
+	u := types.Point3D{X: tri.V2.X - tri.V1.X, Y: tri.V2.Y - tri.V1.Y, Z: tri.V2.Z - tri.V1.Z}
+	v := types.Point3D{X: tri.V3.X - tri.V1.X, Y: tri.V3.Y - tri.V1.Y, Z: tri.V3.Z - tri.V1.Z}
+	n := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+	length := math.Sqrt(n.X*n.X + n.Y*n.Y + n.Z*n.Z)
+	if length < 1e-12 {
+		return types.Point3D{}, false
+	}
+	return types.Point3D{X: n.X / length, Y: n.Y / length, Z: n.Z / length}, true
+}
+
+// appendEdges appends the three edges of the triangle at index face.
+func appendEdges(edges []meshEdge, tri types.Triangle, face int) []meshEdge {
+	vertices := [3]types.Point3D{tri.V1, tri.V2, tri.V3}
+	for i, a := range vertices {
+		b := vertices[(i+1)%3]
+		if comparePoints(&a, &b) < 0 {
+			edges = append(edges, meshEdge{a, b, face, true})
+		} else {
+			edges = append(edges, meshEdge{b, a, face, false})
+		}
+	}
+	return edges
+}
+
+// balanced reports whether as many of the triangles sharing an edge run along it in each
+// direction, closing the surface there.
+func balanced(shared []meshEdge) bool {
+	forward := 0
+	for _, e := range shared {
+		if e.forward {
+			forward++
+		}
+	}
+	return 2*forward == len(shared)
+}
+
+// forEachSharedEdge sorts edges and calls fn with each run of them joining the same two
+// vertices.
+func forEachSharedEdge(edges []meshEdge, fn func(shared []meshEdge)) {
+	slices.SortFunc(edges, func(x, y meshEdge) int {
+		if c := comparePoints(&x.a, &y.a); c != 0 {
+			return c
+		}
+		return comparePoints(&x.b, &y.b)
+	})
+	for start := 0; start < len(edges); {
+		end := start + 1
+		for end < len(edges) && edges[end].a == edges[start].a && edges[end].b == edges[start].b {
+			end++
+		}
+		fn(edges[start:end])
+		start = end
+	}
+}
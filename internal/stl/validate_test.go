@@ -0,0 +1,66 @@
+package stl
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestValidateMesh(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A second cube touching the first along one edge.
+	corner, err := geometry.CreateCube(2, 3, 0, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := append([]types.Triangle(nil), cube...)
+	flipped[0].V2, flipped[0].V3 = flipped[0].V3, flipped[0].V2
+	degenerate := types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{X: 2}, V3: types.Point3D{X: 3}}
+
+	tests := []struct {
+		name       string
+		triangles  []types.Triangle
+		want       Report
+		watertight bool
+		ok         bool
+	}{
+		{"cube", cube, Report{Triangles: 12}, true, true},
+		{"missing face", cube[2:], Report{Triangles: 10, OpenEdges: 4}, false, false},
+		{"flipped face", flipped, Report{Triangles: 12, FlippedNormals: 1, OpenEdges: 3}, false, false},
+		{"degenerate", append([]types.Triangle{degenerate}, cube...), Report{Triangles: 13, Degenerate: 1}, true, false},
+		{"touching cubes", append(append([]types.Triangle(nil), cube...), corner...), Report{Triangles: 24, NonManifoldEdges: 1}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateMesh(tt.triangles)
+			if got != tt.want {
+				t.Errorf("ValidateMesh() = %+v, want %+v", got, tt.want)
+			}
+			if got.Watertight() != tt.watertight || got.OK() != tt.ok {
+				t.Errorf("Watertight() = %v, OK() = %v, want %v, %v", got.Watertight(), got.OK(), tt.watertight, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cube's two halves are open on their own but close each other.
+	model := &types.Model{Components: []types.Component{{Name: "bottom", Triangles: cube[:6]}, {Name: "top", Triangles: cube[6:]}}}
+	if got := ValidateModel(model); got != (Report{Triangles: 12}) {
+		t.Errorf("ValidateModel() = %+v, want a closed cube", got)
+	}
+
+	// Components are checked where their transforms place them.
+	model.Components[1].Transform = types.Transform{Offset: types.Point3D{Z: 10}}
+	if got := ValidateModel(model); got.Watertight() {
+		t.Errorf("ValidateModel() = %+v, want open edges between moved halves", got)
+	}
+}
@@ -0,0 +1,108 @@
+package stl
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// geometryJob meshes one independent piece of the model: a whole component, or the
+// columns of a single year.
+type geometryJob struct {
+	component int  // Index of the component the job belongs to
+	last      bool // Whether the job completes its component
+	name      string
+	mesh      func() ([]types.Triangle, error)
+}
+
+// workerCount is how many geometry jobs run at once: Options.Workers, or every CPU when unset.
+func (o Options) workerCount() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// modelJobs splits the components into jobs in output order. Columns are meshed one year
+// at a time, most recent year first, so long ranges spread across the workers; every other
+// component is a single job. Each job sorts its own triangles, and snaps them when asked.
+func modelJobs(components []modelComponent, contributionsPerYear [][][]types.ContributionDay, maxContrib int, dims modelDimensions, opts Options) []geometryJob {
+	var jobs []geometryJob
+	for i, component := range components {
+		if component.name == "columns" {
+			for year := len(contributionsPerYear) - 1; year >= 0; year-- {
+				jobs = append(jobs, geometryJob{component: i, last: year == 0, name: component.name, mesh: func() ([]types.Triangle, error) {
+					return columnsForYear(contributionsPerYear, year, maxContrib, dims, opts)
+				}})
+			}
+			continue
+		}
+		jobs = append(jobs, geometryJob{component: i, last: true, name: component.name, mesh: func() ([]types.Triangle, error) {
+			ch := make(chan geometryResult, 1)
+			component.generate(ch)
+			result := <-ch
+			if result.err != nil {
+				return nil, result.err
+			}
+			sortTriangles(result.triangles)
+			return result.triangles, nil
+		}})
+	}
+	if opts.Snap > 0 {
+		for i := range jobs {
+			mesh := jobs[i].mesh
+			jobs[i].mesh = func() ([]types.Triangle, error) {
+				triangles, err := mesh()
+				if err != nil {
+					return nil, err
+				}
+				return geometry.SnapVertices(triangles, opts.Snap), nil
+			}
+		}
+	}
+	return jobs
+}
+
+// runOrdered meshes jobs on up to workers goroutines and hands each result to emit in job
+// order, so the output is the same for any number of workers. A job only starts once fewer
+// than workers results are waiting to be emitted, which bounds the triangles held at once.
+// The first error stops the run; jobs already started finish into buffered channels and exit.
+func runOrdered(jobs []geometryJob, workers int, emit func(job geometryJob, triangles []types.Triangle) error) error {
+	workers = max(workers, 1)
+	results := make([]chan geometryResult, len(jobs))
+	for i := range results {
+		results[i] = make(chan geometryResult, 1)
+	}
+
+	slots := make(chan struct{}, workers)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for i, job := range jobs {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			go func() {
+				triangles, err := job.mesh()
+				results[i] <- geometryResult{triangles: triangles, err: err}
+			}()
+		}
+	}()
+
+	for i, job := range jobs {
+		result := <-results[i]
+		<-slots
+		if result.err != nil {
+			return errors.Wrap(result.err, fmt.Sprintf("failed to generate %s geometry", job.name))
+		}
+		if err := emit(job, result.triangles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package stl
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestRunOrdered(t *testing.T) {
+	const workers = 3
+	var running, peak atomic.Int32
+	jobs := make([]geometryJob, 12)
+	for i := range jobs {
+		jobs[i] = geometryJob{component: i, name: "job", mesh: func() ([]types.Triangle, error) {
+			peak.Store(max(peak.Load(), running.Add(1)))
+			defer running.Add(-1)
+			// Later jobs finish first, so results arrive out of order.
+			time.Sleep(time.Duration(len(jobs)-i) * time.Millisecond)
+			return []types.Triangle{{Normal: types.Point3D{X: float64(i)}}}, nil
+		}}
+	}
+
+	var order []int
+	err := runOrdered(jobs, workers, func(job geometryJob, triangles []types.Triangle) error {
+		if int(triangles[0].Normal.X) != job.component {
+			t.Errorf("job %d got triangles of job %v", job.component, triangles[0].Normal.X)
+		}
+		order = append(order, job.component)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runOrdered() error = %v", err)
+	}
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("emit order = %v, want job order", order)
+		}
+	}
+	if len(order) != len(jobs) {
+		t.Errorf("emitted %d jobs, want %d", len(order), len(jobs))
+	}
+	if peak.Load() > workers {
+		t.Errorf("%d jobs ran at once, want at most %d", peak.Load(), workers)
+	}
+}
+
+func TestRunOrderedErrors(t *testing.T) {
+	failing := stderrors.New("mesh failed")
+	jobs := []geometryJob{
+		{name: "base", mesh: func() ([]types.Triangle, error) { return nil, nil }},
+		{name: "text", mesh: func() ([]types.Triangle, error) { return nil, failing }},
+		{name: "image", mesh: func() ([]types.Triangle, error) { return nil, nil }},
+	}
+
+	var emitted int
+	err := runOrdered(jobs, 2, func(geometryJob, []types.Triangle) error {
+		emitted++
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to generate text geometry") {
+		t.Errorf("runOrdered() error = %v, want text geometry failure", err)
+	}
+	if emitted != 1 {
+		t.Errorf("emitted %d jobs before the failure, want 1", emitted)
+	}
+
+	stop := stderrors.New("write failed")
+	err = runOrdered(jobs[:1], 1, func(geometryJob, []types.Triangle) error { return stop })
+	if !stderrors.Is(err, stop) {
+		t.Errorf("runOrdered() error = %v, want emit error", err)
+	}
+}
+
+func TestGenerateWorkersReproducible(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions(), createTestContributions()}
+	tempDir := t.TempDir()
+
+	var want []byte
+	for _, layout := range []Layout{LayoutStacked, LayoutStrip} {
+		for _, workers := range []int{1, 2, 8} {
+			path := filepath.Join(tempDir, "model.stl")
+			opts := Options{Workers: workers, Layout: layout}
+			if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2022, 2024, opts); err != nil {
+				t.Fatalf("workers %d: %v", workers, err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if workers == 1 {
+				want = got
+			} else if !bytes.Equal(got, want) {
+				t.Errorf("layout %d with %d workers differs from one worker", layout, workers)
+			}
+		}
+	}
+}
+
+func TestGenerateSnap(t *testing.T) {
+	contributions := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	tempDir := t.TempDir()
+
+	// Assembled and streamed models are snapped alike.
+	var models [][]types.Triangle
+	for _, memory := range []uint64{0, 1} {
+		path := filepath.Join(tempDir, fmt.Sprintf("snapped-%d.stl", memory))
+		opts := Options{Snap: 0.01}
+		if memory > 0 {
+			estimate := EstimateModelWithOptions(contributions, "testuser", 2023, 2024, opts)
+			opts.MaxMemory = estimate.StreamingBytes
+		}
+		if err := GenerateSTLRangeWithOptions(contributions, path, "testuser", 2023, 2024, opts); err != nil {
+			t.Fatalf("GenerateSTLRangeWithOptions() error = %v", err)
+		}
+		triangles, err := ReadSTLBinary(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		models = append(models, triangles)
+	}
+	if !slices.Equal(models[0], models[1]) {
+		t.Error("streamed model differs from the assembled one")
+	}
+
+	// Every vertex lies on the grid, up to the precision of the file's 32-bit floats.
+	for _, tri := range models[0] {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			for _, c := range []float64{v.X, v.Y, v.Z} {
+				if off := math.Abs(c/0.01 - math.Round(c/0.01)); off > 0.01 {
+					t.Fatalf("vertex %v is off the 0.01 mm grid", v)
+				}
+			}
+		}
+	}
+}
@@ -34,6 +34,27 @@ func GenerateContributionsResponse(username string, year int) *types.Contributio
 	return response
 }
 
+// GenerateContributionsResponseForDateRange creates a mock contributions response for
+// the days from start to end, in weeks starting on Sunday like the API's calendar
+func GenerateContributionsResponseForDateRange(username string, start, end time.Time) *types.ContributionsResponse {
+	response := &types.ContributionsResponse{}
+	response.User.Login = username
+	calendar := &response.User.ContributionsCollection.ContributionCalendar
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if len(calendar.Weeks) == 0 || day.Weekday() == time.Sunday {
+			calendar.Weeks = append(calendar.Weeks, struct {
+				ContributionDays []types.ContributionDay `json:"contributionDays"`
+			}{})
+		}
+		week := &calendar.Weeks[len(calendar.Weeks)-1]
+		count := (day.YearDay() + int(day.Weekday())) % 10
+		week.ContributionDays = append(week.ContributionDays, CreateMockContributionDay(day, count))
+		calendar.TotalContributions += count
+	}
+	return response
+}
+
 // CreateMockContributionDay creates a mock contribution day
 func CreateMockContributionDay(date time.Time, count int) types.ContributionDay {
 	return types.ContributionDay{
@@ -0,0 +1,98 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	recorded "github.com/github/gh-skyline/internal/github/fixtures"
+)
+
+// Replayer is an http.RoundTripper that answers requests from recorded fixtures
+// without touching the network. When several fixtures match a request they are
+// served in file name order, and the last one is repeated once exhausted.
+type Replayer struct {
+	mu       sync.Mutex
+	fixtures []recorded.Fixture
+	served   map[int]int
+}
+
+// NewReplayer loads every *.json fixture in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	r := &Replayer{served: map[int]int{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 -- fixture paths come from the test's own directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var fixture recorded.Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", filepath.Base(path), err)
+		}
+		r.fixtures = append(r.fixtures, fixture)
+	}
+	if len(r.fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	fr, err := recorded.ParseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var candidates []int
+	for i, fixture := range r.fixtures {
+		if fixture.Request.Matches(fr) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		vars, _ := json.Marshal(fr.Variables)
+		return nil, fmt.Errorf("no fixture recorded for %s %s with variables %s", fr.Method, fr.Operation, vars)
+	}
+
+	key := candidates[0]
+	n := min(r.served[key], len(candidates)-1)
+	r.served[key]++
+	fixture := r.fixtures[candidates[n]]
+
+	header := http.Header{}
+	for name, value := range fixture.Response.Headers {
+		header.Set(name, value)
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	body := fixture.Response.Content()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	recorded "github.com/github/gh-skyline/internal/github/fixtures"
+)
+
+func postGraphQL(t *testing.T, client *http.Client, url, query string, variables map[string]interface{}) (int, string) {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fixtures are stored indented, so compare bodies in compact form.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		t.Fatalf("response is not JSON: %s", data)
+	}
+	return resp.StatusCode, compact.String()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	page := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		page++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Github-Request-Id", "secret")
+		w.Header().Set("X-Ratelimit-Remaining", "42")
+		_, _ = w.Write([]byte(`{"data":{"page":` + string(rune('0'+page)) + `}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recording := &http.Client{Transport: recorded.NewRecorder(dir, nil)}
+	query := "query Pages($after: String) { pages }"
+
+	// The same request twice, as a paginated or polling client would send it.
+	for _, want := range []string{`{"data":{"page":1}}`, `{"data":{"page":2}}`} {
+		if _, body := postGraphQL(t, recording, server.URL, query, map[string]interface{}{"after": nil}); body != want {
+			t.Fatalf("recorded body = %s, want %s", body, want)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "Pages-*.json"))
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("expected 2 fixtures, got %v (%v)", paths, err)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("fixture should not contain unrecorded headers")
+	}
+
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	replaying := &http.Client{Transport: replayer}
+	for _, want := range []string{`{"data":{"page":1}}`, `{"data":{"page":2}}`, `{"data":{"page":2}}`} {
+		status, body := postGraphQL(t, replaying, "https://api.github.com/graphql", query, map[string]interface{}{"after": nil})
+		if status != http.StatusOK || body != want {
+			t.Errorf("replayed %d %s, want 200 %s", status, body, want)
+		}
+	}
+
+	if _, err := replaying.Post("https://api.github.com/graphql", "application/json",
+		strings.NewReader(`{"query":"query Other { x }","variables":{}}`)); err == nil {
+		t.Error("expected an error for a request without a fixture")
+	}
+}
+
+func TestNewReplayerErrors(t *testing.T) {
+	if _, err := NewReplayer(t.TempDir()); err == nil {
+		t.Error("NewReplayer() on an empty directory should fail")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayer(dir); err == nil {
+		t.Error("NewReplayer() with an invalid fixture should fail")
+	}
+}
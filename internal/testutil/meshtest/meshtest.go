@@ -0,0 +1,163 @@
+// Package meshtest pins the shape of generated models in tests. It measures geometric
+// invariants of a mesh, such as its triangle count, bounding box, volume and surface
+// area, and compares them against golden values stored in testdata, so geometry
+// refactors cannot silently change what gets printed.
+package meshtest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Update rewrites golden files with the measured invariants instead of comparing them.
+// Run the tests with -update-meshes after an intended change to the geometry.
+var Update = flag.Bool("update-meshes", false, "rewrite golden mesh invariants")
+
+// Tolerance is the relative difference allowed between measured and golden values. It
+// absorbs the last-bit differences of summing in another order or of fused
+// multiply-adds on other architectures, while any real change to the shape exceeds it.
+const Tolerance = 1e-6
+
+// Invariants are properties of a mesh that do not depend on the order of its triangles.
+type Invariants struct {
+	Triangles   int           `json:"triangles"`
+	Min         types.Point3D `json:"min"`         // Lowest corner of the bounding box
+	Max         types.Point3D `json:"max"`         // Highest corner of the bounding box
+	Volume      float64       `json:"volume"`      // Signed volume enclosed by the triangles
+	SurfaceArea float64       `json:"surfaceArea"` // Total area of the triangles
+}
+
+// Measure computes the invariants of a mesh. The volume sums the signed tetrahedra
+// between the origin and each triangle, so closed shells facing outward count positive
+// and shells that overlap are counted once each.
+func Measure(triangles []types.Triangle) Invariants {
+	inv := Invariants{Triangles: len(triangles)}
+	if len(triangles) == 0 {
+		return inv
+	}
+	inv.Min = types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	inv.Max = types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, tri := range triangles {
+		for _, v := range []types.Point3D{tri.V1, tri.V2, tri.V3} {
+			inv.Min = types.Point3D{X: min(inv.Min.X, v.X), Y: min(inv.Min.Y, v.Y), Z: min(inv.Min.Z, v.Z)}
+			inv.Max = types.Point3D{X: max(inv.Max.X, v.X), Y: max(inv.Max.Y, v.Y), Z: max(inv.Max.Z, v.Z)}
+		}
+
+		u := sub(tri.V2, tri.V1)
+		v := sub(tri.V3, tri.V1)
+		cross := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+		inv.SurfaceArea += math.Sqrt(cross.X*cross.X+cross.Y*cross.Y+cross.Z*cross.Z) / 2
+		inv.Volume += (tri.V1.X*(tri.V2.Y*tri.V3.Z-tri.V2.Z*tri.V3.Y) +
+			tri.V1.Y*(tri.V2.Z*tri.V3.X-tri.V2.X*tri.V3.Z) +
+			tri.V1.Z*(tri.V2.X*tri.V3.Y-tri.V2.Y*tri.V3.X)) / 6
+	}
+	return inv
+}
+
+// sub returns a - b.
+func sub(a, b types.Point3D) types.Point3D {
+	return types.Point3D{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+// Diff describes how inv differs from want beyond tolerance, one line per invariant;
+// it is empty when they match.
+func (inv Invariants) Diff(want Invariants, tolerance float64) []string {
+	var diffs []string
+	if inv.Triangles != want.Triangles {
+		diffs = append(diffs, fmt.Sprintf("triangles = %d, want %d", inv.Triangles, want.Triangles))
+	}
+	values := []struct {
+		name      string
+		got, want float64
+	}{
+		{"min x", inv.Min.X, want.Min.X}, {"min y", inv.Min.Y, want.Min.Y}, {"min z", inv.Min.Z, want.Min.Z},
+		{"max x", inv.Max.X, want.Max.X}, {"max y", inv.Max.Y, want.Max.Y}, {"max z", inv.Max.Z, want.Max.Z},
+		{"volume", inv.Volume, want.Volume},
+		{"surface area", inv.SurfaceArea, want.SurfaceArea},
+	}
+	for _, v := range values {
+		if math.Abs(v.got-v.want) > tolerance*max(1, math.Abs(v.want)) {
+			diffs = append(diffs, fmt.Sprintf("%s = %g, want %g", v.name, v.got, v.want))
+		}
+	}
+	return diffs
+}
+
+// Compare lists the differences between measured and golden invariants by name,
+// including models missing from either side, sorted for stable output.
+func Compare(got, want map[string]Invariants, tolerance float64) []string {
+	var diffs []string
+	for name, inv := range got {
+		golden, ok := want[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: no golden invariants", name))
+			continue
+		}
+		for _, d := range inv.Diff(golden, tolerance) {
+			diffs = append(diffs, fmt.Sprintf("%s: %s", name, d))
+		}
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: golden invariants for a model no longer measured", name))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// Check compares measured invariants against the golden file at path, failing t for
+// every difference, or rewrites the file when run with -update-meshes.
+func Check(t testing.TB, path string, got map[string]Invariants) {
+	t.Helper()
+	if *Update {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden mesh invariants (run with -update-meshes to create them): %v", err)
+	}
+	var want map[string]Invariants
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("parsing %s: %v", path, err)
+	}
+	for _, d := range Compare(got, want, Tolerance) {
+		t.Error(d)
+	}
+}
+
+// Grid returns fixed, varied contributions ([year][week][day], oldest first) of full
+// weeks, with a breakdown on every day, so golden models depend on nothing but the code
+// that generates them.
+func Grid(years, weeks int) [][][]types.ContributionDay {
+	grid := make([][][]types.ContributionDay, years)
+	for y := range grid {
+		grid[y] = make([][]types.ContributionDay, weeks)
+		for w := range grid[y] {
+			grid[y][w] = make([]types.ContributionDay, 7)
+			for d := range grid[y][w] {
+				count := (w*7 + d + y*3) % 11
+				grid[y][w][d] = types.ContributionDay{
+					ContributionCount: count,
+					Breakdown:         types.Breakdown{Commits: count / 2, PullRequests: count / 4, Issues: count - count/2 - count/4},
+				}
+			}
+		}
+	}
+	return grid
+}
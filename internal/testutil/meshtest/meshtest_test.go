@@ -0,0 +1,95 @@
+package meshtest
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// unitCube returns the 12 outward-facing triangles of the cube from the origin to (1, 1, 1).
+func unitCube() []types.Triangle {
+	p := func(x, y, z float64) types.Point3D { return types.Point3D{X: x, Y: y, Z: z} }
+	quad := func(a, b, c, d types.Point3D) []types.Triangle {
+		return []types.Triangle{{V1: a, V2: b, V3: c}, {V1: a, V2: c, V3: d}}
+	}
+	var triangles []types.Triangle
+	for _, q := range [][4]types.Point3D{
+		{p(0, 0, 0), p(0, 1, 0), p(1, 1, 0), p(1, 0, 0)}, // bottom
+		{p(0, 0, 1), p(1, 0, 1), p(1, 1, 1), p(0, 1, 1)}, // top
+		{p(0, 0, 0), p(1, 0, 0), p(1, 0, 1), p(0, 0, 1)}, // front
+		{p(0, 1, 0), p(0, 1, 1), p(1, 1, 1), p(1, 1, 0)}, // back
+		{p(0, 0, 0), p(0, 0, 1), p(0, 1, 1), p(0, 1, 0)}, // left
+		{p(1, 0, 0), p(1, 1, 0), p(1, 1, 1), p(1, 0, 1)}, // right
+	} {
+		triangles = append(triangles, quad(q[0], q[1], q[2], q[3])...)
+	}
+	return triangles
+}
+
+func TestMeasure(t *testing.T) {
+	got := Measure(unitCube())
+	if got.Triangles != 12 || got.Min != (types.Point3D{}) || got.Max != (types.Point3D{X: 1, Y: 1, Z: 1}) {
+		t.Errorf("Measure() = %+v, want 12 triangles in the unit box", got)
+	}
+	if math.Abs(got.Volume-1) > 1e-12 || math.Abs(got.SurfaceArea-6) > 1e-12 {
+		t.Errorf("volume, area = %v, %v, want 1, 6", got.Volume, got.SurfaceArea)
+	}
+
+	if empty := Measure(nil); empty != (Invariants{}) {
+		t.Errorf("Measure(nil) = %+v, want zero invariants", empty)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cube := Measure(unitCube())
+	nudged := cube
+	nudged.Volume *= 1 + Tolerance/10
+	grown := cube
+	grown.Max.Z, grown.Triangles = 2, 14
+
+	if diffs := Compare(map[string]Invariants{"cube": nudged}, map[string]Invariants{"cube": cube}, Tolerance); len(diffs) != 0 {
+		t.Errorf("Compare() within tolerance = %q, want no differences", diffs)
+	}
+
+	diffs := Compare(map[string]Invariants{"cube": grown, "new": cube}, map[string]Invariants{"cube": cube, "old": cube}, Tolerance)
+	want := []string{
+		"cube: max z = 2, want 1",
+		"cube: triangles = 14, want 12",
+		"new: no golden invariants",
+		"old: golden invariants for a model no longer measured",
+	}
+	if strings.Join(diffs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Compare() = %q, want %q", diffs, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	got := map[string]Invariants{"cube": Measure(unitCube())}
+
+	*Update = true
+	Check(t, path, got)
+	*Update = false
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+	Check(t, path, got)
+}
+
+func TestGrid(t *testing.T) {
+	grid := Grid(2, 53)
+	if len(grid) != 2 || len(grid[1]) != 53 || len(grid[1][52]) != 7 {
+		t.Fatalf("Grid(2, 53) has shape %d×%d×%d, want 2×53×7", len(grid), len(grid[1]), len(grid[1][52]))
+	}
+	for _, week := range grid[0] {
+		for _, day := range week {
+			if day.Breakdown.Total() != day.ContributionCount {
+				t.Fatalf("day %+v: breakdown does not add up to the count", day)
+			}
+		}
+	}
+}
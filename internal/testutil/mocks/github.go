@@ -2,6 +2,7 @@
 package mocks
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -16,6 +17,14 @@ type MockGitHubClient struct {
 	MockData *types.ContributionsResponse
 	Response interface{} // Generic response field for testing
 	Err      error       // Error to return if needed
+	Stars    []time.Time // Stargazer timestamps returned for any repository
+	Release  string      // Tag of the latest release returned for any repository
+	Avatar   string      // Avatar URL returned for any user
+	Commits  []time.Time // Authored times of the commits to the single repository any user committed to
+	Members  []string    // Logins of the members of any organization
+
+	// RateLimit is the budget reported by contribution queries; nil reports none.
+	RateLimit *types.RateLimit
 }
 
 // GetAuthenticatedUser implements GitHubClientInterface
@@ -49,8 +58,24 @@ func (m *MockGitHubClient) FetchContributions(username string, year int) (*types
 	return fixtures.GenerateContributionsResponse(username, year), nil
 }
 
+// FetchContributionsForDateRange implements GitHubClientInterface
+func (m *MockGitHubClient) FetchContributionsForDateRange(username string, start, end time.Time) (*types.ContributionsResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return fixtures.GenerateContributionsResponseForDateRange(username, start, end), nil
+}
+
+// FetchContributionBreakdown implements GitHubClientInterface
+func (m *MockGitHubClient) FetchContributionBreakdown(_ string, _ int) (map[string]types.Breakdown, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return map[string]types.Breakdown{}, nil
+}
+
 // Do implements APIClient
-func (m *MockGitHubClient) Do(_ string, _ map[string]interface{}, response interface{}) error {
+func (m *MockGitHubClient) Do(_ string, variables map[string]interface{}, response interface{}) error {
 	if m.Err != nil {
 		return m.Err
 	}
@@ -70,10 +95,73 @@ func (m *MockGitHubClient) Do(_ string, _ map[string]interface{}, response inter
 		if m.JoinYear > 0 {
 			v.User.CreatedAt = time.Date(m.JoinYear, 1, 1, 0, 0, 0, 0, time.UTC)
 		}
+	case *types.StargazersResponse:
+		for _, starredAt := range m.Stars {
+			v.Repository.Stargazers.Edges = append(v.Repository.Stargazers.Edges, struct {
+				StarredAt time.Time `json:"starredAt"`
+			}{StarredAt: starredAt})
+		}
+	case *types.LatestReleaseResponse:
+		v.Repository.LatestRelease.TagName = m.Release
+		if m.Release != "" {
+			v.Repository.LatestRelease.URL = "https://github.com/github/gh-skyline/releases/tag/" + m.Release
+		}
+	case *types.OrgMembersResponse:
+		for _, login := range m.Members {
+			v.Organization.MembersWithRole.Nodes = append(v.Organization.MembersWithRole.Nodes, struct {
+				Login string `json:"login"`
+			}{Login: login})
+		}
+	case *types.AvatarResponse:
+		v.User.AvatarURL = m.Avatar
+	case *types.CommitRepositoriesResponse:
+		if len(m.Commits) > 0 {
+			repositories := `{"user": {"id": "MDQ6VXNlcjE=", "contributionsCollection": {"commitContributionsByRepository": [{"repository": {"owner": {"login": "octocat"}, "name": "Hello-World"}}]}}}`
+			if err := json.Unmarshal([]byte(repositories), v); err != nil {
+				return err
+			}
+		}
+		v.RateLimit = m.RateLimit
+	case *types.CommitHistoryResponse:
+		// Every commit is on the first page; the branch is allocated through its JSON shape.
+		if err := json.Unmarshal([]byte(`{"repository": {"defaultBranchRef": {}}}`), v); err != nil {
+			return err
+		}
+		history := &v.Repository.DefaultBranchRef.Target.History
+		for _, authoredDate := range m.Commits {
+			history.Nodes = append(history.Nodes, struct {
+				AuthoredDate time.Time `json:"authoredDate"`
+			}{AuthoredDate: authoredDate})
+		}
+		v.RateLimit = m.RateLimit
 	case *types.ContributionsResponse:
-		// Always use generated mock data instead of empty response
-		mockResp := fixtures.GenerateContributionsResponse(m.Username, time.Now().Year())
-		*v = *mockResp
+		// Date windows other than a whole year get a calendar covering exactly the window;
+		// otherwise use generated mock data instead of an empty response.
+		if start, end, ok := dateWindow(variables); ok {
+			*v = *fixtures.GenerateContributionsResponseForDateRange(m.Username, start, end)
+		} else {
+			*v = *fixtures.GenerateContributionsResponse(m.Username, time.Now().Year())
+		}
+		v.RateLimit = m.RateLimit
 	}
 	return nil
 }
+
+// dateWindow returns the days requested by a contributions query's from and to variables,
+// reporting false when they are missing or span a whole calendar year.
+func dateWindow(variables map[string]interface{}) (time.Time, time.Time, bool) {
+	from, _ := variables["from"].(string)
+	to, _ := variables["to"].(string)
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	if start.YearDay() == 1 && end.Month() == time.December && end.Day() == 31 && start.Year() == end.Year() {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end.Truncate(24 * time.Hour), true
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import "fmt"
+
+// MockObserver implements progress.Observer by recording every event as a string,
+// e.g. "fetch-start octocat 2020-2024" or "geometry base 1/4".
+type MockObserver struct {
+	Events []string
+}
+
+// OnFetchStart implements progress.Observer
+func (m *MockObserver) OnFetchStart(user string, startYear, endYear int) {
+	m.Events = append(m.Events, fmt.Sprintf("fetch-start %s %d-%d", user, startYear, endYear))
+}
+
+// OnYearFetched implements progress.Observer
+func (m *MockObserver) OnYearFetched(year int, cached bool) {
+	m.Events = append(m.Events, fmt.Sprintf("year-fetched %d cached=%t", year, cached))
+}
+
+// OnGeometryProgress implements progress.Observer
+func (m *MockObserver) OnGeometryProgress(component string, done, total int) {
+	m.Events = append(m.Events, fmt.Sprintf("geometry %s %d/%d", component, done, total))
+}
+
+// OnWriteComplete implements progress.Observer
+func (m *MockObserver) OnWriteComplete(path string) {
+	m.Events = append(m.Events, "write "+path)
+}
@@ -0,0 +1,112 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import "time"
+
+// ContributionTypes names the contribution types of a Breakdown, in the order their
+// segments are stacked from the bottom of a column.
+var ContributionTypes = []string{"commits", "pull-requests", "issues", "reviews"}
+
+// Breakdown splits a day's contributions by type. Commits also counts any other
+// contributions included in the calendar total, such as created repositories.
+type Breakdown struct {
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pullRequests"`
+	Issues       int `json:"issues"`
+	Reviews      int `json:"reviews"`
+}
+
+// Counts returns the counts in ContributionTypes order.
+func (b Breakdown) Counts() []int {
+	return []int{b.Commits, b.PullRequests, b.Issues, b.Reviews}
+}
+
+// Total returns the number of contributions across all types.
+func (b Breakdown) Total() int {
+	return b.Commits + b.PullRequests + b.Issues + b.Reviews
+}
+
+// ContributionEventsResponse is one page of dated contributions of a single type. Queries
+// alias the type's connection (e.g. issueContributions) as "contributions".
+type ContributionEventsResponse struct {
+	User struct {
+		ContributionsCollection struct {
+			Contributions struct {
+				Nodes []struct {
+					OccurredAt time.Time `json:"occurredAt"`
+				} `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"contributions"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// PostsResponse is one page of things a user wrote, such as issue comments or
+// discussions. Queries alias the connection on the user as "posts".
+type PostsResponse struct {
+	User struct {
+		Posts struct {
+			Nodes []struct {
+				CreatedAt time.Time `json:"createdAt"`
+				UpdatedAt time.Time `json:"updatedAt"`
+			} `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"posts"`
+	} `json:"user"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// ApplyBreakdown returns a copy of a year's grid with each day's Breakdown filled in from
+// per-date counts of pull requests, issues and reviews. Whatever remains of the day's
+// total is attributed to commits.
+func ApplyBreakdown(weeks [][]ContributionDay, counts map[string]Breakdown) [][]ContributionDay {
+	result := make([][]ContributionDay, len(weeks))
+	for i, week := range weeks {
+		result[i] = make([]ContributionDay, len(week))
+		for j, day := range week {
+			b := counts[day.Date]
+			b.Commits = max(0, day.ContributionCount-b.PullRequests-b.Issues-b.Reviews)
+			day.Breakdown = b
+			result[i][j] = day
+		}
+	}
+	return result
+}
+
+// ReplaceCounts returns a copy of a year's grid with each day's count taken from counts,
+// keyed by date, for rendering a metric other than contributions. Days missing from counts
+// become zero, and breakdowns are cleared as they describe the original totals.
+func ReplaceCounts(weeks [][]ContributionDay, counts map[string]int) [][]ContributionDay {
+	result := make([][]ContributionDay, len(weeks))
+	for i, week := range weeks {
+		result[i] = make([]ContributionDay, len(week))
+		for j, day := range week {
+			result[i][j] = ContributionDay{ContributionCount: counts[day.Date], Date: day.Date}
+		}
+	}
+	return result
+}
+
+// AddCounts returns a copy of a year's grid with the counts of extra, another account's
+// grid for the same days, added to each day by date. Days only extra holds are dropped,
+// and breakdowns are cleared as they describe the original totals.
+func AddCounts(weeks, extra [][]ContributionDay) [][]ContributionDay {
+	counts := map[string]int{}
+	for _, week := range weeks {
+		for _, day := range week {
+			counts[day.Date] += day.ContributionCount
+		}
+	}
+	for _, week := range extra {
+		for _, day := range week {
+			counts[day.Date] += day.ContributionCount
+		}
+	}
+	return ReplaceCounts(weeks, counts)
+}
@@ -0,0 +1,75 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import "testing"
+
+func TestApplyBreakdown(t *testing.T) {
+	weeks := [][]ContributionDay{{
+		{ContributionCount: 5, Date: "2024-03-04"},
+		{ContributionCount: 1, Date: "2024-03-05"},
+		{ContributionCount: 2, Date: "2024-03-06"},
+	}}
+	counts := map[string]Breakdown{
+		"2024-03-04": {PullRequests: 2, Issues: 1},
+		"2024-03-05": {Reviews: 3},
+	}
+
+	got := ApplyBreakdown(weeks, counts)
+	want := []Breakdown{
+		{Commits: 2, PullRequests: 2, Issues: 1},
+		{Reviews: 3}, // More typed contributions than the calendar total leaves no commits.
+		{Commits: 2},
+	}
+	for i, day := range got[0] {
+		if day.Breakdown != want[i] {
+			t.Errorf("day %s breakdown = %+v, want %+v", day.Date, day.Breakdown, want[i])
+		}
+	}
+	if weeks[0][0].Breakdown.Total() != 0 {
+		t.Error("ApplyBreakdown modified its input")
+	}
+	if got := got[0][0].Breakdown.Counts(); len(got) != len(ContributionTypes) {
+		t.Errorf("Counts() has %d entries, want %d", len(got), len(ContributionTypes))
+	}
+}
+
+func TestReplaceCounts(t *testing.T) {
+	weeks := [][]ContributionDay{{
+		{ContributionCount: 5, Date: "2024-03-04", Breakdown: Breakdown{Commits: 5}},
+		{ContributionCount: 1, Date: "2024-03-05"},
+	}}
+
+	got := ReplaceCounts(weeks, map[string]int{"2024-03-04": 2})
+	if got[0][0].ContributionCount != 2 || got[0][0].Breakdown.Total() != 0 {
+		t.Errorf("2024-03-04 = %+v, want count 2 and no breakdown", got[0][0])
+	}
+	if got[0][1].ContributionCount != 0 || got[0][1].Date != "2024-03-05" {
+		t.Errorf("2024-03-05 = %+v, want count 0", got[0][1])
+	}
+	if weeks[0][0].ContributionCount != 5 {
+		t.Error("ReplaceCounts modified its input")
+	}
+}
+
+func TestAddCounts(t *testing.T) {
+	weeks := [][]ContributionDay{{
+		{ContributionCount: 5, Date: "2024-03-04", Breakdown: Breakdown{Commits: 5}},
+		{ContributionCount: 1, Date: "2024-03-05"},
+	}}
+	extra := [][]ContributionDay{
+		{{ContributionCount: 2, Date: "2024-03-03"}, {ContributionCount: 3, Date: "2024-03-04"}},
+	}
+
+	got := AddCounts(weeks, extra)
+	if len(got) != 1 || len(got[0]) != 2 {
+		t.Fatalf("AddCounts() changed the grid's shape: %+v", got)
+	}
+	if got[0][0].ContributionCount != 8 || got[0][0].Breakdown.Total() != 0 {
+		t.Errorf("2024-03-04 = %+v, want count 8 and no breakdown", got[0][0])
+	}
+	if got[0][1].ContributionCount != 1 {
+		t.Errorf("2024-03-05 = %+v, want count 1", got[0][1])
+	}
+	if weeks[0][0].ContributionCount != 5 {
+		t.Error("AddCounts modified its input")
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+// ClockRow lays commit counts per hour of the day out as a row of the grid: a week per
+// hour, each of its seven days carrying the hour's count, so every hour renders as a
+// single tower as deep as a week. The days are undated.
+func ClockRow(hours [24]int) [][]ContributionDay {
+	row := make([][]ContributionDay, len(hours))
+	for hour, count := range hours {
+		row[hour] = make([]ContributionDay, 7)
+		for day := range row[hour] {
+			row[hour][day].ContributionCount = count
+		}
+	}
+	return row
+}
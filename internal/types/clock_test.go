@@ -0,0 +1,21 @@
+package types
+
+import "testing"
+
+func TestClockRow(t *testing.T) {
+	row := ClockRow([24]int{9: 4, 23: 1})
+	if len(row) != 24 {
+		t.Fatalf("ClockRow() = %d weeks, want one per hour", len(row))
+	}
+	for hour, week := range row {
+		want := map[int]int{9: 4, 23: 1}[hour]
+		if len(week) != 7 {
+			t.Fatalf("ClockRow() hour %d = %d days, want 7", hour, len(week))
+		}
+		for _, day := range week {
+			if day.ContributionCount != want || day.Date != "" {
+				t.Errorf("ClockRow() hour %d day = %+v, want an undated count of %d", hour, day, want)
+			}
+		}
+	}
+}
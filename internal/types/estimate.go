@@ -0,0 +1,44 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// PrintEstimate is the print time and filament predicted for a model, by a slicer from the
+// G-code it wrote or natively from the model's volume.
+type PrintEstimate struct {
+	GCode          string        // Path of the sliced G-code; empty for native estimates
+	PrintTime      time.Duration // Estimated print time in normal mode
+	FilamentLength float64       // Filament used in millimetres
+	FilamentWeight float64       // Filament used in grams; zero when the profile has no density
+}
+
+// MarshalJSON encodes the estimate for run summaries, with the G-code's file name alone
+// and the print time in whole seconds rather than nanoseconds.
+func (e PrintEstimate) MarshalJSON() ([]byte, error) {
+	gcode := ""
+	if e.GCode != "" {
+		gcode = filepath.Base(e.GCode)
+	}
+	return json.Marshal(struct {
+		GCode          string  `json:"gcode,omitempty"`
+		PrintSeconds   int64   `json:"printSeconds"`
+		FilamentLength float64 `json:"filamentLength,omitempty"`
+		FilamentWeight float64 `json:"filamentWeight,omitempty"`
+	}{gcode, int64(e.PrintTime / time.Second), e.FilamentLength, e.FilamentWeight})
+}
+
+// String summarises the estimate, such as "2h14m0s, 12.3 g (4.05 m) of filament".
+func (e PrintEstimate) String() string {
+	summary := e.PrintTime.String()
+	switch {
+	case e.FilamentWeight > 0:
+		summary += fmt.Sprintf(", %.1f g (%.2f m) of filament", e.FilamentWeight, e.FilamentLength/1000)
+	case e.FilamentLength > 0:
+		summary += fmt.Sprintf(", %.2f m of filament", e.FilamentLength/1000)
+	}
+	return summary
+}
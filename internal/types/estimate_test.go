@@ -0,0 +1,28 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPrintEstimate(t *testing.T) {
+	for _, tt := range []struct {
+		estimate PrintEstimate
+		want     string
+	}{
+		{PrintEstimate{PrintTime: 90 * time.Minute}, "1h30m0s"},
+		{PrintEstimate{PrintTime: time.Hour, FilamentLength: 4050}, "1h0m0s, 4.05 m of filament"},
+		{PrintEstimate{PrintTime: time.Hour, FilamentLength: 4050, FilamentWeight: 12.3}, "1h0m0s, 12.3 g (4.05 m) of filament"},
+	} {
+		if got := tt.estimate.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+
+	// Native estimates have no G-code to name.
+	data, err := json.Marshal(PrintEstimate{PrintTime: 90*time.Minute + 500*time.Millisecond})
+	if want := `{"printSeconds":5400}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", data, err, want)
+	}
+}
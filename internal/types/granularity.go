@@ -0,0 +1,140 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Granularity is the span of time each tower of a skyline stands for.
+type Granularity int
+
+// Supported granularities.
+const (
+	GranularityDay   Granularity = iota // A column per day, the classic skyline
+	GranularityWeek                     // A tower per week, as deep as the week's seven days
+	GranularityMonth                    // A tower per month, spanning the weeks of the month
+)
+
+// ParseGranularity converts a flag value ("day", "week" or "month") into a Granularity.
+func ParseGranularity(name string) (Granularity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "day":
+		return GranularityDay, nil
+	case "week":
+		return GranularityWeek, nil
+	case "month":
+		return GranularityMonth, nil
+	}
+	return GranularityDay, fmt.Errorf("unknown granularity %q (expected day, week or month)", name)
+}
+
+// String returns the flag value of the granularity.
+func (g Granularity) String() string {
+	switch g {
+	case GranularityWeek:
+		return "week"
+	case GranularityMonth:
+		return "month"
+	}
+	return "day"
+}
+
+// Aggregate returns a copy of the contributions ([year][week][day]) summed over each span
+// of the granularity. Every day keeps its date and carries its span's total count and
+// breakdown, so renderers that draw days, such as the ASCII preview, show the span as one
+// block, and maximums and thresholds apply to the totals. GranularityDay returns the
+// contributions unchanged.
+//
+// A month spans the weeks WeekMonth assigns to it, and they carry the total of the days
+// dated in that calendar month, so the month's days in a neighbouring week still count.
+func Aggregate(years [][][]ContributionDay, g Granularity) [][][]ContributionDay {
+	switch g {
+	case GranularityDay:
+		return years
+	case GranularityMonth:
+		return aggregateMonths(years)
+	}
+	result := make([][][]ContributionDay, len(years))
+	for y, weeks := range years {
+		result[y] = make([][]ContributionDay, len(weeks))
+		for w, week := range weeks {
+			var total ContributionDay
+			for _, day := range week {
+				total.ContributionCount += day.ContributionCount
+				total.Breakdown.Commits += day.Breakdown.Commits
+				total.Breakdown.PullRequests += day.Breakdown.PullRequests
+				total.Breakdown.Issues += day.Breakdown.Issues
+				total.Breakdown.Reviews += day.Breakdown.Reviews
+			}
+			result[y][w] = make([]ContributionDay, len(week))
+			for d, day := range week {
+				total.Date = day.Date
+				result[y][w][d] = total
+			}
+		}
+	}
+	return result
+}
+
+// aggregateMonths fills every week of a month with the month's total. Weeks without a
+// dated day belong to no month and are left empty.
+func aggregateMonths(years [][][]ContributionDay) [][][]ContributionDay {
+	totals := map[string]ContributionDay{}
+	for _, weeks := range years {
+		for _, week := range weeks {
+			for _, day := range week {
+				key := monthKey(day.Date)
+				if key == "" {
+					continue
+				}
+				total := totals[key]
+				total.ContributionCount += day.ContributionCount
+				total.Breakdown.Commits += day.Breakdown.Commits
+				total.Breakdown.PullRequests += day.Breakdown.PullRequests
+				total.Breakdown.Issues += day.Breakdown.Issues
+				total.Breakdown.Reviews += day.Breakdown.Reviews
+				totals[key] = total
+			}
+		}
+	}
+
+	result := make([][][]ContributionDay, len(years))
+	for y, weeks := range years {
+		result[y] = make([][]ContributionDay, len(weeks))
+		for w, week := range weeks {
+			key := WeekMonth(week)
+			result[y][w] = make([]ContributionDay, len(week))
+			for d, day := range week {
+				var total ContributionDay
+				if key != "" {
+					total = totals[key]
+				}
+				total.Date = day.Date
+				result[y][w][d] = total
+			}
+		}
+	}
+	return result
+}
+
+// WeekMonth returns the month a week belongs to as "2006-01": that of its last dated
+// day, so the week holding the first of a month starts it, as month labels mark it. It
+// is empty for a week without dated days, such as padding.
+func WeekMonth(week []ContributionDay) string {
+	for d := len(week) - 1; d >= 0; d-- {
+		if key := monthKey(week[d].Date); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// monthKey returns the "2006-01" month of a "2006-01-02" date, or "" if it does not parse.
+func monthKey(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01")
+}
@@ -0,0 +1,79 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGranularity(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Granularity
+		wantErr bool
+	}{
+		{"", GranularityDay, false},
+		{"day", GranularityDay, false},
+		{" Week ", GranularityWeek, false},
+		{"month", GranularityMonth, false},
+		{"fortnight", GranularityDay, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGranularity(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseGranularity(%q) = %v, %v, want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil && tt.name != "" && got.String() != strings.ToLower(strings.TrimSpace(tt.name)) {
+			t.Errorf("String() = %q, want %q", got.String(), tt.name)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	years := [][][]ContributionDay{{
+		{{Date: "2024-01-07", ContributionCount: 2, Breakdown: Breakdown{Commits: 2}}, {Date: "2024-01-08", ContributionCount: 3, Breakdown: Breakdown{Issues: 3}}},
+		{{Date: "2024-01-14"}},
+	}}
+	if got := Aggregate(years, GranularityDay); &got[0] != &years[0] {
+		t.Error("Aggregate() by day should return the contributions unchanged")
+	}
+
+	weekly := Aggregate(years, GranularityWeek)
+	for _, day := range weekly[0][0] {
+		if day.ContributionCount != 5 || day.Breakdown != (Breakdown{Commits: 2, Issues: 3}) {
+			t.Errorf("%s = %+v, want the week's total of 5", day.Date, day)
+		}
+	}
+	if weekly[0][0][1].Date != "2024-01-08" || weekly[0][1][0].ContributionCount != 0 {
+		t.Errorf("Aggregate() = %+v, want days to keep their dates and quiet weeks to stay empty", weekly)
+	}
+	if years[0][0][0].ContributionCount != 2 {
+		t.Error("Aggregate() should not modify its input")
+	}
+}
+
+func TestAggregateMonths(t *testing.T) {
+	// The second week holds the first of February, so it belongs to February, but its
+	// January days still count towards January.
+	years := [][][]ContributionDay{{
+		{{Date: "2024-01-28", ContributionCount: 1}, {Date: "2024-01-29", ContributionCount: 2}},
+		{{Date: "2024-01-31", ContributionCount: 4, Breakdown: Breakdown{Commits: 4}}, {Date: "2024-02-01", ContributionCount: 8}},
+		{{}},
+	}}
+	if got := WeekMonth(years[0][1]); got != "2024-02" {
+		t.Errorf("WeekMonth() = %q, want 2024-02", got)
+	}
+	if got := WeekMonth(years[0][2]); got != "" {
+		t.Errorf("WeekMonth() of padding = %q, want none", got)
+	}
+
+	monthly := Aggregate(years, GranularityMonth)
+	if day := monthly[0][0][1]; day.ContributionCount != 7 || day.Breakdown.Commits != 4 || day.Date != "2024-01-29" {
+		t.Errorf("January day = %+v, want the month's total of 7", day)
+	}
+	if day := monthly[0][1][0]; day.ContributionCount != 8 || day.Date != "2024-01-31" {
+		t.Errorf("February week day = %+v, want February's total of 8", day)
+	}
+	if monthly[0][2][0].ContributionCount != 0 {
+		t.Error("padding should belong to no month")
+	}
+}
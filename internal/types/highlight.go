@@ -0,0 +1,32 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import "sort"
+
+// TopDays returns the dates of the n busiest days across the years ([year][week][day]),
+// the personal records highlighted on a model. Ties go to the earlier day, so exactly n
+// days are picked, or every active day when there are fewer. Days without contributions
+// are never picked.
+func TopDays(years [][][]ContributionDay, n int) map[string]bool {
+	var active []ContributionDay
+	for _, weeks := range years {
+		for _, week := range weeks {
+			for _, day := range week {
+				if day.ContributionCount > 0 && day.Date != "" {
+					active = append(active, day)
+				}
+			}
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].ContributionCount != active[j].ContributionCount {
+			return active[i].ContributionCount > active[j].ContributionCount
+		}
+		return active[i].Date < active[j].Date
+	})
+
+	top := make(map[string]bool, min(n, len(active)))
+	for _, day := range active[:min(max(n, 0), len(active))] {
+		top[day.Date] = true
+	}
+	return top
+}
@@ -0,0 +1,34 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import "testing"
+
+func TestTopDays(t *testing.T) {
+	years := [][][]ContributionDay{
+		{{{Date: "2023-05-01", ContributionCount: 9}, {Date: "2023-05-02", ContributionCount: 4}}},
+		{{{Date: "2024-02-01", ContributionCount: 4}, {Date: "2024-02-02", ContributionCount: 12}, {Date: "2024-02-03"}}},
+	}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, nil},
+		{1, []string{"2024-02-02"}},
+		// The tie at 4 goes to the earlier day.
+		{3, []string{"2024-02-02", "2023-05-01", "2023-05-02"}},
+		// The quiet day is never picked.
+		{10, []string{"2024-02-02", "2023-05-01", "2023-05-02", "2024-02-01"}},
+	}
+	for _, tt := range tests {
+		got := TopDays(years, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("TopDays(%d) = %v, want %v", tt.n, got, tt.want)
+			continue
+		}
+		for _, date := range tt.want {
+			if !got[date] {
+				t.Errorf("TopDays(%d) = %v, missing %s", tt.n, got, date)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package types
+
+// Material is what a component of a model prints as, so exporters that support several
+// materials or colors, and slicers that read them, can tell the parts apart.
+type Material string
+
+// Materials of the components of a skyline.
+const (
+	MaterialBase    Material = "base"    // The base slab or frame the model stands on
+	MaterialSkyline Material = "skyline" // The contribution columns or surface
+	MaterialLabel   Material = "label"   // Embossed text, logos, icons and Braille
+)
+
+// Transform places a component in the model: each vertex is scaled about the origin by
+// Scale, then moved by Offset. The zero Transform leaves the component where it is.
+type Transform struct {
+	Scale  float64 // Uniform scale; zero means 1
+	Offset Point3D
+}
+
+// IsIdentity reports whether t leaves every vertex where it is.
+func (t Transform) IsIdentity() bool {
+	return (t.Scale == 0 || t.Scale == 1) && t.Offset == Point3D{}
+}
+
+// Apply returns tri with the transform applied. A uniform scale keeps the direction of
+// the normal, so only the vertices move; negative scales are not supported.
+func (t Transform) Apply(tri Triangle) Triangle {
+	scale := t.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	place := func(p Point3D) Point3D {
+		return Point3D{X: p.X*scale + t.Offset.X, Y: p.Y*scale + t.Offset.Y, Z: p.Z*scale + t.Offset.Z}
+	}
+	return Triangle{Normal: tri.Normal, V1: place(tri.V1), V2: place(tri.V2), V3: place(tri.V3)}
+}
+
+// Component is a named part of a model, such as its base or columns, meshed in its own
+// coordinates and placed by its Transform.
+type Component struct {
+	Name      string
+	Material  Material
+	Transform Transform
+	Triangles []Triangle
+}
+
+// Mesh returns the component's triangles placed in the model.
+func (c Component) Mesh() []Triangle {
+	if c.Transform.IsIdentity() {
+		return c.Triangles
+	}
+	mesh := make([]Triangle, len(c.Triangles))
+	for i, tri := range c.Triangles {
+		mesh[i] = c.Transform.Apply(tri)
+	}
+	return mesh
+}
+
+// Model is a generated model as its components, in output order, with metadata on how
+// it was produced. Exporters, validators and renderers share it, so they need not
+// re-derive which triangles belong to which part.
+type Model struct {
+	Components []Component
+
+	// Metadata describes the model, such as the user and range of years it shows, keyed
+	// by field name.
+	Metadata map[string]string
+}
+
+// TriangleCount returns the number of triangles over every component.
+func (m *Model) TriangleCount() int {
+	count := 0
+	for _, c := range m.Components {
+		count += len(c.Triangles)
+	}
+	return count
+}
+
+// Triangles returns every component's placed triangles, in component order, as written
+// to formats without components such as STL.
+func (m *Model) Triangles() []Triangle {
+	if len(m.Components) == 1 {
+		return m.Components[0].Mesh()
+	}
+	triangles := make([]Triangle, 0, m.TriangleCount())
+	for _, c := range m.Components {
+		triangles = append(triangles, c.Mesh()...)
+	}
+	return triangles
+}
+
+// Component returns the first component with the given name, reporting false when the
+// model has none.
+func (m *Model) Component(name string) (Component, bool) {
+	for _, c := range m.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Component{}, false
+}
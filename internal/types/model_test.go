@@ -0,0 +1,50 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import "testing"
+
+// unitTriangle returns a triangle in the XY plane with corners at the origin and on the
+// X and Y axes.
+func unitTriangle() Triangle {
+	return Triangle{Normal: Point3D{Z: 1}, V2: Point3D{X: 1}, V3: Point3D{Y: 1}}
+}
+
+func TestTransformApply(t *testing.T) {
+	tri := Transform{Scale: 2, Offset: Point3D{X: 10, Z: 1}}.Apply(unitTriangle())
+	want := Triangle{Normal: Point3D{Z: 1}, V1: Point3D{X: 10, Z: 1}, V2: Point3D{X: 12, Z: 1}, V3: Point3D{X: 10, Y: 2, Z: 1}}
+	if tri != want {
+		t.Errorf("Apply() = %+v, want %+v", tri, want)
+	}
+	if !(Transform{}).IsIdentity() || !(Transform{Scale: 1}).IsIdentity() {
+		t.Error("zero and unit-scale transforms should be the identity")
+	}
+	if (Transform{Offset: Point3D{Y: 1}}).IsIdentity() {
+		t.Error("an offset transform should not be the identity")
+	}
+}
+
+func TestModelTriangles(t *testing.T) {
+	model := &Model{Components: []Component{
+		{Name: "base", Material: MaterialBase, Triangles: []Triangle{unitTriangle(), unitTriangle()}},
+		{Name: "columns", Material: MaterialSkyline, Transform: Transform{Offset: Point3D{Z: 5}}, Triangles: []Triangle{unitTriangle()}},
+	}}
+	if got := model.TriangleCount(); got != 3 {
+		t.Errorf("TriangleCount() = %d, want 3", got)
+	}
+
+	triangles := model.Triangles()
+	if len(triangles) != 3 {
+		t.Fatalf("Triangles() returned %d triangles, want 3", len(triangles))
+	}
+	// Components keep their order, each placed by its transform.
+	if triangles[0] != unitTriangle() || triangles[2].V1 != (Point3D{Z: 5}) {
+		t.Errorf("Triangles() = %+v", triangles)
+	}
+
+	columns, ok := model.Component("columns")
+	if !ok || columns.Material != MaterialSkyline {
+		t.Errorf("Component(columns) = %+v, %v", columns, ok)
+	}
+	if _, ok := model.Component("text"); ok {
+		t.Error("Component(text) should report a missing component")
+	}
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Thresholds are ascending daily counts at which a day reaches each activity level, like
+// the quartiles GitHub uses to shade its contribution graph. The zero value leaves levels
+// to each renderer's own scale.
+type Thresholds []int
+
+// ParseThresholds converts a comma-separated flag value such as "1,5,10,20" into
+// Thresholds. An empty value returns nil.
+func ParseThresholds(value string) (Thresholds, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var thresholds Thresholds
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("threshold %q is not a positive whole number", strings.TrimSpace(field))
+		}
+		if len(thresholds) > 0 && n <= thresholds[len(thresholds)-1] {
+			return nil, fmt.Errorf("thresholds must increase, got %d after %d", n, thresholds[len(thresholds)-1])
+		}
+		thresholds = append(thresholds, n)
+	}
+	return thresholds, nil
+}
+
+// Level returns the number of thresholds count reaches, from 0 to len(t).
+func (t Thresholds) Level(count int) int {
+	level := 0
+	for level < len(t) && count >= t[level] {
+		level++
+	}
+	return level
+}
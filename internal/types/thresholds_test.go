@@ -0,0 +1,48 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseThresholds(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Thresholds
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"1,5,10,20", Thresholds{1, 5, 10, 20}, false},
+		{" 2, 8 ", Thresholds{2, 8}, false},
+		{"3", Thresholds{3}, false},
+		{"1,5,5", nil, true},
+		{"5,1", nil, true},
+		{"0,4", nil, true},
+		{"1,,4", nil, true},
+		{"low", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseThresholds(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseThresholds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseThresholds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThresholdsLevel(t *testing.T) {
+	thresholds := Thresholds{1, 5, 10, 20}
+	for count, want := range map[int]int{0: 0, 1: 1, 4: 1, 5: 2, 19: 3, 20: 4, 500: 4} {
+		if got := thresholds.Level(count); got != want {
+			t.Errorf("Level(%d) = %d, want %d", count, got, want)
+		}
+	}
+	if got := Thresholds(nil).Level(10); got != 0 {
+		t.Errorf("unset Level() = %d, want 0", got)
+	}
+}
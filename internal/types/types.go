@@ -10,8 +10,9 @@ import (
 
 // ContributionDay represents a single day of GitHub contributions.
 type ContributionDay struct {
-	ContributionCount int    `json:"contributionCount"`
-	Date              string `json:"date"`
+	ContributionCount int       `json:"contributionCount"`
+	Date              string    `json:"date"`
+	Breakdown         Breakdown `json:"breakdown,omitzero"` // Counts by type, when fetched
 }
 
 // IsAfter checks if the contribution day is after the given time
@@ -36,9 +37,20 @@ func (c ContributionDay) Validate() error {
 	return nil
 }
 
+// RateLimit is the GraphQL API budget a query reports through its rateLimit field.
+type RateLimit struct {
+	Limit     int       `json:"limit"`     // Points available per hour
+	Cost      int       `json:"cost"`      // Points the query cost
+	Remaining int       `json:"remaining"` // Points left until the budget resets
+	ResetAt   time.Time `json:"resetAt"`   // When the budget is restored
+}
+
 // ContributionsResponse represents the contribution data returned by the GitHub API.
+// Approximate is set when the calendar was rebuilt from REST endpoints because the
+// GraphQL API was unavailable.
 type ContributionsResponse struct {
-	User struct {
+	Approximate bool `json:"approximate,omitempty"`
+	User        struct {
 		Login                   string `json:"login"`
 		ContributionsCollection struct {
 			ContributionCalendar struct {
@@ -49,6 +61,137 @@ type ContributionsResponse struct {
 			} `json:"contributionCalendar"`
 		} `json:"contributionsCollection"`
 	} `json:"user"`
+	RateLimit *RateLimit `json:"rateLimit,omitempty"` // Budget left after the query; nil once recorded
+}
+
+// StargazersResponse is one page of a repository's stargazers, oldest first.
+type StargazersResponse struct {
+	Repository struct {
+		Stargazers struct {
+			Edges []struct {
+				StarredAt time.Time `json:"starredAt"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"stargazers"`
+	} `json:"repository"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// CommitRepositoriesResponse lists the repositories a user committed to within a date
+// range, with the user's node ID for filtering their commit histories.
+type CommitRepositoriesResponse struct {
+	User struct {
+		ID                      string `json:"id"`
+		ContributionsCollection struct {
+			CommitContributionsByRepository []struct {
+				Repository struct {
+					Owner struct {
+						Login string `json:"login"`
+					} `json:"owner"`
+					Name string `json:"name"`
+				} `json:"repository"`
+			} `json:"commitContributionsByRepository"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// CommitHistoryResponse is one page of the commits an author made to a repository's
+// default branch; the branch is nil for an empty repository.
+type CommitHistoryResponse struct {
+	Repository struct {
+		DefaultBranchRef *struct {
+			Target struct {
+				History struct {
+					Nodes []struct {
+						AuthoredDate time.Time `json:"authoredDate"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"history"`
+			} `json:"target"`
+		} `json:"defaultBranchRef"`
+	} `json:"repository"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// OrgMembersResponse is one page of an organization's members visible to the viewer.
+type OrgMembersResponse struct {
+	Organization struct {
+		MembersWithRole struct {
+			Nodes []struct {
+				Login string `json:"login"`
+			} `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"membersWithRole"`
+	} `json:"organization"`
+	RateLimit *RateLimit `json:"rateLimit"`
+}
+
+// LatestReleaseResponse is a repository's most recent published release; the tag is empty
+// if it has none.
+type LatestReleaseResponse struct {
+	Repository struct {
+		LatestRelease struct {
+			TagName string `json:"tagName"`
+			URL     string `json:"url"`
+		} `json:"latestRelease"`
+	} `json:"repository"`
+}
+
+// AvatarResponse is the address of a user's avatar image; it is empty if the user does
+// not exist.
+type AvatarResponse struct {
+	User struct {
+		AvatarURL string `json:"avatarUrl"`
+	} `json:"user"`
+}
+
+// SearchCommitsResponse is one page of REST commit search results.
+type SearchCommitsResponse struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		Commit struct {
+			Author struct {
+				Date time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	} `json:"items"`
+}
+
+// SearchIssuesResponse is one page of REST issue and pull request search results.
+type SearchIssuesResponse struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"items"`
+}
+
+// EventResponse is one of a user's recent public events from the REST API.
+type EventResponse struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RepositoryResponse is one of a user's repositories from the REST API.
+type RepositoryResponse struct {
+	Fork      bool      `json:"fork"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserResponse is a user's public profile from the REST API.
+type UserResponse struct {
+	Login     string    `json:"login"`
+	AvatarURL string    `json:"avatar_url"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Point3D represents a point in 3D space using float64 for accuracy in calculations.
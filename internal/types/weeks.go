@@ -0,0 +1,96 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeekStart converts a day name such as "sunday" or "monday" into the weekday
+// that starts each week of the contribution grid.
+func ParseWeekStart(name string) (time.Weekday, error) {
+	if name == "" {
+		return time.Sunday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown week start %q (expected a day name such as sunday or monday)", name)
+}
+
+// RebucketWeeks regroups a year's contribution grid, as returned by GitHub with weeks
+// starting on Sunday, into weeks starting on the given weekday. Days keep their order;
+// a new week begins at each start day. Days without a valid date stay in the current week.
+func RebucketWeeks(weeks [][]ContributionDay, start time.Weekday) [][]ContributionDay {
+	if start == time.Sunday {
+		return weeks
+	}
+
+	var rebucketed [][]ContributionDay
+	var current []ContributionDay
+	for _, week := range weeks {
+		for _, day := range week {
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err == nil && date.Weekday() == start && len(current) > 0 {
+				rebucketed = append(rebucketed, current)
+				current = nil
+			}
+			current = append(current, day)
+		}
+	}
+	if len(current) > 0 {
+		rebucketed = append(rebucketed, current)
+	}
+	return rebucketed
+}
+
+// SplitWeeks splits a contribution grid into consecutive parts, starting a new part, and a
+// new week within it, at each of the given dates ("YYYY-MM-DD", ascending). Weeks
+// otherwise keep their days.
+func SplitWeeks(weeks [][]ContributionDay, starts []string) [][][]ContributionDay {
+	parts := make([][][]ContributionDay, 1, len(starts)+1)
+	next := 0
+	for _, week := range weeks {
+		var current []ContributionDay
+		for _, day := range week {
+			if next < len(starts) && day.Date >= starts[next] {
+				if len(current) > 0 {
+					parts[len(parts)-1] = append(parts[len(parts)-1], current)
+					current = nil
+				}
+				parts = append(parts, nil)
+				next++
+			}
+			current = append(current, day)
+		}
+		if len(current) > 0 {
+			parts[len(parts)-1] = append(parts[len(parts)-1], current)
+		}
+	}
+	return parts
+}
+
+// PadWeeks returns a copy of a year's grid with the partial first and last weeks filled
+// out to seven days with empty leading and trailing days, and empty weeks appended until
+// there are at least weekCount, so every day sits in its weekday's row and years of
+// different lengths line up.
+func PadWeeks(weeks [][]ContributionDay, weekCount int) [][]ContributionDay {
+	padded := make([][]ContributionDay, 0, max(len(weeks), weekCount))
+	for i, week := range weeks {
+		if missing := 7 - len(week); missing > 0 && len(weeks) > 1 {
+			switch i {
+			case 0:
+				week = append(make([]ContributionDay, missing), week...)
+			case len(weeks) - 1:
+				week = append(week[:len(week):len(week)], make([]ContributionDay, missing)...)
+			}
+		}
+		padded = append(padded, week)
+	}
+	for len(padded) < weekCount {
+		padded = append(padded, make([]ContributionDay, 7))
+	}
+	return padded
+}
@@ -0,0 +1,132 @@
+package types //nolint:revive // package name is appropriate for this internal module
+
+import (
+	"testing"
+	"time"
+)
+
+// sundayWeeks builds a year's grid the way GitHub returns it: weeks starting on Sunday,
+// with partial first and last weeks.
+func sundayWeeks(year int) [][]ContributionDay {
+	var weeks [][]ContributionDay
+	var week []ContributionDay
+	for d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Sunday && len(week) > 0 {
+			weeks = append(weeks, week)
+			week = nil
+		}
+		week = append(week, ContributionDay{ContributionCount: d.YearDay(), Date: d.Format("2006-01-02")})
+	}
+	return append(weeks, week)
+}
+
+func TestParseWeekStart(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{"", time.Sunday, false},
+		{"sunday", time.Sunday, false},
+		{"Monday", time.Monday, false},
+		{"saturday", time.Saturday, false},
+		{"mon", time.Sunday, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWeekStart(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekStart() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWeekStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebucketWeeks(t *testing.T) {
+	weeks := sundayWeeks(2024)
+	if got := RebucketWeeks(weeks, time.Sunday); len(got) != len(weeks) {
+		t.Errorf("Sunday start changed the grid: %d weeks, want %d", len(got), len(weeks))
+	}
+
+	monday := RebucketWeeks(weeks, time.Monday)
+	// 2024 starts on a Monday and ends on a Tuesday.
+	if len(monday) != 53 {
+		t.Fatalf("Monday start has %d weeks, want 53", len(monday))
+	}
+	total := 0
+	for i, week := range monday {
+		total += len(week)
+		first, err := time.Parse("2006-01-02", week[0].Date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && first.Weekday() != time.Monday {
+			t.Errorf("week %d starts on %v, want Monday", i, first.Weekday())
+		}
+	}
+	if total != 366 {
+		t.Errorf("Monday start has %d days, want 366", total)
+	}
+	if len(monday[len(monday)-1]) != 2 {
+		t.Errorf("last week has %d days, want 2", len(monday[len(monday)-1]))
+	}
+
+	// A leap year starting on Sunday touches 54 Monday-start weeks.
+	if got := len(RebucketWeeks(sundayWeeks(2012), time.Monday)); got != 54 {
+		t.Errorf("2012 with Monday start has %d weeks, want 54", got)
+	}
+}
+
+func TestSplitWeeks(t *testing.T) {
+	weeks := [][]ContributionDay{
+		{{Date: "2024-06-23"}, {Date: "2024-06-24"}, {Date: "2024-06-25"}},
+		{{Date: "2024-06-30"}, {Date: "2024-07-01"}, {Date: "2024-07-02"}},
+		{{Date: "2024-07-07"}},
+	}
+	parts := SplitWeeks(weeks, []string{"2024-07-01"})
+	if len(parts) != 2 {
+		t.Fatalf("SplitWeeks() = %d parts, want 2", len(parts))
+	}
+	if len(parts[0]) != 2 || parts[0][1][0].Date != "2024-06-30" || len(parts[0][1]) != 1 {
+		t.Errorf("first part = %v, want the weeks up to 2024-06-30", parts[0])
+	}
+	if len(parts[1]) != 2 || parts[1][0][0].Date != "2024-07-01" || parts[1][1][0].Date != "2024-07-07" {
+		t.Errorf("second part = %v, want the weeks from 2024-07-01", parts[1])
+	}
+	if parts := SplitWeeks(weeks, nil); len(parts) != 1 || len(parts[0]) != 3 {
+		t.Errorf("SplitWeeks() without dates = %v, want the grid unchanged", parts)
+	}
+}
+
+func TestPadWeeks(t *testing.T) {
+	// 2023 starts on a Sunday and ends on a Sunday: a full first week and a one-day last week.
+	weeks := sundayWeeks(2023)
+	padded := PadWeeks(weeks, 53)
+	if len(padded) != 53 {
+		t.Fatalf("padded grid has %d weeks, want 53", len(padded))
+	}
+	for i, week := range padded {
+		if len(week) != 7 {
+			t.Errorf("week %d has %d days, want 7", i, len(week))
+		}
+	}
+	if last := padded[52]; last[0].Date != "2023-12-31" || last[6].Date != "" {
+		t.Errorf("last week = %v, want Dec 31 followed by empty days", last)
+	}
+	if len(weeks[52]) != 1 {
+		t.Error("PadWeeks modified its input")
+	}
+
+	// 2025 starts on a Wednesday, so Jan 1 moves to the Wednesday row and an empty week is added.
+	padded = PadWeeks(sundayWeeks(2025), 54)
+	if got := padded[0][3].Date; got != "2025-01-01" {
+		t.Errorf("Wednesday row of the first week = %q, want 2025-01-01", got)
+	}
+	if len(padded) != 54 {
+		t.Errorf("padded grid has %d weeks, want 54", len(padded))
+	}
+}
@@ -3,6 +3,11 @@ package utils //nolint:revive // package name is appropriate for this internal m
 
 import (
 	"fmt"
+	"image/color"
+	"math"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
@@ -11,11 +16,31 @@ import (
 // Constants for GitHub launch year and default output file format
 const (
 	githubLaunchYear = 2008
-	outputFileFormat = "%s-%s-github-skyline.stl"
+	outputFormat     = "stl"
+
+	// DefaultNameTemplate reproduces the historical "{user}-{range}-github-skyline.stl" name.
+	DefaultNameTemplate = "{user}-{range}-github-skyline"
 )
 
+// placeholderPattern matches a single {name} placeholder in a filename template.
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// now is replaced in tests so {date} placeholders are deterministic.
+var now = time.Now
+
 // ParseYearRange parses whether a year is a single year or a range of years.
 func ParseYearRange(yearRange string) (startYear, endYear int, err error) {
+	return parseYearRange(yearRange, time.Now().Year())
+}
+
+// ParseTemplateYearRange parses a year or range of years like ParseYearRange, but also
+// allows next year, which has no contributions yet, for blank template models.
+func ParseTemplateYearRange(yearRange string) (startYear, endYear int, err error) {
+	return parseYearRange(yearRange, time.Now().Year()+1)
+}
+
+// parseYearRange parses a year or range of years ending no later than lastYear.
+func parseYearRange(yearRange string, lastYear int) (startYear, endYear int, err error) {
 	if strings.Contains(yearRange, "-") {
 		parts := strings.Split(yearRange, "-")
 		if len(parts) != 2 {
@@ -36,16 +61,40 @@ func ParseYearRange(yearRange string) (startYear, endYear int, err error) {
 		}
 		startYear, endYear = year, year
 	}
-	return startYear, endYear, validateYearRange(startYear, endYear)
+	return startYear, endYear, validateYearSpan(startYear, endYear, lastYear)
+}
+
+// ParseYearPair parses two years separated by a colon, such as "2015:2025", in the order
+// given. Each must be a year GitHub has data for; they may be equal or in either order.
+func ParseYearPair(pair string) (first, second int, err error) {
+	parts := strings.Split(pair, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid year pair format (expected FIRST:SECOND, e.g. 2015:2025)")
+	}
+	if first, err = strconv.Atoi(strings.TrimSpace(parts[0])); err != nil {
+		return 0, 0, err
+	}
+	if second, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+		return 0, 0, err
+	}
+	if err := validateYearRange(min(first, second), max(first, second)); err != nil {
+		return 0, 0, err
+	}
+	return first, second, nil
 }
 
 // validateYearRange checks if the years are within the range
 // of GitHub's launch year to the current year and if
 // the start year is not greater than the end year.
 func validateYearRange(startYear, endYear int) error {
-	currentYear := time.Now().Year()
-	if startYear < githubLaunchYear || endYear > currentYear {
-		return fmt.Errorf("years must be between %d and %d", githubLaunchYear, currentYear)
+	return validateYearSpan(startYear, endYear, time.Now().Year())
+}
+
+// validateYearSpan checks if the years are within the range of GitHub's launch year to
+// lastYear and if the start year is not greater than the end year.
+func validateYearSpan(startYear, endYear, lastYear int) error {
+	if startYear < githubLaunchYear || endYear > lastYear {
+		return fmt.Errorf("years must be between %d and %d", githubLaunchYear, lastYear)
 	}
 	if startYear > endYear {
 		return fmt.Errorf("start year cannot be after end year")
@@ -62,15 +111,302 @@ func FormatYearRange(startYear, endYear int) string {
 	return fmt.Sprintf("%04d-%02d", startYear, endYear%100)
 }
 
-// GenerateOutputFilename creates a consistent filename for the STL output
-func GenerateOutputFilename(user string, startYear, endYear int, output string) string {
-	if output != "" {
-		// Ensure the filename ends with .stl
-		if !strings.HasSuffix(strings.ToLower(output), ".stl") {
-			return output + ".stl"
+// ParseDateRange parses the ISO dates (YYYY-MM-DD) bounding a window of days, both
+// included. The window must start no earlier than GitHub's launch and end by the close of
+// the current year; it may be of any length.
+func ParseDateRange(from, to string) (start, end time.Time, err error) {
+	if start, err = time.Parse(time.DateOnly, from); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q (expected YYYY-MM-DD)", from)
+	}
+	if end, err = time.Parse(time.DateOnly, to); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q (expected YYYY-MM-DD)", to)
+	}
+	currentYear := time.Now().Year()
+	if start.Year() < githubLaunchYear || end.Year() > currentYear {
+		return time.Time{}, time.Time{}, fmt.Errorf("dates must be between %d and %d", githubLaunchYear, currentYear)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date cannot be after end date")
+	}
+	return start, end, nil
+}
+
+// SplitDateRange splits a window of days into consecutive spans of at most a year, each
+// starting on an anniversary of start, as a row of the model holds a year at most.
+func SplitDateRange(start, end time.Time) [][2]time.Time {
+	var spans [][2]time.Time
+	for from := start; !from.After(end); from = from.AddDate(1, 0, 0) {
+		spans = append(spans, [2]time.Time{from, minTime(from.AddDate(1, 0, -1), end)})
+	}
+	return spans
+}
+
+// minTime returns the earlier of two times.
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// FormatDateRange returns a window of days as an ISO 8601 interval, leaving out the end
+// date's year when it matches the start's, e.g. "2024-03-01/06-30".
+func FormatDateRange(start, end time.Time) string {
+	if start.Year() == end.Year() {
+		return start.Format(time.DateOnly) + "/" + end.Format("01-02")
+	}
+	return start.Format(time.DateOnly) + "/" + end.Format(time.DateOnly)
+}
+
+// FormatDateSpan returns a window of days written out in full for engraving, such as
+// "2024-03-01 → 2025-02-28".
+func FormatDateSpan(start, end time.Time) string {
+	return start.Format(time.DateOnly) + " → " + end.Format(time.DateOnly)
+}
+
+// byteUnits lists the size suffixes accepted by ParseByteSize, largest first so that
+// longer suffixes are matched before their single-letter forms.
+var byteUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	{"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a human-readable size such as "512MB" or "2G" into bytes.
+// Units are binary multiples and case-insensitive; a bare number is taken as bytes.
+func ParseByteSize(size string) (uint64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	multiplier := uint64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	// Sizes must be finite and fit in 64 bits, as the conversion of any other is undefined.
+	number, err := strconv.ParseFloat(value, 64)
+	bytes := number * float64(multiplier)
+	if err != nil || number < 0 || math.IsNaN(bytes) || bytes >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid size %q (expected a value like 512MB)", size)
+	}
+	return uint64(bytes), nil
+}
+
+// FormatByteSize renders a byte count with a binary unit, e.g. "1.5 MB".
+func FormatByteSize(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 2; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMG"[exp])
+}
+
+// ParseHexColor parses a color written as "#rrggbb" or "#rgb", with or without the "#".
+func ParseHexColor(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q (expected a hex value like #2da44e)", value)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+// OutputNaming controls where generated files are written and how they are named.
+type OutputNaming struct {
+	Dir      string // Directory for generated and relative output paths; empty means the working directory
+	Template string // Filename template; empty means DefaultNameTemplate
+	Range    string // Replaces the formatted year range in {range}, e.g. for a date window
+	Format   string // Extension of the model's format, also used for {format}; empty means stl
+}
+
+// ValidateNameTemplate reports unknown placeholders in a filename template.
+// Supported placeholders are {user}, {range}, {start}, {end}, {date} and {format}.
+func ValidateNameTemplate(template string) error {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if _, ok := templateValues("", 0, 0)[match[1]]; !ok {
+			return fmt.Errorf("unknown placeholder %s (expected {user}, {range}, {start}, {end}, {date} or {format})", match[0])
+		}
+	}
+	return nil
+}
+
+// templateValues returns the replacement for every supported filename placeholder.
+func templateValues(user string, startYear, endYear int) map[string]string {
+	return map[string]string{
+		"user":   user,
+		"range":  FormatYearRange(startYear, endYear),
+		"start":  strconv.Itoa(startYear),
+		"end":    strconv.Itoa(endYear),
+		"date":   now().Format("2006-01-02"),
+		"format": outputFormat,
+	}
+}
+
+// ExpandTemplate replaces the placeholders of a template, as accepted by
+// ValidateNameTemplate, with the given user and year range.
+// Unknown placeholders are left untouched.
+func ExpandTemplate(template, user string, startYear, endYear int) string {
+	return expandValues(template, templateValues(user, startYear, endYear))
+}
+
+// expandValues replaces the placeholders of a template with values.
+func expandValues(template string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := values[strings.Trim(placeholder, "{}")]; ok {
+			return value
 		}
-		return output
+		return placeholder
+	})
+}
+
+// GenerateOutputFilename creates a consistent filename for the model, STL unless
+// naming.Format says otherwise.
+// An explicit output path wins over the template; relative paths are placed in naming.Dir.
+// Unknown placeholders are left untouched, so templates should be checked with ValidateNameTemplate.
+func GenerateOutputFilename(user string, startYear, endYear int, output string, naming OutputNaming) string {
+	name := output
+	if name == "" {
+		template := naming.Template
+		if template == "" {
+			template = DefaultNameTemplate
+		}
+		values := templateValues(user, startYear, endYear)
+		if naming.Range != "" {
+			values["range"] = naming.Range
+		}
+		if naming.Format != "" {
+			values["format"] = naming.Format
+		}
+		name = expandValues(template, values)
+	}
+
+	// Ensure the filename ends with the format's extension
+	format := naming.Format
+	if format == "" {
+		format = outputFormat
+	}
+	if !strings.HasSuffix(strings.ToLower(name), "."+format) {
+		name += "." + format
+	}
+	if naming.Dir != "" && !filepath.IsAbs(name) {
+		name = filepath.Join(naming.Dir, name)
+	}
+	return name
+}
+
+// StandFilename derives the display stand's STL path from the model's, e.g.
+// "octocat-2024-github-skyline.stl" becomes "octocat-2024-github-skyline-stand.stl".
+func StandFilename(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-stand." + outputFormat
+}
+
+// SilhouetteFilename derives the path of a silhouette's SVG profile from the model's, e.g.
+// "octocat-2024-github-skyline.stl" becomes "octocat-2024-github-skyline-silhouette.svg".
+func SilhouetteFilename(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-silhouette.svg"
+}
+
+// BreakdownFilename derives the STL path for one contribution type's columns, e.g.
+// "octocat-2024-github-skyline.stl" becomes "octocat-2024-github-skyline-commits.stl".
+func BreakdownFilename(outputPath, contributionType string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-" + contributionType + "." + outputFormat
+}
+
+// PreviewFilename derives the preview image's path from the model's, e.g.
+// "octocat-2024-github-skyline.stl" becomes "octocat-2024-github-skyline-preview.png".
+func PreviewFilename(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "-preview.png"
+}
+
+// ToolVersion returns the module version gh-skyline was built from, or "dev" for local
+// builds without one.
+func ToolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// ToolCommit returns the abbreviated VCS revision gh-skyline was built from, marked
+// "-dirty" if the working tree had changes, or "unknown" if the build did not record it.
+func ToolCommit() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	revision, dirty := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			dirty = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "unknown"
+	}
+	revision = revision[:min(len(revision), 7)]
+	if dirty {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// CompareVersions compares two semantic versions such as "v1.4.0" and "1.5.0-rc.1",
+// returning -1, 0 or 1 as a is older than, the same as or newer than b. A pre-release is
+// older than its release; pre-releases of the same version compare equal. It reports
+// false if either version cannot be parsed.
+func CompareVersions(a, b string) (int, bool) {
+	parse := func(v string) ([3]int, bool, bool) {
+		var parts [3]int
+		core, pre, _ := strings.Cut(strings.TrimPrefix(v, "v"), "-")
+		core, _, _ = strings.Cut(core, "+")
+		fields := strings.Split(core, ".")
+		if len(fields) != 3 {
+			return parts, false, false
+		}
+		for i, field := range fields {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 0 {
+				return parts, false, false
+			}
+			parts[i] = n
+		}
+		return parts, pre != "", true
+	}
+	va, preA, okA := parse(a)
+	vb, preB, okB := parse(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	switch {
+	case preA && !preB:
+		return -1, true
+	case !preA && preB:
+		return 1, true
 	}
-	yearStr := FormatYearRange(startYear, endYear)
-	return fmt.Sprintf(outputFileFormat, user, yearStr)
+	return 0, true
 }
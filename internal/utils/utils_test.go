@@ -1,6 +1,13 @@
 package utils //nolint:revive // package name is appropriate for this internal module
 
-import "testing"
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
 
 func TestParseYearRange(t *testing.T) {
 	tests := []struct {
@@ -61,6 +68,41 @@ func TestParseYearRange(t *testing.T) {
 	}
 }
 
+func TestParseTemplateYearRange(t *testing.T) {
+	next := time.Now().Year() + 1
+	start, end, err := ParseTemplateYearRange(fmt.Sprint(next))
+	if err != nil || start != next || end != next {
+		t.Errorf("ParseTemplateYearRange(%d) = %d, %d, %v, want next year", next, start, end, err)
+	}
+	if _, _, err := ParseYearRange(fmt.Sprint(next)); err == nil {
+		t.Errorf("ParseYearRange(%d) expected an error for next year", next)
+	}
+	if _, _, err := ParseTemplateYearRange(fmt.Sprint(next + 1)); err == nil {
+		t.Errorf("ParseTemplateYearRange(%d) expected an error beyond next year", next+1)
+	}
+}
+
+func TestParseYearPair(t *testing.T) {
+	tests := []struct {
+		pair        string
+		first, last int
+		wantErr     bool
+	}{
+		{"2015:2024", 2015, 2024, false},
+		{"2024:2015", 2024, 2015, false},
+		{"2020:2020", 2020, 2020, false},
+		{"2015-2024", 0, 0, true},
+		{"2015:abc", 0, 0, true},
+		{"1999:2024", 0, 0, true},
+	}
+	for _, tt := range tests {
+		first, last, err := ParseYearPair(tt.pair)
+		if (err != nil) != tt.wantErr || first != tt.first || last != tt.last {
+			t.Errorf("ParseYearPair(%q) = %d, %d, %v, want %d, %d, error %v", tt.pair, first, last, err, tt.first, tt.last, tt.wantErr)
+		}
+	}
+}
+
 func TestValidateYearRange(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -129,13 +171,90 @@ func TestFormatYearRange(t *testing.T) {
 	}
 }
 
+func TestParseDateRange(t *testing.T) {
+	currentYear := time.Now().Year()
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"quarter", "2024-03-01", "2024-05-31", false},
+		{"across new year", "2023-11-01", "2024-02-29", false},
+		{"single day", "2024-03-01", "2024-03-01", false},
+		{"full year", "2023-07-01", "2024-06-30", false},
+		{"eighteen months", "2023-07-01", "2024-12-31", false},
+		{"reversed", "2024-05-31", "2024-03-01", true},
+		{"before launch", "2007-12-01", "2008-01-31", true},
+		{"future year", fmt.Sprintf("%d-12-01", currentYear), fmt.Sprintf("%d-01-31", currentYear+1), true},
+		{"not a date", "2024-02-30", "2024-03-01", true},
+		{"year only", "2024", "2024-03-01", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseDateRange(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (start.Format(time.DateOnly) != tt.from || end.Format(time.DateOnly) != tt.to) {
+				t.Errorf("ParseDateRange() = %v, %v", start, end)
+			}
+		})
+	}
+}
+
+func TestSplitDateRange(t *testing.T) {
+	for name, tt := range map[string]struct {
+		from, to string
+		want     []string
+	}{
+		"quarter":         {"2024-03-01", "2024-05-31", []string{"2024-03-01/2024-05-31"}},
+		"full year":       {"2023-07-01", "2024-06-30", []string{"2023-07-01/2024-06-30"}},
+		"eighteen months": {"2023-07-01", "2024-12-31", []string{"2023-07-01/2024-06-30", "2024-07-01/2024-12-31"}},
+		"a day over":      {"2023-01-01", "2024-01-01", []string{"2023-01-01/2023-12-31", "2024-01-01/2024-01-01"}},
+	} {
+		start, end, err := ParseDateRange(tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, span := range SplitDateRange(start, end) {
+			got = append(got, span[0].Format(time.DateOnly)+"/"+span[1].Format(time.DateOnly))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: SplitDateRange() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDateRange(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	if got := FormatDateRange(day(2024, 3, 1), day(2024, 6, 30)); got != "2024-03-01/06-30" {
+		t.Errorf("FormatDateRange() within a year = %q", got)
+	}
+	if got := FormatDateRange(day(2023, 11, 1), day(2024, 2, 29)); got != "2023-11-01/2024-02-29" {
+		t.Errorf("FormatDateRange() across years = %q", got)
+	}
+}
+
+func TestFormatDateSpan(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	if got := FormatDateSpan(day(2024, 3, 1), day(2025, 2, 28)); got != "2024-03-01 → 2025-02-28" {
+		t.Errorf("FormatDateSpan() = %q", got)
+	}
+}
+
 func TestGenerateOutputFilename(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+
 	tests := []struct {
 		name      string
 		user      string
 		startYear int
 		endYear   int
 		output    string
+		naming    OutputNaming
 		want      string
 	}{
 		{
@@ -162,14 +281,268 @@ func TestGenerateOutputFilename(t *testing.T) {
 			output:    "myoutput.stl",
 			want:      "myoutput.stl",
 		},
+		{
+			name:      "override without extension",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			output:    "myoutput",
+			want:      "myoutput.stl",
+		},
+		{
+			name:      "output directory",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			naming:    OutputNaming{Dir: "models"},
+			want:      filepath.Join("models", "testuser-2024-github-skyline.stl"),
+		},
+		{
+			name:      "date window",
+			user:      "testuser",
+			startYear: 2023,
+			endYear:   2024,
+			naming:    OutputNaming{Range: "2023-11-01--2024-02-29"},
+			want:      "testuser-2023-11-01--2024-02-29-github-skyline.stl",
+		},
+		{
+			name:      "relative override in output directory",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			output:    "myoutput.stl",
+			naming:    OutputNaming{Dir: "models"},
+			want:      filepath.Join("models", "myoutput.stl"),
+		},
+		{
+			name:      "template",
+			user:      "testuser",
+			startYear: 2020,
+			endYear:   2024,
+			naming:    OutputNaming{Template: "{user}-{range}-{format}"},
+			want:      "testuser-2020-24-stl.stl",
+		},
+		{
+			name:      "template with dates",
+			user:      "testuser",
+			startYear: 2020,
+			endYear:   2024,
+			naming:    OutputNaming{Template: "{date}/{user}_{start}_{end}.stl"},
+			want:      "2024-03-05/testuser_2020_2024.stl",
+		},
+		{
+			name:      "other format",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			naming:    OutputNaming{Template: "{user}-{range}-{format}", Format: "obj"},
+			want:      "testuser-2024-obj.obj",
+		},
+		{
+			name:      "other format with output",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			output:    "model.OBJ",
+			naming:    OutputNaming{Format: "obj"},
+			want:      "model.OBJ",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GenerateOutputFilename(tt.user, tt.startYear, tt.endYear, tt.output)
+			got := GenerateOutputFilename(tt.user, tt.startYear, tt.endYear, tt.output, tt.naming)
 			if got != tt.want {
 				t.Errorf("generateOutputFilename() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    uint64
+		wantErr bool
+	}{
+		{"bare bytes", "2048", 2048, false},
+		{"megabytes", "512MB", 512 << 20, false},
+		{"lowercase gigabytes", "2gb", 2 << 30, false},
+		{"short suffix", "64k", 64 << 10, false},
+		{"binary suffix", "1MiB", 1 << 20, false},
+		{"fractional", "1.5G", 3 << 29, false},
+		{"spaces", " 10 MB ", 10 << 20, false},
+		{"empty", "", 0, true},
+		{"negative", "-1MB", 0, true},
+		{"garbage", "lots", 0, true},
+		{"infinite", "inf", 0, true},
+		{"infinite gigabytes", "+InfGB", 0, true},
+		{"not a number", "nan", 0, true},
+		{"overflow", "1e30GB", 0, true},
+		{"exactly 64 bits", "17179869184G", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{"#2da44e", color.RGBA{R: 0x2d, G: 0xa4, B: 0x4e, A: 0xff}, false},
+		{"FFFFFF", color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, false},
+		{" #f0a ", color.RGBA{R: 0xff, G: 0x00, B: 0xaa, A: 0xff}, false},
+		{"", color.RGBA{}, true},
+		{"#12345", color.RGBA{}, true},
+		{"#gggggg", color.RGBA{}, true},
+		{"#-12345", color.RGBA{}, true},
+		{"red", color.RGBA{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHexColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHexColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHexColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		bytes uint64
+		want  string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{512 << 20, "512.0 MB"},
+		{3 << 30, "3.0 GB"},
+		{2048 << 30, "2048.0 GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatByteSize(tt.bytes); got != tt.want {
+				t.Errorf("FormatByteSize(%d) = %v, want %v", tt.bytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNameTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{"", false},
+		{DefaultNameTemplate, false},
+		{"{user}-{start}-{end}-{date}.{format}", false},
+		{"{user}-{year}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if err := ValidateNameTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNameTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStandFilename(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"testuser-2024-github-skyline.stl", "testuser-2024-github-skyline-stand.stl"},
+		{filepath.Join("models", "skyline.STL"), filepath.Join("models", "skyline-stand.stl")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := StandFilename(tt.input); got != tt.want {
+				t.Errorf("StandFilename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakdownFilename(t *testing.T) {
+	if got, want := BreakdownFilename("testuser-2024-github-skyline.stl", "pull-requests"), "testuser-2024-github-skyline-pull-requests.stl"; got != want {
+		t.Errorf("BreakdownFilename() = %v, want %v", got, want)
+	}
+}
+
+func TestPreviewFilename(t *testing.T) {
+	if got, want := PreviewFilename("testuser-2024-github-skyline.stl"), "testuser-2024-github-skyline-preview.png"; got != want {
+		t.Errorf("PreviewFilename() = %v, want %v", got, want)
+	}
+}
+
+func TestSilhouetteFilename(t *testing.T) {
+	if got, want := SilhouetteFilename("testuser-2024-github-skyline.stl"), "testuser-2024-github-skyline-silhouette.svg"; got != want {
+		t.Errorf("SilhouetteFilename() = %v, want %v", got, want)
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	got := ExpandTemplate("{user}'s skyline {range} ({start}-{end}) {unknown}", "mona", 2020, 2024)
+	if want := "mona's skyline 2020-24 (2020-2024) {unknown}"; got != want {
+		t.Errorf("ExpandTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestToolVersion(t *testing.T) {
+	// Test binaries are built without a module version.
+	if got := ToolVersion(); got != "dev" {
+		t.Errorf("ToolVersion() = %q, want dev", got)
+	}
+}
+
+func TestToolCommit(t *testing.T) {
+	// Test binaries record no VCS revision.
+	if got := ToolCommit(); got != "unknown" {
+		t.Errorf("ToolCommit() = %q, want unknown", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+		ok   bool
+	}{
+		{"v1.4.0", "v1.4.0", 0, true},
+		{"v1.4.0", "v1.5.0", -1, true},
+		{"1.10.0", "v1.9.3", 1, true},
+		{"v2.0.0", "v1.99.99", 1, true},
+		{"v1.5.0-rc.1", "v1.5.0", -1, true},
+		{"v1.5.0", "v1.5.0-rc.1", 1, true},
+		{"v1.5.0+build.7", "v1.5.0", 0, true},
+		{"dev", "v1.5.0", 0, false},
+		{"v1.5", "v1.5.0", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := CompareVersions(tt.a, tt.b)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v, want %d, %v", tt.a, tt.b, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package wireframe draws the edges of a 3D mesh with Braille characters, so a model's
+// proportions can be checked in the terminal without an external viewer.
+package wireframe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// quantum is the resolution, in millimeters, at which vertices are matched to find
+	// the edges that triangles share.
+	quantum = 1e-3
+
+	// creaseCosine is the largest cosine of the angle between the faces either side of an
+	// edge for the edge to be drawn; flatter edges only split a face into triangles.
+	creaseCosine = 0.999
+
+	// SpinStep is the angle, in degrees, the model turns between frames of Spin.
+	SpinStep = 6.0
+)
+
+// Braille dots are numbered down the left column of a character, then the right, with the
+// bottom row last; brailleDots maps a dot's row and column onto its bit.
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// Mesh is the outline of a model: the edges where its surface creases or ends.
+type Mesh struct {
+	edges  [][2]types.Point3D
+	centre types.Point3D // Centre of the bounding box
+	radius float64       // Horizontal distance of the farthest vertex from the centre
+	height float64       // Height of the bounding box
+}
+
+// edge is a triangle edge between two vertices matched at the quantum, in order, with
+// the normal of the triangle it belongs to.
+type edge struct {
+	a, b   [3]int32
+	normal types.Point3D
+}
+
+// NewMesh finds the edges outlining triangles. Edges between coplanar triangles, which
+// only split a face, are left out.
+func NewMesh(triangles []types.Triangle) (Mesh, error) {
+	if len(triangles) == 0 {
+		return Mesh{}, errors.New(errors.ValidationError, "mesh has no triangles", nil)
+	}
+
+	lo := types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	hi := types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	edges := make([]edge, 0, 3*len(triangles))
+	for _, tri := range triangles {
+		normal, ok := unitNormal(tri)
+		if !ok {
+			continue
+		}
+		vertices := [3]types.Point3D{tri.V1, tri.V2, tri.V3}
+		for i, v := range vertices {
+			lo = types.Point3D{X: min(lo.X, v.X), Y: min(lo.Y, v.Y), Z: min(lo.Z, v.Z)}
+			hi = types.Point3D{X: max(hi.X, v.X), Y: max(hi.Y, v.Y), Z: max(hi.Z, v.Z)}
+			a, b := quantize(v), quantize(vertices[(i+1)%3])
+			if slices.Compare(a[:], b[:]) > 0 {
+				a, b = b, a
+			}
+			edges = append(edges, edge{a, b, normal})
+		}
+	}
+	if len(edges) == 0 {
+		return Mesh{}, errors.New(errors.ValidationError, "mesh has only degenerate triangles", nil)
+	}
+
+	// Sorting brings together the triangles sharing each edge.
+	slices.SortFunc(edges, func(x, y edge) int {
+		if c := slices.Compare(x.a[:], y.a[:]); c != 0 {
+			return c
+		}
+		return slices.Compare(x.b[:], y.b[:])
+	})
+
+	m := Mesh{
+		centre: types.Point3D{X: (lo.X + hi.X) / 2, Y: (lo.Y + hi.Y) / 2, Z: (lo.Z + hi.Z) / 2},
+		height: hi.Z - lo.Z,
+	}
+	for start := 0; start < len(edges); {
+		end, crease := start+1, false
+		for end < len(edges) && edges[end].a == edges[start].a && edges[end].b == edges[start].b {
+			crease = crease || dot(edges[end].normal, edges[start].normal) < creaseCosine
+			end++
+		}
+		if crease || end-start == 1 {
+			a, b := unquantize(edges[start].a), unquantize(edges[start].b)
+			m.edges = append(m.edges, [2]types.Point3D{a, b})
+			for _, p := range []types.Point3D{a, b} {
+				m.radius = max(m.radius, math.Hypot(p.X-m.centre.X, p.Y-m.centre.Y))
+			}
+		}
+		start = end
+	}
+	return m, nil
+}
+
+// Edges returns the number of edges drawn for the mesh.
+func (m Mesh) Edges() int {
+	return len(m.edges)
+}
+
+// Render draws the mesh into rows lines of cols characters, each holding two by four
+// Braille dots, as seen from a camera swung azimuth degrees from the front of the model
+// towards its right and raised elevation degrees above it. The mesh is scaled to fit
+// at every azimuth, so frames of a turning model keep their size. Trailing blanks are
+// trimmed.
+func (m Mesh) Render(cols, rows int, azimuth, elevation float64) []string {
+	width, height := 2*cols, 4*rows
+	dots := make([][]bool, height)
+	for i := range dots {
+		dots[i] = make([]bool, width)
+	}
+
+	a, e := azimuth*math.Pi/180, elevation*math.Pi/180
+	right := types.Point3D{X: math.Cos(a), Y: math.Sin(a)}
+	up := types.Point3D{X: -math.Sin(e) * math.Sin(a), Y: math.Sin(e) * math.Cos(a), Z: math.Cos(e)}
+	halfWidth := max(m.radius, quantum)
+	halfHeight := max(m.radius*math.Sin(e)+m.height/2*math.Cos(e), quantum)
+	scale := min(float64(width-1)/(2*halfWidth), float64(height-1)/(2*halfHeight))
+	project := func(p types.Point3D) (float64, float64) {
+		d := types.Point3D{X: p.X - m.centre.X, Y: p.Y - m.centre.Y, Z: p.Z - m.centre.Z}
+		return float64(width-1)/2 + dot(d, right)*scale, float64(height-1)/2 - dot(d, up)*scale
+	}
+
+	for _, edge := range m.edges {
+		x0, y0 := project(edge[0])
+		x1, y1 := project(edge[1])
+		steps := int(math.Ceil(max(math.Abs(x1-x0), math.Abs(y1-y0)))) + 1
+		for i := 0; i <= steps; i++ {
+			t := float64(i) / float64(steps)
+			x, y := int(math.Round(x0+(x1-x0)*t)), int(math.Round(y0+(y1-y0)*t))
+			if x >= 0 && x < width && y >= 0 && y < height {
+				dots[y][x] = true
+			}
+		}
+	}
+
+	lines := make([]string, rows)
+	for row := range lines {
+		var line strings.Builder
+		for col := range cols {
+			var cell rune
+			for dy := range 4 {
+				for dx := range 2 {
+					if dots[4*row+dy][2*col+dx] {
+						cell |= brailleDots[dy][dx]
+					}
+				}
+			}
+			if cell == 0 {
+				line.WriteByte(' ')
+			} else {
+				line.WriteRune(0x2800 + cell)
+			}
+		}
+		lines[row] = strings.TrimRight(line.String(), " ")
+	}
+	return lines
+}
+
+// Spin draws the mesh turning about its vertical axis, SpinStep degrees per frame, one
+// frame every delay. Each frame is drawn over the last with ANSI cursor movement, so w
+// should be a terminal. It stops after frames frames, or when ctx is done if frames is
+// zero, leaving the last frame on screen.
+func (m Mesh) Spin(ctx context.Context, w io.Writer, cols, rows int, elevation float64, frames int, delay time.Duration) (err error) {
+	// Hide the cursor while drawing and restore it however the spin ends.
+	if _, err := io.WriteString(w, "\x1b[?25l"); err != nil {
+		return err
+	}
+	defer func() {
+		if _, werr := io.WriteString(w, "\x1b[?25h"); werr != nil && err == nil {
+			err = werr
+		}
+	}()
+
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for frame := 0; frames == 0 || frame < frames; frame++ {
+		if frame > 0 {
+			if ctx.Err() != nil {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			if _, err := fmt.Fprintf(w, "\x1b[%dA", rows); err != nil {
+				return err
+			}
+		}
+		for _, line := range m.Render(cols, rows, float64(frame)*SpinStep, elevation) {
+			if _, err := fmt.Fprintf(w, "%s\x1b[K\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unitNormal returns the unit normal of a triangle from its winding, or false for a
+// degenerate triangle.
+func unitNormal(tri types.Triangle) (types.Point3D, bool) {
+	u := types.Point3D{X: tri.V2.X - tri.V1.X, Y: tri.V2.Y - tri.V1.Y, Z: tri.V2.Z - tri.V1.Z}
+	v := types.Point3D{X: tri.V3.X - tri.V1.X, Y: tri.V3.Y - tri.V1.Y, Z: tri.V3.Z - tri.V1.Z}
+	n := types.Point3D{X: u.Y*v.Z - u.Z*v.Y, Y: u.Z*v.X - u.X*v.Z, Z: u.X*v.Y - u.Y*v.X}
+	length := math.Sqrt(dot(n, n))
+	if length == 0 {
+		return types.Point3D{}, false
+	}
+	return types.Point3D{X: n.X / length, Y: n.Y / length, Z: n.Z / length}, true
+}
+
+func dot(a, b types.Point3D) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func quantize(p types.Point3D) [3]int32 {
+	round := func(v float64) int32 { return int32(math.Round(v / quantum)) }
+	return [3]int32{round(p.X), round(p.Y), round(p.Z)}
+}
+
+func unquantize(q [3]int32) types.Point3D {
+	return types.Point3D{X: float64(q[0]) * quantum, Y: float64(q[1]) * quantum, Z: float64(q[2]) * quantum}
+}
@@ -0,0 +1,114 @@
+package wireframe
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestNewMesh(t *testing.T) {
+	box, err := geometry.CreateCube(0, 0, 0, 10, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh, err := NewMesh(box)
+	if err != nil {
+		t.Fatalf("NewMesh() error = %v", err)
+	}
+	// The diagonals splitting each face into triangles are left out.
+	if got := mesh.Edges(); got != 12 {
+		t.Errorf("box has %d edges, want 12", got)
+	}
+
+	// Two boxes side by side keep the edges along the step where their heights differ.
+	tall, err := geometry.CreateCube(10, 0, 0, 10, 4, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mesh, err := NewMesh(append(box, tall...)); err != nil || mesh.Edges() <= 12 {
+		t.Errorf("two boxes have %d edges (error %v), want more than one box", mesh.Edges(), err)
+	}
+
+	if _, err := NewMesh(nil); err == nil {
+		t.Error("NewMesh() expected error for no triangles")
+	}
+	flat := types.Triangle{V1: types.Point3D{X: 1}, V2: types.Point3D{X: 2}, V3: types.Point3D{X: 3}}
+	if _, err := NewMesh([]types.Triangle{flat}); err == nil {
+		t.Error("NewMesh() expected error for degenerate triangles")
+	}
+}
+
+func TestRender(t *testing.T) {
+	box, err := geometry.CreateCube(0, 0, 0, 10, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh, err := NewMesh(box)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Straight from the front, a cube is a square outline.
+	lines := mesh.Render(20, 10, 0, 0)
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n > 20 {
+			t.Errorf("line %d is %d characters, want at most 20", i, n)
+		}
+		for _, r := range line {
+			if r != ' ' && (r < 0x2800 || r > 0x28ff) {
+				t.Fatalf("line %d has %q, want Braille or spaces", i, r)
+			}
+		}
+	}
+	// The frame fits the model's widest turn, its diagonal, so the square sits inside it
+	// with only its sides drawn across the middle.
+	if middle := strings.Fields(lines[5]); len(middle) != 2 {
+		t.Errorf("middle line %q, want only the two sides of the square:\n%s", lines[5], strings.Join(lines, "\n"))
+	}
+
+	// Turning the camera changes the view but not its size.
+	turned := mesh.Render(20, 10, 45, 30)
+	if strings.Join(turned, "\n") == strings.Join(lines, "\n") {
+		t.Error("turned view matches the front view")
+	}
+}
+
+func TestSpin(t *testing.T) {
+	box, err := geometry.CreateCube(0, 0, 0, 10, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh, err := NewMesh(box)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := mesh.Spin(context.Background(), &out, 20, 6, 30, 3, 1); err != nil {
+		t.Fatalf("Spin() error = %v", err)
+	}
+	if got := strings.Count(out.String(), "\x1b[6A"); got != 2 {
+		t.Errorf("moved up over %d frames, want 2", got)
+	}
+	if !strings.HasSuffix(out.String(), "\x1b[?25h") {
+		t.Error("Spin() did not restore the cursor")
+	}
+
+	// A cancelled spin keeps the first frame.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out.Reset()
+	if err := mesh.Spin(ctx, &out, 20, 6, 30, 0, 1); err != nil {
+		t.Fatalf("Spin() error = %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 6 {
+		t.Errorf("cancelled spin drew %d lines, want one frame of 6", got)
+	}
+}
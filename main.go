@@ -6,13 +6,13 @@ import (
 	"os"
 
 	"github.com/github/gh-skyline/cmd"
+	"github.com/github/gh-skyline/internal/errors"
 )
 
 type exitCode int
 
 const (
-	exitOK    exitCode = 0
-	exitError exitCode = 1
+	exitOK exitCode = errors.ExitOK
 )
 
 func main() {
@@ -21,12 +21,12 @@ func main() {
 }
 
 func start() exitCode {
-	exitCode := exitOK
+	code := exitOK
 	ctx := context.Background()
 
 	if err := cmd.Execute(ctx); err != nil {
-		exitCode = exitError
+		code = exitCode(errors.ExitCode(err))
 	}
 
-	return exitCode
+	return code
 }